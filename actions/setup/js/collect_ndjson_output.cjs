@@ -287,10 +287,23 @@ async function main() {
       }
     }
     core.info(`Successfully parsed ${parsedItems.length} valid output items`);
+    /** @type {{items: any[], errors: string[], content_policy_violations?: Array<{index: number, type: string, reason: string}>}} */
     const validatedOutput = {
       items: parsedItems,
       errors: errors,
     };
+
+    // Record safe-outputs.content-policy violations so they show up in gh aw audit.
+    // The handler manager enforces the policy again before applying the items.
+    const { loadContentPolicy, findContentPolicyViolations } = require("./safe_output_content_policy.cjs");
+    const contentPolicy = loadContentPolicy();
+    if (contentPolicy) {
+      const violations = findContentPolicyViolations(parsedItems, contentPolicy);
+      if (violations.size > 0) {
+        core.warning(`${violations.size} output item(s) violate the safe-outputs content policy`);
+        validatedOutput.content_policy_violations = Array.from(violations, ([index, reason]) => ({ index, type: parsedItems[index].type, reason }));
+      }
+    }
     const path = require("path");
     const agentOutputFile = path.join(TMP_GH_AW_PATH, AGENT_OUTPUT_FILENAME);
     const validatedOutputJson = JSON.stringify(validatedOutput);
@@ -977,6 +977,18 @@ describe("collect_ndjson_output.cjs", () => {
       const parsedOutput = JSON.parse(outputCall[1]);
       (expect(parsedOutput.items).toHaveLength(2), expect(parsedOutput.errors).toHaveLength(0));
     }),
+    it("should record safe-outputs content policy violations in agent_output.json", async () => {
+      const testFile = "/tmp/gh-aw/test-ndjson-output.txt",
+        ndjsonContent = '{"type": "create_issue", "title": "Test Issue", "body": "Confidential details"}\n{"type": "add_comment", "body": "Test comment"}';
+      (fs.writeFileSync(testFile, ndjsonContent), (process.env.GH_AW_SAFE_OUTPUTS = testFile), (process.env.GH_AW_SAFE_OUTPUTS_CONTENT_POLICY = JSON.stringify({ banned_words: ["confidential"] })));
+      const __config = '{"create_issue": true, "add_comment": true}',
+        configPath = "/opt/gh-aw/safeoutputs/config.json";
+      (fs.mkdirSync("/opt/gh-aw/safeoutputs", { recursive: !0 }), fs.writeFileSync(configPath, __config), await eval(`(async () => { ${collectScript}; await main(); })()`), delete process.env.GH_AW_SAFE_OUTPUTS_CONTENT_POLICY);
+      const agentOutputJson = JSON.parse(fs.readFileSync("/tmp/gh-aw/agent_output.json", "utf8"));
+      (expect(agentOutputJson.items).toHaveLength(2),
+        expect(agentOutputJson.content_policy_violations).toEqual([{ index: 0, type: "create_issue", reason: "Content policy violation: body contains banned word 'confidential'" }]),
+        expect(mockCore.warning).toHaveBeenCalledWith("1 output item(s) violate the safe-outputs content policy"));
+    }),
     it("should handle errors when writing agent_output.json file gracefully", async () => {
       const testFile = "/tmp/gh-aw/test-ndjson-output.txt",
         ndjsonContent = '{"type": "create_issue", "title": "Test Issue", "body": "Test body"}';
@@ -0,0 +1,99 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Shared helpers for create_commit_status and the pending/resolve steps that wrap the agent run
+ */
+
+const { renderTemplate } = require("./messages_core.cjs");
+
+/** States accepted by the GitHub commit status API */
+const COMMIT_STATUS_STATES = ["error", "failure", "pending", "success"];
+
+/** Description of the pending status set before the agent runs, used to recognize it when resolving */
+const PENDING_DESCRIPTION = "Agentic workflow is running";
+
+/** The commit status API rejects descriptions longer than 140 characters */
+const MAX_DESCRIPTION_LENGTH = 140;
+
+/**
+ * Returns the fixed status context for this workflow
+ * @param {string|undefined} configuredContext - Context from the handler config or environment
+ * @returns {string}
+ */
+function getStatusContext(configuredContext) {
+  return configuredContext || process.env.GH_AW_COMMIT_STATUS_CONTEXT || process.env.GH_AW_WORKFLOW_NAME || "Agentic Workflow";
+}
+
+/**
+ * Resolves the commit the status is attached to. Pull request events use the head commit,
+ * comments on pull requests look the head commit up, and everything else uses the triggering SHA.
+ * @returns {Promise<string|undefined>}
+ */
+async function resolveStatusSha() {
+  const payload = context.payload || {};
+  if (payload.pull_request?.head?.sha) {
+    return payload.pull_request.head.sha;
+  }
+  if (payload.workflow_run?.head_sha) {
+    return payload.workflow_run.head_sha;
+  }
+  if (payload.issue?.pull_request && payload.issue.number) {
+    const { data: pullRequest } = await github.rest.pulls.get({
+      owner: context.repo.owner,
+      repo: context.repo.repo,
+      pull_number: payload.issue.number,
+    });
+    return pullRequest.head.sha;
+  }
+  return context.sha || undefined;
+}
+
+/**
+ * Renders the target URL template, falling back to the workflow run URL
+ * @param {string|undefined} template - Template supporting {run_url}, {server_url}, {repository}, {run_id}, {sha} and {workflow_name}
+ * @param {string} sha - Commit the status is attached to
+ * @returns {string}
+ */
+function renderTargetUrl(template, sha) {
+  const repository = `${context.repo.owner}/${context.repo.repo}`;
+  const runUrl = `${context.serverUrl}/${repository}/actions/runs/${context.runId}`;
+  if (!template) {
+    return runUrl;
+  }
+
+  const rendered = renderTemplate(template, {
+    run_url: runUrl,
+    server_url: context.serverUrl,
+    repository,
+    run_id: context.runId,
+    sha,
+    workflow_name: process.env.GH_AW_WORKFLOW_NAME || "",
+  });
+  if (!/^https?:\/\//.test(rendered)) {
+    core.warning(`Ignoring target URL '${rendered}': it must be an http(s) URL`);
+    return runUrl;
+  }
+  return rendered;
+}
+
+/**
+ * Truncates a description to the length the commit status API accepts
+ * @param {string} description
+ * @returns {string}
+ */
+function truncateDescription(description) {
+  if (description.length <= MAX_DESCRIPTION_LENGTH) {
+    return description;
+  }
+  return description.slice(0, MAX_DESCRIPTION_LENGTH - 1) + "…";
+}
+
+module.exports = {
+  COMMIT_STATUS_STATES,
+  PENDING_DESCRIPTION,
+  getStatusContext,
+  resolveStatusSha,
+  renderTargetUrl,
+  truncateDescription,
+};
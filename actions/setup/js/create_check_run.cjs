@@ -0,0 +1,255 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { sanitizeContent } = require("./sanitize_content.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "create_check_run";
+
+/** Conclusions accepted by the GitHub Checks API for a completed check run */
+const CHECK_RUN_CONCLUSIONS = ["success", "failure", "neutral", "cancelled", "skipped", "timed_out", "action_required"];
+
+/** Annotation levels accepted by the GitHub Checks API */
+const ANNOTATION_LEVELS = ["notice", "warning", "failure"];
+
+/** The Checks API accepts at most 50 annotations per request */
+const ANNOTATIONS_PER_REQUEST = 50;
+
+/**
+ * Parses a positive integer from a number or numeric string
+ * @param {any} value
+ * @returns {number|undefined}
+ */
+function parsePositiveInteger(value) {
+  const parsed = typeof value === "number" ? value : parseInt(String(value), 10);
+  return Number.isInteger(parsed) && parsed > 0 ? parsed : undefined;
+}
+
+/**
+ * Validates and normalizes a single annotation from agent output
+ * @param {any} annotation - Annotation provided by the agent
+ * @param {number} index - Position of the annotation, used in error messages
+ * @returns {{annotation?: Object, error?: string}}
+ */
+function normalizeAnnotation(annotation, index) {
+  if (!annotation || typeof annotation !== "object") {
+    return { error: `annotation ${index} must be an object` };
+  }
+  if (typeof annotation.path !== "string" || annotation.path.trim() === "") {
+    return { error: `annotation ${index} requires a 'path'` };
+  }
+  if (typeof annotation.message !== "string" || annotation.message.trim() === "") {
+    return { error: `annotation ${index} requires a 'message'` };
+  }
+
+  const startLine = parsePositiveInteger(annotation.start_line);
+  if (startLine === undefined) {
+    return { error: `annotation ${index} requires a positive 'start_line'` };
+  }
+  const endLine = annotation.end_line !== undefined ? parsePositiveInteger(annotation.end_line) : startLine;
+  if (endLine === undefined || endLine < startLine) {
+    return { error: `annotation ${index} has an invalid 'end_line'` };
+  }
+
+  const level = annotation.annotation_level || "warning";
+  if (!ANNOTATION_LEVELS.includes(level)) {
+    return { error: `annotation ${index} has an invalid 'annotation_level' '${level}' (expected one of: ${ANNOTATION_LEVELS.join(", ")})` };
+  }
+
+  /** @type {any} */
+  const normalized = {
+    path: annotation.path.trim().replace(/^\.?\//, ""),
+    start_line: startLine,
+    end_line: endLine,
+    annotation_level: level,
+    message: sanitizeContent(annotation.message, { maxLength: 65000 }),
+  };
+  if (typeof annotation.title === "string" && annotation.title.trim() !== "") {
+    normalized.title = sanitizeContent(annotation.title, { maxLength: 255 });
+  }
+  // Columns are only allowed by the API for single-line annotations
+  if (startLine === endLine) {
+    const startColumn = parsePositiveInteger(annotation.start_column);
+    const endColumn = parsePositiveInteger(annotation.end_column);
+    if (startColumn !== undefined) {
+      normalized.start_column = startColumn;
+      normalized.end_column = endColumn !== undefined && endColumn >= startColumn ? endColumn : startColumn;
+    }
+  }
+  return { annotation: normalized };
+}
+
+/**
+ * Resolves the head SHA the check run is attached to
+ * @param {any} message - The create_check_run message
+ * @returns {Promise<{headSha?: string, prNumber?: number, error?: string}>}
+ */
+async function resolveHeadSha(message) {
+  if (message.pull_request_number !== undefined) {
+    const prNumber = parsePositiveInteger(message.pull_request_number);
+    if (prNumber === undefined) {
+      return { error: `Invalid pull_request_number: ${message.pull_request_number}` };
+    }
+    const { data: pullRequest } = await github.rest.pulls.get({
+      owner: context.repo.owner,
+      repo: context.repo.repo,
+      pull_number: prNumber,
+    });
+    return { headSha: pullRequest.head.sha, prNumber };
+  }
+
+  const pullRequest = context.payload?.pull_request;
+  if (!pullRequest?.head?.sha) {
+    return { error: "No pull_request_number provided and not in pull request context" };
+  }
+  return { headSha: pullRequest.head.sha, prNumber: pullRequest.number };
+}
+
+/**
+ * Main handler factory for create_check_run
+ * Returns a message handler function that processes individual create_check_run messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  // Extract configuration
+  const checkName = config.name || process.env.GH_AW_WORKFLOW_NAME || "Agentic Workflow";
+  const maxCount = config.max || 1;
+  const maxAnnotations = config.max_annotations || ANNOTATIONS_PER_REQUEST;
+  const allowedConclusions = config.allowed_conclusions && config.allowed_conclusions.length > 0 ? config.allowed_conclusions : CHECK_RUN_CONCLUSIONS;
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Create check run configuration: name=${checkName}, max=${maxCount}, max_annotations=${maxAnnotations}`);
+  core.info(`Allowed conclusions: ${allowedConclusions.join(", ")}`);
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single create_check_run message
+   * @param {Object} message - The create_check_run message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleCreateCheckRun(message, resolvedTemporaryIds) {
+    // Check if we've hit the max limit
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+
+    if (!allowedConclusions.includes(item.conclusion)) {
+      core.warning(`Conclusion '${item.conclusion}' is not allowed`);
+      return {
+        success: false,
+        error: `Conclusion '${item.conclusion}' is not allowed (allowed: ${allowedConclusions.join(", ")})`,
+      };
+    }
+
+    // Validate annotations, dropping invalid ones and applying the configured limit
+    const annotations = [];
+    const requestedAnnotations = Array.isArray(item.annotations) ? item.annotations : [];
+    for (let i = 0; i < requestedAnnotations.length; i++) {
+      const { annotation, error } = normalizeAnnotation(requestedAnnotations[i], i);
+      if (error) {
+        core.warning(`Skipping ${error}`);
+        continue;
+      }
+      annotations.push(annotation);
+    }
+    if (annotations.length > maxAnnotations) {
+      core.warning(`Truncating ${annotations.length} annotations to the configured maximum of ${maxAnnotations}`);
+      annotations.length = maxAnnotations;
+    }
+
+    try {
+      const { headSha, prNumber, error } = await resolveHeadSha(item);
+      if (error || !headSha) {
+        core.warning(error || "Could not resolve the pull request head SHA");
+        return {
+          success: false,
+          error: error || "Could not resolve the pull request head SHA",
+        };
+      }
+
+      const output = {
+        title: item.title,
+        summary: item.summary,
+        ...(item.text ? { text: item.text } : {}),
+      };
+
+      // If in staged mode, preview without executing
+      if (isStaged) {
+        core.info(`Staged mode: Would create check run '${checkName}' (${item.conclusion}) on ${headSha} with ${annotations.length} annotation(s)`);
+        return {
+          success: true,
+          staged: true,
+          previewInfo: {
+            name: checkName,
+            conclusion: item.conclusion,
+            headSha,
+            annotations: annotations.length,
+          },
+        };
+      }
+
+      const runUrl = `${context.serverUrl}/${context.repo.owner}/${context.repo.repo}/actions/runs/${context.runId}`;
+
+      core.info(`Creating check run '${checkName}' (${item.conclusion}) on ${headSha}`);
+      const { data: checkRun } = await github.rest.checks.create({
+        owner: context.repo.owner,
+        repo: context.repo.repo,
+        name: checkName,
+        head_sha: headSha,
+        status: "completed",
+        conclusion: item.conclusion,
+        completed_at: new Date().toISOString(),
+        details_url: runUrl,
+        external_id: String(context.runId),
+        output: { ...output, annotations: annotations.slice(0, ANNOTATIONS_PER_REQUEST) },
+      });
+
+      // The API limits annotations per request, so remaining annotations are appended with updates
+      for (let offset = ANNOTATIONS_PER_REQUEST; offset < annotations.length; offset += ANNOTATIONS_PER_REQUEST) {
+        await github.rest.checks.update({
+          owner: context.repo.owner,
+          repo: context.repo.repo,
+          check_run_id: checkRun.id,
+          output: { ...output, annotations: annotations.slice(offset, offset + ANNOTATIONS_PER_REQUEST) },
+        });
+      }
+
+      core.info(`Created check run ${checkRun.id}: ${checkRun.html_url}`);
+      return {
+        success: true,
+        checkRunId: checkRun.id,
+        url: checkRun.html_url,
+        headSha,
+        prNumber,
+        annotations: annotations.length,
+      };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to create check run: ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main, normalizeAnnotation };
@@ -0,0 +1,186 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    checks: {
+      create: vi.fn(),
+      update: vi.fn(),
+    },
+    pulls: {
+      get: vi.fn(),
+    },
+  },
+};
+
+const mockContext = {
+  eventName: "pull_request",
+  runId: 4242,
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+  payload: {
+    pull_request: {
+      number: 123,
+      head: { sha: "headsha123" },
+    },
+  },
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+describe("create_check_run (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    global.context = mockContext;
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    mockGithub.rest.checks.create.mockResolvedValue({ data: { id: 99, html_url: "https://github.com/testowner/testrepo/runs/99" } });
+    mockGithub.rest.checks.update.mockResolvedValue({});
+  });
+
+  it("should create a completed check run on the pull request head SHA", async () => {
+    const { main } = require("./create_check_run.cjs");
+    const handler = await main({ name: "AI Review" });
+
+    const result = await handler(
+      {
+        type: "create_check_run",
+        conclusion: "failure",
+        title: "1 issue found",
+        summary: "Found a problem",
+        annotations: [{ path: "./src/app.js", start_line: "10", message: "Unchecked error", annotation_level: "failure" }],
+      },
+      {}
+    );
+
+    expect(result.success).toBe(true);
+    expect(result.checkRunId).toBe(99);
+    expect(mockGithub.rest.checks.create).toHaveBeenCalledWith(
+      expect.objectContaining({
+        owner: "testowner",
+        repo: "testrepo",
+        name: "AI Review",
+        head_sha: "headsha123",
+        status: "completed",
+        conclusion: "failure",
+        details_url: "https://github.com/testowner/testrepo/actions/runs/4242",
+        output: {
+          title: "1 issue found",
+          summary: "Found a problem",
+          annotations: [{ path: "src/app.js", start_line: 10, end_line: 10, annotation_level: "failure", message: "Unchecked error" }],
+        },
+      })
+    );
+  });
+
+  it("should reject conclusions that are not allowed", async () => {
+    const { main } = require("./create_check_run.cjs");
+    const handler = await main({ allowed_conclusions: ["success", "neutral"] });
+
+    const result = await handler({ type: "create_check_run", conclusion: "failure", title: "t", summary: "s" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("not allowed");
+    expect(mockGithub.rest.checks.create).not.toHaveBeenCalled();
+  });
+
+  it("should enforce the max count", async () => {
+    const { main } = require("./create_check_run.cjs");
+    const handler = await main({ max: 1 });
+
+    await handler({ type: "create_check_run", conclusion: "success", title: "t", summary: "s" }, {});
+    const result = await handler({ type: "create_check_run", conclusion: "success", title: "t", summary: "s" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Max count of 1 reached");
+    expect(mockGithub.rest.checks.create).toHaveBeenCalledTimes(1);
+  });
+
+  it("should truncate annotations and send them in batches of 50", async () => {
+    const { main } = require("./create_check_run.cjs");
+    const handler = await main({ max_annotations: 120 });
+    const annotations = Array.from({ length: 130 }, (_, i) => ({ path: "a.js", start_line: i + 1, message: `finding ${i}` }));
+
+    const result = await handler({ type: "create_check_run", conclusion: "neutral", title: "t", summary: "s", annotations }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.annotations).toBe(120);
+    expect(mockGithub.rest.checks.create.mock.calls[0][0].output.annotations).toHaveLength(50);
+    expect(mockGithub.rest.checks.update).toHaveBeenCalledTimes(2);
+    expect(mockGithub.rest.checks.update.mock.calls[1][0].output.annotations).toHaveLength(20);
+    expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("Truncating 130 annotations"));
+  });
+
+  it("should skip invalid annotations", async () => {
+    const { main } = require("./create_check_run.cjs");
+    const handler = await main({});
+
+    const result = await handler(
+      {
+        type: "create_check_run",
+        conclusion: "neutral",
+        title: "t",
+        summary: "s",
+        annotations: [
+          { path: "a.js", message: "missing line" },
+          { path: "a.js", start_line: 5, message: "bad level", annotation_level: "error" },
+          { path: "a.js", start_line: 5, end_line: 7, message: "ok" },
+        ],
+      },
+      {}
+    );
+
+    expect(result.success).toBe(true);
+    expect(result.annotations).toBe(1);
+    expect(mockCore.warning).toHaveBeenCalledTimes(2);
+  });
+
+  it("should resolve the head SHA of an explicit pull request", async () => {
+    const { main } = require("./create_check_run.cjs");
+    const handler = await main({});
+    mockGithub.rest.pulls.get.mockResolvedValue({ data: { head: { sha: "othersha" } } });
+
+    const result = await handler({ type: "create_check_run", conclusion: "success", title: "t", summary: "s", pull_request_number: 7 }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.pulls.get).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", pull_number: 7 });
+    expect(mockGithub.rest.checks.create).toHaveBeenCalledWith(expect.objectContaining({ head_sha: "othersha" }));
+  });
+
+  it("should fail outside of a pull request context", async () => {
+    global.context = { ...mockContext, payload: {} };
+    const { main } = require("./create_check_run.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_check_run", conclusion: "success", title: "t", summary: "s" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("not in pull request context");
+  });
+
+  it("should preview without creating a check run in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./create_check_run.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_check_run", conclusion: "success", title: "t", summary: "s" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.rest.checks.create).not.toHaveBeenCalled();
+  });
+});
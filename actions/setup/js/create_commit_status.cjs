@@ -0,0 +1,121 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { sanitizeContent } = require("./sanitize_content.cjs");
+const { COMMIT_STATUS_STATES, getStatusContext, resolveStatusSha, renderTargetUrl, truncateDescription } = require("./commit_status_helpers.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "create_commit_status";
+
+/**
+ * Main handler factory for create_commit_status
+ * Returns a message handler function that processes individual create_commit_status messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  // Extract configuration
+  const statusContext = getStatusContext(config.context);
+  const maxCount = config.max || 1;
+  const allowedStates = config.allowed_states && config.allowed_states.length > 0 ? config.allowed_states : COMMIT_STATUS_STATES;
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Create commit status configuration: context=${statusContext}, max=${maxCount}`);
+  core.info(`Allowed states: ${allowedStates.join(", ")}`);
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single create_commit_status message
+   * @param {Object} message - The create_commit_status message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleCreateCommitStatus(message, resolvedTemporaryIds) {
+    // Check if we've hit the max limit
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+
+    if (!allowedStates.includes(item.state)) {
+      core.warning(`State '${item.state}' is not allowed`);
+      return {
+        success: false,
+        error: `State '${item.state}' is not allowed (allowed: ${allowedStates.join(", ")})`,
+      };
+    }
+
+    try {
+      const sha = await resolveStatusSha();
+      if (!sha) {
+        core.warning("Could not resolve the commit to attach the status to");
+        return {
+          success: false,
+          error: "Could not resolve the commit to attach the status to",
+        };
+      }
+
+      const description = item.description ? truncateDescription(sanitizeContent(item.description, { maxLength: 140 })) : undefined;
+      const targetUrl = renderTargetUrl(config.target_url, sha);
+
+      // If in staged mode, preview without executing
+      if (isStaged) {
+        core.info(`Staged mode: Would set commit status '${statusContext}' to ${item.state} on ${sha}`);
+        return {
+          success: true,
+          staged: true,
+          previewInfo: {
+            context: statusContext,
+            state: item.state,
+            sha,
+            description,
+          },
+        };
+      }
+
+      core.info(`Setting commit status '${statusContext}' to ${item.state} on ${sha}`);
+      const { data: status } = await github.rest.repos.createCommitStatus({
+        owner: context.repo.owner,
+        repo: context.repo.repo,
+        sha,
+        state: item.state,
+        context: statusContext,
+        target_url: targetUrl,
+        ...(description ? { description } : {}),
+      });
+
+      core.info(`Set commit status ${status.id} on ${sha}`);
+      return {
+        success: true,
+        statusId: status.id,
+        context: statusContext,
+        state: item.state,
+        sha,
+      };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to set commit status: ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main };
@@ -0,0 +1,232 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    repos: {
+      createCommitStatus: vi.fn(),
+      listCommitStatusesForRef: vi.fn(),
+    },
+    pulls: {
+      get: vi.fn(),
+    },
+  },
+};
+
+const mockContext = {
+  eventName: "pull_request",
+  runId: 4242,
+  sha: "mergesha456",
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+  payload: {
+    pull_request: {
+      number: 123,
+      head: { sha: "headsha123" },
+    },
+  },
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+const RUN_URL = "https://github.com/testowner/testrepo/actions/runs/4242";
+
+describe("create_commit_status (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    global.context = mockContext;
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    delete process.env.GH_AW_COMMIT_STATUS_CONTEXT;
+    delete process.env.GH_AW_COMMIT_STATUS_TARGET_URL;
+    process.env.GH_AW_WORKFLOW_NAME = "Changelog check";
+    mockGithub.rest.repos.createCommitStatus.mockResolvedValue({ data: { id: 77 } });
+  });
+
+  it("should set the status on the pull request head SHA with the configured context", async () => {
+    const { main } = require("./create_commit_status.cjs");
+    const handler = await main({ context: "agentic/changelog" });
+
+    const result = await handler({ type: "create_commit_status", state: "failure", description: "Changelog entry missing" }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.repos.createCommitStatus).toHaveBeenCalledWith({
+      owner: "testowner",
+      repo: "testrepo",
+      sha: "headsha123",
+      state: "failure",
+      context: "agentic/changelog",
+      target_url: RUN_URL,
+      description: "Changelog entry missing",
+    });
+  });
+
+  it("should default the context to the workflow name and render the target URL template", async () => {
+    const { main } = require("./create_commit_status.cjs");
+    const handler = await main({ target_url: "{run_url}#summary-{sha}" });
+
+    await handler({ type: "create_commit_status", state: "success" }, {});
+
+    expect(mockGithub.rest.repos.createCommitStatus).toHaveBeenCalledWith(
+      expect.objectContaining({ context: "Changelog check", target_url: `${RUN_URL}#summary-headsha123` })
+    );
+  });
+
+  it("should fall back to the run URL when the rendered target URL is not http(s)", async () => {
+    const { main } = require("./create_commit_status.cjs");
+    const handler = await main({ target_url: "javascript:{sha}" });
+
+    await handler({ type: "create_commit_status", state: "success" }, {});
+
+    expect(mockGithub.rest.repos.createCommitStatus).toHaveBeenCalledWith(expect.objectContaining({ target_url: RUN_URL }));
+    expect(mockCore.warning).toHaveBeenCalled();
+  });
+
+  it("should reject states outside allowed_states", async () => {
+    const { main } = require("./create_commit_status.cjs");
+    const handler = await main({ allowed_states: ["success", "failure"] });
+
+    const result = await handler({ type: "create_commit_status", state: "pending" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("State 'pending' is not allowed");
+    expect(mockGithub.rest.repos.createCommitStatus).not.toHaveBeenCalled();
+  });
+
+  it("should use the triggering SHA outside pull request events", async () => {
+    global.context = { ...mockContext, eventName: "push", payload: {} };
+    const { main } = require("./create_commit_status.cjs");
+    const handler = await main({});
+
+    await handler({ type: "create_commit_status", state: "success" }, {});
+
+    expect(mockGithub.rest.repos.createCommitStatus).toHaveBeenCalledWith(expect.objectContaining({ sha: "mergesha456" }));
+  });
+
+  it("should look up the head SHA for comments on pull requests", async () => {
+    global.context = { ...mockContext, eventName: "issue_comment", payload: { issue: { number: 9, pull_request: {} } } };
+    mockGithub.rest.pulls.get.mockResolvedValue({ data: { head: { sha: "prhead999" } } });
+    const { main } = require("./create_commit_status.cjs");
+    const handler = await main({});
+
+    await handler({ type: "create_commit_status", state: "success" }, {});
+
+    expect(mockGithub.rest.pulls.get).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", pull_number: 9 });
+    expect(mockGithub.rest.repos.createCommitStatus).toHaveBeenCalledWith(expect.objectContaining({ sha: "prhead999" }));
+  });
+
+  it("should enforce the max count", async () => {
+    const { main } = require("./create_commit_status.cjs");
+    const handler = await main({ max: 1 });
+
+    await handler({ type: "create_commit_status", state: "success" }, {});
+    const result = await handler({ type: "create_commit_status", state: "failure" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Max count of 1 reached");
+  });
+
+  it("should preview without setting a status in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./create_commit_status.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_commit_status", state: "success" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.rest.repos.createCommitStatus).not.toHaveBeenCalled();
+  });
+});
+
+describe("set_pending_commit_status", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    global.context = mockContext;
+    process.env.GH_AW_WORKFLOW_NAME = "Changelog check";
+    process.env.GH_AW_COMMIT_STATUS_CONTEXT = "agentic/changelog";
+    delete process.env.GH_AW_COMMIT_STATUS_TARGET_URL;
+    mockGithub.rest.repos.createCommitStatus.mockResolvedValue({ data: { id: 1 } });
+  });
+
+  it("should mark the head commit as pending", async () => {
+    const { main } = require("./set_pending_commit_status.cjs");
+
+    await main();
+
+    expect(mockGithub.rest.repos.createCommitStatus).toHaveBeenCalledWith({
+      owner: "testowner",
+      repo: "testrepo",
+      sha: "headsha123",
+      state: "pending",
+      context: "agentic/changelog",
+      description: "Agentic workflow is running",
+      target_url: RUN_URL,
+    });
+  });
+
+  it("should only warn when the status API fails", async () => {
+    mockGithub.rest.repos.createCommitStatus.mockRejectedValue(new Error("Resource not accessible"));
+    const { main } = require("./set_pending_commit_status.cjs");
+
+    await main();
+
+    expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("Resource not accessible"));
+    expect(mockCore.setFailed).not.toHaveBeenCalled();
+  });
+});
+
+describe("resolve_commit_status", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    global.context = mockContext;
+    process.env.GH_AW_WORKFLOW_NAME = "Changelog check";
+    process.env.GH_AW_COMMIT_STATUS_CONTEXT = "agentic/changelog";
+    process.env.GH_AW_AGENT_CONCLUSION = "failure";
+    mockGithub.rest.repos.createCommitStatus.mockResolvedValue({ data: { id: 2 } });
+  });
+
+  it("should resolve a pending status left by pre-activation to error", async () => {
+    mockGithub.rest.repos.listCommitStatusesForRef.mockResolvedValue({
+      data: [
+        { context: "ci/build", state: "success", description: "ok" },
+        { context: "agentic/changelog", state: "pending", description: "Agentic workflow is running" },
+      ],
+    });
+    const { main } = require("./resolve_commit_status.cjs");
+
+    await main();
+
+    expect(mockGithub.rest.repos.createCommitStatus).toHaveBeenCalledWith(
+      expect.objectContaining({ sha: "headsha123", state: "error", context: "agentic/changelog", description: "Agentic workflow did not complete (agent job: failure)" })
+    );
+  });
+
+  it("should leave statuses reported by the agent alone", async () => {
+    mockGithub.rest.repos.listCommitStatusesForRef.mockResolvedValue({
+      data: [
+        { context: "agentic/changelog", state: "success", description: "Changelog found" },
+        { context: "agentic/changelog", state: "pending", description: "Agentic workflow is running" },
+      ],
+    });
+    const { main } = require("./resolve_commit_status.cjs");
+
+    await main();
+
+    expect(mockGithub.rest.repos.createCommitStatus).not.toHaveBeenCalled();
+  });
+});
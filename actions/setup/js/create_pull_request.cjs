@@ -806,7 +806,9 @@ ${patchPreview}`;
         pull_request_number: pullRequest.number,
         pull_request_url: pullRequest.html_url,
         branch_name: branchName,
-        temporary_id: temporaryId,
+        // temporaryId and number let the handler manager resolve #aw_ID references to this PR
+        temporaryId: temporaryId,
+        number: pullRequest.number,
         repo: itemRepo,
       };
     } catch (prError) {
@@ -0,0 +1,173 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const fs = require("fs");
+const path = require("path");
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { buildAIFooter } = require("./update_pr_description_helpers.cjs");
+const { isTagAllowed, isValidTagName, getTagSha, createTagRef } = require("./create_tag.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "create_release";
+
+/** Directory the release assets are downloaded to from the agent artifacts */
+const RELEASE_ASSETS_DIR = "/tmp/gh-aw/safeoutputs/release-assets";
+
+/**
+ * Uploads a release asset from the release assets directory
+ * @param {number} releaseId - Release ID
+ * @param {string} fileName - Asset file name
+ * @returns {Promise<string>} Download URL of the uploaded asset
+ */
+async function uploadReleaseAsset(releaseId, fileName) {
+  const filePath = path.join(RELEASE_ASSETS_DIR, path.basename(fileName));
+  if (!fs.existsSync(filePath)) {
+    throw new Error(`Release asset not found in agent artifacts: ${fileName}`);
+  }
+  const data = fs.readFileSync(filePath);
+  const { data: asset } = await github.rest.repos.uploadReleaseAsset({
+    owner: context.repo.owner,
+    repo: context.repo.repo,
+    release_id: releaseId,
+    name: path.basename(fileName),
+    // @ts-ignore - the REST client accepts a Buffer for binary uploads
+    data,
+    headers: {
+      "content-type": "application/octet-stream",
+      "content-length": data.length,
+    },
+  });
+  return asset.browser_download_url;
+}
+
+/**
+ * Main handler factory for create_release
+ * Returns a message handler function that processes individual create_release messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  const maxCount = config.max || 1;
+  const allowedTags = config.allowed_tags || [];
+  const forceDraft = config.draft !== false; // Default to true (always create drafts)
+  const allowedExts = config.allowed_exts || [];
+  const includeFooter = config.footer !== false; // Default to true (include footer)
+  const workflowName = process.env.GH_AW_WORKFLOW_NAME || "GitHub Agentic Workflow";
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Create release configuration: max=${maxCount}, force_draft=${forceDraft}`);
+  if (allowedTags.length > 0) {
+    core.info(`Allowed tags: ${allowedTags.join(", ")}`);
+  }
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single create_release message
+   * @param {Object} message - The create_release message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleCreateRelease(message, resolvedTemporaryIds) {
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+    const tag = String(item.tag || "").trim();
+    if (!isValidTagName(tag)) {
+      return { success: false, error: `Invalid tag name: '${tag}'` };
+    }
+    if (!isTagAllowed(tag, allowedTags)) {
+      core.warning(`Tag '${tag}' does not match allowed-tags`);
+      return { success: false, error: `Tag '${tag}' does not match allowed tag patterns (${allowedTags.join(", ")})` };
+    }
+
+    // Assets were validated by the safe outputs MCP server; re-check the extension as defense in depth
+    const assets = Array.isArray(item.assets) ? item.assets.map(String) : [];
+    const rejectedAsset = allowedExts.length > 0 ? assets.find(name => !allowedExts.some(ext => name.toLowerCase().endsWith(String(ext).toLowerCase()))) : undefined;
+    if (rejectedAsset) {
+      return { success: false, error: `Release asset '${rejectedAsset}' has an extension that is not allowed` };
+    }
+
+    const draft = forceDraft || item.draft === true;
+    if (forceDraft && item.draft === false) {
+      core.info("draft: false requested by the agent, but releases are configured to always be drafts");
+    }
+
+    if (isStaged) {
+      core.info(`Staged mode: Would create ${draft ? "draft " : ""}release '${tag}' with ${assets.length} asset(s)`);
+      return {
+        success: true,
+        staged: true,
+        previewInfo: { tag, draft, assets },
+      };
+    }
+
+    try {
+      // Create the tag at the requested commit when it does not exist yet.
+      // Draft releases do not create their tag until published, so the tag is created explicitly.
+      const existingSha = await getTagSha(tag);
+      if (!existingSha) {
+        const sha = item.target_commitish && /^[0-9a-fA-F]{7,40}$/.test(item.target_commitish) ? item.target_commitish : null;
+        if (!sha) {
+          return { success: false, error: `Tag '${tag}' does not exist. Provide target_commitish as a commit SHA to create it` };
+        }
+        core.info(`Creating tag '${tag}' at ${sha}`);
+        await createTagRef(tag, sha);
+      }
+
+      const runUrl = `${context.serverUrl}/${context.repo.owner}/${context.repo.repo}/actions/runs/${context.runId}`;
+      const body = includeFooter ? item.body + buildAIFooter(workflowName, runUrl) : item.body;
+
+      core.info(`Creating ${draft ? "draft " : ""}release for tag '${tag}'`);
+      const { data: release } = await github.rest.repos.createRelease({
+        owner: context.repo.owner,
+        repo: context.repo.repo,
+        tag_name: tag,
+        name: item.name || tag,
+        body,
+        draft,
+        prerelease: item.prerelease === true,
+      });
+
+      const uploadedAssets = [];
+      for (const asset of assets) {
+        core.info(`Uploading release asset: ${asset}`);
+        uploadedAssets.push(await uploadReleaseAsset(release.id, asset));
+      }
+
+      core.info(`Created release ${release.id}: ${release.html_url}`);
+      return {
+        success: true,
+        tag,
+        draft,
+        url: release.html_url,
+        id: release.id,
+        releaseId: release.id,
+        assets: uploadedAssets,
+      };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to create release '${tag}': ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main };
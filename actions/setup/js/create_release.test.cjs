@@ -0,0 +1,165 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import fs from "fs";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    git: {
+      getRef: vi.fn(),
+      createRef: vi.fn(),
+    },
+    repos: {
+      createRelease: vi.fn(),
+      uploadReleaseAsset: vi.fn(),
+    },
+  },
+};
+
+const mockContext = {
+  runId: 4242,
+  sha: "abc1234def",
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+const RELEASE_ASSETS_DIR = "/tmp/gh-aw/safeoutputs/release-assets";
+const notFound = Object.assign(new Error("Not Found"), { status: 404 });
+
+describe("create_release (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    process.env.GH_AW_WORKFLOW_NAME = "Release Bot";
+    mockGithub.rest.git.getRef.mockResolvedValue({ data: { object: { sha: "existingsha" } } });
+    mockGithub.rest.git.createRef.mockResolvedValue({});
+    mockGithub.rest.repos.createRelease.mockResolvedValue({ data: { id: 7, html_url: "https://github.com/testowner/testrepo/releases/tag/v1.0.0" } });
+    mockGithub.rest.repos.uploadReleaseAsset.mockResolvedValue({ data: { browser_download_url: "https://github.com/testowner/testrepo/releases/download/v1.0.0/app.zip" } });
+  });
+
+  afterEach(() => {
+    fs.rmSync(RELEASE_ASSETS_DIR, { recursive: true, force: true });
+  });
+
+  it("should create a draft release with a footer by default", async () => {
+    const { main } = require("./create_release.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_release", tag: "v1.0.0", body: "Notes", draft: false }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.draft).toBe(true);
+    const args = mockGithub.rest.repos.createRelease.mock.calls[0][0];
+    expect(args).toEqual(expect.objectContaining({ owner: "testowner", repo: "testrepo", tag_name: "v1.0.0", name: "v1.0.0", draft: true, prerelease: false }));
+    expect(args.body).toContain("Notes");
+    expect(args.body).toContain("Release Bot");
+    expect(mockGithub.rest.git.createRef).not.toHaveBeenCalled();
+  });
+
+  it("should let the agent publish when drafts are not forced", async () => {
+    const { main } = require("./create_release.cjs");
+    const handler = await main({ draft: false, footer: false });
+
+    const result = await handler({ type: "create_release", tag: "v1.0.0", body: "Notes", prerelease: true }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.repos.createRelease).toHaveBeenCalledWith(expect.objectContaining({ draft: false, prerelease: true, body: "Notes" }));
+  });
+
+  it("should reject tags that do not match allowed-tags", async () => {
+    const { main } = require("./create_release.cjs");
+    const handler = await main({ allowed_tags: ["v*"] });
+
+    const result = await handler({ type: "create_release", tag: "nightly", body: "Notes" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("does not match allowed tag patterns");
+    expect(mockGithub.rest.repos.createRelease).not.toHaveBeenCalled();
+  });
+
+  it("should create a missing tag at target_commitish", async () => {
+    mockGithub.rest.git.getRef.mockRejectedValue(notFound);
+    const { main } = require("./create_release.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_release", tag: "v2.0.0", body: "Notes", target_commitish: "0123456789abcdef" }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.git.createRef).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", ref: "refs/tags/v2.0.0", sha: "0123456789abcdef" });
+  });
+
+  it("should fail for a missing tag without a commit SHA", async () => {
+    mockGithub.rest.git.getRef.mockRejectedValue(notFound);
+    const { main } = require("./create_release.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_release", tag: "v2.0.0", body: "Notes" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("does not exist");
+    expect(mockGithub.rest.repos.createRelease).not.toHaveBeenCalled();
+  });
+
+  it("should upload staged release assets", async () => {
+    fs.mkdirSync(RELEASE_ASSETS_DIR, { recursive: true });
+    fs.writeFileSync(`${RELEASE_ASSETS_DIR}/app.zip`, "zip-content");
+    const { main } = require("./create_release.cjs");
+    const handler = await main({ allowed_exts: [".zip"] });
+
+    const result = await handler({ type: "create_release", tag: "v1.0.0", body: "Notes", assets: ["app.zip"] }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.assets).toHaveLength(1);
+    expect(mockGithub.rest.repos.uploadReleaseAsset).toHaveBeenCalledWith(expect.objectContaining({ release_id: 7, name: "app.zip" }));
+  });
+
+  it("should reject assets with disallowed extensions", async () => {
+    const { main } = require("./create_release.cjs");
+    const handler = await main({ allowed_exts: [".zip"] });
+
+    const result = await handler({ type: "create_release", tag: "v1.0.0", body: "Notes", assets: ["install.sh"] }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("install.sh");
+    expect(mockGithub.rest.repos.createRelease).not.toHaveBeenCalled();
+  });
+
+  it("should enforce the max count", async () => {
+    const { main } = require("./create_release.cjs");
+    const handler = await main({ max: 1 });
+
+    await handler({ type: "create_release", tag: "v1.0.0", body: "Notes" }, {});
+    const result = await handler({ type: "create_release", tag: "v1.0.1", body: "Notes" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Max count of 1 reached");
+  });
+
+  it("should preview without creating a release in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./create_release.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_release", tag: "v1.0.0", body: "Notes" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.rest.repos.createRelease).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,156 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { globPatternToRegex } = require("./glob_pattern_helpers.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "create_tag";
+
+/**
+ * Checks a tag name against the configured tag patterns
+ * @param {string} tag - Tag name
+ * @param {string[]} allowedTags - Glob patterns (empty allows any tag)
+ * @returns {boolean}
+ */
+function isTagAllowed(tag, allowedTags) {
+  if (!allowedTags || allowedTags.length === 0) {
+    return true;
+  }
+  return allowedTags.some(pattern => globPatternToRegex(pattern).test(tag));
+}
+
+/**
+ * Validates a tag name against git ref naming rules
+ * @param {string} tag - Tag name
+ * @returns {boolean}
+ */
+function isValidTagName(tag) {
+  return typeof tag === "string" && /^[A-Za-z0-9][A-Za-z0-9._\/-]*$/.test(tag) && !tag.includes("..") && !tag.endsWith(".lock") && !tag.endsWith("/") && !tag.endsWith(".");
+}
+
+/**
+ * Returns the SHA a tag points to, or null if the tag does not exist
+ * @param {string} tag - Tag name
+ * @returns {Promise<string|null>}
+ */
+async function getTagSha(tag) {
+  try {
+    const { data: ref } = await github.rest.git.getRef({
+      owner: context.repo.owner,
+      repo: context.repo.repo,
+      ref: `tags/${tag}`,
+    });
+    return ref.object.sha;
+  } catch (error) {
+    if (/** @type {any} */ (error)?.status === 404) {
+      return null;
+    }
+    throw error;
+  }
+}
+
+/**
+ * Creates a lightweight tag at a commit
+ * @param {string} tag - Tag name
+ * @param {string} sha - Commit SHA
+ * @returns {Promise<void>}
+ */
+async function createTagRef(tag, sha) {
+  await github.rest.git.createRef({
+    owner: context.repo.owner,
+    repo: context.repo.repo,
+    ref: `refs/tags/${tag}`,
+    sha,
+  });
+}
+
+/**
+ * Main handler factory for create_tag
+ * Returns a message handler function that processes individual create_tag messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  const maxCount = config.max || 1;
+  const allowedTags = config.allowed_tags || [];
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Create tag configuration: max=${maxCount}`);
+  if (allowedTags.length > 0) {
+    core.info(`Allowed tags: ${allowedTags.join(", ")}`);
+  }
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single create_tag message
+   * @param {Object} message - The create_tag message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleCreateTag(message, resolvedTemporaryIds) {
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+    const tag = String(item.tag || "").trim();
+    if (!isValidTagName(tag)) {
+      return { success: false, error: `Invalid tag name: '${tag}'` };
+    }
+    if (!isTagAllowed(tag, allowedTags)) {
+      core.warning(`Tag '${tag}' does not match allowed-tags`);
+      return { success: false, error: `Tag '${tag}' does not match allowed tag patterns (${allowedTags.join(", ")})` };
+    }
+
+    const sha = item.sha || context.sha;
+
+    if (isStaged) {
+      core.info(`Staged mode: Would create tag '${tag}' at ${sha}`);
+      return {
+        success: true,
+        staged: true,
+        previewInfo: { tag, sha },
+      };
+    }
+
+    try {
+      const existingSha = await getTagSha(tag);
+      if (existingSha) {
+        return { success: false, error: `Tag '${tag}' already exists (at ${existingSha})` };
+      }
+
+      core.info(`Creating tag '${tag}' at ${sha}`);
+      await createTagRef(tag, sha);
+
+      return {
+        success: true,
+        tag,
+        sha,
+        url: `${context.serverUrl}/${context.repo.owner}/${context.repo.repo}/releases/tag/${encodeURIComponent(tag)}`,
+      };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to create tag '${tag}': ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main, isTagAllowed, isValidTagName, getTagSha, createTagRef };
@@ -0,0 +1,99 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    git: {
+      getRef: vi.fn(),
+      createRef: vi.fn(),
+    },
+  },
+};
+
+const mockContext = {
+  sha: "abc1234def",
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+const notFound = Object.assign(new Error("Not Found"), { status: 404 });
+
+describe("create_tag (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    mockGithub.rest.git.getRef.mockRejectedValue(notFound);
+    mockGithub.rest.git.createRef.mockResolvedValue({});
+  });
+
+  it("should create a lightweight tag at the triggering commit", async () => {
+    const { main } = require("./create_tag.cjs");
+    const handler = await main({ allowed_tags: ["v*"] });
+
+    const result = await handler({ type: "create_tag", tag: "v1.0.0" }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.git.createRef).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", ref: "refs/tags/v1.0.0", sha: "abc1234def" });
+  });
+
+  it("should not move an existing tag", async () => {
+    mockGithub.rest.git.getRef.mockResolvedValue({ data: { object: { sha: "oldsha" } } });
+    const { main } = require("./create_tag.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_tag", tag: "v1.0.0", sha: "0123456" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("already exists");
+    expect(mockGithub.rest.git.createRef).not.toHaveBeenCalled();
+  });
+
+  it("should reject tags that do not match allowed-tags", async () => {
+    const { main } = require("./create_tag.cjs");
+    const handler = await main({ allowed_tags: ["v*"] });
+
+    const result = await handler({ type: "create_tag", tag: "latest" }, {});
+
+    expect(result.success).toBe(false);
+    expect(mockGithub.rest.git.createRef).not.toHaveBeenCalled();
+  });
+
+  it("should reject invalid tag names", async () => {
+    const { isValidTagName } = require("./create_tag.cjs");
+
+    expect(isValidTagName("v1.2.3")).toBe(true);
+    expect(isValidTagName("release/2024-01")).toBe(true);
+    expect(isValidTagName("v1..2")).toBe(false);
+    expect(isValidTagName("-v1")).toBe(false);
+    expect(isValidTagName("v1 beta")).toBe(false);
+  });
+
+  it("should preview without creating a tag in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./create_tag.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "create_tag", tag: "v1.0.0" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.rest.git.createRef).not.toHaveBeenCalled();
+  });
+});
@@ -20,6 +20,9 @@ async function main(config = {}) {
   const allowedWorkflows = config.workflows || [];
   const maxCount = config.max || 1;
   const workflowFiles = config.workflow_files || {}; // Map of workflow name to file extension
+  /** @type {Record<string, {repo: string, workflow: string, ref?: string, token_env?: string}>} */
+  const targets = config.targets || {}; // Cross-repository workflows keyed by owner/repo/workflow
+  const allowedRepos = config.allowed_repos || [];
 
   core.info(`Dispatch workflow configuration: max=${maxCount}`);
   if (allowedWorkflows.length > 0) {
@@ -73,6 +76,73 @@ async function main(config = {}) {
     core.info(`Using default branch ref: ${ref}`);
   }
 
+  /** @type {Map<string, any>} Authenticated clients for cross-repository targets, keyed by token env var */
+  const targetClients = new Map();
+
+  /**
+   * Get the client used to dispatch a cross-repository workflow. Targets with a token use
+   * their own client; the default client is used otherwise.
+   * @param {{token_env?: string}} target - Cross-repository target configuration
+   * @returns {Promise<any>} Octokit client
+   */
+  const getTargetClient = async target => {
+    const tokenEnv = target.token_env;
+    if (!tokenEnv || !process.env[tokenEnv]) {
+      if (tokenEnv) {
+        core.warning(`${tokenEnv} is empty - dispatching with the default token`);
+      }
+      return github;
+    }
+    if (!targetClients.has(tokenEnv)) {
+      // Lazy-load @actions/github only when a cross-repository token is configured
+      const { getOctokit } = await import("@actions/github");
+      targetClients.set(tokenEnv, getOctokit(process.env[tokenEnv] || ""));
+    }
+    return targetClients.get(tokenEnv);
+  };
+
+  /**
+   * Dispatch a workflow in another repository
+   * @param {any} client - Octokit client
+   * @param {string} workflowName - Workflow reference (owner/repo/workflow)
+   * @param {{repo: string, workflow: string, ref?: string}} target - Cross-repository target configuration
+   * @param {Record<string, string>} inputs - Workflow inputs
+   * @returns {Promise<string>} Dispatched workflow file
+   */
+  const dispatchCrossRepoWorkflow = async (client, workflowName, target, inputs) => {
+    const [owner, repoName] = target.repo.split("/");
+
+    let targetRef = target.ref;
+    if (!targetRef) {
+      const { data: repoData } = await client.rest.repos.get({ owner, repo: repoName });
+      targetRef = repoData.default_branch;
+    }
+
+    // Use the extension resolved at compile time; otherwise try the compiled lock file first
+    const extensions = workflowFiles[workflowName] ? [workflowFiles[workflowName]] : [".lock.yml", ".yml"];
+    for (let i = 0; i < extensions.length; i++) {
+      const workflowFile = `${target.workflow}${extensions[i]}`;
+      try {
+        core.info(`Dispatching workflow: ${target.repo}/${workflowFile} on ${targetRef}`);
+        await client.rest.actions.createWorkflowDispatch({
+          owner,
+          repo: repoName,
+          workflow_id: workflowFile,
+          ref: targetRef,
+          inputs: inputs,
+        });
+        return `${target.repo}/${workflowFile}`;
+      } catch (error) {
+        // @ts-ignore
+        const notFound = error && error.status === 404;
+        if (!notFound || i === extensions.length - 1) {
+          throw error;
+        }
+      }
+    }
+    throw new Error(`Workflow "${workflowName}" not found`);
+  };
+
   /**
    * Message handler function that processes a single dispatch_workflow message
    * @param {Object} message - The dispatch_workflow message to process
@@ -141,6 +211,31 @@ async function main(config = {}) {
         }
       }
 
+      // Workflows in other repositories are dispatched with their target's token and ref
+      const target = targets[workflowName];
+      if (target) {
+        if (allowedRepos.length > 0 && !allowedRepos.includes(target.repo)) {
+          const error = `Repository "${target.repo}" is not in the allowed repositories list: ${allowedRepos.join(", ")}`;
+          core.warning(error);
+          return {
+            success: false,
+            error: error,
+          };
+        }
+
+        const client = await getTargetClient(target);
+        const dispatchedFile = await dispatchCrossRepoWorkflow(client, workflowName, target, inputs);
+        core.info(`✓ Successfully dispatched workflow: ${dispatchedFile}`);
+        lastDispatchTime = Date.now();
+
+        return {
+          success: true,
+          workflow_name: workflowName,
+          repo: target.repo,
+          inputs: inputs,
+        };
+      }
+
       // Get the workflow file extension from compile-time resolution
       const extension = workflowFiles[workflowName];
       if (!extension) {
@@ -441,4 +441,57 @@ describe("dispatch_workflow handler factory", () => {
       inputs: {},
     });
   });
+
+  it("should dispatch cross-repository workflows to the target repository's default branch", async () => {
+    github.rest.repos.get.mockResolvedValueOnce({ data: { default_branch: "trunk" } });
+    const handler = await main({
+      workflows: ["acme/payments/deploy"],
+      workflow_files: { "acme/payments/deploy": ".lock.yml" },
+      targets: { "acme/payments/deploy": { repo: "acme/payments", workflow: "deploy" } },
+      allowed_repos: ["acme/payments"],
+    });
+
+    const result = await handler({ type: "dispatch_workflow", workflow_name: "acme/payments/deploy", inputs: { env: "staging" } }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.repo).toBe("acme/payments");
+    expect(github.rest.repos.get).toHaveBeenCalledWith({ owner: "acme", repo: "payments" });
+    expect(github.rest.actions.createWorkflowDispatch).toHaveBeenCalledWith({
+      owner: "acme",
+      repo: "payments",
+      workflow_id: "deploy.lock.yml",
+      ref: "trunk",
+      inputs: { env: "staging" },
+    });
+  });
+
+  it("should use the configured ref and fall back to .yml when the extension is unknown", async () => {
+    const notFound = Object.assign(new Error("Not Found"), { status: 404 });
+    github.rest.actions.createWorkflowDispatch.mockRejectedValueOnce(notFound);
+    const handler = await main({
+      workflows: ["acme/billing/release"],
+      targets: { "acme/billing/release": { repo: "acme/billing", workflow: "release", ref: "release/v2" } },
+    });
+
+    const result = await handler({ type: "dispatch_workflow", workflow_name: "acme/billing/release", inputs: {} }, {});
+
+    expect(result.success).toBe(true);
+    expect(github.rest.repos.get).not.toHaveBeenCalled();
+    expect(github.rest.actions.createWorkflowDispatch).toHaveBeenCalledTimes(2);
+    expect(github.rest.actions.createWorkflowDispatch).toHaveBeenLastCalledWith(expect.objectContaining({ workflow_id: "release.yml", ref: "release/v2" }));
+  });
+
+  it("should reject cross-repository workflows outside the allowed repositories", async () => {
+    const handler = await main({
+      workflows: ["acme/other/deploy"],
+      targets: { "acme/other/deploy": { repo: "acme/other", workflow: "deploy" } },
+      allowed_repos: ["acme/payments"],
+    });
+
+    const result = await handler({ type: "dispatch_workflow", workflow_name: "acme/other/deploy", inputs: {} }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("not in the allowed repositories list");
+    expect(github.rest.actions.createWorkflowDispatch).not.toHaveBeenCalled();
+  });
 });
@@ -45,6 +45,7 @@ function interpolateVariables(content, variables) {
 
 /**
  * Renders a Markdown template by processing {{#if}} conditional blocks.
+ * A block can have an {{else}} branch, which is kept when the condition is falsy.
  * When a conditional block is removed (falsy condition) and the template tags
  * were on their own lines, the empty lines are cleaned up to avoid
  * leaving excessive blank lines in the output.
@@ -53,6 +54,26 @@ function interpolateVariables(content, variables) {
  */
 function renderMarkdownTemplate(markdown) {
   core.info(`[renderMarkdownTemplate] Starting template rendering`);
+
+  /**
+   * Splits the body of a conditional at its {{else}} tag; an {{else}} alone on its line is removed with its line
+   * @param {string} body - Body of the conditional
+   * @returns {{thenBranch: string, elseBranch: string|null}}
+   */
+  const splitElseBranch = body => {
+    const index = body.indexOf("{{else}}");
+    if (index === -1) {
+      return { thenBranch: body, elseBranch: null };
+    }
+    const end = index + "{{else}}".length;
+    const lineStart = body.lastIndexOf("\n", index - 1) + 1;
+    const newline = body.indexOf("\n", end);
+    const lineEnd = newline === -1 ? body.length : newline + 1;
+    if (body.slice(lineStart, index).trim() === "" && body.slice(end, lineEnd).trim() === "") {
+      return { thenBranch: body.slice(0, lineStart), elseBranch: body.slice(lineEnd) };
+    }
+    return { thenBranch: body.slice(0, index), elseBranch: body.slice(end) };
+  };
   core.info(`[renderMarkdownTemplate] Input length: ${markdown.length} characters`);
 
   // Count conditionals before processing
@@ -76,11 +97,17 @@ function renderMarkdownTemplate(markdown) {
     core.info(`[renderMarkdownTemplate] Block ${blockCount}: condition="${condTrimmed}" -> ${truthyResult ? "KEEP" : "REMOVE"}`);
     core.info(`[renderMarkdownTemplate]   Body preview: "${bodyPreview}${body.length > 60 ? "..." : ""}"`);
 
+    const { thenBranch, elseBranch } = splitElseBranch(body);
     if (truthyResult) {
       // Keep body with leading newline if there was one before the opening tag
       keptBlocks++;
       core.info(`[renderMarkdownTemplate]   Action: Keeping body with leading newline=${!!leadNL}`);
-      return leadNL + body;
+      return leadNL + thenBranch;
+    } else if (elseBranch !== null) {
+      // Keep the else branch in place of the block
+      removedBlocks++;
+      core.info(`[renderMarkdownTemplate]   Action: Keeping else branch with leading newline=${!!leadNL}`);
+      return leadNL + elseBranch;
     } else {
       // Remove entire block completely - the line containing the template is removed
       removedBlocks++;
@@ -105,12 +132,13 @@ function renderMarkdownTemplate(markdown) {
     core.info(`[renderMarkdownTemplate] Inline ${inlineCount}: condition="${condTrimmed}" -> ${truthyResult ? "KEEP" : "REMOVE"}`);
     core.info(`[renderMarkdownTemplate]   Body preview: "${bodyPreview}${body.length > 40 ? "..." : ""}"`);
 
+    const { thenBranch, elseBranch } = splitElseBranch(body);
     if (truthyResult) {
       keptInline++;
-      return body;
+      return thenBranch;
     } else {
       removedInline++;
-      return "";
+      return elseBranch ?? "";
     }
   });
 
@@ -85,6 +85,12 @@ describe("interpolate_prompt", () => {
           const output = renderMarkdownTemplate("# Title\n\n{{#if false}}\n## Hidden Section\nThis should be removed.\n{{/if}}\n\n## Visible Section\nThis is always visible.");
           expect(output).toBe("# Title\n\n## Visible Section\nThis is always visible.");
         }),
+        it("should render the else branch of falsy blocks", () => {
+          (expect(renderMarkdownTemplate("{{#if false}}\nStrict\n{{else}}\nLenient\n{{/if}}\nEnd")).toBe("Lenient\nEnd"),
+            expect(renderMarkdownTemplate("{{#if true}}\nStrict\n{{else}}\nLenient\n{{/if}}\nEnd")).toBe("Strict\nEnd"),
+            expect(renderMarkdownTemplate("Item{{#if false}}.{{else}},{{/if}}")).toBe("Item,"),
+            expect(renderMarkdownTemplate("Item{{#if true}}.{{else}},{{/if}}")).toBe("Item."));
+        }),
         it("should collapse multiple false blocks without excessive empty lines", () => {
           const output = renderMarkdownTemplate("Start\n\n{{#if false}}\nBlock 1\n{{/if}}\n\n{{#if false}}\nBlock 2\n{{/if}}\n\n{{#if false}}\nBlock 3\n{{/if}}\n\nEnd");
           (expect(output).not.toMatch(/\n{3,}/), expect(output).toContain("Start"), expect(output).toContain("End"));
@@ -0,0 +1,127 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { resolveTargetRepoConfig, resolveAndValidateRepo } = require("./repo_helpers.cjs");
+const { resolveModerationTarget, fetchModerationTarget } = require("./moderation_helpers.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "lock_conversation";
+
+/** Lock reasons accepted by the GitHub lock API */
+const LOCK_REASONS = ["off-topic", "too heated", "resolved", "spam"];
+
+/**
+ * Normalizes a lock reason so that "too_heated" and "too-heated" match the API value "too heated"
+ * @param {string} reason - Reason provided by the agent
+ * @returns {string}
+ */
+function normalizeLockReason(reason) {
+  const normalized = reason.trim().toLowerCase();
+  return normalized === "too_heated" || normalized === "too-heated" ? "too heated" : normalized.replace("off_topic", "off-topic");
+}
+
+/**
+ * Main handler factory for lock_conversation
+ * Returns a message handler function that processes individual lock_conversation messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  const maxCount = config.max || 1;
+  const requiredLabels = config.required_labels || [];
+  const requiredTitlePrefix = config.required_title_prefix || "";
+  const allowedReasons = config.allowed_reasons && config.allowed_reasons.length > 0 ? config.allowed_reasons : LOCK_REASONS;
+  const { defaultTargetRepo, allowedRepos } = resolveTargetRepoConfig(config);
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Lock conversation configuration: max=${maxCount}, target=${config.target || "triggering"}`);
+  core.info(`Allowed reasons: ${allowedReasons.join(", ")}`);
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single lock_conversation message
+   * @param {Object} message - The lock_conversation message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleLockConversation(message, resolvedTemporaryIds) {
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+
+    const reason = item.reason ? normalizeLockReason(String(item.reason)) : undefined;
+    if (reason && !allowedReasons.includes(reason)) {
+      core.warning(`Lock reason '${reason}' is not allowed`);
+      return { success: false, error: `Lock reason '${reason}' is not allowed (allowed: ${allowedReasons.join(", ")})` };
+    }
+
+    const repoResult = resolveAndValidateRepo(item, defaultTargetRepo, allowedRepos, "conversation");
+    if (!repoResult.success) {
+      core.warning(`Skipping ${HANDLER_TYPE}: ${repoResult.error}`);
+      return { success: false, error: repoResult.error };
+    }
+    const { repo: itemRepo, repoParts } = repoResult;
+
+    const targetResult = resolveModerationTarget(item, config.target, HANDLER_TYPE, true);
+    if (!targetResult.success) {
+      core.warning(targetResult.error);
+      return { success: false, error: targetResult.error };
+    }
+    const number = targetResult.number;
+
+    try {
+      const targetCheck = await fetchModerationTarget(repoParts, number, { requiredLabels, requiredTitlePrefix });
+      if (!targetCheck.success) {
+        core.warning(targetCheck.error);
+        return { success: false, error: targetCheck.error };
+      }
+      if (targetCheck.issue.locked) {
+        core.info(`#${number} in ${itemRepo} is already locked`);
+        return { success: true, number, repo: itemRepo, unchanged: true };
+      }
+
+      if (isStaged) {
+        core.info(`Staged mode: Would lock #${number} in ${itemRepo}${reason ? ` as '${reason}'` : ""}`);
+        return {
+          success: true,
+          staged: true,
+          previewInfo: { number, repo: itemRepo, reason },
+        };
+      }
+
+      await github.rest.issues.lock({
+        ...repoParts,
+        issue_number: number,
+        ...(reason ? { lock_reason: /** @type {any} */ (reason) } : {}),
+      });
+
+      core.info(`✓ Locked #${number} in ${itemRepo}${reason ? ` as '${reason}'` : ""}`);
+      return { success: true, number, repo: itemRepo, reason };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to lock #${number}: ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main, normalizeLockReason };
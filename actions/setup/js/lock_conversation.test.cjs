@@ -0,0 +1,121 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    issues: {
+      get: vi.fn(),
+      lock: vi.fn(),
+    },
+  },
+};
+
+const mockContext = {
+  eventName: "issue_comment",
+  runId: 4242,
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+  payload: {
+    issue: { number: 7 },
+  },
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+const baseIssue = {
+  number: 7,
+  title: "Heated thread",
+  labels: [{ name: "moderation" }],
+  locked: false,
+};
+
+describe("lock_conversation (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    mockGithub.rest.issues.get.mockResolvedValue({ data: baseIssue });
+    mockGithub.rest.issues.lock.mockResolvedValue({});
+  });
+
+  it("should lock the triggering conversation with a normalized reason", async () => {
+    const { main } = require("./lock_conversation.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "lock_conversation", reason: "too_heated" }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.issues.lock).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", issue_number: 7, lock_reason: "too heated" });
+  });
+
+  it("should reject reasons outside allowed_reasons", async () => {
+    const { main } = require("./lock_conversation.cjs");
+    const handler = await main({ allowed_reasons: ["spam"] });
+
+    const result = await handler({ type: "lock_conversation", reason: "resolved" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Lock reason 'resolved' is not allowed");
+    expect(mockGithub.rest.issues.lock).not.toHaveBeenCalled();
+  });
+
+  it("should enforce required labels and title prefix", async () => {
+    const { main } = require("./lock_conversation.cjs");
+    const byLabel = await (await main({ target: "*", required_labels: ["spam-wave"] }))({ type: "lock_conversation", item_number: 7 }, {});
+    const byTitle = await (await main({ target: "*", required_title_prefix: "[RFC]" }))({ type: "lock_conversation", item_number: 7 }, {});
+
+    expect(byLabel.success).toBe(false);
+    expect(byLabel.error).toContain("required labels");
+    expect(byTitle.success).toBe(false);
+    expect(byTitle.error).toContain('title does not start with "[RFC]"');
+    expect(mockGithub.rest.issues.lock).not.toHaveBeenCalled();
+  });
+
+  it("should treat an already locked conversation as unchanged", async () => {
+    mockGithub.rest.issues.get.mockResolvedValue({ data: { ...baseIssue, locked: true } });
+    const { main } = require("./lock_conversation.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "lock_conversation" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.unchanged).toBe(true);
+    expect(mockGithub.rest.issues.lock).not.toHaveBeenCalled();
+  });
+
+  it("should require an item number when target is '*'", async () => {
+    const { main } = require("./lock_conversation.cjs");
+    const handler = await main({ target: "*" });
+
+    const result = await handler({ type: "lock_conversation" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain('Target is "*"');
+  });
+
+  it("should preview without locking in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./lock_conversation.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "lock_conversation", reason: "spam" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.rest.issues.lock).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,199 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { resolveTargetRepoConfig, resolveAndValidateRepo } = require("./repo_helpers.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "manage_labels";
+
+/** Color used for new labels when the agent does not provide one (GitHub's default) */
+const DEFAULT_LABEL_COLOR = "ededed";
+
+/** Supported label operations */
+const OPERATIONS = ["create", "update", "delete"];
+
+/**
+ * Checks whether a label name starts with one of the allowed prefixes (case-insensitive,
+ * matching how GitHub compares label names)
+ * @param {string} name - Label name
+ * @param {string[]} allowedPrefixes - Allowed label name prefixes
+ * @returns {boolean}
+ */
+function isLabelNameAllowed(name, allowedPrefixes) {
+  const lowerName = name.toLowerCase();
+  return allowedPrefixes.some(prefix => lowerName.startsWith(prefix.toLowerCase()) && lowerName.length > prefix.length);
+}
+
+/**
+ * Normalizes a hex color by stripping a leading '#' and lowercasing
+ * @param {string|undefined} color - Color provided by the agent
+ * @returns {string|undefined}
+ */
+function normalizeColor(color) {
+  if (color === undefined || color === null || color === "") {
+    return undefined;
+  }
+  const normalized = String(color).trim().replace(/^#/, "").toLowerCase();
+  if (!/^[0-9a-f]{6}$/.test(normalized)) {
+    throw new Error(`Invalid label color '${color}': must be a 6-digit hex color`);
+  }
+  return normalized;
+}
+
+/**
+ * Returns an existing label, or null if it does not exist
+ * @param {{owner: string, repo: string}} repoParts - Target repository
+ * @param {string} name - Label name
+ * @returns {Promise<{name: string, color: string, description: string|null}|null>}
+ */
+async function getLabel(repoParts, name) {
+  try {
+    const { data } = await github.rest.issues.getLabel({ ...repoParts, name });
+    return data;
+  } catch (error) {
+    if (/** @type {any} */ (error)?.status === 404) {
+      return null;
+    }
+    throw error;
+  }
+}
+
+/**
+ * Main handler factory for manage_labels
+ * Returns a message handler function that processes individual manage_labels messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  const maxCount = config.max || 10;
+  const allowedPrefixes = config.allowed_prefixes || [];
+  const allowDelete = config.allow_delete === true;
+  const { defaultTargetRepo, allowedRepos } = resolveTargetRepoConfig(config);
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Manage labels configuration: max=${maxCount}, allow_delete=${allowDelete}`);
+  core.info(`Allowed label prefixes: ${allowedPrefixes.join(", ")}`);
+  core.info(`Default target repo: ${defaultTargetRepo}`);
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single manage_labels message
+   * @param {Object} message - The manage_labels message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleManageLabels(message, resolvedTemporaryIds) {
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+    const operation = String(item.operation || "");
+    const name = String(item.name || "").trim();
+    const newName = item.new_name ? String(item.new_name).trim() : undefined;
+
+    if (!OPERATIONS.includes(operation)) {
+      return { success: false, error: `Invalid operation '${operation}' (expected one of: ${OPERATIONS.join(", ")})` };
+    }
+    if (!name) {
+      return { success: false, error: "manage_labels requires a label 'name'" };
+    }
+    if (operation === "delete" && !allowDelete) {
+      core.warning(`Refusing to delete label '${name}': allow-delete is not enabled`);
+      return { success: false, error: "Deleting labels is not enabled (set allow-delete: true)" };
+    }
+    for (const labelName of [name, newName]) {
+      if (labelName !== undefined && !isLabelNameAllowed(labelName, allowedPrefixes)) {
+        core.warning(`Label '${labelName}' does not match allowed prefixes`);
+        return { success: false, error: `Label '${labelName}' does not start with an allowed prefix (${allowedPrefixes.join(", ")})` };
+      }
+    }
+
+    const repoResult = resolveAndValidateRepo(item, defaultTargetRepo, allowedRepos, "label");
+    if (!repoResult.success) {
+      core.warning(`Skipping ${HANDLER_TYPE}: ${repoResult.error}`);
+      return { success: false, error: repoResult.error };
+    }
+    const { repo: itemRepo, repoParts } = repoResult;
+
+    try {
+      const color = normalizeColor(item.color);
+      const description = item.description !== undefined ? String(item.description) : undefined;
+
+      if (isStaged) {
+        core.info(`Staged mode: Would ${operation} label '${name}' in ${itemRepo}`);
+        return {
+          success: true,
+          staged: true,
+          previewInfo: { operation, name, newName, color, description, repo: itemRepo },
+        };
+      }
+
+      const existing = await getLabel(repoParts, name);
+
+      if (operation === "delete") {
+        if (!existing) {
+          core.info(`Label '${name}' does not exist in ${itemRepo}, nothing to delete`);
+          return { success: true, operation, name, repo: itemRepo, unchanged: true };
+        }
+        await github.rest.issues.deleteLabel({ ...repoParts, name: existing.name });
+        core.info(`✓ Deleted label '${existing.name}' in ${itemRepo}`);
+        return { success: true, operation, name: existing.name, repo: itemRepo };
+      }
+
+      if (operation === "update" && !existing) {
+        return { success: false, error: `Label '${name}' does not exist in ${itemRepo}` };
+      }
+
+      if (!existing) {
+        const { data: created } = await github.rest.issues.createLabel({
+          ...repoParts,
+          name,
+          color: color || DEFAULT_LABEL_COLOR,
+          ...(description !== undefined ? { description } : {}),
+        });
+        core.info(`✓ Created label '${created.name}' in ${itemRepo}`);
+        return { success: true, operation, name: created.name, repo: itemRepo, created: true };
+      }
+
+      // Creating an existing label syncs its color and description; update may also rename it
+      const targetName = operation === "update" && newName ? newName : existing.name;
+      const changes = {
+        ...(targetName !== existing.name ? { new_name: targetName } : {}),
+        ...(color && color !== existing.color.toLowerCase() ? { color } : {}),
+        ...(description !== undefined && description !== (existing.description || "") ? { description } : {}),
+      };
+      if (Object.keys(changes).length === 0) {
+        core.info(`Label '${existing.name}' in ${itemRepo} is already up to date`);
+        return { success: true, operation, name: existing.name, repo: itemRepo, unchanged: true };
+      }
+
+      await github.rest.issues.updateLabel({ ...repoParts, name: existing.name, ...changes });
+      core.info(`✓ Updated label '${existing.name}'${changes.new_name ? ` → '${changes.new_name}'` : ""} in ${itemRepo}`);
+      return { success: true, operation, name: targetName, previousName: existing.name, repo: itemRepo };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to ${operation} label '${name}': ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main, isLabelNameAllowed };
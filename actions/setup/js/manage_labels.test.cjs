@@ -0,0 +1,173 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    issues: {
+      getLabel: vi.fn(),
+      createLabel: vi.fn(),
+      updateLabel: vi.fn(),
+      deleteLabel: vi.fn(),
+    },
+  },
+};
+
+const mockContext = {
+  eventName: "issues",
+  runId: 4242,
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+  payload: {},
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+/**
+ * Builds a 404 error like the one Octokit throws for missing resources
+ * @returns {Error & {status: number}}
+ */
+function notFound() {
+  return Object.assign(new Error("Not Found"), { status: 404 });
+}
+
+describe("manage_labels (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    mockGithub.rest.issues.getLabel.mockImplementation(async ({ name }) => {
+      if (name === "area/old") {
+        return { data: { name: "area/old", color: "AAAAAA", description: "Old area" } };
+      }
+      throw notFound();
+    });
+    mockGithub.rest.issues.createLabel.mockImplementation(async ({ name }) => ({ data: { name } }));
+    mockGithub.rest.issues.updateLabel.mockResolvedValue({ data: {} });
+    mockGithub.rest.issues.deleteLabel.mockResolvedValue({});
+  });
+
+  it("should create a missing label with a normalized color", async () => {
+    const { main } = require("./manage_labels.cjs");
+    const handler = await main({ allowed_prefixes: ["area/"] });
+
+    const result = await handler({ type: "manage_labels", operation: "create", name: "area/docs", color: "#D73A4A", description: "Documentation" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.created).toBe(true);
+    expect(mockGithub.rest.issues.createLabel).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", name: "area/docs", color: "d73a4a", description: "Documentation" });
+  });
+
+  it("should sync color and description when creating an existing label", async () => {
+    const { main } = require("./manage_labels.cjs");
+    const handler = await main({ allowed_prefixes: ["area/"] });
+
+    const result = await handler({ type: "manage_labels", operation: "create", name: "area/old", color: "00ff00", description: "Old area" }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.issues.createLabel).not.toHaveBeenCalled();
+    expect(mockGithub.rest.issues.updateLabel).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", name: "area/old", color: "00ff00" });
+  });
+
+  it("should skip labels that are already up to date", async () => {
+    const { main } = require("./manage_labels.cjs");
+    const handler = await main({ allowed_prefixes: ["area/"] });
+
+    const result = await handler({ type: "manage_labels", operation: "create", name: "area/old", color: "aaaaaa" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.unchanged).toBe(true);
+    expect(mockGithub.rest.issues.updateLabel).not.toHaveBeenCalled();
+  });
+
+  it("should rename a label when both names match the allowed prefixes", async () => {
+    const { main } = require("./manage_labels.cjs");
+    const handler = await main({ allowed_prefixes: ["area/"] });
+
+    const result = await handler({ type: "manage_labels", operation: "update", name: "area/old", new_name: "area/new" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.previousName).toBe("area/old");
+    expect(mockGithub.rest.issues.updateLabel).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", name: "area/old", new_name: "area/new" });
+  });
+
+  it("should reject names outside the allowed prefixes", async () => {
+    const { main } = require("./manage_labels.cjs");
+    const handler = await main({ allowed_prefixes: ["area/"] });
+
+    const rename = await handler({ type: "manage_labels", operation: "update", name: "area/old", new_name: "bug" }, {});
+    const create = await handler({ type: "manage_labels", operation: "create", name: "priority/high" }, {});
+
+    expect(rename.success).toBe(false);
+    expect(rename.error).toContain("Label 'bug' does not start with an allowed prefix");
+    expect(create.success).toBe(false);
+    expect(mockGithub.rest.issues.updateLabel).not.toHaveBeenCalled();
+    expect(mockGithub.rest.issues.createLabel).not.toHaveBeenCalled();
+  });
+
+  it("should fail to update a label that does not exist", async () => {
+    const { main } = require("./manage_labels.cjs");
+    const handler = await main({ allowed_prefixes: ["area/"] });
+
+    const result = await handler({ type: "manage_labels", operation: "update", name: "area/missing", color: "ffffff" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("does not exist");
+  });
+
+  it("should only delete labels when allow_delete is enabled", async () => {
+    const { main } = require("./manage_labels.cjs");
+    const denied = await (await main({ allowed_prefixes: ["area/"] }))({ type: "manage_labels", operation: "delete", name: "area/old" }, {});
+    const allowed = await (await main({ allowed_prefixes: ["area/"], allow_delete: true }))({ type: "manage_labels", operation: "delete", name: "area/old" }, {});
+
+    expect(denied.success).toBe(false);
+    expect(denied.error).toContain("allow-delete");
+    expect(allowed.success).toBe(true);
+    expect(mockGithub.rest.issues.deleteLabel).toHaveBeenCalledTimes(1);
+  });
+
+  it("should match prefixes case-insensitively and require a name after the prefix", async () => {
+    const { isLabelNameAllowed } = require("./manage_labels.cjs");
+
+    expect(isLabelNameAllowed("Area/Docs", ["area/"])).toBe(true);
+    expect(isLabelNameAllowed("area/", ["area/"])).toBe(false);
+    expect(isLabelNameAllowed("bug", ["area/", "priority/"])).toBe(false);
+  });
+
+  it("should enforce the max count", async () => {
+    const { main } = require("./manage_labels.cjs");
+    const handler = await main({ allowed_prefixes: ["area/"], max: 1 });
+
+    await handler({ type: "manage_labels", operation: "create", name: "area/a" }, {});
+    const result = await handler({ type: "manage_labels", operation: "create", name: "area/b" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Max count of 1 reached");
+  });
+
+  it("should preview without calling the API in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./manage_labels.cjs");
+    const handler = await main({ allowed_prefixes: ["area/"] });
+
+    const result = await handler({ type: "manage_labels", operation: "create", name: "area/docs" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.rest.issues.getLabel).not.toHaveBeenCalled();
+    expect(mockGithub.rest.issues.createLabel).not.toHaveBeenCalled();
+  });
+});
@@ -58,12 +58,14 @@ class MCPServer {
    * @param {string} description - Tool description
    * @param {Object} inputSchema - JSON Schema for tool input
    * @param {Function} handler - Async function that handles tool calls
+   * @param {Object} [outputSchema] - Optional JSON Schema for structured tool output
    */
-  tool(name, description, inputSchema, handler) {
+  tool(name, description, inputSchema, handler, outputSchema) {
     this.tools.set(name, {
       name,
       description,
       inputSchema,
+      outputSchema,
       handler,
     });
     // Also register with the core server
@@ -71,6 +73,7 @@ class MCPServer {
       name,
       description,
       inputSchema,
+      outputSchema,
       handler,
     });
   }
@@ -47,6 +47,7 @@ const encoder = new TextEncoder();
  * @property {string} name - Tool name
  * @property {string} description - Tool description
  * @property {Object} inputSchema - JSON Schema for tool inputs
+ * @property {Object} [outputSchema] - Optional JSON Schema for structured tool output
  * @property {Function} [handler] - Tool handler function
  * @property {string} [handlerPath] - Optional file path to handler module (original path from config)
  * @property {number} [timeout] - Timeout in seconds for tool execution (default: 60)
@@ -579,6 +580,9 @@ async function handleRequest(server, request, defaultHandler) {
           description: tool.description,
           inputSchema: tool.inputSchema,
         };
+        if (tool.outputSchema) {
+          toolDef.outputSchema = tool.outputSchema;
+        }
         list.push(toolDef);
       });
       result = { tools: list };
@@ -632,6 +636,9 @@ async function handleRequest(server, request, defaultHandler) {
       const handlerResult = await Promise.resolve(handler(args));
       const content = handlerResult && handlerResult.content ? handlerResult.content : [];
       result = { content, isError: false };
+      if (handlerResult && handlerResult.structuredContent !== undefined) {
+        result.structuredContent = handlerResult.structuredContent;
+      }
     } else if (/^notifications\//.test(method)) {
       // Notifications don't need a response
       return null;
@@ -709,6 +716,9 @@ async function handleMessage(server, req, defaultHandler) {
           description: tool.description,
           inputSchema: tool.inputSchema,
         };
+        if (tool.outputSchema) {
+          toolDef.outputSchema = tool.outputSchema;
+        }
         list.push(toolDef);
       });
       server.replyResult(id, { tools: list });
@@ -755,7 +765,11 @@ async function handleMessage(server, req, defaultHandler) {
       const result = await Promise.resolve(handler(args));
       server.debug(`Handler returned for tool: ${name}`);
       const content = result && result.content ? result.content : [];
-      server.replyResult(id, { content, isError: false });
+      if (result && result.structuredContent !== undefined) {
+        server.replyResult(id, { content, structuredContent: result.structuredContent, isError: false });
+      } else {
+        server.replyResult(id, { content, isError: false });
+      }
     } else if (/^notifications\//.test(method)) {
       server.debug(`ignore ${method}`);
     } else {
@@ -0,0 +1,307 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { getTrackerID } = require("./get_tracker_id.cjs");
+const { resolveTarget } = require("./safe_output_helpers.cjs");
+const { resolveTargetRepoConfig, resolveAndValidateRepo } = require("./repo_helpers.cjs");
+const { sanitizeContent } = require("./sanitize_content.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "merge_pull_request";
+
+/** Check run conclusions that count as passing */
+const PASSING_CONCLUSIONS = ["success", "neutral", "skipped"];
+
+/** Page size used when listing check runs and reviews */
+const PAGE_SIZE = 100;
+
+/**
+ * Lists all check runs for a commit, excluding the check runs of the current workflow run
+ * (the safe outputs job itself is still in progress when it evaluates the gates)
+ * @param {string} owner - Repository owner
+ * @param {string} repo - Repository name
+ * @param {string} sha - Commit SHA
+ * @returns {Promise<Array<{name: string, status: string, conclusion: string|null}>>}
+ */
+async function listCheckRuns(owner, repo, sha) {
+  const ownRunMarker = `/actions/runs/${context.runId}/`;
+  const checkRuns = [];
+  for (let page = 1; ; page++) {
+    const { data } = await github.rest.checks.listForRef({ owner, repo, ref: sha, per_page: PAGE_SIZE, page });
+    checkRuns.push(...data.check_runs.filter(run => !String(run.details_url || "").includes(ownRunMarker)));
+    if (data.check_runs.length < PAGE_SIZE) {
+      return checkRuns;
+    }
+  }
+}
+
+/**
+ * Lists all reviews of a pull request
+ * @param {string} owner - Repository owner
+ * @param {string} repo - Repository name
+ * @param {number} prNumber - Pull request number
+ * @returns {Promise<Array<{user: {login: string}|null, state: string}>>}
+ */
+async function listReviews(owner, repo, prNumber) {
+  const reviews = [];
+  for (let page = 1; ; page++) {
+    const { data } = await github.rest.pulls.listReviews({ owner, repo, pull_number: prNumber, per_page: PAGE_SIZE, page });
+    reviews.push(...data);
+    if (data.length < PAGE_SIZE) {
+      return reviews;
+    }
+  }
+}
+
+/**
+ * Computes the latest decisive review state per reviewer (comments do not override approvals)
+ * @param {Array<{user: {login: string}|null, state: string}>} reviews - Reviews in chronological order
+ * @returns {{approvals: number, changesRequested: string[]}}
+ */
+function summarizeReviews(reviews) {
+  /** @type {Map<string, string>} */
+  const latestByReviewer = new Map();
+  for (const review of reviews) {
+    const login = review.user?.login;
+    if (!login || !["APPROVED", "CHANGES_REQUESTED", "DISMISSED"].includes(review.state)) {
+      continue;
+    }
+    latestByReviewer.set(login, review.state);
+  }
+  const states = [...latestByReviewer.entries()];
+  return {
+    approvals: states.filter(([, state]) => state === "APPROVED").length,
+    changesRequested: states.filter(([, state]) => state === "CHANGES_REQUESTED").map(([login]) => login),
+  };
+}
+
+/**
+ * Reports whether a pull request body carries the tracker-id marker of this workflow.
+ * The tracker-id is matched literally and must not be followed by another identifier character,
+ * so "deps" does not match a marker for "deps-updater".
+ * @param {string} body - Pull request body
+ * @param {string} trackerID - Tracker-id of the current workflow
+ * @returns {boolean}
+ */
+function hasTrackerIDMarker(body, trackerID) {
+  const marker = `gh-aw-tracker-id: ${trackerID}`;
+  for (let index = body.indexOf(marker); index !== -1; index = body.indexOf(marker, index + 1)) {
+    const next = body.charAt(index + marker.length);
+    if (!/[A-Za-z0-9_-]/.test(next)) {
+      return true;
+    }
+  }
+  return false;
+}
+
+/**
+ * Evaluates the merge policy gates for a pull request
+ * @param {any} pr - Pull request from the REST API
+ * @param {Object} config - Handler configuration
+ * @param {{checkRuns?: Array<any>, combinedStatus?: {state: string, total_count: number}, reviews?: Array<any>}} evidence - Check and review data
+ * @returns {string[]} Reasons the pull request cannot be merged (empty when all gates pass)
+ */
+function evaluateMergeGates(pr, config, evidence) {
+  const failures = [];
+
+  if (pr.state !== "open" || pr.merged) {
+    failures.push(`pull request is ${pr.merged ? "already merged" : pr.state}`);
+  }
+  if (pr.draft) {
+    failures.push("pull request is a draft");
+  }
+  if (pr.mergeable === false) {
+    failures.push("pull request has merge conflicts");
+  }
+
+  const requiredLabels = config.required_labels || [];
+  const labelNames = (pr.labels || []).map(label => label.name);
+  if (requiredLabels.length > 0 && !requiredLabels.some(label => labelNames.includes(label))) {
+    failures.push(`pull request has none of the required labels (${requiredLabels.join(", ")})`);
+  }
+
+  if (config.required_title_prefix && !String(pr.title || "").startsWith(config.required_title_prefix)) {
+    failures.push(`pull request title does not start with '${config.required_title_prefix}'`);
+  }
+
+  const allowedAuthors = (config.allowed_authors || []).map(author => String(author).toLowerCase());
+  const author = String(pr.user?.login || "");
+  if (allowedAuthors.length > 0 && !allowedAuthors.includes(author.toLowerCase())) {
+    failures.push(`author '${author}' is not in allowed-authors`);
+  }
+
+  if (config.require_tracker_id) {
+    const trackerID = getTrackerID();
+    if (!trackerID || !hasTrackerIDMarker(String(pr.body || ""), trackerID)) {
+      failures.push("pull request was not created by this workflow (tracker-id not found)");
+    }
+  }
+
+  if (config.require_checks !== false) {
+    const checkRuns = evidence.checkRuns || [];
+    const combinedStatus = evidence.combinedStatus;
+    // A head commit without any checks has nothing green to show, so it must not pass the gate
+    if (checkRuns.length === 0 && !(combinedStatus && combinedStatus.total_count > 0)) {
+      failures.push("no checks or commit statuses were reported for the head commit (set require-checks: false to merge without checks)");
+    }
+    for (const run of checkRuns) {
+      if (run.status !== "completed") {
+        failures.push(`check '${run.name}' has not completed`);
+      } else if (!PASSING_CONCLUSIONS.includes(run.conclusion)) {
+        failures.push(`check '${run.name}' concluded with '${run.conclusion}'`);
+      }
+    }
+    // Combined status is "pending" when no commit statuses exist, so only judge it when statuses were reported
+    if (combinedStatus && combinedStatus.total_count > 0 && combinedStatus.state !== "success") {
+      failures.push(`commit status is '${combinedStatus.state}'`);
+    }
+  }
+
+  const requiredApprovals = config.required_approvals || 0;
+  if (requiredApprovals > 0) {
+    const { approvals, changesRequested } = summarizeReviews(evidence.reviews || []);
+    if (approvals < requiredApprovals) {
+      failures.push(`pull request has ${approvals} approving review(s), ${requiredApprovals} required`);
+    }
+    if (changesRequested.length > 0) {
+      failures.push(`changes requested by ${changesRequested.join(", ")}`);
+    }
+  }
+
+  return failures;
+}
+
+/**
+ * Main handler factory for merge_pull_request
+ * Returns a message handler function that processes individual merge_pull_request messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  const maxCount = config.max || 1;
+  const mergeMethod = config.merge_method || "squash";
+  const { defaultTargetRepo, allowedRepos } = resolveTargetRepoConfig(config);
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Merge pull request configuration: max=${maxCount}, merge_method=${mergeMethod}, require_checks=${config.require_checks !== false}`);
+  if (config.required_approvals) {
+    core.info(`Required approvals: ${config.required_approvals}`);
+  }
+  if (config.allowed_authors && config.allowed_authors.length > 0) {
+    core.info(`Allowed authors: ${config.allowed_authors.join(", ")}`);
+  }
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single merge_pull_request message
+   * @param {Object} message - The merge_pull_request message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleMergePullRequest(message, resolvedTemporaryIds) {
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+
+    // Resolve and validate target repository
+    const repoResult = resolveAndValidateRepo(item, defaultTargetRepo, allowedRepos, "pull request");
+    if (!repoResult.success) {
+      core.warning(`Skipping ${HANDLER_TYPE}: ${repoResult.error}`);
+      return { success: false, error: repoResult.error };
+    }
+    const { owner, repo } = repoResult.repoParts;
+
+    const targetResult = resolveTarget({
+      targetConfig: config.target,
+      item,
+      context,
+      itemType: "merge pull request",
+      supportsPR: false,
+    });
+    if (!targetResult.success) {
+      core.warning(targetResult.error);
+      return { success: false, error: targetResult.error };
+    }
+    const prNumber = targetResult.number;
+
+    try {
+      core.info(`Evaluating merge gates for PR #${prNumber} in ${owner}/${repo}`);
+      const { data: pr } = await github.rest.pulls.get({ owner, repo, pull_number: prNumber });
+      const headSha = pr.head.sha;
+
+      /** @type {{checkRuns?: Array<any>, combinedStatus?: any, reviews?: Array<any>}} */
+      const evidence = {};
+      if (config.require_checks !== false) {
+        evidence.checkRuns = await listCheckRuns(owner, repo, headSha);
+        evidence.combinedStatus = (await github.rest.repos.getCombinedStatusForRef({ owner, repo, ref: headSha })).data;
+      }
+      if (config.required_approvals) {
+        evidence.reviews = await listReviews(owner, repo, prNumber);
+      }
+
+      const failures = evaluateMergeGates(pr, config, evidence);
+      if (failures.length > 0) {
+        core.warning(`Not merging PR #${prNumber}: ${failures.join("; ")}`);
+        return {
+          success: false,
+          error: `PR #${prNumber} does not satisfy the merge policy: ${failures.join("; ")}`,
+        };
+      }
+      core.info(`PR #${prNumber} satisfies all merge gates`);
+
+      if (isStaged) {
+        core.info(`Staged mode: Would ${mergeMethod}-merge PR #${prNumber} at ${headSha}`);
+        return {
+          success: true,
+          staged: true,
+          previewInfo: { number: prNumber, mergeMethod, sha: headSha },
+        };
+      }
+
+      // Pin the merge to the evaluated head SHA so commits pushed after the checks cannot be merged
+      const { data: result } = await github.rest.pulls.merge({
+        owner,
+        repo,
+        pull_number: prNumber,
+        merge_method: mergeMethod,
+        sha: headSha,
+        ...(item.commit_title ? { commit_title: sanitizeContent(item.commit_title, { maxLength: 256 }) } : {}),
+        ...(item.commit_message ? { commit_message: sanitizeContent(item.commit_message) } : {}),
+      });
+
+      core.info(`✓ Merged PR #${prNumber}: ${result.sha}`);
+      return {
+        success: true,
+        pull_request_number: prNumber,
+        pull_request_url: pr.html_url,
+        sha: result.sha,
+        mergeMethod,
+      };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to merge PR #${prNumber}: ${errorMessage}`);
+      return {
+        success: false,
+        error: `Failed to merge PR #${prNumber}: ${errorMessage}`,
+      };
+    }
+  };
+}
+
+module.exports = { main, evaluateMergeGates, hasTrackerIDMarker, summarizeReviews };
@@ -0,0 +1,211 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    pulls: {
+      get: vi.fn(),
+      listReviews: vi.fn(),
+      merge: vi.fn(),
+    },
+    checks: {
+      listForRef: vi.fn(),
+    },
+    repos: {
+      getCombinedStatusForRef: vi.fn(),
+    },
+  },
+};
+
+const mockContext = {
+  eventName: "schedule",
+  runId: 4242,
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+  payload: {},
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+const basePR = {
+  number: 12,
+  state: "open",
+  draft: false,
+  merged: false,
+  mergeable: true,
+  title: "chore(deps): bump lodash",
+  body: "Bump lodash\n\n<!-- gh-aw-tracker-id: deps-updater -->",
+  labels: [{ name: "dependencies" }],
+  user: { login: "dependabot[bot]" },
+  head: { sha: "headsha123" },
+  html_url: "https://github.com/testowner/testrepo/pull/12",
+};
+
+describe("merge_pull_request (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    process.env.GH_AW_TRACKER_ID = "deps-updater";
+    mockGithub.rest.pulls.get.mockResolvedValue({ data: basePR });
+    mockGithub.rest.pulls.listReviews.mockResolvedValue({ data: [{ user: { login: "alice" }, state: "APPROVED" }] });
+    mockGithub.rest.pulls.merge.mockResolvedValue({ data: { sha: "mergesha", merged: true } });
+    mockGithub.rest.checks.listForRef.mockResolvedValue({
+      data: {
+        check_runs: [
+          { name: "build", status: "completed", conclusion: "success" },
+          // The safe outputs job of the current run is still in progress and must be ignored
+          { name: "safe_outputs", status: "in_progress", conclusion: null, details_url: "https://github.com/testowner/testrepo/actions/runs/4242/job/1" },
+        ],
+      },
+    });
+    mockGithub.rest.repos.getCombinedStatusForRef.mockResolvedValue({ data: { state: "pending", total_count: 0 } });
+  });
+
+  it("should merge a pull request that passes all gates at the evaluated head SHA", async () => {
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({ target: "*", merge_method: "rebase", required_approvals: 1, allowed_authors: ["dependabot[bot]"], required_labels: ["dependencies"], require_tracker_id: true });
+
+    const result = await handler({ type: "merge_pull_request", pull_request_number: 12 }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.sha).toBe("mergesha");
+    expect(mockGithub.rest.pulls.merge).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", pull_number: 12, merge_method: "rebase", sha: "headsha123" });
+  });
+
+  it("should refuse to merge when a check failed", async () => {
+    mockGithub.rest.checks.listForRef.mockResolvedValue({ data: { check_runs: [{ name: "test", status: "completed", conclusion: "failure" }] } });
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({ target: "*" });
+
+    const result = await handler({ type: "merge_pull_request", pull_request_number: 12 }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("check 'test' concluded with 'failure'");
+    expect(mockGithub.rest.pulls.merge).not.toHaveBeenCalled();
+  });
+
+  it("should skip check evaluation when require_checks is false", async () => {
+    mockGithub.rest.checks.listForRef.mockResolvedValue({ data: { check_runs: [{ name: "test", status: "completed", conclusion: "failure" }] } });
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({ target: "*", require_checks: false });
+
+    const result = await handler({ type: "merge_pull_request", pull_request_number: 12 }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.checks.listForRef).not.toHaveBeenCalled();
+  });
+
+  it("should refuse to merge when no checks or commit statuses were reported", async () => {
+    mockGithub.rest.checks.listForRef.mockResolvedValue({ data: { check_runs: [] } });
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({ target: "*" });
+
+    const result = await handler({ type: "merge_pull_request", pull_request_number: 12 }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("no checks or commit statuses were reported");
+    expect(mockGithub.rest.pulls.merge).not.toHaveBeenCalled();
+  });
+
+  it("should accept commit statuses when no check runs were reported", async () => {
+    mockGithub.rest.checks.listForRef.mockResolvedValue({ data: { check_runs: [] } });
+    mockGithub.rest.repos.getCombinedStatusForRef.mockResolvedValue({ data: { state: "success", total_count: 1 } });
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({ target: "*" });
+
+    const result = await handler({ type: "merge_pull_request", pull_request_number: 12 }, {});
+
+    expect(result.success).toBe(true);
+  });
+
+  it("should match the tracker-id marker literally", async () => {
+    const { hasTrackerIDMarker } = require("./merge_pull_request.cjs");
+
+    expect(hasTrackerIDMarker("<!-- gh-aw-tracker-id: deps-updater -->", "deps-updater")).toBe(true);
+    expect(hasTrackerIDMarker("<!-- gh-aw-tracker-id: deps-updater -->", "deps")).toBe(false);
+    expect(hasTrackerIDMarker("gh-aw-tracker-id: deps-updater2\ngh-aw-tracker-id: deps-updater", "deps-updater")).toBe(true);
+    // Regular expression metacharacters in the tracker-id must not act as wildcards
+    expect(hasTrackerIDMarker("<!-- gh-aw-tracker-id: deps-updater -->", "deps.updater")).toBe(false);
+    expect(hasTrackerIDMarker("<!-- gh-aw-tracker-id: deps-updater -->", ".*")).toBe(false);
+  });
+
+  it("should report every failing gate", async () => {
+    mockGithub.rest.pulls.get.mockResolvedValue({ data: { ...basePR, draft: true, labels: [], user: { login: "mallory" }, body: "no marker" } });
+    mockGithub.rest.pulls.listReviews.mockResolvedValue({ data: [{ user: { login: "bob" }, state: "CHANGES_REQUESTED" }] });
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({ target: "*", required_approvals: 1, allowed_authors: ["dependabot[bot]"], required_labels: ["dependencies"], require_tracker_id: true });
+
+    const result = await handler({ type: "merge_pull_request", pull_request_number: 12 }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("draft");
+    expect(result.error).toContain("required labels");
+    expect(result.error).toContain("author 'mallory' is not in allowed-authors");
+    expect(result.error).toContain("tracker-id not found");
+    expect(result.error).toContain("0 approving review(s), 1 required");
+    expect(result.error).toContain("changes requested by bob");
+  });
+
+  it("should use the latest decisive review per reviewer", async () => {
+    const { summarizeReviews } = require("./merge_pull_request.cjs");
+
+    const summary = summarizeReviews([
+      { user: { login: "alice" }, state: "CHANGES_REQUESTED" },
+      { user: { login: "alice" }, state: "APPROVED" },
+      { user: { login: "alice" }, state: "COMMENTED" },
+      { user: { login: "bob" }, state: "APPROVED" },
+      { user: { login: "bob" }, state: "DISMISSED" },
+    ]);
+
+    expect(summary).toEqual({ approvals: 1, changesRequested: [] });
+  });
+
+  it("should fail outside of a pull request context with the default target", async () => {
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "merge_pull_request" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("not running in pull request context");
+  });
+
+  it("should enforce the max count", async () => {
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({ target: "*", max: 1 });
+
+    await handler({ type: "merge_pull_request", pull_request_number: 12 }, {});
+    const result = await handler({ type: "merge_pull_request", pull_request_number: 13 }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Max count of 1 reached");
+    expect(mockGithub.rest.pulls.merge).toHaveBeenCalledTimes(1);
+  });
+
+  it("should evaluate gates but not merge in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./merge_pull_request.cjs");
+    const handler = await main({ target: "*" });
+
+    const result = await handler({ type: "merge_pull_request", pull_request_number: 12 }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.rest.pulls.merge).not.toHaveBeenCalled();
+  });
+});
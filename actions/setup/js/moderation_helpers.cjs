@@ -0,0 +1,53 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Shared helpers for the moderation safe outputs (lock_conversation, pin_issue, transfer_issue)
+ */
+
+const { checkLabelFilter, checkTitlePrefixFilter } = require("./close_entity_helpers.cjs");
+const { resolveTarget } = require("./safe_output_helpers.cjs");
+
+/**
+ * Resolves the issue or pull request number a moderation message targets
+ * @param {any} item - Safe output item
+ * @param {string|undefined} targetConfig - Target configuration ("triggering", "*", or explicit number)
+ * @param {string} itemType - Safe output type (for error messages)
+ * @param {boolean} supportsPR - Whether pull requests can be targeted in addition to issues
+ * @returns {{success: true, number: number} | {success: false, error: string}}
+ */
+function resolveModerationTarget(item, targetConfig, itemType, supportsPR) {
+  const result = resolveTarget({
+    targetConfig: targetConfig || "triggering",
+    item,
+    context,
+    itemType,
+    supportsPR,
+    supportsIssue: !supportsPR,
+  });
+  if (!result.success) {
+    return { success: false, error: result.error };
+  }
+  return { success: true, number: result.number };
+}
+
+/**
+ * Fetches an issue (or pull request through the issues API) and checks the configured filters
+ * @param {{owner: string, repo: string}} repoParts - Repository containing the item
+ * @param {number} number - Issue or pull request number
+ * @param {{requiredLabels: string[], requiredTitlePrefix: string}} filters - Configured filters
+ * @returns {Promise<{success: true, issue: any} | {success: false, error: string}>}
+ */
+async function fetchModerationTarget(repoParts, number, filters) {
+  const { data: issue } = await github.rest.issues.get({ ...repoParts, issue_number: number });
+
+  if (!checkLabelFilter(issue.labels.map(label => (typeof label === "string" ? { name: label } : label)), filters.requiredLabels)) {
+    return { success: false, error: `#${number} does not have any of the required labels: ${filters.requiredLabels.join(", ")}` };
+  }
+  if (!checkTitlePrefixFilter(issue.title, filters.requiredTitlePrefix)) {
+    return { success: false, error: `#${number} title does not start with "${filters.requiredTitlePrefix}"` };
+  }
+  return { success: true, issue };
+}
+
+module.exports = { resolveModerationTarget, fetchModerationTarget };
@@ -0,0 +1,110 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { resolveTargetRepoConfig, resolveAndValidateRepo } = require("./repo_helpers.cjs");
+const { resolveModerationTarget, fetchModerationTarget } = require("./moderation_helpers.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "pin_issue";
+
+/**
+ * Main handler factory for pin_issue
+ * Returns a message handler function that processes individual pin_issue messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  const maxCount = config.max || 1;
+  const requiredLabels = config.required_labels || [];
+  const requiredTitlePrefix = config.required_title_prefix || "";
+  const { defaultTargetRepo, allowedRepos } = resolveTargetRepoConfig(config);
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Pin issue configuration: max=${maxCount}, target=${config.target || "triggering"}`);
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single pin_issue message
+   * @param {Object} message - The pin_issue message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handlePinIssue(message, resolvedTemporaryIds) {
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+
+    const repoResult = resolveAndValidateRepo(item, defaultTargetRepo, allowedRepos, "issue");
+    if (!repoResult.success) {
+      core.warning(`Skipping ${HANDLER_TYPE}: ${repoResult.error}`);
+      return { success: false, error: repoResult.error };
+    }
+    const { repo: itemRepo, repoParts } = repoResult;
+
+    const targetResult = resolveModerationTarget(item, config.target, HANDLER_TYPE, false);
+    if (!targetResult.success) {
+      core.warning(targetResult.error);
+      return { success: false, error: targetResult.error };
+    }
+    const issueNumber = targetResult.number;
+
+    try {
+      const targetCheck = await fetchModerationTarget(repoParts, issueNumber, { requiredLabels, requiredTitlePrefix });
+      if (!targetCheck.success) {
+        core.warning(targetCheck.error);
+        return { success: false, error: targetCheck.error };
+      }
+      const issue = targetCheck.issue;
+      if (issue.pull_request) {
+        return { success: false, error: `#${issueNumber} is a pull request; only issues can be pinned` };
+      }
+
+      if (isStaged) {
+        core.info(`Staged mode: Would pin issue #${issueNumber} in ${itemRepo}`);
+        return {
+          success: true,
+          staged: true,
+          previewInfo: { number: issueNumber, repo: itemRepo, title: issue.title },
+        };
+      }
+
+      // Pinning is only available through GraphQL
+      await github.graphql(
+        `mutation($issueId: ID!) {
+          pinIssue(input: { issueId: $issueId }) {
+            issue { number }
+          }
+        }`,
+        { issueId: issue.node_id }
+      );
+
+      core.info(`✓ Pinned issue #${issueNumber} in ${itemRepo}`);
+      return { success: true, number: issueNumber, repo: itemRepo, url: issue.html_url };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to pin issue #${issueNumber}: ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main };
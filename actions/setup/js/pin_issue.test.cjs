@@ -0,0 +1,120 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    issues: {
+      get: vi.fn(),
+    },
+  },
+  graphql: vi.fn(),
+};
+
+const mockContext = {
+  eventName: "issues",
+  runId: 4242,
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+  payload: {
+    issue: { number: 3 },
+  },
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+const baseIssue = {
+  number: 3,
+  node_id: "I_kwDOissue3",
+  title: "Release 2.0 announcement",
+  labels: [{ name: "announcement" }],
+  html_url: "https://github.com/testowner/testrepo/issues/3",
+};
+
+describe("pin_issue (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    mockGithub.rest.issues.get.mockResolvedValue({ data: baseIssue });
+    mockGithub.graphql.mockResolvedValue({ pinIssue: { issue: { number: 3 } } });
+  });
+
+  it("should pin the triggering issue through GraphQL", async () => {
+    const { main } = require("./pin_issue.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "pin_issue" }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.graphql).toHaveBeenCalledWith(expect.stringContaining("pinIssue"), { issueId: "I_kwDOissue3" });
+  });
+
+  it("should pin an explicit issue when target is '*' and labels match", async () => {
+    const { main } = require("./pin_issue.cjs");
+    const handler = await main({ target: "*", required_labels: ["announcement"] });
+
+    const result = await handler({ type: "pin_issue", issue_number: 3 }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.issues.get).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", issue_number: 3 });
+  });
+
+  it("should refuse issues without the required labels", async () => {
+    mockGithub.rest.issues.get.mockResolvedValue({ data: { ...baseIssue, labels: [] } });
+    const { main } = require("./pin_issue.cjs");
+    const handler = await main({ required_labels: ["announcement"] });
+
+    const result = await handler({ type: "pin_issue" }, {});
+
+    expect(result.success).toBe(false);
+    expect(mockGithub.graphql).not.toHaveBeenCalled();
+  });
+
+  it("should refuse to pin pull requests", async () => {
+    mockGithub.rest.issues.get.mockResolvedValue({ data: { ...baseIssue, pull_request: { url: "..." } } });
+    const { main } = require("./pin_issue.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "pin_issue" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("only issues can be pinned");
+  });
+
+  it("should enforce the max count", async () => {
+    const { main } = require("./pin_issue.cjs");
+    const handler = await main({ target: "*", max: 1 });
+
+    await handler({ type: "pin_issue", issue_number: 3 }, {});
+    const result = await handler({ type: "pin_issue", issue_number: 4 }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Max count of 1 reached");
+  });
+
+  it("should preview without pinning in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./pin_issue.cjs");
+    const handler = await main({});
+
+    const result = await handler({ type: "pin_issue" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.graphql).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,54 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { PENDING_DESCRIPTION, getStatusContext, resolveStatusSha, renderTargetUrl, truncateDescription } = require("./commit_status_helpers.cjs");
+
+/**
+ * Replaces the pending commit status set in pre-activation when the agent run ended without
+ * reporting a final state. The status is resolved to "error" so a failed, cancelled, or silent
+ * run never leaves a required check pending. Statuses already set by the agent are left alone.
+ */
+async function main() {
+  const statusContext = getStatusContext(undefined);
+  const agentConclusion = process.env.GH_AW_AGENT_CONCLUSION || "unknown";
+
+  try {
+    const sha = await resolveStatusSha();
+    if (!sha) {
+      core.info("No commit to resolve a pending status on, skipping");
+      return;
+    }
+
+    // Statuses are returned newest first, so the first match is the current state of the context
+    const { data: statuses } = await github.rest.repos.listCommitStatusesForRef({
+      owner: context.repo.owner,
+      repo: context.repo.repo,
+      ref: sha,
+      per_page: 100,
+    });
+    const current = statuses.find(status => status.context === statusContext);
+    if (!current || current.state !== "pending" || current.description !== PENDING_DESCRIPTION) {
+      core.info(`Commit status '${statusContext}' was already resolved (${current ? current.state : "none"}), nothing to do`);
+      return;
+    }
+
+    const description =
+      agentConclusion === "success" ? "Agentic workflow finished without reporting a status" : `Agentic workflow did not complete (agent job: ${agentConclusion})`;
+
+    await github.rest.repos.createCommitStatus({
+      owner: context.repo.owner,
+      repo: context.repo.repo,
+      sha,
+      state: "error",
+      context: statusContext,
+      description: truncateDescription(description),
+      target_url: renderTargetUrl(process.env.GH_AW_COMMIT_STATUS_TARGET_URL, sha),
+    });
+    core.info(`Resolved pending commit status '${statusContext}' on ${sha} to error`);
+  } catch (error) {
+    core.warning(`Failed to resolve pending commit status: ${getErrorMessage(error)}`);
+  }
+}
+
+module.exports = { main };
@@ -19,6 +19,7 @@
  */
 
 const { createServer, registerTool, start } = require("./mcp_server_core.cjs");
+const { createSchemaValidatingHandler } = require("./safe_inputs_validation.cjs");
 const { loadConfig } = require("./safe_inputs_config_loader.cjs");
 const { createToolConfig } = require("./safe_inputs_tool_factory.cjs");
 const { bootstrapSafeInputsServer, cleanupConfigFile } = require("./safe_inputs_bootstrap.cjs");
@@ -64,8 +65,11 @@ function startSafeInputsServer(configPath, options = {}) {
     server.logDir = config.logDir;
   }
 
-  // Register all tools with the server
+  // Register all tools with the server, validating arguments and outputs against their schemas
   for (const tool of tools) {
+    if (tool.handler) {
+      tool.handler = createSchemaValidatingHandler(tool);
+    }
     registerTool(server, tool);
   }
 
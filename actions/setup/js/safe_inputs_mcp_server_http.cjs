@@ -21,7 +21,7 @@
 const http = require("http");
 const { randomUUID } = require("crypto");
 const { MCPServer, MCPHTTPTransport } = require("./mcp_http_transport.cjs");
-const { validateRequiredFields } = require("./safe_inputs_validation.cjs");
+const { validateRequiredFields, createSchemaValidatingHandler } = require("./safe_inputs_validation.cjs");
 const { generateEnhancedErrorMessage } = require("./mcp_enhanced_errors.cjs");
 const { createLogger } = require("./mcp_logger.cjs");
 const { bootstrapSafeInputsServer, cleanupConfigFile } = require("./safe_inputs_bootstrap.cjs");
@@ -80,7 +80,8 @@ function createMCPServer(configPath, options = {}) {
 
     // Register the tool with the MCP SDK using the high-level API
     // The callback receives the arguments directly as the first parameter
-    server.tool(tool.name, tool.description || "", tool.inputSchema || { type: "object", properties: {} }, async args => {
+    const validatingHandler = createSchemaValidatingHandler(tool);
+    const handler = async args => {
       logger.debug(`Calling handler for tool: ${tool.name}`);
 
       // Validate required fields using helper
@@ -89,14 +90,18 @@ function createMCPServer(configPath, options = {}) {
         throw new Error(generateEnhancedErrorMessage(missing, tool.name, tool.inputSchema));
       }
 
-      // Call the handler
-      const result = await Promise.resolve(tool.handler(args));
+      // Call the handler (arguments and output are validated against the tool schemas)
+      const result = await validatingHandler(args);
       logger.debug(`Handler returned for tool: ${tool.name}`);
 
       // Normalize result to MCP format
       const content = result && result.content ? result.content : [];
+      if (result && result.structuredContent !== undefined) {
+        return { content, structuredContent: result.structuredContent, isError: false };
+      }
       return { content, isError: false };
-    });
+    };
+    server.tool(tool.name, tool.description || "", tool.inputSchema || { type: "object", properties: {} }, handler, tool.outputSchema);
 
     registeredCount++;
   }
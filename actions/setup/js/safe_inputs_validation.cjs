@@ -27,6 +27,211 @@ function validateRequiredFields(args, inputSchema) {
   return missing;
 }
 
+/**
+ * Get the JSON Schema type name of a value
+ * @param {any} value - The value to inspect
+ * @returns {string} JSON Schema type name
+ */
+function getJsonType(value) {
+  if (value === null) {
+    return "null";
+  }
+  if (Array.isArray(value)) {
+    return "array";
+  }
+  if (typeof value === "number") {
+    return Number.isInteger(value) ? "integer" : "number";
+  }
+  return typeof value;
+}
+
+/**
+ * Check whether a value matches a JSON Schema type
+ * @param {any} value - The value to check
+ * @param {string} type - JSON Schema type name
+ * @returns {boolean} True if the value matches the type
+ */
+function matchesJsonType(value, type) {
+  const actual = getJsonType(value);
+  if (type === "number") {
+    return actual === "number" || actual === "integer";
+  }
+  return actual === type;
+}
+
+/**
+ * Validate a value against the subset of JSON Schema used by safe-inputs tools.
+ * Supports type, enum, const, string/number/array constraints, nested objects
+ * and arrays of objects. The compiler rejects schemas with other keywords
+ * (validateSafeInputSchemaKeywords in safe_inputs_validation.go), so none are ignored.
+ *
+ * @param {any} value - The value to validate
+ * @param {Object} schema - JSON Schema to validate against
+ * @param {string} [path="$"] - JSON path of the value, used in error messages
+ * @returns {string[]} Array of validation errors (empty if the value is valid)
+ */
+function validateValueAgainstSchema(value, schema, path = "$") {
+  if (!schema || typeof schema !== "object") {
+    return [];
+  }
+
+  /** @type {string[]} */
+  const errors = [];
+
+  if (schema.type !== undefined) {
+    const types = Array.isArray(schema.type) ? schema.type : [schema.type];
+    if (!types.some(t => matchesJsonType(value, t))) {
+      errors.push(`${path}: expected ${types.join(" or ")}, got ${getJsonType(value)}`);
+      return errors;
+    }
+  }
+
+  if (Array.isArray(schema.enum) && !schema.enum.some(e => JSON.stringify(e) === JSON.stringify(value))) {
+    errors.push(`${path}: must be one of ${schema.enum.map(e => JSON.stringify(e)).join(", ")}`);
+  }
+
+  if (schema.const !== undefined && JSON.stringify(schema.const) !== JSON.stringify(value)) {
+    errors.push(`${path}: must be ${JSON.stringify(schema.const)}`);
+  }
+
+  if (typeof value === "string") {
+    if (typeof schema.minLength === "number" && value.length < schema.minLength) {
+      errors.push(`${path}: must be at least ${schema.minLength} characters`);
+    }
+    if (typeof schema.maxLength === "number" && value.length > schema.maxLength) {
+      errors.push(`${path}: must be at most ${schema.maxLength} characters`);
+    }
+    if (typeof schema.pattern === "string" && !new RegExp(schema.pattern, "u").test(value)) {
+      errors.push(`${path}: must match pattern ${schema.pattern}`);
+    }
+  }
+
+  if (typeof value === "number") {
+    if (typeof schema.minimum === "number" && value < schema.minimum) {
+      errors.push(`${path}: must be >= ${schema.minimum}`);
+    }
+    if (typeof schema.maximum === "number" && value > schema.maximum) {
+      errors.push(`${path}: must be <= ${schema.maximum}`);
+    }
+    if (typeof schema.exclusiveMinimum === "number" && value <= schema.exclusiveMinimum) {
+      errors.push(`${path}: must be > ${schema.exclusiveMinimum}`);
+    }
+    if (typeof schema.exclusiveMaximum === "number" && value >= schema.exclusiveMaximum) {
+      errors.push(`${path}: must be < ${schema.exclusiveMaximum}`);
+    }
+  }
+
+  if (Array.isArray(value)) {
+    if (typeof schema.minItems === "number" && value.length < schema.minItems) {
+      errors.push(`${path}: must have at least ${schema.minItems} items`);
+    }
+    if (typeof schema.maxItems === "number" && value.length > schema.maxItems) {
+      errors.push(`${path}: must have at most ${schema.maxItems} items`);
+    }
+    if (schema.uniqueItems === true && new Set(value.map(v => JSON.stringify(v))).size !== value.length) {
+      errors.push(`${path}: items must be unique`);
+    }
+    if (schema.items && typeof schema.items === "object") {
+      value.forEach((item, index) => {
+        errors.push(...validateValueAgainstSchema(item, schema.items, `${path}[${index}]`));
+      });
+    }
+  }
+
+  if (getJsonType(value) === "object") {
+    const properties = schema.properties && typeof schema.properties === "object" ? schema.properties : {};
+    if (Array.isArray(schema.required)) {
+      for (const field of schema.required) {
+        if (value[field] === undefined) {
+          errors.push(`${path}.${field}: is required`);
+        }
+      }
+    }
+    for (const [key, propValue] of Object.entries(value)) {
+      if (Object.prototype.hasOwnProperty.call(properties, key)) {
+        errors.push(...validateValueAgainstSchema(propValue, properties[key], `${path}.${key}`));
+      } else if (schema.additionalProperties === false) {
+        errors.push(`${path}.${key}: is not allowed`);
+      } else if (schema.additionalProperties && typeof schema.additionalProperties === "object") {
+        errors.push(...validateValueAgainstSchema(propValue, schema.additionalProperties, `${path}.${key}`));
+      }
+    }
+  }
+
+  return errors;
+}
+
+/**
+ * Extract the structured output of a safe-input tool from its MCP result.
+ * Handlers return JSON text content; shell handlers wrap stdout in
+ * { stdout, stderr, outputs }, in which case stdout must contain the JSON output.
+ *
+ * @param {Array<{type: string, text?: string}>} content - MCP content items returned by the handler
+ * @param {string} [handlerPath] - Path of the tool handler (used to detect shell handlers)
+ * @returns {{value?: any, error?: string}} The parsed output value or an error message
+ */
+function extractStructuredOutput(content, handlerPath) {
+  const textItem = Array.isArray(content) ? content.find(item => item && item.type === "text") : undefined;
+  if (!textItem || typeof textItem.text !== "string") {
+    return { error: "tool returned no text content" };
+  }
+
+  let value;
+  try {
+    value = JSON.parse(textItem.text);
+  } catch {
+    return { error: "tool output is not valid JSON" };
+  }
+
+  if (handlerPath && handlerPath.endsWith(".sh") && value && typeof value.stdout === "string") {
+    try {
+      value = JSON.parse(value.stdout.trim());
+    } catch {
+      return { error: "tool stdout is not valid JSON" };
+    }
+  }
+
+  return { value };
+}
+
+/**
+ * Wrap a safe-input tool handler with JSON Schema validation of its arguments
+ * and, when the tool declares an outputSchema, of its structured output.
+ * Valid structured output is returned as structuredContent alongside the text content.
+ *
+ * @param {{name: string, inputSchema?: Object, outputSchema?: Object, handlerPath?: string, handler: Function}} tool - Tool with a loaded handler
+ * @returns {Function} Async handler function
+ */
+function createSchemaValidatingHandler(tool) {
+  const handler = tool.handler;
+  return async args => {
+    const inputErrors = validateValueAgainstSchema(args || {}, tool.inputSchema);
+    if (inputErrors.length) {
+      throw new Error(`Invalid arguments for tool '${tool.name}':\n${inputErrors.map(e => `  - ${e}`).join("\n")}`);
+    }
+
+    const result = await Promise.resolve(handler(args));
+    if (!tool.outputSchema) {
+      return result;
+    }
+
+    const content = result && result.content ? result.content : [];
+    const { value, error } = extractStructuredOutput(content, tool.handlerPath);
+    if (error) {
+      throw new Error(`Output of tool '${tool.name}' does not match its output-schema: ${error}`);
+    }
+    const outputErrors = validateValueAgainstSchema(value, tool.outputSchema);
+    if (outputErrors.length) {
+      throw new Error(`Output of tool '${tool.name}' does not match its output-schema:\n${outputErrors.map(e => `  - ${e}`).join("\n")}`);
+    }
+
+    return { content, structuredContent: value };
+  };
+}
+
 module.exports = {
   validateRequiredFields,
+  validateValueAgainstSchema,
+  extractStructuredOutput,
+  createSchemaValidatingHandler,
 };
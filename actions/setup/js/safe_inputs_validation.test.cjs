@@ -135,4 +135,131 @@ describe("safe_inputs_validation.cjs", () => {
       expect(missing).toEqual([]);
     });
   });
+
+  describe("validateValueAgainstSchema", () => {
+    it("should accept values matching the schema", async () => {
+      const { validateValueAgainstSchema } = await import("./safe_inputs_validation.cjs");
+
+      const schema = {
+        type: "object",
+        properties: {
+          level: { type: "string", enum: ["low", "high"] },
+          count: { type: "integer", minimum: 1, maximum: 10 },
+          tags: { type: "array", items: { type: "string", pattern: "^[a-z]+$" } },
+        },
+        required: ["level"],
+      };
+
+      expect(validateValueAgainstSchema({ level: "low", count: 3, tags: ["a", "b"] }, schema)).toEqual([]);
+    });
+
+    it("should report enum, range and pattern violations with paths", async () => {
+      const { validateValueAgainstSchema } = await import("./safe_inputs_validation.cjs");
+
+      const schema = {
+        type: "object",
+        properties: {
+          level: { type: "string", enum: ["low", "high"] },
+          count: { type: "integer", maximum: 10 },
+          tags: { type: "array", items: { type: "string", pattern: "^[a-z]+$" } },
+        },
+      };
+
+      const errors = validateValueAgainstSchema({ level: "medium", count: 11, tags: ["ok", "NOT"] }, schema);
+
+      expect(errors).toEqual(['$.level: must be one of "low", "high"', "$.count: must be <= 10", "$.tags[1]: must match pattern ^[a-z]+$"]);
+    });
+
+    it("should validate arrays of nested objects", async () => {
+      const { validateValueAgainstSchema } = await import("./safe_inputs_validation.cjs");
+
+      const schema = {
+        type: "array",
+        items: {
+          type: "object",
+          properties: { id: { type: "integer" } },
+          required: ["id"],
+          additionalProperties: false,
+        },
+      };
+
+      const errors = validateValueAgainstSchema([{ id: 1 }, { name: "x" }], schema);
+
+      expect(errors).toEqual(["$[1].id: is required", "$[1].name: is not allowed"]);
+    });
+
+    it("should report type mismatches", async () => {
+      const { validateValueAgainstSchema } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateValueAgainstSchema("5", { type: "number" })).toEqual(["$: expected number, got string"]);
+      expect(validateValueAgainstSchema(1.5, { type: "integer" })).toEqual(["$: expected integer, got number"]);
+      expect(validateValueAgainstSchema(2, { type: "number" })).toEqual([]);
+    });
+  });
+
+  describe("extractStructuredOutput", () => {
+    it("should parse JSON text content", async () => {
+      const { extractStructuredOutput } = await import("./safe_inputs_validation.cjs");
+
+      expect(extractStructuredOutput([{ type: "text", text: '{"url":"https://example.com"}' }], "tool.cjs")).toEqual({ value: { url: "https://example.com" } });
+    });
+
+    it("should parse stdout of shell handlers", async () => {
+      const { extractStructuredOutput } = await import("./safe_inputs_validation.cjs");
+
+      const text = JSON.stringify({ stdout: '{"count":2}\n', stderr: "", outputs: {} });
+
+      expect(extractStructuredOutput([{ type: "text", text }], "tool.sh")).toEqual({ value: { count: 2 } });
+    });
+
+    it("should return an error for non-JSON output", async () => {
+      const { extractStructuredOutput } = await import("./safe_inputs_validation.cjs");
+
+      expect(extractStructuredOutput([{ type: "text", text: "not json" }], "tool.cjs").error).toBe("tool output is not valid JSON");
+    });
+  });
+
+  describe("createSchemaValidatingHandler", () => {
+    it("should reject arguments that violate the input schema", async () => {
+      const { createSchemaValidatingHandler } = await import("./safe_inputs_validation.cjs");
+
+      const handler = createSchemaValidatingHandler({
+        name: "deploy",
+        inputSchema: { type: "object", properties: { env: { type: "string", enum: ["staging", "prod"] } } },
+        handler: async () => ({ content: [{ type: "text", text: "{}" }] }),
+      });
+
+      await expect(handler({ env: "dev" })).rejects.toThrow("Invalid arguments for tool 'deploy'");
+    });
+
+    it("should return structured content when output matches the output schema", async () => {
+      const { createSchemaValidatingHandler } = await import("./safe_inputs_validation.cjs");
+
+      const handler = createSchemaValidatingHandler({
+        name: "deploy",
+        inputSchema: { type: "object", properties: {} },
+        outputSchema: { type: "object", properties: { url: { type: "string" } }, required: ["url"] },
+        handlerPath: "deploy.cjs",
+        handler: async () => ({ content: [{ type: "text", text: '{"url":"https://example.com"}' }] }),
+      });
+
+      const result = await handler({});
+
+      expect(result.structuredContent).toEqual({ url: "https://example.com" });
+    });
+
+    it("should reject output that violates the output schema", async () => {
+      const { createSchemaValidatingHandler } = await import("./safe_inputs_validation.cjs");
+
+      const handler = createSchemaValidatingHandler({
+        name: "deploy",
+        inputSchema: { type: "object", properties: {} },
+        outputSchema: { type: "object", properties: { url: { type: "string" } }, required: ["url"] },
+        handlerPath: "deploy.cjs",
+        handler: async () => ({ content: [{ type: "text", text: '{"status":"ok"}' }] }),
+      });
+
+      await expect(handler({})).rejects.toThrow("does not match its output-schema");
+    });
+  });
 });
@@ -0,0 +1,168 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Safe-job output helper utilities for safe outputs
+ *
+ * Safe-jobs can declare typed outputs (e.g., a deployment URL) that messages of
+ * built-in safe outputs reference with {{ safe_jobs.<job>.<output> }}. The compiler
+ * makes the safe_outputs job wait for those safe-jobs and passes their outputs in
+ * GH_AW_SAFE_JOB_OUTPUTS_<JOB> env vars, with the declared types in GH_AW_SAFE_JOB_OUTPUT_TYPES.
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+
+/**
+ * Regex pattern for matching safe-job output references in text
+ * Format: {{ safe_jobs.<job>.<output> }}
+ */
+const SAFE_JOB_OUTPUT_PATTERN = /\{\{\s*safe_jobs\.([A-Za-z0-9_-]+)\.([A-Za-z0-9_-]+)\s*\}\}/g;
+
+/**
+ * Normalize a safe-job name the same way the compiler names safe-job jobs
+ * @param {string} jobName - Job name as written in the reference
+ * @returns {string} Normalized job name
+ */
+function normalizeSafeJobName(jobName) {
+  return jobName.replace(/-/g, "_");
+}
+
+/**
+ * Check that an output value matches its declared type
+ * @param {string} value - Output value (job outputs are always strings)
+ * @param {string} type - Declared type: string, number or boolean
+ * @returns {boolean} Whether the value matches the type
+ */
+function matchesSafeJobOutputType(value, type) {
+  switch (type) {
+    case "number":
+      return value.trim() !== "" && Number.isFinite(Number(value));
+    case "boolean":
+      return value === "true" || value === "false";
+    default:
+      return true;
+  }
+}
+
+/**
+ * Load the outputs of safe-jobs from the environment. Outputs that are empty
+ * (e.g., the safe-job was skipped) or do not match their declared type are left out.
+ * @returns {Map<string, Map<string, string>>} Map of normalized job name to output values
+ */
+function loadSafeJobOutputs() {
+  /** @type {Map<string, Map<string, string>>} */
+  const outputs = new Map();
+
+  const typesJSON = process.env.GH_AW_SAFE_JOB_OUTPUT_TYPES;
+  if (!typesJSON) {
+    return outputs;
+  }
+
+  /** @type {Record<string, Record<string, string>>} */
+  let types;
+  try {
+    types = JSON.parse(typesJSON);
+  } catch (error) {
+    core.warning(`Failed to parse GH_AW_SAFE_JOB_OUTPUT_TYPES: ${getErrorMessage(error)}`);
+    return outputs;
+  }
+
+  for (const [jobName, outputTypes] of Object.entries(types)) {
+    const envVar = `GH_AW_SAFE_JOB_OUTPUTS_${jobName.toUpperCase()}`;
+    /** @type {Record<string, any>} */
+    let values = {};
+    try {
+      values = JSON.parse(process.env[envVar] || "{}") || {};
+    } catch (error) {
+      core.warning(`Failed to parse ${envVar}: ${getErrorMessage(error)}`);
+    }
+
+    /** @type {Map<string, string>} */
+    const jobOutputs = new Map();
+    for (const [outputName, type] of Object.entries(outputTypes)) {
+      const value = values[outputName];
+      if (value === undefined || value === null || value === "") {
+        continue;
+      }
+      const stringValue = String(value);
+      if (!matchesSafeJobOutputType(stringValue, type)) {
+        core.warning(`Output '${outputName}' of safe-job '${jobName}' is not a valid ${type}: ${JSON.stringify(stringValue)}. References to it will not be replaced.`);
+        continue;
+      }
+      jobOutputs.set(outputName, stringValue);
+    }
+    outputs.set(jobName, jobOutputs);
+    core.info(`Loaded ${jobOutputs.size} output(s) of safe-job '${jobName}'`);
+  }
+
+  return outputs;
+}
+
+/**
+ * Replace {{ safe_jobs.<job>.<output> }} references in text. References without
+ * a value are kept as-is and reported once through the unresolved set.
+ * @param {string} text - The text to process
+ * @param {Map<string, Map<string, string>>} safeJobOutputs - Outputs from loadSafeJobOutputs
+ * @param {Set<string>} [unresolved] - Collects references that could not be resolved
+ * @returns {string} Text with references replaced
+ */
+function replaceSafeJobOutputReferences(text, safeJobOutputs, unresolved) {
+  return text.replace(SAFE_JOB_OUTPUT_PATTERN, (match, jobName, outputName) => {
+    const value = safeJobOutputs.get(normalizeSafeJobName(jobName))?.get(outputName);
+    if (value === undefined) {
+      unresolved?.add(`safe_jobs.${jobName}.${outputName}`);
+      return match;
+    }
+    return value;
+  });
+}
+
+/**
+ * Replace safe-job output references in all string fields of safe output messages
+ * @param {Array<any>} messages - Safe output messages from the agent output
+ * @param {Map<string, Map<string, string>>} safeJobOutputs - Outputs from loadSafeJobOutputs
+ * @returns {Array<any>} Messages with references replaced
+ */
+function resolveSafeJobOutputsInMessages(messages, safeJobOutputs) {
+  /** @type {Set<string>} */
+  const unresolved = new Set();
+
+  /**
+   * @param {any} value
+   * @returns {any}
+   */
+  const resolve = value => {
+    if (typeof value === "string") {
+      return replaceSafeJobOutputReferences(value, safeJobOutputs, unresolved);
+    }
+    if (Array.isArray(value)) {
+      return value.map(resolve);
+    }
+    if (value && typeof value === "object") {
+      /** @type {Record<string, any>} */
+      const resolved = {};
+      for (const [key, item] of Object.entries(value)) {
+        resolved[key] = resolve(item);
+      }
+      return resolved;
+    }
+    return value;
+  };
+
+  const resolvedMessages = messages.map(message => (message && message.type ? { ...resolve(message), type: message.type } : message));
+
+  for (const reference of unresolved) {
+    core.warning(`No value for {{ ${reference} }} - the safe-job did not run, failed, or did not set the output. The reference is left unchanged.`);
+  }
+
+  return resolvedMessages;
+}
+
+module.exports = {
+  SAFE_JOB_OUTPUT_PATTERN,
+  normalizeSafeJobName,
+  matchesSafeJobOutputType,
+  loadSafeJobOutputs,
+  replaceSafeJobOutputReferences,
+  resolveSafeJobOutputsInMessages,
+};
@@ -0,0 +1,111 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+const mockCore = {
+  info: vi.fn(),
+  warning: vi.fn(),
+};
+global.core = mockCore;
+
+describe("safe_job_outputs.cjs", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_JOB_OUTPUT_TYPES;
+    delete process.env.GH_AW_SAFE_JOB_OUTPUTS_DEPLOY;
+    delete process.env.GH_AW_SAFE_JOB_OUTPUTS_CREATE_TICKET;
+  });
+
+  describe("matchesSafeJobOutputType", () => {
+    it("should validate number and boolean values", async () => {
+      const { matchesSafeJobOutputType } = await import("./safe_job_outputs.cjs");
+      expect(matchesSafeJobOutputType("42", "number")).toBe(true);
+      expect(matchesSafeJobOutputType("4.5", "number")).toBe(true);
+      expect(matchesSafeJobOutputType("abc", "number")).toBe(false);
+      expect(matchesSafeJobOutputType(" ", "number")).toBe(false);
+      expect(matchesSafeJobOutputType("true", "boolean")).toBe(true);
+      expect(matchesSafeJobOutputType("yes", "boolean")).toBe(false);
+      expect(matchesSafeJobOutputType("anything", "string")).toBe(true);
+    });
+  });
+
+  describe("loadSafeJobOutputs", () => {
+    it("should return an empty map when no safe-job outputs are configured", async () => {
+      const { loadSafeJobOutputs } = await import("./safe_job_outputs.cjs");
+      expect(loadSafeJobOutputs().size).toBe(0);
+    });
+
+    it("should load outputs and drop values that do not match their type", async () => {
+      const { loadSafeJobOutputs } = await import("./safe_job_outputs.cjs");
+      process.env.GH_AW_SAFE_JOB_OUTPUT_TYPES = JSON.stringify({
+        deploy: { url: "string", healthy: "boolean" },
+        create_ticket: { id: "number" },
+      });
+      process.env.GH_AW_SAFE_JOB_OUTPUTS_DEPLOY = JSON.stringify({ url: "https://preview.example.com", healthy: "maybe" });
+      process.env.GH_AW_SAFE_JOB_OUTPUTS_CREATE_TICKET = JSON.stringify({ id: "1234" });
+
+      const outputs = loadSafeJobOutputs();
+
+      expect(outputs.get("deploy").get("url")).toBe("https://preview.example.com");
+      expect(outputs.get("deploy").has("healthy")).toBe(false);
+      expect(outputs.get("create_ticket").get("id")).toBe("1234");
+      expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("not a valid boolean"));
+    });
+
+    it("should treat a skipped safe-job as having no outputs", async () => {
+      const { loadSafeJobOutputs } = await import("./safe_job_outputs.cjs");
+      process.env.GH_AW_SAFE_JOB_OUTPUT_TYPES = JSON.stringify({ deploy: { url: "string" } });
+      process.env.GH_AW_SAFE_JOB_OUTPUTS_DEPLOY = "{}";
+
+      const outputs = loadSafeJobOutputs();
+
+      expect(outputs.get("deploy").size).toBe(0);
+    });
+  });
+
+  describe("replaceSafeJobOutputReferences", () => {
+    it("should replace references and normalize dashed job names", async () => {
+      const { replaceSafeJobOutputReferences } = await import("./safe_job_outputs.cjs");
+      const outputs = new Map([["create_ticket", new Map([["id", "1234"]])]]);
+
+      expect(replaceSafeJobOutputReferences("Ticket {{ safe_jobs.create-ticket.id }} and {{safe_jobs.create_ticket.id}}", outputs)).toBe("Ticket 1234 and 1234");
+    });
+
+    it("should keep unresolved references and report them", async () => {
+      const { replaceSafeJobOutputReferences } = await import("./safe_job_outputs.cjs");
+      const unresolved = new Set();
+
+      expect(replaceSafeJobOutputReferences("See {{ safe_jobs.deploy.url }}", new Map(), unresolved)).toBe("See {{ safe_jobs.deploy.url }}");
+      expect([...unresolved]).toEqual(["safe_jobs.deploy.url"]);
+    });
+  });
+
+  describe("resolveSafeJobOutputsInMessages", () => {
+    it("should replace references in nested string fields without changing message types", async () => {
+      const { resolveSafeJobOutputsInMessages } = await import("./safe_job_outputs.cjs");
+      const outputs = new Map([["deploy", new Map([["url", "https://preview.example.com"]])]]);
+      const messages = [
+        { type: "add_comment", body: "Preview: {{ safe_jobs.deploy.url }}" },
+        { type: "create_issue", title: "Follow up", labels: ["{{ safe_jobs.deploy.url }}"], temporary_id: "aw_abc123" },
+      ];
+
+      const resolved = resolveSafeJobOutputsInMessages(messages, outputs);
+
+      expect(resolved[0]).toEqual({ type: "add_comment", body: "Preview: https://preview.example.com" });
+      expect(resolved[1].labels).toEqual(["https://preview.example.com"]);
+      expect(resolved[1].temporary_id).toBe("aw_abc123");
+      expect(messages[0].body).toBe("Preview: {{ safe_jobs.deploy.url }}");
+    });
+
+    it("should warn once per unresolved reference", async () => {
+      const { resolveSafeJobOutputsInMessages } = await import("./safe_job_outputs.cjs");
+      const messages = [
+        { type: "add_comment", body: "{{ safe_jobs.deploy.url }}" },
+        { type: "add_comment", body: "{{ safe_jobs.deploy.url }}" },
+      ];
+
+      resolveSafeJobOutputsInMessages(messages, new Map([["deploy", new Map()]]));
+
+      expect(mockCore.warning).toHaveBeenCalledTimes(1);
+      expect(mockCore.warning).toHaveBeenCalledWith(expect.stringContaining("{{ safe_jobs.deploy.url }}"));
+    });
+  });
+});
@@ -0,0 +1,153 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Safe Output Approval Gates
+ *
+ * Safe output types configured with require-approval are applied by a separate job bound to a
+ * GitHub Environment. The compiler passes the gates as JSON in GH_AW_SAFE_OUTPUTS_APPROVAL, mapping
+ * each gated type to its environment. The approval job also sets GH_AW_SAFE_OUTPUTS_APPROVAL_ENVIRONMENT.
+ *
+ * - In the safe_outputs job, gated messages are skipped and previewed in the step summary for reviewers.
+ * - In an approval job, only the messages gated by its environment are applied.
+ */
+
+const fs = require("fs");
+const { generateStagedPreview } = require("./staged_preview.cjs");
+
+/** Maximum body length shown in the approval preview */
+const MAX_PREVIEW_BODY_LENGTH = 1000;
+
+/**
+ * Load the approval gates from the environment
+ * @returns {Object<string, string>|null} Map of safe output type to environment, or null when not configured
+ */
+function loadApprovalGates() {
+  const raw = process.env.GH_AW_SAFE_OUTPUTS_APPROVAL;
+  if (!raw) {
+    return null;
+  }
+
+  try {
+    const gates = JSON.parse(raw);
+    if (!gates || typeof gates !== "object" || Array.isArray(gates) || Object.keys(gates).length === 0) {
+      return null;
+    }
+    return gates;
+  } catch (error) {
+    core.warning(`Failed to parse GH_AW_SAFE_OUTPUTS_APPROVAL, ignoring approval gates: ${error instanceof Error ? error.message : String(error)}`);
+    return null;
+  }
+}
+
+/**
+ * Split messages between this job and the jobs that apply them elsewhere
+ *
+ * @param {Array<any>} messages - Safe output messages in order of appearance
+ * @param {Object<string, string>} gates - Map of safe output type to environment
+ * @param {string} environment - Environment of the current approval job, or empty in the safe_outputs job
+ * @returns {{skippedMessages: Map<number, string>, pendingApproval: Map<string, Array<any>>}}
+ *   Messages to skip in this job with the reason, and the gated messages awaiting approval grouped by environment
+ */
+function partitionForApproval(messages, gates, environment) {
+  /** @type {Map<number, string>} */
+  const skippedMessages = new Map();
+  /** @type {Map<string, Array<any>>} */
+  const pendingApproval = new Map();
+
+  for (let i = 0; i < messages.length; i++) {
+    const message = messages[i];
+    const gate = message && message.type ? gates[message.type] : undefined;
+
+    if (environment) {
+      // Approval job: only apply the messages gated by this environment
+      if (gate !== environment) {
+        skippedMessages.set(i, gate ? `Applied by the approval job for environment '${gate}'` : "Applied by the safe_outputs job");
+      }
+      continue;
+    }
+
+    if (gate) {
+      skippedMessages.set(i, `Awaiting approval in environment '${gate}'`);
+      const pending = pendingApproval.get(gate) || [];
+      pending.push(message);
+      pendingApproval.set(gate, pending);
+    }
+  }
+
+  return { skippedMessages, pendingApproval };
+}
+
+/**
+ * Render a gated message for the approval preview
+ * @param {any} message - The safe output message
+ * @returns {string} Markdown for the step summary
+ */
+function renderApprovalItem(message) {
+  let content = `**Type:** \`${message.type}\`\n\n`;
+
+  const fields = [
+    ["title", "Title"],
+    ["branch", "Branch"],
+    ["tag", "Tag"],
+    ["path", "Path"],
+    ["pull_request_number", "Pull Request"],
+    ["workflow_name", "Workflow"],
+    ["commit_message", "Commit Message"],
+  ];
+  for (const [field, label] of fields) {
+    if (message[field] !== undefined && message[field] !== null && message[field] !== "") {
+      content += `**${label}:** ${message[field]}\n\n`;
+    }
+  }
+
+  if (Array.isArray(message.labels) && message.labels.length > 0) {
+    content += `**Labels:** ${message.labels.join(", ")}\n\n`;
+  }
+
+  if (typeof message.body === "string" && message.body) {
+    const body = message.body.length > MAX_PREVIEW_BODY_LENGTH ? message.body.substring(0, MAX_PREVIEW_BODY_LENGTH) + "..." : message.body;
+    content += `**Body:**\n\n${body}\n\n`;
+  }
+
+  if ((message.type === "create_pull_request" || message.type === "push_to_pull_request_branch") && fs.existsSync("/tmp/gh-aw/aw.patch")) {
+    const patch = fs.readFileSync("/tmp/gh-aw/aw.patch", "utf8");
+    if (patch.trim()) {
+      content += `<details><summary>Show patch preview</summary>\n\n\`\`\`diff\n${patch.slice(0, 2000)}${patch.length > 2000 ? "\n... (truncated)" : ""}\n\`\`\`\n\n</details>\n\n`;
+    }
+  } else if (message.type === "create_pull_request" || message.type === "push_to_pull_request_branch") {
+    content += `**Changes:** see \`aw.patch\` in the \`agent-artifacts\` artifact\n\n`;
+  }
+
+  return content;
+}
+
+/**
+ * Write a staged preview of the messages awaiting approval and export the pending environments
+ * so only the approval jobs with pending items request a review.
+ *
+ * @param {Map<string, Array<any>>} pendingApproval - Gated messages grouped by environment
+ * @returns {Promise<void>}
+ */
+async function writeApprovalPreview(pendingApproval) {
+  const environments = Array.from(pendingApproval.keys()).sort();
+  core.setOutput("pending_approval_environments", JSON.stringify(environments));
+
+  for (const environment of environments) {
+    const items = pendingApproval.get(environment) || [];
+    core.info(`${items.length} safe output(s) awaiting approval in environment '${environment}'`);
+    await generateStagedPreview({
+      title: `Awaiting Approval (${environment})`,
+      description: `The following safe outputs will be applied after a reviewer approves the \`${environment}\` environment for this run:`,
+      items,
+      renderItem: item => renderApprovalItem(item),
+    });
+  }
+}
+
+module.exports = {
+  loadApprovalGates,
+  partitionForApproval,
+  renderApprovalItem,
+  writeApprovalPreview,
+};
@@ -0,0 +1,103 @@
+// @ts-check
+
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import { loadApprovalGates, partitionForApproval, renderApprovalItem, writeApprovalPreview } from "./safe_output_approval.cjs";
+
+describe("safe_output_approval", () => {
+  const gates = { create_pull_request: "production", dispatch_workflow: "ops" };
+  const messages = [
+    { type: "add_comment", body: "Fixed in the linked PR" },
+    { type: "create_pull_request", title: "Fix crash", body: "Fixes the crash", branch: "fix-crash" },
+    { type: "dispatch_workflow", workflow_name: "deploy" },
+  ];
+
+  beforeEach(() => {
+    global.core = {
+      info: vi.fn(),
+      debug: vi.fn(),
+      warning: vi.fn(),
+      error: vi.fn(),
+      setOutput: vi.fn(),
+      setFailed: vi.fn(),
+      summary: {
+        addRaw: vi.fn().mockImplementation(() => global.core.summary),
+        write: vi.fn().mockResolvedValue(undefined),
+      },
+    };
+  });
+
+  afterEach(() => {
+    delete process.env.GH_AW_SAFE_OUTPUTS_APPROVAL;
+  });
+
+  describe("loadApprovalGates", () => {
+    it("should return null when no gates are configured", () => {
+      expect(loadApprovalGates()).toBe(null);
+    });
+
+    it("should parse the gates from the environment", () => {
+      process.env.GH_AW_SAFE_OUTPUTS_APPROVAL = JSON.stringify(gates);
+
+      expect(loadApprovalGates()).toEqual(gates);
+    });
+
+    it("should warn and ignore invalid JSON", () => {
+      process.env.GH_AW_SAFE_OUTPUTS_APPROVAL = "not json";
+
+      expect(loadApprovalGates()).toBe(null);
+      expect(global.core.warning).toHaveBeenCalledWith(expect.stringContaining("Failed to parse GH_AW_SAFE_OUTPUTS_APPROVAL"));
+    });
+  });
+
+  describe("partitionForApproval", () => {
+    it("should skip gated messages in the safe_outputs job and group them by environment", () => {
+      const { skippedMessages, pendingApproval } = partitionForApproval(messages, gates, "");
+
+      expect(skippedMessages.size).toBe(2);
+      expect(skippedMessages.get(1)).toBe("Awaiting approval in environment 'production'");
+      expect(skippedMessages.get(2)).toBe("Awaiting approval in environment 'ops'");
+      expect(pendingApproval.get("production")).toEqual([messages[1]]);
+      expect(pendingApproval.get("ops")).toEqual([messages[2]]);
+    });
+
+    it("should only apply messages gated by the environment in an approval job", () => {
+      const { skippedMessages, pendingApproval } = partitionForApproval(messages, gates, "production");
+
+      expect(skippedMessages.size).toBe(2);
+      expect(skippedMessages.get(0)).toBe("Applied by the safe_outputs job");
+      expect(skippedMessages.get(2)).toBe("Applied by the approval job for environment 'ops'");
+      expect(skippedMessages.get(1)).toBe(undefined);
+      expect(pendingApproval.size).toBe(0);
+    });
+  });
+
+  describe("renderApprovalItem", () => {
+    it("should render the fields a reviewer needs", () => {
+      const content = renderApprovalItem(messages[1]);
+
+      expect(content).toContain("**Type:** `create_pull_request`");
+      expect(content).toContain("**Title:** Fix crash");
+      expect(content).toContain("**Branch:** fix-crash");
+      expect(content).toContain("Fixes the crash");
+    });
+  });
+
+  describe("writeApprovalPreview", () => {
+    it("should export the pending environments and write a preview per environment", async () => {
+      const { pendingApproval } = partitionForApproval(messages, gates, "");
+
+      await writeApprovalPreview(pendingApproval);
+
+      expect(global.core.setOutput).toHaveBeenCalledWith("pending_approval_environments", '["ops","production"]');
+      expect(global.core.summary.addRaw).toHaveBeenCalledWith(expect.stringContaining("Awaiting Approval (production)"));
+      expect(global.core.summary.addRaw).toHaveBeenCalledWith(expect.stringContaining("Awaiting Approval (ops)"));
+    });
+
+    it("should export an empty list when nothing is pending", async () => {
+      await writeApprovalPreview(new Map());
+
+      expect(global.core.setOutput).toHaveBeenCalledWith("pending_approval_environments", "[]");
+      expect(global.core.summary.addRaw).not.toHaveBeenCalled();
+    });
+  });
+});
@@ -0,0 +1,299 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Safe Output Content Policy
+ *
+ * Enforces the safe-outputs.content-policy frontmatter on the text of safe outputs before any
+ * GitHub API call is made. The compiler passes the policy as JSON in GH_AW_SAFE_OUTPUTS_CONTENT_POLICY:
+ *
+ * - deny: regular expressions (case-insensitive) that must not match any text field
+ * - allow: exceptions; a deny match is ignored when it falls within text matched by an allow pattern
+ * - max_length: maximum length per text field (title, body, annotations[].message, ...)
+ * - required_sections: headers that must appear in the body, keyed by safe output type
+ * - banned_words: words (case-insensitive, whole word) that must not appear in any text field
+ * - detect_secrets: reject text containing secret-looking strings
+ * - text_fields: the text fields of each safe output type, derived from the tool schemas;
+ *   "[]" marks array items (e.g. "annotations[].message"). Every string value of a message
+ *   is checked when its type has no entry.
+ *
+ * Messages that violate the policy are reported as failed items instead of being applied.
+ * The policy fails closed: when a pattern cannot be compiled, every message is rejected.
+ */
+
+const { BUILT_IN_PATTERNS } = require("./redact_secrets.cjs");
+
+/** Secret-looking strings detected in addition to the token patterns redacted from logs */
+const SECRET_PATTERNS = [...BUILT_IN_PATTERNS, { name: "Private Key", pattern: /-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----/g }];
+
+/**
+ * Load the content policy from the environment
+ * @returns {any|null} The content policy, or null when not configured
+ */
+function loadContentPolicy() {
+  const raw = process.env.GH_AW_SAFE_OUTPUTS_CONTENT_POLICY;
+  if (!raw) {
+    return null;
+  }
+
+  try {
+    const policy = JSON.parse(raw);
+    if (!policy || typeof policy !== "object" || Array.isArray(policy)) {
+      core.warning("GH_AW_SAFE_OUTPUTS_CONTENT_POLICY is not an object, ignoring content policy");
+      return null;
+    }
+    return policy;
+  } catch (error) {
+    core.warning(`Failed to parse GH_AW_SAFE_OUTPUTS_CONTENT_POLICY, ignoring content policy: ${error instanceof Error ? error.message : String(error)}`);
+    return null;
+  }
+}
+
+/**
+ * Compile a list of patterns. Patterns that JavaScript cannot compile are collected in
+ * invalid so that messages are rejected rather than checked against a partial policy.
+ * @param {any} patterns - Pattern strings from the policy
+ * @param {string[]} invalid - Receives a description of each pattern that cannot be compiled
+ * @returns {RegExp[]} Compiled case-insensitive regular expressions
+ */
+function compilePatterns(patterns, invalid) {
+  if (!Array.isArray(patterns)) {
+    return [];
+  }
+  /** @type {RegExp[]} */
+  const compiled = [];
+  for (const pattern of patterns) {
+    try {
+      compiled.push(new RegExp(pattern, "i"));
+    } catch (error) {
+      invalid.push(`content-policy pattern '${pattern}' is invalid: ${error instanceof Error ? error.message : String(error)}`);
+    }
+  }
+  return compiled;
+}
+
+/**
+ * Find all matches of a pattern without mutating it (the stored patterns have no "g" flag)
+ * @param {string} value - Text to search
+ * @param {RegExp} pattern - Pattern to match
+ * @returns {Array<[number, number]>} Start and end offsets of each match
+ */
+function matchRanges(value, pattern) {
+  return Array.from(value.matchAll(new RegExp(pattern.source, pattern.flags + "g")), match => [match.index, match.index + match[0].length]);
+}
+
+/**
+ * @typedef {Object} TextValue
+ * @property {string} field - Location of the value in the message, e.g. "annotations[2].message"
+ * @property {string} path - Schema path of the field, e.g. "annotations[].message"
+ * @property {string} value - The text
+ */
+
+/**
+ * Collect the text values of a message at a schema path
+ * @param {any} value - Current value
+ * @param {string[]} segments - Remaining path segments
+ * @param {string} path - Full schema path
+ * @param {string} field - Location of value in the message
+ * @param {TextValue[]} out - Receives the text values
+ */
+function collectPathValues(value, segments, path, field, out) {
+  if (segments.length === 0) {
+    if (typeof value === "string" && value !== "") {
+      out.push({ field, path, value });
+    }
+    return;
+  }
+  if (value === null || typeof value !== "object") {
+    return;
+  }
+  const [segment, ...rest] = segments;
+  const isArray = segment.endsWith("[]");
+  const name = isArray ? segment.slice(0, -2) : segment;
+  const child = name === "" ? value : value[name];
+  const childField = name === "" ? field : field ? `${field}.${name}` : name;
+  if (!isArray) {
+    collectPathValues(child, rest, path, childField, out);
+  } else if (Array.isArray(child)) {
+    child.forEach((item, index) => collectPathValues(item, rest, path, `${childField}[${index}]`, out));
+  }
+}
+
+/**
+ * Collect every string value of a message except its type
+ * @param {any} value - Current value
+ * @param {string} field - Location of value in the message
+ * @param {string} path - Schema path of value
+ * @param {TextValue[]} out - Receives the text values
+ */
+function collectAllValues(value, field, path, out) {
+  if (typeof value === "string") {
+    if (value !== "") {
+      out.push({ field, path, value });
+    }
+  } else if (Array.isArray(value)) {
+    value.forEach((item, index) => collectAllValues(item, `${field}[${index}]`, `${path}[]`, out));
+  } else if (value !== null && typeof value === "object") {
+    for (const [key, child] of Object.entries(value)) {
+      if (field === "" && key === "type") {
+        continue;
+      }
+      collectAllValues(child, field ? `${field}.${key}` : key, path ? `${path}.${key}` : key, out);
+    }
+  }
+}
+
+/**
+ * Collect the text values of a message checked by the content policy
+ * @param {any} message - The safe output message
+ * @param {Record<string, string[]>} textFields - Text fields by safe output type
+ * @returns {TextValue[]} The text values
+ */
+function collectTextValues(message, textFields) {
+  /** @type {TextValue[]} */
+  const values = [];
+  const fields = textFields[message.type];
+  if (!Array.isArray(fields)) {
+    collectAllValues(message, "", "", values);
+    return values;
+  }
+  for (const path of fields) {
+    collectPathValues(message, path.split("."), path, "", values);
+  }
+  return values;
+}
+
+/**
+ * Escape a string for use in a regular expression
+ * @param {string} value
+ * @returns {string}
+ */
+function escapeRegExp(value) {
+  return value.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+}
+
+/**
+ * Check whether a markdown body contains a section header
+ * @param {string} body - The markdown body
+ * @param {string} header - The required header, e.g. "## Summary" or "Summary"
+ * @returns {boolean}
+ */
+function hasSection(body, header) {
+  const wanted = header.trim().toLowerCase();
+  const wantedText = wanted.replace(/^#+\s*/, "");
+  return body.split("\n").some(line => {
+    const trimmed = line.trim().toLowerCase();
+    if (wanted.startsWith("#")) {
+      return trimmed === wanted;
+    }
+    // Headers given without '#' match a markdown heading of any level
+    return /^#{1,6}\s/.test(trimmed) && trimmed.replace(/^#+\s*/, "") === wantedText;
+  });
+}
+
+/**
+ * Compile the content policy into matchers
+ * @param {any} policy - The content policy from the environment
+ */
+function compileContentPolicy(policy) {
+  const bannedWords = Array.isArray(policy.banned_words) ? policy.banned_words.filter(word => typeof word === "string" && word.trim()) : [];
+  /** @type {string[]} */
+  const invalidPatterns = [];
+  return {
+    deny: compilePatterns(policy.deny, invalidPatterns),
+    allow: compilePatterns(policy.allow, invalidPatterns),
+    invalidPatterns,
+    textFields: policy.text_fields && typeof policy.text_fields === "object" ? policy.text_fields : {},
+    maxLength: policy.max_length && typeof policy.max_length === "object" ? policy.max_length : {},
+    requiredSections: policy.required_sections && typeof policy.required_sections === "object" ? policy.required_sections : {},
+    bannedWords: bannedWords.map(word => ({ word, pattern: new RegExp(`(?<![\\p{L}\\p{N}_])${escapeRegExp(word.trim())}(?![\\p{L}\\p{N}_])`, "iu") })),
+    detectSecrets: policy.detect_secrets === true,
+  };
+}
+
+/**
+ * Check a message against the compiled content policy
+ * @param {any} message - The safe output message
+ * @param {ReturnType<typeof compileContentPolicy>} compiled - The compiled content policy
+ * @returns {string|null} The reason the message violates the policy, or null when it complies
+ */
+function checkMessage(message, compiled) {
+  if (compiled.invalidPatterns.length > 0) {
+    return compiled.invalidPatterns.join("; ");
+  }
+
+  for (const { field, path, value } of collectTextValues(message, compiled.textFields)) {
+    const maxLength = compiled.maxLength[path];
+    if (typeof maxLength === "number" && value.length > maxLength) {
+      return `${field} is ${value.length} characters long, exceeding the content-policy maximum of ${maxLength}`;
+    }
+
+    if (compiled.deny.length > 0) {
+      // A deny match is allowed when it falls within text matched by an allow pattern
+      const allowed = compiled.allow.flatMap(allow => matchRanges(value, allow));
+      for (const deny of compiled.deny) {
+        for (const [start, end] of matchRanges(value, deny)) {
+          if (!allowed.some(([allowStart, allowEnd]) => allowStart <= start && end <= allowEnd)) {
+            return `${field} matches denied pattern '${deny.source}'`;
+          }
+        }
+      }
+    }
+
+    for (const { word, pattern } of compiled.bannedWords) {
+      if (pattern.test(value)) {
+        return `${field} contains banned word '${word}'`;
+      }
+    }
+
+    if (compiled.detectSecrets) {
+      for (const { name, pattern } of SECRET_PATTERNS) {
+        if (new RegExp(pattern.source).test(value)) {
+          return `${field} contains a secret-looking string (${name})`;
+        }
+      }
+    }
+  }
+
+  const requiredSections = compiled.requiredSections[message.type];
+  if (Array.isArray(requiredSections)) {
+    const body = typeof message.body === "string" ? message.body : "";
+    const missing = requiredSections.filter(header => !hasSection(body, header));
+    if (missing.length > 0) {
+      return `body is missing required section(s): ${missing.join(", ")}`;
+    }
+  }
+
+  return null;
+}
+
+/**
+ * Find messages that violate the content policy
+ *
+ * @param {Array<any>} messages - Safe output messages in order of appearance
+ * @param {any} policy - The content policy from the environment
+ * @returns {Map<number, string>} Map of message index to the reason it is blocked
+ */
+function findContentPolicyViolations(messages, policy) {
+  const compiled = compileContentPolicy(policy);
+
+  /** @type {Map<number, string>} */
+  const violations = new Map();
+  for (let i = 0; i < messages.length; i++) {
+    const message = messages[i];
+    if (!message || !message.type) {
+      continue;
+    }
+    const reason = checkMessage(message, compiled);
+    if (reason) {
+      violations.set(i, `Content policy violation: ${reason}`);
+    }
+  }
+  return violations;
+}
+
+module.exports = {
+  loadContentPolicy,
+  hasSection,
+  findContentPolicyViolations,
+};
@@ -0,0 +1,148 @@
+// @ts-check
+
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import { loadContentPolicy, hasSection, findContentPolicyViolations } from "./safe_output_content_policy.cjs";
+
+describe("safe_output_content_policy", () => {
+  beforeEach(() => {
+    global.core = {
+      info: vi.fn(),
+      debug: vi.fn(),
+      warning: vi.fn(),
+      error: vi.fn(),
+    };
+  });
+
+  afterEach(() => {
+    delete process.env.GH_AW_SAFE_OUTPUTS_CONTENT_POLICY;
+  });
+
+  describe("loadContentPolicy", () => {
+    it("should return null when no policy is configured", () => {
+      expect(loadContentPolicy()).toBe(null);
+    });
+
+    it("should parse the policy from the environment", () => {
+      process.env.GH_AW_SAFE_OUTPUTS_CONTENT_POLICY = JSON.stringify({ banned_words: ["confidential"] });
+
+      expect(loadContentPolicy()).toEqual({ banned_words: ["confidential"] });
+    });
+
+    it("should warn and ignore invalid JSON", () => {
+      process.env.GH_AW_SAFE_OUTPUTS_CONTENT_POLICY = "not json";
+
+      expect(loadContentPolicy()).toBe(null);
+      expect(global.core.warning).toHaveBeenCalledWith(expect.stringContaining("Failed to parse GH_AW_SAFE_OUTPUTS_CONTENT_POLICY"));
+    });
+  });
+
+  describe("hasSection", () => {
+    it("should match headers exactly or at any level when given without '#'", () => {
+      const body = "Intro\n\n## Summary\n\nText\n\n### Testing\n";
+
+      expect(hasSection(body, "## Summary")).toBe(true);
+      expect(hasSection(body, "summary")).toBe(true);
+      expect(hasSection(body, "Testing")).toBe(true);
+      expect(hasSection(body, "## Testing")).toBe(false);
+      expect(hasSection(body, "Intro")).toBe(false);
+    });
+  });
+
+  describe("findContentPolicyViolations", () => {
+    it("should block text matching a deny pattern unless an allow pattern matches", () => {
+      const policy = { deny: ["internal\\.example\\.com"], allow: ["docs\\.internal\\.example\\.com"] };
+      const messages = [
+        { type: "add_comment", body: "See https://wiki.internal.example.com/page" },
+        { type: "add_comment", body: "See https://docs.internal.example.com/page" },
+      ];
+
+      const violations = findContentPolicyViolations(messages, policy);
+
+      expect(violations.size).toBe(1);
+      expect(violations.get(0)).toBe("Content policy violation: body matches denied pattern 'internal\\.example\\.com'");
+    });
+
+    it("should enforce maximum lengths per field", () => {
+      const violations = findContentPolicyViolations([{ type: "create_issue", title: "A very long title", body: "ok" }], { max_length: { title: 5 } });
+
+      expect(violations.get(0)).toBe("Content policy violation: title is 17 characters long, exceeding the content-policy maximum of 5");
+    });
+
+    it("should only match banned words as whole words", () => {
+      const policy = { banned_words: ["secret"] };
+      const messages = [
+        { type: "add_comment", body: "This is SECRET information" },
+        { type: "add_comment", body: "Rotate the secrets manager keys" },
+      ];
+
+      const violations = findContentPolicyViolations(messages, policy);
+
+      expect(violations.size).toBe(1);
+      expect(violations.get(0)).toBe("Content policy violation: body contains banned word 'secret'");
+    });
+
+    it("should require sections for the configured types only", () => {
+      const policy = { required_sections: { create_pull_request: ["## Summary", "## Testing"] } };
+      const messages = [
+        { type: "create_pull_request", title: "Fix", body: "## Summary\n\nFixes the crash" },
+        { type: "add_comment", body: "No sections here" },
+      ];
+
+      const violations = findContentPolicyViolations(messages, policy);
+
+      expect(violations.size).toBe(1);
+      expect(violations.get(0)).toBe("Content policy violation: body is missing required section(s): ## Testing");
+    });
+
+    it("should detect secret-looking strings when enabled", () => {
+      const messages = [{ type: "create_issue", title: "Leak", body: `token: ghp_${"a".repeat(36)}` }];
+
+      expect(findContentPolicyViolations(messages, {}).size).toBe(0);
+      expect(findContentPolicyViolations(messages, { detect_secrets: true }).get(0)).toBe("Content policy violation: body contains a secret-looking string (GitHub Personal Access Token (classic))");
+    });
+
+    it("should reject every message when a pattern cannot be compiled", () => {
+      const violations = findContentPolicyViolations([{ type: "add_comment", body: "text" }], { deny: ["(unclosed"] });
+
+      expect(violations.size).toBe(1);
+      expect(violations.get(0)).toContain("Content policy violation: content-policy pattern '(unclosed' is invalid");
+    });
+
+    it("should check the text fields of each type, including nested arrays", () => {
+      const policy = {
+        deny: ["internal\\.example\\.com"],
+        max_length: { "annotations[].message": 25 },
+        text_fields: { create_check_run: ["title", "summary", "text", "annotations[].message"] },
+      };
+      const messages = [
+        { type: "create_check_run", title: "Lint", summary: "ok", annotations: [{ path: "internal.example.com", message: "fine" }] },
+        { type: "create_check_run", title: "Lint", summary: "ok", annotations: [{ path: "a.js", message: "ok" }, { path: "b.js", message: "see internal.example.com" }] },
+        { type: "create_check_run", title: "Lint", summary: "ok", annotations: [{ path: "a.js", message: "a message that is too long" }] },
+      ];
+
+      const violations = findContentPolicyViolations(messages, policy);
+
+      expect(violations.has(0)).toBe(false);
+      expect(violations.get(1)).toBe("Content policy violation: annotations[1].message matches denied pattern 'internal\\.example\\.com'");
+      expect(violations.get(2)).toBe("Content policy violation: annotations[0].message is 26 characters long, exceeding the content-policy maximum of 25");
+    });
+
+    it("should check every string value of types without text fields", () => {
+      const messages = [{ type: "merge_pull_request", commit_title: "Merge JIRA-123" }];
+
+      expect(findContentPolicyViolations(messages, { deny: ["JIRA-\\d+"] }).get(0)).toBe("Content policy violation: commit_title matches denied pattern 'JIRA-\\d+'");
+      expect(findContentPolicyViolations([{ type: "jira" }], { deny: ["jira"] }).size).toBe(0);
+    });
+
+    it("should give the same result when a pattern is checked repeatedly", () => {
+      const policy = { deny: ["secret"] };
+      const messages = [
+        { type: "add_comment", body: "a secret" },
+        { type: "add_comment", body: "another secret" },
+        { type: "add_comment", body: "secret again" },
+      ];
+
+      expect(findContentPolicyViolations(messages, policy).size).toBe(3);
+    });
+  });
+});
@@ -11,13 +11,17 @@
 
 const { loadAgentOutput } = require("./load_agent_output.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
-const { hasUnresolvedTemporaryIds, replaceTemporaryIdReferences, normalizeTemporaryId } = require("./temporary_id.cjs");
+const { hasUnresolvedTemporaryIds, replaceTemporaryIdReferences, normalizeTemporaryId, loadTemporaryIdMap } = require("./temporary_id.cjs");
 const { generateMissingInfoSections } = require("./missing_info_formatter.cjs");
 const { setCollectedMissings } = require("./missing_messages_helper.cjs");
 const { writeSafeOutputSummaries } = require("./safe_output_summary.cjs");
 const { getIssuesToAssignCopilot } = require("./create_issue.cjs");
 const { createReviewBuffer } = require("./pr_review_buffer.cjs");
 const { sanitizeContent } = require("./sanitize_content.cjs");
+const { loadSafeJobOutputs, resolveSafeJobOutputsInMessages } = require("./safe_job_outputs.cjs");
+const { loadReferencePolicy, findReferenceViolations } = require("./safe_output_references.cjs");
+const { loadApprovalGates, partitionForApproval, writeApprovalPreview } = require("./safe_output_approval.cjs");
+const { loadContentPolicy, findContentPolicyViolations } = require("./safe_output_content_policy.cjs");
 
 /**
  * Handler map configuration
@@ -31,10 +35,14 @@ const HANDLER_MAP = {
   close_discussion: "./close_discussion.cjs",
   add_labels: "./add_labels.cjs",
   remove_labels: "./remove_labels.cjs",
+  manage_labels: "./manage_labels.cjs",
   update_issue: "./update_issue.cjs",
   update_discussion: "./update_discussion.cjs",
   link_sub_issue: "./link_sub_issue.cjs",
   update_release: "./update_release.cjs",
+  create_release: "./create_release.cjs",
+  create_tag: "./create_tag.cjs",
+  update_file: "./update_file.cjs",
   create_pull_request_review_comment: "./create_pr_review_comment.cjs",
   submit_pull_request_review: "./submit_pr_review.cjs",
   reply_to_pull_request_review_comment: "./reply_to_pr_review_comment.cjs",
@@ -43,13 +51,19 @@ const HANDLER_MAP = {
   push_to_pull_request_branch: "./push_to_pull_request_branch.cjs",
   update_pull_request: "./update_pull_request.cjs",
   close_pull_request: "./close_pull_request.cjs",
+  merge_pull_request: "./merge_pull_request.cjs",
   mark_pull_request_as_ready_for_review: "./mark_pull_request_as_ready_for_review.cjs",
   hide_comment: "./hide_comment.cjs",
+  lock_conversation: "./lock_conversation.cjs",
+  pin_issue: "./pin_issue.cjs",
+  transfer_issue: "./transfer_issue.cjs",
   add_reviewer: "./add_reviewer.cjs",
   assign_milestone: "./assign_milestone.cjs",
   assign_to_user: "./assign_to_user.cjs",
   unassign_from_user: "./unassign_from_user.cjs",
   create_code_scanning_alert: "./create_code_scanning_alert.cjs",
+  create_check_run: "./create_check_run.cjs",
+  create_commit_status: "./create_commit_status.cjs",
   autofix_code_scanning_alert: "./autofix_code_scanning_alert.cjs",
   dispatch_workflow: "./dispatch_workflow.cjs",
   create_missing_tool_issue: "./create_missing_tool_issue.cjs",
@@ -205,18 +219,23 @@ function collectMissingMessages(messages) {
  *
  * @param {Map<string, Function>} messageHandlers - Map of message handler functions
  * @param {Array<Object>} messages - Array of safe output messages
+ * @param {{blockedMessages?: Map<number, string>, skippedMessages?: Map<number, string>}} [options] - Messages to fail with the reason
+ *   (e.g. reference policy violations) and messages to skip with the reason (e.g. applied by an approval job)
  * @returns {Promise<{success: boolean, results: Array<any>, temporaryIdMap: Object, outputsWithUnresolvedIds: Array<any>, missings: Object}>}
  */
-async function processMessages(messageHandlers, messages) {
+async function processMessages(messageHandlers, messages, options = {}) {
+  const blockedMessages = options.blockedMessages || new Map();
+  const skippedMessages = options.skippedMessages || new Map();
   const results = [];
 
   // Collect missing_tool and missing_data messages first
   const missings = collectMissingMessages(messages);
 
   // Initialize shared temporary ID map
-  // This will be populated by handlers as they create entities with temporary IDs
+  // This will be populated by handlers as they create entities with temporary IDs.
+  // Approval jobs start from the IDs resolved by the safe_outputs job (GH_AW_TEMPORARY_ID_MAP).
   /** @type {Map<string, {repo: string, number: number}>} */
-  const temporaryIdMap = new Map();
+  const temporaryIdMap = loadTemporaryIdMap();
 
   // Track outputs that were created with unresolved temporary IDs
   // Format: {type, message, result, originalTempIdMapSize}
@@ -240,6 +259,31 @@ async function processMessages(messageHandlers, messages) {
       continue;
     }
 
+    const skippedReason = skippedMessages.get(i);
+    if (skippedReason) {
+      core.info(`Skipping message ${i + 1} (${messageType}): ${skippedReason}`);
+      results.push({
+        type: messageType,
+        messageIndex: i,
+        success: false,
+        skipped: true,
+        reason: skippedReason,
+      });
+      continue;
+    }
+
+    const blockedReason = blockedMessages.get(i);
+    if (blockedReason) {
+      core.error(`✗ Message ${i + 1} (${messageType}) blocked: ${blockedReason}`);
+      results.push({
+        type: messageType,
+        messageIndex: i,
+        success: false,
+        error: blockedReason,
+      });
+      continue;
+    }
+
     const messageHandler = messageHandlers.get(messageType);
 
     if (!messageHandler) {
@@ -766,8 +810,45 @@ async function main() {
       return;
     }
 
+    // Replace {{ safe_jobs.<job>.<output> }} references with the outputs of safe-jobs
+    const safeJobOutputs = loadSafeJobOutputs();
+    const messages = safeJobOutputs.size > 0 ? resolveSafeJobOutputsInMessages(agentOutput.items, safeJobOutputs) : agentOutput.items;
+
+    // Block cross-item references that safe-outputs.references does not allow
+    const referencePolicy = loadReferencePolicy();
+    const blockedMessages = referencePolicy ? findReferenceViolations(messages, referencePolicy) : new Map();
+    if (blockedMessages.size > 0) {
+      core.warning(`${blockedMessages.size} message(s) blocked by the safe-outputs reference policy`);
+    }
+
+    // Block messages whose text violates safe-outputs.content-policy before any API call
+    const contentPolicy = loadContentPolicy();
+    if (contentPolicy) {
+      const contentViolations = findContentPolicyViolations(messages, contentPolicy);
+      for (const [index, reason] of contentViolations) {
+        if (!blockedMessages.has(index)) {
+          blockedMessages.set(index, reason);
+        }
+      }
+      if (contentViolations.size > 0) {
+        core.warning(`${contentViolations.size} message(s) blocked by the safe-outputs content policy`);
+      }
+    }
+
+    // Leave safe outputs that require approval to their approval job, previewing them for reviewers
+    const approvalGates = loadApprovalGates();
+    let skippedMessages = new Map();
+    if (approvalGates) {
+      const approvalEnvironment = process.env.GH_AW_SAFE_OUTPUTS_APPROVAL_ENVIRONMENT || "";
+      const partition = partitionForApproval(messages, approvalGates, approvalEnvironment);
+      skippedMessages = partition.skippedMessages;
+      if (!approvalEnvironment) {
+        await writeApprovalPreview(partition.pendingApproval);
+      }
+    }
+
     // Process all messages in order of appearance
-    const processingResult = await processMessages(messageHandlers, agentOutput.items);
+    const processingResult = await processMessages(messageHandlers, messages, { blockedMessages, skippedMessages });
 
     // Finalize buffered PR review — submit when comments or metadata exist
     if (prReviewBuffer.hasBufferedComments() || prReviewBuffer.hasReviewMetadata()) {
@@ -0,0 +1,100 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Safe Output Reference Policy
+ *
+ * Enforces the safe-outputs.references frontmatter: which safe output types may
+ * reference items created earlier in the same run through temporary IDs (#aw_ID).
+ * The compiler passes the policy as JSON in GH_AW_SAFE_OUTPUT_REFERENCES, mapping
+ * each consumer type to the producer types it may reference.
+ *
+ * The policy is only enforced when configured. References to temporary IDs that
+ * are not created by a producer in this run are left to the handlers.
+ */
+
+const { extractTemporaryIdReferences, getCreatedTemporaryId } = require("./temporary_id.cjs");
+
+/**
+ * Safe output types whose created items can be referenced by temporary ID.
+ * Must match safeOutputReferenceProducers in pkg/workflow/safe_output_references.go.
+ */
+const REFERENCE_PRODUCER_TYPES = new Set(["create_issue", "create_pull_request"]);
+
+/**
+ * Load the reference policy from the environment
+ * @returns {Object<string, string[]>|null} Map of consumer type to allowed producer types, or null when not configured
+ */
+function loadReferencePolicy() {
+  const raw = process.env.GH_AW_SAFE_OUTPUT_REFERENCES;
+  if (!raw) {
+    return null;
+  }
+
+  try {
+    const policy = JSON.parse(raw);
+    if (!policy || typeof policy !== "object" || Array.isArray(policy)) {
+      core.warning("GH_AW_SAFE_OUTPUT_REFERENCES is not an object, ignoring reference policy");
+      return null;
+    }
+    return policy;
+  } catch (error) {
+    core.warning(`Failed to parse GH_AW_SAFE_OUTPUT_REFERENCES, ignoring reference policy: ${error instanceof Error ? error.message : String(error)}`);
+    return null;
+  }
+}
+
+/**
+ * Find messages that reference items created in this run without being allowed to
+ *
+ * @param {Array<any>} messages - Safe output messages in order of appearance
+ * @param {Object<string, string[]>} policy - Map of consumer type to allowed producer types
+ * @returns {Map<number, string>} Map of message index to the reason it is blocked
+ */
+function findReferenceViolations(messages, policy) {
+  /** @type {Map<string, string>} */
+  const producerTypes = new Map();
+  for (const message of messages) {
+    if (!message || !REFERENCE_PRODUCER_TYPES.has(message.type)) {
+      continue;
+    }
+    const tempId = getCreatedTemporaryId(message);
+    if (tempId) {
+      producerTypes.set(tempId, message.type);
+    }
+  }
+
+  /** @type {Map<number, string>} */
+  const violations = new Map();
+  if (producerTypes.size === 0) {
+    return violations;
+  }
+
+  for (let i = 0; i < messages.length; i++) {
+    const message = messages[i];
+    if (!message || !message.type) {
+      continue;
+    }
+
+    const allowed = Array.isArray(policy[message.type]) ? policy[message.type] : [];
+    const ownTempId = getCreatedTemporaryId(message);
+    for (const tempId of extractTemporaryIdReferences(message)) {
+      if (tempId === ownTempId) {
+        continue;
+      }
+      const producerType = producerTypes.get(tempId);
+      if (producerType && !allowed.includes(producerType)) {
+        violations.set(i, `${message.type} is not allowed to reference ${producerType} output '${tempId}'. Add it to safe-outputs.references to allow this.`);
+        break;
+      }
+    }
+  }
+
+  return violations;
+}
+
+module.exports = {
+  REFERENCE_PRODUCER_TYPES,
+  loadReferencePolicy,
+  findReferenceViolations,
+};
@@ -0,0 +1,107 @@
+// @ts-check
+
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+import { loadReferencePolicy, findReferenceViolations } from "./safe_output_references.cjs";
+import { processMessages } from "./safe_output_handler_manager.cjs";
+
+describe("safe_output_references", () => {
+  beforeEach(() => {
+    global.core = {
+      info: vi.fn(),
+      debug: vi.fn(),
+      warning: vi.fn(),
+      error: vi.fn(),
+      setOutput: vi.fn(),
+      setFailed: vi.fn(),
+    };
+  });
+
+  afterEach(() => {
+    delete process.env.GH_AW_SAFE_OUTPUT_REFERENCES;
+  });
+
+  describe("loadReferencePolicy", () => {
+    it("should return null when no policy is configured", () => {
+      expect(loadReferencePolicy()).toBe(null);
+    });
+
+    it("should parse the policy from the environment", () => {
+      process.env.GH_AW_SAFE_OUTPUT_REFERENCES = JSON.stringify({ link_sub_issue: ["create_issue"] });
+
+      expect(loadReferencePolicy()).toEqual({ link_sub_issue: ["create_issue"] });
+    });
+
+    it("should warn and ignore invalid JSON", () => {
+      process.env.GH_AW_SAFE_OUTPUT_REFERENCES = "not json";
+
+      expect(loadReferencePolicy()).toBe(null);
+      expect(global.core.warning).toHaveBeenCalledWith(expect.stringContaining("Failed to parse GH_AW_SAFE_OUTPUT_REFERENCES"));
+    });
+  });
+
+  describe("findReferenceViolations", () => {
+    const messages = [
+      { type: "create_issue", temporary_id: "aw_story1", title: "Story", body: "Part of the epic" },
+      { type: "create_pull_request", temporary_id: "aw_pr1", title: "Fix", body: "Implements #aw_story1" },
+      { type: "link_sub_issue", parent_issue_number: 10, sub_issue_number: "aw_story1" },
+      { type: "add_comment", body: "Opened #aw_pr1 for #aw_story1" },
+      { type: "update_project", content_number: "aw_story1" },
+    ];
+
+    it("should allow references listed in the policy", () => {
+      const policy = {
+        create_pull_request: ["create_issue"],
+        link_sub_issue: ["create_issue"],
+        add_comment: ["create_issue", "create_pull_request"],
+        update_project: ["create_issue"],
+      };
+
+      expect(findReferenceViolations(messages, policy).size).toBe(0);
+    });
+
+    it("should block references to producers that are not listed", () => {
+      const policy = {
+        create_pull_request: ["create_issue"],
+        link_sub_issue: ["create_issue"],
+        add_comment: ["create_issue"],
+      };
+
+      const violations = findReferenceViolations(messages, policy);
+
+      expect(violations.size).toBe(2);
+      expect(violations.get(3)).toContain("add_comment is not allowed to reference create_pull_request output 'aw_pr1'");
+      expect(violations.get(4)).toContain("update_project is not allowed to reference create_issue output 'aw_story1'");
+    });
+
+    it("should ignore temporary IDs not created in this run and a message's own ID", () => {
+      const violations = findReferenceViolations(
+        [
+          { type: "create_issue", temporary_id: "aw_self1", title: "Self", body: "See #aw_self1 and #aw_other1" },
+          { type: "add_comment", body: "See #aw_other1" },
+        ],
+        {}
+      );
+
+      expect(violations.size).toBe(0);
+    });
+  });
+
+  describe("processMessages with blocked messages", () => {
+    it("should report blocked messages as failures without calling the handler", async () => {
+      const commentHandler = vi.fn().mockResolvedValue({ success: true });
+      const handlers = new Map([["add_comment", commentHandler]]);
+      const blockedMessages = new Map([[0, "add_comment is not allowed to reference create_pull_request output 'aw_pr1'"]]);
+
+      const result = await processMessages(handlers, [{ type: "add_comment", body: "See #aw_pr1" }, { type: "add_comment", body: "Done" }], { blockedMessages });
+
+      expect(commentHandler).toHaveBeenCalledTimes(1);
+      expect(result.results[0]).toEqual({
+        type: "add_comment",
+        messageIndex: 0,
+        success: false,
+        error: "add_comment is not allowed to reference create_pull_request output 'aw_pr1'",
+      });
+      expect(result.results[1].success).toBe(true);
+    });
+  });
+});
@@ -39,10 +39,14 @@ const HANDLER_MAP = {
   close_discussion: "./close_discussion.cjs",
   add_labels: "./add_labels.cjs",
   remove_labels: "./remove_labels.cjs",
+  manage_labels: "./manage_labels.cjs",
   update_issue: "./update_issue.cjs",
   update_discussion: "./update_discussion.cjs",
   link_sub_issue: "./link_sub_issue.cjs",
   update_release: "./update_release.cjs",
+  create_release: "./create_release.cjs",
+  create_tag: "./create_tag.cjs",
+  update_file: "./update_file.cjs",
   create_pull_request_review_comment: "./create_pr_review_comment.cjs",
   submit_pull_request_review: "./submit_pr_review.cjs",
   reply_to_pull_request_review_comment: "./reply_to_pr_review_comment.cjs",
@@ -51,13 +55,19 @@ const HANDLER_MAP = {
   push_to_pull_request_branch: "./push_to_pull_request_branch.cjs",
   update_pull_request: "./update_pull_request.cjs",
   close_pull_request: "./close_pull_request.cjs",
+  merge_pull_request: "./merge_pull_request.cjs",
   mark_pull_request_as_ready_for_review: "./mark_pull_request_as_ready_for_review.cjs",
   hide_comment: "./hide_comment.cjs",
+  lock_conversation: "./lock_conversation.cjs",
+  pin_issue: "./pin_issue.cjs",
+  transfer_issue: "./transfer_issue.cjs",
   add_reviewer: "./add_reviewer.cjs",
   assign_milestone: "./assign_milestone.cjs",
   assign_to_user: "./assign_to_user.cjs",
   unassign_from_user: "./unassign_from_user.cjs",
   create_code_scanning_alert: "./create_code_scanning_alert.cjs",
+  create_check_run: "./create_check_run.cjs",
+  create_commit_status: "./create_commit_status.cjs",
   autofix_code_scanning_alert: "./autofix_code_scanning_alert.cjs",
   dispatch_workflow: "./dispatch_workflow.cjs",
   create_missing_tool_issue: "./create_missing_tool_issue.cjs",
@@ -12,6 +12,7 @@ const { getBaseBranch } = require("./get_base_branch.cjs");
 const { generateGitPatch } = require("./generate_git_patch.cjs");
 const { enforceCommentLimits } = require("./comment_limit_helpers.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
+const { validateFilePath } = require("./update_file.cjs");
 
 /**
  * Create handlers for safe output tools
@@ -182,6 +183,96 @@ function createHandlers(server, appendSafeOutput, config = {}) {
     };
   };
 
+  /**
+   * Handler for create_release tool
+   * Copies the release assets into the release assets directory, which is uploaded with the
+   * agent artifacts so threat detection can inspect them before the release is created
+   */
+  const createReleaseHandler = args => {
+    const entry = { ...args, type: "create_release" };
+    const assets = Array.isArray(entry.assets) ? entry.assets : [];
+    if (assets.length === 0) {
+      return defaultHandler("create_release")(args);
+    }
+
+    const releaseConfig = config.create_release || {};
+    const maxSizeKB = releaseConfig.max_size_kb || 10240;
+    const allowedExts = releaseConfig.allowed_exts || [".zip", ".tar.gz", ".tgz", ".txt", ".md", ".json"];
+    const workspaceDir = path.resolve(process.env.GITHUB_WORKSPACE || process.cwd());
+    const assetsDir = "/tmp/gh-aw/safeoutputs/release-assets";
+
+    const assetNames = [];
+    for (const assetPath of assets) {
+      const absolutePath = path.resolve(String(assetPath));
+      if (!absolutePath.startsWith(workspaceDir) && !absolutePath.startsWith("/tmp")) {
+        throw new Error(`Release asset must be within workspace directory (${workspaceDir}) or /tmp directory. Provided path: ${assetPath}`);
+      }
+      if (!fs.existsSync(absolutePath) || !fs.statSync(absolutePath).isFile()) {
+        throw new Error(`Release asset not found: ${assetPath}`);
+      }
+
+      const fileName = path.basename(absolutePath);
+      if (!allowedExts.some(ext => fileName.toLowerCase().endsWith(ext.toLowerCase()))) {
+        throw new Error(`Release asset '${fileName}' has an extension that is not allowed. Allowed extensions: ${allowedExts.join(", ")}`);
+      }
+      const sizeKB = Math.ceil(fs.statSync(absolutePath).size / 1024);
+      if (sizeKB > maxSizeKB) {
+        throw new Error(`Release asset '${fileName}' size ${sizeKB} KB exceeds maximum allowed size ${maxSizeKB} KB`);
+      }
+      if (assetNames.includes(fileName)) {
+        throw new Error(`Duplicate release asset name: ${fileName}`);
+      }
+
+      fs.mkdirSync(assetsDir, { recursive: true });
+      fs.copyFileSync(absolutePath, path.join(assetsDir, fileName));
+      assetNames.push(fileName);
+      server.debug(`Copied release asset ${absolutePath} to ${assetsDir}/${fileName}`);
+    }
+
+    appendSafeOutput({ ...entry, assets: assetNames });
+    return {
+      content: [
+        {
+          type: "text",
+          text: JSON.stringify({ result: "success", assets: assetNames }),
+        },
+      ],
+    };
+  };
+
+  /**
+   * Handler for update_file tool
+   * Validates the path and content size up front so the agent gets immediate feedback,
+   * and records the content inline (bypassing the large-content file redirect)
+   */
+  const updateFileHandler = args => {
+    const entry = { ...args, type: "update_file" };
+    const fileConfig = config.update_file || {};
+    const maxSizeKB = fileConfig.max_size_kb || 512;
+
+    const pathResult = validateFilePath(String(entry.path || ""), fileConfig.allowed_paths || []);
+    if (!pathResult.valid) {
+      throw new Error(pathResult.error);
+    }
+    if (typeof entry.content !== "string") {
+      throw new Error("update_file requires 'content' as a string");
+    }
+    const sizeKB = Math.ceil(Buffer.byteLength(entry.content, "utf8") / 1024);
+    if (sizeKB > maxSizeKB) {
+      throw new Error(`File content size ${sizeKB} KB exceeds maximum allowed size ${maxSizeKB} KB`);
+    }
+
+    appendSafeOutput({ ...entry, path: pathResult.path });
+    return {
+      content: [
+        {
+          type: "text",
+          text: JSON.stringify({ result: "success", path: pathResult.path }),
+        },
+      ],
+    };
+  };
+
   /**
    * Handler for create_pull_request tool
    * Resolves the current branch if branch is not provided or is the base branch
@@ -402,6 +493,8 @@ function createHandlers(server, appendSafeOutput, config = {}) {
   return {
     defaultHandler,
     uploadAssetHandler,
+    createReleaseHandler,
+    updateFileHandler,
     createPullRequestHandler,
     pushToPullRequestBranchHandler,
     createProjectHandler,
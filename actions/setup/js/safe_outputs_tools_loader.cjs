@@ -56,6 +56,8 @@ function attachHandlers(tools, handlers) {
     create_pull_request: handlers.createPullRequestHandler,
     push_to_pull_request_branch: handlers.pushToPullRequestBranchHandler,
     upload_asset: handlers.uploadAssetHandler,
+    create_release: handlers.createReleaseHandler,
+    update_file: handlers.updateFileHandler,
     create_project: handlers.createProjectHandler,
     add_comment: handlers.addCommentHandler,
   };
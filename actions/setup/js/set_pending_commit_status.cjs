@@ -0,0 +1,38 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { PENDING_DESCRIPTION, getStatusContext, resolveStatusSha, renderTargetUrl } = require("./commit_status_helpers.cjs");
+
+/**
+ * Marks the triggering commit as pending before the agent runs so the commit status can be
+ * a required check. The safe_outputs job replaces it with the agent's result and the
+ * conclusion job resolves it if the agent never reports one.
+ * Failures only produce a warning so a status API error never blocks activation.
+ */
+async function main() {
+  const statusContext = getStatusContext(undefined);
+
+  try {
+    const sha = await resolveStatusSha();
+    if (!sha) {
+      core.warning("Could not resolve the commit to attach the pending status to, skipping");
+      return;
+    }
+
+    await github.rest.repos.createCommitStatus({
+      owner: context.repo.owner,
+      repo: context.repo.repo,
+      sha,
+      state: "pending",
+      context: statusContext,
+      description: PENDING_DESCRIPTION,
+      target_url: renderTargetUrl(process.env.GH_AW_COMMIT_STATUS_TARGET_URL, sha),
+    });
+    core.info(`Set pending commit status '${statusContext}' on ${sha}`);
+  } catch (error) {
+    core.warning(`Failed to set pending commit status: ${getErrorMessage(error)}`);
+  }
+}
+
+module.exports = { main };
@@ -76,6 +76,18 @@ async function main() {
     .replace(/{AGENT_OUTPUT_FILE}/g, agentOutputFileInfo)
     .replace(/{AGENT_PATCH_FILE}/g, patchFileInfo);
 
+  // List release assets staged by create_release so they are reviewed before being published
+  // /tmp/gh-aw/safeoutputs/release-assets/ becomes /tmp/gh-aw/threat-detection/safeoutputs/release-assets/
+  const releaseAssetsDir = path.join(threatDetectionDir, "safeoutputs", "release-assets");
+  if (fs.existsSync(releaseAssetsDir)) {
+    const assetFiles = fs.readdirSync(releaseAssetsDir).map(name => path.join(releaseAssetsDir, name));
+    if (assetFiles.length > 0) {
+      promptContent +=
+        "\n\n## Release Assets\n\nThe agent requested these files to be attached to a release. Inspect them for malicious content (e.g. embedded secrets, executables or backdoored scripts):\n\n" +
+        assetFiles.map(file => "- " + file + " (" + fs.statSync(file).size + " bytes)").join("\n");
+    }
+  }
+
   // Append custom prompt instructions if provided
   const customPrompt = process.env.CUSTOM_PROMPT;
   if (customPrompt) {
@@ -0,0 +1,138 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { resolveTargetRepoConfig, resolveAndValidateRepo, parseRepoSlug } = require("./repo_helpers.cjs");
+const { resolveModerationTarget, fetchModerationTarget } = require("./moderation_helpers.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "transfer_issue";
+
+/**
+ * Main handler factory for transfer_issue
+ * Returns a message handler function that processes individual transfer_issue messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  const maxCount = config.max || 1;
+  const requiredLabels = config.required_labels || [];
+  const requiredTitlePrefix = config.required_title_prefix || "";
+  const allowedDestinations = (config.allowed_destinations || []).map(repo => String(repo).toLowerCase());
+  const { defaultTargetRepo, allowedRepos } = resolveTargetRepoConfig(config);
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Transfer issue configuration: max=${maxCount}, target=${config.target || "triggering"}`);
+  core.info(`Allowed destinations: ${allowedDestinations.join(", ")}`);
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single transfer_issue message
+   * @param {Object} message - The transfer_issue message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleTransferIssue(message, resolvedTemporaryIds) {
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+
+    const destination = String(item.destination || "").trim();
+    const destinationParts = parseRepoSlug(destination);
+    if (!destinationParts) {
+      return { success: false, error: `Invalid destination repository '${destination}' (expected 'owner/repo')` };
+    }
+    if (!allowedDestinations.includes(destination.toLowerCase())) {
+      core.warning(`Destination '${destination}' is not allowed`);
+      return { success: false, error: `Destination '${destination}' is not in allowed-destinations (${allowedDestinations.join(", ")})` };
+    }
+
+    const repoResult = resolveAndValidateRepo(item, defaultTargetRepo, allowedRepos, "issue");
+    if (!repoResult.success) {
+      core.warning(`Skipping ${HANDLER_TYPE}: ${repoResult.error}`);
+      return { success: false, error: repoResult.error };
+    }
+    const { repo: itemRepo, repoParts } = repoResult;
+    if (itemRepo.toLowerCase() === destination.toLowerCase()) {
+      return { success: false, error: `Issue is already in ${destination}` };
+    }
+
+    const targetResult = resolveModerationTarget(item, config.target, HANDLER_TYPE, false);
+    if (!targetResult.success) {
+      core.warning(targetResult.error);
+      return { success: false, error: targetResult.error };
+    }
+    const issueNumber = targetResult.number;
+
+    try {
+      const targetCheck = await fetchModerationTarget(repoParts, issueNumber, { requiredLabels, requiredTitlePrefix });
+      if (!targetCheck.success) {
+        core.warning(targetCheck.error);
+        return { success: false, error: targetCheck.error };
+      }
+      const issue = targetCheck.issue;
+      if (issue.pull_request) {
+        return { success: false, error: `#${issueNumber} is a pull request; only issues can be transferred` };
+      }
+
+      if (isStaged) {
+        core.info(`Staged mode: Would transfer issue #${issueNumber} from ${itemRepo} to ${destination}`);
+        return {
+          success: true,
+          staged: true,
+          previewInfo: { number: issueNumber, repo: itemRepo, destination, title: issue.title },
+        };
+      }
+
+      // Transfers are only available through GraphQL and need the destination repository node ID
+      const { repository } = await github.graphql(
+        `query($owner: String!, $name: String!) {
+          repository(owner: $owner, name: $name) { id }
+        }`,
+        { owner: destinationParts.owner, name: destinationParts.repo }
+      );
+      const { transferIssue } = await github.graphql(
+        `mutation($issueId: ID!, $repositoryId: ID!) {
+          transferIssue(input: { issueId: $issueId, repositoryId: $repositoryId, createLabelsIfMissing: true }) {
+            issue { number url }
+          }
+        }`,
+        { issueId: issue.node_id, repositoryId: repository.id }
+      );
+
+      core.info(`✓ Transferred issue #${issueNumber} from ${itemRepo} to ${destination}: ${transferIssue.issue.url}`);
+      return {
+        success: true,
+        number: transferIssue.issue.number,
+        url: transferIssue.issue.url,
+        repo: destination,
+        previousNumber: issueNumber,
+        previousRepo: itemRepo,
+      };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to transfer issue #${issueNumber} to ${destination}: ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main };
@@ -0,0 +1,128 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    issues: {
+      get: vi.fn(),
+    },
+  },
+  graphql: vi.fn(),
+};
+
+const mockContext = {
+  eventName: "issues",
+  runId: 4242,
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+  payload: {
+    issue: { number: 11 },
+  },
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+const baseIssue = {
+  number: 11,
+  node_id: "I_kwDOissue11",
+  title: "[docs] Typo in the install guide",
+  labels: [{ name: "documentation" }],
+  html_url: "https://github.com/testowner/testrepo/issues/11",
+};
+
+describe("transfer_issue (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    mockGithub.rest.issues.get.mockResolvedValue({ data: baseIssue });
+    mockGithub.graphql.mockImplementation(async query => {
+      if (query.includes("transferIssue")) {
+        return { transferIssue: { issue: { number: 42, url: "https://github.com/testowner/docs/issues/42" } } };
+      }
+      return { repository: { id: "R_docs" } };
+    });
+  });
+
+  it("should transfer the triggering issue to an allowed destination", async () => {
+    const { main } = require("./transfer_issue.cjs");
+    const handler = await main({ allowed_destinations: ["testowner/docs"] });
+
+    const result = await handler({ type: "transfer_issue", destination: "TestOwner/docs" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.number).toBe(42);
+    expect(result.previousNumber).toBe(11);
+    expect(mockGithub.graphql).toHaveBeenCalledWith(expect.stringContaining("repository(owner"), { owner: "TestOwner", name: "docs" });
+    expect(mockGithub.graphql).toHaveBeenCalledWith(expect.stringContaining("transferIssue"), { issueId: "I_kwDOissue11", repositoryId: "R_docs" });
+  });
+
+  it("should reject destinations outside allowed_destinations", async () => {
+    const { main } = require("./transfer_issue.cjs");
+    const handler = await main({ allowed_destinations: ["testowner/docs"] });
+
+    const result = await handler({ type: "transfer_issue", destination: "attacker/inbox" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("not in allowed-destinations");
+    expect(mockGithub.graphql).not.toHaveBeenCalled();
+  });
+
+  it("should reject transfers into the source repository", async () => {
+    const { main } = require("./transfer_issue.cjs");
+    const handler = await main({ allowed_destinations: ["testowner/testrepo"] });
+
+    const result = await handler({ type: "transfer_issue", destination: "testowner/testrepo" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("already in");
+  });
+
+  it("should enforce the required title prefix", async () => {
+    const { main } = require("./transfer_issue.cjs");
+    const handler = await main({ allowed_destinations: ["testowner/docs"], required_title_prefix: "[api]" });
+
+    const result = await handler({ type: "transfer_issue", destination: "testowner/docs" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain('title does not start with "[api]"');
+    expect(mockGithub.graphql).not.toHaveBeenCalled();
+  });
+
+  it("should refuse to transfer pull requests", async () => {
+    mockGithub.rest.issues.get.mockResolvedValue({ data: { ...baseIssue, pull_request: { url: "..." } } });
+    const { main } = require("./transfer_issue.cjs");
+    const handler = await main({ allowed_destinations: ["testowner/docs"] });
+
+    const result = await handler({ type: "transfer_issue", destination: "testowner/docs" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("only issues can be transferred");
+  });
+
+  it("should preview without transferring in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./transfer_issue.cjs");
+    const handler = await main({ allowed_destinations: ["testowner/docs"] });
+
+    const result = await handler({ type: "transfer_issue", destination: "testowner/docs" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.graphql).not.toHaveBeenCalled();
+  });
+});
@@ -89,6 +89,19 @@ interface ClosePullRequestItem extends BaseSafeOutputItem {
   pull_request_number?: number | string;
 }
 
+/**
+ * JSONL item for merging a pull request
+ */
+interface MergePullRequestItem extends BaseSafeOutputItem {
+  type: "merge_pull_request";
+  /** Pull request number to merge (optional - uses triggering PR if not provided) */
+  pull_request_number?: number | string;
+  /** Optional merge commit title */
+  commit_title?: string;
+  /** Optional merge commit message */
+  commit_message?: string;
+}
+
 /**
  * JSONL item for marking a draft pull request as ready for review
  */
@@ -162,6 +175,58 @@ interface CreateCodeScanningAlertItem extends BaseSafeOutputItem {
   ruleIdSuffix?: string;
 }
 
+/**
+ * Line-level annotation of a check run
+ */
+interface CheckRunAnnotation {
+  /** File path relative to the repository root */
+  path: string;
+  /** First line of the annotated range */
+  start_line: number | string;
+  /** Optional last line of the annotated range */
+  end_line?: number | string;
+  /** Optional first column (single-line annotations only) */
+  start_column?: number | string;
+  /** Optional last column (single-line annotations only) */
+  end_column?: number | string;
+  /** Annotation severity (default: "warning") */
+  annotation_level?: "notice" | "warning" | "failure";
+  /** Description of the finding */
+  message: string;
+  /** Optional short title */
+  title?: string;
+}
+
+/**
+ * JSONL item for publishing a check run on the pull request head commit
+ */
+interface CreateCheckRunItem extends BaseSafeOutputItem {
+  type: "create_check_run";
+  /** Conclusion of the check run */
+  conclusion: "success" | "failure" | "neutral" | "cancelled" | "skipped" | "timed_out" | "action_required";
+  /** Title of the check run output */
+  title: string;
+  /** Markdown summary */
+  summary: string;
+  /** Optional detailed Markdown results */
+  text?: string;
+  /** Optional line-level annotations */
+  annotations?: CheckRunAnnotation[];
+  /** Optional pull request number (defaults to the triggering pull request) */
+  pull_request_number?: number | string;
+}
+
+/**
+ * JSONL item for setting a commit status on the triggering commit
+ */
+interface CreateCommitStatusItem extends BaseSafeOutputItem {
+  type: "create_commit_status";
+  /** State of the commit status */
+  state: "error" | "failure" | "pending" | "success";
+  /** Optional short description (max 140 characters) */
+  description?: string;
+}
+
 /**
  * JSONL item for adding labels to an issue or PR
  */
@@ -184,6 +249,25 @@ interface RemoveLabelsItem extends BaseSafeOutputItem {
   item_number?: number;
 }
 
+/**
+ * JSONL item for creating, editing or deleting a repository label
+ */
+interface ManageLabelsItem extends BaseSafeOutputItem {
+  type: "manage_labels";
+  /** Label operation to perform */
+  operation: "create" | "update" | "delete";
+  /** Current label name */
+  name: string;
+  /** New label name when renaming */
+  new_name?: string;
+  /** Label color as a 6-digit hex code */
+  color?: string;
+  /** Label description */
+  description?: string;
+  /** Target repository in format "owner/repo" */
+  repo?: string;
+}
+
 /**
  * JSONL item for adding reviewers to a pull request
  */
@@ -295,6 +379,53 @@ interface UpdateReleaseItem extends BaseSafeOutputItem {
   body: string;
 }
 
+/**
+ * JSONL item for creating a release
+ */
+interface CreateReleaseItem extends BaseSafeOutputItem {
+  type: "create_release";
+  /** Tag name for the release */
+  tag: string;
+  /** Release title (defaults to the tag name) */
+  name?: string;
+  /** Release notes in Markdown */
+  body: string;
+  /** Commit SHA to create the tag at when it does not exist */
+  target_commitish?: string;
+  /** Whether to create the release as a draft (ignored when drafts are forced) */
+  draft?: boolean;
+  /** Whether to mark the release as a pre-release */
+  prerelease?: boolean;
+  /** File names of assets in the release assets directory */
+  assets?: string[];
+}
+
+/**
+ * JSONL item for creating a lightweight tag
+ */
+interface CreateTagItem extends BaseSafeOutputItem {
+  type: "create_tag";
+  /** Tag name to create */
+  tag: string;
+  /** Commit SHA to tag (defaults to the triggering commit) */
+  sha?: string;
+}
+
+/**
+ * JSONL item for committing a single file
+ */
+interface UpdateFileItem extends BaseSafeOutputItem {
+  type: "update_file";
+  /** File path relative to the repository root */
+  path: string;
+  /** Complete new content of the file */
+  content: string;
+  /** Branch to commit to (defaults to the configured branch) */
+  branch?: string;
+  /** Short description of the change used in the commit message */
+  summary?: string;
+}
+
 /**
  * JSONL item for no-op (logging only)
  */
@@ -326,6 +457,43 @@ interface HideCommentItem extends BaseSafeOutputItem {
   reason?: "SPAM" | "ABUSE" | "OFF_TOPIC" | "OUTDATED" | "RESOLVED";
 }
 
+/**
+ * JSONL item for locking an issue or pull request conversation
+ */
+interface LockConversationItem extends BaseSafeOutputItem {
+  type: "lock_conversation";
+  /** Issue or pull request number (defaults to the triggering item) */
+  item_number?: number | string;
+  /** Optional lock reason */
+  reason?: "off-topic" | "too heated" | "resolved" | "spam";
+  /** Target repository in format "owner/repo" */
+  repo?: string;
+}
+
+/**
+ * JSONL item for pinning an issue
+ */
+interface PinIssueItem extends BaseSafeOutputItem {
+  type: "pin_issue";
+  /** Issue number (defaults to the triggering issue) */
+  issue_number?: number | string;
+  /** Target repository in format "owner/repo" */
+  repo?: string;
+}
+
+/**
+ * JSONL item for transferring an issue to another repository
+ */
+interface TransferIssueItem extends BaseSafeOutputItem {
+  type: "transfer_issue";
+  /** Destination repository in format "owner/repo" */
+  destination: string;
+  /** Issue number (defaults to the triggering issue) */
+  issue_number?: number | string;
+  /** Source repository in format "owner/repo" */
+  repo?: string;
+}
+
 /**
  * JSONL item for replying to a pull request review comment
  */
@@ -387,12 +555,16 @@ type SafeOutputItem =
   | CloseIssueItem
   | ClosePullRequestItem
   | MarkPullRequestAsReadyForReviewItem
+  | MergePullRequestItem
   | AddCommentItem
   | CreatePullRequestItem
   | CreatePullRequestReviewCommentItem
   | CreateCodeScanningAlertItem
+  | CreateCheckRunItem
+  | CreateCommitStatusItem
   | AddLabelsItem
   | RemoveLabelsItem
+  | ManageLabelsItem
   | AddReviewerItem
   | UpdateIssueItem
   | UpdatePullRequestItem
@@ -402,9 +574,15 @@ type SafeOutputItem =
   | AssignMilestoneItem
   | AssignToAgentItem
   | UpdateReleaseItem
+  | CreateReleaseItem
+  | CreateTagItem
+  | UpdateFileItem
   | NoOpItem
   | LinkSubIssueItem
   | HideCommentItem
+  | LockConversationItem
+  | PinIssueItem
+  | TransferIssueItem
   | ReplyToPullRequestReviewCommentItem
   | CreateProjectItem
   | AutofixCodeScanningAlertItem
@@ -428,12 +606,17 @@ export {
   CloseIssueItem,
   ClosePullRequestItem,
   MarkPullRequestAsReadyForReviewItem,
+  MergePullRequestItem,
   AddCommentItem,
   CreatePullRequestItem,
   CreatePullRequestReviewCommentItem,
   CreateCodeScanningAlertItem,
+  CheckRunAnnotation,
+  CreateCheckRunItem,
+  CreateCommitStatusItem,
   AddLabelsItem,
   RemoveLabelsItem,
+  ManageLabelsItem,
   AddReviewerItem,
   UpdateIssueItem,
   UpdatePullRequestItem,
@@ -443,9 +626,15 @@ export {
   AssignMilestoneItem,
   AssignToAgentItem,
   UpdateReleaseItem,
+  CreateReleaseItem,
+  CreateTagItem,
+  UpdateFileItem,
   NoOpItem,
   LinkSubIssueItem,
   HideCommentItem,
+  LockConversationItem,
+  PinIssueItem,
+  TransferIssueItem,
   ReplyToPullRequestReviewCommentItem,
   AutofixCodeScanningAlertItem,
   ResolvePullRequestReviewThreadItem,
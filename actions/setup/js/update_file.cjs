@@ -0,0 +1,235 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { globPatternToRegex } = require("./glob_pattern_helpers.cjs");
+const { renderTemplate } = require("./messages_core.cjs");
+
+/** @type {string} Safe output type handled by this module */
+const HANDLER_TYPE = "update_file";
+
+/** Default commit message template */
+const DEFAULT_COMMIT_MESSAGE = "Update {path}";
+
+/** Paths that are never written, regardless of allowed-paths */
+const PROTECTED_PATH_PREFIXES = [".git/", ".github/workflows/"];
+
+/**
+ * Normalizes a repository-relative file path and checks it against the allowed path globs
+ * @param {string} filePath - Path requested by the agent
+ * @param {string[]} allowedPaths - Glob patterns of writable files
+ * @returns {{valid: true, path: string} | {valid: false, error: string}}
+ */
+function validateFilePath(filePath, allowedPaths) {
+  const normalized = filePath.trim().replace(/^\.\//, "");
+  if (!normalized || normalized.endsWith("/")) {
+    return { valid: false, error: `Invalid file path: '${filePath}'` };
+  }
+  if (normalized.startsWith("/") || normalized.includes("\\") || normalized.split("/").some(segment => segment === ".." || segment === "." || segment === "")) {
+    return { valid: false, error: `File path must be relative to the repository root without '.', '..' or empty segments: '${filePath}'` };
+  }
+  if (PROTECTED_PATH_PREFIXES.some(prefix => normalized.startsWith(prefix))) {
+    return { valid: false, error: `File path '${normalized}' is protected and cannot be updated` };
+  }
+  if (!allowedPaths || allowedPaths.length === 0) {
+    return { valid: false, error: "No allowed-paths are configured for update_file" };
+  }
+  if (!allowedPaths.some(pattern => globPatternToRegex(pattern).test(normalized))) {
+    return { valid: false, error: `File path '${normalized}' does not match allowed paths (${allowedPaths.join(", ")})` };
+  }
+  return { valid: true, path: normalized };
+}
+
+/**
+ * Returns the repository default branch
+ * @returns {Promise<string>}
+ */
+async function getDefaultBranch() {
+  const fromPayload = context.payload?.repository?.default_branch;
+  if (fromPayload) {
+    return fromPayload;
+  }
+  const { data: repository } = await github.rest.repos.get({ owner: context.repo.owner, repo: context.repo.repo });
+  return repository.default_branch;
+}
+
+/**
+ * Returns the SHA a branch points to, or null if the branch does not exist
+ * @param {string} branch - Branch name
+ * @returns {Promise<string|null>}
+ */
+async function getBranchSha(branch) {
+  try {
+    const { data: ref } = await github.rest.git.getRef({ owner: context.repo.owner, repo: context.repo.repo, ref: `heads/${branch}` });
+    return ref.object.sha;
+  } catch (error) {
+    if (/** @type {any} */ (error)?.status === 404) {
+      return null;
+    }
+    throw error;
+  }
+}
+
+/**
+ * Returns the current blob SHA and decoded content of a file, or null if it does not exist
+ * @param {string} filePath - Repository-relative path
+ * @param {string} branch - Branch name
+ * @returns {Promise<{sha: string, content: string}|null>}
+ */
+async function getExistingFile(filePath, branch) {
+  try {
+    const { data } = await github.rest.repos.getContent({ owner: context.repo.owner, repo: context.repo.repo, path: filePath, ref: branch });
+    if (Array.isArray(data) || data.type !== "file") {
+      throw new Error(`'${filePath}' is not a file on branch '${branch}'`);
+    }
+    return { sha: data.sha, content: Buffer.from(data.content || "", "base64").toString("utf8") };
+  } catch (error) {
+    if (/** @type {any} */ (error)?.status === 404) {
+      return null;
+    }
+    throw error;
+  }
+}
+
+/**
+ * Main handler factory for update_file
+ * Returns a message handler function that processes individual update_file messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  const maxCount = config.max || 1;
+  const allowedPaths = config.allowed_paths || [];
+  const allowedBranches = config.allowed_branches || [];
+  const maxSizeKB = config.max_size_kb || 512;
+  const commitMessageTemplate = config.commit_message || DEFAULT_COMMIT_MESSAGE;
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Update file configuration: max=${maxCount}, max_size_kb=${maxSizeKB}`);
+  core.info(`Allowed paths: ${allowedPaths.join(", ")}`);
+  if (allowedBranches.length > 0) {
+    core.info(`Allowed branches: ${allowedBranches.join(", ")}`);
+  }
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single update_file message
+   * @param {Object} message - The update_file message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number}
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleUpdateFile(message, resolvedTemporaryIds) {
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping ${HANDLER_TYPE}: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const item = /** @type {any} */ message;
+
+    const pathResult = validateFilePath(String(item.path || ""), allowedPaths);
+    if (!pathResult.valid) {
+      core.warning(pathResult.error);
+      return { success: false, error: pathResult.error };
+    }
+    const filePath = pathResult.path;
+
+    if (typeof item.content !== "string") {
+      return { success: false, error: "update_file requires 'content' as a string" };
+    }
+    const sizeKB = Math.ceil(Buffer.byteLength(item.content, "utf8") / 1024);
+    if (sizeKB > maxSizeKB) {
+      return { success: false, error: `File content size ${sizeKB} KB exceeds maximum allowed size ${maxSizeKB} KB` };
+    }
+
+    try {
+      const defaultBranch = await getDefaultBranch();
+      const configuredBranch = config.branch || defaultBranch;
+      const branch = item.branch ? String(item.branch).trim() : configuredBranch;
+
+      // Without allowed-branches, only the configured branch may be written
+      const branchAllowed = allowedBranches.length > 0 ? allowedBranches.some(pattern => globPatternToRegex(pattern).test(branch)) : branch === configuredBranch;
+      if (!branchAllowed) {
+        const allowed = allowedBranches.length > 0 ? allowedBranches.join(", ") : configuredBranch;
+        core.warning(`Branch '${branch}' is not allowed`);
+        return { success: false, error: `Branch '${branch}' is not allowed (allowed: ${allowed})` };
+      }
+
+      const runUrl = `${context.serverUrl}/${context.repo.owner}/${context.repo.repo}/actions/runs/${context.runId}`;
+      const commitMessage = renderTemplate(commitMessageTemplate, {
+        path: filePath,
+        branch,
+        summary: item.summary || "",
+        workflow_name: process.env.GH_AW_WORKFLOW_NAME || "",
+        run_url: runUrl,
+      }).trim();
+
+      if (isStaged) {
+        core.info(`Staged mode: Would commit ${filePath} (${sizeKB} KB) to '${branch}' with message: ${commitMessage}`);
+        return {
+          success: true,
+          staged: true,
+          previewInfo: { path: filePath, branch, commitMessage },
+        };
+      }
+
+      // Create the branch from the default branch when it does not exist yet
+      const branchSha = await getBranchSha(branch);
+      if (!branchSha) {
+        const baseSha = await getBranchSha(defaultBranch);
+        if (!baseSha) {
+          return { success: false, error: `Default branch '${defaultBranch}' not found` };
+        }
+        core.info(`Creating branch '${branch}' from '${defaultBranch}'`);
+        await github.rest.git.createRef({ owner: context.repo.owner, repo: context.repo.repo, ref: `refs/heads/${branch}`, sha: baseSha });
+      }
+
+      const existing = await getExistingFile(filePath, branch);
+      if (existing && existing.content === item.content) {
+        core.info(`${filePath} on '${branch}' is already up to date`);
+        return { success: true, path: filePath, branch, unchanged: true };
+      }
+
+      core.info(`Committing ${filePath} to '${branch}'`);
+      const { data: result } = await github.rest.repos.createOrUpdateFileContents({
+        owner: context.repo.owner,
+        repo: context.repo.repo,
+        path: filePath,
+        branch,
+        message: commitMessage,
+        content: Buffer.from(item.content, "utf8").toString("base64"),
+        ...(existing ? { sha: existing.sha } : {}),
+      });
+
+      core.info(`✓ Committed ${filePath}: ${result.commit.sha}`);
+      return {
+        success: true,
+        path: filePath,
+        branch,
+        sha: result.commit.sha,
+        url: result.commit.html_url,
+        created: !existing,
+      };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to update ${filePath}: ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main, validateFilePath };
@@ -0,0 +1,182 @@
+import { describe, it, expect, beforeEach, vi } from "vitest";
+
+// Mock the global objects that GitHub Actions provides
+const mockCore = {
+  debug: vi.fn(),
+  info: vi.fn(),
+  warning: vi.fn(),
+  error: vi.fn(),
+  setFailed: vi.fn(),
+  setOutput: vi.fn(),
+};
+
+const mockGithub = {
+  rest: {
+    repos: {
+      get: vi.fn(),
+      getContent: vi.fn(),
+      createOrUpdateFileContents: vi.fn(),
+    },
+    git: {
+      getRef: vi.fn(),
+      createRef: vi.fn(),
+    },
+  },
+};
+
+const mockContext = {
+  eventName: "workflow_dispatch",
+  runId: 4242,
+  serverUrl: "https://github.com",
+  repo: {
+    owner: "testowner",
+    repo: "testrepo",
+  },
+  payload: {
+    repository: { default_branch: "main" },
+  },
+};
+
+// Set up global mocks before importing the module
+global.core = mockCore;
+global.github = mockGithub;
+global.context = mockContext;
+
+/**
+ * Builds a 404 error like the one Octokit throws for missing resources
+ * @returns {Error & {status: number}}
+ */
+function notFound() {
+  return Object.assign(new Error("Not Found"), { status: 404 });
+}
+
+describe("update_file (Handler Factory Architecture)", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    process.env.GH_AW_WORKFLOW_NAME = "Status page";
+    mockGithub.rest.git.getRef.mockResolvedValue({ data: { object: { sha: "branchsha" } } });
+    mockGithub.rest.git.createRef.mockResolvedValue({ data: {} });
+    mockGithub.rest.repos.getContent.mockRejectedValue(notFound());
+    mockGithub.rest.repos.createOrUpdateFileContents.mockResolvedValue({
+      data: { commit: { sha: "commitsha", html_url: "https://github.com/testowner/testrepo/commit/commitsha" } },
+    });
+  });
+
+  it("should commit a new file to the default branch", async () => {
+    const { main } = require("./update_file.cjs");
+    const handler = await main({ allowed_paths: ["STATUS.md"] });
+
+    const result = await handler({ type: "update_file", path: "STATUS.md", content: "# Status\n" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.created).toBe(true);
+    expect(result.sha).toBe("commitsha");
+    expect(mockGithub.rest.repos.createOrUpdateFileContents).toHaveBeenCalledWith({
+      owner: "testowner",
+      repo: "testrepo",
+      path: "STATUS.md",
+      branch: "main",
+      message: "Update STATUS.md",
+      content: Buffer.from("# Status\n").toString("base64"),
+    });
+  });
+
+  it("should pass the existing blob SHA and render the commit message template", async () => {
+    mockGithub.rest.repos.getContent.mockResolvedValue({ data: { type: "file", sha: "blobsha", content: Buffer.from("old").toString("base64") } });
+    const { main } = require("./update_file.cjs");
+    const handler = await main({ allowed_paths: ["dashboards/**/*.json"], branch: "status", commit_message: "chore({workflow_name}): {summary} [{path}]" });
+
+    const result = await handler({ type: "update_file", path: "./dashboards/ci/summary.json", content: "{}", summary: "refresh" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.created).toBe(false);
+    expect(mockGithub.rest.repos.createOrUpdateFileContents).toHaveBeenCalledWith(
+      expect.objectContaining({ path: "dashboards/ci/summary.json", branch: "status", sha: "blobsha", message: "chore(Status page): refresh [dashboards/ci/summary.json]" })
+    );
+  });
+
+  it("should skip the commit when the content is unchanged", async () => {
+    mockGithub.rest.repos.getContent.mockResolvedValue({ data: { type: "file", sha: "blobsha", content: Buffer.from("same").toString("base64") } });
+    const { main } = require("./update_file.cjs");
+    const handler = await main({ allowed_paths: ["STATUS.md"] });
+
+    const result = await handler({ type: "update_file", path: "STATUS.md", content: "same" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.unchanged).toBe(true);
+    expect(mockGithub.rest.repos.createOrUpdateFileContents).not.toHaveBeenCalled();
+  });
+
+  it("should reject paths outside allowed-paths and protected paths", async () => {
+    const { validateFilePath } = require("./update_file.cjs");
+
+    expect(validateFilePath("README.md", ["STATUS.md"]).valid).toBe(false);
+    expect(validateFilePath("docs/../STATUS.md", ["**"]).valid).toBe(false);
+    expect(validateFilePath("/STATUS.md", ["**"]).valid).toBe(false);
+    expect(validateFilePath(".github/workflows/ci.yml", ["**"]).valid).toBe(false);
+    expect(validateFilePath("docs/a/b.md", ["docs/*.md"]).valid).toBe(false);
+    expect(validateFilePath("docs/b.md", ["docs/*.md"])).toEqual({ valid: true, path: "docs/b.md" });
+  });
+
+  it("should reject branches that are not allowed", async () => {
+    const { main } = require("./update_file.cjs");
+    const handler = await main({ allowed_paths: ["STATUS.md"], branch: "status" });
+
+    const result = await handler({ type: "update_file", path: "STATUS.md", content: "x", branch: "main" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Branch 'main' is not allowed");
+    expect(mockGithub.rest.repos.createOrUpdateFileContents).not.toHaveBeenCalled();
+  });
+
+  it("should create a missing branch matching allowed-branches from the default branch", async () => {
+    mockGithub.rest.git.getRef.mockImplementation(async ({ ref }) => {
+      if (ref === "heads/main") {
+        return { data: { object: { sha: "mainsha" } } };
+      }
+      throw notFound();
+    });
+    const { main } = require("./update_file.cjs");
+    const handler = await main({ allowed_paths: ["STATUS.md"], allowed_branches: ["status-*"] });
+
+    const result = await handler({ type: "update_file", path: "STATUS.md", content: "x", branch: "status-weekly" }, {});
+
+    expect(result.success).toBe(true);
+    expect(mockGithub.rest.git.createRef).toHaveBeenCalledWith({ owner: "testowner", repo: "testrepo", ref: "refs/heads/status-weekly", sha: "mainsha" });
+  });
+
+  it("should enforce the size limit", async () => {
+    const { main } = require("./update_file.cjs");
+    const handler = await main({ allowed_paths: ["STATUS.md"], max_size_kb: 1 });
+
+    const result = await handler({ type: "update_file", path: "STATUS.md", content: "x".repeat(2048) }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("exceeds maximum allowed size 1 KB");
+  });
+
+  it("should enforce the max count", async () => {
+    const { main } = require("./update_file.cjs");
+    const handler = await main({ allowed_paths: ["STATUS.md"], max: 1 });
+
+    await handler({ type: "update_file", path: "STATUS.md", content: "a" }, {});
+    const result = await handler({ type: "update_file", path: "STATUS.md", content: "b" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Max count of 1 reached");
+  });
+
+  it("should preview without committing in staged mode", async () => {
+    process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+    const { main } = require("./update_file.cjs");
+    const handler = await main({ allowed_paths: ["STATUS.md"] });
+
+    const result = await handler({ type: "update_file", path: "STATUS.md", content: "x" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.staged).toBe(true);
+    expect(mockGithub.rest.repos.createOrUpdateFileContents).not.toHaveBeenCalled();
+    expect(mockGithub.rest.git.createRef).not.toHaveBeenCalled();
+  });
+});
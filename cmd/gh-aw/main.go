@@ -231,6 +231,28 @@ The --dependabot flag generates dependency manifests when dependencies are detec
   - Cannot be used with specific workflow files or custom --dir
   - Only processes workflows in the default .github/workflows directory
 
+Compilation is incremental: workflows whose inputs (source, imports, compiler version,
+options and action pins) are unchanged since the last compile are skipped, using the
+content-addressed cache in .github/aw/compile-cache.json. Use --no-cache to recompile
+everything. The --check flag verifies that every lock file is up to date without
+writing anything, and exits with an error if any lock file is stale.
+
+The --lint flag runs the built-in security linter on the generated lock files. It checks
+for untrusted expressions in scripts, excessive permissions, unpinned actions,
+pull_request_target checkouts of pull request heads, secrets exposed to the agent and
+artifact poisoning, without requiring Docker. With --strict, any finding fails the compile.
+
+The --sarif flag writes every diagnostic of the run to a SARIF 2.1.0 log: compile and
+schema errors, strict mode, expression safety and template injection findings, markdown
+security scanner findings, and the results of --lint, --actionlint and --zizmor. Upload it
+with github/codeql-action/upload-sarif to show the findings in GitHub code scanning.
+
+Every compiler warning has a rule ID, shown in brackets before the message. Rules can be
+turned off or promoted to errors in .github/aw/lint.yml, and a workflow can accept a
+warning with a '# gh-aw-ignore: <rule-id> <reason>' comment in its frontmatter. Suppressed
+warnings are listed in the --json output. The --fail-on-warnings flag fails the compile
+when any warning remains.
+
 Examples:
   ` + string(constants.CLIExtensionPrefix) + ` compile                    # Compile all Markdown files
   ` + string(constants.CLIExtensionPrefix) + ` compile ci-doctor    # Compile a specific workflow
@@ -240,7 +262,13 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` compile --watch ci-doctor     # Watch and auto-compile
   ` + string(constants.CLIExtensionPrefix) + ` compile --trial --logical-repo owner/repo  # Compile for trial mode
   ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot        # Generate Dependabot manifests
-  ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml`,
+  ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml
+  ` + string(constants.CLIExtensionPrefix) + ` compile --check             # Fail if any lock file is out of date
+  ` + string(constants.CLIExtensionPrefix) + ` compile --no-cache          # Recompile all workflows, ignoring the cache
+  ` + string(constants.CLIExtensionPrefix) + ` compile --jobs 8            # Compile up to 8 workflows concurrently
+  ` + string(constants.CLIExtensionPrefix) + ` compile --lint              # Run the built-in security linter on lock files
+  ` + string(constants.CLIExtensionPrefix) + ` compile --lint --sarif results.sarif  # Write all diagnostics as SARIF
+  ` + string(constants.CLIExtensionPrefix) + ` compile --fail-on-warnings  # Fail if any warning is not suppressed`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		engineOverride, _ := cmd.Flags().GetString("engine")
 		actionMode, _ := cmd.Flags().GetString("action-mode")
@@ -260,11 +288,17 @@ Examples:
 		forceRefreshActionPins, _ := cmd.Flags().GetBool("force-refresh-action-pins")
 		zizmor, _ := cmd.Flags().GetBool("zizmor")
 		poutine, _ := cmd.Flags().GetBool("poutine")
+		lint, _ := cmd.Flags().GetBool("lint")
+		sarifFile, _ := cmd.Flags().GetString("sarif")
+		failOnWarnings, _ := cmd.Flags().GetBool("fail-on-warnings")
 		actionlint, _ := cmd.Flags().GetBool("actionlint")
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 		fix, _ := cmd.Flags().GetBool("fix")
 		stats, _ := cmd.Flags().GetBool("stats")
 		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		check, _ := cmd.Flags().GetBool("check")
+		noCache, _ := cmd.Flags().GetBool("no-cache")
+		jobs, _ := cmd.Flags().GetInt("jobs")
 		noCheckUpdate, _ := cmd.Flags().GetBool("no-check-update")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		if err := validateEngine(engineOverride); err != nil {
@@ -313,10 +347,16 @@ Examples:
 			ForceRefreshActionPins: forceRefreshActionPins,
 			Zizmor:                 zizmor,
 			Poutine:                poutine,
+			Lint:                   lint,
+			SARIFFile:              sarifFile,
+			FailOnWarnings:         failOnWarnings,
 			Actionlint:             actionlint,
 			JSONOutput:             jsonOutput,
 			Stats:                  stats,
 			FailFast:               failFast,
+			Check:                  check,
+			NoCache:                noCache,
+			Jobs:                   jobs,
 		}
 		if _, err := cli.CompileWorkflows(cmd.Context(), config); err != nil {
 			// Return error as-is without additional formatting
@@ -543,11 +583,17 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().Bool("force-refresh-action-pins", false, "Force refresh of action pins by clearing the cache and resolving all action SHAs from GitHub API")
 	compileCmd.Flags().Bool("zizmor", false, "Run zizmor security scanner on generated .lock.yml files")
 	compileCmd.Flags().Bool("poutine", false, "Run poutine security scanner on generated .lock.yml files")
+	compileCmd.Flags().Bool("lint", false, "Run the built-in security linter on generated .lock.yml files (no Docker required)")
+	compileCmd.Flags().String("sarif", "", "Write all compile and validation diagnostics to this file as a SARIF 2.1.0 log")
+	compileCmd.Flags().Bool("fail-on-warnings", false, "Fail when compilation reports warnings that are not turned off in .github/aw/lint.yml or suppressed with gh-aw-ignore")
 	compileCmd.Flags().Bool("actionlint", false, "Run actionlint linter on generated .lock.yml files")
 	compileCmd.Flags().Bool("fix", false, "Apply automatic codemod fixes to workflows before compiling")
 	compileCmd.Flags().BoolP("json", "j", false, "Output results in JSON format")
-	compileCmd.Flags().Bool("stats", false, "Display statistics table sorted by file size (shows jobs, steps, scripts, and shells)")
+	compileCmd.Flags().Bool("stats", false, "Display statistics table sorted by file size, with size budget usage and a breakdown of lock files approaching their budget")
 	compileCmd.Flags().Bool("fail-fast", false, "Stop at the first validation error instead of collecting all errors")
+	compileCmd.Flags().Bool("check", false, "Check that all lock files are up to date without writing them (exits with an error if any are stale)")
+	compileCmd.Flags().Bool("no-cache", false, "Ignore the incremental compilation cache and recompile all workflows")
+	compileCmd.Flags().Int("jobs", 1, "Number of workflows to compile concurrently")
 	compileCmd.Flags().Bool("no-check-update", false, "Skip checking for gh-aw updates")
 	compileCmd.MarkFlagsMutuallyExclusive("dir", "workflows-dir")
 
@@ -593,6 +639,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 
 	// Create commands that need group assignment
 	mcpCmd := cli.NewMCPCommand()
+	safeInputsCmd := cli.NewSafeInputsCommand()
 	logsCmd := cli.NewLogsCommand()
 	auditCmd := cli.NewAuditCommand()
 	healthCmd := cli.NewHealthCommand()
@@ -600,9 +647,11 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	prCmd := cli.NewPRCommand()
 	secretsCmd := cli.NewSecretsCommand()
 	fixCmd := cli.NewFixCommand()
+	lspCmd := cli.NewLSPCommand()
 	upgradeCmd := cli.NewUpgradeCommand()
 	completionCmd := cli.NewCompletionCommand()
 	hashCmd := cli.NewHashCommand()
+	promptCmd := cli.NewPromptCommand()
 	projectCmd := cli.NewProjectCommand()
 
 	// Assign commands to groups
@@ -618,9 +667,12 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	// Development Commands
 	compileCmd.GroupID = "development"
 	mcpCmd.GroupID = "development"
+	safeInputsCmd.GroupID = "development"
 	statusCmd.GroupID = "development"
 	listCmd.GroupID = "development"
 	fixCmd.GroupID = "development"
+	lspCmd.GroupID = "development"
+	promptCmd.GroupID = "development"
 
 	// Execution Commands
 	runCmd.GroupID = "execution"
@@ -660,13 +712,16 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(healthCmd)
 	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(safeInputsCmd)
 	rootCmd.AddCommand(mcpServerCmd)
 	rootCmd.AddCommand(prCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(secretsCmd)
 	rootCmd.AddCommand(fixCmd)
+	rootCmd.AddCommand(lspCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(hashCmd)
+	rootCmd.AddCommand(promptCmd)
 	rootCmd.AddCommand(projectCmd)
 }
 
@@ -391,6 +391,13 @@ func parseAndDisplayActionlintOutput(stdout string, verbose bool) (int, map[stri
 		}
 
 		fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
+
+		ruleID := "actionlint"
+		if err.Kind != "" {
+			ruleID += "/" + err.Kind
+		}
+		recordSARIFResult(ruleID, "actionlint: "+err.Kind, getActionlintDocsURL(err.Kind),
+			errorType, err.Message, err.Filepath, err.Line, err.Column)
 	}
 
 	return totalErrors, errorsByKind, nil
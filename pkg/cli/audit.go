@@ -286,6 +286,12 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract noops: %v", noopErr)))
 	}
 
+	// Extract safe output items rejected by the content policy
+	contentPolicyViolations, err := extractContentPolicyViolationsFromRun(runOutputDir, run, verbose)
+	if err != nil && verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract content policy violations: %v", err)))
+	}
+
 	// Extract MCP failures
 	mcpFailures, err := extractMCPFailuresFromRun(runOutputDir, run, verbose)
 	if err != nil && verbose {
@@ -330,6 +336,7 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		MissingTools:            missingTools,
 		MissingData:             missingData,
 		Noops:                   noops,
+		ContentPolicyViolations: contentPolicyViolations,
 		MCPFailures:             mcpFailures,
 		JobDetails:              jobDetails,
 	}
@@ -401,6 +408,7 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		MissingTools:            missingTools,
 		MissingData:             missingData,
 		Noops:                   noops,
+		ContentPolicyViolations: contentPolicyViolations,
 		MCPFailures:             mcpFailures,
 		ArtifactsList:           artifacts,
 		JobDetails:              jobDetails,
@@ -834,6 +842,15 @@ func generateAuditReport(processedRun ProcessedRun, metrics LogMetrics, download
 		}
 	}
 
+	// Content Policy Violations
+	if len(processedRun.ContentPolicyViolations) > 0 {
+		report.WriteString("## Content Policy Violations\n\n")
+		for _, violation := range processedRun.ContentPolicyViolations {
+			fmt.Fprintf(&report, "- **Item %d** (`%s`): %s\n", violation.Index+1, violation.Type, violation.Reason)
+		}
+		report.WriteString("\n")
+	}
+
 	// Error Summary
 	if run.ErrorCount > 0 || run.WarningCount > 0 {
 		report.WriteString("## Issue Summary\n\n")
@@ -16,24 +16,26 @@ var auditReportLog = logger.New("cli:audit_report")
 
 // AuditData represents the complete structured audit data for a workflow run
 type AuditData struct {
-	Overview                OverviewData             `json:"overview"`
-	Metrics                 MetricsData              `json:"metrics"`
-	KeyFindings             []Finding                `json:"key_findings,omitempty"`
-	Recommendations         []Recommendation         `json:"recommendations,omitempty"`
-	FailureAnalysis         *FailureAnalysis         `json:"failure_analysis,omitempty"`
-	PerformanceMetrics      *PerformanceMetrics      `json:"performance_metrics,omitempty"`
-	Jobs                    []JobData                `json:"jobs,omitempty"`
-	DownloadedFiles         []FileInfo               `json:"downloaded_files"`
-	MissingTools            []MissingToolReport      `json:"missing_tools,omitempty"`
-	MissingData             []MissingDataReport      `json:"missing_data,omitempty"`
-	Noops                   []NoopReport             `json:"noops,omitempty"`
-	MCPFailures             []MCPFailureReport       `json:"mcp_failures,omitempty"`
-	FirewallAnalysis        *FirewallAnalysis        `json:"firewall_analysis,omitempty"`
-	RedactedDomainsAnalysis *RedactedDomainsAnalysis `json:"redacted_domains_analysis,omitempty"`
-	Errors                  []ErrorInfo              `json:"errors,omitempty"`
-	Warnings                []ErrorInfo              `json:"warnings,omitempty"`
-	ToolUsage               []ToolUsageInfo          `json:"tool_usage,omitempty"`
-	MCPToolUsage            *MCPToolUsageData        `json:"mcp_tool_usage,omitempty"`
+	Overview                OverviewData                   `json:"overview"`
+	Metrics                 MetricsData                    `json:"metrics"`
+	KeyFindings             []Finding                      `json:"key_findings,omitempty"`
+	Recommendations         []Recommendation               `json:"recommendations,omitempty"`
+	FailureAnalysis         *FailureAnalysis               `json:"failure_analysis,omitempty"`
+	PerformanceMetrics      *PerformanceMetrics            `json:"performance_metrics,omitempty"`
+	Jobs                    []JobData                      `json:"jobs,omitempty"`
+	DownloadedFiles         []FileInfo                     `json:"downloaded_files"`
+	MissingTools            []MissingToolReport            `json:"missing_tools,omitempty"`
+	MissingData             []MissingDataReport            `json:"missing_data,omitempty"`
+	Noops                   []NoopReport                   `json:"noops,omitempty"`
+	ContentPolicyViolations []ContentPolicyViolationReport `json:"content_policy_violations,omitempty"`
+	MCPFailures             []MCPFailureReport             `json:"mcp_failures,omitempty"`
+	FirewallAnalysis        *FirewallAnalysis              `json:"firewall_analysis,omitempty"`
+	RedactedDomainsAnalysis *RedactedDomainsAnalysis       `json:"redacted_domains_analysis,omitempty"`
+	Errors                  []ErrorInfo                    `json:"errors,omitempty"`
+	Warnings                []ErrorInfo                    `json:"warnings,omitempty"`
+	ToolUsage               []ToolUsageInfo                `json:"tool_usage,omitempty"`
+	MCPToolUsage            *MCPToolUsageData              `json:"mcp_tool_usage,omitempty"`
+	PromptLimit             *PromptLimitInfo               `json:"prompt_limit,omitempty"`
 }
 
 // Finding represents a key insight discovered during audit
@@ -284,8 +286,17 @@ func buildAuditData(processedRun ProcessedRun, metrics LogMetrics, mcpToolUsage
 		toolUsage = append(toolUsage, *info)
 	}
 
+	// Read the prompt size limit decisions recorded in aw_info.json
+	var promptLimit *PromptLimitInfo
+	if run.LogsPath != "" {
+		if info, err := parseAwInfo(filepath.Join(run.LogsPath, "aw_info.json"), false); err == nil && info != nil {
+			promptLimit = info.PromptLimit
+		}
+	}
+
 	// Generate key findings
 	findings := generateFindings(processedRun, metricsData, errors, warnings)
+	findings = append(findings, generatePromptLimitFindings(promptLimit)...)
 
 	// Generate recommendations
 	recommendations := generateRecommendations(processedRun, metricsData, findings)
@@ -316,6 +327,7 @@ func buildAuditData(processedRun ProcessedRun, metrics LogMetrics, mcpToolUsage
 		MissingTools:            processedRun.MissingTools,
 		MissingData:             processedRun.MissingData,
 		Noops:                   processedRun.Noops,
+		ContentPolicyViolations: processedRun.ContentPolicyViolations,
 		MCPFailures:             processedRun.MCPFailures,
 		FirewallAnalysis:        processedRun.FirewallAnalysis,
 		RedactedDomainsAnalysis: processedRun.RedactedDomainsAnalysis,
@@ -323,6 +335,7 @@ func buildAuditData(processedRun ProcessedRun, metrics LogMetrics, mcpToolUsage
 		Warnings:                warnings,
 		ToolUsage:               toolUsage,
 		MCPToolUsage:            mcpToolUsage,
+		PromptLimit:             promptLimit,
 	}
 }
 
@@ -122,6 +122,17 @@ func generateFindings(processedRun ProcessedRun, metrics MetricsData, errors []E
 		})
 	}
 
+	// Content policy findings
+	if len(processedRun.ContentPolicyViolations) > 0 {
+		findings = append(findings, Finding{
+			Category:    "security",
+			Severity:    "high",
+			Title:       "Content Policy Violations",
+			Description: fmt.Sprintf("%d safe output item(s) were rejected by safe-outputs.content-policy", len(processedRun.ContentPolicyViolations)),
+			Impact:      "Rejected items were not applied; review the agent output or adjust the content policy",
+		})
+	}
+
 	// Firewall findings
 	if processedRun.FirewallAnalysis != nil && processedRun.FirewallAnalysis.BlockedRequests > 0 {
 		findings = append(findings, Finding{
@@ -147,6 +158,42 @@ func generateFindings(processedRun ProcessedRun, metrics MetricsData, errors []E
 	return findings
 }
 
+// generatePromptLimitFindings creates findings from the prompt size limit decisions
+func generatePromptLimitFindings(promptLimit *PromptLimitInfo) []Finding {
+	if promptLimit == nil {
+		return nil
+	}
+	if promptLimit.Exceeded {
+		return []Finding{{
+			Category:    "error",
+			Severity:    "critical",
+			Title:       "Prompt Too Large",
+			Description: fmt.Sprintf("Prompt was ~%d tokens after reduction, more than prompt.max-tokens (%d)", promptLimit.FinalTokens, promptLimit.MaxTokens),
+			Impact:      "The engine did not run; raise prompt.max-tokens or add sections to prompt.summarize or prompt.truncate",
+		}}
+	}
+	if len(promptLimit.Truncated) == 0 {
+		return nil
+	}
+	title := "Prompt Summarized"
+	impact := "The agent saw summaries instead of the full content of the summarized sections"
+	sections := make([]string, len(promptLimit.Truncated))
+	for i, truncation := range promptLimit.Truncated {
+		sections[i] = fmt.Sprintf("%s (%s)", truncation.Section, truncation.Action)
+		if truncation.Action != "summarized" {
+			title = "Prompt Truncated"
+			impact = "The agent did not see the full content of the truncated sections"
+		}
+	}
+	return []Finding{{
+		Category:    "performance",
+		Severity:    "medium",
+		Title:       title,
+		Description: fmt.Sprintf("Prompt was reduced from ~%d to ~%d tokens to fit prompt.max-tokens (%d): %s", promptLimit.OriginalTokens, promptLimit.FinalTokens, promptLimit.MaxTokens, strings.Join(sections, ", ")),
+		Impact:      impact,
+	}}
+}
+
 // generateRecommendations creates actionable recommendations based on findings
 func generateRecommendations(processedRun ProcessedRun, metrics MetricsData, findings []Finding) []Recommendation {
 	auditReportLog.Printf("Generating recommendations: findings_count=%d, workflow_conclusion=%s", len(findings), processedRun.Run.Conclusion)
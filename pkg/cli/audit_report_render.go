@@ -60,6 +60,13 @@ func renderConsole(data AuditData, logsPath string) {
 		renderPerformanceMetrics(data.PerformanceMetrics)
 	}
 
+	// Prompt Size Section
+	if data.PromptLimit != nil {
+		fmt.Fprintln(os.Stderr, console.FormatSectionHeader("Prompt Size"))
+		fmt.Fprintln(os.Stderr)
+		renderPromptLimit(data.PromptLimit)
+	}
+
 	// Metrics Section - use new rendering system
 	fmt.Fprintln(os.Stderr, console.FormatSectionHeader("Metrics"))
 	fmt.Fprintln(os.Stderr)
@@ -102,6 +109,17 @@ func renderConsole(data AuditData, logsPath string) {
 		fmt.Fprintln(os.Stderr)
 	}
 
+	// Content Policy Violations Section
+	if len(data.ContentPolicyViolations) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSectionHeader("Content Policy Violations"))
+		fmt.Fprintln(os.Stderr)
+		for _, violation := range data.ContentPolicyViolations {
+			fmt.Fprintf(os.Stderr, "  • Item %d (%s)\n", violation.Index+1, violation.Type)
+			fmt.Fprintf(os.Stderr, "    Reason: %s\n", violation.Reason)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+
 	// MCP Failures Section
 	if len(data.MCPFailures) > 0 {
 		fmt.Fprintln(os.Stderr, console.FormatSectionHeader("MCP Server Failures"))
@@ -539,6 +557,37 @@ func renderFailureAnalysis(analysis *FailureAnalysis) {
 	}
 }
 
+// renderPromptLimit renders the prompt size limit decisions
+func renderPromptLimit(promptLimit *PromptLimitInfo) {
+	fmt.Fprintf(os.Stderr, "  Limit: %s tokens\n", console.FormatNumber(promptLimit.MaxTokens))
+	fmt.Fprintf(os.Stderr, "  Rendered: ~%s tokens\n", console.FormatNumber(promptLimit.OriginalTokens))
+	if len(promptLimit.Truncated) > 0 {
+		fmt.Fprintf(os.Stderr, "  After reduction: ~%s tokens\n", console.FormatNumber(promptLimit.FinalTokens))
+	}
+	if promptLimit.Exceeded {
+		fmt.Fprintf(os.Stderr, "  %s\n", console.FormatErrorMessage("Prompt did not fit; the engine did not run"))
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if len(promptLimit.Truncated) == 0 {
+		return
+	}
+	config := console.TableConfig{
+		Headers: []string{"Section", "Action", "Before", "After"},
+		Rows:    make([][]string, 0, len(promptLimit.Truncated)),
+	}
+	for _, truncation := range promptLimit.Truncated {
+		config.Rows = append(config.Rows, []string{
+			truncation.Section,
+			truncation.Action,
+			"~" + console.FormatNumber(truncation.OriginalTokens),
+			"~" + console.FormatNumber(truncation.FinalTokens),
+		})
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(config))
+	fmt.Fprintln(os.Stderr)
+}
+
 // renderPerformanceMetrics renders performance metrics
 func renderPerformanceMetrics(metrics *PerformanceMetrics) {
 	if metrics.TokensPerMinute > 0 {
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -1029,6 +1030,41 @@ func TestBuildAuditDataMinimal(t *testing.T) {
 	_ = auditData.Jobs
 }
 
+func TestBuildAuditDataPromptLimit(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "audit-prompt-limit-*")
+	awInfo := `{"engine_id":"copilot","prompt_limit":{"max_tokens":1000,"original_tokens":1800,"final_tokens":990,"truncated":[{"section":"import:.github/workflows/shared/guide.md","original_tokens":900,"final_tokens":90,"action":"truncated"}],"exceeded":false}}`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "aw_info.json"), []byte(awInfo), 0o644), "should write aw_info.json")
+
+	processedRun := createTestProcessedRun(func(pr *ProcessedRun) {
+		pr.Run.LogsPath = tmpDir
+	})
+	auditData := buildAuditData(processedRun, workflow.LogMetrics{}, nil)
+
+	require.NotNil(t, auditData.PromptLimit, "prompt limit should be read from aw_info.json")
+	assert.Equal(t, 1800, auditData.PromptLimit.OriginalTokens, "original prompt size should be reported")
+	require.Len(t, auditData.PromptLimit.Truncated, 1, "truncation decisions should be reported")
+	assert.Equal(t, "truncated", auditData.PromptLimit.Truncated[0].Action, "truncation action should be reported")
+
+	var found bool
+	for _, finding := range auditData.KeyFindings {
+		if finding.Title == "Prompt Truncated" {
+			found = true
+			assert.Contains(t, finding.Description, "import:.github/workflows/shared/guide.md", "finding should name the truncated section")
+		}
+	}
+	assert.True(t, found, "truncation should be a key finding")
+
+	summarized := generatePromptLimitFindings(&PromptLimitInfo{MaxTokens: 1000, OriginalTokens: 1800, FinalTokens: 900, Truncated: []PromptTruncationInfo{{Section: "imports", OriginalTokens: 1000, FinalTokens: 100, Action: "summarized"}}})
+	require.Len(t, summarized, 1, "summarization should be a finding")
+	assert.Equal(t, "Prompt Summarized", summarized[0].Title, "summarized prompts should not be reported as truncated")
+	assert.Contains(t, summarized[0].Description, "imports (summarized)", "finding should name the summarized section")
+
+	exceeded := generatePromptLimitFindings(&PromptLimitInfo{MaxTokens: 1000, OriginalTokens: 3000, FinalTokens: 2500, Exceeded: true})
+	require.Len(t, exceeded, 1, "an exceeded limit should be a finding")
+	assert.Equal(t, "critical", exceeded[0].Severity, "an exceeded limit stops the run")
+	assert.Empty(t, generatePromptLimitFindings(&PromptLimitInfo{MaxTokens: 1000, OriginalTokens: 500, FinalTokens: 500}), "prompts within the limit should not be findings")
+}
+
 func TestRenderJSONComplete(t *testing.T) {
 	auditData := AuditData{
 		Overview: OverviewData{
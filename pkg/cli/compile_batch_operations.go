@@ -15,6 +15,7 @@
 //
 // Batch Linting:
 //   - runBatchActionlint() - Run actionlint on multiple lock files
+//   - runBatchSecurityLint() - Run the built-in security linter on multiple lock files
 //
 // File Cleanup:
 //   - purgeOrphanedLockFiles() - Remove orphaned .lock.yml files
@@ -81,6 +82,26 @@ func runBatchZizmor(lockFiles []string, verbose bool, strict bool) error {
 	return nil
 }
 
+// runBatchSecurityLint runs the built-in security linter on all lock files in batch
+func runBatchSecurityLint(lockFiles []string, verbose bool, strict bool) error {
+	if len(lockFiles) == 0 {
+		compileBatchOperationsLog.Print("No lock files to lint with the security linter")
+		return nil
+	}
+
+	compileBatchOperationsLog.Printf("Running batch security linter on %d lock files", len(lockFiles))
+
+	if err := runSecurityLintOnFiles(lockFiles, verbose, strict); err != nil {
+		if strict {
+			return fmt.Errorf("security linter failed: %w", err)
+		}
+		// In non-strict mode, linter errors are warnings
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("security linter warnings: %v", err)))
+	}
+
+	return nil
+}
+
 // runBatchPoutine runs poutine security scanner once for the entire directory
 func runBatchPoutine(workflowDir string, verbose bool, strict bool) error {
 	compileBatchOperationsLog.Printf("Running batch poutine on directory: %s", workflowDir)
@@ -0,0 +1,565 @@
+// This file provides the incremental compilation cache.
+//
+// The cache records, for each compiled workflow, a content-addressed key of every input
+// that affects the generated lock file, together with the hash of the lock file that was
+// written. A workflow whose key and lock file are unchanged does not need to be recompiled.
+//
+// # Cache Key
+//
+// The key is a SHA-256 over:
+//   - the cache format version and the compiler build (the version of a release, the hash of
+//     the executable for development builds, whose version does not change with the source)
+//   - compiler options that change the output (engine override, action mode/tag, strict, trial)
+//   - the action pin cache (.github/aw/actions-lock.json)
+//   - the rule severities (.github/aw/lint.yml), which can turn warnings into errors
+//   - the repository slug used to scatter fuzzy schedules
+//   - the frontmatter hash (ComputeFrontmatterHash), which covers imported frontmatter
+//   - the SHA-256 of the workflow source (frontmatter and body)
+//   - the SHA-256 of every resolved import and include, as recorded by the previous compilation
+//
+// Each entry also stores the rule warnings and suppressions of the compilation, which are
+// reported again when the lock file is reused so that warning counts and SARIF results do not
+// depend on whether a workflow was cached.
+//
+// The cache is stored in .github/aw/compile-cache.json of the repository that contains the
+// compiled workflows, and entries are keyed by repository-relative path. Workflows outside
+// that repository are never cached, and the cache is only used in repositories that already
+// have a .github/aw directory. `gh aw init` adds the file to .gitignore; it can be persisted
+// between CI runs so that `gh aw compile --check` skips unchanged workflows.
+
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var compileCacheLog = logger.New("cli:compile_cache")
+
+const (
+	// CompileCacheFileName is the name of the compilation cache file in .github/aw/
+	CompileCacheFileName = "compile-cache.json"
+
+	// compileCacheFormatVersion is bumped whenever the key computation or the entry format changes
+	compileCacheFormatVersion = 3
+)
+
+// CompileCacheEntry records the inputs and output of one workflow compilation
+type CompileCacheEntry struct {
+	Key          string   `json:"key"`                    // Content-addressed key of all compilation inputs
+	LockHash     string   `json:"lock_hash"`              // SHA-256 of the generated lock file
+	Validated    bool     `json:"validated,omitempty"`    // True when the workflow passed --validate
+	Dependencies []string `json:"dependencies,omitempty"` // Repository-relative imports and includes
+
+	Warnings   []workflow.RuleDiagnostic       `json:"warnings,omitempty"`   // Rule warnings reported by the compilation
+	Suppressed []workflow.SuppressedDiagnostic `json:"suppressed,omitempty"` // Warnings silenced by gh-aw-ignore comments
+}
+
+// CompileCache is the persistent, content-addressed compilation cache.
+// It is safe for concurrent use during parallel compilation.
+type CompileCache struct {
+	Version int                          `json:"version"`
+	Entries map[string]CompileCacheEntry `json:"entries"` // key: repository-relative workflow path
+
+	path    string
+	gitRoot string
+	options string // Compiler version and options that affect the generated output
+	dirty   bool
+	mu      sync.Mutex // guards Entries, dirty and dirRoots
+
+	dirRoots map[string]string // git root of each workflow directory ("" outside a repository)
+}
+
+// NewCompileCache creates a compilation cache for the repository at gitRoot. options
+// identifies the compiler version and settings; entries recorded with different options
+// never match.
+func NewCompileCache(gitRoot string, options string) *CompileCache {
+	return &CompileCache{
+		Version: compileCacheFormatVersion,
+		Entries: make(map[string]CompileCacheEntry),
+		path:    filepath.Join(gitRoot, ".github", "aw", CompileCacheFileName),
+		gitRoot: gitRoot,
+		options: options,
+
+		dirRoots: make(map[string]string),
+	}
+}
+
+// compilerBuildID identifies the running compiler build. Release builds are identified by their
+// version. Development builds ("dev", or "<sha>-dirty" from make) keep their version while the
+// compiler source changes, so they are identified by the SHA-256 of the executable. It returns ""
+// when the executable cannot be read, which disables the cache.
+var compilerBuildID = sync.OnceValue(func() string {
+	if workflow.IsRelease() {
+		return "release"
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		compileCacheLog.Printf("Failed to locate the executable: %v", err)
+		return ""
+	}
+	file, err := os.Open(executable)
+	if err != nil {
+		compileCacheLog.Printf("Failed to open the executable: %v", err)
+		return ""
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		compileCacheLog.Printf("Failed to hash the executable: %v", err)
+		return ""
+	}
+	return "executable:" + hex.EncodeToString(hash.Sum(nil))
+})
+
+// compileCacheOptions describes the compiler build and settings that affect the generated lock files
+func compileCacheOptions(compiler *workflow.Compiler, config CompileConfig) string {
+	return strings.Join([]string{
+		"version=" + compiler.GetVersion(),
+		"build=" + compilerBuildID(),
+		"engine=" + config.EngineOverride,
+		"action-mode=" + string(compiler.GetActionMode()),
+		"action-tag=" + compiler.GetActionTag(),
+		fmt.Sprintf("strict=%t", config.Strict),
+		fmt.Sprintf("trial=%t", config.TrialMode),
+		"logical-repo=" + config.TrialLogicalRepoSlug,
+	}, "\n")
+}
+
+// newCompileCacheForConfig loads the compilation cache of the repository containing the first
+// of workflowPaths that is in a git repository, or returns nil when caching does not apply:
+// --no-cache, watch mode, when action pins or stop times are being refreshed, with
+// --fail-on-warnings, which needs the warnings of every workflow, when no workflow is in a git
+// repository with a .github/aw directory, or when the compiler build cannot be identified.
+func newCompileCacheForConfig(compiler *workflow.Compiler, config CompileConfig, workflowPaths []string) *CompileCache {
+	if config.NoCache || config.Watch || config.ForceRefreshActionPins || config.RefreshStopTime || config.FailOnWarnings {
+		compileCacheLog.Print("Compilation cache disabled for this run")
+		return nil
+	}
+	if compilerBuildID() == "" {
+		compileCacheLog.Print("Compilation cache disabled, the compiler build cannot be identified")
+		return nil
+	}
+
+	var gitRoot string
+	for _, workflowPath := range workflowPaths {
+		if root, err := findGitRootForPath(workflowPath); err == nil {
+			gitRoot = root
+			break
+		}
+	}
+	if gitRoot == "" {
+		compileCacheLog.Print("Compilation cache disabled, no workflow is in a git repository")
+		return nil
+	}
+	if info, err := os.Stat(filepath.Join(gitRoot, ".github", "aw")); err != nil || !info.IsDir() {
+		compileCacheLog.Printf("Compilation cache disabled, %s has no .github/aw directory", gitRoot)
+		return nil
+	}
+
+	cache := NewCompileCache(gitRoot, compileCacheOptions(compiler, config))
+	if err := cache.Load(); err != nil {
+		// A corrupt cache is discarded and rebuilt
+		compileCacheLog.Printf("Ignoring unreadable compilation cache: %v", err)
+		cache.Entries = make(map[string]CompileCacheEntry)
+		cache.dirty = true
+	}
+	return cache
+}
+
+// Load reads the cache from disk. A missing file or a different format version yields an empty cache.
+func (c *CompileCache) Load() error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			compileCacheLog.Print("Compilation cache does not exist, starting empty")
+			return nil
+		}
+		return err
+	}
+
+	var stored CompileCache
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", c.path, err)
+	}
+	if stored.Version != compileCacheFormatVersion {
+		compileCacheLog.Printf("Compilation cache format %d does not match %d, starting empty", stored.Version, compileCacheFormatVersion)
+		c.dirty = true
+		return nil
+	}
+	if stored.Entries != nil {
+		c.Entries = stored.Entries
+	}
+	compileCacheLog.Printf("Loaded compilation cache with %d entries", len(c.Entries))
+	return nil
+}
+
+// Save writes the cache to disk if it changed
+func (c *CompileCache) Save() error {
+	if !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	compileCacheLog.Printf("Saved compilation cache with %d entries to %s", len(c.Entries), c.path)
+	c.dirty = false
+	return nil
+}
+
+// GetCachePath returns the path of the cache file
+func (c *CompileCache) GetCachePath() string {
+	return c.path
+}
+
+// relativePath returns the repository-relative, slash-separated form of path, or false when
+// path is outside the cache's repository
+func (c *CompileCache) relativePath(path string) (string, bool) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	candidates := []string{absPath}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil && resolved != absPath {
+		candidates = append(candidates, resolved)
+	}
+	for _, candidate := range candidates {
+		if rel, err := filepath.Rel(c.gitRoot, candidate); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel), true
+		}
+	}
+	return "", false
+}
+
+// workflowKey returns the cache entry key of a workflow, or false when the workflow does not
+// belong to the cache's repository (including nested repositories) and must not be cached
+func (c *CompileCache) workflowKey(workflowPath string) (string, bool) {
+	dir := filepath.Dir(workflowPath)
+	c.mu.Lock()
+	root, ok := c.dirRoots[dir]
+	c.mu.Unlock()
+	if !ok {
+		root, _ = findGitRootForPath(workflowPath)
+		c.mu.Lock()
+		c.dirRoots[dir] = root
+		c.mu.Unlock()
+	}
+	if root != c.gitRoot {
+		compileCacheLog.Printf("Not caching %s: it is not in %s", workflowPath, c.gitRoot)
+		return "", false
+	}
+	return c.relativePath(workflowPath)
+}
+
+// computeKey computes the content-addressed key of a workflow with the given dependencies
+func (c *CompileCache) computeKey(workflowPath string, dependencies []string) (string, error) {
+	source, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return "", err
+	}
+	frontmatterHash, err := parser.ComputeFrontmatterHashFromFile(workflowPath, parser.NewImportCache(c.gitRoot))
+	if err != nil {
+		return "", err
+	}
+
+	var key strings.Builder
+	fmt.Fprintf(&key, "format=%d\n%s\n", compileCacheFormatVersion, c.options)
+	fmt.Fprintf(&key, "actions-lock=%s\n", hashFileOrEmpty(filepath.Join(c.gitRoot, ".github", "aw", workflow.CacheFileName)))
+	fmt.Fprintf(&key, "lint=%s\n", hashFileOrEmpty(filepath.Join(c.gitRoot, ".github", "aw", workflow.LintConfigFileName)))
+	fmt.Fprintf(&key, "repository=%s\n", getRepositorySlugFromRemoteForPath(workflowPath))
+	fmt.Fprintf(&key, "frontmatter=%s\n", frontmatterHash)
+	fmt.Fprintf(&key, "source=%s\n", hashBytes(source))
+	for _, dependency := range dependencies {
+		if filepath.IsAbs(filepath.FromSlash(dependency)) {
+			return "", fmt.Errorf("dependency %s is outside the repository", dependency)
+		}
+		dependencyHash := hashFileOrEmpty(filepath.Join(c.gitRoot, filepath.FromSlash(dependency)))
+		if dependencyHash == "" {
+			return "", fmt.Errorf("dependency %s is missing", dependency)
+		}
+		fmt.Fprintf(&key, "dependency=%s:%s\n", dependency, dependencyHash)
+	}
+	return hashBytes([]byte(key.String())), nil
+}
+
+// IsFresh reports whether a workflow's lock file is up to date with its cached inputs.
+// When validate is set, the cached compilation must also have passed validation.
+func (c *CompileCache) IsFresh(workflowPath string, validate bool) bool {
+	_, fresh := c.lookupFresh(workflowPath, validate)
+	return fresh
+}
+
+// lookupFresh returns the cache entry of a workflow whose lock file is up to date (see IsFresh)
+func (c *CompileCache) lookupFresh(workflowPath string, validate bool) (CompileCacheEntry, bool) {
+	relPath, ok := c.workflowKey(workflowPath)
+	if !ok {
+		return CompileCacheEntry{}, false
+	}
+	c.mu.Lock()
+	entry, ok := c.Entries[relPath]
+	c.mu.Unlock()
+	if !ok || (validate && !entry.Validated) {
+		return CompileCacheEntry{}, false
+	}
+
+	if hashFileOrEmpty(stringutil.MarkdownToLockFile(workflowPath)) != entry.LockHash {
+		compileCacheLog.Printf("Lock file for %s changed since it was cached", workflowPath)
+		return CompileCacheEntry{}, false
+	}
+
+	key, err := c.computeKey(workflowPath, entry.Dependencies)
+	if err != nil {
+		compileCacheLog.Printf("Failed to compute cache key for %s: %v", workflowPath, err)
+		return CompileCacheEntry{}, false
+	}
+	fresh := key == entry.Key
+	compileCacheLog.Printf("Cache lookup for %s: fresh=%t", workflowPath, fresh)
+	return entry, fresh
+}
+
+// Record stores the result of a successful compilation that wrote the workflow's lock file,
+// with the rule warnings and suppressions it reported
+func (c *CompileCache) Record(workflowPath string, data *workflow.WorkflowData, validated bool, diagnostics *workflow.WorkflowDiagnostics) {
+	relPath, ok := c.workflowKey(workflowPath)
+	if !ok {
+		return
+	}
+	dependencies := c.dependenciesOf(workflowPath, data)
+
+	key, err := c.computeKey(workflowPath, dependencies)
+	lockHash := hashFileOrEmpty(stringutil.MarkdownToLockFile(workflowPath))
+	if err != nil || lockHash == "" {
+		compileCacheLog.Printf("Not caching %s: key error=%v, lock file present=%t", workflowPath, err, lockHash != "")
+		c.Forget(workflowPath)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Entries[relPath] = CompileCacheEntry{
+		Key:          key,
+		LockHash:     lockHash,
+		Validated:    validated,
+		Dependencies: dependencies,
+	}
+	if diagnostics != nil {
+		entry := c.Entries[relPath]
+		entry.Warnings = slices.Clone(diagnostics.Warnings)
+		entry.Suppressed = slices.Clone(diagnostics.Suppressed)
+		c.Entries[relPath] = entry
+	}
+	c.dirty = true
+}
+
+// Forget removes a workflow from the cache, e.g. after a failed compilation
+func (c *CompileCache) Forget(workflowPath string) {
+	relPath, ok := c.workflowKey(workflowPath)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.Entries[relPath]; ok {
+		delete(c.Entries, relPath)
+		c.dirty = true
+	}
+}
+
+// Refresh recomputes the keys of the given workflows. Keys include the action pin cache,
+// which is saved after all workflows are compiled, so they are refreshed before saving.
+func (c *CompileCache) Refresh(workflowPaths []string) {
+	for _, workflowPath := range workflowPaths {
+		relPath, ok := c.workflowKey(workflowPath)
+		if !ok {
+			continue
+		}
+		entry, ok := c.Entries[relPath]
+		if !ok {
+			continue
+		}
+		key, err := c.computeKey(workflowPath, entry.Dependencies)
+		if err != nil {
+			c.Forget(workflowPath)
+			continue
+		}
+		if key != entry.Key {
+			entry.Key = key
+			c.Entries[relPath] = entry
+			c.dirty = true
+		}
+	}
+}
+
+// dependenciesOf returns the sorted repository-relative imports and includes of a workflow.
+// Dependencies outside the repository are kept as absolute paths, which computeKey rejects.
+func (c *CompileCache) dependenciesOf(workflowPath string, data *workflow.WorkflowData) []string {
+	if data == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if rel, ok := c.relativePath(path); ok {
+			seen[rel] = true
+		} else if absPath, err := filepath.Abs(path); err == nil {
+			seen[filepath.ToSlash(absPath)] = true
+		}
+	}
+	for _, file := range data.ResolvedImportFiles {
+		add(file)
+	}
+	for _, file := range data.IncludedFiles {
+		path := filepath.FromSlash(file)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(workflowPath), path)
+		}
+		add(path)
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// hashBytes returns the hex SHA-256 of data
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashFileOrEmpty returns the hex SHA-256 of a file, or "" when it cannot be read
+func hashFileOrEmpty(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			compileCacheLog.Printf("Failed to read %s: %v", path, err)
+		}
+		return ""
+	}
+	return hashBytes(data)
+}
+
+// compileWorkflowFileWithCache compiles a workflow file, skipping generation and validation
+// when the compilation cache shows that its lock file is up to date. Cached workflows are
+// still parsed so that post-processing (Dependabot, maintenance workflow) sees every workflow.
+func compileWorkflowFileWithCache(
+	compiler *workflow.Compiler,
+	cache *CompileCache,
+	resolvedFile string,
+	config CompileConfig,
+	validate bool,
+) compileWorkflowFileResult {
+	if cache == nil {
+		return compileWorkflowFile(
+			compiler, resolvedFile, config.Verbose, config.JSONOutput,
+			config.NoEmit, false, false, false, // Disable per-file security tools
+			config.Strict, validate,
+		)
+	}
+
+	if !config.NoEmit {
+		if entry, fresh := cache.lookupFresh(resolvedFile, validate); fresh {
+			if result, ok := loadCachedWorkflowFile(compiler, resolvedFile, entry, config.JSONOutput); ok {
+				return result
+			}
+		}
+	}
+
+	result := compileWorkflowFile(
+		compiler, resolvedFile, config.Verbose, config.JSONOutput,
+		config.NoEmit, false, false, false, // Disable per-file security tools
+		config.Strict, validate,
+	)
+	switch {
+	case !result.success:
+		cache.Forget(resolvedFile)
+	case !config.NoEmit && result.workflowData != nil:
+		cache.Record(resolvedFile, result.workflowData, validate, compiler.GetWorkflowDiagnostics())
+	}
+	return result
+}
+
+// loadCachedWorkflowFile parses a workflow whose lock file is up to date without regenerating it,
+// and reports the warnings recorded in its cache entry again
+func loadCachedWorkflowFile(compiler *workflow.Compiler, resolvedFile string, entry CompileCacheEntry, jsonOutput bool) (compileWorkflowFileResult, bool) {
+	lockFile := stringutil.MarkdownToLockFile(resolvedFile)
+
+	relPath, err := getRepositoryRelativePath(resolvedFile)
+	if err != nil {
+		relPath = filepath.Base(resolvedFile)
+	}
+	compiler.SetWorkflowIdentifier(relPath)
+	if fileRepoSlug := getRepositorySlugFromRemoteForPath(resolvedFile); fileRepoSlug != "" {
+		compiler.SetRepositorySlug(fileRepoSlug)
+	}
+
+	// The warnings recorded with the entry are replayed below, so the warnings printed while
+	// parsing are discarded
+	diagnosticWriter := compiler.GetDiagnosticWriter()
+	compiler.SetDiagnosticWriter(io.Discard)
+	workflowData, err := compiler.ParseWorkflowFile(resolvedFile)
+	compiler.SetDiagnosticWriter(diagnosticWriter)
+	if err != nil {
+		// Fall back to a full compilation so the error is reported as usual
+		compileCacheLog.Printf("Failed to parse cached workflow %s: %v", resolvedFile, err)
+		compiler.ReplayWorkflowDiagnostics(nil, nil) // Uncounts the discarded warnings
+		return compileWorkflowFileResult{}, false
+	}
+
+	if !jsonOutput {
+		fmt.Fprintln(compiler.GetDiagnosticWriter(), console.FormatSuccessMessage(console.ToRelativePath(resolvedFile)+" (up to date)"))
+	}
+	compileCacheLog.Printf("Lock file for %s is up to date, skipping compilation", resolvedFile)
+	compiler.ReplayWorkflowDiagnostics(entry.Warnings, entry.Suppressed)
+
+	result := compileWorkflowFileResult{
+		workflowData: workflowData,
+		lockFile:     lockFile,
+		validationResult: ValidationResult{
+			Workflow:     filepath.Base(resolvedFile),
+			Valid:        true,
+			Errors:       []CompileValidationError{},
+			Warnings:     []CompileValidationError{},
+			CompiledFile: lockFile,
+		},
+		success: true,
+		cached:  true,
+	}
+	appendRuleDiagnostics(&result.validationResult, compiler)
+	return result, true
+}
+
+// saveCompileCache refreshes the keys of the processed workflows and saves the cache.
+// It runs after the action cache is saved because keys include the action pins.
+// Errors are logged but non-fatal: the cache only speeds up later compilations.
+func saveCompileCache(cache *CompileCache, processedFiles []string, verbose bool) {
+	if cache == nil {
+		return
+	}
+	cache.Refresh(processedFiles)
+	if err := cache.Save(); err != nil {
+		compileCacheLog.Printf("Failed to save compilation cache: %v", err)
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save compilation cache: %v", err)))
+		}
+	}
+}
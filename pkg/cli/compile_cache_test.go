@@ -0,0 +1,188 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCompileCacheRepo creates a git repository with a .github/aw directory and a workflow that
+// imports a shared file, and changes into it. It returns the repository root and the workflow path.
+func setupCompileCacheRepo(t *testing.T) (string, string) {
+	t.Helper()
+	gitRoot := testutil.GitWorkflowRepo(t, "compile-cache-*", map[string]string{
+		"shared/extra.md": "---\ntools:\n  bash: [\"echo\"]\n---\n\nShared instructions.\n",
+		"test.md":         "---\non: workflow_dispatch\nengine: copilot\nimports:\n  - shared/extra.md\n---\n\n# Test\n\nDo something.\n",
+	})
+	require.NoError(t, os.MkdirAll(filepath.Join(gitRoot, ".github", "aw"), 0o755), "should create .github/aw")
+	return gitRoot, filepath.Join(gitRoot, ".github", "workflows", "test.md")
+}
+
+func loadTestCompileCache(t *testing.T, gitRoot string, config CompileConfig) *CompileCache {
+	t.Helper()
+	compiler := createAndConfigureCompiler(config)
+	cache := NewCompileCache(gitRoot, compileCacheOptions(compiler, config))
+	require.NoError(t, cache.Load(), "cache should load")
+	return cache
+}
+
+func TestCompileCacheRecordsAndInvalidates(t *testing.T) {
+	gitRoot, workflowPath := setupCompileCacheRepo(t)
+	config := CompileConfig{}
+
+	_, err := CompileWorkflows(context.Background(), config)
+	require.NoError(t, err, "initial compilation should succeed")
+
+	cache := loadTestCompileCache(t, gitRoot, config)
+	entry, ok := cache.Entries[".github/workflows/test.md"]
+	require.True(t, ok, "compiled workflow should be cached")
+	assert.Equal(t, []string{".github/workflows/shared/extra.md"}, entry.Dependencies, "imports should be recorded as dependencies")
+	assert.False(t, entry.Validated, "compilation without --validate should not be marked validated")
+	assert.True(t, cache.IsFresh(workflowPath, false), "unchanged workflow should be fresh")
+	assert.False(t, cache.IsFresh(workflowPath, true), "--validate needs a validated entry")
+
+	otherOptions := loadTestCompileCache(t, gitRoot, CompileConfig{EngineOverride: "claude"})
+	assert.False(t, otherOptions.IsFresh(workflowPath, false), "different compiler options should miss")
+
+	// A second compilation reuses the cache and keeps the lock file
+	lockFile := filepath.Join(filepath.Dir(workflowPath), "test.lock.yml")
+	lockBefore, err := os.ReadFile(lockFile)
+	require.NoError(t, err, "lock file should exist")
+	_, err = CompileWorkflows(context.Background(), config)
+	require.NoError(t, err, "cached compilation should succeed")
+	lockAfter, err := os.ReadFile(lockFile)
+	require.NoError(t, err, "lock file should still exist")
+	assert.Equal(t, string(lockBefore), string(lockAfter), "cached compilation should not change the lock file")
+
+	// Changing only the body of an imported file invalidates the importer
+	sharedPath := filepath.Join(filepath.Dir(workflowPath), "shared", "extra.md")
+	require.NoError(t, os.WriteFile(sharedPath, []byte("---\ntools:\n  bash: [\"echo\"]\n---\n\nDifferent instructions.\n"), 0o644), "should update shared workflow")
+	assert.False(t, cache.IsFresh(workflowPath, false), "changed import should invalidate the workflow")
+
+	_, err = CompileWorkflows(context.Background(), config)
+	require.NoError(t, err, "recompilation should succeed")
+	cache = loadTestCompileCache(t, gitRoot, config)
+	assert.NotEqual(t, entry.Key, cache.Entries[".github/workflows/test.md"].Key, "changed import should be recompiled with a new key")
+	assert.True(t, cache.IsFresh(workflowPath, false), "recompiled workflow should be fresh")
+
+	// Editing the lock file by hand invalidates the entry
+	lockAfter, err = os.ReadFile(lockFile)
+	require.NoError(t, err, "lock file should still exist")
+	require.NoError(t, os.WriteFile(lockFile, append(lockAfter, []byte("# edited\n")...), 0o644), "should edit lock file")
+	assert.False(t, cache.IsFresh(workflowPath, false), "edited lock file should not be fresh")
+}
+
+func TestCompileCacheDisabled(t *testing.T) {
+	gitRoot, workflowPath := setupCompileCacheRepo(t)
+	compiler := workflow.NewCompiler()
+	workflowPaths := []string{workflowPath}
+
+	assert.Nil(t, newCompileCacheForConfig(compiler, CompileConfig{NoCache: true}, workflowPaths), "--no-cache should disable the cache")
+	assert.Nil(t, newCompileCacheForConfig(compiler, CompileConfig{ForceRefreshActionPins: true}, workflowPaths), "refreshing action pins should disable the cache")
+	assert.NotNil(t, newCompileCacheForConfig(compiler, CompileConfig{}, workflowPaths), "cache should be enabled by default")
+
+	require.NoError(t, os.Remove(filepath.Join(gitRoot, ".github", "aw")), "should remove .github/aw")
+	assert.Nil(t, newCompileCacheForConfig(compiler, CompileConfig{}, workflowPaths), "repositories without .github/aw should not be cached")
+	_, err := CompileWorkflows(context.Background(), CompileConfig{})
+	require.NoError(t, err, "compilation should succeed")
+	assert.NoDirExists(t, filepath.Join(".github", "aw"), "compilation should not create .github/aw for the cache")
+	require.NoError(t, os.Mkdir(filepath.Join(gitRoot, ".github", "aw"), 0o755), "should recreate .github/aw")
+
+	_, err = CompileWorkflows(context.Background(), CompileConfig{NoCache: true})
+	require.NoError(t, err, "compilation should succeed")
+	assert.NoFileExists(t, filepath.Join(".github", "aw", CompileCacheFileName), "--no-cache should not write the cache")
+}
+
+func TestCompileCheck(t *testing.T) {
+	gitRoot, workflowPath := setupCompileCacheRepo(t)
+	lockFile := filepath.Join(filepath.Dir(workflowPath), "test.lock.yml")
+	cachePath := filepath.Join(gitRoot, ".github", "aw", CompileCacheFileName)
+
+	_, err := CompileWorkflows(context.Background(), CompileConfig{Check: true})
+	require.Error(t, err, "missing lock file should fail the check")
+	assert.Contains(t, err.Error(), "1 lock file(s) are out of date", "error should count stale lock files")
+	assert.NoFileExists(t, lockFile, "--check should not write lock files")
+
+	_, err = CompileWorkflows(context.Background(), CompileConfig{})
+	require.NoError(t, err, "compilation should succeed")
+
+	_, err = CompileWorkflows(context.Background(), CompileConfig{Check: true})
+	require.NoError(t, err, "fresh lock files should pass the check")
+
+	// Without the cache, the check compiles in memory and compares
+	require.NoError(t, os.Remove(cachePath), "should remove the cache")
+	_, err = CompileWorkflows(context.Background(), CompileConfig{Check: true})
+	require.NoError(t, err, "fresh lock files should pass the check without a cache")
+	assert.NoFileExists(t, cachePath, "--check should not write the cache")
+
+	require.NoError(t, os.WriteFile(workflowPath, []byte("---\non: workflow_dispatch\nengine: copilot\n---\n\n# Test\n\nDo something else.\n"), 0o644), "should edit workflow")
+	_, err = CompileWorkflows(context.Background(), CompileConfig{Check: true})
+	require.Error(t, err, "edited workflow should fail the check")
+	assert.Contains(t, err.Error(), "Run 'gh aw compile'", "error should explain how to fix")
+
+	_, err = CompileWorkflows(context.Background(), CompileConfig{Check: true, NoEmit: true})
+	assert.Error(t, err, "--check cannot be combined with --no-emit")
+}
+
+func TestCompileCacheSkipsWorkflowsOutsideRepository(t *testing.T) {
+	gitRoot, workflowPath := setupCompileCacheRepo(t)
+	compiler := workflow.NewCompiler()
+
+	outsideDir := testutil.TempDir(t, "compile-cache-outside-*")
+	outsidePath := filepath.Join(outsideDir, "outside.md")
+	require.NoError(t, os.WriteFile(outsidePath, []byte("---\non: workflow_dispatch\nengine: copilot\n---\n\n# Outside\n"), 0o644), "should write workflow outside the repository")
+	assert.Nil(t, newCompileCacheForConfig(compiler, CompileConfig{}, []string{outsidePath}), "workflows outside any repository should not be cached")
+
+	cache := newCompileCacheForConfig(compiler, CompileConfig{}, []string{outsidePath, workflowPath})
+	require.NotNil(t, cache, "the repository of the first workflow in a repository should be used")
+	assert.Equal(t, filepath.Join(gitRoot, ".github", "aw", CompileCacheFileName), cache.GetCachePath(), "cache should be stored in the workflow's repository")
+
+	_, ok := cache.workflowKey(outsidePath)
+	assert.False(t, ok, "workflows outside the repository should have no cache key")
+	cache.Record(outsidePath, &workflow.WorkflowData{}, false, nil)
+	assert.Empty(t, cache.Entries, "workflows outside the repository should not be recorded")
+
+	key, ok := cache.workflowKey(workflowPath)
+	require.True(t, ok, "workflows in the repository should have a cache key")
+	assert.Equal(t, ".github/workflows/test.md", key, "cache keys should be repository-relative")
+}
+
+func TestCompileCacheReplaysWarnings(t *testing.T) {
+	gitRoot := setupFailOnWarningsRepo(t, "")
+	require.NoError(t, os.MkdirAll(filepath.Join(gitRoot, ".github", "aw"), 0o755), "should create .github/aw")
+	workflowPath := filepath.Join(gitRoot, ".github", "workflows", "deploy.md")
+	config := CompileConfig{}
+
+	compiler := createAndConfigureCompiler(config)
+	cache := newCompileCacheForConfig(compiler, config, []string{workflowPath})
+	require.NotNil(t, cache, "cache should be enabled")
+	first := compileWorkflowFileWithCache(compiler, cache, workflowPath, config, false)
+	require.True(t, first.success, "workflow should compile")
+	require.False(t, first.cached, "first compilation should not be cached")
+	require.Len(t, first.validationResult.Warnings, 1, "id-token warning should be reported")
+	saveCompileCache(cache, []string{workflowPath}, false)
+
+	// A new run loads the warnings from the saved cache
+	compiler = createAndConfigureCompiler(config)
+	cache = newCompileCacheForConfig(compiler, config, []string{workflowPath})
+	require.NotNil(t, cache, "cache should be enabled")
+	second := compileWorkflowFileWithCache(compiler, cache, workflowPath, config, false)
+	require.True(t, second.success, "cached workflow should succeed")
+	assert.True(t, second.cached, "second compilation should reuse the lock file")
+	assert.Equal(t, first.validationResult.Warnings, second.validationResult.Warnings, "cached workflow should report the same warnings")
+	assert.Equal(t, 1, compiler.GetWarningCount(), "replayed warnings should be counted")
+}
+
+func TestCompilerBuildID(t *testing.T) {
+	buildID := compilerBuildID()
+	require.NotEmpty(t, buildID, "test binary should be identified")
+	assert.Contains(t, compileCacheOptions(workflow.NewCompiler(), CompileConfig{}), "build="+buildID, "cache options should include the compiler build")
+}
@@ -0,0 +1,139 @@
+// This file provides `gh aw compile --check`, which verifies that every lock file is up to
+// date with its workflow without writing anything.
+//
+// Workflows whose lock files are fresh in the compilation cache are accepted without
+// compiling. The remaining workflows are compiled in memory and the generated YAML is
+// compared with the lock file on disk.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var compileCheckLog = logger.New("cli:compile_check")
+
+// checkLockFiles reports workflows whose lock files are missing or out of date
+func checkLockFiles(compiler *workflow.Compiler, config CompileConfig, workflowDir string) error {
+	files, err := resolveCheckFiles(config, workflowDir)
+	if err != nil {
+		return err
+	}
+	compileCheckLog.Printf("Checking %d workflow lock files", len(files))
+
+	// Compile in memory only; the action and compilation caches are not saved in check mode
+	compiler.SetNoEmit(true)
+	compiler.SetQuiet(true)
+	compileCache := newCompileCacheForConfig(compiler, config, files)
+
+	var validationResults []ValidationResult
+	var stale []string
+	var errorCount int
+	for _, file := range files {
+		lockFile := stringutil.MarkdownToLockFile(file)
+		result := ValidationResult{
+			Workflow: filepath.Base(file),
+			Valid:    true,
+			Errors:   []CompileValidationError{},
+			Warnings: []CompileValidationError{},
+		}
+
+		if compileCache != nil && compileCache.IsFresh(file, false) {
+			compileCheckLog.Printf("Lock file for %s is fresh in the compilation cache", file)
+			validationResults = append(validationResults, result)
+			continue
+		}
+
+		fileResult := compileWorkflowFile(
+			compiler, file, config.Verbose, config.JSONOutput,
+			true, false, false, false, // Compile in memory, no security tools
+			config.Strict, false,
+		)
+		switch {
+		case !fileResult.success:
+			errorCount++
+			result = fileResult.validationResult
+		case fileResult.workflowData == nil:
+			// Shared workflows have no lock file
+			result = fileResult.validationResult
+		default:
+			existing, err := os.ReadFile(lockFile)
+			if err != nil || string(existing) != compiler.GetLastLockFileContent() {
+				message := "lock file is out of date"
+				if err != nil {
+					message = "lock file is missing"
+				}
+				stale = append(stale, file)
+				result.Valid = false
+				result.Errors = append(result.Errors, CompileValidationError{
+					Type:    "stale_lock_file",
+					Message: fmt.Sprintf("%s: %s", console.ToRelativePath(lockFile), message),
+				})
+			}
+		}
+		validationResults = append(validationResults, result)
+	}
+
+	if config.JSONOutput {
+		jsonStr, err := formatValidationOutput(validationResults)
+		if err != nil {
+			return err
+		}
+		fmt.Println(jsonStr)
+	} else {
+		for _, result := range validationResults {
+			for _, verr := range result.Errors {
+				fmt.Fprintln(os.Stderr, console.FormatErrorMessage(verr.Message))
+			}
+		}
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("%d workflow(s) failed to compile", errorCount)
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("%d lock file(s) are out of date. Run 'gh aw compile' to update them", len(stale))
+	}
+	if !config.JSONOutput {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("All %d lock file(s) are up to date", len(files))))
+	}
+	return nil
+}
+
+// resolveCheckFiles returns the workflow files to check: the requested files, or every
+// workflow in the workflow directory
+func resolveCheckFiles(config CompileConfig, workflowDir string) ([]string, error) {
+	if len(config.MarkdownFiles) > 0 {
+		var files []string
+		for _, markdownFile := range config.MarkdownFiles {
+			resolvedFile, err := resolveWorkflowFile(markdownFile, config.Verbose)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, resolvedFile)
+		}
+		return files, nil
+	}
+
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		return nil, fmt.Errorf("compile --check without arguments requires being in a git repository: %w", err)
+	}
+	workflowsDir := filepath.Join(gitRoot, workflowDir)
+	mdFiles, err := filepath.Glob(filepath.Join(workflowsDir, "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find markdown files: %w", err)
+	}
+	mdFiles = filterWorkflowFiles(mdFiles)
+	if len(mdFiles) == 0 {
+		return nil, fmt.Errorf("no markdown files found in %s", workflowsDir)
+	}
+	return mdFiles, nil
+}
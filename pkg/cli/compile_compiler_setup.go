@@ -21,6 +21,7 @@
 //   - configureCompilerFlags() - Sets validation, strict mode, trial mode flags
 //   - setupActionMode() - Configures action script inlining mode
 //   - setupRepositoryContext() - Sets repository slug for schedule scattering
+//   - setupLintConfig() - Applies the rule severities from .github/aw/lint.yml
 //
 // These functions abstract compiler setup, allowing the main compile
 // orchestrator to focus on coordination while these handle configuration.
@@ -193,6 +194,23 @@ func setupRepositoryContext(compiler *workflow.Compiler) {
 	}
 }
 
+// setupLintConfig applies the repository's rule severities (.github/aw/lint.yml) to the
+// compiler warnings. Outside a git repository the defaults are kept.
+func setupLintConfig(compiler *workflow.Compiler) error {
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		compileCompilerSetupLog.Printf("Not in a git repository, using default rule severities: %v", err)
+		return nil
+	}
+	config, err := workflow.LoadLintConfig(gitRoot)
+	if err != nil {
+		return err
+	}
+	compiler.SetLintConfig(config)
+	compileCompilerSetupLog.Printf("Lint configuration loaded: %d rule severities", len(config.Rules))
+	return nil
+}
+
 // validateActionModeConfig validates the action mode configuration
 func validateActionModeConfig(actionMode string) error {
 	if actionMode == "" {
@@ -27,12 +27,18 @@ type CompileConfig struct {
 	ForceRefreshActionPins bool     // Force refresh of action pins by clearing cache and resolving from GitHub API
 	Zizmor                 bool     // Run zizmor security scanner on generated .lock.yml files
 	Poutine                bool     // Run poutine security scanner on generated .lock.yml files
+	Lint                   bool     // Run the built-in security linter on generated .lock.yml files
 	Actionlint             bool     // Run actionlint linter on generated .lock.yml files
 	JSONOutput             bool     // Output validation results as JSON
 	ActionMode             string   // Action script inlining mode: inline, dev, or release
 	ActionTag              string   // Override action SHA or tag for actions/setup (overrides action-mode to release)
 	Stats                  bool     // Display statistics table sorted by file size
 	FailFast               bool     // Stop at first error instead of collecting all errors
+	Check                  bool     // Report out-of-date lock files without writing anything
+	NoCache                bool     // Ignore the incremental compilation cache
+	Jobs                   int      // Number of workflows to compile concurrently (0 or 1: sequential)
+	SARIFFile              string   // Write all diagnostics to this file as a SARIF 2.1.0 log
+	FailOnWarnings         bool     // Fail when compilation reports warnings that are not suppressed
 }
 
 // WorkflowFailure represents a failed workflow with its error count
@@ -47,6 +53,7 @@ type CompilationStats struct {
 	Total           int
 	Errors          int
 	Warnings        int
+	Cached          int               // Workflows whose lock files were up to date in the compilation cache
 	FailedWorkflows []string          // Names of workflows that failed compilation (deprecated, use FailedWorkflowDetails)
 	FailureDetails  []WorkflowFailure // Detailed information about failed workflows
 }
@@ -54,16 +61,26 @@ type CompilationStats struct {
 // CompileValidationError represents a single validation error or warning
 type CompileValidationError struct {
 	Type    string `json:"type"`
+	RuleID  string `json:"rule_id,omitempty"` // Rule of a compiler warning (see .github/aw/lint.yml)
 	Message string `json:"message"`
 	Line    int    `json:"line,omitempty"`
 }
 
+// SuppressedWarning is a compiler warning accepted with a gh-aw-ignore comment
+type SuppressedWarning struct {
+	RuleID  string `json:"rule_id"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Line    int    `json:"line"` // Line of the gh-aw-ignore comment
+}
+
 // ValidationResult represents the validation result for a single workflow
 type ValidationResult struct {
 	Workflow     string                   `json:"workflow"`
 	Valid        bool                     `json:"valid"`
 	Errors       []CompileValidationError `json:"errors"`
 	Warnings     []CompileValidationError `json:"warnings"`
+	Suppressed   []SuppressedWarning      `json:"suppressed,omitempty"`
 	CompiledFile string                   `json:"compiled_file,omitempty"`
 }
 
@@ -87,11 +104,15 @@ func sanitizeValidationResults(results []ValidationResult) []ValidationResult {
 			Errors:       make([]CompileValidationError, len(result.Errors)),
 			Warnings:     make([]CompileValidationError, len(result.Warnings)),
 		}
+		if len(result.Suppressed) > 0 {
+			sanitized[i].Suppressed = make([]SuppressedWarning, len(result.Suppressed))
+		}
 
 		// Sanitize all error messages
 		for j, err := range result.Errors {
 			sanitized[i].Errors[j] = CompileValidationError{
 				Type:    err.Type,
+				RuleID:  err.RuleID,
 				Message: stringutil.SanitizeErrorMessage(err.Message),
 				Line:    err.Line,
 			}
@@ -101,10 +122,21 @@ func sanitizeValidationResults(results []ValidationResult) []ValidationResult {
 		for j, warn := range result.Warnings {
 			sanitized[i].Warnings[j] = CompileValidationError{
 				Type:    warn.Type,
+				RuleID:  warn.RuleID,
 				Message: stringutil.SanitizeErrorMessage(warn.Message),
 				Line:    warn.Line,
 			}
 		}
+
+		// Sanitize all suppressed warning messages
+		for j, suppressed := range result.Suppressed {
+			sanitized[i].Suppressed[j] = SuppressedWarning{
+				RuleID:  suppressed.RuleID,
+				Reason:  stringutil.SanitizeErrorMessage(suppressed.Reason),
+				Message: stringutil.SanitizeErrorMessage(suppressed.Message),
+				Line:    suppressed.Line,
+			}
+		}
 	}
 
 	return sanitized
@@ -0,0 +1,75 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const idTokenWorkflow = `---
+on: push
+engine: copilot
+%s
+permissions:
+  contents: read
+  id-token: write
+---
+
+# Deploy
+`
+
+func setupFailOnWarningsRepo(t *testing.T, suppression string) string {
+	t.Helper()
+	return testutil.GitWorkflowRepo(t, "compile-fail-on-warnings-*", map[string]string{
+		"deploy.md": fmt.Sprintf(idTokenWorkflow, suppression),
+	})
+}
+
+func TestCompileWorkflowsFailOnWarnings(t *testing.T) {
+	t.Run("unsuppressed warning fails", func(t *testing.T) {
+		setupFailOnWarningsRepo(t, "")
+		_, err := CompileWorkflows(context.Background(), CompileConfig{NoCache: true, FailOnWarnings: true})
+		require.Error(t, err, "warning should fail compilation")
+		assert.Contains(t, err.Error(), "--fail-on-warnings", "error should name the flag")
+	})
+
+	t.Run("suppressed warning passes", func(t *testing.T) {
+		setupFailOnWarningsRepo(t, "# gh-aw-ignore: id-token-write deploys to AWS with OIDC")
+		_, err := CompileWorkflows(context.Background(), CompileConfig{NoCache: true, FailOnWarnings: true})
+		require.NoError(t, err, "suppressed warning should not fail compilation")
+	})
+
+	t.Run("rule turned off passes", func(t *testing.T) {
+		gitRoot := setupFailOnWarningsRepo(t, "")
+		lintConfig := filepath.Join(gitRoot, ".github", "aw", workflow.LintConfigFileName)
+		require.NoError(t, os.MkdirAll(filepath.Dir(lintConfig), 0o755), "should create config directory")
+		require.NoError(t, os.WriteFile(lintConfig, []byte("rules:\n  id-token-write: off\n"), 0o644), "should write lint config")
+		_, err := CompileWorkflows(context.Background(), CompileConfig{NoCache: true, FailOnWarnings: true})
+		require.NoError(t, err, "warning of a rule turned off should not fail compilation")
+	})
+}
+
+func TestCompileWorkflowFileReportsSuppressedWarnings(t *testing.T) {
+	gitRoot := setupFailOnWarningsRepo(t, "# gh-aw-ignore: id-token-write deploys to AWS with OIDC")
+
+	compiler := workflow.NewCompiler()
+	result := compileWorkflowFile(compiler, filepath.Join(gitRoot, ".github", "workflows", "deploy.md"), false, true, true, false, false, false, false, false)
+	require.True(t, result.success, "workflow should compile")
+
+	assert.Empty(t, result.validationResult.Warnings, "suppressed warning should not be reported as a warning")
+	require.Len(t, result.validationResult.Suppressed, 1, "suppressed warning should be reported")
+	suppressed := result.validationResult.Suppressed[0]
+	assert.Equal(t, "id-token-write", suppressed.RuleID, "suppressed warning should carry its rule")
+	assert.Equal(t, "deploys to AWS with OIDC", suppressed.Reason, "suppressed warning should carry its reason")
+	assert.Equal(t, 4, suppressed.Line, "suppressed warning should point at its comment")
+	assert.Contains(t, suppressed.Message, "id-token: write", "suppressed warning should keep its message")
+}
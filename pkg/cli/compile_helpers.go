@@ -364,6 +364,9 @@ func printCompilationSummary(stats *CompilationStats) {
 
 	summary := fmt.Sprintf("Compiled %d workflow(s): %d error(s), %d warning(s)",
 		stats.Total, stats.Errors, stats.Warnings)
+	if stats.Cached > 0 {
+		summary += fmt.Sprintf(" (%d up to date)", stats.Cached)
+	}
 
 	// Use different formatting based on whether there were errors
 	if stats.Errors > 0 {
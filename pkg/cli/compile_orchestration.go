@@ -53,14 +53,35 @@ func compileSpecificFiles(
 	}
 
 	var workflowDataList []*workflow.WorkflowData
+	var processedFiles []string
 	var compiledCount int
 	var errorCount int
 	var errorMessages []string
 	var lockFilesForActionlint []string
 	var lockFilesForZizmor []string
+	var lockFilesForLint []string
 
-	// Compile each specified file
-	for _, markdownFile := range config.MarkdownFiles {
+	// Resolve workflow IDs or file paths to actual file paths
+	resolvedFiles := make([]string, len(config.MarkdownFiles))
+	resolveErrors := make([]error, len(config.MarkdownFiles))
+	var filesToCompile []string
+	for i, markdownFile := range config.MarkdownFiles {
+		compileOrchestrationLog.Printf("Resolving workflow file: %s", markdownFile)
+		resolvedFiles[i], resolveErrors[i] = resolveWorkflowFile(markdownFile, config.Verbose)
+		if resolveErrors[i] == nil {
+			compileOrchestrationLog.Printf("Resolved to: %s", resolvedFiles[i])
+			filesToCompile = append(filesToCompile, resolvedFiles[i])
+		}
+	}
+
+	// Load the incremental compilation cache (nil when caching is disabled)
+	compileCache := newCompileCacheForConfig(compiler, config, filesToCompile)
+
+	// Compile the resolved files (per-file security tools are disabled, they run in batch below)
+	fileResults := compileWorkflowFiles(compiler, compileCache, filesToCompile, config, shouldValidate)
+
+	// Collect results for each specified file
+	for i, markdownFile := range config.MarkdownFiles {
 		stats.Total++
 
 		// Initialize validation result
@@ -71,10 +92,7 @@ func compileSpecificFiles(
 			Warnings: []CompileValidationError{},
 		}
 
-		// Resolve workflow ID or file path to actual file path
-		compileOrchestrationLog.Printf("Resolving workflow file: %s", markdownFile)
-		resolvedFile, err := resolveWorkflowFile(markdownFile, config.Verbose)
-		if err != nil {
+		if err := resolveErrors[i]; err != nil {
 			// Don't print error here - it will be displayed in the compilation summary
 			// The error is stored in ValidationResult for JSON output and returned for main to display
 			errorMessages = append(errorMessages, err.Error())
@@ -86,20 +104,16 @@ func compileSpecificFiles(
 				Type:    "resolution_error",
 				Message: err.Error(),
 			})
+			recordWorkflowSARIFResults(markdownFile, result)
 			*validationResults = append(*validationResults, result)
 			continue
 		}
-		compileOrchestrationLog.Printf("Resolved to: %s", resolvedFile)
-
-		// Update result with resolved file name
-		result.Workflow = filepath.Base(resolvedFile)
-
-		// Compile regular workflow file (disable per-file security tools)
-		fileResult := compileWorkflowFile(
-			compiler, resolvedFile, config.Verbose, config.JSONOutput,
-			config.NoEmit, false, false, false, // Disable per-file security tools
-			config.Strict, shouldValidate,
-		)
+		resolvedFile := resolvedFiles[i]
+		fileResult := fileResults[0]
+		fileResults = fileResults[1:]
+		if fileResult.cached {
+			stats.Cached++
+		}
 
 		if !fileResult.success {
 			errorCount++
@@ -118,6 +132,7 @@ func compileSpecificFiles(
 		} else {
 			compiledCount++
 			workflowDataList = append(workflowDataList, fileResult.workflowData)
+			processedFiles = append(processedFiles, resolvedFile)
 
 			// Collect lock files for batch security tools
 			if !config.NoEmit && fileResult.lockFile != "" {
@@ -128,10 +143,14 @@ func compileSpecificFiles(
 					if config.Zizmor {
 						lockFilesForZizmor = append(lockFilesForZizmor, fileResult.lockFile)
 					}
+					if config.Lint {
+						lockFilesForLint = append(lockFilesForLint, fileResult.lockFile)
+					}
 				}
 			}
 		}
 
+		recordWorkflowSARIFResults(resolvedFile, fileResult.validationResult)
 		*validationResults = append(*validationResults, fileResult.validationResult)
 	}
 
@@ -153,6 +172,15 @@ func compileSpecificFiles(
 		}
 	}
 
+	// Run the built-in security linter on all collected lock files
+	if config.Lint && !config.NoEmit && len(lockFilesForLint) > 0 {
+		if err := runBatchSecurityLint(lockFilesForLint, config.Verbose && !config.JSONOutput, config.Strict); err != nil {
+			if config.Strict {
+				return workflowDataList, err
+			}
+		}
+	}
+
 	// Run batch poutine once on the workflow directory
 	// Get the directory from the first lock file (all should be in same directory)
 	if config.Poutine && !config.NoEmit && len(lockFilesForZizmor) > 0 {
@@ -175,8 +203,11 @@ func compileSpecificFiles(
 		return workflowDataList, err
 	}
 
+	// Save the compilation cache after the action cache, whose pins are part of the keys
+	saveCompileCache(compileCache, processedFiles, config.Verbose)
+
 	// Output results
-	if err := outputResults(stats, validationResults, config); err != nil {
+	if err := outputResults(stats, validationResults, config, config.MarkdownFiles); err != nil {
 		return workflowDataList, err
 	}
 
@@ -188,7 +219,7 @@ func compileSpecificFiles(
 		return workflowDataList, fmt.Errorf("compilation failed")
 	}
 
-	return workflowDataList, nil
+	return workflowDataList, checkFailOnWarnings(config, stats)
 }
 
 // compileAllFilesInDirectory compiles all workflow files in a directory
@@ -248,22 +279,28 @@ func compileAllFilesInDirectory(
 		compileOrchestrationLog.Print("Automatically enabling action SHA validation due to --force-refresh-action-pins")
 	}
 
+	// Load the incremental compilation cache (nil when caching is disabled)
+	compileCache := newCompileCacheForConfig(compiler, config, mdFiles)
+
 	// Compile each file
 	var workflowDataList []*workflow.WorkflowData
+	var processedFiles []string
 	var successCount int
 	var errorCount int
 	var lockFilesForActionlint []string
 	var lockFilesForZizmor []string
+	var lockFilesForLint []string
 
-	for _, file := range mdFiles {
+	// Compile regular workflow files (per-file security tools are disabled, they run in batch below)
+	fileResults := compileWorkflowFiles(compiler, compileCache, mdFiles, config, shouldValidate)
+
+	for i, file := range mdFiles {
 		stats.Total++
 
-		// Compile regular workflow file (disable per-file security tools)
-		fileResult := compileWorkflowFile(
-			compiler, file, config.Verbose, config.JSONOutput,
-			config.NoEmit, false, false, false, // Disable per-file security tools
-			config.Strict, shouldValidate,
-		)
+		fileResult := fileResults[i]
+		if fileResult.cached {
+			stats.Cached++
+		}
 
 		if !fileResult.success {
 			errorCount++
@@ -277,6 +314,7 @@ func compileAllFilesInDirectory(
 		} else {
 			successCount++
 			workflowDataList = append(workflowDataList, fileResult.workflowData)
+			processedFiles = append(processedFiles, file)
 
 			// Collect lock files for batch security tools
 			if !config.NoEmit && fileResult.lockFile != "" {
@@ -287,10 +325,14 @@ func compileAllFilesInDirectory(
 					if config.Zizmor {
 						lockFilesForZizmor = append(lockFilesForZizmor, fileResult.lockFile)
 					}
+					if config.Lint {
+						lockFilesForLint = append(lockFilesForLint, fileResult.lockFile)
+					}
 				}
 			}
 		}
 
+		recordWorkflowSARIFResults(file, fileResult.validationResult)
 		*validationResults = append(*validationResults, fileResult.validationResult)
 	}
 
@@ -312,6 +354,15 @@ func compileAllFilesInDirectory(
 		}
 	}
 
+	// Run the built-in security linter on all collected lock files
+	if config.Lint && !config.NoEmit && len(lockFilesForLint) > 0 {
+		if err := runBatchSecurityLint(lockFilesForLint, config.Verbose && !config.JSONOutput, config.Strict); err != nil {
+			if config.Strict {
+				return workflowDataList, err
+			}
+		}
+	}
+
 	// Run batch poutine once on the workflow directory
 	if config.Poutine && !config.NoEmit && len(lockFilesForZizmor) > 0 {
 		if err := runBatchPoutine(workflowsDir, config.Verbose && !config.JSONOutput, config.Strict); err != nil {
@@ -341,8 +392,11 @@ func compileAllFilesInDirectory(
 		return workflowDataList, err
 	}
 
+	// Save the compilation cache after the action cache, whose pins are part of the keys
+	saveCompileCache(compileCache, processedFiles, config.Verbose)
+
 	// Output results
-	if err := outputResults(stats, validationResults, config); err != nil {
+	if err := outputResults(stats, validationResults, config, mdFiles); err != nil {
 		return workflowDataList, err
 	}
 
@@ -351,7 +405,16 @@ func compileAllFilesInDirectory(
 		return workflowDataList, fmt.Errorf("compilation failed")
 	}
 
-	return workflowDataList, nil
+	return workflowDataList, checkFailOnWarnings(config, stats)
+}
+
+// checkFailOnWarnings returns an error when --fail-on-warnings is set and compilation reported
+// warnings. Warnings whose rules are turned off or suppressed are not counted.
+func checkFailOnWarnings(config CompileConfig, stats *CompilationStats) error {
+	if !config.FailOnWarnings || stats.Warnings == 0 {
+		return nil
+	}
+	return fmt.Errorf("compilation reported %d warning(s) and --fail-on-warnings is set - fix them, accept them with a '# gh-aw-ignore: <rule-id> <reason>' frontmatter comment, or turn their rules off in .github/aw/%s", stats.Warnings, workflow.LintConfigFileName)
 }
 
 // purgeTrackingData holds data needed for purge operations
@@ -485,19 +548,17 @@ func runPostProcessingForDirectory(
 	return nil
 }
 
-// outputResults outputs compilation results in the requested format
+// outputResults outputs compilation results in the requested format.
+// markdownFiles lists the compiled workflows reported by --stats.
 func outputResults(
 	stats *CompilationStats,
 	validationResults *[]ValidationResult,
 	config CompileConfig,
+	markdownFiles []string,
 ) error {
 	// Collect and display stats if requested
 	if config.Stats && !config.NoEmit && !config.JSONOutput {
-		var statsList []*WorkflowStats
-		if len(config.MarkdownFiles) > 0 {
-			statsList = collectWorkflowStatisticsWrapper(config.MarkdownFiles)
-		}
-		formatStatsTable(statsList)
+		formatStatsTable(collectWorkflowStatisticsWrapper(markdownFiles))
 	}
 
 	// Output JSON if requested
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -59,6 +60,9 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 
 	// Create and configure compiler
 	compiler := createAndConfigureCompiler(config)
+	if err := setupLintConfig(compiler); err != nil {
+		return nil, err
+	}
 
 	// Handle watch mode (early return)
 	if config.Watch {
@@ -80,12 +84,35 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 		return nil, watchAndCompileWorkflows(markdownFile, compiler, config.Verbose)
 	}
 
+	// Handle check mode (early return): report stale lock files without writing
+	if config.Check {
+		return nil, checkLockFiles(compiler, config, workflowDir)
+	}
+
+	// Collect diagnostics for the SARIF log, which is written even when compilation fails
+	if config.SARIFFile != "" {
+		initSARIFReport()
+		defer func() { sarifReport = nil }()
+	}
+
 	// Compile specific files or all files in directory
+	var workflowDataList []*workflow.WorkflowData
+	var err error
 	if len(config.MarkdownFiles) > 0 {
 		// Compile specific workflow files
-		return compileSpecificFiles(compiler, config, stats, &validationResults)
+		workflowDataList, err = compileSpecificFiles(compiler, config, stats, &validationResults)
+	} else {
+		// Compile all workflow files in directory
+		workflowDataList, err = compileAllFilesInDirectory(compiler, config, workflowDir, stats, &validationResults)
 	}
 
-	// Compile all workflow files in directory
-	return compileAllFilesInDirectory(compiler, config, workflowDir, stats, &validationResults)
+	if config.SARIFFile != "" {
+		if sarifErr := writeSARIFReport(config.SARIFFile); sarifErr != nil {
+			return workflowDataList, errors.Join(err, sarifErr)
+		}
+		if !config.JSONOutput {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("SARIF log written to "+config.SARIFFile))
+		}
+	}
+	return workflowDataList, err
 }
@@ -0,0 +1,102 @@
+// This file provides concurrent compilation of independent workflows (compile --jobs N).
+//
+// Each workflow is compiled by a fork of the main compiler. Forks share the action pin
+// cache, action resolver and import cache, which are safe for concurrent use, but keep
+// their own per-workflow state. Warnings and progress messages are buffered per workflow
+// and printed in input order once all workflows are compiled, so the output does not
+// depend on scheduling.
+
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/sourcegraph/conc/pool"
+)
+
+var compileParallelLog = logger.New("cli:compile_parallel")
+
+// compileWorkflowFiles compiles workflow files, concurrently when config.Jobs > 1, and
+// returns the results in the order of files
+func compileWorkflowFiles(
+	compiler *workflow.Compiler,
+	cache *CompileCache,
+	files []string,
+	config CompileConfig,
+	validate bool,
+) []compileWorkflowFileResult {
+	results := make([]compileWorkflowFileResult, len(files))
+
+	jobs := min(config.Jobs, len(files))
+	if jobs <= 1 {
+		for i, file := range files {
+			results[i] = compileWorkflowFileWithCache(compiler, cache, file, config, validate)
+		}
+		return results
+	}
+
+	compileParallelLog.Printf("Compiling %d workflow files with %d jobs", len(files), jobs)
+
+	forks := make([]*workflow.Compiler, len(files))
+	outputs := make([]workflowDiagnostics, len(files))
+	for i := range files {
+		forks[i] = compiler.Fork()
+		forks[i].SetDiagnosticWriter(&outputs[i])
+	}
+
+	p := pool.New().WithMaxGoroutines(jobs)
+	for i, file := range files {
+		p.Go(func() {
+			results[i] = compileWorkflowFileWithCache(forks[i], cache, file, config, validate)
+		})
+	}
+	p.Wait()
+
+	// Emit diagnostics in input order. Action pin warnings are printed once, for the first
+	// workflow that needs the pin, as in sequential compilation.
+	warnedPins := make(map[string]bool)
+	for i := range files {
+		outputs[i].writeTo(os.Stderr, warnedPins)
+		compiler.MergeForkDiagnostics(forks[i])
+	}
+
+	return results
+}
+
+// workflowDiagnostics buffers the messages printed while compiling one workflow
+type workflowDiagnostics struct {
+	segments []diagnosticSegment
+}
+
+// diagnosticSegment is a chunk of buffered output; pinKey is set for action pin warnings
+type diagnosticSegment struct {
+	text   string
+	pinKey string
+}
+
+// Write implements io.Writer
+func (d *workflowDiagnostics) Write(p []byte) (int, error) {
+	d.segments = append(d.segments, diagnosticSegment{text: string(p)})
+	return len(p), nil
+}
+
+// WriteActionPinWarning implements workflow.ActionPinWarningWriter
+func (d *workflowDiagnostics) WriteActionPinWarning(key string, text string) {
+	d.segments = append(d.segments, diagnosticSegment{text: text, pinKey: key})
+}
+
+// writeTo writes the buffered output, skipping action pin warnings already in warnedPins
+func (d *workflowDiagnostics) writeTo(w io.Writer, warnedPins map[string]bool) {
+	for _, segment := range d.segments {
+		if segment.pinKey != "" {
+			if warnedPins[segment.pinKey] {
+				continue
+			}
+			warnedPins[segment.pinKey] = true
+		}
+		_, _ = io.WriteString(w, segment.text)
+	}
+}
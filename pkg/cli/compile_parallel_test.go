@@ -0,0 +1,102 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupParallelCompileRepo creates a git repository with several independent workflows and
+// changes into it. It returns the workflow paths in a stable order.
+func setupParallelCompileRepo(t *testing.T) []string {
+	t.Helper()
+	workflows := make(map[string]string)
+	for i := range 6 {
+		workflows[fmt.Sprintf("workflow-%d.md", i)] = fmt.Sprintf("---\non:\n  schedule: daily\nengine: copilot\n---\n\n# Workflow %d\n\nDo task %d.\n", i, i)
+	}
+	gitRoot := testutil.GitWorkflowRepo(t, "compile-parallel-*", workflows)
+
+	var files []string
+	for i := range 6 {
+		files = append(files, filepath.Join(gitRoot, ".github", "workflows", fmt.Sprintf("workflow-%d.md", i)))
+	}
+	return files
+}
+
+// readLockFiles returns the lock file contents for the given workflows and removes them
+func readLockFiles(t *testing.T, files []string) []string {
+	t.Helper()
+	var contents []string
+	for _, file := range files {
+		lockFile := strings.TrimSuffix(file, ".md") + ".lock.yml"
+		content, err := os.ReadFile(lockFile)
+		require.NoError(t, err, "lock file should exist for %s", file)
+		contents = append(contents, string(content))
+		require.NoError(t, os.Remove(lockFile), "should remove lock file")
+	}
+	return contents
+}
+
+func TestCompileWorkflowFilesParallelMatchesSequential(t *testing.T) {
+	files := setupParallelCompileRepo(t)
+
+	sequentialConfig := CompileConfig{NoCache: true}
+	sequentialCompiler := createAndConfigureCompiler(sequentialConfig)
+	sequentialResults := compileWorkflowFiles(sequentialCompiler, nil, files, sequentialConfig, false)
+	sequentialLocks := readLockFiles(t, files)
+
+	parallelConfig := CompileConfig{NoCache: true, Jobs: 4}
+	parallelCompiler := createAndConfigureCompiler(parallelConfig)
+	parallelResults := compileWorkflowFiles(parallelCompiler, nil, files, parallelConfig, false)
+	parallelLocks := readLockFiles(t, files)
+
+	require.Len(t, parallelResults, len(files), "should return one result per workflow")
+	for i, result := range parallelResults {
+		assert.True(t, result.success, "workflow %d should compile", i)
+		assert.Equal(t, sequentialResults[i].lockFile, result.lockFile, "results should be in input order")
+		assert.Equal(t, sequentialResults[i].validationResult, result.validationResult, "validation results should match")
+	}
+	assert.Equal(t, sequentialLocks, parallelLocks, "parallel compilation should generate the same lock files")
+	assert.Equal(t, sequentialCompiler.GetWarningCount(), parallelCompiler.GetWarningCount(), "fork warnings should be merged")
+	assert.Equal(t, sequentialCompiler.GetScheduleWarnings(), parallelCompiler.GetScheduleWarnings(), "fork schedule warnings should be merged in order")
+}
+
+func TestCompileWorkflowsWithJobs(t *testing.T) {
+	files := setupParallelCompileRepo(t)
+
+	workflowData, err := CompileWorkflows(context.Background(), CompileConfig{NoCache: true, Jobs: 3})
+	require.NoError(t, err, "parallel compilation should succeed")
+	assert.Len(t, workflowData, len(files), "should return data for every workflow")
+	for _, file := range files {
+		assert.FileExists(t, strings.TrimSuffix(file, ".md")+".lock.yml", "lock file should be written")
+	}
+
+	_, err = CompileWorkflows(context.Background(), CompileConfig{NoCache: true, Jobs: -1})
+	require.Error(t, err, "negative jobs should be rejected")
+	assert.Contains(t, err.Error(), "--jobs cannot be negative", "error should name the flag")
+}
+
+func TestWorkflowDiagnosticsDeduplicatesActionPinWarnings(t *testing.T) {
+	var first, second workflowDiagnostics
+	_, _ = fmt.Fprintln(&first, "first workflow")
+	first.WriteActionPinWarning("actions/checkout@v6", "pin warning\n")
+	_, _ = fmt.Fprintln(&second, "second workflow")
+	second.WriteActionPinWarning("actions/checkout@v6", "pin warning\n")
+	second.WriteActionPinWarning("actions/setup-node@v6", "other pin warning\n")
+
+	var out strings.Builder
+	warnedPins := make(map[string]bool)
+	first.writeTo(&out, warnedPins)
+	second.writeTo(&out, warnedPins)
+
+	assert.Equal(t, "first workflow\npin warning\nsecond workflow\nother pin warning\n", out.String(), "each pin warning should be printed once, in input order")
+}
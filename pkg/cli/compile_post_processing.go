@@ -32,6 +32,7 @@ import (
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/stringutil"
 	"github.com/github/gh-aw/pkg/workflow"
 )
@@ -80,18 +81,33 @@ func generateMaintenanceWorkflowWrapper(
 	return nil
 }
 
-// collectWorkflowStatisticsWrapper collects and returns workflow statistics
+// collectWorkflowStatisticsWrapper collects and returns workflow statistics, checking each
+// lock file against the repository budget policy and the workflow's lock-budget frontmatter
 func collectWorkflowStatisticsWrapper(markdownFiles []string) []*WorkflowStats {
 	compilePostProcessingLog.Printf("Collecting workflow statistics for %d files", len(markdownFiles))
 
+	var policy LockBudget
+	var importCache *parser.ImportCache
+	if gitRoot, err := findGitRoot(); err == nil {
+		importCache = parser.NewImportCache(gitRoot)
+		if policy, err = loadLockBudgetPolicy(gitRoot); err != nil {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(err.Error()))
+		}
+	}
+
 	var statsList []*WorkflowStats
 	for _, file := range markdownFiles {
 		resolvedFile, err := resolveWorkflowFile(file, false)
 		if err != nil {
 			continue // Skip files that couldn't be resolved
 		}
+		budget, importedSteps, err := workflowBudgetInputs(resolvedFile, policy, importCache)
+		if err != nil {
+			compilePostProcessingLog.Printf("Using the policy budget for %s: %v", resolvedFile, err)
+		}
 		lockFile := stringutil.MarkdownToLockFile(resolvedFile)
-		if workflowStats, err := collectWorkflowStats(lockFile); err == nil {
+		if workflowStats, err := collectWorkflowStatsWithBudget(lockFile, budget, importedSteps); err == nil {
+			estimateWorkflowPrompt(workflowStats, resolvedFile)
 			statsList = append(statsList, workflowStats)
 		}
 	}
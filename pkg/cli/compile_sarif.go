@@ -0,0 +1,318 @@
+// This file collects compile and validation diagnostics into a SARIF 2.1.0 log
+// (gh aw compile --sarif <file>) for upload to GitHub code scanning.
+//
+// The log has a single run of the gh-aw tool. It covers compile errors of each workflow
+// (schema errors, strict-mode violations, expression-safety and template-injection
+// findings), compiler warnings under their rule IDs (including markdown security scanner
+// findings), and the results of the security linter, actionlint and zizmor when they are
+// enabled. Compile errors are classified into rules from their messages, and their
+// locations are read from the file:line:column prefix of the formatted error. Warnings
+// accepted with a gh-aw-ignore comment are reported as suppressed in source.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var compileSARIFLog = logger.New("cli:compile_sarif")
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Rule IDs for compile errors
+const (
+	SARIFRuleSchemaValidation  = "schema-validation"
+	SARIFRuleStrictMode        = "strict-mode"
+	SARIFRuleExpressionSafety  = "expression-safety"
+	SARIFRuleTemplateInjection = "template-injection"
+	SARIFRuleParseError        = "parse-error"
+	SARIFRuleCompileError      = "compile-error"
+)
+
+// sarifRuleDescriptions describes the fixed compile error rules
+var sarifRuleDescriptions = map[string]string{
+	SARIFRuleSchemaValidation:  "Frontmatter does not match the workflow schema",
+	SARIFRuleStrictMode:        "Workflow violates strict mode",
+	SARIFRuleExpressionSafety:  "Workflow uses expressions that are not allowed",
+	SARIFRuleTemplateInjection: "Untrusted expression used directly in a shell command",
+	SARIFRuleParseError:        "Workflow could not be parsed",
+	SARIFRuleCompileError:      "Workflow could not be compiled",
+}
+
+// SARIF 2.1.0 log format (subset used by GitHub code scanning)
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SARIFReport collects diagnostics for the SARIF log
+type SARIFReport struct {
+	mu      sync.Mutex
+	baseDir string // Directory that artifact URIs are relative to (the repository root)
+	rules   map[string]sarifRule
+	results []sarifResult
+}
+
+// sarifReport collects diagnostics when compile runs with --sarif, nil otherwise
+var sarifReport *SARIFReport
+
+// initSARIFReport starts collecting diagnostics for the SARIF log
+func initSARIFReport() {
+	baseDir, err := findGitRoot()
+	if err != nil {
+		baseDir, _ = os.Getwd()
+	}
+	sarifReport = &SARIFReport{baseDir: baseDir, rules: make(map[string]sarifRule)}
+}
+
+// recordSARIFResult adds a diagnostic to the SARIF log if one is being collected.
+// level is "error", "warning" or "note"; line and column are 1-based, 0 if unknown.
+func recordSARIFResult(ruleID, description, helpURI, level, message, file string, line, column int) {
+	if sarifReport == nil {
+		return
+	}
+	sarifReport.add(ruleID, description, helpURI, level, message, file, line, column, nil)
+}
+
+// recordSARIFSuppressedResult adds a warning accepted with a gh-aw-ignore comment to the SARIF
+// log, marked as suppressed in source with the comment's reason
+func recordSARIFSuppressedResult(ruleID, description, message, reason, file string, line int) {
+	if sarifReport == nil {
+		return
+	}
+	sarifReport.add(ruleID, description, "", "warning", message, file, line, 0,
+		[]sarifSuppression{{Kind: "inSource", Justification: reason}})
+}
+
+func (r *SARIFReport) add(ruleID, description, helpURI, level, message, file string, line, column int, suppressions []sarifSuppression) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rules[ruleID]; !exists {
+		r.rules[ruleID] = sarifRule{ID: ruleID, ShortDescription: sarifMessage{Text: description}, HelpURI: helpURI}
+	}
+	r.results = append(r.results, sarifResult{
+		RuleID:  ruleID,
+		Level:   level,
+		Message: sarifMessage{Text: message},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: r.artifactURI(file)},
+				// Results without a known line are reported on the first line of the file
+				Region: sarifRegion{StartLine: max(line, 1), StartColumn: column},
+			},
+		}},
+		Suppressions: suppressions,
+	})
+}
+
+// artifactURI returns the path of a file relative to the repository root, with forward slashes
+func (r *SARIFReport) artifactURI(file string) string {
+	if filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(r.baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	} else if abs, err := filepath.Abs(file); err == nil {
+		if rel, err := filepath.Rel(r.baseDir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// log returns the collected diagnostics as a SARIF log with rules sorted by ID
+func (r *SARIFReport) log() sarifLog {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := make([]sarifRule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	results := r.results
+	if results == nil {
+		results = []sarifResult{}
+	}
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gh-aw",
+				Version:        GetVersion(),
+				InformationURI: "https://github.com/github/gh-aw",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// writeSARIFReport writes the collected diagnostics to path
+func writeSARIFReport(path string) error {
+	if sarifReport == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(sarifReport.log(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF log: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write SARIF log: %w", err)
+	}
+	compileSARIFLog.Printf("Wrote SARIF log with %d result(s) to %s", len(sarifReport.results), path)
+	return nil
+}
+
+// recordWorkflowSARIFResults adds the compile errors, warnings and suppressed warnings of a
+// workflow to the SARIF log
+func recordWorkflowSARIFResults(markdownFile string, result ValidationResult) {
+	if sarifReport == nil {
+		return
+	}
+	for _, verr := range result.Errors {
+		recordCompileDiagnostic(markdownFile, verr, "error")
+	}
+	for _, warning := range result.Warnings {
+		switch {
+		case warning.Type == "shared_workflow":
+			continue
+		case warning.RuleID != "":
+			rule := workflow.DiagnosticRule(warning.RuleID)
+			recordSARIFResult(warning.RuleID, workflow.DiagnosticRuleDescription(rule), "", "warning", warning.Message, markdownFile, warning.Line, 0)
+		default:
+			recordCompileDiagnostic(markdownFile, warning, "warning")
+		}
+	}
+	for _, suppressed := range result.Suppressed {
+		rule := workflow.DiagnosticRule(suppressed.RuleID)
+		recordSARIFSuppressedResult(suppressed.RuleID, workflow.DiagnosticRuleDescription(rule), suppressed.Message, suppressed.Reason, markdownFile, suppressed.Line)
+	}
+}
+
+// compileErrorPositionPattern matches the file:line:column: severity: prefix of formatted errors
+var compileErrorPositionPattern = regexp.MustCompile(`^(.+?):(\d+):(\d+): (?:error|warning): `)
+
+// recordCompileDiagnostic adds one compile error or warning to the SARIF log
+func recordCompileDiagnostic(markdownFile string, verr CompileValidationError, level string) {
+	message := stringutil.StripANSIEscapeCodes(verr.Message)
+	line, column := verr.Line, 0
+	if match := compileErrorPositionPattern.FindStringSubmatch(message); match != nil {
+		line, _ = strconv.Atoi(match[2])
+		column, _ = strconv.Atoi(match[3])
+		message = message[len(match[0]):]
+	}
+	message = stripErrorContext(message)
+
+	ruleID := classifyCompileError(verr.Type, message)
+	recordSARIFResult(ruleID, sarifRuleDescriptions[ruleID], "", level, message, markdownFile, line, column)
+}
+
+// classifyCompileError returns the rule of a compile error from its type and message
+func classifyCompileError(errorType, message string) string {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "template injection"):
+		return SARIFRuleTemplateInjection
+	case strings.Contains(lower, "unauthorized expressions"):
+		return SARIFRuleExpressionSafety
+	case strings.Contains(lower, "strict mode"):
+		return SARIFRuleStrictMode
+	case strings.Contains(message, "at '/") || strings.Contains(message, "Unknown propert"):
+		return SARIFRuleSchemaValidation
+	case errorType == "parse_error":
+		return SARIFRuleParseError
+	default:
+		return SARIFRuleCompileError
+	}
+}
+
+// errorContextLinePattern matches the source lines and markers that formatted errors show
+// below the message
+var errorContextLinePattern = regexp.MustCompile(`^\s*>?\s*\d+ \| |^\s*\^+\s*$`)
+
+// stripErrorContext removes source context lines from a formatted error message
+func stripErrorContext(message string) string {
+	var lines []string
+	for line := range strings.SplitSeq(message, "\n") {
+		if !errorContextLinePattern.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,94 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyCompileError(t *testing.T) {
+	tests := []struct {
+		name      string
+		errorType string
+		message   string
+		want      string
+	}{
+		{"template injection", "compilation_error", "template injection vulnerabilities detected in compiled workflow", SARIFRuleTemplateInjection},
+		{"expression safety", "compilation_error", "Validation failed for field 'expressions'\n\nValue: 1 unauthorized expressions found", SARIFRuleExpressionSafety},
+		{"strict mode", "parse_error", "strict mode: write permission 'contents: write' is not allowed", SARIFRuleStrictMode},
+		{"schema", "parse_error", "at '/timeout-minutes': got string, want integer", SARIFRuleSchemaValidation},
+		{"unknown property", "parse_error", "Unknown property: bogus-field", SARIFRuleSchemaValidation},
+		{"yaml syntax", "parse_error", "failed to extract frontmatter: failed to parse frontmatter", SARIFRuleParseError},
+		{"other", "compilation_error", "failed to write lock file", SARIFRuleCompileError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyCompileError(tt.errorType, tt.message), "error should be classified by its message")
+		})
+	}
+}
+
+func TestRecordCompileDiagnostic(t *testing.T) {
+	sarifReport = &SARIFReport{baseDir: "/repo", rules: make(map[string]sarifRule)}
+	t.Cleanup(func() { sarifReport = nil })
+
+	recordCompileDiagnostic("/repo/.github/workflows/test.md", CompileValidationError{
+		Type:    "parse_error",
+		Message: ".github/workflows/test.md:3:17: error: at '/timeout-minutes': got string, want integer\n2 | on: push\n3 | timeout-minutes: abc\n                    ^^^\n4 | ---\n",
+	}, "error")
+
+	log := sarifReport.log()
+	require.Len(t, log.Runs, 1, "log should have one run")
+	require.Len(t, log.Runs[0].Results, 1, "diagnostic should be recorded")
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, SARIFRuleSchemaValidation, result.RuleID, "result should carry its rule")
+	assert.Equal(t, "error", result.Level, "result should carry its level")
+	assert.Equal(t, "at '/timeout-minutes': got string, want integer", result.Message.Text, "position prefix and context lines should be removed")
+	location := result.Locations[0].PhysicalLocation
+	assert.Equal(t, ".github/workflows/test.md", location.ArtifactLocation.URI, "URI should be relative to the repository root")
+	assert.Equal(t, sarifRegion{StartLine: 3, StartColumn: 17}, location.Region, "region should come from the error position")
+	require.Len(t, log.Runs[0].Tool.Driver.Rules, 1, "rule should be described")
+	assert.NotEmpty(t, log.Runs[0].Tool.Driver.Rules[0].ShortDescription.Text, "rule should have a description")
+}
+
+func TestCompileWorkflowsWithSARIF(t *testing.T) {
+	gitRoot := testutil.GitWorkflowRepo(t, "compile-sarif-*", map[string]string{
+		"invalid.md": "---\non: push\ntimeout-minutes: abc\n---\n\n# Invalid\n",
+		"hidden.md":  "---\non: push\nengine: copilot\n---\n\n# Hidden\n\nHello\u200Bworld\n",
+	})
+	lintConfig := filepath.Join(gitRoot, ".github", "aw", workflow.LintConfigFileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(lintConfig), 0o755), "should create config directory")
+	require.NoError(t, os.WriteFile(lintConfig, []byte("rules:\n  markdown-security/unicode-abuse: warn\n"), 0o644), "should turn on the markdown security rule")
+
+	sarifPath := filepath.Join(gitRoot, "results.sarif")
+	_, err := CompileWorkflows(context.Background(), CompileConfig{NoCache: true, SARIFFile: sarifPath, Lint: true})
+	require.Error(t, err, "invalid workflow should fail compilation")
+
+	content, err := os.ReadFile(sarifPath)
+	require.NoError(t, err, "SARIF log should be written even when compilation fails")
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(content, &log), "SARIF log should be valid JSON")
+	assert.Equal(t, "2.1.0", log.Version, "log should use SARIF 2.1.0")
+	require.Len(t, log.Runs, 1, "log should have one run")
+
+	results := make(map[string]string)
+	for _, result := range log.Runs[0].Results {
+		results[result.RuleID] = result.Locations[0].PhysicalLocation.ArtifactLocation.URI
+	}
+	assert.Equal(t, ".github/workflows/invalid.md", results[SARIFRuleSchemaValidation], "schema error should be reported on the workflow")
+	assert.Equal(t, ".github/workflows/hidden.md", results["markdown-security/unicode-abuse"], "enabled markdown security findings should be reported")
+	assert.Nil(t, sarifReport, "collection should stop after the compilation")
+
+	_, err = CompileWorkflows(context.Background(), CompileConfig{NoCache: true, SARIFFile: sarifPath, Check: true})
+	require.Error(t, err, "--sarif should not be combined with --check")
+}
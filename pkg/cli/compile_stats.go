@@ -5,11 +5,13 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/styles"
 	"github.com/github/gh-aw/pkg/tty"
+	"github.com/github/gh-aw/pkg/workflow"
 	"github.com/goccy/go-yaml"
 )
 
@@ -25,10 +27,27 @@ type WorkflowStats struct {
 	ScriptSize  int
 	ShellCount  int
 	ShellSize   int
+	LongestLine int          // Length of the longest line, checked against the expression size limit
+	Sources     []SizeSource // Bytes attributed to each source, largest first
+	Budget      LockBudget   // Size budget the lock file is checked against
+	// BudgetWarnings and Suggestions are set when the lock file approaches its budget
+	BudgetWarnings []string
+	Suggestions    []string
+	// Prompt size estimate from the content known at compile time, 0 if the prompt could not be rendered
+	PromptTokens    int
+	PromptMaxTokens int // prompt.max-tokens, 0 if unset
+	PromptSources   []workflow.PromptSource
 }
 
-// collectWorkflowStats parses a lock file and collects statistics
+// collectWorkflowStats parses a lock file and collects statistics against the default budget
 func collectWorkflowStats(lockFilePath string) (*WorkflowStats, error) {
+	return collectWorkflowStatsWithBudget(lockFilePath, defaultLockBudget(), nil)
+}
+
+// collectWorkflowStatsWithBudget parses a lock file, collects statistics, attributes its
+// bytes to sources and checks it against budget. importedSteps holds the names of steps
+// that come from imported workflows.
+func collectWorkflowStatsWithBudget(lockFilePath string, budget LockBudget, importedSteps map[string]bool) (*WorkflowStats, error) {
 	compileStatsLog.Printf("Collecting workflow stats: file=%s", lockFilePath)
 	// Get file size
 	fileInfo, err := os.Stat(lockFilePath)
@@ -51,6 +70,11 @@ func collectWorkflowStats(lockFilePath string) (*WorkflowStats, error) {
 	stats := &WorkflowStats{
 		Workflow: filepath.Base(lockFilePath),
 		FileSize: fileInfo.Size(),
+		Sources:  attributeLockFileBytes(string(content), importedSteps),
+		Budget:   budget,
+	}
+	for line := range strings.SplitSeq(string(content), "\n") {
+		stats.LongestLine = max(stats.LongestLine, len(line))
 	}
 
 	// Count jobs and steps
@@ -85,8 +109,10 @@ func collectWorkflowStats(lockFilePath string) (*WorkflowStats, error) {
 		}
 	}
 
-	compileStatsLog.Printf("Stats collected: jobs=%d, steps=%d, scripts=%d, size=%d bytes",
-		stats.Jobs, stats.Steps, stats.ScriptCount, stats.FileSize)
+	checkLockBudget(stats, string(content))
+
+	compileStatsLog.Printf("Stats collected: jobs=%d, steps=%d, scripts=%d, size=%d bytes, budget_warnings=%d",
+		stats.Jobs, stats.Steps, stats.ScriptCount, stats.FileSize, len(stats.BudgetWarnings))
 	return stats, nil
 }
 
@@ -131,13 +157,13 @@ func displayStatsTable(statsList []*WorkflowStats) {
 		if i >= maxDisplay {
 			break
 		}
-		// Check if workflow is above 500KB (512000 bytes)
-		const maxSize = 500 * 1024
+		budget := defaultLockBudget().override(stats.Budget)
+		percent := percentOf(stats.FileSize, budget.MaxSize)
 		workflowName := stats.Workflow
 		fileSize := console.FormatFileSize(stats.FileSize)
 
-		if stats.FileSize > maxSize {
-			// Apply red color and error icon for large workflows
+		if stats.FileSize > budget.MaxSize {
+			// Apply red color and error icon for workflows over budget
 			if tty.IsStderrTerminal() {
 				workflowName = styles.Error.Render("✗ ") + styles.Error.Render(stats.Workflow)
 				fileSize = styles.Error.Render(console.FormatFileSize(stats.FileSize))
@@ -145,21 +171,30 @@ func displayStatsTable(statsList []*WorkflowStats) {
 				// In non-TTY mode, just add the icon without color
 				workflowName = "✗ " + stats.Workflow
 			}
+		} else if len(stats.BudgetWarnings) > 0 {
+			// Apply warning color and icon for workflows approaching their budget
+			if tty.IsStderrTerminal() {
+				workflowName = styles.Warning.Render("⚠ ") + styles.Warning.Render(stats.Workflow)
+			} else {
+				workflowName = "⚠ " + stats.Workflow
+			}
 		}
 
 		rows = append(rows, []string{
 			workflowName,
 			fileSize,
+			fmt.Sprintf("%d%%", percent),
 			fmt.Sprintf("%d", stats.Jobs),
 			fmt.Sprintf("%d", stats.Steps),
 			fmt.Sprintf("%d", stats.ScriptCount),
+			formatPromptEstimate(stats),
 		})
 	}
 
 	// Create table config
 	tableConfig := console.TableConfig{
 		Title:   "",
-		Headers: []string{"WORKFLOW", "FILE SIZE", "JOBS", "STEPS", "SCRIPTS"},
+		Headers: []string{"WORKFLOW", "FILE SIZE", "BUDGET", "JOBS", "STEPS", "SCRIPTS", "PROMPT"},
 		Rows:    rows,
 	}
 
@@ -176,4 +211,105 @@ func displayStatsTable(statsList []*WorkflowStats) {
 	fmt.Fprintf(os.Stderr, "  Total jobs:      %d\n", totalJobs)
 	fmt.Fprintf(os.Stderr, "  Total steps:     %d\n", totalSteps)
 	fmt.Fprintf(os.Stderr, "  Total scripts:   %d (%s)\n", totalScripts, console.FormatFileSize(int64(totalScriptSize)))
+
+	// Break down workflows that approach their budget, or the only workflow compiled
+	for _, stats := range statsList {
+		if len(stats.BudgetWarnings) > 0 || len(statsList) == 1 {
+			displaySizeBreakdown(stats)
+		}
+		if promptExceedsLimit(stats) || len(statsList) == 1 {
+			displayPromptBreakdown(stats)
+		}
+	}
+}
+
+// estimateWorkflowPrompt renders the prompt of a workflow with the content known at compile
+// time and records its token estimate
+func estimateWorkflowPrompt(stats *WorkflowStats, markdownPath string) {
+	opts := workflow.PromptRenderOptions{
+		EventName:  "workflow_dispatch",
+		PromptsDir: defaultPromptsDir(markdownPath),
+	}
+	rendered, err := workflow.NewCompiler().RenderPrompt(markdownPath, opts)
+	if err != nil {
+		compileStatsLog.Printf("Skipping prompt estimate for %s: %v", markdownPath, err)
+		return
+	}
+	stats.PromptTokens = rendered.Tokens
+	stats.PromptMaxTokens = rendered.MaxTokens
+	stats.PromptSources = rendered.Sources
+}
+
+// promptExceedsLimit reports whether the prompt estimate of a workflow exceeds prompt.max-tokens
+func promptExceedsLimit(stats *WorkflowStats) bool {
+	return stats.PromptMaxTokens > 0 && stats.PromptTokens > stats.PromptMaxTokens
+}
+
+// formatPromptEstimate formats the prompt estimate of a workflow for the stats table
+func formatPromptEstimate(stats *WorkflowStats) string {
+	if stats.PromptTokens == 0 {
+		return "-"
+	}
+	estimate := "~" + console.FormatNumber(stats.PromptTokens)
+	if stats.PromptMaxTokens > 0 {
+		estimate += " / " + console.FormatNumber(stats.PromptMaxTokens)
+	}
+	return estimate
+}
+
+// displayPromptBreakdown displays the token estimate of each included prompt section
+func displayPromptBreakdown(stats *WorkflowStats) {
+	if stats.PromptTokens == 0 {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr)
+	header := fmt.Sprintf("Prompt estimate for %s (~%s tokens", stats.Workflow, console.FormatNumber(stats.PromptTokens))
+	if stats.PromptMaxTokens > 0 {
+		header += fmt.Sprintf(", %d%% of prompt.max-tokens", percentOf(int64(stats.PromptTokens), int64(stats.PromptMaxTokens)))
+	}
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(header+"):"))
+	for _, source := range stats.PromptSources {
+		if !source.Included {
+			continue
+		}
+		label := source.Kind + ": " + source.Name
+		fmt.Fprintf(os.Stderr, "  %-40s %10s  %3d%%\n", label, "~"+console.FormatNumber(source.Tokens), percentOf(int64(source.Tokens), int64(stats.PromptTokens)))
+	}
+	if promptExceedsLimit(stats) {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("The prompt is estimated to exceed prompt.max-tokens (%d) before runtime values; sections in prompt.summarize and prompt.truncate will be reduced on every run", stats.PromptMaxTokens)))
+	}
+}
+
+// maxBreakdownSources is the number of sources listed in a size breakdown
+const maxBreakdownSources = 8
+
+// displaySizeBreakdown displays the largest sources of a lock file, its budget warnings and
+// suggestions for reducing its size
+func displaySizeBreakdown(stats *WorkflowStats) {
+	if len(stats.Sources) == 0 {
+		return
+	}
+	budget := defaultLockBudget().override(stats.Budget)
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Size breakdown for %s (%s, %d%% of %s budget):",
+		stats.Workflow, console.FormatFileSize(stats.FileSize), percentOf(stats.FileSize, budget.MaxSize), console.FormatFileSize(budget.MaxSize))))
+	for i, source := range stats.Sources {
+		if i >= maxBreakdownSources {
+			fmt.Fprintf(os.Stderr, "  ... %d more sources\n", len(stats.Sources)-maxBreakdownSources)
+			break
+		}
+		fmt.Fprintf(os.Stderr, "  %-40s %10s  %3d%%\n", source.Label(), console.FormatFileSize(int64(source.Bytes)), percentOf(int64(source.Bytes), stats.FileSize))
+	}
+
+	for _, warning := range stats.BudgetWarnings {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warning))
+	}
+	if len(stats.Suggestions) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Suggestions:"))
+		for _, suggestion := range stats.Suggestions {
+			fmt.Fprintln(os.Stderr, console.FormatListItem(suggestion))
+		}
+	}
 }
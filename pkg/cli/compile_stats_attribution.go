@@ -0,0 +1,204 @@
+// This file attributes the bytes of a compiled lock file to the sources that produced them
+// (gh aw compile --stats).
+//
+// The lock file is scanned line by line. Every step is attributed as a whole to one source:
+// github-script steps to the script they run, steps imported from shared workflows to
+// imported-steps, the steps that install the gh-aw scripts in each job to action-setup, and
+// all other steps to steps. Heredocs inside run scripts are attributed by
+// their delimiter instead, so the prompt, MCP gateway configuration and safe outputs
+// configuration are reported separately from the step that writes them. Lines outside of
+// steps (triggers, permissions, job headers, comments) are attributed to structure.
+
+package cli
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Size source kinds reported by compile --stats
+const (
+	SizeSourcePrompt            = "prompt"
+	SizeSourceScript            = "script"
+	SizeSourceMCPConfig         = "mcp-config"
+	SizeSourceSafeOutputsConfig = "safe-outputs-config"
+	SizeSourceImportedSteps     = "imported-steps"
+	SizeSourceActionSetup       = "action-setup"
+	SizeSourceSteps             = "steps"
+	SizeSourceStructure         = "structure"
+)
+
+// SizeSource is a number of lock file bytes attributed to one source
+type SizeSource struct {
+	Kind  string // One of the SizeSource* kinds
+	Name  string // Script ID for script sources, empty otherwise
+	Bytes int
+}
+
+// Label returns the display name of the source
+func (s SizeSource) Label() string {
+	if s.Name != "" {
+		return s.Kind + ":" + s.Name
+	}
+	return s.Kind
+}
+
+// devModeActionsCheckoutStepName is the step that checks out the local actions folder in each
+// job when compiling with action-mode dev
+const devModeActionsCheckoutStepName = "Checkout actions folder"
+
+var (
+	// heredocStartPattern matches the start of a heredoc with a GH_AW delimiter, quoted or not
+	heredocStartPattern = regexp.MustCompile(`<<-?\s*['"]?([A-Z0-9_]+_EOF)['"]?`)
+	// scriptRequirePattern matches the gh-aw script loaded by a github-script step
+	scriptRequirePattern = regexp.MustCompile(`require\(['"]/opt/gh-aw/actions/([A-Za-z0-9_.-]+)\.cjs['"]\)`)
+)
+
+// attributeLockFileBytes splits the bytes of a lock file between its sources. importedSteps
+// holds the names of steps that come from imported workflows. The result is sorted by size,
+// largest first, and its sizes add up to the size of the content.
+func attributeLockFileBytes(content string, importedSteps map[string]bool) []SizeSource {
+	totals := make(map[SizeSource]int)
+	add := func(kind, name string, n int) {
+		if n > 0 {
+			totals[SizeSource{Kind: kind, Name: name}] += n
+		}
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	stepIndent := -1
+	var step []string
+	flushStep := func() {
+		if len(step) > 0 {
+			attributeStepBytes(step, importedSteps, add)
+			step = nil
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if step != nil && (trimmed == "" || indent > stepIndent) {
+			step = append(step, line)
+			continue
+		}
+		flushStep()
+
+		if stepIndent >= 0 && indent == stepIndent && strings.HasPrefix(trimmed, "- ") {
+			step = []string{line}
+			continue
+		}
+		if trimmed == "steps:" {
+			stepIndent = indent + 2
+		} else if trimmed != "" && indent < stepIndent {
+			stepIndent = -1
+		}
+		add(SizeSourceStructure, "", len(line))
+	}
+	flushStep()
+
+	sources := make([]SizeSource, 0, len(totals))
+	for source, bytes := range totals {
+		source.Bytes = bytes
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Bytes != sources[j].Bytes {
+			return sources[i].Bytes > sources[j].Bytes
+		}
+		return sources[i].Label() < sources[j].Label()
+	})
+	return sources
+}
+
+// attributeStepBytes attributes the lines of one step, splitting out known heredocs
+func attributeStepBytes(lines []string, importedSteps map[string]bool, add func(kind, name string, n int)) {
+	kind, name := classifyStep(lines, importedSteps)
+
+	heredocEnd := ""
+	heredocKind := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case heredocEnd != "" && trimmed == heredocEnd:
+			heredocEnd = ""
+			add(kind, name, len(line))
+		case heredocEnd != "":
+			add(heredocKind, "", len(line))
+		default:
+			add(kind, name, len(line))
+			if match := heredocStartPattern.FindStringSubmatch(line); match != nil {
+				if k := heredocSourceKind(match[1]); k != "" {
+					heredocEnd = match[1]
+					heredocKind = k
+				}
+			}
+		}
+	}
+}
+
+// heredocSourceKind returns the source kind of a heredoc delimiter, or "" if the heredoc
+// belongs to its step
+func heredocSourceKind(delimiter string) string {
+	switch {
+	case strings.Contains(delimiter, "PROMPT"):
+		return SizeSourcePrompt
+	case strings.Contains(delimiter, "MCP_CONFIG"):
+		return SizeSourceMCPConfig
+	case strings.Contains(delimiter, "SAFE_OUTPUTS"):
+		return SizeSourceSafeOutputsConfig
+	default:
+		return ""
+	}
+}
+
+// classifyStep returns the source kind and name of a step from its lock file lines
+func classifyStep(lines []string, importedSteps map[string]bool) (string, string) {
+	var parsed []map[string]any
+	if err := yaml.Unmarshal([]byte(dedentStep(lines)), &parsed); err != nil || len(parsed) == 0 {
+		return SizeSourceSteps, ""
+	}
+	step := parsed[0]
+
+	stepName, _ := step["name"].(string)
+	if stepName != "" && importedSteps[stepName] {
+		return SizeSourceImportedSteps, ""
+	}
+
+	uses, _ := step["uses"].(string)
+	if stepName == devModeActionsCheckoutStepName || uses == "./actions/setup" || strings.HasPrefix(uses, "github/gh-aw/actions/setup@") {
+		return SizeSourceActionSetup, ""
+	}
+
+	with, _ := step["with"].(map[string]any)
+	script, _ := with["script"].(string)
+	if !strings.HasPrefix(uses, "actions/github-script@") || script == "" {
+		return SizeSourceSteps, ""
+	}
+
+	// The main script is the last one required; setup helpers are required first
+	if matches := scriptRequirePattern.FindAllStringSubmatch(script, -1); len(matches) > 0 {
+		return SizeSourceScript, matches[len(matches)-1][1]
+	}
+	if id, _ := step["id"].(string); id != "" {
+		return SizeSourceScript, id
+	}
+	return SizeSourceScript, stepName
+}
+
+// dedentStep removes the indentation of a step so it can be parsed as a YAML list
+func dedentStep(lines []string) string {
+	indent := len(lines[0]) - len(strings.TrimLeft(lines[0], " "))
+	var b strings.Builder
+	for _, line := range lines {
+		if len(line) >= indent && strings.TrimSpace(line[:indent]) == "" {
+			line = line[indent:]
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const attributionTestLockFile = `# This file was automatically generated by gh-aw. DO NOT EDIT.
+name: "test"
+on:
+  workflow_dispatch:
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Checkout actions folder
+        uses: actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd # v6.0.2
+        with:
+          sparse-checkout: |
+            actions
+      - name: Imported setup
+        run: echo "imported"
+      - name: Create prompt
+        run: |
+          cat << 'GH_AW_PROMPT_EOF' > "$GH_AW_PROMPT"
+          Do the thing.
+
+          Carefully.
+          GH_AW_PROMPT_EOF
+      - name: Start MCP gateway
+        run: |
+          cat << GH_AW_MCP_CONFIG_EOF | bash /opt/gh-aw/actions/start_mcp_gateway.sh
+          {"mcpServers": {}}
+          GH_AW_MCP_CONFIG_EOF
+      - name: Create issue
+        uses: actions/github-script@ed597411d8f924073f98dfc5c65a23a2325f34cd # v8
+        with:
+          script: |
+            const { setupGlobals } = require('/opt/gh-aw/actions/setup_globals.cjs');
+            setupGlobals(core, github, context, exec, io);
+            const { main } = require('/opt/gh-aw/actions/create_issue.cjs');
+            await main();
+  conclusion:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo done
+`
+
+func sourceBytes(sources []SizeSource) map[string]int {
+	result := make(map[string]int)
+	for _, source := range sources {
+		result[source.Label()] = source.Bytes
+	}
+	return result
+}
+
+func TestAttributeLockFileBytes(t *testing.T) {
+	sources := attributeLockFileBytes(attributionTestLockFile, map[string]bool{"Imported setup": true})
+	bytes := sourceBytes(sources)
+
+	total := 0
+	for _, source := range sources {
+		total += source.Bytes
+	}
+	assert.Equal(t, len(attributionTestLockFile), total, "attributed bytes should add up to the file size")
+
+	assert.Equal(t, len("          Do the thing.\n\n          Carefully.\n"), bytes[SizeSourcePrompt], "prompt heredoc content should be attributed to the prompt")
+	assert.Equal(t, len("          {\"mcpServers\": {}}\n"), bytes[SizeSourceMCPConfig], "MCP config heredoc should be attributed to mcp-config")
+	assert.Equal(t, len("      - name: Imported setup\n        run: echo \"imported\"\n"), bytes[SizeSourceImportedSteps], "imported steps should be matched by name")
+	assert.Positive(t, bytes["script:create_issue"], "github-script steps should be attributed to the script they run")
+	assert.NotContains(t, bytes, "script:setup_globals", "setup helpers should not be reported as the script")
+	assert.Positive(t, bytes[SizeSourceActionSetup], "actions folder checkout should be attributed to action-setup")
+	assert.Positive(t, bytes[SizeSourceSteps], "other steps should be attributed to steps")
+	assert.Positive(t, bytes[SizeSourceStructure], "triggers and job headers should be attributed to structure")
+
+	for i := 1; i < len(sources); i++ {
+		require.GreaterOrEqual(t, sources[i-1].Bytes, sources[i].Bytes, "sources should be sorted by size")
+	}
+}
+
+func TestAttributeLockFileBytesWithoutImports(t *testing.T) {
+	bytes := sourceBytes(attributeLockFileBytes(attributionTestLockFile, nil))
+	assert.NotContains(t, bytes, SizeSourceImportedSteps, "steps should not be attributed to imports without import names")
+}
@@ -0,0 +1,247 @@
+// This file checks compiled lock files against their size budget (gh aw compile --stats).
+//
+// The budget defaults to GitHub's limits for workflow files and expression values. A
+// repository can lower it for every workflow in .github/aw/lock-budget.yml, and a workflow
+// can lower it further with the lock-budget frontmatter field:
+//
+//	lock-budget:
+//	  max-size: 300000          # bytes
+//	  max-expression-size: 15000
+//	  warn-at: 75               # percent of a limit at which --stats warns
+//
+// When a lock file approaches its budget, --stats reports which sources take the most space
+// and suggests how to reduce them.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var compileStatsBudgetLog = logger.New("cli:compile_stats_budget")
+
+// LockBudgetFileName is the repository-wide lock file budget policy in .github/aw
+const LockBudgetFileName = "lock-budget.yml"
+
+// defaultLockBudgetWarnAt is the percentage of a limit at which --stats warns by default
+const defaultLockBudgetWarnAt = 80
+
+// suggestionMinShare is the percentage of a lock file a source must take before --stats
+// suggests reducing it
+const suggestionMinShare = 10
+
+// LockBudget holds the size thresholds a lock file is checked against. Zero values inherit
+// the next level: frontmatter, then repository policy, then GitHub's limits.
+type LockBudget struct {
+	MaxSize           int64 `yaml:"max-size,omitempty"`            // Maximum lock file size in bytes
+	MaxExpressionSize int   `yaml:"max-expression-size,omitempty"` // Maximum length of a single line in bytes
+	WarnAt            int   `yaml:"warn-at,omitempty"`             // Percentage of a limit at which to warn
+}
+
+// defaultLockBudget returns the budget derived from GitHub's limits
+func defaultLockBudget() LockBudget {
+	return LockBudget{
+		MaxSize:           workflow.MaxLockFileSize,
+		MaxExpressionSize: workflow.MaxExpressionSize,
+		WarnAt:            defaultLockBudgetWarnAt,
+	}
+}
+
+// override returns the budget with the thresholds set in other replacing its own
+func (b LockBudget) override(other LockBudget) LockBudget {
+	if other.MaxSize > 0 {
+		b.MaxSize = other.MaxSize
+	}
+	if other.MaxExpressionSize > 0 {
+		b.MaxExpressionSize = other.MaxExpressionSize
+	}
+	if other.WarnAt > 0 {
+		b.WarnAt = other.WarnAt
+	}
+	return b
+}
+
+// validate checks that the thresholds are within GitHub's limits
+func (b LockBudget) validate() error {
+	if b.MaxSize < 0 || b.MaxSize > workflow.MaxLockFileSize {
+		return fmt.Errorf("max-size must be between 1 and %d bytes, got: %d", workflow.MaxLockFileSize, b.MaxSize)
+	}
+	if b.MaxExpressionSize < 0 || b.MaxExpressionSize > workflow.MaxExpressionSize {
+		return fmt.Errorf("max-expression-size must be between 1 and %d bytes, got: %d", workflow.MaxExpressionSize, b.MaxExpressionSize)
+	}
+	if b.WarnAt < 0 || b.WarnAt > 100 {
+		return fmt.Errorf("warn-at must be a percentage between 1 and 100, got: %d", b.WarnAt)
+	}
+	return nil
+}
+
+// loadLockBudgetPolicy reads the repository-wide budget. A missing policy file is not an error.
+func loadLockBudgetPolicy(gitRoot string) (LockBudget, error) {
+	path := filepath.Join(gitRoot, ".github", "aw", LockBudgetFileName)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return LockBudget{}, nil
+	}
+	if err != nil {
+		return LockBudget{}, fmt.Errorf("failed to read %s: %w", LockBudgetFileName, err)
+	}
+
+	var budget LockBudget
+	if err := yaml.UnmarshalWithOptions(content, &budget, yaml.DisallowUnknownField()); err != nil {
+		return LockBudget{}, fmt.Errorf("invalid %s: %w", LockBudgetFileName, err)
+	}
+	if err := budget.validate(); err != nil {
+		return LockBudget{}, fmt.Errorf("invalid %s: %w", LockBudgetFileName, err)
+	}
+	compileStatsBudgetLog.Printf("Loaded lock budget policy: %+v", budget)
+	return budget, nil
+}
+
+// parseLockBudgetFrontmatter reads the lock-budget frontmatter field
+func parseLockBudgetFrontmatter(frontmatter map[string]any) (LockBudget, error) {
+	value, ok := frontmatter["lock-budget"]
+	if !ok || value == nil {
+		return LockBudget{}, nil
+	}
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return LockBudget{}, fmt.Errorf("invalid lock-budget: %w", err)
+	}
+	var budget LockBudget
+	if err := yaml.UnmarshalWithOptions(content, &budget, yaml.DisallowUnknownField()); err != nil {
+		return LockBudget{}, fmt.Errorf("invalid lock-budget: %w", err)
+	}
+	if err := budget.validate(); err != nil {
+		return LockBudget{}, fmt.Errorf("invalid lock-budget: %w", err)
+	}
+	return budget, nil
+}
+
+// workflowBudgetInputs reads the budget and the names of imported steps of a workflow.
+// Errors in the frontmatter or imports are reported to the caller, which falls back to
+// the policy budget and no imported steps.
+func workflowBudgetInputs(markdownPath string, policy LockBudget, importCache *parser.ImportCache) (LockBudget, map[string]bool, error) {
+	budget := defaultLockBudget().override(policy)
+
+	content, err := os.ReadFile(markdownPath)
+	if err != nil {
+		return budget, nil, err
+	}
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	if err != nil {
+		return budget, nil, err
+	}
+
+	frontmatterBudget, err := parseLockBudgetFrontmatter(result.Frontmatter)
+	if err != nil {
+		return budget, nil, err
+	}
+	budget = budget.override(frontmatterBudget)
+
+	importsResult, err := parser.ProcessImportsFromFrontmatterWithManifest(result.Frontmatter, filepath.Dir(markdownPath), importCache)
+	if err != nil {
+		return budget, nil, err
+	}
+	importedSteps := make(map[string]bool)
+	for _, stepsYAML := range []string{importsResult.CopilotSetupSteps, importsResult.MergedSteps, importsResult.MergedPostSteps} {
+		var steps []map[string]any
+		if stepsYAML == "" || yaml.Unmarshal([]byte(stepsYAML), &steps) != nil {
+			continue
+		}
+		for _, step := range steps {
+			if name, ok := step["name"].(string); ok && name != "" {
+				importedSteps[name] = true
+			}
+		}
+	}
+	return budget, importedSteps, nil
+}
+
+// checkLockBudget compares the statistics of a lock file with its budget and records
+// warnings and suggestions for reducing its size
+func checkLockBudget(stats *WorkflowStats, content string) {
+	budget := stats.Budget
+	warnAt := budget.WarnAt
+
+	sizePercent := percentOf(stats.FileSize, budget.MaxSize)
+	if stats.FileSize > budget.MaxSize {
+		stats.BudgetWarnings = append(stats.BudgetWarnings, fmt.Sprintf("Lock file size (%s) exceeds its budget (%s)",
+			console.FormatFileSize(stats.FileSize), console.FormatFileSize(budget.MaxSize)))
+	} else if sizePercent >= warnAt {
+		stats.BudgetWarnings = append(stats.BudgetWarnings, fmt.Sprintf("Lock file size (%s) is %d%% of its budget (%s)",
+			console.FormatFileSize(stats.FileSize), sizePercent, console.FormatFileSize(budget.MaxSize)))
+	}
+
+	linePercent := percentOf(int64(stats.LongestLine), int64(budget.MaxExpressionSize))
+	if stats.LongestLine > budget.MaxExpressionSize {
+		stats.BudgetWarnings = append(stats.BudgetWarnings, fmt.Sprintf("Longest line (%s) exceeds the expression size budget (%s)",
+			console.FormatFileSize(int64(stats.LongestLine)), console.FormatFileSize(int64(budget.MaxExpressionSize))))
+	} else if linePercent >= warnAt {
+		stats.BudgetWarnings = append(stats.BudgetWarnings, fmt.Sprintf("Longest line (%s) is %d%% of the expression size budget (%s)",
+			console.FormatFileSize(int64(stats.LongestLine)), linePercent, console.FormatFileSize(int64(budget.MaxExpressionSize))))
+	}
+
+	if len(stats.BudgetWarnings) > 0 {
+		stats.Suggestions = lockBudgetSuggestions(stats, content)
+	}
+}
+
+// lockBudgetSuggestions returns concrete ways to reduce the largest sources of a lock file,
+// largest source first
+func lockBudgetSuggestions(stats *WorkflowStats, content string) []string {
+	kindBytes := make(map[string]int)
+	for _, source := range stats.Sources {
+		kindBytes[source.Kind] += source.Bytes
+	}
+
+	var suggestions []string
+	seen := make(map[string]bool)
+	for _, source := range stats.Sources {
+		if seen[source.Kind] {
+			continue
+		}
+		seen[source.Kind] = true
+
+		bytes := kindBytes[source.Kind]
+		if percentOf(int64(bytes), stats.FileSize) < suggestionMinShare {
+			continue
+		}
+		size := console.FormatFileSize(int64(bytes))
+
+		switch source.Kind {
+		case SizeSourceActionSetup:
+			if strings.Contains(content, "- name: "+devModeActionsCheckoutStepName) {
+				suggestions = append(suggestions, fmt.Sprintf("Set features.action-mode: release to remove the %q step from each job (action setup takes %s)", devModeActionsCheckoutStepName, size))
+			}
+		case SizeSourcePrompt:
+			suggestions = append(suggestions, fmt.Sprintf("Move inlined prompt text (%s) into a file loaded at run time with {{#runtime-import path}}", size))
+		case SizeSourceMCPConfig:
+			suggestions = append(suggestions, fmt.Sprintf("Remove unused MCP servers and tools; the MCP gateway configuration takes %s", size))
+		case SizeSourceSafeOutputsConfig:
+			suggestions = append(suggestions, fmt.Sprintf("Remove unused safe outputs; their tool definitions and validation rules take %s", size))
+		case SizeSourceImportedSteps:
+			suggestions = append(suggestions, fmt.Sprintf("Move long run scripts of imported steps (%s) into script files in the repository", size))
+		case SizeSourceScript:
+			suggestions = append(suggestions, fmt.Sprintf("github-script steps take %s; disable safe outputs and features whose scripts the workflow does not need", size))
+		}
+	}
+	return suggestions
+}
+
+// percentOf returns value as a whole percentage of limit
+func percentOf(value, limit int64) int {
+	if limit <= 0 {
+		return 0
+	}
+	return int(value * 100 / limit)
+}
@@ -0,0 +1,124 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLockBudgetFrontmatter(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter map[string]any
+		want        LockBudget
+		wantErr     string
+	}{
+		{
+			name:        "not set",
+			frontmatter: map[string]any{"on": "push"},
+		},
+		{
+			name:        "all thresholds",
+			frontmatter: map[string]any{"lock-budget": map[string]any{"max-size": 300000, "max-expression-size": 15000, "warn-at": 90}},
+			want:        LockBudget{MaxSize: 300000, MaxExpressionSize: 15000, WarnAt: 90},
+		},
+		{
+			name:        "above GitHub limit",
+			frontmatter: map[string]any{"lock-budget": map[string]any{"max-size": 600000}},
+			wantErr:     "max-size must be between 1 and 512000",
+		},
+		{
+			name:        "unknown field",
+			frontmatter: map[string]any{"lock-budget": map[string]any{"max-jobs": 3}},
+			wantErr:     "invalid lock-budget",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, err := parseLockBudgetFrontmatter(tt.frontmatter)
+			if tt.wantErr != "" {
+				require.Error(t, err, "invalid budget should fail")
+				assert.Contains(t, err.Error(), tt.wantErr, "error should explain the problem")
+				return
+			}
+			require.NoError(t, err, "valid budget should parse")
+			assert.Equal(t, tt.want, budget, "budget should match frontmatter")
+		})
+	}
+}
+
+func TestLoadLockBudgetPolicy(t *testing.T) {
+	gitRoot := testutil.TempDir(t, "lock-budget-*")
+
+	budget, err := loadLockBudgetPolicy(gitRoot)
+	require.NoError(t, err, "missing policy should not be an error")
+	assert.Equal(t, LockBudget{}, budget, "missing policy should not set thresholds")
+
+	policyPath := filepath.Join(gitRoot, ".github", "aw", LockBudgetFileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(policyPath), 0o755), "should create .github/aw")
+	require.NoError(t, os.WriteFile(policyPath, []byte("max-size: 400000\nwarn-at: 50\n"), 0o644), "should write policy")
+	budget, err = loadLockBudgetPolicy(gitRoot)
+	require.NoError(t, err, "valid policy should load")
+	assert.Equal(t, LockBudget{MaxSize: 400000, WarnAt: 50}, budget, "policy thresholds should be loaded")
+
+	// Frontmatter overrides the policy, which overrides GitHub's limits
+	effective := defaultLockBudget().override(budget).override(LockBudget{MaxSize: 300000})
+	assert.Equal(t, LockBudget{MaxSize: 300000, MaxExpressionSize: workflow.MaxExpressionSize, WarnAt: 50}, effective, "thresholds should be inherited level by level")
+
+	require.NoError(t, os.WriteFile(policyPath, []byte("warn-at: 150\n"), 0o644), "should write policy")
+	_, err = loadLockBudgetPolicy(gitRoot)
+	require.Error(t, err, "out of range policy should fail")
+	assert.Contains(t, err.Error(), LockBudgetFileName, "error should name the policy file")
+}
+
+func TestWorkflowBudgetInputs(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "lock-budget-inputs-*")
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "shared"), 0o755), "should create shared directory")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "shared", "setup.md"), []byte("---\nsteps:\n  - name: Install tools\n    run: echo install\n---\n"), 0o644), "should write shared workflow")
+	workflowPath := filepath.Join(tmpDir, "test.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte("---\non: workflow_dispatch\nimports:\n  - shared/setup.md\nlock-budget:\n  max-size: 200000\n---\n\n# Test\n"), 0o644), "should write workflow")
+
+	budget, importedSteps, err := workflowBudgetInputs(workflowPath, LockBudget{MaxSize: 400000, WarnAt: 60}, nil)
+	require.NoError(t, err, "workflow inputs should be read")
+	assert.Equal(t, LockBudget{MaxSize: 200000, MaxExpressionSize: workflow.MaxExpressionSize, WarnAt: 60}, budget, "frontmatter should override the policy")
+	assert.Equal(t, map[string]bool{"Install tools": true}, importedSteps, "imported step names should be collected")
+}
+
+func TestCheckLockBudget(t *testing.T) {
+	stats := &WorkflowStats{
+		FileSize:    90000,
+		LongestLine: 1000,
+		Budget:      LockBudget{MaxSize: 100000, MaxExpressionSize: workflow.MaxExpressionSize, WarnAt: 80},
+		Sources: []SizeSource{
+			{Kind: SizeSourcePrompt, Bytes: 40000},
+			{Kind: SizeSourceActionSetup, Bytes: 20000},
+			{Kind: SizeSourceSteps, Bytes: 25000},
+			{Kind: SizeSourceStructure, Bytes: 5000},
+		},
+	}
+	checkLockBudget(stats, "      - name: Checkout actions folder\n")
+
+	require.Len(t, stats.BudgetWarnings, 1, "only the file size should approach its budget")
+	assert.Contains(t, stats.BudgetWarnings[0], "90% of its budget", "warning should report the share of the budget")
+	require.Len(t, stats.Suggestions, 2, "should suggest reductions for large sources with a remedy")
+	assert.Contains(t, stats.Suggestions[0], "runtime-import", "largest source should be suggested first")
+	assert.Contains(t, stats.Suggestions[1], "action-mode: release", "dev mode action setup should suggest release mode")
+
+	small := &WorkflowStats{FileSize: 1000, Budget: defaultLockBudget(), Sources: stats.Sources}
+	checkLockBudget(small, "")
+	assert.Empty(t, small.BudgetWarnings, "small lock files should not warn")
+	assert.Empty(t, small.Suggestions, "suggestions are only made for lock files approaching their budget")
+
+	over := &WorkflowStats{FileSize: 1000, LongestLine: workflow.MaxExpressionSize + 1, Budget: defaultLockBudget()}
+	checkLockBudget(over, "")
+	require.Len(t, over.BudgetWarnings, 1, "long lines should be checked against the expression size budget")
+	assert.Contains(t, over.BudgetWarnings[0], "exceeds the expression size budget", "warning should report the exceeded limit")
+}
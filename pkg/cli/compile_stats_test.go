@@ -299,3 +299,29 @@ func TestCollectWorkflowStats_InvalidYAML(t *testing.T) {
 		t.Error("Expected nil stats for invalid YAML")
 	}
 }
+
+func TestFormatPromptEstimate(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats WorkflowStats
+		want  string
+	}{
+		{"not rendered", WorkflowStats{}, "-"},
+		{"no limit", WorkflowStats{PromptTokens: 1500}, "~1.50k"},
+		{"with limit", WorkflowStats{PromptTokens: 1500, PromptMaxTokens: 1000}, "~1.50k / 1.00k"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatPromptEstimate(&tt.stats); got != tt.want {
+				t.Errorf("formatPromptEstimate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if !promptExceedsLimit(&WorkflowStats{PromptTokens: 1500, PromptMaxTokens: 1000}) {
+		t.Error("Expected a prompt over its limit to be reported")
+	}
+	if promptExceedsLimit(&WorkflowStats{PromptTokens: 1500}) {
+		t.Error("Expected prompts without a limit not to be reported")
+	}
+}
@@ -211,6 +211,30 @@ func validateCompileConfig(config CompileConfig) error {
 		return fmt.Errorf("--purge flag can only be used when compiling all markdown files (no specific files specified)")
 	}
 
+	// Validate check flag usage: --check only reads lock files
+	if config.Check && (config.Watch || config.Purge || config.Dependabot || config.NoEmit) {
+		compileValidationLog.Print("Config validation failed: check flag with a writing mode")
+		return fmt.Errorf("--check cannot be combined with --watch, --purge, --dependabot or --no-emit")
+	}
+
+	// Validate sarif flag usage: the SARIF log covers a compilation
+	if config.SARIFFile != "" && (config.Watch || config.Check) {
+		compileValidationLog.Print("Config validation failed: sarif flag with watch or check")
+		return fmt.Errorf("--sarif cannot be combined with --watch or --check")
+	}
+
+	// Validate fail-on-warnings flag usage: warnings are counted over a compilation
+	if config.FailOnWarnings && (config.Watch || config.Check) {
+		compileValidationLog.Print("Config validation failed: fail-on-warnings flag with watch or check")
+		return fmt.Errorf("--fail-on-warnings cannot be combined with --watch or --check")
+	}
+
+	// Validate jobs flag usage
+	if config.Jobs < 0 {
+		compileValidationLog.Printf("Config validation failed: negative jobs: %d", config.Jobs)
+		return fmt.Errorf("--jobs cannot be negative, got: %d", config.Jobs)
+	}
+
 	// Validate workflow directory path
 	if config.WorkflowDir != "" && filepath.IsAbs(config.WorkflowDir) {
 		compileValidationLog.Printf("Config validation failed: absolute path in workflowDir: %s", config.WorkflowDir)
@@ -54,46 +54,13 @@ func watchAndCompileWorkflows(markdownFile string, compiler *workflow.Compiler,
 		}
 	}
 
-	// Set up file system watcher with buffered events for better handling of burst activity
-	watcher, err := fsnotify.NewBufferedWatcher(100)
+	// Watch the workflows directory and its subdirectories (for include files)
+	watcher, _, err := newDirectoryTreeWatcher(workflowsDir, compileWatchLog)
 	if err != nil {
-		return fmt.Errorf("failed to create file watcher: %w", err)
+		return err
 	}
 	defer watcher.Close()
 
-	// addWatchPath adds a path to the watcher with platform-specific configuration.
-	// On Windows, uses a larger buffer (64KB) to prevent event overflow in busy directories.
-	addWatchPath := func(path string) error {
-		if runtime.GOOS == "windows" {
-			return watcher.AddWith(path, fsnotify.WithBufferSize(64*1024))
-		}
-		return watcher.Add(path)
-	}
-
-	// Add the workflows directory to the watcher
-	if err := addWatchPath(workflowsDir); err != nil {
-		return fmt.Errorf("failed to watch directory %s: %w", workflowsDir, err)
-	}
-
-	// Also watch subdirectories for include files (recursive watching)
-	err = filepath.Walk(workflowsDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip errors but continue walking
-		}
-		if info.IsDir() && path != workflowsDir {
-			// Add subdirectories to the watcher
-			if err := addWatchPath(path); err != nil {
-				compileWatchLog.Printf("Failed to watch subdirectory %s: %v", path, err)
-			} else {
-				compileWatchLog.Printf("Watching subdirectory: %s", path)
-			}
-		}
-		return nil
-	})
-	if err != nil {
-		compileWatchLog.Printf("Failed to walk subdirectories: %v", err)
-	}
-
 	// Always emit the begin pattern for task integration
 	if markdownFile != "" {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Watching for file changes to %s...", markdownFile)))
@@ -111,8 +78,7 @@ func watchAndCompileWorkflows(markdownFile string, compiler *workflow.Compiler,
 	defer signal.Stop(sigChan)
 
 	// Debouncing setup
-	const debounceDelay = 300 * time.Millisecond
-	var debounceTimer *time.Timer
+	var debouncer watchDebouncer
 	modifiedFiles := make(map[string]struct{})
 
 	// Compile initially if no specific file provided
@@ -190,10 +156,7 @@ func watchAndCompileWorkflows(markdownFile string, compiler *workflow.Compiler,
 				modifiedFiles[event.Name] = struct{}{}
 
 				// Reset debounce timer
-				if debounceTimer != nil {
-					debounceTimer.Stop()
-				}
-				debounceTimer = time.AfterFunc(debounceDelay, func() {
+				debouncer.trigger(func() {
 					filesToCompile := make([]string, 0, len(modifiedFiles))
 					for file := range modifiedFiles {
 						filesToCompile = append(filesToCompile, file)
@@ -219,10 +182,72 @@ func watchAndCompileWorkflows(markdownFile string, compiler *workflow.Compiler,
 			if verbose {
 				fmt.Fprintln(os.Stderr, "\n🛑 Stopping watch mode...")
 			}
-			if debounceTimer != nil {
-				debounceTimer.Stop()
-			}
+			debouncer.stop()
 			return nil
 		}
 	}
 }
+
+// watchDebounceDelay is how long watch mode waits for a burst of file changes to settle
+const watchDebounceDelay = 300 * time.Millisecond
+
+// watchDebouncer runs an action once file changes have settled for watchDebounceDelay.
+// It is not safe for concurrent use; call it from the watch loop only.
+type watchDebouncer struct {
+	timer *time.Timer
+}
+
+// trigger (re)starts the debounce timer, replacing any pending action
+func (d *watchDebouncer) trigger(action func()) {
+	d.stop()
+	d.timer = time.AfterFunc(watchDebounceDelay, action)
+}
+
+// stop cancels the pending action, if any
+func (d *watchDebouncer) stop() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
+
+// newDirectoryTreeWatcher creates a file watcher with buffered events (for burst activity) on root
+// and its subdirectories. It also returns the function used to add further paths to the watcher.
+func newDirectoryTreeWatcher(root string, log *logger.Logger) (*fsnotify.Watcher, func(string) error, error) {
+	watcher, err := fsnotify.NewBufferedWatcher(100)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	// addWatchPath adds a path to the watcher with platform-specific configuration.
+	// On Windows, uses a larger buffer (64KB) to prevent event overflow in busy directories.
+	addWatchPath := func(path string) error {
+		if runtime.GOOS == "windows" {
+			return watcher.AddWith(path, fsnotify.WithBufferSize(64*1024))
+		}
+		return watcher.Add(path)
+	}
+
+	if err := addWatchPath(root); err != nil {
+		watcher.Close()
+		return nil, nil, fmt.Errorf("failed to watch directory %s: %w", root, err)
+	}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip errors but continue walking
+		}
+		if info.IsDir() && path != root {
+			if err := addWatchPath(path); err != nil {
+				log.Printf("Failed to watch subdirectory %s: %v", path, err)
+			} else {
+				log.Printf("Watching subdirectory: %s", path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to walk subdirectories: %v", err)
+	}
+
+	return watcher, addWatchPath, nil
+}
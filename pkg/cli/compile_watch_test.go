@@ -0,0 +1,56 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchDebouncerCoalescesTriggers(t *testing.T) {
+	var debouncer watchDebouncer
+	var runs atomic.Int32
+	done := make(chan struct{}, 1)
+
+	for range 3 {
+		debouncer.trigger(func() {
+			runs.Add(1)
+			done <- struct{}{}
+		})
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("debounced action did not run")
+	}
+	time.Sleep(2 * watchDebounceDelay)
+	assert.Equal(t, int32(1), runs.Load(), "a burst of triggers should run the action once")
+}
+
+func TestWatchDebouncerStop(t *testing.T) {
+	var debouncer watchDebouncer
+	var runs atomic.Int32
+	debouncer.trigger(func() { runs.Add(1) })
+	debouncer.stop()
+
+	time.Sleep(2 * watchDebounceDelay)
+	assert.Equal(t, int32(0), runs.Load(), "a stopped debouncer should not run the pending action")
+}
+
+func TestNewDirectoryTreeWatcher(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "shared", "nested"), 0o755), "should create subdirectories")
+
+	watcher, _, err := newDirectoryTreeWatcher(root, compileWatchLog)
+	require.NoError(t, err, "should create the watcher")
+	defer watcher.Close()
+
+	assert.ElementsMatch(t, []string{root, filepath.Join(root, "shared"), filepath.Join(root, "shared", "nested")}, watcher.WatchList(), "should watch the root and every subdirectory")
+}
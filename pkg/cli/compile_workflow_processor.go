@@ -15,6 +15,7 @@
 // Workflow Processing:
 //   - processWorkflowFile() - Process a single workflow markdown file
 //   - collectLockFilesForLinting() - Collect lock files for batch linting
+//   - appendRuleDiagnostics() - Add rule warnings and suppressions to a validation result
 //
 // These functions abstract per-file processing, allowing the main compile
 // orchestrator to focus on coordination while these handle file processing.
@@ -23,7 +24,6 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/github/gh-aw/pkg/console"
@@ -40,6 +40,7 @@ type compileWorkflowFileResult struct {
 	lockFile         string
 	validationResult ValidationResult
 	success          bool
+	cached           bool // True when the lock file was up to date in the compilation cache
 }
 
 // compileWorkflowFile compiles a single workflow file (not a campaign spec)
@@ -97,11 +98,14 @@ func compileWorkflowFile(
 	// Parse the workflow
 	workflowData, err := compiler.ParseWorkflowFile(resolvedFile)
 	if err != nil {
+		// Keep the warnings reported before parsing failed
+		appendRuleDiagnostics(&result.validationResult, compiler)
+
 		// Check if this is a shared workflow (not an error, just info)
 		if sharedErr, ok := err.(*workflow.SharedWorkflowError); ok {
 			if !jsonOutput {
 				// Print info message instead of error
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(sharedErr.Error()))
+				fmt.Fprintln(compiler.GetDiagnosticWriter(), console.FormatInfoMessage(sharedErr.Error()))
 			}
 			// Mark as valid but skipped
 			result.validationResult.Valid = true
@@ -128,7 +132,9 @@ func compileWorkflowFile(
 
 	// Compile the workflow
 	// Disable per-file actionlint run (false instead of actionlint && !noEmit) - we'll batch them
-	if err := CompileWorkflowDataWithValidation(compiler, workflowData, resolvedFile, verbose && !jsonOutput, zizmor && !noEmit, poutine && !noEmit, false, strict, validate && !noEmit); err != nil {
+	err = CompileWorkflowDataWithValidation(compiler, workflowData, resolvedFile, verbose && !jsonOutput, zizmor && !noEmit, poutine && !noEmit, false, strict, validate && !noEmit)
+	appendRuleDiagnostics(&result.validationResult, compiler)
+	if err != nil {
 		// Don't print error here - it will be displayed in the compilation summary
 		// The error is stored in ValidationResult for JSON output and summary display
 		result.validationResult.Valid = false
@@ -143,3 +149,25 @@ func compileWorkflowFile(
 	compileWorkflowProcessorLog.Printf("Successfully processed workflow file: %s", resolvedFile)
 	return result
 }
+
+// appendRuleDiagnostics adds the rule warnings and the suppressed warnings that the compiler
+// recorded for the current workflow to its validation result
+func appendRuleDiagnostics(result *ValidationResult, compiler *workflow.Compiler) {
+	diagnostics := compiler.GetWorkflowDiagnostics()
+	for _, warning := range diagnostics.Warnings {
+		result.Warnings = append(result.Warnings, CompileValidationError{
+			Type:    "compiler_warning",
+			RuleID:  string(warning.Rule),
+			Message: warning.Message,
+			Line:    warning.Line,
+		})
+	}
+	for _, suppressed := range diagnostics.Suppressed {
+		result.Suppressed = append(result.Suppressed, SuppressedWarning{
+			RuleID:  string(suppressed.Rule),
+			Reason:  suppressed.Reason,
+			Message: suppressed.Message,
+			Line:    suppressed.Line,
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/huh"
@@ -233,6 +234,52 @@ func ensureGitAttributes() error {
 	return nil
 }
 
+// ensureGitIgnore ensures that .gitignore ignores the local compilation cache
+func ensureGitIgnore() error {
+	gitLog.Print("Ensuring .gitignore is updated")
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		return err // Not in a git repository, skip
+	}
+
+	gitIgnorePath := filepath.Join(gitRoot, ".gitignore")
+	requiredEntries := []string{".github/aw/" + CompileCacheFileName}
+
+	var lines []string
+	if content, err := os.ReadFile(gitIgnorePath); err == nil {
+		lines = strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+		gitLog.Printf("Read existing .gitignore with %d lines", len(lines))
+	} else {
+		gitLog.Print("No existing .gitignore file found")
+	}
+
+	modified := false
+	for _, required := range requiredEntries {
+		if slices.ContainsFunc(lines, func(line string) bool { return strings.TrimSpace(line) == required }) {
+			continue
+		}
+		gitLog.Printf("Adding new .gitignore entry: %s", required)
+		if len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "# gh-aw incremental compilation cache", required)
+		modified = true
+	}
+
+	if !modified {
+		gitLog.Print(".gitignore already contains required entries")
+		return nil
+	}
+
+	if err := os.WriteFile(gitIgnorePath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		gitLog.Printf("Failed to write .gitignore: %v", err)
+		return fmt.Errorf("failed to write .gitignore: %w", err)
+	}
+
+	gitLog.Print("Successfully updated .gitignore")
+	return nil
+}
+
 // stageGitAttributesIfChanged stages .gitattributes if it was modified
 func stageGitAttributesIfChanged() error {
 	gitRoot, err := findGitRoot()
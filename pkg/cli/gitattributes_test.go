@@ -152,3 +152,29 @@ func TestEnsureGitAttributesNotInGitRepo(t *testing.T) {
 		t.Errorf("Expected no .gitattributes file to be created outside git repository")
 	}
 }
+
+func TestEnsureGitIgnore(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	if cmd := exec.Command("git", "init"); cmd.Run() != nil {
+		t.Skip("Skipping test - git not available")
+	}
+
+	if err := os.WriteFile(".gitignore", []byte("node_modules/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create initial .gitignore: %v", err)
+	}
+	for range 2 {
+		if err := ensureGitIgnore(); err != nil {
+			t.Fatalf("ensureGitIgnore() returned error: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(".gitignore")
+	if err != nil {
+		t.Fatalf("Failed to read .gitignore: %v", err)
+	}
+	expected := "node_modules/\n\n# gh-aw incremental compilation cache\n.github/aw/compile-cache.json\n"
+	if string(content) != expected {
+		t.Errorf("Expected .gitignore content:\n%q\nGot:\n%q", expected, string(content))
+	}
+}
@@ -159,6 +159,16 @@ func initializeBasicRepository(verbose bool) error {
 		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Configured .gitattributes"))
 	}
 
+	// Ignore the local compilation cache
+	initLog.Print("Configuring .gitignore")
+	if err := ensureGitIgnore(); err != nil {
+		initLog.Printf("Failed to configure .gitignore: %v", err)
+		return fmt.Errorf("failed to configure .gitignore: %w", err)
+	}
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Configured .gitignore"))
+	}
+
 	// Write dispatcher agent
 	initLog.Print("Writing agentic workflows dispatcher agent")
 	if err := ensureAgenticWorkflowsDispatcher(verbose, false); err != nil {
@@ -416,6 +426,16 @@ func InitRepository(verbose bool, mcp bool, tokens bool, engine string, codespac
 		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Configured .gitattributes"))
 	}
 
+	// Ignore the local compilation cache
+	initLog.Print("Configuring .gitignore")
+	if err := ensureGitIgnore(); err != nil {
+		initLog.Printf("Failed to configure .gitignore: %v", err)
+		return fmt.Errorf("failed to configure .gitignore: %w", err)
+	}
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Configured .gitignore"))
+	}
+
 	// Write dispatcher agent
 	initLog.Print("Writing agentic workflows dispatcher agent")
 	if err := ensureAgenticWorkflowsDispatcher(verbose, false); err != nil {
@@ -558,7 +578,7 @@ func InitRepository(verbose bool, mcp bool, tokens bool, engine string, codespac
 		// Create PR
 		prTitle := "Initialize agentic workflows"
 		prBody := "This PR initializes the repository for agentic workflows by:\n" +
-			"- Configuring .gitattributes\n" +
+			"- Configuring .gitattributes and .gitignore\n" +
 			"- Creating GitHub Copilot custom instructions\n" +
 			"- Setting up workflow prompts and agents"
 		if _, _, err := createPR(branchName, prTitle, prBody, verbose); err != nil {
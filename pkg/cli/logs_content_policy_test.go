@@ -0,0 +1,71 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractContentPolicyViolationsFromRun(t *testing.T) {
+	runDir := testutil.TempDir(t, "content-policy-*")
+	run := WorkflowRun{DatabaseID: 12345, WorkflowName: "Triage"}
+
+	violations, err := extractContentPolicyViolationsFromRun(runDir, run, false)
+	require.NoError(t, err, "missing agent output should not be an error")
+	assert.Empty(t, violations, "no violations without agent output")
+
+	agentOutput := `{
+  "items": [
+    {"type": "create_issue", "title": "Leak", "body": "Confidential details"},
+    {"type": "add_comment", "body": "Looks good"}
+  ],
+  "errors": [],
+  "content_policy_violations": [
+    {"index": 0, "type": "create_issue", "reason": "Content policy violation: body contains banned word 'confidential'"}
+  ]
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "agent_output.json"), []byte(agentOutput), 0o644), "should write agent output")
+
+	violations, err = extractContentPolicyViolationsFromRun(runDir, run, false)
+	require.NoError(t, err, "should extract violations")
+	require.Len(t, violations, 1, "should find one violation")
+	assert.Equal(t, ContentPolicyViolationReport{
+		Index:        0,
+		Type:         "create_issue",
+		Reason:       "Content policy violation: body contains banned word 'confidential'",
+		WorkflowName: "Triage",
+		RunID:        12345,
+	}, violations[0], "violation should be attributed to the run")
+}
+
+func TestContentPolicyViolationsInAudit(t *testing.T) {
+	processedRun := ProcessedRun{
+		Run: WorkflowRun{DatabaseID: 12345, WorkflowName: "Triage", Conclusion: "success"},
+		ContentPolicyViolations: []ContentPolicyViolationReport{
+			{Index: 2, Type: "add_comment", Reason: "Content policy violation: body matches denied pattern 'internal\\.example\\.com'"},
+		},
+	}
+
+	auditData := buildAuditData(processedRun, LogMetrics{}, nil)
+	assert.Equal(t, processedRun.ContentPolicyViolations, auditData.ContentPolicyViolations, "audit data should include the violations")
+
+	var finding *Finding
+	for i := range auditData.KeyFindings {
+		if auditData.KeyFindings[i].Title == "Content Policy Violations" {
+			finding = &auditData.KeyFindings[i]
+		}
+	}
+	require.NotNil(t, finding, "audit should report a content policy finding")
+	assert.Equal(t, "security", finding.Category, "finding category")
+	assert.Equal(t, "high", finding.Severity, "finding severity")
+
+	report := generateAuditReport(processedRun, LogMetrics{}, nil)
+	assert.Contains(t, report, "## Content Policy Violations", "markdown report should list violations")
+	assert.Contains(t, report, "- **Item 3** (`add_comment`)", "markdown report should identify the item")
+}
@@ -455,6 +455,45 @@ func extractNoopsFromRun(runDir string, run WorkflowRun, verbose bool) ([]NoopRe
 	return noops, nil
 }
 
+// extractContentPolicyViolationsFromRun extracts the safe output items rejected by
+// safe-outputs.content-policy, as recorded in agent_output.json by the output collector
+func extractContentPolicyViolationsFromRun(runDir string, run WorkflowRun, verbose bool) ([]ContentPolicyViolationReport, error) {
+	logsMetricsLog.Printf("Extracting content policy violations from run: %d", run.DatabaseID)
+
+	agentOutputPath := filepath.Join(runDir, constants.AgentOutputFilename)
+	if _, err := os.Stat(agentOutputPath); err != nil {
+		found, ok := findAgentOutputFile(runDir)
+		if !ok {
+			return nil, nil
+		}
+		agentOutputPath = found
+	}
+
+	content, err := os.ReadFile(filepath.Clean(agentOutputPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", agentOutputPath, err)
+	}
+
+	var safeOutput struct {
+		ContentPolicyViolations []ContentPolicyViolationReport `json:"content_policy_violations,omitempty"`
+	}
+	if err := json.Unmarshal(content, &safeOutput); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", agentOutputPath, err)
+	}
+
+	violations := safeOutput.ContentPolicyViolations
+	for i := range violations {
+		violations[i].WorkflowName = run.WorkflowName
+		violations[i].RunID = run.DatabaseID
+	}
+
+	if verbose && len(violations) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Found %d content policy violation(s) in safe output artifact for run %d", len(violations), run.DatabaseID)))
+	}
+	logsMetricsLog.Printf("Found %d content policy violations", len(violations))
+	return violations, nil
+}
+
 // extractMissingDataFromRun extracts missing data reports from a workflow run's artifacts
 func extractMissingDataFromRun(runDir string, run WorkflowRun, verbose bool) ([]MissingDataReport, error) {
 	logsMetricsLog.Printf("Extracting missing data from run: %d", run.DatabaseID)
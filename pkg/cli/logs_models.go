@@ -74,6 +74,7 @@ type ProcessedRun struct {
 	MissingTools            []MissingToolReport
 	MissingData             []MissingDataReport
 	Noops                   []NoopReport
+	ContentPolicyViolations []ContentPolicyViolationReport
 	MCPFailures             []MCPFailureReport
 	MCPToolUsage            *MCPToolUsageData
 	JobDetails              []JobInfoWithDuration
@@ -97,6 +98,15 @@ type NoopReport struct {
 	RunID        int64  `json:"run_id,omitempty"`        // Added for tracking which run reported this
 }
 
+// ContentPolicyViolationReport represents a safe output item rejected by safe-outputs.content-policy
+type ContentPolicyViolationReport struct {
+	Index        int    `json:"index"`                   // Position of the item in the agent output
+	Type         string `json:"type"`                    // Safe output type of the item
+	Reason       string `json:"reason"`                  // Why the item violates the content policy
+	WorkflowName string `json:"workflow_name,omitempty"` // Added for tracking which workflow reported this
+	RunID        int64  `json:"run_id,omitempty"`        // Added for tracking which run reported this
+}
+
 // MissingDataReport represents missing data reported by an agentic workflow
 type MissingDataReport struct {
 	DataType     string `json:"data_type"`
@@ -172,21 +182,22 @@ var ErrNoArtifacts = errors.New("no artifacts found for this run")
 // - If the CLI version in the summary doesn't match the current version, the run is reprocessed
 // - This ensures that bug fixes and improvements in log parsing are automatically applied
 type RunSummary struct {
-	CLIVersion              string                   `json:"cli_version"`               // CLI version used to process this run
-	RunID                   int64                    `json:"run_id"`                    // Workflow run database ID
-	ProcessedAt             time.Time                `json:"processed_at"`              // When this summary was created
-	Run                     WorkflowRun              `json:"run"`                       // Full workflow run metadata
-	Metrics                 LogMetrics               `json:"metrics"`                   // Extracted log metrics
-	AccessAnalysis          *DomainAnalysis          `json:"access_analysis"`           // Network access analysis
-	FirewallAnalysis        *FirewallAnalysis        `json:"firewall_analysis"`         // Firewall log analysis
-	RedactedDomainsAnalysis *RedactedDomainsAnalysis `json:"redacted_domains_analysis"` // Redacted URL domains analysis
-	MissingTools            []MissingToolReport      `json:"missing_tools"`             // Missing tool reports
-	MissingData             []MissingDataReport      `json:"missing_data"`              // Missing data reports
-	Noops                   []NoopReport             `json:"noops"`                     // Noop messages
-	MCPFailures             []MCPFailureReport       `json:"mcp_failures"`              // MCP server failures
-	MCPToolUsage            *MCPToolUsageData        `json:"mcp_tool_usage,omitempty"`  // MCP tool usage data
-	ArtifactsList           []string                 `json:"artifacts_list"`            // List of downloaded artifact files
-	JobDetails              []JobInfoWithDuration    `json:"job_details"`               // Job execution details
+	CLIVersion              string                         `json:"cli_version"`                         // CLI version used to process this run
+	RunID                   int64                          `json:"run_id"`                              // Workflow run database ID
+	ProcessedAt             time.Time                      `json:"processed_at"`                        // When this summary was created
+	Run                     WorkflowRun                    `json:"run"`                                 // Full workflow run metadata
+	Metrics                 LogMetrics                     `json:"metrics"`                             // Extracted log metrics
+	AccessAnalysis          *DomainAnalysis                `json:"access_analysis"`                     // Network access analysis
+	FirewallAnalysis        *FirewallAnalysis              `json:"firewall_analysis"`                   // Firewall log analysis
+	RedactedDomainsAnalysis *RedactedDomainsAnalysis       `json:"redacted_domains_analysis"`           // Redacted URL domains analysis
+	MissingTools            []MissingToolReport            `json:"missing_tools"`                       // Missing tool reports
+	MissingData             []MissingDataReport            `json:"missing_data"`                        // Missing data reports
+	Noops                   []NoopReport                   `json:"noops"`                               // Noop messages
+	ContentPolicyViolations []ContentPolicyViolationReport `json:"content_policy_violations,omitempty"` // Safe output items rejected by the content policy
+	MCPFailures             []MCPFailureReport             `json:"mcp_failures"`                        // MCP server failures
+	MCPToolUsage            *MCPToolUsageData              `json:"mcp_tool_usage,omitempty"`            // MCP tool usage data
+	ArtifactsList           []string                       `json:"artifacts_list"`                      // List of downloaded artifact files
+	JobDetails              []JobInfoWithDuration          `json:"job_details"`                         // Job execution details
 }
 
 // DownloadResult represents the result of downloading and processing a workflow run
@@ -245,6 +256,25 @@ type AwInfo struct {
 	RunID      any    `json:"run_id,omitempty"`
 	RunNumber  any    `json:"run_number,omitempty"`
 	Repository string `json:"repository,omitempty"`
+	// PromptLimit is recorded by the prompt size limit step (prompt.max-tokens)
+	PromptLimit *PromptLimitInfo `json:"prompt_limit,omitempty"`
+}
+
+// PromptLimitInfo records how the prompt size limit was enforced before the engine ran
+type PromptLimitInfo struct {
+	MaxTokens      int                    `json:"max_tokens"`
+	OriginalTokens int                    `json:"original_tokens"`
+	FinalTokens    int                    `json:"final_tokens"`
+	Truncated      []PromptTruncationInfo `json:"truncated,omitempty"`
+	Exceeded       bool                   `json:"exceeded"` // prompt did not fit and the run failed
+}
+
+// PromptTruncationInfo records the summarization or truncation of one prompt section
+type PromptTruncationInfo struct {
+	Section        string `json:"section"` // "imports", "workflow" or "import:<path>"
+	OriginalTokens int    `json:"original_tokens"`
+	FinalTokens    int    `json:"final_tokens"`
+	Action         string `json:"action"` // "summarized", "truncated" or "removed"
 }
 
 // GetFirewallVersion returns the AWF firewall version, preferring the new field name
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+// codeActionsFor returns a quick fix for each `gh aw fix` codemod that changes the document,
+// plus a combined action when several codemods apply. Each action replaces the whole document.
+func codeActionsFor(uri, text string) []lspCodeAction {
+	actions := []lspCodeAction{}
+	current := text
+	applied := 0
+
+	for _, codemod := range GetAllCodemods() {
+		// Each standalone action applies one codemod to the original document
+		if result, err := parser.ExtractFrontmatterFromContent(text); err == nil {
+			if fixed, ok, err := codemod.Apply(text, result.Frontmatter); err == nil && ok && fixed != text {
+				actions = append(actions, wholeDocumentAction(uri, text, "Fix: "+codemod.Name, fixed))
+			}
+		}
+
+		// The combined action chains the codemods, as `gh aw fix --write` does
+		result, err := parser.ExtractFrontmatterFromContent(current)
+		if err != nil {
+			continue
+		}
+		if fixed, ok, err := codemod.Apply(current, result.Frontmatter); err == nil && ok {
+			current = fixed
+			applied++
+		}
+	}
+
+	if applied > 1 && current != text {
+		actions = append(actions, wholeDocumentAction(uri, text, "Fix: apply all gh aw fix codemods", current))
+	}
+	return actions
+}
+
+// wholeDocumentAction creates a quick fix replacing the entire document with newText
+func wholeDocumentAction(uri, oldText, title, newText string) lspCodeAction {
+	lines := strings.Split(oldText, "\n")
+	return lspCodeAction{
+		Title: title,
+		Kind:  lspCodeActionQuickFix,
+		Edit: &lspWorkspaceEdit{
+			Changes: map[string][]lspTextEdit{
+				uri: {{
+					Range: lspRange{
+						Start: lspPosition{Line: 0, Character: 0},
+						End:   lspPosition{Line: len(lines) - 1, Character: len(lines[len(lines)-1])},
+					},
+					NewText: newText,
+				}},
+			},
+		},
+	}
+}
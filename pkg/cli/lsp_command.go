@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewLSPCommand creates the lsp command
+func NewLSPCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "Run a language server for agentic workflow markdown files",
+		Long: `Run a Language Server Protocol (LSP) server for agentic workflow markdown files over stdio.
+
+Editors connect to the server to get, for .github/workflows/*.md files:
+  - Diagnostics on open and save, using the same validation and file:line:column
+    positions as 'gh aw compile'
+  - Completions for frontmatter keys, engine IDs, network ecosystem identifiers,
+    GitHub toolsets and safe-output types
+  - Hover documentation for frontmatter keys from the workflow JSON schema
+  - Go-to-definition on imports: entries and {{#import}} directives
+  - Code actions that apply the 'gh aw fix' codemods
+
+The server communicates over stdin/stdout; the --stdio flag is accepted for
+compatibility with editors that always pass it.
+
+Examples:
+  gh aw lsp                    # Run the language server over stdio
+  gh aw lsp --stdio            # Same, for editors that pass --stdio
+  DEBUG=cli:lsp gh aw lsp      # Log requests to stderr`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunLSPServer()
+		},
+	}
+
+	cmd.Flags().Bool("stdio", true, "Communicate over stdin/stdout (the only supported transport)")
+
+	return cmd
+}
+
+// RunLSPServer runs the language server over stdin/stdout until the client exits
+func RunLSPServer() error {
+	// The protocol owns stdout: send anything else printed while compiling to stderr
+	protocolOut := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = protocolOut }()
+
+	lspLog.Print("Starting language server on stdio")
+	server, err := newLSPServer(os.Stdin, protocolOut)
+	if err != nil {
+		return err
+	}
+	return server.serve()
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+// completionsAt returns the completion items for a zero-based position in the document
+func (s *lspServer) completionsAt(text string, line, character int) []lspCompletionItem {
+	lines := strings.Split(text, "\n")
+	ctx, ok := analyzeYAMLCursor(lines, line, character)
+	if !ok {
+		return []lspCompletionItem{}
+	}
+
+	if ctx.IsValue {
+		valuePath := ctx.ValuePath()
+		if ctx.Key != "" {
+			afterKey := lines[line][ctx.KeyEnd:]
+			if value := strings.TrimSpace(afterKey[strings.Index(afterKey, ":")+1:]); strings.HasPrefix(value, "[") {
+				// Flow sequence: key: [a, b]
+				valuePath = append(valuePath, lspArrayItem)
+			}
+		}
+		items := s.valueCompletions(valuePath)
+		if ctx.Key == "" {
+			// A bare sequence item may also start an object item (e.g. "- path: ...")
+			items = append(items, s.keyCompletions(ctx.Path)...)
+		}
+		return items
+	}
+
+	return s.keyCompletions(ctx.Path)
+}
+
+// keyCompletions returns the frontmatter keys allowed in the mapping at path
+func (s *lspServer) keyCompletions(path []string) []lspCompletionItem {
+	properties := s.schema.properties(path)
+	items := make([]lspCompletionItem, 0, len(properties))
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		item := lspCompletionItem{
+			Label:      name,
+			Kind:       lspCompletionKindProperty,
+			InsertText: name + ": ",
+		}
+		if description := properties[name]; description != "" {
+			item.Documentation = &lspMarkupContent{Kind: "markdown", Value: description}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// valueCompletions returns the values allowed at path: schema enums and booleans, plus the
+// engine IDs and network ecosystem identifiers that the compiler knows about
+func (s *lspServer) valueCompletions(path []string) []lspCompletionItem {
+	values := make(map[string]int)
+	for _, value := range s.schema.values(path) {
+		values[value] = lspCompletionKindEnumMember
+	}
+
+	joined := strings.Join(path, ".")
+	switch joined {
+	case "engine", "engine.id":
+		for _, engine := range workflow.GetGlobalEngineRegistry().GetSupportedEngines() {
+			values[engine] = lspCompletionKindEnumMember
+		}
+	case "network.allowed.[]":
+		for _, ecosystem := range workflow.GetEcosystemNames() {
+			values[ecosystem] = lspCompletionKindValue
+		}
+	}
+
+	items := make([]lspCompletionItem, 0, len(values))
+	for _, value := range slices.Sorted(maps.Keys(values)) {
+		items = append(items, lspCompletionItem{Label: value, Kind: values[value]})
+	}
+	return items
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// yamlKeyPattern matches the mapping key declared at the start of a trimmed YAML line
+var yamlKeyPattern = regexp.MustCompile(`^(["']?)([A-Za-z0-9_$][\w.$-]*)(["']?)\s*:(\s|$)`)
+
+// yamlCursorContext describes what the cursor is positioned on inside the frontmatter
+type yamlCursorContext struct {
+	// Path is the key path of the mapping (or sequence, ending in lspArrayItem) holding the cursor
+	Path []string
+	// Key is set when the cursor is on the value of this key, or on the key itself for hover
+	Key string
+	// IsValue is true when the cursor is in a value position (after "key:" or "- ")
+	IsValue bool
+	// KeyStart and KeyEnd are the character range of Key on the line
+	KeyStart int
+	KeyEnd   int
+}
+
+// ValuePath returns the key path of the value under the cursor
+func (c yamlCursorContext) ValuePath() []string {
+	if c.Key == "" {
+		return c.Path
+	}
+	return append(slices.Clone(c.Path), c.Key)
+}
+
+// frontmatterBounds returns the zero-based line indexes of the opening and closing "---"
+// delimiters. An unterminated frontmatter extends to the end of the document.
+func frontmatterBounds(lines []string) (start, end int, ok bool) {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0, 0, false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return 0, i, true
+		}
+	}
+	return 0, len(lines), true
+}
+
+// inFrontmatter reports whether the zero-based line is inside the frontmatter
+func inFrontmatter(lines []string, line int) bool {
+	start, end, ok := frontmatterBounds(lines)
+	return ok && line > start && line < end
+}
+
+// lineIndent returns the number of leading spaces of a line
+func lineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// yamlLineKey returns the mapping key declared on a trimmed line, or "" when there is none
+func yamlLineKey(trimmed string) string {
+	match := yamlKeyPattern.FindStringSubmatch(trimmed)
+	if match == nil {
+		return ""
+	}
+	return match[2]
+}
+
+// splitListItem strips a leading "- " sequence marker, returning the item content and
+// the number of characters removed. ok is false when the line is not a sequence item.
+func splitListItem(trimmed string) (content string, offset int, ok bool) {
+	if trimmed != "-" && !strings.HasPrefix(trimmed, "- ") {
+		return trimmed, 0, false
+	}
+	content = strings.TrimLeft(trimmed[1:], " ")
+	return content, len(trimmed) - len(content), true
+}
+
+// yamlParentPath walks up from line to find the keys enclosing content at the given indentation.
+// Sequence items at siblingDash indentation are siblings of the cursor and are skipped.
+func yamlParentPath(lines []string, start, line, indent, siblingDash int) []string {
+	var path []string
+	current := indent
+	for i := line - 1; i > start && current > 0; i-- {
+		raw := lines[i]
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lineInd := lineIndent(raw)
+		if lineInd >= current {
+			continue
+		}
+
+		if content, offset, isItem := splitListItem(trimmed); isItem {
+			if lineInd == siblingDash {
+				continue
+			}
+			// The cursor is nested in this sequence item; a key on the item line
+			// encloses the cursor only when the cursor is indented below the key
+			if key := yamlLineKey(content); key != "" && lineInd+offset < current {
+				path = append([]string{key}, path...)
+			}
+			path = append([]string{lspArrayItem}, path...)
+			current = lineInd
+			continue
+		}
+
+		if key := yamlLineKey(trimmed); key != "" {
+			path = append([]string{key}, path...)
+			current = lineInd
+		}
+	}
+	return path
+}
+
+// analyzeYAMLCursor determines the frontmatter context at a zero-based line and character
+func analyzeYAMLCursor(lines []string, line, character int) (yamlCursorContext, bool) {
+	start, _, ok := frontmatterBounds(lines)
+	if !ok || !inFrontmatter(lines, line) {
+		return yamlCursorContext{}, false
+	}
+
+	raw := lines[line]
+	indent := lineIndent(raw)
+	trimmed := strings.TrimSpace(raw)
+	content, offset, isItem := splitListItem(trimmed)
+
+	var ctx yamlCursorContext
+	contentStart := indent
+	if isItem {
+		// Sequence items belong to the enclosing key, which may share the dash's indentation
+		ctx.Path = append(yamlParentPath(lines, start, line, indent+1, indent), lspArrayItem)
+		contentStart += offset
+	} else {
+		ctx.Path = yamlParentPath(lines, start, line, indent, -1)
+	}
+
+	if key := yamlLineKey(content); key != "" {
+		keyStart := contentStart + strings.Index(content, key)
+		ctx.Key = key
+		ctx.KeyStart = keyStart
+		ctx.KeyEnd = keyStart + len(key)
+		ctx.IsValue = character > strings.Index(raw[contentStart:], ":")+contentStart
+		return ctx, true
+	}
+
+	// A sequence item without a key is a value of the sequence itself
+	ctx.IsValue = isItem && character >= contentStart
+	return ctx, true
+}
+
+// wordAt returns the token under the character position and its range, where
+// tokens are delimited by whitespace, quotes, commas and flow brackets
+func wordAt(line string, character int) (word string, start, end int) {
+	isDelimiter := func(c byte) bool {
+		return strings.IndexByte(" \t\"',[]{}", c) >= 0
+	}
+	character = min(max(character, 0), len(line))
+	start = character
+	for start > 0 && !isDelimiter(line[start-1]) {
+		start--
+	}
+	end = character
+	for end < len(line) && !isDelimiter(line[end]) {
+		end++
+	}
+	return line[start:end], start, end
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+// compilerErrorLinePattern matches the IDE-parseable "file:line:column: type: message" format
+// produced by console.FormatError
+var compilerErrorLinePattern = regexp.MustCompile(`^(.+?):(\d+):(\d+): (error|warning): (.*)$`)
+
+// compilerContextLinePattern matches the source context and column marker lines rendered below an error
+var compilerContextLinePattern = regexp.MustCompile(`^\s*(\d*\s*\||\^+\s*$)`)
+
+// compileDiagnostics validates a workflow file with the compiler (without writing the
+// lock file) and converts the compiler errors and rule warnings into diagnostics
+func compileDiagnostics(path, text string) []lspDiagnostic {
+	compiler := workflow.NewCompiler(workflow.WithNoEmit(true))
+	compiler.SetDiagnosticWriter(io.Discard)
+	err := compiler.CompileWorkflow(path)
+	lines := strings.Split(text, "\n")
+	if err == nil {
+		return ruleWarningDiagnostics(compiler.GetWorkflowDiagnostics(), lines)
+	}
+
+	// Shared workflows (no 'on' field) are validated when imported, not on their own
+	var sharedErr *workflow.SharedWorkflowError
+	if errors.As(err, &sharedErr) {
+		return []lspDiagnostic{}
+	}
+
+	lspLog.Printf("Compilation of %s failed: %v", path, err)
+	return parseCompilerDiagnostics(err.Error(), lines)
+}
+
+// ruleWarningDiagnostics converts the rule warnings of a compiled workflow into diagnostics.
+// Warnings without a line are reported on the first line.
+func ruleWarningDiagnostics(workflowDiagnostics *workflow.WorkflowDiagnostics, lines []string) []lspDiagnostic {
+	diagnostics := []lspDiagnostic{}
+	for _, warning := range workflowDiagnostics.Warnings {
+		diagnostics = append(diagnostics, lspDiagnostic{
+			Range:    diagnosticRange(lines, warning.Line-1, 0),
+			Severity: lspSeverityWarning,
+			Source:   "gh-aw",
+			Message:  fmt.Sprintf("[%s] %s", warning.Rule, warning.Message),
+		})
+	}
+	return diagnostics
+}
+
+// parseCompilerDiagnostics converts compiler error output into diagnostics. Positions are
+// converted from the compiler's 1-based lines and byte columns; the range extends to the end
+// of the word at the position. Errors without a position are reported on the first line.
+func parseCompilerDiagnostics(output string, lines []string) []lspDiagnostic {
+	diagnostics := []lspDiagnostic{}
+	seen := make(map[string]bool)
+	var current *lspDiagnostic
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		key := strconv.Itoa(current.Range.Start.Line) + ":" + strconv.Itoa(current.Range.Start.Character) + ":" + current.Message
+		if !seen[key] {
+			seen[key] = true
+			diagnostics = append(diagnostics, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if match := compilerErrorLinePattern.FindStringSubmatch(line); match != nil {
+			flush()
+			lineNum, _ := strconv.Atoi(match[2])
+			column, _ := strconv.Atoi(match[3])
+			severity := lspSeverityError
+			if match[4] == "warning" {
+				severity = lspSeverityWarning
+			}
+			current = &lspDiagnostic{
+				Range:    diagnosticRange(lines, lineNum-1, column-1),
+				Severity: severity,
+				Source:   "gh-aw",
+				Message:  strings.TrimSpace(match[5]),
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(strings.TrimPrefix(line, ": "))
+		if trimmed == "" || compilerContextLinePattern.MatchString(line) {
+			continue
+		}
+		if current == nil {
+			current = &lspDiagnostic{
+				Range:    diagnosticRange(lines, 0, 0),
+				Severity: lspSeverityError,
+				Source:   "gh-aw",
+				Message:  trimmed,
+			}
+			continue
+		}
+		// Wrapped causes repeat the message; only append new information
+		if !strings.Contains(current.Message, trimmed) {
+			current.Message += ": " + trimmed
+		}
+	}
+	flush()
+
+	return diagnostics
+}
+
+// diagnosticRange returns the range from a zero-based line and byte column to the end of the
+// word there, with the columns converted to the UTF-16 offsets used by LSP positions
+func diagnosticRange(lines []string, line, character int) lspRange {
+	line = max(line, 0)
+	character = max(character, 0)
+	end := character
+	if line < len(lines) {
+		text := lines[line]
+		character = min(character, len(text))
+		end = character
+		for end < len(text) && strings.IndexByte(" \t:", text[end]) < 0 {
+			end++
+		}
+		if end == character {
+			end = len(text)
+		}
+		character = utf16Offset(text, character)
+		end = utf16Offset(text, end)
+	}
+	return lspRange{
+		Start: lspPosition{Line: line, Character: character},
+		End:   lspPosition{Line: line, Character: end},
+	}
+}
+
+// utf16Offset converts a byte offset in text to a count of UTF-16 code units
+func utf16Offset(text string, byteOffset int) int {
+	return len(utf16.Encode([]rune(text[:byteOffset])))
+}
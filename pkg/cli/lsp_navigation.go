@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+// hoverAt returns the schema documentation for the frontmatter key at a zero-based position
+func (s *lspServer) hoverAt(text string, line, character int) *lspHover {
+	lines := strings.Split(text, "\n")
+	ctx, ok := analyzeYAMLCursor(lines, line, character)
+	if !ok || ctx.Key == "" || character < ctx.KeyStart || character > ctx.KeyEnd {
+		return nil
+	}
+
+	nodes := s.schema.lookup(ctx.ValuePath())
+	description := s.schema.description(nodes)
+	if description == "" {
+		return nil
+	}
+
+	value := fmt.Sprintf("**%s**\n\n%s", ctx.Key, description)
+	if values := s.schema.values(ctx.ValuePath()); len(values) > 0 && len(values) <= 20 {
+		value += "\n\nAllowed values: `" + strings.Join(values, "`, `") + "`"
+	}
+
+	return &lspHover{
+		Contents: lspMarkupContent{Kind: "markdown", Value: value},
+		Range: &lspRange{
+			Start: lspPosition{Line: line, Character: ctx.KeyStart},
+			End:   lspPosition{Line: line, Character: ctx.KeyEnd},
+		},
+	}
+}
+
+// definitionAt resolves an import under a zero-based position, either an entry of the
+// imports: frontmatter field or an {{#import}} directive in the markdown body.
+// Only local files are resolved; remote workflowspecs are not downloaded.
+func (s *lspServer) definitionAt(documentPath, text string, line, character int) *lspLocation {
+	lines := strings.Split(text, "\n")
+	if line < 0 || line >= len(lines) {
+		return nil
+	}
+
+	var importPath string
+	if ctx, ok := analyzeYAMLCursor(lines, line, character); ok {
+		valuePath := ctx.ValuePath()
+		if !ctx.IsValue || len(valuePath) == 0 || valuePath[0] != "imports" {
+			return nil
+		}
+		if ctx.Key != "" && ctx.Key != "imports" && ctx.Key != "path" {
+			return nil
+		}
+		importPath, _, _ = wordAt(lines[line], character)
+	} else if directive := parser.ParseImportDirective(lines[line]); directive != nil {
+		importPath = directive.Path
+	}
+
+	// Strip section references (file.md#Section)
+	importPath, _, _ = strings.Cut(importPath, "#")
+	if importPath == "" {
+		return nil
+	}
+
+	fullPath := importPath
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(filepath.Dir(documentPath), importPath)
+	}
+	if _, err := os.Stat(fullPath); err != nil {
+		lspLog.Printf("Import %s does not resolve to a local file: %v", importPath, err)
+		return nil
+	}
+
+	return &lspLocation{URI: pathToFileURI(fullPath)}
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+// lspArrayItem is the key path segment used for the items of a YAML sequence
+const lspArrayItem = "[]"
+
+// lspSchema navigates the main workflow JSON schema by frontmatter key path
+type lspSchema struct {
+	root map[string]any
+}
+
+// newLSPSchema parses the embedded main workflow schema
+func newLSPSchema() (*lspSchema, error) {
+	var root map[string]any
+	if err := json.Unmarshal([]byte(parser.GetMainWorkflowSchema()), &root); err != nil {
+		return nil, fmt.Errorf("failed to parse main workflow schema: %w", err)
+	}
+	return &lspSchema{root: root}, nil
+}
+
+// resolveRef follows local "#/$defs/..." references
+func (s *lspSchema) resolveRef(node map[string]any) map[string]any {
+	// Bound the number of hops so a cyclic reference cannot loop forever
+	for range 10 {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node
+		}
+		defs, _ := s.root["$defs"].(map[string]any)
+		target, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		if !ok {
+			return node
+		}
+		// Keep the description of the referencing node, which is usually more specific
+		if _, hasDescription := node["description"]; hasDescription {
+			merged := maps.Clone(target)
+			merged["description"] = node["description"]
+			target = merged
+		}
+		node = target
+	}
+	return node
+}
+
+// alternatives returns the node and all of its oneOf/anyOf/allOf alternatives, resolved
+func (s *lspSchema) alternatives(node map[string]any) []map[string]any {
+	node = s.resolveRef(node)
+	result := []map[string]any{node}
+	for _, keyword := range []string{"oneOf", "anyOf", "allOf"} {
+		options, _ := node[keyword].([]any)
+		for _, option := range options {
+			if optionMap, ok := option.(map[string]any); ok {
+				result = append(result, s.alternatives(optionMap)...)
+			}
+		}
+	}
+	return result
+}
+
+// lookup returns the schema nodes describing the value at the given key path.
+// Path segments are property names, or lspArrayItem for sequence items.
+func (s *lspSchema) lookup(path []string) []map[string]any {
+	nodes := []map[string]any{s.root}
+	for _, segment := range path {
+		var next []map[string]any
+		for _, node := range nodes {
+			for _, alt := range s.alternatives(node) {
+				if segment == lspArrayItem {
+					if items, ok := alt["items"].(map[string]any); ok {
+						next = append(next, items)
+					}
+					continue
+				}
+				if properties, ok := alt["properties"].(map[string]any); ok {
+					if property, ok := properties[segment].(map[string]any); ok {
+						next = append(next, property)
+						continue
+					}
+				}
+				if additional, ok := alt["additionalProperties"].(map[string]any); ok {
+					next = append(next, additional)
+				}
+			}
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// properties returns the property names allowed at the given key path with their descriptions
+func (s *lspSchema) properties(path []string) map[string]string {
+	result := make(map[string]string)
+	for _, node := range s.lookup(path) {
+		for _, alt := range s.alternatives(node) {
+			properties, _ := alt["properties"].(map[string]any)
+			for name, property := range properties {
+				propertyMap, ok := property.(map[string]any)
+				if !ok {
+					continue
+				}
+				if _, exists := result[name]; !exists || result[name] == "" {
+					result[name] = s.description([]map[string]any{propertyMap})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// values returns the enum values (and booleans) allowed at the given key path, sorted
+func (s *lspSchema) values(path []string) []string {
+	seen := make(map[string]bool)
+	for _, node := range s.lookup(path) {
+		for _, alt := range s.alternatives(node) {
+			enum, _ := alt["enum"].([]any)
+			for _, value := range enum {
+				if str, ok := value.(string); ok {
+					seen[str] = true
+				}
+			}
+			if alt["type"] == "boolean" {
+				seen["true"] = true
+				seen["false"] = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// description returns the first description found among the nodes and their alternatives
+func (s *lspSchema) description(nodes []map[string]any) string {
+	for _, node := range nodes {
+		for _, alt := range s.alternatives(node) {
+			if description, ok := alt["description"].(string); ok && description != "" {
+				return description
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var lspLog = logger.New("cli:lsp")
+
+// lspServer is a language server for agentic workflow markdown files
+type lspServer struct {
+	reader    *bufio.Reader
+	writer    io.Writer
+	writeMu   sync.Mutex
+	schema    *lspSchema
+	documents map[string]string // Open documents by URI
+}
+
+// newLSPServer creates a language server reading requests from in and writing responses to out
+func newLSPServer(in io.Reader, out io.Writer) (*lspServer, error) {
+	schema, err := newLSPSchema()
+	if err != nil {
+		return nil, err
+	}
+	return &lspServer{
+		reader:    bufio.NewReader(in),
+		writer:    out,
+		schema:    schema,
+		documents: make(map[string]string),
+	}, nil
+}
+
+// serve processes messages until the client sends "exit" or closes the input
+func (s *lspServer) serve() error {
+	for {
+		msg, err := readLSPMessage(s.reader)
+		if errors.Is(err, io.EOF) {
+			lspLog.Print("Input closed, stopping language server")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			lspLog.Print("Received exit notification")
+			return nil
+		}
+
+		result, rpcErr := s.dispatch(msg)
+		if len(msg.ID) == 0 {
+			// Notifications have no response
+			if rpcErr != nil {
+				lspLog.Printf("Notification %s failed: %s", msg.Method, rpcErr.Message)
+			}
+			continue
+		}
+		if err := s.respond(msg.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+}
+
+// dispatch handles one request or notification
+func (s *lspServer) dispatch(msg *lspMessage) (any, *lspError) {
+	lspLog.Printf("Handling %s", msg.Method)
+
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    lspTextDocumentSyncFull,
+					"save":      map[string]any{"includeText": true},
+				},
+				"completionProvider": map[string]any{"triggerCharacters": []string{":", " ", "-"}},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+				"codeActionProvider": map[string]any{"codeActionKinds": []string{lspCodeActionQuickFix}},
+			},
+			"serverInfo": map[string]any{"name": "gh-aw", "version": GetVersion()},
+		}, nil
+
+	case "initialized", "shutdown":
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params lspDidOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.documents[params.TextDocument.URI] = params.TextDocument.Text
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+
+	case "textDocument/didChange":
+		var params lspDidChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		// Full synchronization: the last change holds the whole document
+		if len(params.ContentChanges) > 0 {
+			s.documents[params.TextDocument.URI] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		}
+		return nil, nil
+
+	case "textDocument/didSave":
+		var params lspDidSaveParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if params.Text != nil {
+			s.documents[params.TextDocument.URI] = *params.Text
+		}
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+
+	case "textDocument/didClose":
+		var params lspDidCloseParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return nil, s.notify("textDocument/publishDiagnostics", lspPublishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []lspDiagnostic{}})
+
+	case "textDocument/completion":
+		var params lspTextDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.completionsAt(s.documents[params.TextDocument.URI], params.Position.Line, params.Position.Character), nil
+
+	case "textDocument/hover":
+		var params lspTextDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.hoverAt(s.documents[params.TextDocument.URI], params.Position.Line, params.Position.Character), nil
+
+	case "textDocument/definition":
+		var params lspTextDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		path, ok := fileURIToPath(params.TextDocument.URI)
+		if !ok {
+			return nil, nil
+		}
+		return s.definitionAt(path, s.documents[params.TextDocument.URI], params.Position.Line, params.Position.Character), nil
+
+	case "textDocument/codeAction":
+		var params lspCodeActionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return codeActionsFor(params.TextDocument.URI, s.documents[params.TextDocument.URI]), nil
+	}
+
+	return nil, &lspError{Code: lspErrMethodNotFound, Message: "method not found: " + msg.Method}
+}
+
+// publishDiagnostics compiles the saved workflow and sends its diagnostics to the client
+func (s *lspServer) publishDiagnostics(uri string) *lspError {
+	path, ok := fileURIToPath(uri)
+	if !ok || !strings.HasSuffix(path, ".md") {
+		return nil
+	}
+	diagnostics := compileDiagnostics(path, s.documents[uri])
+	lspLog.Printf("Publishing %d diagnostics for %s", len(diagnostics), path)
+	return s.notify("textDocument/publishDiagnostics", lspPublishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+}
+
+// respond sends the response to a request
+func (s *lspServer) respond(id json.RawMessage, result any, rpcErr *lspError) error {
+	msg := &lspMessage{ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal result: %w", err)
+		}
+		msg.Result = data
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return writeLSPMessage(s.writer, msg)
+}
+
+// notify sends a notification to the client
+func (s *lspServer) notify(method string, params any) *lspError {
+	data, err := json.Marshal(params)
+	if err == nil {
+		s.writeMu.Lock()
+		err = writeLSPMessage(s.writer, &lspMessage{Method: method, Params: data})
+		s.writeMu.Unlock()
+	}
+	if err != nil {
+		lspLog.Printf("Failed to send %s: %v", method, err)
+		return &lspError{Code: lspErrInternal, Message: err.Error()}
+	}
+	return nil
+}
+
+// invalidParams creates an invalid params error
+func invalidParams(err error) *lspError {
+	return &lspError{Code: lspErrInvalidParams, Message: "invalid params: " + err.Error()}
+}
+
+// fileURIToPath converts a file:// URI to a local path
+func fileURIToPath(uri string) (string, bool) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return "", false
+	}
+	return filepath.FromSlash(parsed.Path), true
+}
+
+// pathToFileURI converts a local path to a file:// URI
+func pathToFileURI(path string) string {
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
@@ -0,0 +1,302 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lspTestWorkflow is a workflow with a deprecated field, an import and an unknown safe output
+const lspTestWorkflow = `---
+on: workflow_dispatch
+engine: copilot
+timeout_minutes: 10
+imports:
+  - shared/tools.md
+network:
+  allowed:
+    - defaults
+tools:
+  github:
+    toolsets: [repos]
+safe-outputs:
+  create-issue:
+  bogus-field: 1
+---
+
+# Test
+
+{{#import shared/tools.md}}
+`
+
+func newTestLSPServer(t *testing.T) *lspServer {
+	t.Helper()
+	server, err := newLSPServer(strings.NewReader(""), &bytes.Buffer{})
+	require.NoError(t, err, "language server should be created")
+	return server
+}
+
+func TestLSPMessageFraming(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeLSPMessage(&buf, &lspMessage{ID: json.RawMessage("1"), Result: json.RawMessage("null")}), "should write message")
+	assert.Equal(t, "Content-Length: 38\r\n\r\n{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":null}", buf.String(), "message should be framed with Content-Length")
+
+	msg, err := readLSPMessage(bufio.NewReader(&buf))
+	require.NoError(t, err, "should read framed message")
+	assert.Equal(t, "1", string(msg.ID), "id should round-trip")
+
+	_, err = readLSPMessage(bufio.NewReader(strings.NewReader("Content-Length: abc\r\n\r\n{}")))
+	assert.Error(t, err, "invalid Content-Length should be rejected")
+}
+
+func TestLSPCompletions(t *testing.T) {
+	server := newTestLSPServer(t)
+	labels := func(text string, line, character int) []string {
+		var result []string
+		for _, item := range server.completionsAt(text, line, character) {
+			result = append(result, item.Label)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		text      string
+		line      int
+		character int
+		contains  []string
+		excludes  []string
+	}{
+		{
+			name:     "top-level keys",
+			text:     "---\neng\n---\n",
+			line:     1,
+			contains: []string{"engine", "safe-outputs", "network", "imports"},
+		},
+		{
+			name:      "engine IDs",
+			text:      "---\nengine: \n---\n",
+			line:      1,
+			character: 8,
+			contains:  []string{"claude", "codex", "copilot", "custom"},
+		},
+		{
+			name:      "ecosystem identifiers",
+			text:      "---\nnetwork:\n  allowed:\n    - py\n---\n",
+			line:      3,
+			character: 8,
+			contains:  []string{"defaults", "python", "node", "github"},
+		},
+		{
+			name:      "GitHub toolsets in a flow sequence",
+			text:      "---\ntools:\n  github:\n    toolsets: [repos, ]\n---\n",
+			line:      3,
+			character: 22,
+			contains:  []string{"repos", "issues", "pull_requests"},
+		},
+		{
+			name:      "safe-output types",
+			text:      "---\nsafe-outputs:\n  create-issue:\n  \n---\n",
+			line:      3,
+			character: 2,
+			contains:  []string{"create-issue", "add-comment", "create-pull-request", "noop"},
+			excludes:  []string{"engine"},
+		},
+		{
+			name:      "safe-output type options",
+			text:      "---\nsafe-outputs:\n  create-issue:\n    \n---\n",
+			line:      3,
+			character: 4,
+			contains:  []string{"title-prefix", "labels", "max"},
+			excludes:  []string{"create-issue"},
+		},
+		{
+			name: "markdown body has no completions",
+			text: "---\non: push\n---\n\n# Title\n",
+			line: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := labels(tt.text, tt.line, tt.character)
+			for _, want := range tt.contains {
+				assert.Contains(t, got, want, "completions should include %q", want)
+			}
+			for _, unwanted := range tt.excludes {
+				assert.NotContains(t, got, unwanted, "completions should not include %q", unwanted)
+			}
+			if len(tt.contains) == 0 {
+				assert.Empty(t, got, "no completions expected")
+			}
+		})
+	}
+}
+
+func TestLSPHover(t *testing.T) {
+	server := newTestLSPServer(t)
+
+	hover := server.hoverAt(lspTestWorkflow, 2, 2)
+	require.NotNil(t, hover, "hover should be returned for the engine key")
+	assert.Contains(t, hover.Contents.Value, "**engine**", "hover should name the key")
+	assert.Contains(t, hover.Contents.Value, "AI engine configuration", "hover should show the schema description")
+	assert.Equal(t, lspRange{Start: lspPosition{Line: 2, Character: 0}, End: lspPosition{Line: 2, Character: 6}}, *hover.Range, "hover should cover the key")
+
+	hover = server.hoverAt(lspTestWorkflow, 13, 4)
+	require.NotNil(t, hover, "hover should be returned for nested keys")
+	assert.Contains(t, hover.Contents.Value, "**create-issue**", "hover should name the nested key")
+
+	assert.Nil(t, server.hoverAt(lspTestWorkflow, 2, 10), "no hover on values")
+	assert.Nil(t, server.hoverAt(lspTestWorkflow, 18, 2), "no hover in the markdown body")
+}
+
+func TestLSPDefinition(t *testing.T) {
+	server := newTestLSPServer(t)
+	tmpDir := testutil.TempDir(t, "lsp-definition-*")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0o755), "should create shared directory")
+	sharedPath := filepath.Join(workflowsDir, "shared", "tools.md")
+	require.NoError(t, os.WriteFile(sharedPath, []byte("---\ntools:\n  github:\n---\n"), 0o644), "should write shared workflow")
+	workflowPath := filepath.Join(workflowsDir, "test.md")
+
+	location := server.definitionAt(workflowPath, lspTestWorkflow, 5, 8)
+	require.NotNil(t, location, "imports: entries should resolve")
+	assert.Equal(t, pathToFileURI(sharedPath), location.URI, "definition should point at the imported file")
+
+	location = server.definitionAt(workflowPath, lspTestWorkflow, 19, 12)
+	require.NotNil(t, location, "import directives should resolve")
+	assert.Equal(t, pathToFileURI(sharedPath), location.URI, "definition should point at the imported file")
+
+	assert.Nil(t, server.definitionAt(workflowPath, lspTestWorkflow, 2, 9), "non-import values have no definition")
+	assert.Nil(t, server.definitionAt(workflowPath, "---\nimports:\n  - owner/repo/file.md@v1\n---\n", 2, 6), "remote imports are not resolved")
+}
+
+func TestLSPCodeActions(t *testing.T) {
+	actions := codeActionsFor("file:///w.md", lspTestWorkflow)
+	require.Len(t, actions, 1, "only the timeout_minutes codemod applies")
+	assert.Equal(t, "Fix: Migrate timeout_minutes to timeout-minutes", actions[0].Title, "action should be named after the codemod")
+	assert.Equal(t, lspCodeActionQuickFix, actions[0].Kind, "action should be a quick fix")
+
+	edits := actions[0].Edit.Changes["file:///w.md"]
+	require.Len(t, edits, 1, "action should replace the document")
+	assert.Contains(t, edits[0].NewText, "timeout-minutes: 10", "the codemod should be applied")
+	assert.Equal(t, lspPosition{Line: 20, Character: 0}, edits[0].Range.End, "the edit should cover the whole document")
+
+	assert.Empty(t, codeActionsFor("file:///w.md", "---\non: push\n---\n"), "no actions when no codemod applies")
+}
+
+func TestParseCompilerDiagnostics(t *testing.T) {
+	lines := strings.Split(lspTestWorkflow, "\n")
+	output := ".github/workflows/test.md:15:3: error: Unknown property: bogus-field\n" +
+		"14 |   create-issue:\n" +
+		"15 |   bogus-field: 1\n" +
+		"       ^^^^^^^^^^^\n" +
+		": Unknown property: bogus-field\n"
+
+	diagnostics := parseCompilerDiagnostics(output, lines)
+	require.Len(t, diagnostics, 1, "duplicate wrapped messages should collapse")
+	assert.Equal(t, "Unknown property: bogus-field", diagnostics[0].Message, "context lines should be dropped")
+	assert.Equal(t, lspRange{Start: lspPosition{Line: 14, Character: 2}, End: lspPosition{Line: 14, Character: 13}}, diagnostics[0].Range, "positions should be zero-based and span the key")
+	assert.Equal(t, lspSeverityError, diagnostics[0].Severity, "compiler errors are errors")
+
+	diagnostics = parseCompilerDiagnostics("failed to read file", lines)
+	require.Len(t, diagnostics, 1, "errors without a position should still be reported")
+	assert.Equal(t, 0, diagnostics[0].Range.Start.Line, "errors without a position go on the first line")
+
+	diagnostics = parseCompilerDiagnostics(".github/workflows/test.md:1:1: warning: [invalid-suppression] gh-aw-ignore names unknown rule \"x\"\n", lines)
+	require.Len(t, diagnostics, 1, "warnings should be reported")
+	assert.Equal(t, lspSeverityWarning, diagnostics[0].Severity, "compiler warnings are warnings")
+}
+
+func TestDiagnosticRangeUTF16(t *testing.T) {
+	// "é" is 2 bytes and 1 UTF-16 code unit, "😀" is 4 bytes and 2 UTF-16 code units
+	lines := []string{"name: é😀 bogus-field"}
+	column := strings.Index(lines[0], "bogus-field")
+
+	assert.Equal(t, lspRange{Start: lspPosition{Line: 0, Character: 10}, End: lspPosition{Line: 0, Character: 21}}, diagnosticRange(lines, 0, column), "byte columns should be converted to UTF-16 offsets")
+}
+
+func TestRuleWarningDiagnostics(t *testing.T) {
+	lines := strings.Split(lspTestWorkflow, "\n")
+	diagnostics := ruleWarningDiagnostics(&workflow.WorkflowDiagnostics{Warnings: []workflow.RuleDiagnostic{
+		{Rule: workflow.RuleIDTokenWrite, Message: "id-token: write"},
+		{Rule: workflow.RuleInvalidSuppression, Message: "unknown rule", Line: 3},
+	}}, lines)
+
+	require.Len(t, diagnostics, 2, "every warning should be reported")
+	assert.Equal(t, lspSeverityWarning, diagnostics[0].Severity, "rule warnings are warnings")
+	assert.Equal(t, "[id-token-write] id-token: write", diagnostics[0].Message, "message should name the rule")
+	assert.Equal(t, 0, diagnostics[0].Range.Start.Line, "warnings without a line go on the first line")
+	assert.Equal(t, 2, diagnostics[1].Range.Start.Line, "warnings with a line should be placed on it")
+}
+
+func TestLSPServerSession(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "lsp-session-*")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0o755), "should create shared directory")
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "tools.md"), []byte("---\ntools:\n  github:\n---\n"), 0o644), "should write shared workflow")
+	workflowPath := filepath.Join(workflowsDir, "test.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(lspTestWorkflow), 0o644), "should write workflow")
+	uri := pathToFileURI(workflowPath)
+
+	var input bytes.Buffer
+	send := func(id int, method string, params any) {
+		msg := &lspMessage{Method: method}
+		if id > 0 {
+			msg.ID = json.RawMessage(strconv.Itoa(id))
+		}
+		if params != nil {
+			data, err := json.Marshal(params)
+			require.NoError(t, err, "should marshal params")
+			msg.Params = data
+		}
+		require.NoError(t, writeLSPMessage(&input, msg), "should write request")
+	}
+	send(1, "initialize", map[string]any{})
+	send(0, "textDocument/didOpen", lspDidOpenParams{TextDocument: lspTextDocumentItem{URI: uri, LanguageID: "markdown", Text: lspTestWorkflow}})
+	send(2, "textDocument/unknown", nil)
+	send(3, "shutdown", nil)
+	send(0, "exit", nil)
+
+	var output bytes.Buffer
+	server, err := newLSPServer(&input, &output)
+	require.NoError(t, err, "language server should be created")
+	require.NoError(t, server.serve(), "session should end cleanly on exit")
+
+	reader := bufio.NewReader(&output)
+	var messages []*lspMessage
+	for {
+		msg, err := readLSPMessage(reader)
+		if err != nil {
+			break
+		}
+		messages = append(messages, msg)
+	}
+	require.Len(t, messages, 4, "initialize, diagnostics, unknown method and shutdown should be answered")
+
+	assert.Contains(t, string(messages[0].Result), `"hoverProvider":true`, "initialize should advertise capabilities")
+
+	assert.Equal(t, "textDocument/publishDiagnostics", messages[1].Method, "opening a document should publish diagnostics")
+	var published lspPublishDiagnosticsParams
+	require.NoError(t, json.Unmarshal(messages[1].Params, &published), "diagnostics should parse")
+	require.NotEmpty(t, published.Diagnostics, "the unknown safe output should be reported")
+	assert.Contains(t, published.Diagnostics[0].Message, "bogus-field", "diagnostic should explain the error")
+	assert.Equal(t, 14, published.Diagnostics[0].Range.Start.Line, "diagnostic should point at the offending line")
+
+	require.NotNil(t, messages[2].Error, "unknown methods should fail")
+	assert.Equal(t, lspErrMethodNotFound, messages[2].Error.Code, "unknown methods should return method not found")
+
+	assert.Equal(t, "null", string(messages[3].Result), "shutdown should return null")
+}
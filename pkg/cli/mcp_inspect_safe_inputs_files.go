@@ -74,6 +74,16 @@ func writeSafeInputsFiles(dir string, safeInputsConfig *workflow.SafeInputsConfi
 	}
 
 	// Generate and write tool handler files
+	if err := writeSafeInputToolHandlers(dir, safeInputsConfig, verbose); err != nil {
+		return err
+	}
+
+	mcpInspectLog.Printf("Successfully wrote all safe-inputs files")
+	return nil
+}
+
+// writeSafeInputToolHandlers writes the generated handler file for every safe-input tool
+func writeSafeInputToolHandlers(dir string, safeInputsConfig *workflow.SafeInputsConfig, verbose bool) error {
 	for toolName, toolConfig := range safeInputsConfig.Tools {
 		var content string
 		var extension string
@@ -87,6 +97,12 @@ func writeSafeInputsFiles(dir string, safeInputsConfig *workflow.SafeInputsConfi
 		} else if toolConfig.Py != "" {
 			content = workflow.GenerateSafeInputPythonToolScriptForInspector(toolConfig)
 			extension = ".py"
+		} else if toolConfig.Go != "" {
+			content = workflow.GenerateSafeInputGoToolScriptForInspector(toolConfig)
+			extension = ".go"
+		} else if toolConfig.Container != nil {
+			content = workflow.GenerateSafeInputContainerToolScriptForInspector(toolConfig)
+			extension = ".sh"
 		} else {
 			continue
 		}
@@ -106,6 +122,5 @@ func writeSafeInputsFiles(dir string, safeInputsConfig *workflow.SafeInputsConfi
 		}
 	}
 
-	mcpInspectLog.Printf("Successfully wrote all safe-inputs files")
 	return nil
 }
@@ -0,0 +1,492 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var promptLog = logger.New("cli:prompt")
+
+// PromptConfig holds configuration for prompt command execution
+type PromptConfig struct {
+	WorkflowName string
+	EventFile    string
+	EventName    string
+	Inputs       []string
+	Repository   string
+	Actor        string
+	PromptsDir   string
+	DiffRef      string
+	Raw          bool
+	JSONOutput   bool
+	Verbose      bool
+}
+
+// PromptOutput is the JSON output of the prompt command
+type PromptOutput struct {
+	Workflow   string                  `json:"workflow"`
+	EventName  string                  `json:"event_name"`
+	Tokens     int                     `json:"tokens"`
+	MaxTokens  int                     `json:"max_tokens,omitempty"`
+	Sources    []workflow.PromptSource `json:"sources"`
+	Unresolved []string                `json:"unresolved,omitempty"`
+	Prompt     string                  `json:"prompt"`
+	BaseRef    string                  `json:"base_ref,omitempty"`
+	BaseTokens int                     `json:"base_tokens,omitempty"`
+	Diff       string                  `json:"diff,omitempty"`
+}
+
+// NewPromptCommand creates the prompt command
+func NewPromptCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompt <workflow>",
+		Short: "Preview the final prompt the agent receives for an event",
+		Long: `Render the exact prompt the agent receives when a workflow runs, without running it.
+
+The prompt is assembled the same way as in the agent job: built-in sections,
+imports and the workflow markdown are combined, runtime imports are expanded,
+expressions are evaluated against the event and template conditionals are rendered.
+
+By default, each contributing source is printed with a boundary header, followed
+by a table of token estimates per source. Expressions that only resolve while the
+workflow runs (needs.*, steps.*, env.*, vars.*, secrets.*) are kept as-is and listed.
+
+Use --diff to compare the prompt with the prompt of the same workflow at another
+git revision.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` prompt issue-triage                                  # Preview for a workflow_dispatch run
+  ` + string(constants.CLIExtensionPrefix) + ` prompt issue-triage --event-name issues --event event.json
+  ` + string(constants.CLIExtensionPrefix) + ` prompt release-notes -F version=v1.2.0                # Set workflow inputs
+  ` + string(constants.CLIExtensionPrefix) + ` prompt issue-triage --raw > prompt.txt                 # Only the final prompt text
+  ` + string(constants.CLIExtensionPrefix) + ` prompt issue-triage --diff main                        # Compare with the prompt at main
+  ` + string(constants.CLIExtensionPrefix) + ` prompt issue-triage --json                             # Output in JSON format`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			eventFile, _ := cmd.Flags().GetString("event")
+			eventName, _ := cmd.Flags().GetString("event-name")
+			inputs, _ := cmd.Flags().GetStringArray("raw-field")
+			repository, _ := cmd.Flags().GetString("repo")
+			actor, _ := cmd.Flags().GetString("actor")
+			promptsDir, _ := cmd.Flags().GetString("prompts-dir")
+			diffRef, _ := cmd.Flags().GetString("diff")
+			raw, _ := cmd.Flags().GetBool("raw")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			config := PromptConfig{
+				WorkflowName: args[0],
+				EventFile:    eventFile,
+				EventName:    eventName,
+				Inputs:       inputs,
+				Repository:   repository,
+				Actor:        actor,
+				PromptsDir:   promptsDir,
+				DiffRef:      diffRef,
+				Raw:          raw,
+				JSONOutput:   jsonOutput,
+				Verbose:      verbose,
+			}
+
+			return RunPrompt(config)
+		},
+	}
+
+	cmd.Flags().String("event", "", "Path to a JSON file with the event payload (github.event)")
+	cmd.Flags().String("event-name", "workflow_dispatch", "Name of the triggering event (github.event_name)")
+	cmd.Flags().StringArrayP("raw-field", "F", []string{}, "Add a workflow input in key=value format (can be used multiple times)")
+	cmd.Flags().String("actor", "", "User that triggers the run (default: event sender or repository owner)")
+	cmd.Flags().String("prompts-dir", "", "Directory with the built-in prompt files (default: actions/setup/md in the repository, if present)")
+	cmd.Flags().String("diff", "", "Compare with the prompt rendered from the workflow at this git revision")
+	cmd.Flags().Bool("raw", false, "Print only the final prompt text")
+	addRepoFlag(cmd)
+	addJSONFlag(cmd)
+
+	// Register completions
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunPrompt renders the prompt of a workflow with the given configuration
+func RunPrompt(config PromptConfig) error {
+	promptLog.Printf("Rendering prompt: workflow=%s, event=%s, diff=%s", config.WorkflowName, config.EventName, config.DiffRef)
+
+	if config.Raw && config.JSONOutput {
+		return fmt.Errorf("--raw and --json cannot be used together")
+	}
+
+	workflowPath, err := resolveWorkflowFile(config.WorkflowName, config.Verbose)
+	if err != nil {
+		return err
+	}
+
+	opts, err := buildPromptRenderOptions(config, workflowPath)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := workflow.NewCompiler().RenderPrompt(workflowPath, opts)
+	if err != nil {
+		return fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	output := PromptOutput{
+		Workflow:   normalizeWorkflowID(workflowPath),
+		EventName:  opts.EventName,
+		Tokens:     rendered.Tokens,
+		MaxTokens:  rendered.MaxTokens,
+		Sources:    rendered.Sources,
+		Unresolved: rendered.Unresolved,
+		Prompt:     rendered.Text,
+	}
+
+	if config.DiffRef != "" {
+		base, err := renderPromptAtRevision(workflowPath, config.DiffRef, opts)
+		if err != nil {
+			return err
+		}
+		output.BaseRef = config.DiffRef
+		output.BaseTokens = base.Tokens
+		output.Diff = udiff.Unified(config.DiffRef, "working tree", base.Text, rendered.Text)
+		return printPromptDiff(output, base, config)
+	}
+
+	if config.JSONOutput {
+		return printPromptJSON(output)
+	}
+
+	if config.Raw {
+		fmt.Print(rendered.Text)
+		return nil
+	}
+
+	for _, source := range rendered.Sources {
+		if !source.Included {
+			continue
+		}
+		fmt.Println(console.FormatSectionHeader(fmt.Sprintf("%s: %s (~%d tokens)", source.Kind, source.Name, source.Tokens)))
+		fmt.Print(source.Content)
+		if !strings.HasSuffix(source.Content, "\n") {
+			fmt.Println()
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:     "Prompt Sources",
+		Headers:   []string{"Kind", "Source", "Tokens", "Note"},
+		Rows:      promptSourceRows(rendered.Sources),
+		ShowTotal: true,
+		TotalRow:  []string{"", "Total", strconv.Itoa(rendered.Tokens), ""},
+	}))
+	printUnresolvedPromptExpressions(rendered.Unresolved)
+	printPromptLimitWarning(rendered.Tokens, rendered.MaxTokens)
+	return nil
+}
+
+// buildPromptRenderOptions loads the event fixture and fills in defaults for the previewed run
+func buildPromptRenderOptions(config PromptConfig, workflowPath string) (workflow.PromptRenderOptions, error) {
+	opts := workflow.PromptRenderOptions{
+		EventName:  config.EventName,
+		Event:      map[string]any{},
+		Inputs:     make(map[string]string),
+		Repository: config.Repository,
+		Actor:      config.Actor,
+		PromptsDir: config.PromptsDir,
+	}
+	if opts.EventName == "" {
+		opts.EventName = "workflow_dispatch"
+	}
+
+	if config.EventFile != "" {
+		content, err := os.ReadFile(config.EventFile)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read event file: %w", err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&opts.Event); err != nil {
+			return opts, fmt.Errorf("failed to parse event file %s: %w", config.EventFile, err)
+		}
+	}
+
+	for _, input := range config.Inputs {
+		key, value, ok := strings.Cut(input, "=")
+		if !ok || key == "" {
+			return opts, fmt.Errorf("invalid input format '%s': expected key=value", input)
+		}
+		opts.Inputs[key] = value
+	}
+
+	if opts.Repository == "" {
+		if repository, ok := opts.Event["repository"].(map[string]any); ok {
+			opts.Repository, _ = repository["full_name"].(string)
+		}
+	}
+	if opts.Repository == "" {
+		slug, err := GetCurrentRepoSlug()
+		if err != nil {
+			promptLog.Printf("Could not determine current repository: %v", err)
+			slug = "owner/repo"
+		}
+		opts.Repository = slug
+	}
+
+	if opts.Actor == "" {
+		if sender, ok := opts.Event["sender"].(map[string]any); ok {
+			opts.Actor, _ = sender["login"].(string)
+		}
+	}
+	if opts.Actor == "" {
+		opts.Actor, _, _ = strings.Cut(opts.Repository, "/")
+	}
+
+	if opts.PromptsDir == "" {
+		opts.PromptsDir = defaultPromptsDir(workflowPath)
+	}
+
+	promptLog.Printf("Render options: event=%s, repository=%s, actor=%s, inputs=%d, promptsDir=%s",
+		opts.EventName, opts.Repository, opts.Actor, len(opts.Inputs), opts.PromptsDir)
+	return opts, nil
+}
+
+// defaultPromptsDir returns the directory with the built-in prompt files in the repository of
+// a workflow, or "" if the repository does not have one
+func defaultPromptsDir(workflowPath string) string {
+	gitRoot, err := findGitRootForPath(workflowPath)
+	if err != nil {
+		return ""
+	}
+	promptsDir := filepath.Join(gitRoot, "actions", "setup", "md")
+	if info, err := os.Stat(promptsDir); err == nil && info.IsDir() {
+		return promptsDir
+	}
+	return ""
+}
+
+// renderPromptAtRevision renders the prompt of a workflow from the repository files at a git revision
+func renderPromptAtRevision(workflowPath, ref string, opts workflow.PromptRenderOptions) (*workflow.RenderedPrompt, error) {
+	gitRoot, err := findGitRootForPath(workflowPath)
+	if err != nil {
+		return nil, err
+	}
+	absPath, err := filepath.Abs(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	relPath, err := filepath.Rel(gitRoot, absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow path relative to repository root: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gh-aw-prompt-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractRevisionWorkflowFiles(gitRoot, ref, tmpDir); err != nil {
+		return nil, err
+	}
+
+	basePath := filepath.Join(tmpDir, relPath)
+	if _, err := os.Stat(basePath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("workflow %s does not exist at revision %s", filepath.ToSlash(relPath), ref)
+	}
+
+	rendered, err := workflow.NewCompiler().RenderPrompt(basePath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt at revision %s: %w", ref, err)
+	}
+	return rendered, nil
+}
+
+// extractRevisionWorkflowFiles extracts the .github and .agents folders of a git revision,
+// which hold everything a prompt can import. The archive is streamed from git archive.
+func extractRevisionWorkflowFiles(gitRoot, ref, destDir string) error {
+	promptLog.Printf("Extracting workflow files at revision %s", ref)
+
+	// git archive fails on pathspecs that match nothing, so only pass the folders that exist
+	lsTree := exec.Command("git", "-C", gitRoot, "ls-tree", "--name-only", ref, "--", ".github", ".agents")
+	var lsStderr bytes.Buffer
+	lsTree.Stderr = &lsStderr
+	listing, err := lsTree.Output()
+	if err != nil {
+		return fmt.Errorf("failed to read revision %s: %s", ref, strings.TrimSpace(lsStderr.String()))
+	}
+	folders := strings.Fields(string(listing))
+	if len(folders) == 0 {
+		return nil
+	}
+
+	cmd := exec.Command("git", append([]string{"-C", gitRoot, "archive", "--format=tar", ref, "--"}, folders...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to read revision %s: %w", ref, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to read revision %s: %w", ref, err)
+	}
+
+	extractErr := extractWorkflowFilesFromTar(tar.NewReader(stdout), ref, destDir)
+	// Drain the rest of the archive so that git archive does not block on a full pipe
+	_, _ = io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("failed to read revision %s: %s", ref, strings.TrimSpace(stderr.String()))
+	}
+	return extractErr
+}
+
+// extractWorkflowFilesFromTar writes the regular files of a tar stream under destDir
+func extractWorkflowFilesFromTar(reader *tar.Reader, ref, destDir string) error {
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read revision %s: %w", ref, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(name, ".github"+string(filepath.Separator)) && !strings.HasPrefix(name, ".agents"+string(filepath.Separator)) {
+			continue
+		}
+
+		target := filepath.Join(destDir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		_, copyErr := io.Copy(file, reader)
+		closeErr := file.Close()
+		if copyErr != nil {
+			return fmt.Errorf("failed to read %s at revision %s: %w", header.Name, ref, copyErr)
+		}
+		if closeErr != nil {
+			return fmt.Errorf("failed to write %s: %w", target, closeErr)
+		}
+	}
+}
+
+// printPromptDiff prints the difference between the prompt at a revision and the current prompt
+func printPromptDiff(output PromptOutput, base *workflow.RenderedPrompt, config PromptConfig) error {
+	if config.JSONOutput {
+		return printPromptJSON(output)
+	}
+
+	if output.Diff == "" {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("The prompt is unchanged since %s (~%d tokens)", config.DiffRef, output.Tokens)))
+		return nil
+	}
+	fmt.Print(output.Diff)
+
+	if config.Raw {
+		return nil
+	}
+
+	baseTokens := make(map[string]int)
+	for _, source := range base.Sources {
+		if source.Included {
+			baseTokens[source.Kind+":"+source.Name] = source.Tokens
+		}
+	}
+	var rows [][]string
+	for _, source := range output.Sources {
+		if !source.Included {
+			continue
+		}
+		key := source.Kind + ":" + source.Name
+		rows = append(rows, []string{source.Kind, source.Name, strconv.Itoa(baseTokens[key]), strconv.Itoa(source.Tokens), formatTokenDelta(source.Tokens - baseTokens[key])})
+		delete(baseTokens, key)
+	}
+	for _, source := range base.Sources {
+		key := source.Kind + ":" + source.Name
+		if tokens, ok := baseTokens[key]; ok {
+			rows = append(rows, []string{source.Kind, source.Name, strconv.Itoa(tokens), "0", formatTokenDelta(-tokens)})
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:     "Prompt Sources (" + config.DiffRef + " → working tree)",
+		Headers:   []string{"Kind", "Source", "Before", "After", "Change"},
+		Rows:      rows,
+		ShowTotal: true,
+		TotalRow:  []string{"", "Total", strconv.Itoa(output.BaseTokens), strconv.Itoa(output.Tokens), formatTokenDelta(output.Tokens - output.BaseTokens)},
+	}))
+	printPromptLimitWarning(output.Tokens, output.MaxTokens)
+	return nil
+}
+
+// printPromptJSON prints the prompt command output as JSON
+func printPromptJSON(output PromptOutput) error {
+	jsonBytes, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+// promptSourceRows returns the table rows of the prompt sources
+func promptSourceRows(sources []workflow.PromptSource) [][]string {
+	rows := make([][]string, 0, len(sources))
+	for _, source := range sources {
+		tokens := strconv.Itoa(source.Tokens)
+		if !source.Included {
+			tokens = "-"
+		}
+		rows = append(rows, []string{source.Kind, source.Name, tokens, source.Note})
+	}
+	return rows
+}
+
+// printUnresolvedPromptExpressions lists the expressions that are only resolved while the workflow runs
+func printUnresolvedPromptExpressions(unresolved []string) {
+	if len(unresolved) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d expression(s) are only resolved while the workflow runs and were left as-is:", len(unresolved))))
+	for _, expr := range unresolved {
+		fmt.Fprintf(os.Stderr, "  %s\n", expr)
+	}
+}
+
+// printPromptLimitWarning warns when the rendered prompt exceeds prompt.max-tokens
+func printPromptLimitWarning(tokens, maxTokens int) {
+	if maxTokens > 0 && tokens > maxTokens {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("The prompt is ~%d tokens, more than prompt.max-tokens (%d). The sections in prompt.summarize and prompt.truncate will be reduced before the engine runs.", tokens, maxTokens)))
+	}
+}
+
+// formatTokenDelta formats a change in tokens with its sign
+func formatTokenDelta(delta int) string {
+	if delta > 0 {
+		return "+" + strconv.Itoa(delta)
+	}
+	return strconv.Itoa(delta)
+}
@@ -0,0 +1,81 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPromptCommand(t *testing.T) {
+	cmd := NewPromptCommand()
+	assert.Equal(t, "prompt <workflow>", cmd.Use, "command should take a workflow")
+	for _, flag := range []string{"event", "event-name", "raw-field", "actor", "prompts-dir", "diff", "raw", "repo", "json"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), "prompt command should have --%s", flag)
+	}
+	assert.Equal(t, "F", cmd.Flags().Lookup("raw-field").Shorthand, "inputs should use -F like the run command")
+}
+
+func TestBuildPromptRenderOptions(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "prompt-options-*")
+	eventFile := filepath.Join(tmpDir, "event.json")
+	require.NoError(t, os.WriteFile(eventFile, []byte(`{"issue":{"number":42},"sender":{"login":"octocat"},"repository":{"full_name":"octo/repo"}}`), 0o644), "should write event")
+
+	opts, err := buildPromptRenderOptions(PromptConfig{
+		EventFile:  eventFile,
+		EventName:  "issues",
+		Inputs:     []string{"mode=fast", "query=a=b"},
+		PromptsDir: tmpDir,
+	}, filepath.Join(tmpDir, "workflow.md"))
+	require.NoError(t, err, "options should build")
+
+	assert.Equal(t, "issues", opts.EventName, "event name should be kept")
+	assert.Equal(t, "octo/repo", opts.Repository, "repository should default to the event repository")
+	assert.Equal(t, "octocat", opts.Actor, "actor should default to the event sender")
+	assert.Equal(t, map[string]string{"mode": "fast", "query": "a=b"}, opts.Inputs, "inputs should be split on the first =")
+	assert.Equal(t, json.Number("42"), opts.Event["issue"].(map[string]any)["number"], "numbers should keep their JSON form")
+
+	_, err = buildPromptRenderOptions(PromptConfig{Inputs: []string{"=value"}, Repository: "octo/repo"}, filepath.Join(tmpDir, "workflow.md"))
+	require.Error(t, err, "inputs without key should fail")
+	assert.Contains(t, err.Error(), "expected key=value", "error should explain the input format")
+}
+
+func TestRenderPromptAtRevision(t *testing.T) {
+	repoDir := testutil.TempDir(t, "prompt-revision-*")
+	workflowsDir := filepath.Join(repoDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0o755), "should create workflows directory")
+	workflowFile := filepath.Join(workflowsDir, "triage.md")
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repoDir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, output)
+	}
+	git("init", "-q")
+	require.NoError(t, os.WriteFile(workflowFile, []byte("---\non: workflow_dispatch\nengine: copilot\n---\n\nTriage the old way.\n"), 0o644), "should write workflow")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("# Repo\n"), 0o644), "should write readme")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	require.NoError(t, os.WriteFile(workflowFile, []byte("---\non: workflow_dispatch\nengine: copilot\n---\n\nTriage the new way.\n"), 0o644), "should update workflow")
+
+	opts := workflow.PromptRenderOptions{EventName: "workflow_dispatch", Repository: "octo/repo"}
+	base, err := renderPromptAtRevision(workflowFile, "HEAD", opts)
+	require.NoError(t, err, "prompt should render at the revision")
+	assert.Contains(t, base.Text, "Triage the old way.", "prompt should be rendered from the committed workflow")
+
+	_, err = renderPromptAtRevision(workflowFile, "no-such-ref", opts)
+	require.Error(t, err, "unknown revisions should fail")
+
+	destDir := t.TempDir()
+	require.NoError(t, extractRevisionWorkflowFiles(repoDir, "HEAD", destDir), "workflow files should be extracted without an .agents folder")
+	assert.FileExists(t, filepath.Join(destDir, ".github", "workflows", "triage.md"), "workflow files should be extracted")
+	assert.NoFileExists(t, filepath.Join(destDir, "README.md"), "files outside .github and .agents should not be extracted")
+}
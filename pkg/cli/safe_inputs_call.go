@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var safeInputsCallLog = logger.New("cli:safe_inputs_call")
+
+// shellToolResult is the result format returned by shell and container safe-input handlers
+type shellToolResult struct {
+	Stdout  *string           `json:"stdout"`
+	Stderr  string            `json:"stderr"`
+	Outputs map[string]string `json:"outputs"`
+}
+
+// parseSafeInputCallArguments parses the JSON object passed as tool arguments
+func parseSafeInputCallArguments(argsJSON string) (map[string]any, error) {
+	args := map[string]any{}
+	if strings.TrimSpace(argsJSON) == "" {
+		return args, nil
+	}
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return nil, fmt.Errorf("tool arguments must be a JSON object: %w", err)
+	}
+	return args, nil
+}
+
+// RunSafeInputsCall invokes a safe-input tool with JSON arguments and prints its output and timing.
+// When url is empty, a temporary HTTP server is started for the workflow; otherwise the tool is
+// called on an already running server (e.g., 'gh aw safe-inputs serve --transport http').
+func RunSafeInputsCall(opts SafeInputsDevOptions, toolName, argsJSON, url string) error {
+	safeInputsCallLog.Printf("Calling safe-input tool: workflow=%s, tool=%s, url=%s", opts.WorkflowFile, toolName, url)
+
+	args, err := parseSafeInputCallArguments(argsJSON)
+	if err != nil {
+		return err
+	}
+
+	if url == "" {
+		opts.Transport = SafeInputsTransportHTTP
+		server, cleanup, err := newSafeInputsDevServer(opts)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		// Server debug logs are only useful when diagnosing the runtime itself
+		if !opts.Verbose {
+			server.serverLogs = io.Discard
+		}
+
+		if err := server.reload(); err != nil {
+			return err
+		}
+		defer server.stop()
+		url = fmt.Sprintf("http://localhost:%d", server.port)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gh-aw-safe-inputs", Version: "1.0.0"}, &mcp.ClientOptions{
+		Logger: logger.NewSlogLoggerWithHandler(safeInputsCallLog),
+	})
+
+	connectCtx, cancel := context.WithTimeout(context.Background(), MCPConnectTimeout)
+	defer cancel()
+	session, err := client.Connect(connectCtx, &mcp.StreamableClientTransport{Endpoint: url}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to safe-inputs server at %s: %w", url, err)
+	}
+	defer session.Close()
+
+	start := time.Now()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: toolName, Arguments: args})
+	duration := time.Since(start)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("Tool %s failed after %s", toolName, duration.Round(time.Millisecond))))
+		return fmt.Errorf("safe-input tool %s failed: %w", toolName, err)
+	}
+
+	printSafeInputCallResult(os.Stdout, os.Stderr, result)
+
+	if result.IsError {
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("Tool %s returned an error after %s", toolName, duration.Round(time.Millisecond))))
+		return fmt.Errorf("safe-input tool %s returned an error", toolName)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Tool %s completed in %s", toolName, duration.Round(time.Millisecond))))
+	return nil
+}
+
+// printSafeInputCallResult prints a tool result. Shell and container tool results are split
+// into stdout, stderr and step outputs; other results are printed as returned.
+func printSafeInputCallResult(stdout, stderr io.Writer, result *mcp.CallToolResult) {
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			data, _ := json.Marshal(content)
+			fmt.Fprintln(stdout, string(data))
+			continue
+		}
+
+		var shellResult shellToolResult
+		if json.Unmarshal([]byte(text.Text), &shellResult) != nil || shellResult.Stdout == nil {
+			fmt.Fprintln(stdout, text.Text)
+			continue
+		}
+
+		fmt.Fprint(stdout, *shellResult.Stdout)
+		if shellResult.Stderr != "" {
+			fmt.Fprintln(stderr, console.FormatInfoMessage("stderr:"))
+			fmt.Fprint(stderr, shellResult.Stderr)
+		}
+		if len(shellResult.Outputs) > 0 {
+			fmt.Fprintln(stderr, console.FormatInfoMessage("outputs:"))
+			names := make([]string, 0, len(shellResult.Outputs))
+			for name := range shellResult.Outputs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(stderr, "  %s=%s\n", name, shellResult.Outputs[name])
+			}
+		}
+	}
+}
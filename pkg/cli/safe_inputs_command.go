@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var safeInputsCommandLog = logger.New("cli:safe_inputs_command")
+
+// NewSafeInputsCommand creates the safe-inputs command with subcommands for local tool development
+func NewSafeInputsCommand() *cobra.Command {
+	safeInputsCommandLog.Print("Creating safe-inputs command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "safe-inputs",
+		Short: "Develop and test safe-input tools locally",
+		Long: `Develop and test the safe-input tools defined in a workflow without running it on GitHub Actions.
+
+Available subcommands:
+  • serve - Run the workflow's safe-inputs MCP server locally with hot reload
+  • call  - Invoke a safe-input tool with JSON arguments
+
+Env vars declared by tools (e.g., ${{ secrets.API_KEY }}) are resolved from a local .env file
+at the repository root and from the current environment. Missing values are reported as warnings.
+
+The MCP server runtime scripts are loaded from actions/setup/js in the current repository;
+use --runtime-dir when working in a repository that is not a gh-aw checkout.
+
+Examples:
+  gh aw safe-inputs serve my-workflow                      # Serve over stdio with hot reload
+  gh aw safe-inputs serve my-workflow --transport http     # Serve over HTTP
+  gh aw safe-inputs call my-workflow search '{"q":"bug"}'  # Invoke a tool`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newSafeInputsServeSubcommand())
+	cmd.AddCommand(newSafeInputsCallSubcommand())
+
+	return cmd
+}
+
+// addSafeInputsDevFlags registers the flags shared by the safe-inputs subcommands
+func addSafeInputsDevFlags(cmd *cobra.Command, opts *SafeInputsDevOptions) {
+	cmd.Flags().StringVar(&opts.RuntimeDir, "runtime-dir", "", "Directory containing the safe-inputs runtime scripts (default: actions/setup/js in the repository)")
+	cmd.Flags().StringVar(&opts.EnvFile, "env-file", "", "File with local values for env and secret references (default: .env in the repository root)")
+}
+
+func newSafeInputsServeSubcommand() *cobra.Command {
+	opts := SafeInputsDevOptions{}
+	var noWatch bool
+
+	cmd := &cobra.Command{
+		Use:   "serve <workflow>",
+		Short: "Run a workflow's safe-inputs MCP server locally",
+		Long: `Run the safe-inputs MCP server of a workflow locally over stdio or HTTP.
+
+The server is regenerated and restarted when the workflow, its imports or the .env file
+change. With the stdio transport the client session is kept across reloads and a
+tools/list_changed notification is sent after each reload. If an edit cannot be parsed,
+the previous server keeps running and the error is printed.
+
+Examples:
+  gh aw safe-inputs serve my-workflow                          # stdio transport
+  gh aw safe-inputs serve my-workflow --transport http --port 3000
+  gh aw safe-inputs serve my-workflow --no-watch               # Disable hot reload`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.WorkflowFile = args[0]
+			opts.Watch = !noWatch
+			opts.Verbose, _ = cmd.Flags().GetBool("verbose")
+			return RunSafeInputsServe(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Transport, "transport", SafeInputsTransportStdio, "Transport to serve the MCP server on: stdio or http")
+	cmd.Flags().IntVarP(&opts.Port, "port", "p", 0, "Port for the HTTP transport (default: first available port from 3000)")
+	cmd.Flags().BoolVar(&noWatch, "no-watch", false, "Do not reload the server when files change")
+	addSafeInputsDevFlags(cmd, &opts)
+
+	return cmd
+}
+
+func newSafeInputsCallSubcommand() *cobra.Command {
+	opts := SafeInputsDevOptions{}
+	var url string
+
+	cmd := &cobra.Command{
+		Use:   "call <workflow> <tool> [json-arguments]",
+		Short: "Invoke a safe-input tool with JSON arguments",
+		Long: `Invoke a safe-input tool of a workflow and print its result.
+
+Shell and container tools print their stdout to stdout and their stderr and step outputs
+to stderr. The call duration is printed when the tool completes.
+
+By default a temporary server is started for the call. Use --url to call a server
+already started with 'gh aw safe-inputs serve --transport http'.
+
+Examples:
+  gh aw safe-inputs call my-workflow search '{"query": "flaky test"}'
+  gh aw safe-inputs call my-workflow deploy '{"env": "staging"}' --url http://localhost:3000`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.WorkflowFile = args[0]
+			argsJSON := ""
+			if len(args) == 3 {
+				argsJSON = args[2]
+			}
+			opts.Verbose, _ = cmd.Flags().GetBool("verbose")
+			return RunSafeInputsCall(opts, args[1], argsJSON, url)
+		},
+	}
+
+	cmd.Flags().StringVar(&url, "url", "", "URL of a running safe-inputs HTTP server")
+	addSafeInputsDevFlags(cmd, &opts)
+
+	return cmd
+}
@@ -0,0 +1,181 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSafeInputsCommand(t *testing.T) {
+	cmd := NewSafeInputsCommand()
+
+	require.NotNil(t, cmd, "NewSafeInputsCommand should not return nil")
+	assert.Equal(t, "safe-inputs", cmd.Use, "command use should be 'safe-inputs'")
+
+	names := make(map[string]bool)
+	for _, subcmd := range cmd.Commands() {
+		names[subcmd.Name()] = true
+	}
+	assert.True(t, names["serve"], "should have 'serve' subcommand")
+	assert.True(t, names["call"], "should have 'call' subcommand")
+
+	serveCmd, _, err := cmd.Find([]string{"serve"})
+	require.NoError(t, err, "serve subcommand should be found")
+	transport := serveCmd.Flags().Lookup("transport")
+	require.NotNil(t, transport, "serve should have --transport flag")
+	assert.Equal(t, SafeInputsTransportStdio, transport.DefValue, "stdio should be the default transport")
+	assert.NotNil(t, serveCmd.Flags().Lookup("no-watch"), "serve should have --no-watch flag")
+	assert.NotNil(t, serveCmd.Flags().Lookup("env-file"), "serve should have --env-file flag")
+
+	callCmd, _, err := cmd.Find([]string{"call"})
+	require.NoError(t, err, "call subcommand should be found")
+	assert.NotNil(t, callCmd.Flags().Lookup("url"), "call should have --url flag")
+	assert.NotNil(t, callCmd.Flags().Lookup("runtime-dir"), "call should have --runtime-dir flag")
+}
+
+func TestResolveSafeInputsRuntimeDir(t *testing.T) {
+	runtimeDir := t.TempDir()
+
+	_, err := resolveSafeInputsRuntimeDir(runtimeDir)
+	require.Error(t, err, "empty runtime directory should be rejected")
+	assert.Contains(t, err.Error(), "safe_inputs_mcp_server.cjs", "error should list missing files")
+
+	for _, file := range safeInputsRuntimeFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(runtimeDir, file), []byte("// "+file), 0644), "should write runtime file")
+	}
+	resolved, err := resolveSafeInputsRuntimeDir(runtimeDir)
+	require.NoError(t, err, "complete runtime directory should be accepted")
+	assert.Equal(t, runtimeDir, resolved, "explicit runtime directory should be used")
+}
+
+func TestWriteSafeInputsDevFiles(t *testing.T) {
+	runtimeDir := t.TempDir()
+	for _, file := range safeInputsRuntimeFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(runtimeDir, file), []byte("// "+file), 0644), "should write runtime file")
+	}
+
+	dir := t.TempDir()
+	safeInputs := &workflow.SafeInputsConfig{
+		Tools: map[string]*workflow.SafeInputToolConfig{
+			"greet": {Name: "greet", Description: "Greet", Run: "echo hi"},
+			"add":   {Name: "add", Description: "Add", Script: "return 1;"},
+		},
+	}
+
+	require.NoError(t, writeSafeInputsDevFiles(dir, runtimeDir, safeInputs, false), "should write dev files")
+
+	for _, file := range append([]string{"tools.json", "mcp-server.cjs", "mcp-server-stdio.cjs", "greet.sh", "add.cjs"}, safeInputsRuntimeFiles...) {
+		assert.FileExists(t, filepath.Join(dir, file), "%s should be written", file)
+	}
+
+	entry, err := os.ReadFile(filepath.Join(dir, "mcp-server-stdio.cjs"))
+	require.NoError(t, err, "should read stdio entry point")
+	assert.Contains(t, string(entry), "skipCleanup: true", "stdio entry point should keep tools.json for reloads")
+}
+
+func TestSafeInputsDevServerReplaysHandshake(t *testing.T) {
+	server := &safeInputsDevServer{}
+
+	server.recordHandshake([]byte(`{"jsonrpc":"2.0","id":7,"method":"initialize","params":{}}` + "\n"))
+	server.recordHandshake([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"))
+	server.recordHandshake([]byte(`{"jsonrpc":"2.0","id":8,"method":"tools/list"}` + "\n"))
+
+	require.Len(t, server.handshake, 2, "only the handshake should be recorded")
+	assert.JSONEq(t, "7", string(server.initializeID), "initialize request ID should be recorded")
+
+	var out bytes.Buffer
+	server.out = &out
+	process := &safeInputsDevProcess{dropID: server.initializeID}
+	serverOutput := `{"jsonrpc":"2.0","id":7,"result":{"protocolVersion":"2024-11-05"}}` + "\n" +
+		`{"jsonrpc":"2.0","id":9,"result":{"tools":[]}}` + "\n"
+	server.forwardServerOutput(process, bytes.NewBufferString(serverOutput))
+
+	assert.Equal(t, `{"jsonrpc":"2.0","id":9,"result":{"tools":[]}}`+"\n", out.String(), "replayed initialize response should be dropped")
+
+	// The first initialize response advertises list_changed notifications
+	out.Reset()
+	server.forwardServerOutput(&safeInputsDevProcess{}, bytes.NewBufferString(`{"jsonrpc":"2.0","id":7,"result":{"protocolVersion":"2024-11-05","capabilities":{"tools":{}}}}`+"\n"))
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"result":{"protocolVersion":"2024-11-05","capabilities":{"tools":{"listChanged":true}}}}`, out.String(), "initialize response should advertise tools.listChanged")
+}
+
+func TestAdvertiseToolsListChanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{"without capabilities", `{"jsonrpc":"2.0","id":"a","result":{}}`, `{"jsonrpc":"2.0","id":"a","result":{"capabilities":{"tools":{"listChanged":true}}}}`},
+		{"keeps other capabilities", `{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"logging":{},"tools":{"x":1}}}}`, `{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"logging":{},"tools":{"x":1,"listChanged":true}}}}`},
+		{"error response", `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"bad"}}`, `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"bad"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.JSONEq(t, tt.expected, string(advertiseToolsListChanged([]byte(tt.message+"\n"))), "capabilities should advertise tools.listChanged")
+		})
+	}
+}
+
+func TestIsSafeInputsWatchEvent(t *testing.T) {
+	envFile := "/repo/.env"
+
+	assert.True(t, isSafeInputsWatchEvent(fsnotify.Event{Name: "/repo/.github/workflows/a.md", Op: fsnotify.Write}, envFile), "markdown writes should reload")
+	assert.True(t, isSafeInputsWatchEvent(fsnotify.Event{Name: envFile, Op: fsnotify.Create}, envFile), "env file changes should reload")
+	assert.False(t, isSafeInputsWatchEvent(fsnotify.Event{Name: "/repo/.github/workflows/a.lock.yml", Op: fsnotify.Write}, envFile), "lock files should not reload")
+	assert.False(t, isSafeInputsWatchEvent(fsnotify.Event{Name: "/repo/.github/workflows/a.md", Op: fsnotify.Chmod}, envFile), "chmod events should be ignored")
+}
+
+func TestPrintSafeInputCallResult(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		expectedStdout string
+		expectedStderr []string
+	}{
+		{
+			name:           "shell result",
+			text:           `{"stdout":"hello\n","stderr":"warning\n","outputs":{"b":"2","a":"1"}}`,
+			expectedStdout: "hello\n",
+			expectedStderr: []string{"stderr:", "warning", "a=1", "b=2"},
+		},
+		{
+			name:           "json result",
+			text:           `{"sum":3}`,
+			expectedStdout: "{\"sum\":3}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			printSafeInputCallResult(&stdout, &stderr, &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: tt.text}}})
+
+			assert.Equal(t, tt.expectedStdout, stdout.String(), "stdout should match")
+			for _, expected := range tt.expectedStderr {
+				assert.Contains(t, stderr.String(), expected, "stderr should contain %q", expected)
+			}
+		})
+	}
+}
+
+func TestParseSafeInputCallArguments(t *testing.T) {
+	args, err := parseSafeInputCallArguments("")
+	require.NoError(t, err, "empty arguments should be allowed")
+	assert.Empty(t, args, "empty arguments should yield an empty object")
+
+	args, err = parseSafeInputCallArguments(`{"query": "bug", "limit": 5}`)
+	require.NoError(t, err, "JSON object should be parsed")
+	assert.Equal(t, "bug", args["query"], "string argument should be parsed")
+
+	_, err = parseSafeInputCallArguments(`["not", "an", "object"]`)
+	assert.Error(t, err, "non-object arguments should be rejected")
+}
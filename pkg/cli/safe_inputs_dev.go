@@ -0,0 +1,692 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var safeInputsDevLog = logger.New("cli:safe_inputs_dev")
+
+const (
+	// SafeInputsTransportStdio serves the safe-inputs MCP server over stdin/stdout
+	SafeInputsTransportStdio = "stdio"
+	// SafeInputsTransportHTTP serves the safe-inputs MCP server over HTTP
+	SafeInputsTransportHTTP = "http"
+
+	// safeInputsStdioStartupGrace is how long a restarted stdio server must stay up to replace the previous one
+	safeInputsStdioStartupGrace = 200 * time.Millisecond
+
+	// safeInputsRuntimeSourceDir is the location of the safe-inputs runtime scripts in a gh-aw checkout
+	safeInputsRuntimeSourceDir = "actions/setup/js"
+)
+
+// safeInputsRuntimeFiles lists the JavaScript files needed to run the safe-inputs MCP server.
+// Keep in sync with SAFE_INPUTS_FILES in actions/setup/setup.sh.
+var safeInputsRuntimeFiles = []string{
+	"safe_inputs_bootstrap.cjs",
+	"safe_inputs_config_loader.cjs",
+	"safe_inputs_mcp_server.cjs",
+	"safe_inputs_mcp_server_http.cjs",
+	"safe_inputs_tool_factory.cjs",
+	"safe_inputs_validation.cjs",
+	"mcp_server_core.cjs",
+	"mcp_logger.cjs",
+	"mcp_http_transport.cjs",
+	"mcp_handler_shell.cjs",
+	"mcp_handler_python.cjs",
+	"mcp_handler_go.cjs",
+	"mcp_handler_javascript.cjs",
+	"read_buffer.cjs",
+	"error_helpers.cjs",
+	"mcp_enhanced_errors.cjs",
+}
+
+// safeInputsDevHTTPEntryScript starts the HTTP server from the dev directory.
+// Unlike the workflow entry point, logs are written next to the generated files.
+const safeInputsDevHTTPEntryScript = `// Auto-generated by gh aw safe-inputs (HTTP transport)
+const path = require("path");
+const { startHttpServer } = require("./safe_inputs_mcp_server_http.cjs");
+
+const port = parseInt(process.env.GH_AW_SAFE_INPUTS_PORT || "3000", 10);
+
+startHttpServer(path.join(__dirname, "tools.json"), {
+  port: port,
+  stateless: true,
+  logDir: path.join(__dirname, "logs"),
+}).catch(error => {
+  console.error("Failed to start safe-inputs HTTP server:", error);
+  process.exit(1);
+});
+`
+
+// safeInputsDevStdioEntryScript starts the stdio server from the dev directory
+const safeInputsDevStdioEntryScript = `// Auto-generated by gh aw safe-inputs (stdio transport)
+const path = require("path");
+const { startSafeInputsServer } = require("./safe_inputs_mcp_server.cjs");
+
+startSafeInputsServer(path.join(__dirname, "tools.json"), {
+  logDir: path.join(__dirname, "logs"),
+  skipCleanup: true,
+});
+`
+
+// SafeInputsDevOptions holds the options shared by the safe-inputs development commands
+type SafeInputsDevOptions struct {
+	WorkflowFile string // Workflow file or name containing the safe-inputs configuration
+	Transport    string // stdio or http
+	Port         int    // HTTP port (0 picks an available port)
+	RuntimeDir   string // Directory containing the safe-inputs runtime scripts
+	EnvFile      string // .env file used to resolve env and secret references
+	Watch        bool   // Reload the server when the workflow or env file changes
+	Verbose      bool
+}
+
+// resolveSafeInputsRuntimeDir locates the safe-inputs runtime scripts.
+// Uses the explicit directory when provided, otherwise actions/setup/js in the current git repository.
+func resolveSafeInputsRuntimeDir(runtimeDir string) (string, error) {
+	if runtimeDir == "" {
+		gitRoot, err := findGitRoot()
+		if err != nil {
+			return "", fmt.Errorf("safe-inputs runtime scripts not found: not in a git repository. Use --runtime-dir to point at a gh-aw %s directory", safeInputsRuntimeSourceDir)
+		}
+		runtimeDir = filepath.Join(gitRoot, safeInputsRuntimeSourceDir)
+	}
+
+	var missing []string
+	for _, file := range safeInputsRuntimeFiles {
+		if _, err := os.Stat(filepath.Join(runtimeDir, file)); err != nil {
+			missing = append(missing, file)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("safe-inputs runtime scripts not found in %s (missing: %s). Use --runtime-dir to point at a gh-aw %s directory", runtimeDir, strings.Join(missing, ", "), safeInputsRuntimeSourceDir)
+	}
+
+	safeInputsDevLog.Printf("Using safe-inputs runtime scripts from %s", runtimeDir)
+	return runtimeDir, nil
+}
+
+// writeSafeInputsDevFiles writes the runtime scripts, tools.json, tool handlers and
+// entry points needed to run the safe-inputs MCP server locally
+func writeSafeInputsDevFiles(dir, runtimeDir string, safeInputsConfig *workflow.SafeInputsConfig, verbose bool) error {
+	safeInputsDevLog.Printf("Writing safe-inputs dev files to %s", dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0755); err != nil {
+		return fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	for _, file := range safeInputsRuntimeFiles {
+		content, err := os.ReadFile(filepath.Join(runtimeDir, file))
+		if err != nil {
+			return fmt.Errorf("failed to read runtime script %s: %w", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"tools.json", workflow.GenerateSafeInputsToolsConfigForInspector(safeInputsConfig)},
+		{"mcp-server.cjs", safeInputsDevHTTPEntryScript},
+		{"mcp-server-stdio.cjs", safeInputsDevStdioEntryScript},
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file.name), []byte(file.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return writeSafeInputToolHandlers(dir, safeInputsConfig, verbose)
+}
+
+// loadSafeInputsForDev parses the workflow (resolving imports) and returns its safe-inputs configuration
+func loadSafeInputsForDev(workflowPath string, verbose bool) (*workflow.SafeInputsConfig, error) {
+	compiler := workflow.NewCompiler(
+		workflow.WithVerbose(verbose),
+	)
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+
+	if workflowData.SafeInputs == nil || len(workflowData.SafeInputs.Tools) == 0 {
+		return nil, fmt.Errorf("no safe-inputs configuration found in workflow")
+	}
+	return workflowData.SafeInputs, nil
+}
+
+// resolveSafeInputsWorkflowPath resolves a workflow name or path to an absolute path
+func resolveSafeInputsWorkflowPath(workflowFile string) (string, error) {
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(workflowPath)
+}
+
+// safeInputsDevServer runs the safe-inputs MCP server for a workflow and restarts it on reload
+type safeInputsDevServer struct {
+	opts         SafeInputsDevOptions
+	workflowPath string
+	runtimeDir   string
+	dir          string
+	port         int
+	out          io.Writer // MCP protocol output for the stdio transport
+	outMu        sync.Mutex
+	serverLogs   io.Writer // Destination of the server's debug logs
+
+	mu       sync.Mutex
+	process  *safeInputsDevProcess
+	tools    int
+	stopping bool
+
+	// Handshake recorded from the client so it can be replayed to a restarted stdio server
+	handshake    [][]byte
+	initializeID json.RawMessage
+}
+
+// safeInputsDevProcess is a running node process serving the safe-inputs MCP server
+type safeInputsDevProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	done   chan struct{}
+	dropID json.RawMessage // Response ID to drop (replayed initialize request)
+}
+
+// load parses the workflow, validates env references and regenerates the server files.
+// It does not touch the running server, so a broken edit keeps the previous version serving.
+func (s *safeInputsDevServer) load() (map[string]string, int, error) {
+	safeInputsConfig, err := loadSafeInputsForDev(s.workflowPath, s.opts.Verbose)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dotEnv, err := loadDotEnvFile(s.opts.EnvFile)
+	if err != nil {
+		return nil, 0, err
+	}
+	env, issues := resolveSafeInputsEnv(safeInputsConfig, dotEnv)
+	for _, issue := range issues {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(issue.String()))
+	}
+
+	if err := writeSafeInputsDevFiles(s.dir, s.runtimeDir, safeInputsConfig, s.opts.Verbose); err != nil {
+		return nil, 0, err
+	}
+	return env, len(safeInputsConfig.Tools), nil
+}
+
+// reload regenerates the server files and restarts the server. A stdio server is replaced only
+// once its successor has started; an HTTP server has to release its port first, so a failed
+// restart leaves no server running until the next change.
+func (s *safeInputsDevServer) reload() error {
+	env, tools, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.Transport == SafeInputsTransportHTTP {
+		s.stopLocked()
+	}
+	process, err := s.startLocked(env)
+	if err != nil {
+		return err
+	}
+	s.stopLocked()
+	s.process = process
+	s.tools = tools
+	return nil
+}
+
+// running reports whether a server process is serving requests
+func (s *safeInputsDevServer) running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.process == nil {
+		return false
+	}
+	select {
+	case <-s.process.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// startLocked starts a new server process and waits until it accepts requests.
+// The caller installs the returned process as s.process. Callers must hold s.mu.
+func (s *safeInputsDevServer) startLocked(env map[string]string) (*safeInputsDevProcess, error) {
+	entry := "mcp-server.cjs"
+	if s.opts.Transport == SafeInputsTransportStdio {
+		entry = "mcp-server-stdio.cjs"
+	}
+
+	cmd := exec.Command("node", filepath.Join(s.dir, entry))
+	cmd.Dir = s.dir
+	cmd.Env = os.Environ()
+	for name, value := range env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+	cmd.Stderr = s.serverLogs
+
+	// A restarted stdio server receives the replayed handshake; its initialize response is not forwarded
+	process := &safeInputsDevProcess{cmd: cmd, done: make(chan struct{}), dropID: s.initializeID}
+
+	var stdout io.ReadCloser
+	if s.opts.Transport == SafeInputsTransportStdio {
+		var err error
+		if process.stdin, err = cmd.StdinPipe(); err != nil {
+			return nil, fmt.Errorf("failed to create server stdin: %w", err)
+		}
+		if stdout, err = cmd.StdoutPipe(); err != nil {
+			return nil, fmt.Errorf("failed to create server stdout: %w", err)
+		}
+	} else {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("GH_AW_SAFE_INPUTS_PORT=%d", s.port))
+		cmd.Stdout = s.serverLogs
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start safe-inputs server: %w", err)
+	}
+	safeInputsDevLog.Printf("Started safe-inputs server: pid=%d, transport=%s", cmd.Process.Pid, s.opts.Transport)
+
+	if stdout != nil {
+		go s.forwardServerOutput(process, stdout)
+	}
+
+	go func() {
+		err := cmd.Wait()
+		close(process.done)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.process == process && !s.stopping {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Safe-inputs server exited unexpectedly: %v. Waiting for changes...", err)))
+		}
+	}()
+
+	if s.opts.Transport == SafeInputsTransportStdio {
+		// Replay the client handshake so the restarted server accepts requests on the existing session
+		for _, line := range s.handshake {
+			if _, err := process.stdin.Write(line); err != nil {
+				stopSafeInputsDevProcess(process)
+				return nil, fmt.Errorf("failed to replay handshake: %w", err)
+			}
+		}
+		// A stdio server has no readiness probe; treat one that survives the grace period as started
+		select {
+		case <-process.done:
+			return nil, fmt.Errorf("safe-inputs server exited during startup")
+		case <-time.After(safeInputsStdioStartupGrace):
+		}
+		return process, nil
+	}
+
+	if !waitForServerReady(s.port, 5*time.Second, s.opts.Verbose) {
+		stopSafeInputsDevProcess(process)
+		return nil, fmt.Errorf("safe-inputs HTTP server failed to start within timeout")
+	}
+	return process, nil
+}
+
+// stopLocked stops the running server process. Callers must hold s.mu.
+func (s *safeInputsDevServer) stopLocked() {
+	process := s.process
+	if process == nil {
+		return
+	}
+	s.process = nil
+	stopSafeInputsDevProcess(process)
+}
+
+// stopSafeInputsDevProcess interrupts a server process and waits for it to exit
+func stopSafeInputsDevProcess(process *safeInputsDevProcess) {
+	if process.stdin != nil {
+		_ = process.stdin.Close()
+	}
+	if process.cmd.Process == nil {
+		return
+	}
+	_ = process.cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-process.done:
+	case <-time.After(2 * time.Second):
+		_ = process.cmd.Process.Kill()
+		<-process.done
+	}
+}
+
+// stop stops the server for good
+func (s *safeInputsDevServer) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopping = true
+	s.stopLocked()
+}
+
+// jsonRPCEnvelope holds the fields of a JSON-RPC message needed to route it
+type jsonRPCEnvelope struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+}
+
+// forwardServerOutput copies stdio server messages to the client, dropping the response to a
+// replayed initialize request and advertising tools.listChanged in the first initialize response
+func (s *safeInputsDevServer) forwardServerOutput(process *safeInputsDevProcess, stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var envelope jsonRPCEnvelope
+			isResponse := json.Unmarshal(line, &envelope) == nil && envelope.Method == "" && envelope.ID != nil
+			s.mu.Lock()
+			initializeID := s.initializeID
+			s.mu.Unlock()
+			switch {
+			case isResponse && process.dropID != nil && bytes.Equal(envelope.ID, process.dropID):
+				process.dropID = nil
+				safeInputsDevLog.Print("Dropped response to replayed initialize request")
+			case isResponse && bytes.Equal(envelope.ID, initializeID):
+				s.writeClientMessage(advertiseToolsListChanged(line))
+			default:
+				s.writeClientMessage(line)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// advertiseToolsListChanged adds tools.listChanged to the capabilities of an initialize response.
+// The generated server does not send list_changed notifications, but the dev server does when a
+// reload changes the tools. Other messages are returned unchanged.
+func advertiseToolsListChanged(line []byte) []byte {
+	var response map[string]json.RawMessage
+	var result, capabilities, tools map[string]json.RawMessage
+	if json.Unmarshal(line, &response) != nil || json.Unmarshal(response["result"], &result) != nil || result == nil {
+		return line
+	}
+	if raw, ok := result["capabilities"]; ok && json.Unmarshal(raw, &capabilities) != nil {
+		return line
+	}
+	if raw, ok := capabilities["tools"]; ok && json.Unmarshal(raw, &tools) != nil {
+		return line
+	}
+	if capabilities == nil {
+		capabilities = make(map[string]json.RawMessage)
+	}
+	if tools == nil {
+		tools = make(map[string]json.RawMessage)
+	}
+
+	tools["listChanged"] = json.RawMessage("true")
+	var err error
+	if capabilities["tools"], err = json.Marshal(tools); err != nil {
+		return line
+	}
+	if result["capabilities"], err = json.Marshal(capabilities); err != nil {
+		return line
+	}
+	if response["result"], err = json.Marshal(result); err != nil {
+		return line
+	}
+	rewritten, err := json.Marshal(response)
+	if err != nil {
+		return line
+	}
+	return append(rewritten, '\n')
+}
+
+// forwardClientInput copies client messages from stdin to the current stdio server,
+// recording the initialize handshake for replay after a reload. Returns when stdin closes.
+func (s *safeInputsDevServer) forwardClientInput(stdin io.Reader) {
+	reader := bufio.NewReader(stdin)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			s.mu.Lock()
+			s.recordHandshake(line)
+			if s.process != nil && s.process.stdin != nil {
+				if _, writeErr := s.process.stdin.Write(line); writeErr != nil {
+					safeInputsDevLog.Printf("Failed to forward client message: %v", writeErr)
+				}
+			}
+			s.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// recordHandshake remembers the initialize request and initialized notification. Callers must hold s.mu.
+func (s *safeInputsDevServer) recordHandshake(line []byte) {
+	var envelope jsonRPCEnvelope
+	if json.Unmarshal(line, &envelope) != nil {
+		return
+	}
+	switch envelope.Method {
+	case "initialize":
+		s.handshake = [][]byte{append([]byte(nil), line...)}
+		s.initializeID = envelope.ID
+	case "notifications/initialized":
+		s.handshake = append(s.handshake, append([]byte(nil), line...))
+	}
+}
+
+// writeClientMessage writes a message to the stdio client
+func (s *safeInputsDevServer) writeClientMessage(line []byte) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	_, _ = s.out.Write(line)
+}
+
+// notifyToolsChanged tells a connected stdio client to refresh its tool list
+func (s *safeInputsDevServer) notifyToolsChanged() {
+	s.mu.Lock()
+	initialized := s.initializeID != nil
+	s.mu.Unlock()
+	if s.opts.Transport == SafeInputsTransportStdio && initialized {
+		s.writeClientMessage([]byte(`{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}` + "\n"))
+	}
+}
+
+// RunSafeInputsServe starts the safe-inputs MCP server of a workflow for local development
+func RunSafeInputsServe(opts SafeInputsDevOptions) error {
+	safeInputsDevLog.Printf("Serving safe-inputs: workflow=%s, transport=%s, watch=%t", opts.WorkflowFile, opts.Transport, opts.Watch)
+
+	if opts.Transport != SafeInputsTransportStdio && opts.Transport != SafeInputsTransportHTTP {
+		return fmt.Errorf("invalid transport '%s'. Valid values: %s, %s", opts.Transport, SafeInputsTransportStdio, SafeInputsTransportHTTP)
+	}
+
+	server, cleanup, err := newSafeInputsDevServer(opts)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := server.reload(); err != nil {
+		return err
+	}
+	defer server.stop()
+
+	if opts.Transport == SafeInputsTransportHTTP {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Serving %d safe-input tool(s) on http://localhost:%d", server.tools, server.port)))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Serving %d safe-input tool(s) on stdio", server.tools)))
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	stdinClosed := make(chan struct{})
+	if opts.Transport == SafeInputsTransportStdio {
+		go func() {
+			server.forwardClientInput(os.Stdin)
+			close(stdinClosed)
+		}()
+	}
+
+	var events <-chan fsnotify.Event
+	var watchErrors <-chan error
+	if opts.Watch {
+		watcher, err := newSafeInputsWatcher(server.workflowPath, opts.EnvFile)
+		if err != nil {
+			return err
+		}
+		defer watcher.Close()
+		events = watcher.Events
+		watchErrors = watcher.Errors
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Watching for changes to %s...", server.workflowPath)))
+	}
+
+	var debouncer watchDebouncer
+	reloadChan := make(chan struct{}, 1)
+
+	for {
+		select {
+		case <-sigChan:
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Stopping safe-inputs server..."))
+			return nil
+		case <-stdinClosed:
+			safeInputsDevLog.Print("Client closed stdin, stopping")
+			return nil
+		case err := <-watchErrors:
+			safeInputsDevLog.Printf("Watcher error: %v", err)
+		case event := <-events:
+			if !isSafeInputsWatchEvent(event, opts.EnvFile) {
+				continue
+			}
+			safeInputsDevLog.Printf("Detected change: %s (%s)", event.Name, event.Op.String())
+			debouncer.trigger(func() {
+				select {
+				case reloadChan <- struct{}{}:
+				default:
+				}
+			})
+		case <-reloadChan:
+			start := time.Now()
+			if err := server.reload(); err != nil {
+				if server.running() {
+					fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("Reload failed, keeping the previous server: %v", err)))
+				} else {
+					fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("Reload failed, server stopped until the next change: %v", err)))
+				}
+				continue
+			}
+			server.notifyToolsChanged()
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Reloaded %d safe-input tool(s) in %s", server.tools, time.Since(start).Round(time.Millisecond))))
+		}
+	}
+}
+
+// newSafeInputsDevServer resolves the workflow and runtime scripts and creates the working directory.
+// The returned cleanup function removes the working directory.
+func newSafeInputsDevServer(opts SafeInputsDevOptions) (*safeInputsDevServer, func(), error) {
+	if _, err := exec.LookPath("node"); err != nil {
+		return nil, nil, fmt.Errorf("node not found. Please install Node.js to run the safe-inputs MCP server: %w", err)
+	}
+
+	workflowPath, err := resolveSafeInputsWorkflowPath(opts.WorkflowFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runtimeDir, err := resolveSafeInputsRuntimeDir(opts.RuntimeDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.EnvFile == "" {
+		opts.EnvFile = ".env"
+		if gitRoot, err := findGitRoot(); err == nil {
+			opts.EnvFile = filepath.Join(gitRoot, ".env")
+		}
+	}
+	if opts.EnvFile, err = filepath.Abs(opts.EnvFile); err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve env file path: %w", err)
+	}
+
+	port := opts.Port
+	if opts.Transport == SafeInputsTransportHTTP && port == 0 {
+		if port = findAvailablePort(safeInputsStartPort, opts.Verbose); port == 0 {
+			return nil, nil, fmt.Errorf("failed to find an available port for the HTTP server")
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "gh-aw-safe-inputs-dev-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			safeInputsDevLog.Printf("Failed to remove %s: %v", dir, err)
+		}
+	}
+
+	if opts.Verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Using runtime scripts from %s", runtimeDir)))
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Resolving env references from %s", opts.EnvFile)))
+	}
+
+	return &safeInputsDevServer{
+		opts:         opts,
+		workflowPath: workflowPath,
+		runtimeDir:   runtimeDir,
+		dir:          dir,
+		port:         port,
+		out:          os.Stdout,
+		serverLogs:   os.Stderr,
+	}, cleanup, nil
+}
+
+// newSafeInputsWatcher watches the workflow directory tree (for the workflow and its imports)
+// and the directory of the env file
+func newSafeInputsWatcher(workflowPath, envFile string) (*fsnotify.Watcher, error) {
+	workflowDir := filepath.Dir(workflowPath)
+	watcher, addWatchPath, err := newDirectoryTreeWatcher(workflowDir, safeInputsDevLog)
+	if err != nil {
+		return nil, err
+	}
+
+	if envDir := filepath.Dir(envFile); envDir != workflowDir {
+		if err := addWatchPath(envDir); err != nil {
+			safeInputsDevLog.Printf("Failed to watch %s: %v", envDir, err)
+		}
+	}
+
+	return watcher, nil
+}
+
+// isSafeInputsWatchEvent returns true if a file system event should trigger a reload
+func isSafeInputsWatchEvent(event fsnotify.Event, envFile string) bool {
+	if event.Has(fsnotify.Chmod) {
+		return false
+	}
+	return strings.HasSuffix(event.Name, ".md") || event.Name == envFile
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var safeInputsEnvLog = logger.New("cli:safe_inputs_env")
+
+// safeInputsEnvExpressionPattern matches GitHub Actions expressions in safe-input env values
+var safeInputsEnvExpressionPattern = regexp.MustCompile(`\$\{\{\s*([^}]+?)\s*\}\}`)
+
+// safeInputsEnvReferencePattern matches the expressions that can be resolved from local values
+// (secrets.NAME, vars.NAME and env.NAME)
+var safeInputsEnvReferencePattern = regexp.MustCompile(`^(?:secrets|vars|env)\.([A-Za-z_][A-Za-z0-9_]*)$`)
+
+// safeInputsEnvIssue describes a safe-input env var that cannot be resolved locally
+type safeInputsEnvIssue struct {
+	Tool       string // Safe-input tool name
+	EnvVar     string // Environment variable declared under the tool's env
+	Expression string // Expression that could not be resolved (e.g., "secrets.API_KEY")
+	Name       string // Local variable name expected in .env, empty if the expression is not supported locally
+}
+
+// String formats the issue with a hint on how to fix it
+func (i safeInputsEnvIssue) String() string {
+	if i.Name == "" {
+		return fmt.Sprintf("safe-inputs.%s.env.%s: '${{ %s }}' cannot be resolved locally", i.Tool, i.EnvVar, i.Expression)
+	}
+	return fmt.Sprintf("safe-inputs.%s.env.%s: '${{ %s }}' is not set. Add %s=... to your .env file", i.Tool, i.EnvVar, i.Expression, i.Name)
+}
+
+// loadDotEnvFile reads KEY=VALUE pairs from a .env file.
+// A missing file is not an error and yields an empty map.
+func loadDotEnvFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		safeInputsEnvLog.Printf("No env file at %s", path)
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file %s: %w", path, err)
+	}
+	return parseDotEnv(string(content)), nil
+}
+
+// parseDotEnv parses .env content: KEY=VALUE lines, optional "export " prefix,
+// single or double quoted values, and # comments
+func parseDotEnv(content string) map[string]string {
+	values := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key == "" {
+			continue
+		}
+
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			quote := value[0]
+			value = value[1 : len(value)-1]
+			if quote == '"' {
+				value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value)
+			}
+		} else if idx := strings.Index(value, " #"); idx >= 0 {
+			// Strip trailing comments from unquoted values
+			value = strings.TrimSpace(value[:idx])
+		}
+
+		values[key] = value
+	}
+
+	return values
+}
+
+// resolveSafeInputsEnv resolves the env vars declared by safe-input tools against local values.
+// secrets.NAME, vars.NAME and env.NAME expressions are looked up in the .env values first and
+// then in the process environment; github.token falls back to GITHUB_TOKEN.
+// Returns the resolved env vars and the references that could not be resolved.
+func resolveSafeInputsEnv(safeInputs *workflow.SafeInputsConfig, dotEnv map[string]string) (map[string]string, []safeInputsEnvIssue) {
+	resolved := make(map[string]string)
+	var issues []safeInputsEnvIssue
+
+	lookup := func(name string) (string, bool) {
+		if value, ok := dotEnv[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	}
+
+	toolNames := make([]string, 0, len(safeInputs.Tools))
+	for toolName := range safeInputs.Tools {
+		toolNames = append(toolNames, toolName)
+	}
+	sort.Strings(toolNames)
+
+	for _, toolName := range toolNames {
+		toolConfig := safeInputs.Tools[toolName]
+
+		envNames := make([]string, 0, len(toolConfig.Env))
+		for envName := range toolConfig.Env {
+			envNames = append(envNames, envName)
+		}
+		sort.Strings(envNames)
+
+		for _, envName := range envNames {
+			value := safeInputsEnvExpressionPattern.ReplaceAllStringFunc(toolConfig.Env[envName], func(match string) string {
+				expression := safeInputsEnvExpressionPattern.FindStringSubmatch(match)[1]
+
+				name := ""
+				if m := safeInputsEnvReferencePattern.FindStringSubmatch(expression); m != nil {
+					name = m[1]
+				} else if expression == "github.token" {
+					name = "GITHUB_TOKEN"
+				}
+
+				if name != "" {
+					if value, ok := lookup(name); ok {
+						return value
+					}
+				}
+				issues = append(issues, safeInputsEnvIssue{Tool: toolName, EnvVar: envName, Expression: expression, Name: name})
+				return ""
+			})
+			resolved[envName] = value
+		}
+	}
+
+	safeInputsEnvLog.Printf("Resolved %d safe-input env vars, %d unresolved references", len(resolved), len(issues))
+	return resolved, issues
+}
@@ -0,0 +1,82 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	content := `# Local secrets
+API_KEY=abc123
+export REGION = us-east-1
+QUOTED="hello world"
+SINGLE='raw $value'
+ESCAPED="line1\nline2"
+TRAILING=value # comment
+EMPTY=
+not a pair
+`
+
+	values := parseDotEnv(content)
+
+	assert.Equal(t, map[string]string{
+		"API_KEY":  "abc123",
+		"REGION":   "us-east-1",
+		"QUOTED":   "hello world",
+		"SINGLE":   "raw $value",
+		"ESCAPED":  "line1\nline2",
+		"TRAILING": "value",
+		"EMPTY":    "",
+	}, values, "parsed .env values should match")
+}
+
+func TestLoadDotEnvFileMissing(t *testing.T) {
+	values, err := loadDotEnvFile(filepath.Join(t.TempDir(), ".env"))
+	require.NoError(t, err, "missing .env file should not be an error")
+	assert.Empty(t, values, "missing .env file should yield no values")
+}
+
+func TestResolveSafeInputsEnv(t *testing.T) {
+	t.Setenv("GH_AW_TEST_FROM_PROCESS", "from-process")
+	require.NoError(t, os.Unsetenv("GITHUB_TOKEN"), "GITHUB_TOKEN should be unset for the test")
+
+	safeInputs := &workflow.SafeInputsConfig{
+		Tools: map[string]*workflow.SafeInputToolConfig{
+			"search": {
+				Name: "search",
+				Env: map[string]string{
+					"API_KEY":  "${{ secrets.API_KEY }}",
+					"ENDPOINT": "https://${{ vars.HOST }}/api",
+					"PROCESS":  "${{ env.GH_AW_TEST_FROM_PROCESS }}",
+					"LITERAL":  "plain",
+					"MISSING":  "${{ secrets.NOT_SET }}",
+					"TOKEN":    "${{ github.token }}",
+					"RUN_ID":   "${{ github.run_id }}",
+				},
+			},
+		},
+	}
+
+	resolved, issues := resolveSafeInputsEnv(safeInputs, map[string]string{"API_KEY": "secret", "HOST": "example.com"})
+
+	assert.Equal(t, "secret", resolved["API_KEY"], "secrets should resolve from .env")
+	assert.Equal(t, "https://example.com/api", resolved["ENDPOINT"], "embedded expressions should be substituted")
+	assert.Equal(t, "from-process", resolved["PROCESS"], "values should fall back to the process environment")
+	assert.Equal(t, "plain", resolved["LITERAL"], "literal values should be kept")
+	assert.Empty(t, resolved["MISSING"], "unresolved references should be empty")
+
+	require.Len(t, issues, 3, "should report unresolved references")
+	assert.Equal(t, safeInputsEnvIssue{Tool: "search", EnvVar: "MISSING", Expression: "secrets.NOT_SET", Name: "NOT_SET"}, issues[0], "missing secret should be reported")
+	assert.Equal(t, safeInputsEnvIssue{Tool: "search", EnvVar: "RUN_ID", Expression: "github.run_id"}, issues[1], "unsupported expressions should be reported")
+	assert.Equal(t, "GITHUB_TOKEN", issues[2].Name, "github.token should map to GITHUB_TOKEN")
+
+	assert.Contains(t, issues[0].String(), "Add NOT_SET=... to your .env file", "issue should explain how to fix it")
+	assert.Contains(t, issues[1].String(), "cannot be resolved locally", "unsupported expressions should say so")
+}
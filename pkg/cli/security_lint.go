@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var securityLintLog = logger.New("cli:security_lint")
+
+// lockFileLintResult holds the findings of the security linter for one lock file
+type lockFileLintResult struct {
+	LockFile string
+	Findings []workflow.LockFileLintFinding
+}
+
+// lintLockFiles runs the built-in security linter on each lock file and applies the rule
+// severities of the lint configuration (nil keeps the defaults)
+func lintLockFiles(lockFiles []string, config *workflow.LintConfig) ([]lockFileLintResult, error) {
+	var results []lockFileLintResult
+	for _, lockFile := range lockFiles {
+		content, err := os.ReadFile(lockFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", lockFile, err)
+		}
+		findings, err := workflow.LintLockFile(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint %s: %w", filepath.Base(lockFile), err)
+		}
+		findings = config.ApplyToLockFileFindings(findings)
+		securityLintLog.Printf("Linted %s: %d finding(s)", lockFile, len(findings))
+		results = append(results, lockFileLintResult{LockFile: lockFile, Findings: findings})
+	}
+	return results, nil
+}
+
+// runSecurityLintOnFiles runs the built-in security linter on one or more .lock.yml files and
+// displays the findings in the compiler error format
+func runSecurityLintOnFiles(lockFiles []string, verbose bool, strict bool) error {
+	if len(lockFiles) == 0 {
+		return nil
+	}
+
+	securityLintLog.Printf("Running security linter on %d file(s) (verbose=%t, strict=%t)", len(lockFiles), verbose, strict)
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Running security linter on %d file(s)", len(lockFiles))))
+	}
+
+	var config *workflow.LintConfig
+	if gitRoot, err := findGitRoot(); err == nil {
+		if config, err = workflow.LoadLintConfig(gitRoot); err != nil {
+			return err
+		}
+	}
+
+	results, err := lintLockFiles(lockFiles, config)
+	if err != nil {
+		return err
+	}
+
+	totalFindings := 0
+	filesWithFindings := 0
+	for _, result := range results {
+		if len(result.Findings) == 0 {
+			continue
+		}
+		filesWithFindings++
+		totalFindings += len(result.Findings)
+		displayLockFileLintFindings(result)
+	}
+
+	if totalFindings == 0 {
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Security linter found no issues"))
+		}
+		return nil
+	}
+
+	if strict {
+		return fmt.Errorf("strict mode: security linter found %d issue(s) in %d file(s) - workflows must have no security linter findings in strict mode", totalFindings, filesWithFindings)
+	}
+	return nil
+}
+
+// displayLockFileLintFindings prints the findings for one lock file with surrounding lines
+func displayLockFileLintFindings(result lockFileLintResult) {
+	var fileLines []string
+	if content, err := os.ReadFile(result.LockFile); err == nil {
+		fileLines = strings.Split(string(content), "\n")
+	}
+
+	for _, finding := range result.Findings {
+		// Context lines are centered on the finding
+		var context []string
+		if finding.Line > 0 && finding.Line <= len(fileLines) {
+			radius := min(2, finding.Line-1, len(fileLines)-finding.Line)
+			context = fileLines[finding.Line-1-radius : finding.Line+radius]
+		}
+
+		fmt.Fprint(os.Stderr, console.FormatError(console.CompilerError{
+			Position: console.ErrorPosition{
+				File:   result.LockFile,
+				Line:   finding.Line,
+				Column: finding.Column,
+			},
+			Type:    finding.Severity,
+			Message: fmt.Sprintf("[%s] %s", finding.Rule, finding.Message),
+			Context: context,
+			Hint:    finding.Hint,
+		}))
+		if finding.Hint != "" {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(finding.Hint))
+		}
+
+		recordSARIFResult(string(finding.Rule), lockFileLintRuleDescription(finding.Rule), "",
+			finding.Severity, finding.Message+". "+finding.Hint, result.LockFile, finding.Line, finding.Column)
+	}
+}
+
+// lockFileLintRuleDescription returns the description of a security linter rule
+func lockFileLintRuleDescription(rule workflow.LockFileLintRule) string {
+	for _, info := range workflow.LockFileLintRules() {
+		if info.ID == rule {
+			return info.Description
+		}
+	}
+	return string(rule)
+}
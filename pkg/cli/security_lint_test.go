@@ -0,0 +1,40 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSecurityLintOnFiles(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "security-lint-*")
+	cleanLock := filepath.Join(tmpDir, "clean.lock.yml")
+	require.NoError(t, os.WriteFile(cleanLock, []byte("permissions: {}\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ok\n"), 0o644), "should write clean lock file")
+	unpinnedLock := filepath.Join(tmpDir, "unpinned.lock.yml")
+	require.NoError(t, os.WriteFile(unpinnedLock, []byte("permissions: {}\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/setup-node@v4\n"), 0o644), "should write unpinned lock file")
+
+	results, err := lintLockFiles([]string{cleanLock, unpinnedLock}, nil)
+	require.NoError(t, err, "lock files should be linted")
+	require.Len(t, results, 2, "should return one result per lock file")
+	assert.Empty(t, results[0].Findings, "clean lock file should have no findings")
+	require.Len(t, results[1].Findings, 1, "unpinned action should be reported")
+	assert.Equal(t, workflow.LintRuleUnpinnedAction, results[1].Findings[0].Rule, "finding should carry its rule ID")
+
+	config := &workflow.LintConfig{Rules: map[string]workflow.RuleSeverity{"unpinned-action": workflow.RuleSeverityOff}}
+	results, err = lintLockFiles([]string{unpinnedLock}, config)
+	require.NoError(t, err, "lock files should be linted")
+	assert.Empty(t, results[0].Findings, "findings of rules turned off should be dropped")
+
+	require.NoError(t, runSecurityLintOnFiles([]string{cleanLock}, false, true), "strict mode should pass without findings")
+	require.NoError(t, runSecurityLintOnFiles([]string{unpinnedLock}, false, false), "findings should not fail in non-strict mode")
+	err = runSecurityLintOnFiles([]string{cleanLock, unpinnedLock}, false, true)
+	require.Error(t, err, "findings should fail in strict mode")
+	assert.Contains(t, err.Error(), "1 issue(s) in 1 file(s)", "error should count the findings")
+}
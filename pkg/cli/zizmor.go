@@ -288,9 +288,23 @@ func parseAndDisplayZizmorOutput(stdout, stderr string, verbose bool) (int, erro
 				}
 
 				fmt.Fprint(os.Stderr, console.FormatError(compilerErr))
+
+				recordSARIFResult("zizmor/"+ident, "zizmor: "+ident, url, zizmorSARIFLevel(severity), desc, filePath, lineNum, colNum)
 			}
 		}
 	}
 
 	return totalWarnings, nil
 }
+
+// zizmorSARIFLevel maps a zizmor severity to a SARIF result level
+func zizmorSARIFLevel(severity string) string {
+	switch severity {
+	case "High", "Critical":
+		return "error"
+	case "Medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
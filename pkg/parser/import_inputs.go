@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var importInputsLog = logger.New("parser:import_inputs")
+
+// importInputTypes are the supported input types of shared workflows and snippets
+var importInputTypes = []string{"string", "number", "boolean", "choice", "array"}
+
+// importInputItemTypes are the supported element types of array inputs
+var importInputItemTypes = []string{"string", "number", "boolean", "object"}
+
+// ParseImportInputDefinitions parses the inputs section of a shared workflow's frontmatter.
+// It returns nil if the file does not declare inputs.
+func ParseImportInputDefinitions(frontmatter map[string]any) (map[string]ImportInputDefinition, error) {
+	inputsValue, ok := frontmatter["inputs"]
+	if !ok || inputsValue == nil {
+		return nil, nil
+	}
+	inputsMap, ok := inputsValue.(map[string]any)
+	if !ok {
+		return nil, errors.New("'inputs' must be an object mapping input names to definitions")
+	}
+
+	definitions := make(map[string]ImportInputDefinition, len(inputsMap))
+	for name, value := range inputsMap {
+		config, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("input '%s' must be an object", name)
+		}
+		definition := ImportInputDefinition{Default: config["default"]}
+		definition.Description, _ = config["description"].(string)
+		definition.Required, _ = config["required"].(bool)
+		definition.Type, _ = config["type"].(string)
+		definition.Items, _ = config["items"].(string)
+		if options, ok := config["options"].([]any); ok {
+			for _, option := range options {
+				definition.Options = append(definition.Options, fmt.Sprintf("%v", option))
+			}
+		}
+
+		if definition.Type != "" && !slices.Contains(importInputTypes, definition.Type) {
+			return nil, fmt.Errorf("input '%s' has unsupported type '%s'. Supported types: %s", name, definition.Type, strings.Join(importInputTypes, ", "))
+		}
+		if definition.Items != "" && !slices.Contains(importInputItemTypes, definition.Items) {
+			return nil, fmt.Errorf("input '%s' has unsupported items type '%s'. Supported types: %s", name, definition.Items, strings.Join(importInputItemTypes, ", "))
+		}
+		if definition.Default != nil {
+			if err := checkImportInputType(definition, definition.Default); err != nil {
+				return nil, fmt.Errorf("default of input '%s' %w", name, err)
+			}
+		}
+		definitions[name] = definition
+	}
+
+	importInputsLog.Printf("Parsed %d input definitions", len(definitions))
+	return definitions, nil
+}
+
+// ValidateImportInputs type-checks the input values passed to a shared workflow against its
+// input definitions and returns the values with defaults applied. Without definitions, the
+// values are returned as-is.
+func ValidateImportInputs(definitions map[string]ImportInputDefinition, inputs map[string]any) (map[string]any, error) {
+	if definitions == nil {
+		return inputs, nil
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(inputs) {
+		definition, ok := definitions[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown input '%s'. Declared inputs: %s", name, formatDeclaredInputs(definitions)))
+			continue
+		}
+		if err := checkImportInputType(definition, inputs[name]); err != nil {
+			errs = append(errs, fmt.Errorf("input '%s' %w", name, err))
+		}
+	}
+
+	resolved := make(map[string]any, len(definitions))
+	for _, name := range sortedKeys(definitions) {
+		definition := definitions[name]
+		if value, ok := inputs[name]; ok {
+			resolved[name] = value
+		} else if definition.Default != nil {
+			resolved[name] = definition.Default
+		} else if definition.Required {
+			errs = append(errs, fmt.Errorf("missing required input '%s'", name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	importInputsLog.Printf("Validated %d inputs against %d definitions", len(inputs), len(definitions))
+	return resolved, nil
+}
+
+// checkImportInputType checks that a value matches the type of an input definition
+func checkImportInputType(definition ImportInputDefinition, value any) error {
+	switch definition.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("must be a string, got %s", describeInputValue(value))
+		}
+	case "number":
+		if !isInputNumber(value) {
+			return fmt.Errorf("must be a number, got %s", describeInputValue(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("must be a boolean, got %s", describeInputValue(value))
+		}
+	case "choice":
+		str, ok := value.(string)
+		if !ok || !slices.Contains(definition.Options, str) {
+			return fmt.Errorf("must be one of %s, got %s", strings.Join(definition.Options, ", "), describeInputValue(value))
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("must be an array, got %s", describeInputValue(value))
+		}
+		if definition.Items == "" {
+			return nil
+		}
+		for i, item := range items {
+			if err := checkImportInputType(ImportInputDefinition{Type: definition.Items}, item); err != nil {
+				return fmt.Errorf("item %d %w", i, err)
+			}
+		}
+	case "object":
+		if _, ok := value.(map[string]any); !ok {
+			return fmt.Errorf("must be an object, got %s", describeInputValue(value))
+		}
+	}
+	// Untyped inputs accept any value
+	return nil
+}
+
+// isInputNumber reports whether a value parsed from YAML is a number
+func isInputNumber(value any) bool {
+	switch value.(type) {
+	case int, int64, uint64, float64:
+		return true
+	}
+	return false
+}
+
+// describeInputValue describes the type of a value in input validation errors
+func describeInputValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	if isInputNumber(value) {
+		return fmt.Sprintf("number %v", value)
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// formatDeclaredInputs lists the declared input names for error messages
+func formatDeclaredInputs(definitions map[string]ImportInputDefinition) string {
+	if len(definitions) == 0 {
+		return "(none)"
+	}
+	return strings.Join(sortedKeys(definitions), ", ")
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+//go:build !integration
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportInputDefinitions(t *testing.T) {
+	definitions, err := ParseImportInputDefinitions(map[string]any{"tools": map[string]any{}})
+	require.NoError(t, err, "frontmatter without inputs should parse")
+	assert.Nil(t, definitions, "frontmatter without inputs should not declare inputs")
+
+	definitions, err = ParseImportInputDefinitions(map[string]any{
+		"inputs": map[string]any{
+			"count":  map[string]any{"type": "number", "default": uint64(10)},
+			"checks": map[string]any{"type": "array", "items": "string", "required": true},
+			"mode":   map[string]any{"type": "choice", "options": []any{"fast", "thorough"}, "default": "fast"},
+		},
+	})
+	require.NoError(t, err, "valid definitions should parse")
+	require.Len(t, definitions, 3, "all inputs should be parsed")
+	assert.Equal(t, "string", definitions["checks"].Items, "items type should be parsed")
+	assert.True(t, definitions["checks"].Required, "required should be parsed")
+	assert.Equal(t, []string{"fast", "thorough"}, definitions["mode"].Options, "options should be parsed")
+
+	tests := []struct {
+		name   string
+		inputs any
+		errMsg string
+	}{
+		{"unsupported type", map[string]any{"x": map[string]any{"type": "date"}}, "unsupported type 'date'"},
+		{"unsupported items", map[string]any{"x": map[string]any{"type": "array", "items": "array"}}, "unsupported items type 'array'"},
+		{"default of wrong type", map[string]any{"x": map[string]any{"type": "boolean", "default": "yes"}}, "default of input 'x' must be a boolean"},
+		{"not an object", []any{"x"}, "'inputs' must be an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseImportInputDefinitions(map[string]any{"inputs": tt.inputs})
+			require.Error(t, err, "invalid definitions should fail")
+			assert.Contains(t, err.Error(), tt.errMsg, "error should describe the problem")
+		})
+	}
+}
+
+func TestValidateImportInputs(t *testing.T) {
+	definitions := map[string]ImportInputDefinition{
+		"count":  {Type: "number", Default: uint64(10)},
+		"checks": {Type: "array", Items: "string", Required: true},
+		"mode":   {Type: "choice", Options: []string{"fast", "thorough"}},
+	}
+
+	resolved, err := ValidateImportInputs(definitions, map[string]any{"checks": []any{"lint", "test"}})
+	require.NoError(t, err, "valid inputs should pass")
+	assert.Equal(t, map[string]any{"count": uint64(10), "checks": []any{"lint", "test"}}, resolved, "defaults should be applied")
+
+	legacy := map[string]any{"anything": "goes"}
+	resolved, err = ValidateImportInputs(nil, legacy)
+	require.NoError(t, err, "inputs of files without definitions should not be checked")
+	assert.Equal(t, legacy, resolved, "inputs of files without definitions should be returned as-is")
+
+	_, err = ValidateImportInputs(definitions, map[string]any{
+		"count":  "many",
+		"checks": []any{"lint", 3},
+		"mode":   "slow",
+		"extra":  true,
+	})
+	require.Error(t, err, "invalid inputs should fail")
+	assert.Contains(t, err.Error(), "input 'count' must be a number, got string \"many\"", "type errors should be reported")
+	assert.Contains(t, err.Error(), "input 'checks' item 1 must be a string, got number 3", "item type errors should be reported")
+	assert.Contains(t, err.Error(), "input 'mode' must be one of fast, thorough", "choice errors should be reported")
+	assert.Contains(t, err.Error(), "unknown input 'extra'. Declared inputs: checks, count, mode", "unknown inputs should be reported")
+
+	untyped := map[string]any{"anything": []any{"a", map[string]any{"b": 1}}, "other": map[string]any{"c": true}}
+	resolved, err = ValidateImportInputs(map[string]ImportInputDefinition{"anything": {}, "other": {}}, untyped)
+	require.NoError(t, err, "untyped inputs should accept arrays and objects")
+	assert.Equal(t, untyped, resolved, "untyped input values should be returned as-is")
+
+	_, err = ValidateImportInputs(definitions, map[string]any{})
+	require.Error(t, err, "missing required inputs should fail")
+	assert.Contains(t, err.Error(), "missing required input 'checks'", "missing inputs should be reported")
+}
@@ -40,6 +40,7 @@ type ImportsResult struct {
 	MergedJobs          string           // Merged jobs from imported YAML workflows (JSON format)
 	MergedFeatures      []map[string]any // Merged features configuration from all imports (parsed YAML structures)
 	ImportedFiles       []string         // List of imported file paths (for manifest)
+	ResolvedFiles       []string         // Resolved paths of all imported files in processing order (for change detection)
 	AgentFile           string           // Path to custom agent file (if imported)
 	AgentImportSpec     string           // Original import specification for agent file (e.g., "owner/repo/path@ref")
 	RepositoryImports   []string         // List of repository imports (format: "owner/repo@ref") for .github folder merging
@@ -48,6 +49,9 @@ type ImportsResult struct {
 	// This is an appropriate use of 'any' for dynamic YAML/JSON data.
 	// See scratchpad/go-type-patterns.md for guidance on when to use map[string]any.
 	ImportInputs map[string]any // Aggregated input values from all imports (key = input name, value = input value)
+	// TemplateInputs holds the input values the inlined imports are rendered with: the values
+	// passed by the imports, checked against the declared inputs, with defaults applied
+	TemplateInputs map[string]any
 }
 
 // ImportInputDefinition defines an input parameter for a shared workflow import.
@@ -58,8 +62,9 @@ type ImportInputDefinition struct {
 	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Required    bool     `yaml:"required,omitempty" json:"required,omitempty"`
 	Default     any      `yaml:"default,omitempty" json:"default,omitempty"` // Can be string, number, or boolean (dynamic type from YAML)
-	Type        string   `yaml:"type,omitempty" json:"type,omitempty"`       // "string", "choice", "boolean", "number"
+	Type        string   `yaml:"type,omitempty" json:"type,omitempty"`       // "string", "choice", "boolean", "number", "array"
 	Options     []string `yaml:"options,omitempty" json:"options,omitempty"` // Options for choice type
+	Items       string   `yaml:"items,omitempty" json:"items,omitempty"`     // Element type for array type: "string", "number", "boolean", "object"
 }
 
 // ImportSpec represents a single import specification (either a string path or an object with path and inputs)
@@ -209,6 +214,7 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 	var queue []importQueueItem
 	visited := make(map[string]bool)
 	processedOrder := []string{} // Track processing order for manifest
+	var resolvedFiles []string   // Track resolved paths for change detection
 
 	// Initialize result accumulators
 	var toolsBuilder strings.Builder
@@ -244,6 +250,9 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 	var repositoryImports []string        // Track repository-only imports for .github folder merging
 	importInputs := make(map[string]any)  // Aggregated input values from all imports
 
+	// Input values the inlined imports are rendered with, with defaults applied
+	templateInputs := make(map[string]any)
+
 	// Seed the queue with initial imports
 	for _, importSpec := range importSpecs {
 		importPath := importSpec.Path
@@ -343,6 +352,7 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 
 		// Add to processing order
 		processedOrder = append(processedOrder, item.importPath)
+		resolvedFiles = append(resolvedFiles, item.fullPath)
 
 		// Check if this is a custom agent file (any markdown file under .github/agents)
 		isAgentFile := strings.Contains(item.fullPath, "/.github/agents/") && strings.HasSuffix(strings.ToLower(item.fullPath), ".md")
@@ -570,13 +580,38 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 			importRelPath = item.importPath
 		}
 
-		if len(item.inputs) == 0 {
+		// Parse the input definitions of the imported file, if it declares inputs, and
+		// check the inputs passed by the import against them
+		var inputDefinitions map[string]ImportInputDefinition
+		if result != nil && result.Frontmatter != nil {
+			inputDefinitions, err = ParseImportInputDefinitions(result.Frontmatter)
+			if err != nil {
+				return nil, fmt.Errorf("invalid inputs in imported file '%s': %w", item.fullPath, err)
+			}
+		}
+		inputs, err := ValidateImportInputs(inputDefinitions, item.inputs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inputs for import '%s': %w", item.importPath, err)
+		}
+		if err := checkImportInputReferences(string(content), inputDefinitions); err != nil {
+			return nil, fmt.Errorf("invalid template in imported file '%s': %w", item.fullPath, err)
+		}
+
+		if !needsCompileTimeRendering(string(content), item.inputs, inputDefinitions) {
 			// No inputs - use runtime-import macro
 			importPaths = append(importPaths, importRelPath)
 			log.Printf("Added import path for runtime-import: %s", importRelPath)
 		} else {
-			// Has inputs - must inline for compile-time substitution
+			// Has inputs or compile-time templates - must inline for compile-time rendering
 			log.Printf("Import %s has inputs - will be inlined for compile-time substitution", importRelPath)
+			for name := range inputDefinitions {
+				if _, ok := templateInputs[name]; !ok {
+					templateInputs[name] = nil // declared inputs without a value render as empty
+				}
+			}
+			for name, value := range inputs {
+				templateInputs[name] = value
+			}
 
 			// Extract markdown content from imported file (only for imports with inputs)
 			markdownContent, err := processIncludedFileWithVisited(item.fullPath, item.sectionName, false, visited)
@@ -800,10 +835,12 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 		MergedJobs:          jobsBuilder.String(),
 		MergedFeatures:      features,
 		ImportedFiles:       topologicalOrder,
+		ResolvedFiles:       resolvedFiles,
 		AgentFile:           agentFile,
 		AgentImportSpec:     agentImportSpec,
 		RepositoryImports:   repositoryImports,
 		ImportInputs:        importInputs,
+		TemplateInputs:      templateInputs,
 	}, nil
 }
 
@@ -0,0 +1,191 @@
+// This file holds the parser side of snippet templates: it decides which imported markdown
+// must be inlined and rendered at compile time, checks the input references of imported files
+// and loads the partials they include. The templates themselves are rendered by the prompt
+// template pipeline of the workflow package (see pkg/workflow/template.go).
+//
+// Template tags inside fenced code blocks and inline code spans are documentation, not
+// templates, so every check in this file ignores them.
+
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var importTemplateLog = logger.New("parser:import_template")
+
+var (
+	// compileTimeTemplateTagRegex matches the tags that are only supported at compile time
+	compileTimeTemplateTagRegex = regexp.MustCompile(`\{\{(?:#each\s|>)`)
+
+	// importInputReferenceRegex matches references to import inputs, in ${{ }} expressions
+	// and in template tags
+	importInputReferenceRegex = regexp.MustCompile(`github\.aw\.inputs\.([A-Za-z0-9_-]+)`)
+)
+
+// UsesCompileTimeTemplates reports whether markdown uses template tags that are only
+// rendered at compile time ({{#each}} or partials) outside of code
+func UsesCompileTimeTemplates(markdown string) bool {
+	return compileTimeTemplateTagRegex.MatchString(MaskMarkdownCode(markdown))
+}
+
+// needsCompileTimeRendering reports whether an imported file must be inlined and rendered at
+// compile time: when the import passes inputs, or the file uses compile-time template tags or
+// references its declared inputs outside of code
+func needsCompileTimeRendering(markdown string, inputs map[string]any, definitions map[string]ImportInputDefinition) bool {
+	if len(inputs) > 0 {
+		return true
+	}
+	masked := MaskMarkdownCode(markdown)
+	if compileTimeTemplateTagRegex.MatchString(masked) {
+		return true
+	}
+	return definitions != nil && importInputReferenceRegex.MatchString(masked)
+}
+
+// checkImportInputReferences checks that the markdown of a file that declares inputs only
+// references declared inputs
+func checkImportInputReferences(markdown string, definitions map[string]ImportInputDefinition) error {
+	if definitions == nil {
+		return nil
+	}
+	for _, match := range importInputReferenceRegex.FindAllStringSubmatch(MaskMarkdownCode(markdown), -1) {
+		if _, ok := definitions[match[1]]; !ok {
+			return fmt.Errorf("unknown input '%s' in github.aw.inputs.%s. Declared inputs: %s", match[1], match[1], formatDeclaredInputs(definitions))
+		}
+	}
+	return nil
+}
+
+// MaskMarkdownCode returns markdown with the content of fenced code blocks and inline code
+// spans replaced by spaces. Offsets and line breaks are preserved, so matches in the masked
+// text can be used as offsets into the original markdown.
+func MaskMarkdownCode(markdown string) string {
+	masked := []byte(markdown)
+	var openFence string
+	offset := 0
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case openFence != "":
+			if fence := codeFenceMarker(trimmed); fence != "" && fence[0] == openFence[0] && len(fence) >= len(openFence) && strings.Trim(trimmed, fence[:1]) == "" {
+				openFence = ""
+			}
+			maskRange(masked, offset, offset+len(line))
+		case codeFenceMarker(trimmed) != "":
+			openFence = codeFenceMarker(trimmed)
+			maskRange(masked, offset, offset+len(line))
+		default:
+			maskInlineCode(masked, offset, line)
+		}
+		offset += len(line)
+	}
+	return string(masked)
+}
+
+// codeFenceMarker returns the fence of a line that opens or closes a fenced code block
+// (three or more backticks or tildes), or "" for other lines
+func codeFenceMarker(trimmedLine string) string {
+	for _, char := range []string{"`", "~"} {
+		count := len(trimmedLine) - len(strings.TrimLeft(trimmedLine, char))
+		if count >= 3 {
+			return strings.Repeat(char, count)
+		}
+	}
+	return ""
+}
+
+// maskInlineCode masks the inline code spans of a line that starts at offset: a run of
+// backticks up to the next run of the same length
+func maskInlineCode(masked []byte, offset int, line string) {
+	for pos := 0; pos < len(line); {
+		start := strings.IndexByte(line[pos:], '`')
+		if start < 0 {
+			return
+		}
+		start += pos
+		ticks := len(line[start:]) - len(strings.TrimLeft(line[start:], "`"))
+		end := -1
+		for search := start + ticks; search < len(line); {
+			next := strings.Index(line[search:], line[start:start+ticks])
+			if next < 0 {
+				break
+			}
+			next += search
+			run := len(line[next:]) - len(strings.TrimLeft(line[next:], "`"))
+			if run == ticks {
+				end = next + ticks
+				break
+			}
+			search = next + run
+		}
+		if end < 0 {
+			pos = start + ticks
+			continue
+		}
+		maskRange(masked, offset+start, offset+end)
+		pos = end
+	}
+}
+
+// maskRange replaces the bytes of a range with spaces, keeping line breaks
+func maskRange(masked []byte, start, end int) {
+	for i := start; i < end; i++ {
+		if masked[i] != '\n' {
+			masked[i] = ' '
+		}
+	}
+}
+
+// TemplatePartial is a markdown file included by a {{> path}} template tag
+type TemplatePartial struct {
+	FullPath string         // Resolved path of the partial file
+	Markdown string         // Markdown body of the partial (or of its section)
+	Inputs   map[string]any // Parameters of the partial, validated against its inputs with defaults applied
+	// Definitions holds the inputs the partial declares; files without an inputs section
+	// declare no inputs
+	Definitions map[string]ImportInputDefinition
+}
+
+// LoadTemplatePartial resolves a partial like an import, from baseDir, and validates its
+// parameters against the inputs the partial declares. The path can select a section with
+// path#Section.
+func LoadTemplatePartial(partialPath string, params map[string]any, baseDir string, cache *ImportCache) (*TemplatePartial, error) {
+	filePath, sectionName, _ := strings.Cut(partialPath, "#")
+	fullPath, err := ResolveIncludePath(filePath, baseDir, cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve partial '%s': %w", partialPath, err)
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partial '%s': %w", partialPath, err)
+	}
+	definitions := map[string]ImportInputDefinition{}
+	if result, err := ExtractFrontmatterFromContent(string(content)); err == nil && result.Frontmatter != nil {
+		parsed, err := ParseImportInputDefinitions(result.Frontmatter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inputs in partial '%s': %w", partialPath, err)
+		}
+		if parsed != nil {
+			definitions = parsed
+		}
+	}
+	inputs, err := ValidateImportInputs(definitions, params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for partial '%s': %w", partialPath, err)
+	}
+
+	markdown, err := processIncludedFileWithVisited(fullPath, sectionName, false, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	importTemplateLog.Printf("Loaded partial %s with %d parameters", partialPath, len(params))
+	return &TemplatePartial{FullPath: fullPath, Markdown: markdown, Inputs: inputs, Definitions: definitions}, nil
+}
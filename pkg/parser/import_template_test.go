@@ -0,0 +1,109 @@
+//go:build !integration
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskMarkdownCode(t *testing.T) {
+	markdown := "Use {{#each x}} here.\n\n```markdown\n{{#each github.aw.inputs.items}}\n```\n\nInline `{{> shared/a.md}}` and ``code with ` tick``.\n~~~~\n{{> b.md}}\n~~~\n~~~~\nAfter {{> c.md}}\n"
+	masked := MaskMarkdownCode(markdown)
+
+	require.Len(t, masked, len(markdown), "masking should preserve offsets")
+	assert.Contains(t, masked, "Use {{#each x}} here.", "text outside code should be kept")
+	assert.Contains(t, masked, "After {{> c.md}}", "text after a closed fence should be kept")
+	assert.NotContains(t, masked, "github.aw.inputs.items", "fenced code should be masked")
+	assert.NotContains(t, masked, "shared/a.md", "inline code should be masked")
+	assert.NotContains(t, masked, "tick", "inline code with a longer backtick run should be masked")
+	assert.NotContains(t, masked, "b.md", "a fence should only be closed by a fence at least as long")
+	assert.Equal(t, strings.Count(markdown, "\n"), strings.Count(masked, "\n"), "masking should preserve line breaks")
+}
+
+func TestNeedsCompileTimeRendering(t *testing.T) {
+	definitions := map[string]ImportInputDefinition{"name": {Type: "string", Default: "x"}}
+
+	tests := []struct {
+		name        string
+		markdown    string
+		inputs      map[string]any
+		definitions map[string]ImportInputDefinition
+		expected    bool
+	}{
+		{"plain markdown", "# Guide\n", nil, nil, false},
+		{"import passes inputs", "# Guide\n", map[string]any{"name": "x"}, nil, true},
+		{"declared inputs alone", "# Guide\n", nil, definitions, false},
+		{"each block", "{{#each github.aw.inputs.name}}{{this}}{{/each}}\n", nil, nil, true},
+		{"partial", "{{> shared/footer.md}}\n", nil, nil, true},
+		{"runtime conditional", "{{#if github.event.issue.number}}\nx\n{{else}}\ny\n{{/if}}\n", nil, nil, false},
+		{"tags in code", "```\n{{#each github.aw.inputs.name}}\n```\nUse `{{> shared/footer.md}}`.\n", nil, nil, false},
+		{"declared input reference", "Hello ${{ github.aw.inputs.name }}\n", nil, definitions, true},
+		{"declared input reference in code", "Write `${{ github.aw.inputs.name }}`\n", nil, definitions, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, needsCompileTimeRendering(tt.markdown, tt.inputs, tt.definitions), "rendering decision should match")
+		})
+	}
+}
+
+func TestCheckImportInputReferences(t *testing.T) {
+	definitions := map[string]ImportInputDefinition{"name": {Type: "string"}, "items": {Type: "array"}}
+
+	require.NoError(t, checkImportInputReferences("${{ github.aw.inputs.name }} {{#each github.aw.inputs.items}}{{/each}}", definitions), "declared inputs should be accepted")
+	require.NoError(t, checkImportInputReferences("${{ github.aw.inputs.other }}", nil), "files without definitions should not be checked")
+	require.NoError(t, checkImportInputReferences("`${{ github.aw.inputs.other }}`", definitions), "references in code should not be checked")
+
+	err := checkImportInputReferences("${{ github.aw.inputs.nme }}", definitions)
+	require.Error(t, err, "unknown inputs should fail")
+	assert.Contains(t, err.Error(), "unknown input 'nme'", "error should name the unknown input")
+	assert.Contains(t, err.Error(), "Declared inputs: items, name", "error should list the declared inputs")
+}
+
+func TestLoadTemplatePartial(t *testing.T) {
+	baseDir := t.TempDir()
+	snippetsDir := filepath.Join(baseDir, "snippets")
+	require.NoError(t, os.MkdirAll(snippetsDir, 0o755), "should create snippets directory")
+	require.NoError(t, os.WriteFile(filepath.Join(snippetsDir, "heading.md"), []byte(`---
+inputs:
+  text:
+    type: string
+    required: true
+  level:
+    type: number
+    default: 2
+---
+**${{ github.aw.inputs.text }}**
+`), 0o644), "should write partial")
+
+	partial, err := LoadTemplatePartial("snippets/heading.md", map[string]any{"text": "Security"}, baseDir, nil)
+	require.NoError(t, err, "partial should load")
+	assert.Equal(t, filepath.Join(snippetsDir, "heading.md"), partial.FullPath, "partial path should be resolved")
+	assert.Contains(t, partial.Markdown, "**${{ github.aw.inputs.text }}**", "partial body should be returned")
+	assert.Equal(t, map[string]any{"text": "Security", "level": uint64(2)}, partial.Inputs, "defaults should be applied")
+
+	errorTests := []struct {
+		name   string
+		path   string
+		params map[string]any
+		errMsg string
+	}{
+		{"missing required parameter", "snippets/heading.md", nil, "missing required input 'text'"},
+		{"parameter of wrong type", "snippets/heading.md", map[string]any{"text": "x", "level": "high"}, "input 'level' must be a number"},
+		{"unknown parameter", "snippets/heading.md", map[string]any{"text": "x", "size": 1}, "unknown input 'size'"},
+		{"missing file", "snippets/missing.md", nil, "failed to resolve partial 'snippets/missing.md'"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadTemplatePartial(tt.path, tt.params, baseDir, nil)
+			require.Error(t, err, "invalid partial should fail")
+			assert.Contains(t, err.Error(), tt.errMsg, "error should describe the problem")
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// WriteWorkflowFiles writes files under the .github/workflows directory of root, creating
+// directories as needed. The keys of files are slash-separated paths relative to
+// .github/workflows. It returns the workflows directory.
+func WriteWorkflowFiles(t *testing.T, root string, files map[string]string) string {
+	t.Helper()
+
+	workflowsDir := filepath.Join(root, ".github", "workflows")
+	if err := os.MkdirAll(workflowsDir, 0o755); err != nil {
+		t.Fatalf("failed to create workflows directory: %v", err)
+	}
+	for name, content := range files {
+		path := filepath.Join(workflowsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return workflowsDir
+}
+
+// GitWorkflowRepo creates a git repository in a temporary directory, writes files under its
+// .github/workflows directory (see WriteWorkflowFiles) and changes into it for the rest of the
+// test. It returns the repository root with symlinks resolved, so paths match the git root
+// reported by git.
+func GitWorkflowRepo(t *testing.T, pattern string, files map[string]string) string {
+	t.Helper()
+
+	tmpDir := TempDir(t, pattern)
+	if output, err := exec.Command("git", "-C", tmpDir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("failed to init git repository: %v: %s", err, output)
+	}
+	gitRoot, err := filepath.EvalSymlinks(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to resolve temp dir: %v", err)
+	}
+	t.Chdir(gitRoot)
+
+	WriteWorkflowFiles(t, gitRoot, files)
+	return gitRoot
+}
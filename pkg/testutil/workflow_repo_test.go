@@ -0,0 +1,30 @@
+//go:build !integration
+
+package testutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+)
+
+func TestGitWorkflowRepo(t *testing.T) {
+	gitRoot := testutil.GitWorkflowRepo(t, "workflow-repo-*", map[string]string{
+		"test.md":         "# Test\n",
+		"shared/extra.md": "# Shared\n",
+	})
+
+	if _, err := os.Stat(filepath.Join(gitRoot, ".git")); err != nil {
+		t.Errorf("repository should be initialized: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(gitRoot, ".github", "workflows", "shared", "extra.md"))
+	if err != nil || string(content) != "# Shared\n" {
+		t.Errorf("nested workflow file should be written, got %q: %v", content, err)
+	}
+	wd, err := os.Getwd()
+	if err != nil || wd != gitRoot {
+		t.Errorf("working directory should be the repository root %s, got %s: %v", gitRoot, wd, err)
+	}
+}
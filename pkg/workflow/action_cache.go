@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -26,10 +27,12 @@ type ActionCacheEntry struct {
 }
 
 // ActionCache manages cached action pin resolutions.
+// It is safe for concurrent use by compilers forked from the same compiler.
 type ActionCache struct {
 	Entries map[string]ActionCacheEntry `json:"entries"` // key: "repo@version"
 	path    string
-	dirty   bool // tracks if cache has unsaved changes
+	dirty   bool         // tracks if cache has unsaved changes
+	mu      sync.RWMutex // guards Entries and dirty during concurrent compilation
 }
 
 // NewActionCache creates a new action cache instance
@@ -45,6 +48,9 @@ func NewActionCache(repoRoot string) *ActionCache {
 
 // Load loads the cache from disk
 func (c *ActionCache) Load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	actionCacheLog.Printf("Loading action cache from: %s", c.path)
 	data, err := os.ReadFile(c.path)
 	if err != nil {
@@ -74,6 +80,9 @@ func (c *ActionCache) Load() error {
 // Deduplicates entries by keeping only the most precise version reference for each repo+SHA combination
 // Only saves if the cache has been modified (dirty flag is true)
 func (c *ActionCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	// Skip saving if cache hasn't been modified
 	if !c.dirty {
 		actionCacheLog.Printf("Cache is clean (no changes), skipping save")
@@ -166,6 +175,9 @@ func (c *ActionCache) marshalSorted() ([]byte, error) {
 
 // Get retrieves a cached entry if it exists
 func (c *ActionCache) Get(repo, version string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	key := formatActionCacheKey(repo, version)
 	entry, exists := c.Entries[key]
 	if !exists {
@@ -180,6 +192,9 @@ func (c *ActionCache) Get(repo, version string) (string, bool) {
 // FindEntryBySHA finds a cache entry with the given repo and SHA
 // Returns the entry and true if found, or empty entry and false if not found
 func (c *ActionCache) FindEntryBySHA(repo, sha string) (ActionCacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	for key, entry := range c.Entries {
 		if entry.Repo == repo && entry.SHA == sha {
 			actionCacheLog.Printf("Found cache entry for %s with SHA %s: %s", repo, sha[:8], key)
@@ -191,6 +206,9 @@ func (c *ActionCache) FindEntryBySHA(repo, sha string) (ActionCacheEntry, bool)
 
 // Set stores a new cache entry
 func (c *ActionCache) Set(repo, version, sha string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	key := formatActionCacheKey(repo, version)
 
 	// Check if there are existing entries with the same repo+SHA but different version
@@ -4,8 +4,10 @@ package workflow
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/github/gh-aw/pkg/testutil"
@@ -545,3 +547,33 @@ func TestActionCacheFindEntryBySHA(t *testing.T) {
 		t.Error("Expected not to find entry for different repo")
 	}
 }
+
+func TestActionCacheConcurrentAccess(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+	cache := NewActionCache(tmpDir)
+
+	// Forked compilers read and write the shared cache concurrently
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo := fmt.Sprintf("actions/action-%d", i)
+			for j := range 50 {
+				version := fmt.Sprintf("v%d", j)
+				sha := fmt.Sprintf("%040d", i*1000+j)
+				cache.Set(repo, version, sha)
+				cache.Get(repo, version)
+				cache.FindEntryBySHA(repo, sha)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(cache.Entries) != 8*50 {
+		t.Errorf("Expected %d entries, got %d", 8*50, len(cache.Entries))
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Failed to save cache: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -154,6 +155,42 @@ func GetActionPin(actionRepo string) string {
 	return formatActionReference(actionRepo, latestPin.SHA, latestPin.Version)
 }
 
+// ActionPinWarningWriter is implemented by diagnostic writers that deduplicate action pin
+// warnings across workflows compiled concurrently by forked compilers
+type ActionPinWarningWriter interface {
+	WriteActionPinWarning(key string, text string)
+}
+
+// emitActionPinWarning prints a warning about an action pin to the workflow's diagnostic writer.
+// It returns false when the rule is turned off, suppressed or configured as an error.
+func emitActionPinWarning(data *WorkflowData, rule DiagnosticRule, message string) bool {
+	if data.Diagnostics != nil && !data.Diagnostics.resolve(rule, message, 0) {
+		return false
+	}
+	var w io.Writer = os.Stderr
+	if data.DiagnosticWriter != nil {
+		w = data.DiagnosticWriter
+	}
+	writeActionPinWarning(w, rule, message)
+	return true
+}
+
+// writeActionPinWarning prints an action pin warning, keyed by its text for writers that
+// deduplicate action pin warnings
+func writeActionPinWarning(w io.Writer, rule DiagnosticRule, message string) {
+	text := console.FormatWarningMessage(ruleMessage(rule, message)) + "\n"
+	if pinWriter, ok := w.(ActionPinWarningWriter); ok {
+		pinWriter.WriteActionPinWarning(ruleMessage(rule, message), text)
+		return
+	}
+	_, _ = io.WriteString(w, text)
+}
+
+// isActionPinRule reports whether warnings of a rule are written by writeActionPinWarning
+func isActionPinRule(rule DiagnosticRule) bool {
+	return rule == RuleActionPinFallback || rule == RuleActionPinUnresolved
+}
+
 // GetActionPinWithData returns the pinned action reference for a given action@version
 // It tries dynamic resolution first, then falls back to hardcoded pins
 // If resolution fails, emits a warning and returns empty string (in both strict and non-strict modes)
@@ -260,8 +297,9 @@ func GetActionPinWithData(actionRepo, version string, data *WorkflowData) (strin
 				if !data.ActionPinWarnings[cacheKey] {
 					warningMsg := fmt.Sprintf("Unable to resolve %s@%s dynamically, using hardcoded pin for %s@%s",
 						actionRepo, version, actionRepo, selectedPin.Version)
-					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-					data.ActionPinWarnings[cacheKey] = true
+					if emitActionPinWarning(data, RuleActionPinFallback, warningMsg) {
+						data.ActionPinWarnings[cacheKey] = true
+					}
 				}
 			}
 			actionPinsLog.Printf("Using version in non-strict mode: %s@%s (requested) → %s@%s (used)",
@@ -297,8 +335,9 @@ func GetActionPinWithData(actionRepo, version string, data *WorkflowData) (strin
 		if data.ActionResolver != nil {
 			warningMsg = fmt.Sprintf("Unable to pin action %s@%s: resolution failed", actionRepo, version)
 		}
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
-		data.ActionPinWarnings[cacheKey] = true
+		if emitActionPinWarning(data, RuleActionPinUnresolved, warningMsg) {
+			data.ActionPinWarnings[cacheKey] = true
+		}
 	}
 	return "", nil
 }
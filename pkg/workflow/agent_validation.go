@@ -91,7 +91,7 @@ func (c *Compiler) validateAgentFile(workflowData *WorkflowData, markdownPath st
 	}
 
 	if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage(
 			fmt.Sprintf("✓ Agent file exists: %s", agentPath)))
 	}
 
@@ -134,8 +134,7 @@ func (c *Compiler) validateWebSearchSupport(tools map[string]any, engine CodingA
 
 	// web-search is specified, check if the engine supports it
 	if !engine.SupportsWebSearch() {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Engine '%s' does not support the web-search tool. See https://github.github.com/gh-aw/guides/web-search/ for alternatives.", engine.GetID())))
-		c.IncrementWarningCount()
+		c.warn(RuleWebSearchUnsupported, fmt.Sprintf("Engine '%s' does not support the web-search tool. See https://github.github.com/gh-aw/guides/web-search/ for alternatives.", engine.GetID()))
 	}
 }
 
@@ -188,7 +187,7 @@ func (c *Compiler) validateWorkflowRunBranches(workflowData *WorkflowData, markd
 	if hasBranches {
 		// Has branch restrictions, validation passed
 		if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("✓ workflow_run trigger has branch restrictions"))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage("✓ workflow_run trigger has branch restrictions"))
 		}
 		return nil
 	}
@@ -212,9 +211,7 @@ func (c *Compiler) validateWorkflowRunBranches(workflowData *WorkflowData, markd
 	}
 
 	// In normal mode, this is a warning
-	formattedWarning := formatCompilerMessage(markdownPath, "warning", message)
-	fmt.Fprintln(os.Stderr, formattedWarning)
-	c.IncrementWarningCount()
+	c.warnAt(RuleStrictWorkflowRunBranches, markdownPath, message)
 
 	return nil
 }
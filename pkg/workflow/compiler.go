@@ -180,6 +180,72 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate create-release and create-tag configuration
+	log.Printf("Validating release safe outputs")
+	if err := validateReleaseSafeOutputs(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate update-file path restrictions
+	log.Printf("Validating update-file configuration")
+	if err := validateUpdateFileConfig(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate manage-labels prefix restrictions
+	log.Printf("Validating manage-labels configuration")
+	if err := validateManageLabelsConfig(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate moderation safe outputs (lock-conversation, pin-issue, transfer-issue)
+	log.Printf("Validating moderation safe outputs")
+	if err := c.validateModerationSafeOutputs(workflowData, markdownPath); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate create-commit-status states
+	log.Printf("Validating create-commit-status configuration")
+	if err := validateCreateCommitStatusConfig(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate merge-pull-request policy gates
+	log.Printf("Validating merge-pull-request configuration")
+	if err := validateMergePullRequestConfig(workflowData.SafeOutputs, workflowData.TrackerID); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate require-approval gates on safe outputs
+	log.Printf("Validating safe-outputs require-approval")
+	if err := validateSafeOutputApprovals(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate cross-item references between safe outputs
+	log.Printf("Validating safe-outputs references")
+	if err := validateSafeOutputReferences(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate content policy for safe output text
+	log.Printf("Validating safe-outputs content-policy")
+	if err := validateSafeOutputContentPolicy(workflowData.SafeOutputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate safe-job outputs and {{ safe_jobs.<job>.<output> }} references
+	log.Printf("Validating safe-job outputs")
+	if err := validateSafeJobOutputs(workflowData); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate safe-inputs tool configuration
+	log.Printf("Validating safe-inputs tools")
+	if err := c.validateSafeInputs(workflowData, markdownPath); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
 	// Validate network allowed domains configuration
 	log.Printf("Validating network allowed domains")
 	if err := c.validateNetworkAllowedDomains(workflowData.NetworkPermissions); err != nil {
@@ -226,26 +292,27 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 
 	// Emit warning for sandbox.agent: false (disables agent sandbox firewall)
 	if isAgentSandboxDisabled(workflowData) {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("⚠️  WARNING: Agent sandbox disabled (sandbox.agent: false). This removes firewall protection. The AI agent will have direct network access without firewall filtering. The MCP gateway remains enabled. Only use this for testing or in controlled environments where you trust the AI agent completely."))
-		c.IncrementWarningCount()
+		c.warn(RuleSandboxDisabled, "⚠️  WARNING: Agent sandbox disabled (sandbox.agent: false). This removes firewall protection. The AI agent will have direct network access without firewall filtering. The MCP gateway remains enabled. Only use this for testing or in controlled environments where you trust the AI agent completely.")
 	}
 
 	// Emit experimental warning for safe-inputs feature
 	if IsSafeInputsEnabled(workflowData.SafeInputs, workflowData) {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: safe-inputs"))
-		c.IncrementWarningCount()
+		c.warn(RuleExperimentalFeature, "Using experimental feature: safe-inputs")
 	}
 
 	// Emit experimental warning for plugins feature
 	if workflowData.PluginInfo != nil && len(workflowData.PluginInfo.Plugins) > 0 {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: plugins"))
-		c.IncrementWarningCount()
+		c.warn(RuleExperimentalFeature, "Using experimental feature: plugins")
 	}
 
 	// Emit experimental warning for rate-limit feature
 	if workflowData.RateLimit != nil {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: rate-limit"))
-		c.IncrementWarningCount()
+		c.warn(RuleExperimentalFeature, "Using experimental feature: rate-limit")
+	}
+
+	// Validate the prompt size limit against the prompt known at compile time
+	if err := c.validatePromptLimit(workflowData, markdownPath); err != nil {
+		return err
 	}
 
 	// Validate workflow_run triggers have branch restrictions
@@ -286,8 +353,7 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 						return formatCompilerError(markdownPath, "error", message, nil)
 					} else {
 						// In non-strict mode, missing permissions are warnings
-						fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", message))
-						c.IncrementWarningCount()
+						c.warnAt(RuleStrictMissingPermissions, markdownPath, message)
 					}
 				}
 			}
@@ -304,8 +370,7 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 				warningMsg := `This workflow grants id-token: write permission
 OIDC tokens can authenticate to cloud providers (AWS, Azure, GCP).
 Ensure proper audience validation and trust policies are configured.`
-				fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", warningMsg))
-				c.IncrementWarningCount()
+				c.warnAt(RuleIDTokenWrite, markdownPath, warningMsg)
 			}
 		}
 	}
@@ -327,8 +392,8 @@ Ensure proper audience validation and trust policies are configured.`
 		originalToolsets := workflowData.ParsedTools.GitHub.Toolset.ToStringSlice()
 		for _, toolset := range originalToolsets {
 			if toolset == "projects" {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage("The 'projects' toolset requires a GitHub token with organization Projects permissions."))
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage("See: https://github.github.com/gh-aw/reference/auth/#gh_aw_project_github_token-github-projects-v2"))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage("The 'projects' toolset requires a GitHub token with organization Projects permissions."))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage("See: https://github.github.com/gh-aw/reference/auth/#gh_aw_project_github_token-github-projects-v2"))
 				break
 			}
 		}
@@ -382,7 +447,7 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 		// Write the invalid YAML to a .invalid.yml file for inspection
 		invalidFile := strings.TrimSuffix(lockFile, ".lock.yml") + ".invalid.yml"
 		if writeErr := os.WriteFile(invalidFile, []byte(yamlContent), 0644); writeErr == nil {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Invalid workflow YAML written to: %s", console.ToRelativePath(invalidFile))))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("Invalid workflow YAML written to: %s", console.ToRelativePath(invalidFile))))
 		}
 		return "", formattedErr
 	}
@@ -395,7 +460,7 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 		// Write the invalid YAML to a .invalid.yml file for inspection
 		invalidFile := strings.TrimSuffix(lockFile, ".lock.yml") + ".invalid.yml"
 		if writeErr := os.WriteFile(invalidFile, []byte(yamlContent), 0644); writeErr == nil {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Workflow with template injection risks written to: %s", console.ToRelativePath(invalidFile))))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("Workflow with template injection risks written to: %s", console.ToRelativePath(invalidFile))))
 		}
 		return "", formattedErr
 	}
@@ -409,7 +474,7 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 			// Write the invalid YAML to a .invalid.yml file for inspection
 			invalidFile := strings.TrimSuffix(lockFile, ".lock.yml") + ".invalid.yml"
 			if writeErr := os.WriteFile(invalidFile, []byte(yamlContent), 0644); writeErr == nil {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Invalid workflow YAML written to: %s", console.ToRelativePath(invalidFile))))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("Invalid workflow YAML written to: %s", console.ToRelativePath(invalidFile))))
 			}
 			return "", formattedErr
 		}
@@ -419,8 +484,7 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 		if err := c.validateContainerImages(workflowData); err != nil {
 			// Treat container image validation failures as warnings, not errors
 			// This is because validation may fail due to auth issues locally (e.g., private registries)
-			fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", fmt.Sprintf("container image validation failed: %v", err)))
-			c.IncrementWarningCount()
+			c.warnAt(RuleContainerImageValidation, markdownPath, fmt.Sprintf("container image validation failed: %v", err))
 		}
 
 		// Validate runtime packages (npx, uv)
@@ -441,8 +505,7 @@ func (c *Compiler) generateAndValidateYAML(workflowData *WorkflowData, markdownP
 			return "", formatCompilerError(markdownPath, "error", fmt.Sprintf("repository feature validation failed: %v", err), err)
 		}
 	} else if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Schema validation available but skipped (use SetSkipValidation(false) to enable)"))
-		c.IncrementWarningCount()
+		c.warn(RuleSchemaValidationSkipped, "Schema validation available but skipped (use SetSkipValidation(false) to enable)")
 	}
 
 	return yamlContent, nil
@@ -481,7 +544,7 @@ func (c *Compiler) writeWorkflowOutput(lockFile, yamlContent string, markdownPat
 				lockSize := console.FormatFileSize(lockFileInfo.Size())
 				maxSize := console.FormatFileSize(MaxLockFileSize)
 				warningMsg := fmt.Sprintf("Generated lock file size (%s) exceeds recommended maximum size (%s)", lockSize, maxSize)
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(warningMsg))
 			}
 		}
 	}
@@ -489,15 +552,15 @@ func (c *Compiler) writeWorkflowOutput(lockFile, yamlContent string, markdownPat
 	// Display success message with file size if we generated a lock file (unless quiet mode)
 	if !c.quiet {
 		if c.noEmit {
-			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(console.ToRelativePath(markdownPath)))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage(console.ToRelativePath(markdownPath)))
 		} else {
 			// Get the size of the generated lock file for display
 			if lockFileInfo, err := os.Stat(lockFile); err == nil {
 				lockSize := console.FormatFileSize(lockFileInfo.Size())
-				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%s (%s)", console.ToRelativePath(markdownPath), lockSize)))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage(fmt.Sprintf("%s (%s)", console.ToRelativePath(markdownPath), lockSize)))
 			} else {
 				// Fallback to original display if we can't get file info
-				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(console.ToRelativePath(markdownPath)))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage(console.ToRelativePath(markdownPath)))
 			}
 		}
 	}
@@ -527,6 +590,12 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 		log.Printf("Compilation completed in %v", time.Since(startTime))
 	}()
 
+	// Continue tracking the rule warnings reported while parsing this workflow
+	if c.diagnostics == nil || c.diagnostics.File != markdownPath {
+		c.beginWorkflowDiagnostics(markdownPath)
+	}
+	workflowData.Diagnostics = c.diagnostics
+
 	// Reset the step order tracker for this compilation
 	c.stepOrderTracker = NewStepOrderTracker()
 
@@ -560,10 +629,17 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 	// instead of using a shared action file
 
 	// Generate and validate YAML
+	c.lastLockFileContent = ""
 	yamlContent, err := c.generateAndValidateYAML(workflowData, markdownPath, lockFile)
 	if err != nil {
 		return err
 	}
+	c.lastLockFileContent = yamlContent
+
+	// Fail on warnings whose rules are configured as errors in lint.yml
+	if err := c.diagnostics.err(); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), nil)
+	}
 
 	// Write output
 	return c.writeWorkflowOutput(lockFile, yamlContent, markdownPath)
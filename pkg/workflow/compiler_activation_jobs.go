@@ -64,6 +64,14 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 		perms.Set(PermissionActions, PermissionRead)
 	}
 
+	// Add statuses: write permission if a pending commit status is set before the agent runs
+	if hasPendingCommitStatus(data) {
+		if perms == nil {
+			perms = NewPermissions()
+		}
+		perms.Set(PermissionStatuses, PermissionWrite)
+	}
+
 	// Set permissions if any were configured
 	if perms != nil {
 		permissions = perms.RenderToYAML()
@@ -294,7 +302,10 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 	}
 
 	// Build the final expression
-	if len(conditions) == 0 {
+	if len(conditions) == 0 && hasPendingCommitStatus(data) {
+		// The pending commit status is the only reason for this job, so activation is unconditional
+		activatedNode = BuildBooleanLiteral(true)
+	} else if len(conditions) == 0 {
 		// This should never happen - it means pre-activation job was created without any checks
 		// If we reach this point, it's a developer error in the compiler logic
 		return nil, fmt.Errorf("developer error: pre-activation job created without permission check or stop-time configuration")
@@ -312,6 +323,15 @@ func (c *Compiler) buildPreActivationJob(data *WorkflowData, needsPermissionChec
 	// Render the expression with ${{ }} wrapper
 	activatedExpression := fmt.Sprintf("${{ %s }}", activatedNode.Render())
 
+	// Set the pending commit status last, gated on the same checks as activation
+	if hasPendingCommitStatus(data) {
+		var pendingCondition string
+		if len(conditions) > 0 {
+			pendingCondition = activatedNode.Render()
+		}
+		steps = append(steps, buildPendingCommitStatusStep(data, pendingCondition)...)
+	}
+
 	outputs := map[string]string{
 		"activated": activatedExpression,
 	}
@@ -897,6 +917,15 @@ func (c *Compiler) buildMainJob(data *WorkflowData, activationJobCreated bool) (
 		}
 	}
 
+	// Summarizing prompt sections calls GitHub Models before the engine runs
+	if data.PromptLimit != nil && len(data.PromptLimit.Summarize) > 0 {
+		perms := NewPermissionsParser(permissions).ToPermissions()
+		if level, exists := perms.Get(PermissionModels); !exists || level == PermissionNone {
+			perms.Set(PermissionModels, PermissionRead)
+			permissions = perms.RenderToYAML()
+		}
+	}
+
 	job := &Job{
 		Name:        string(constants.AgentJobName),
 		If:          jobCondition,
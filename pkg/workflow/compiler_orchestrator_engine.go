@@ -5,7 +5,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 )
@@ -84,8 +83,7 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 	if c.engineOverride != "" {
 		originalEngineSetting := engineSetting
 		if originalEngineSetting != "" && originalEngineSetting != c.engineOverride {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Command line --engine %s overrides markdown file engine: %s", c.engineOverride, originalEngineSetting)))
-			c.IncrementWarningCount()
+			c.warn(RuleEngineOverride, fmt.Sprintf("Command line --engine %s overrides markdown file engine: %s", c.engineOverride, originalEngineSetting))
 		}
 		engineSetting = c.engineOverride
 	}
@@ -115,13 +113,13 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 		fullPath, resolveErr := parser.ResolveIncludePath(importFilePath, markdownDir, importCache)
 		if resolveErr != nil {
 			orchestratorEngineLog.Printf("Skipping security scan for unresolvable import: %s: %v", importedFile, resolveErr)
-			fmt.Fprintf(os.Stderr, "WARNING: Skipping security scan for unresolvable import '%s': %v\n", importedFile, resolveErr)
+			fmt.Fprintf(c.GetDiagnosticWriter(), "WARNING: Skipping security scan for unresolvable import '%s': %v\n", importedFile, resolveErr)
 			continue
 		}
 		importContent, readErr := os.ReadFile(fullPath)
 		if readErr != nil {
 			orchestratorEngineLog.Printf("Skipping security scan for unreadable import: %s: %v", fullPath, readErr)
-			fmt.Fprintf(os.Stderr, "WARNING: Skipping security scan for unreadable import '%s' (resolved path: %s): %v\n", importedFile, fullPath, readErr)
+			fmt.Fprintf(c.GetDiagnosticWriter(), "WARNING: Skipping security scan for unreadable import '%s' (resolved path: %s): %v\n", importedFile, fullPath, readErr)
 			continue
 		}
 		if findings := ScanMarkdownSecurity(string(importContent)); len(findings) > 0 {
@@ -213,8 +211,7 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 
 	log.Printf("AI engine: %s (%s)", agenticEngine.GetDisplayName(), engineSetting)
 	if agenticEngine.IsExperimental() && c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Using experimental engine: %s", agenticEngine.GetDisplayName())))
-		c.IncrementWarningCount()
+		c.warn(RuleExperimentalFeature, fmt.Sprintf("Using experimental engine: %s", agenticEngine.GetDisplayName()))
 	}
 
 	// Enable firewall by default for copilot engine when network restrictions are present
@@ -60,6 +60,9 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 		return nil, fmt.Errorf("no frontmatter found")
 	}
 
+	// Read the gh-aw-ignore comments before any warning is reported
+	c.applySuppressions(cleanPath, result)
+
 	// Preprocess schedule fields to convert human-friendly format to cron expressions
 	if err := c.preprocessScheduleFields(result.Frontmatter, cleanPath, string(content)); err != nil {
 		orchestratorFrontmatterLog.Printf("Schedule preprocessing failed: %v", err)
@@ -115,6 +118,16 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 		orchestratorFrontmatterLog.Printf("Template region validation failed: %v", err)
 		return nil, fmt.Errorf("template region validation failed: %w", err)
 	}
+	if err := validateNoCompileTimeTemplates(result.Markdown); err != nil {
+		orchestratorFrontmatterLog.Printf("Template validation failed: %v", err)
+		return nil, fmt.Errorf("template validation failed: %w", err)
+	}
+
+	// Report dangerous patterns in the workflow's own markdown (imports are rejected outright).
+	// The markdown-security rules are off unless turned on in lint.yml.
+	for _, finding := range ScanMarkdownSecurity(string(content)) {
+		c.warnAtLine(MarkdownSecurityRule(finding.Category), cleanPath, finding.Line, finding.Description)
+	}
 
 	log.Printf("Frontmatter: %d chars, Markdown: %d chars", len(result.Frontmatter), len(result.Markdown))
 
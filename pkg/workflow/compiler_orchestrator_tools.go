@@ -2,11 +2,9 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 )
@@ -22,6 +20,7 @@ type toolsProcessingResult struct {
 	toolsStartupTimeout   int
 	markdownContent       string
 	importedMarkdown      string   // Only imports WITH inputs (for compile-time substitution)
+	partialFiles          []string // Partial files included by the rendered imported markdown
 	importPaths           []string // Import paths for runtime-import macro generation (imports without inputs)
 	mainWorkflowMarkdown  string   // main workflow markdown without imports (for runtime-import)
 	allIncludedFiles      []string
@@ -216,11 +215,9 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 
 	if !agenticEngine.SupportsToolsAllowlist() {
 		// For engines that don't support tool allowlists (like custom engine), ignore tools section and provide warnings
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Using experimental %s support (engine: %s)", agenticEngine.GetDisplayName(), agenticEngine.GetID())))
-		c.IncrementWarningCount()
+		c.warn(RuleExperimentalFeature, fmt.Sprintf("Using experimental %s support (engine: %s)", agenticEngine.GetDisplayName(), agenticEngine.GetID()))
 		if _, hasTools := result.Frontmatter["tools"]; hasTools {
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("'tools' section ignored when using engine: %s (%s doesn't support MCP tool allow-listing)", agenticEngine.GetID(), agenticEngine.GetDisplayName())))
-			c.IncrementWarningCount()
+			c.warn(RuleToolsIgnored, fmt.Sprintf("'tools' section ignored when using engine: %s (%s doesn't support MCP tool allow-listing)", agenticEngine.GetID(), agenticEngine.GetDisplayName()))
 		}
 		tools = map[string]any{}
 		// For now, we'll add a basic github tool (always uses docker MCP)
@@ -256,9 +253,13 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 	// Handle imported markdown from frontmatter imports field
 	// Only imports WITH inputs will have markdown content (for compile-time substitution)
 	var importedMarkdown string
+	var partialFiles []string
 	if importsResult.MergedMarkdown != "" {
-		importedMarkdown = importsResult.MergedMarkdown
-		markdownContent = importsResult.MergedMarkdown + markdownContent
+		importedMarkdown, partialFiles, err = renderSnippetTemplates(importsResult.MergedMarkdown, importsResult.TemplateInputs, markdownDir, c.getSharedImportCache())
+		if err != nil {
+			return nil, fmt.Errorf("failed to render imported markdown: %w", err)
+		}
+		markdownContent = importedMarkdown + markdownContent
 		orchestratorToolsLog.Printf("Stored imported markdown with inputs: %d bytes, combined markdown: %d bytes", len(importedMarkdown), len(markdownContent))
 	} else {
 		orchestratorToolsLog.Print("No imported markdown with inputs")
@@ -322,6 +323,7 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 		markdownContent:       markdownContent,
 		importedMarkdown:      importedMarkdown, // Only imports WITH inputs
 		importPaths:           importPaths,      // Import paths for runtime-import macros (imports without inputs)
+		partialFiles:          partialFiles,
 		mainWorkflowMarkdown:  mainWorkflowMarkdown,
 		allIncludedFiles:      allIncludedFiles,
 		workflowName:          workflowName,
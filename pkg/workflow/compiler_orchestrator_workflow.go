@@ -3,6 +3,7 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
@@ -17,6 +18,9 @@ var orchestratorWorkflowLog = logger.New("workflow:compiler_orchestrator_workflo
 func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error) {
 	orchestratorWorkflowLog.Printf("Starting workflow file parsing: %s", markdownPath)
 
+	// Track the rule warnings of this workflow
+	c.beginWorkflowDiagnostics(markdownPath)
+
 	// Parse frontmatter section
 	parseResult, err := c.parseFrontmatterSection(markdownPath)
 	if err != nil {
@@ -62,6 +66,8 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 	workflowData.ActionCache = actionCache
 	workflowData.ActionResolver = actionResolver
 	workflowData.ActionPinWarnings = c.actionPinWarnings
+	workflowData.DiagnosticWriter = c.GetDiagnosticWriter()
+	workflowData.Diagnostics = c.diagnostics
 
 	// Extract YAML configuration sections from frontmatter
 	c.extractYAMLSections(result.Frontmatter, workflowData)
@@ -130,6 +136,7 @@ func (c *Compiler) buildInitialWorkflowData(
 		Source:                c.extractSource(result.Frontmatter),
 		TrackerID:             toolsResult.trackerID,
 		ImportedFiles:         importsResult.ImportedFiles,
+		ResolvedImportFiles:   slices.Concat(importsResult.ResolvedFiles, toolsResult.partialFiles),
 		ImportedMarkdown:      toolsResult.importedMarkdown, // Only imports WITH inputs
 		ImportPaths:           toolsResult.importPaths,      // Import paths for runtime-import macros (imports without inputs)
 		MainWorkflowMarkdown:  toolsResult.mainWorkflowMarkdown,
@@ -437,6 +444,7 @@ func (c *Compiler) extractAdditionalConfigurations(
 	workflowData.Roles = c.extractRoles(frontmatter)
 	workflowData.Bots = c.extractBots(frontmatter)
 	workflowData.RateLimit = c.extractRateLimitConfig(frontmatter)
+	workflowData.PromptLimit = c.extractPromptLimitConfig(frontmatter)
 	workflowData.SkipRoles = c.mergeSkipRoles(c.extractSkipRoles(frontmatter), importsResult.MergedSkipRoles)
 	workflowData.SkipBots = c.mergeSkipBots(c.extractSkipBots(frontmatter), importsResult.MergedSkipBots)
 
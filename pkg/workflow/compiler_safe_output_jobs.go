@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
@@ -39,11 +40,23 @@ func (c *Compiler) buildSafeOutputsJobs(data *WorkflowData, jobName, markdownPat
 	// Track safe output job names to establish dependencies for conclusion job
 	var safeOutputJobNames []string
 
+	// Safe outputs with require-approval are applied by separate jobs bound to their environment,
+	// so they are left out of the consolidated job
+	approvalGates := safeOutputApprovalGates(data.SafeOutputs)
+
 	// Build consolidated safe outputs job containing all safe output operations as steps
-	consolidatedJob, consolidatedStepNames, err := c.buildConsolidatedSafeOutputsJob(data, jobName, markdownPath)
+	consolidatedJob, consolidatedStepNames, err := c.buildConsolidatedSafeOutputsJob(withoutApprovalGatedOutputs(data, approvalGates), jobName, markdownPath)
 	if err != nil {
 		return fmt.Errorf("failed to build consolidated safe outputs job: %w", err)
 	}
+
+	// The handler manager in the consolidated job previews the gated safe outputs for reviewers
+	var approvalPreviewJob *Job
+	if len(approvalGates) > 0 && consolidatedJob != nil && slices.Contains(consolidatedStepNames, "process_safe_outputs") {
+		addApprovalPreviewToSafeOutputsJob(consolidatedJob, approvalGates)
+		approvalPreviewJob = consolidatedJob
+	}
+
 	if consolidatedJob != nil {
 		if err := c.jobManager.AddJob(consolidatedJob); err != nil {
 			return fmt.Errorf("failed to add consolidated safe outputs job: %w", err)
@@ -52,6 +65,21 @@ func (c *Compiler) buildSafeOutputsJobs(data *WorkflowData, jobName, markdownPat
 		compilerSafeOutputJobsLog.Printf("Added consolidated safe outputs job with %d steps: %v", len(consolidatedStepNames), consolidatedStepNames)
 	}
 
+	// Build one approval job per environment for the safe outputs that require approval.
+	// These are not conclusion dependencies so the conclusion does not wait for reviewers.
+	if len(approvalGates) > 0 {
+		approvalJobs, err := c.buildApprovalSafeOutputsJobs(data, approvalGates, jobName, markdownPath, approvalPreviewJob)
+		if err != nil {
+			return err
+		}
+		for _, approvalJob := range approvalJobs {
+			if err := c.jobManager.AddJob(approvalJob); err != nil {
+				return fmt.Errorf("failed to add approval job: %w", err)
+			}
+			compilerSafeOutputJobsLog.Printf("Added approval job: %s", approvalJob.Name)
+		}
+	}
+
 	// Build safe-jobs if configured
 	// Safe-jobs should depend on agent job (always) AND detection job (if threat detection is enabled)
 	// These custom safe-jobs should also be included in the conclusion job's dependencies
@@ -238,6 +238,19 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddStringSlice("allowed_repos", c.AllowedRepos).
 			Build()
 	},
+	"manage_labels": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.ManageLabels == nil {
+			return nil
+		}
+		c := cfg.ManageLabels
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddStringSlice("allowed_prefixes", c.AllowedPrefixes).
+			AddIfTrue("allow_delete", c.AllowDelete).
+			AddIfNotEmpty("target-repo", c.TargetRepoSlug).
+			AddStringSlice("allowed_repos", c.AllowedRepos).
+			Build()
+	},
 	"update_issue": func(cfg *SafeOutputsConfig) map[string]any {
 		if cfg.UpdateIssues == nil {
 			return nil
@@ -311,6 +324,44 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddBoolPtr("footer", getEffectiveFooter(c.Footer, cfg.Footer)).
 			Build()
 	},
+	"create_release": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.CreateRelease == nil {
+			return nil
+		}
+		c := cfg.CreateRelease
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddStringSlice("allowed_tags", c.AllowedTags).
+			AddDefault("draft", c.forcesDraft()).
+			AddStringSlice("allowed_exts", c.AllowedExts).
+			AddIfPositive("max_size_kb", c.MaxSizeKB).
+			AddBoolPtr("footer", getEffectiveFooter(c.Footer, cfg.Footer)).
+			Build()
+	},
+	"create_tag": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.CreateTag == nil {
+			return nil
+		}
+		c := cfg.CreateTag
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddStringSlice("allowed_tags", c.AllowedTags).
+			Build()
+	},
+	"update_file": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.UpdateFile == nil {
+			return nil
+		}
+		c := cfg.UpdateFile
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddStringSlice("allowed_paths", c.AllowedPaths).
+			AddIfNotEmpty("branch", c.Branch).
+			AddStringSlice("allowed_branches", c.AllowedBranches).
+			AddIfPositive("max_size_kb", c.MaxSizeKB).
+			AddIfNotEmpty("commit_message", c.CommitMessage).
+			Build()
+	},
 	"create_pull_request_review_comment": func(cfg *SafeOutputsConfig) map[string]any {
 		if cfg.CreatePullRequestReviewComments == nil {
 			return nil
@@ -436,6 +487,25 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddStringSlice("allowed_repos", c.AllowedRepos).
 			Build()
 	},
+	"merge_pull_request": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.MergePullRequest == nil {
+			return nil
+		}
+		c := cfg.MergePullRequest
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddIfNotEmpty("target", c.Target).
+			AddIfNotEmpty("merge_method", c.MergeMethod).
+			AddBoolPtrOrDefault("require_checks", c.RequireChecks, true).
+			AddIfPositive("required_approvals", c.RequiredApprovals).
+			AddStringSlice("required_labels", c.RequiredLabels).
+			AddIfNotEmpty("required_title_prefix", c.RequiredTitlePrefix).
+			AddStringSlice("allowed_authors", c.AllowedAuthors).
+			AddIfTrue("require_tracker_id", c.RequireTrackerID).
+			AddIfNotEmpty("target-repo", c.TargetRepoSlug).
+			AddStringSlice("allowed_repos", c.AllowedRepos).
+			Build()
+	},
 	"hide_comment": func(cfg *SafeOutputsConfig) map[string]any {
 		if cfg.HideComment == nil {
 			return nil
@@ -448,6 +518,50 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddStringSlice("allowed_repos", c.AllowedRepos).
 			Build()
 	},
+	"lock_conversation": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.LockConversation == nil {
+			return nil
+		}
+		c := cfg.LockConversation
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddIfNotEmpty("target", c.Target).
+			AddStringSlice("required_labels", c.RequiredLabels).
+			AddIfNotEmpty("required_title_prefix", c.RequiredTitlePrefix).
+			AddStringSlice("allowed_reasons", c.AllowedReasons).
+			AddIfNotEmpty("target-repo", c.TargetRepoSlug).
+			AddStringSlice("allowed_repos", c.AllowedRepos).
+			Build()
+	},
+	"pin_issue": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.PinIssue == nil {
+			return nil
+		}
+		c := cfg.PinIssue
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddIfNotEmpty("target", c.Target).
+			AddStringSlice("required_labels", c.RequiredLabels).
+			AddIfNotEmpty("required_title_prefix", c.RequiredTitlePrefix).
+			AddIfNotEmpty("target-repo", c.TargetRepoSlug).
+			AddStringSlice("allowed_repos", c.AllowedRepos).
+			Build()
+	},
+	"transfer_issue": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.TransferIssue == nil {
+			return nil
+		}
+		c := cfg.TransferIssue
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddIfNotEmpty("target", c.Target).
+			AddStringSlice("required_labels", c.RequiredLabels).
+			AddIfNotEmpty("required_title_prefix", c.RequiredTitlePrefix).
+			AddStringSlice("allowed_destinations", c.AllowedDestinations).
+			AddIfNotEmpty("target-repo", c.TargetRepoSlug).
+			AddStringSlice("allowed_repos", c.AllowedRepos).
+			Build()
+	},
 	"dispatch_workflow": func(cfg *SafeOutputsConfig) map[string]any {
 		if cfg.DispatchWorkflow == nil {
 			return nil
@@ -462,6 +576,12 @@ var handlerRegistry = map[string]handlerBuilder{
 			builder.AddDefault("workflow_files", c.WorkflowFiles)
 		}
 
+		// Add cross-repository targets and their allowlist
+		if targets := dispatchWorkflowTargetsConfig(c); targets != nil {
+			builder.AddDefault("targets", targets)
+		}
+		builder.AddStringSlice("allowed_repos", c.AllowedRepos)
+
 		return builder.Build()
 	},
 	"missing_tool": func(cfg *SafeOutputsConfig) map[string]any {
@@ -485,6 +605,30 @@ var handlerRegistry = map[string]handlerBuilder{
 	// Note: "noop" is intentionally NOT included here because it is always processed
 	// by a dedicated standalone step (see notify_comment.go buildConclusionJob).
 	// Adding it to the handler manager would create duplicate configuration overhead.
+	"create_check_run": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.CreateCheckRun == nil {
+			return nil
+		}
+		c := cfg.CreateCheckRun
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddIfNotEmpty("name", c.Name).
+			AddIfPositive("max_annotations", c.MaxAnnotations).
+			AddStringSlice("allowed_conclusions", c.effectiveCheckRunConclusions()).
+			Build()
+	},
+	"create_commit_status": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.CreateCommitStatus == nil {
+			return nil
+		}
+		c := cfg.CreateCommitStatus
+		return newHandlerConfigBuilder().
+			AddIfPositive("max", c.Max).
+			AddIfNotEmpty("context", c.Context).
+			AddIfNotEmpty("target_url", c.TargetURL).
+			AddStringSlice("allowed_states", c.effectiveCommitStatusStates()).
+			Build()
+	},
 	"autofix_code_scanning_alert": func(cfg *SafeOutputsConfig) map[string]any {
 		if cfg.AutofixCodeScanningAlert == nil {
 			return nil
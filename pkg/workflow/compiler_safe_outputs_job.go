@@ -57,19 +57,9 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 	// Add artifact download steps after setup
 	steps = append(steps, buildAgentOutputDownloadSteps()...)
 
-	// Add patch artifact download if create-pull-request or push-to-pull-request-branch is enabled
-	// Both of these safe outputs require the patch file to apply changes
-	// Download from unified agent-artifacts artifact
-	if data.SafeOutputs.CreatePullRequests != nil || data.SafeOutputs.PushToPullRequestBranch != nil {
-		consolidatedSafeOutputsJobLog.Print("Adding patch artifact download for create-pull-request or push-to-pull-request-branch")
-		patchDownloadSteps := buildArtifactDownloadSteps(ArtifactDownloadConfig{
-			ArtifactName: "agent-artifacts",
-			DownloadPath: "/tmp/gh-aw/",
-			SetupEnvStep: false, // No environment variable needed, the script checks the file directly
-			StepName:     "Download patch artifact",
-		})
-		steps = append(steps, patchDownloadSteps...)
-	}
+	// Add patch artifact download if create-pull-request or push-to-pull-request-branch is enabled,
+	// and release assets download if create-release is enabled
+	steps = append(steps, buildAgentArtifactsDownloadSteps(data)...)
 
 	// Add shared checkout and git config steps for PR operations
 	// Both create-pull-request and push-to-pull-request-branch need these steps,
@@ -103,10 +93,14 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 		data.SafeOutputs.CloseDiscussions != nil ||
 		data.SafeOutputs.AddLabels != nil ||
 		data.SafeOutputs.RemoveLabels != nil ||
+		data.SafeOutputs.ManageLabels != nil ||
 		data.SafeOutputs.UpdateIssues != nil ||
 		data.SafeOutputs.UpdateDiscussions != nil ||
 		data.SafeOutputs.LinkSubIssue != nil ||
 		data.SafeOutputs.UpdateRelease != nil ||
+		data.SafeOutputs.CreateRelease != nil ||
+		data.SafeOutputs.CreateTag != nil ||
+		data.SafeOutputs.UpdateFile != nil ||
 		data.SafeOutputs.CreatePullRequestReviewComments != nil ||
 		data.SafeOutputs.SubmitPullRequestReview != nil ||
 		data.SafeOutputs.ReplyToPullRequestReviewComment != nil ||
@@ -115,10 +109,16 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 		data.SafeOutputs.PushToPullRequestBranch != nil ||
 		data.SafeOutputs.UpdatePullRequests != nil ||
 		data.SafeOutputs.ClosePullRequests != nil ||
+		data.SafeOutputs.MergePullRequest != nil ||
 		data.SafeOutputs.MarkPullRequestAsReadyForReview != nil ||
 		data.SafeOutputs.HideComment != nil ||
+		data.SafeOutputs.LockConversation != nil ||
+		data.SafeOutputs.PinIssue != nil ||
+		data.SafeOutputs.TransferIssue != nil ||
 		data.SafeOutputs.DispatchWorkflow != nil ||
 		data.SafeOutputs.CreateCodeScanningAlerts != nil ||
+		data.SafeOutputs.CreateCheckRun != nil ||
+		data.SafeOutputs.CreateCommitStatus != nil ||
 		data.SafeOutputs.AutofixCodeScanningAlert != nil ||
 		data.SafeOutputs.MissingTool != nil ||
 		data.SafeOutputs.MissingData != nil
@@ -259,17 +259,8 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 		// Add artifact download steps count
 		insertIndex += len(buildAgentOutputDownloadSteps())
 
-		// Add patch download steps if present
-		// Download from unified agent-artifacts artifact
-		if data.SafeOutputs.CreatePullRequests != nil || data.SafeOutputs.PushToPullRequestBranch != nil {
-			patchDownloadSteps := buildArtifactDownloadSteps(ArtifactDownloadConfig{
-				ArtifactName: "agent-artifacts",
-				DownloadPath: "/tmp/gh-aw/",
-				SetupEnvStep: false,
-				StepName:     "Download patch artifact",
-			})
-			insertIndex += len(patchDownloadSteps)
-		}
+		// Add patch and release assets download steps if present
+		insertIndex += len(buildAgentArtifactsDownloadSteps(data))
 
 		// Insert app token steps
 		var newSteps []string
@@ -315,6 +306,13 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 		consolidatedSafeOutputsJobLog.Print("Added unlock job dependency to safe_outputs job")
 	}
 
+	// Wait for safe-jobs that declare outputs so messages can reference them with {{ safe_jobs.<job>.<output> }}.
+	// The job condition uses !cancelled(), so safe-jobs that were skipped do not block it.
+	if outputJobs := safeJobsWithOutputs(data.SafeOutputs.Jobs); len(outputJobs) > 0 {
+		needs = append(needs, outputJobs...)
+		consolidatedSafeOutputsJobLog.Printf("Added safe-job output dependencies to safe_outputs job: %v", outputJobs)
+	}
+
 	// Extract workflow ID from markdown path for GH_AW_WORKFLOW_ID
 	workflowID := GetWorkflowIDFromPath(markdownPath)
 
@@ -375,6 +373,11 @@ func (c *Compiler) buildJobLevelSafeOutputEnvVars(data *WorkflowData, workflowID
 		}
 	}
 
+	// Add outputs of safe-jobs referenced with {{ safe_jobs.<job>.<output> }}
+	if data.SafeOutputs != nil {
+		addSafeJobOutputEnvVars(envVars, data.SafeOutputs.Jobs)
+	}
+
 	// Add safe output job environment variables (staged/target repo)
 	if data.SafeOutputs != nil && (c.trialMode || data.SafeOutputs.Staged) {
 		envVars["GH_AW_SAFE_OUTPUTS_STAGED"] = "\"true\""
@@ -409,3 +412,25 @@ func buildDetectionSuccessCondition() ConditionNode {
 		BuildStringLiteral("true"),
 	)
 }
+
+// buildAgentArtifactsDownloadSteps downloads the unified agent-artifacts artifact when a safe output
+// needs files from the agent job: the patch for create-pull-request and push-to-pull-request-branch,
+// and the release assets for create-release
+func buildAgentArtifactsDownloadSteps(data *WorkflowData) []string {
+	needsPatch := data.SafeOutputs.CreatePullRequests != nil || data.SafeOutputs.PushToPullRequestBranch != nil
+	if !needsPatch && data.SafeOutputs.CreateRelease == nil {
+		return nil
+	}
+
+	stepName := "Download patch artifact"
+	if !needsPatch {
+		stepName = "Download release assets"
+	}
+	consolidatedSafeOutputsJobLog.Printf("Adding agent artifacts download: %s", stepName)
+	return buildArtifactDownloadSteps(ArtifactDownloadConfig{
+		ArtifactName: "agent-artifacts",
+		DownloadPath: "/tmp/gh-aw/",
+		SetupEnvStep: false, // No environment variable needed, the scripts check the files directly
+		StepName:     stepName,
+	})
+}
@@ -190,6 +190,15 @@ func (c *Compiler) buildHandlerManagerStep(data *WorkflowData) []string {
 	// Add handler manager config as JSON
 	c.addHandlerManagerConfigEnvVar(&steps, data)
 
+	// Add the cross-item reference policy for the handler manager
+	c.addSafeOutputReferencesEnvVar(&steps, data)
+
+	// Add the content policy enforced on safe output text
+	c.addSafeOutputContentPolicyEnvVar(&steps, data)
+
+	// Add tokens for dispatching workflows in other repositories
+	c.addDispatchWorkflowTokenEnvVars(&steps, data)
+
 	// Add all safe output configuration env vars (still needed by individual handlers)
 	c.addAllSafeOutputConfigEnvVars(&steps, data)
 
@@ -1,7 +1,9 @@
 package workflow
 
 import (
+	"io"
 	"os"
+	"sync"
 
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
@@ -102,35 +104,43 @@ type Compiler struct {
 	verbose                 bool
 	quiet                   bool // If true, suppress success messages (for interactive mode)
 	engineOverride          string
-	customOutput            string              // If set, output will be written to this path instead of default location
-	version                 string              // Version of the extension
-	skipValidation          bool                // If true, skip schema validation
-	noEmit                  bool                // If true, validate without generating lock files
-	strictMode              bool                // If true, enforce strict validation requirements
-	trialMode               bool                // If true, suppress safe outputs for trial mode execution
-	trialLogicalRepoSlug    string              // If set in trial mode, the logical repository to checkout
-	refreshStopTime         bool                // If true, regenerate stop-after times instead of preserving existing ones
-	forceRefreshActionPins  bool                // If true, clear action cache and resolve all actions from GitHub API
-	failFast                bool                // If true, stop at first validation error instead of collecting all errors
-	actionCacheCleared      bool                // Tracks if action cache has already been cleared (for forceRefreshActionPins)
-	markdownPath            string              // Path to the markdown file being compiled (for context in dynamic tool generation)
-	actionMode              ActionMode          // Mode for generating JavaScript steps (inline vs custom actions)
-	actionTag               string              // Override action SHA or tag for actions/setup (when set, overrides actionMode to release)
-	jobManager              *JobManager         // Manages jobs and dependencies
-	engineRegistry          *EngineRegistry     // Registry of available agentic engines
-	fileTracker             FileTracker         // Optional file tracker for tracking created files
-	warningCount            int                 // Number of warnings encountered during compilation
-	stepOrderTracker        *StepOrderTracker   // Tracks step ordering for validation
-	actionCache             *ActionCache        // Shared cache for action pin resolutions across all workflows
-	actionResolver          *ActionResolver     // Shared resolver for action pins across all workflows
-	actionPinWarnings       map[string]bool     // Shared cache of already-warned action pin failures (key: "repo@version")
-	importCache             *parser.ImportCache // Shared cache for imported workflow files
-	workflowIdentifier      string              // Identifier for the current workflow being compiled (for schedule scattering)
-	scheduleWarnings        []string            // Accumulated schedule warnings for this compiler instance
-	repositorySlug          string              // Repository slug (owner/repo) used as seed for scattering
-	artifactManager         *ArtifactManager    // Tracks artifact uploads/downloads for validation
-	scheduleFriendlyFormats map[int]string      // Maps schedule item index to friendly format string for current workflow
-	gitRoot                 string              // Git repository root directory (if set, used for action cache path)
+	customOutput            string               // If set, output will be written to this path instead of default location
+	version                 string               // Version of the extension
+	skipValidation          bool                 // If true, skip schema validation
+	noEmit                  bool                 // If true, validate without generating lock files
+	strictMode              bool                 // If true, enforce strict validation requirements
+	trialMode               bool                 // If true, suppress safe outputs for trial mode execution
+	trialLogicalRepoSlug    string               // If set in trial mode, the logical repository to checkout
+	refreshStopTime         bool                 // If true, regenerate stop-after times instead of preserving existing ones
+	forceRefreshActionPins  bool                 // If true, clear action cache and resolve all actions from GitHub API
+	failFast                bool                 // If true, stop at first validation error instead of collecting all errors
+	actionCacheCleared      bool                 // Tracks if action cache has already been cleared (for forceRefreshActionPins)
+	markdownPath            string               // Path to the markdown file being compiled (for context in dynamic tool generation)
+	actionMode              ActionMode           // Mode for generating JavaScript steps (inline vs custom actions)
+	actionTag               string               // Override action SHA or tag for actions/setup (when set, overrides actionMode to release)
+	jobManager              *JobManager          // Manages jobs and dependencies
+	engineRegistry          *EngineRegistry      // Registry of available agentic engines
+	fileTracker             FileTracker          // Optional file tracker for tracking created files
+	warningCount            int                  // Number of warnings encountered during compilation
+	stepOrderTracker        *StepOrderTracker    // Tracks step ordering for validation
+	actionCache             *ActionCache         // Shared cache for action pin resolutions across all workflows
+	actionResolver          *ActionResolver      // Shared resolver for action pins across all workflows
+	actionPinWarnings       map[string]bool      // Shared cache of already-warned action pin failures (key: "repo@version")
+	importCache             *parser.ImportCache  // Shared cache for imported workflow files
+	workflowIdentifier      string               // Identifier for the current workflow being compiled (for schedule scattering)
+	scheduleWarnings        []string             // Accumulated schedule warnings for this compiler instance
+	repositorySlug          string               // Repository slug (owner/repo) used as seed for scattering
+	artifactManager         *ArtifactManager     // Tracks artifact uploads/downloads for validation
+	scheduleFriendlyFormats map[int]string       // Maps schedule item index to friendly format string for current workflow
+	gitRoot                 string               // Git repository root directory (if set, used for action cache path)
+	lastLockFileContent     string               // Lock file content generated by the most recent compilation (also set with noEmit)
+	diagnosticWriter        io.Writer            // Destination for warnings and progress messages (defaults to os.Stderr)
+	lintConfig              *LintConfig          // Rule severities from .github/aw/lint.yml (nil keeps the defaults)
+	diagnostics             *WorkflowDiagnostics // Rule warnings of the workflow being compiled
+
+	// remoteDispatchWorkflows caches the workflows fetched from other repositories for
+	// dispatch-workflow (key: "owner/repo/workflow@ref"), shared with forks
+	remoteDispatchWorkflows *sync.Map
 }
 
 // NewCompiler creates a new workflow compiler with functional options.
@@ -248,6 +258,57 @@ func (c *Compiler) GetVersion() string {
 	return c.version
 }
 
+// GetLastLockFileContent returns the lock file content generated by the most recent
+// successful compilation, including when lock files are not written (noEmit)
+func (c *Compiler) GetLastLockFileContent() string {
+	return c.lastLockFileContent
+}
+
+// SetDiagnosticWriter redirects the warnings and progress messages printed while compiling.
+// A nil writer restores the default, os.Stderr.
+func (c *Compiler) SetDiagnosticWriter(w io.Writer) {
+	c.diagnosticWriter = w
+}
+
+// GetDiagnosticWriter returns the destination for warnings and progress messages
+func (c *Compiler) GetDiagnosticWriter() io.Writer {
+	if c.diagnosticWriter == nil {
+		return os.Stderr
+	}
+	return c.diagnosticWriter
+}
+
+// Fork returns a compiler with the same configuration that shares this compiler's action
+// cache, action resolver and import cache. Per-workflow state (warning count, schedule
+// warnings, jobs, workflow identifier) is independent, so forks can compile workflows
+// concurrently with each other.
+func (c *Compiler) Fork() *Compiler {
+	// Initialize the shared caches before copying so that all forks use the same instances
+	c.getSharedActionResolver()
+	c.getSharedImportCache()
+	c.getRemoteDispatchWorkflowCache()
+
+	fork := *c
+	fork.jobManager = NewJobManager()
+	fork.stepOrderTracker = NewStepOrderTracker()
+	fork.artifactManager = NewArtifactManager()
+	fork.actionPinWarnings = make(map[string]bool)
+	fork.warningCount = 0
+	fork.scheduleWarnings = nil
+	fork.scheduleFriendlyFormats = nil
+	fork.markdownPath = ""
+	fork.lastLockFileContent = ""
+	fork.diagnostics = nil
+	return &fork
+}
+
+// MergeForkDiagnostics adds the warning count and schedule warnings of a forked compiler
+// to this compiler's totals
+func (c *Compiler) MergeForkDiagnostics(fork *Compiler) {
+	c.warningCount += fork.warningCount
+	c.scheduleWarnings = append(c.scheduleWarnings, fork.scheduleWarnings...)
+}
+
 // IncrementWarningCount increments the warning counter
 func (c *Compiler) IncrementWarningCount() {
 	c.warningCount++
@@ -346,6 +407,15 @@ func (c *Compiler) getSharedImportCache() *parser.ImportCache {
 	return c.importCache
 }
 
+// getRemoteDispatchWorkflowCache returns the cache of workflows fetched from other repositories,
+// creating it on first use
+func (c *Compiler) getRemoteDispatchWorkflowCache() *sync.Map {
+	if c.remoteDispatchWorkflows == nil {
+		c.remoteDispatchWorkflows = &sync.Map{}
+	}
+	return c.remoteDispatchWorkflows
+}
+
 // GetSharedActionCache returns the shared action cache used by this compiler instance.
 // The cache is lazily initialized on first access and shared across all workflows.
 // This allows action SHA validation and other operations to reuse cached resolutions.
@@ -386,6 +456,7 @@ type WorkflowData struct {
 	Source                string         // optional source field (owner/repo@ref/path) rendered as comment in lock file
 	TrackerID             string         // optional tracker identifier for created assets (min 8 chars, alphanumeric + hyphens/underscores)
 	ImportedFiles         []string       // list of files imported via imports field (rendered as comment in lock file)
+	ResolvedImportFiles   []string       // resolved paths of all imported files (used for incremental compilation)
 	ImportedMarkdown      string         // Only imports WITH inputs (for compile-time substitution)
 	ImportPaths           []string       // Import file paths for runtime-import macro generation (imports without inputs)
 	MainWorkflowMarkdown  string         // main workflow markdown without imports (for runtime-import)
@@ -435,6 +506,7 @@ type WorkflowData struct {
 	Roles                 []string             // permission levels required to trigger workflow
 	Bots                  []string             // allow list of bot identifiers that can trigger workflow
 	RateLimit             *RateLimitConfig     // rate limiting configuration for workflow triggers
+	PromptLimit           *PromptLimitConfig   // prompt size limit and summarization and truncation orders
 	CacheMemoryConfig     *CacheMemoryConfig   // parsed cache-memory configuration
 	RepoMemoryConfig      *RepoMemoryConfig    // parsed repo-memory configuration
 	Runtimes              map[string]any       // runtime version overrides from frontmatter
@@ -449,15 +521,18 @@ type WorkflowData struct {
 	SecretMasking         *SecretMaskingConfig // secret masking configuration
 	ParsedFrontmatter     *FrontmatterConfig   // cached parsed frontmatter configuration (for performance optimization)
 	ActionPinWarnings     map[string]bool      // cache of already-warned action pin failures (key: "repo@version")
+	DiagnosticWriter      io.Writer            // destination for warnings printed while generating steps (the compiler's diagnostic writer)
+	Diagnostics           *WorkflowDiagnostics // rule warnings of the workflow, to which action pin warnings are reported
 	ActionMode            ActionMode           // action mode for workflow compilation (dev, release, script)
 	HasExplicitGitHubTool bool                 // true if tools.github was explicitly configured in frontmatter
 }
 
 // BaseSafeOutputConfig holds common configuration fields for all safe output types
 type BaseSafeOutputConfig struct {
-	Max         int    `yaml:"max,omitempty"`          // Maximum number of items to create
-	GitHubToken string `yaml:"github-token,omitempty"` // GitHub token for this specific output type
-	Staged      bool   `yaml:"staged,omitempty"`       // If true, emit step summary messages instead of making GitHub API calls for this specific output type
+	Max             int    `yaml:"max,omitempty"`              // Maximum number of items to create
+	GitHubToken     string `yaml:"github-token,omitempty"`     // GitHub token for this specific output type
+	Staged          bool   `yaml:"staged,omitempty"`           // If true, emit step summary messages instead of making GitHub API calls for this specific output type
+	RequireApproval string `yaml:"require-approval,omitempty"` // GitHub Environment whose reviewers must approve before this output type is applied
 }
 
 // SafeOutputsConfig holds configuration for automatic output routes
@@ -469,6 +544,7 @@ type SafeOutputsConfig struct {
 	CloseIssues                     *CloseIssuesConfig                     `yaml:"close-issue,omitempty"`
 	ClosePullRequests               *ClosePullRequestsConfig               `yaml:"close-pull-request,omitempty"`
 	MarkPullRequestAsReadyForReview *MarkPullRequestAsReadyForReviewConfig `yaml:"mark-pull-request-as-ready-for-review,omitempty"`
+	MergePullRequest                *MergePullRequestConfig                `yaml:"merge-pull-request,omitempty"` // Merge pull requests that pass the configured policy gates
 	AddComments                     *AddCommentsConfig                     `yaml:"add-comments,omitempty"`
 	CreatePullRequests              *CreatePullRequestsConfig              `yaml:"create-pull-requests,omitempty"`
 	CreatePullRequestReviewComments *CreatePullRequestReviewCommentsConfig `yaml:"create-pull-request-review-comments,omitempty"`
@@ -476,9 +552,12 @@ type SafeOutputsConfig struct {
 	ReplyToPullRequestReviewComment *ReplyToPullRequestReviewCommentConfig `yaml:"reply-to-pull-request-review-comment,omitempty"` // Reply to existing review comments on PRs
 	ResolvePullRequestReviewThread  *ResolvePullRequestReviewThreadConfig  `yaml:"resolve-pull-request-review-thread,omitempty"`   // Resolve a review thread on a pull request
 	CreateCodeScanningAlerts        *CreateCodeScanningAlertsConfig        `yaml:"create-code-scanning-alerts,omitempty"`
+	CreateCheckRun                  *CreateCheckRunConfig                  `yaml:"create-check-run,omitempty"`     // Publish a check run with annotations on the PR head SHA
+	CreateCommitStatus              *CreateCommitStatusConfig              `yaml:"create-commit-status,omitempty"` // Set a commit status with a fixed context on the triggering commit
 	AutofixCodeScanningAlert        *AutofixCodeScanningAlertConfig        `yaml:"autofix-code-scanning-alert,omitempty"`
 	AddLabels                       *AddLabelsConfig                       `yaml:"add-labels,omitempty"`
 	RemoveLabels                    *RemoveLabelsConfig                    `yaml:"remove-labels,omitempty"`
+	ManageLabels                    *ManageLabelsConfig                    `yaml:"manage-labels,omitempty"` // Create, edit and delete repository labels within allowed prefixes
 	AddReviewer                     *AddReviewerConfig                     `yaml:"add-reviewer,omitempty"`
 	AssignMilestone                 *AssignMilestoneConfig                 `yaml:"assign-milestone,omitempty"`
 	AssignToAgent                   *AssignToAgentConfig                   `yaml:"assign-to-agent,omitempty"`
@@ -489,12 +568,18 @@ type SafeOutputsConfig struct {
 	PushToPullRequestBranch         *PushToPullRequestBranchConfig         `yaml:"push-to-pull-request-branch,omitempty"`
 	UploadAssets                    *UploadAssetsConfig                    `yaml:"upload-asset,omitempty"`
 	UpdateRelease                   *UpdateReleaseConfig                   `yaml:"update-release,omitempty"`               // Update GitHub release descriptions
+	CreateRelease                   *CreateReleaseConfig                   `yaml:"create-release,omitempty"`               // Create GitHub releases with notes and assets
+	CreateTag                       *CreateTagConfig                       `yaml:"create-tag,omitempty"`                   // Create git tags
+	UpdateFile                      *UpdateFileConfig                      `yaml:"update-file,omitempty"`                  // Commit single files through the contents API
 	CreateAgentSessions             *CreateAgentSessionConfig              `yaml:"create-agent-session,omitempty"`         // Create GitHub Copilot agent sessions
 	UpdateProjects                  *UpdateProjectConfig                   `yaml:"update-project,omitempty"`               // Smart project board management (create/add/update)
 	CreateProjects                  *CreateProjectsConfig                  `yaml:"create-project,omitempty"`               // Create GitHub Projects V2
 	CreateProjectStatusUpdates      *CreateProjectStatusUpdateConfig       `yaml:"create-project-status-update,omitempty"` // Create GitHub project status updates
 	LinkSubIssue                    *LinkSubIssueConfig                    `yaml:"link-sub-issue,omitempty"`               // Link issues as sub-issues
 	HideComment                     *HideCommentConfig                     `yaml:"hide-comment,omitempty"`                 // Hide comments
+	LockConversation                *LockConversationConfig                `yaml:"lock-conversation,omitempty"`            // Lock issue and pull request conversations
+	PinIssue                        *PinIssueConfig                        `yaml:"pin-issue,omitempty"`                    // Pin issues to the repository
+	TransferIssue                   *TransferIssueConfig                   `yaml:"transfer-issue,omitempty"`               // Transfer issues to allowed repositories
 	DispatchWorkflow                *DispatchWorkflowConfig                `yaml:"dispatch-workflow,omitempty"`            // Dispatch workflow_dispatch events to other workflows
 	MissingTool                     *MissingToolConfig                     `yaml:"missing-tool,omitempty"`                 // Optional for reporting missing functionality
 	MissingData                     *MissingDataConfig                     `yaml:"missing-data,omitempty"`                 // Optional for reporting missing data required to achieve goals
@@ -512,6 +597,8 @@ type SafeOutputsConfig struct {
 	Messages                        *SafeOutputMessagesConfig              `yaml:"messages,omitempty"`                  // Custom message templates for footer and notifications
 	Mentions                        *MentionsConfig                        `yaml:"mentions,omitempty"`                  // Configuration for @mention filtering in safe outputs
 	Footer                          *bool                                  `yaml:"footer,omitempty"`                    // Global footer control - when false, omits visible footer from all safe outputs (XML markers still included)
+	References                      map[string][]string                    `yaml:"references,omitempty"`                // Allowed cross-item references: consumer type -> producer types it may reference by temporary ID
+	ContentPolicy                   *ContentPolicyConfig                   `yaml:"content-policy,omitempty"`            // Content rules enforced on the text of every safe output
 }
 
 // SafeOutputMessagesConfig holds custom message templates for safe-output footer and notification messages
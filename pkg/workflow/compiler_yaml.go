@@ -241,6 +241,39 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData) {
 	builtinSections := c.collectPromptSections(data)
 	compilerYamlLog.Printf("Collected %d built-in prompt sections", len(builtinSections))
 
+	userPromptChunks, expressionMappings := c.collectUserPromptChunks(data)
+
+	// Mark the user sections so that the prompt size limit step can summarize and truncate them
+	if data.PromptLimit != nil {
+		userPromptChunks = addPromptSectionMarkers(userPromptChunks)
+	}
+	c.generatePromptSectionNonceStep(yaml, data)
+
+	// Generate a single unified prompt creation step
+	c.generateUnifiedPromptCreationStep(yaml, builtinSections, userPromptChunks, expressionMappings, data)
+
+	// Add combined interpolation and template rendering step
+	c.generateInterpolationAndTemplateStep(yaml, expressionMappings, data)
+
+	// Validate that all placeholders have been substituted
+	yaml.WriteString("      - name: Validate prompt placeholders\n")
+	yaml.WriteString("        env:\n")
+	yaml.WriteString("          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	yaml.WriteString("        run: bash /opt/gh-aw/actions/validate_prompt_placeholders.sh\n")
+
+	// Enforce prompt.max-tokens before the prompt is printed and handed to the engine
+	c.generateEnforcePromptLimitStep(yaml, data)
+
+	// Print prompt (merged into prompt generation)
+	yaml.WriteString("      - name: Print prompt\n")
+	yaml.WriteString("        env:\n")
+	yaml.WriteString("          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	yaml.WriteString("        run: bash /opt/gh-aw/actions/print_prompt_summary.sh\n")
+}
+
+// collectUserPromptChunks collects the user prompt chunks that follow the built-in sections,
+// along with the expressions extracted from the workflow markdown for the substitution step.
+func (c *Compiler) collectUserPromptChunks(data *WorkflowData) ([]string, []*ExpressionMapping) {
 	// NEW APPROACH: Use runtime-import macros for imports without inputs
 	// - Imported markdown without inputs uses runtime-import macros (loaded at runtime)
 	// - Imported markdown with inputs is still inlined (compile-time substitution required)
@@ -347,24 +380,9 @@ func (c *Compiler) generatePrompt(yaml *strings.Builder, data *WorkflowData) {
 	// Append runtime-import macro after imported chunks
 	userPromptChunks = append(userPromptChunks, runtimeImportMacro)
 
-	// Generate a single unified prompt creation step
-	c.generateUnifiedPromptCreationStep(yaml, builtinSections, userPromptChunks, expressionMappings, data)
-
-	// Add combined interpolation and template rendering step
-	c.generateInterpolationAndTemplateStep(yaml, expressionMappings, data)
-
-	// Validate that all placeholders have been substituted
-	yaml.WriteString("      - name: Validate prompt placeholders\n")
-	yaml.WriteString("        env:\n")
-	yaml.WriteString("          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
-	yaml.WriteString("        run: bash /opt/gh-aw/actions/validate_prompt_placeholders.sh\n")
-
-	// Print prompt (merged into prompt generation)
-	yaml.WriteString("      - name: Print prompt\n")
-	yaml.WriteString("        env:\n")
-	yaml.WriteString("          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
-	yaml.WriteString("        run: bash /opt/gh-aw/actions/print_prompt_summary.sh\n")
+	return userPromptChunks, expressionMappings
 }
+
 func (c *Compiler) generatePostSteps(yaml *strings.Builder, data *WorkflowData) {
 	if data.PostSteps != "" {
 		// Remove "post-steps:" line and adjust indentation, similar to CustomSteps processing
@@ -600,6 +618,11 @@ func (c *Compiler) generateOutputCollectionStep(yaml *strings.Builder, data *Wor
 		fmt.Fprintf(yaml, "          GH_AW_ALLOWED_GITHUB_REFS: %q\n", refsStr)
 	}
 
+	// Add content policy so violations are recorded in the agent output for audit
+	if contentPolicy := contentPolicyEnvValue(data.SafeOutputs); contentPolicy != "" {
+		fmt.Fprintf(yaml, "          GH_AW_SAFE_OUTPUTS_CONTENT_POLICY: %s\n", contentPolicy)
+	}
+
 	// Add GitHub server URL and API URL for dynamic domain extraction
 	// This allows the sanitization code to permit GitHub domains that vary by deployment
 	yaml.WriteString("          GITHUB_SERVER_URL: ${{ github.server_url }}\n")
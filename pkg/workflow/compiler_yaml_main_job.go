@@ -416,6 +416,11 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 		artifactPaths = append(artifactPaths, "/tmp/gh-aw/aw.patch")
 	}
 
+	// Collect release assets copied by the create_release tool
+	if data.SafeOutputs != nil && data.SafeOutputs.CreateRelease != nil {
+		artifactPaths = append(artifactPaths, releaseAssetsDir)
+	}
+
 	// Add post-steps (if any) after AI execution
 	c.generatePostSteps(yaml, data)
 
@@ -0,0 +1,71 @@
+package workflow
+
+import (
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var createCheckRunLog = logger.New("workflow:create_check_run")
+
+// checkRunConclusions lists the conclusions the GitHub Checks API accepts for a completed check run
+var checkRunConclusions = []string{"success", "failure", "neutral", "cancelled", "skipped", "timed_out", "action_required"}
+
+// defaultCheckRunMaxAnnotations is the default number of annotations a check run may carry
+const defaultCheckRunMaxAnnotations = 50
+
+// CreateCheckRunConfig holds configuration for publishing check runs from agent output.
+// Check runs are created on the pull request head SHA with a fixed name per workflow.
+type CreateCheckRunConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	Name                 string   `yaml:"name,omitempty"`                // Check run name (default: workflow name)
+	MaxAnnotations       int      `yaml:"max-annotations,omitempty"`     // Maximum number of annotations per check run (default: 50)
+	AllowedConclusions   []string `yaml:"allowed-conclusions,omitempty"` // Conclusions the agent may report (default: all conclusions)
+}
+
+// parseCreateCheckRunConfig handles create-check-run configuration
+func (c *Compiler) parseCreateCheckRunConfig(outputMap map[string]any) *CreateCheckRunConfig {
+	configData, exists := outputMap["create-check-run"]
+	if !exists {
+		return nil
+	}
+
+	createCheckRunLog.Print("Parsing create-check-run configuration")
+	config := &CreateCheckRunConfig{MaxAnnotations: defaultCheckRunMaxAnnotations}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		if name, ok := configMap["name"].(string); ok {
+			config.Name = name
+		}
+
+		if maxAnnotations, exists := configMap["max-annotations"]; exists {
+			if maxInt, ok := parseIntValue(maxAnnotations); ok {
+				config.MaxAnnotations = maxInt
+			}
+		}
+
+		if conclusions, ok := configMap["allowed-conclusions"].([]any); ok {
+			for _, conclusion := range conclusions {
+				if conclusionStr, ok := conclusion.(string); ok {
+					config.AllowedConclusions = append(config.AllowedConclusions, conclusionStr)
+				}
+			}
+		}
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		config.Max = 1
+	}
+
+	createCheckRunLog.Printf("Parsed create-check-run config: name=%q, max=%d, max_annotations=%d, allowed_conclusions=%v",
+		config.Name, config.Max, config.MaxAnnotations, config.AllowedConclusions)
+	return config
+}
+
+// effectiveCheckRunConclusions returns the conclusions the agent may report
+func (config *CreateCheckRunConfig) effectiveCheckRunConclusions() []string {
+	if len(config.AllowedConclusions) > 0 {
+		return config.AllowedConclusions
+	}
+	return checkRunConclusions
+}
@@ -0,0 +1,97 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreateCheckRunConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   any
+		expected *CreateCheckRunConfig
+	}{
+		{
+			name:   "null config uses defaults",
+			config: nil,
+			expected: &CreateCheckRunConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				MaxAnnotations:       defaultCheckRunMaxAnnotations,
+			},
+		},
+		{
+			name: "full config",
+			config: map[string]any{
+				"name":                "AI Review",
+				"max":                 2,
+				"max-annotations":     20,
+				"allowed-conclusions": []any{"success", "neutral"},
+				"github-token":        "${{ secrets.CHECKS_TOKEN }}",
+			},
+			expected: &CreateCheckRunConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 2, GitHubToken: "${{ secrets.CHECKS_TOKEN }}"},
+				Name:                 "AI Review",
+				MaxAnnotations:       20,
+				AllowedConclusions:   []string{"success", "neutral"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewCompiler().parseCreateCheckRunConfig(map[string]any{"create-check-run": tt.config})
+			assert.Equal(t, tt.expected, config, "config should be parsed")
+		})
+	}
+
+	assert.Nil(t, NewCompiler().parseCreateCheckRunConfig(map[string]any{}), "missing config should return nil")
+}
+
+func TestCreateCheckRunCompilation(t *testing.T) {
+	markdown := `---
+on: pull_request
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  create-check-run:
+    name: AI Review
+    max-annotations: 20
+    allowed-conclusions: [success, neutral, failure]
+---
+
+# Review
+
+Review the pull request and publish a check run.
+`
+
+	tmpDir := testutil.TempDir(t, "create-check-run-*")
+	testFile := filepath.Join(tmpDir, "review.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "review.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `\"create_check_run\":{\"allowed_conclusions\":[\"success\",\"neutral\",\"failure\"],\"max\":1,\"max_annotations\":20,\"name\":\"AI Review\"}`, "handler config should include the check run options")
+	assert.Contains(t, lock, `"name": "create_check_run"`, "create_check_run tool should be generated")
+
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	assert.Contains(t, safeOutputsJob[:strings.Index(safeOutputsJob, "    steps:")], "checks: write", "safe_outputs job should have checks: write")
+
+	for _, job := range []string{"agent", "conclusion"} {
+		jobSection := lock[strings.Index(lock, "\n  "+job+":\n"):]
+		jobHeader := jobSection[:strings.Index(jobSection, "    steps:")]
+		assert.NotContains(t, jobHeader, "checks: write", "%s job should not have checks: write", job)
+	}
+}
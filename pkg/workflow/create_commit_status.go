@@ -0,0 +1,136 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var createCommitStatusLog = logger.New("workflow:create_commit_status")
+
+// commitStatusStates lists the states the GitHub commit status API accepts
+var commitStatusStates = []string{"error", "failure", "pending", "success"}
+
+// CreateCommitStatusConfig holds configuration for setting a commit status from agent output.
+// Statuses are always set on the triggering commit with a fixed context so branch protection
+// can require them.
+type CreateCommitStatusConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	Context              string   `yaml:"context,omitempty"`        // Status context name (default: workflow name)
+	AllowedStates        []string `yaml:"allowed-states,omitempty"` // States the agent may report (default: all states)
+	TargetURL            string   `yaml:"target-url,omitempty"`     // Target URL template (default: {run_url})
+	Pending              bool     `yaml:"pending,omitempty"`        // Set a pending status in the pre-activation job
+}
+
+// parseCreateCommitStatusConfig handles create-commit-status configuration
+func (c *Compiler) parseCreateCommitStatusConfig(outputMap map[string]any) *CreateCommitStatusConfig {
+	configData, exists := outputMap["create-commit-status"]
+	if !exists {
+		return nil
+	}
+
+	createCommitStatusLog.Print("Parsing create-commit-status configuration")
+	config := &CreateCommitStatusConfig{}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		if context, ok := configMap["context"].(string); ok {
+			config.Context = context
+		}
+		if targetURL, ok := configMap["target-url"].(string); ok {
+			config.TargetURL = targetURL
+		}
+		if pending, ok := configMap["pending"].(bool); ok {
+			config.Pending = pending
+		}
+		config.AllowedStates = ParseStringArrayFromConfig(configMap, "allowed-states", createCommitStatusLog)
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		config.Max = 1
+	}
+
+	createCommitStatusLog.Printf("Parsed create-commit-status config: context=%q, max=%d, allowed_states=%v, pending=%t",
+		config.Context, config.Max, config.AllowedStates, config.Pending)
+	return config
+}
+
+// validateCreateCommitStatusConfig ensures the configured states are accepted by the commit status API
+func validateCreateCommitStatusConfig(config *SafeOutputsConfig) error {
+	if config == nil || config.CreateCommitStatus == nil {
+		return nil
+	}
+
+	for _, state := range config.CreateCommitStatus.AllowedStates {
+		if !slices.Contains(commitStatusStates, state) {
+			return fmt.Errorf("create-commit-status: invalid state '%s' in allowed-states (expected one of: error, failure, pending, success)", state)
+		}
+	}
+	return nil
+}
+
+// effectiveCommitStatusStates returns the states the agent may report
+func (config *CreateCommitStatusConfig) effectiveCommitStatusStates() []string {
+	if len(config.AllowedStates) > 0 {
+		return config.AllowedStates
+	}
+	return commitStatusStates
+}
+
+// hasPendingCommitStatus reports whether the workflow sets a pending commit status before the agent runs
+func hasPendingCommitStatus(data *WorkflowData) bool {
+	return data.SafeOutputs != nil && data.SafeOutputs.CreateCommitStatus != nil && data.SafeOutputs.CreateCommitStatus.Pending
+}
+
+// buildCommitStatusEnvVars builds the environment shared by the pending and resolve steps so
+// both use the same context and target URL as the safe output handler
+func buildCommitStatusEnvVars(data *WorkflowData) []string {
+	config := data.SafeOutputs.CreateCommitStatus
+	envVars := []string{fmt.Sprintf("          GH_AW_WORKFLOW_NAME: %q\n", data.Name)}
+	if config.Context != "" {
+		envVars = append(envVars, fmt.Sprintf("          GH_AW_COMMIT_STATUS_CONTEXT: %q\n", config.Context))
+	}
+	if config.TargetURL != "" {
+		envVars = append(envVars, fmt.Sprintf("          GH_AW_COMMIT_STATUS_TARGET_URL: %q\n", config.TargetURL))
+	}
+	return envVars
+}
+
+// buildPendingCommitStatusStep generates the pre-activation step that marks the triggering commit as pending.
+// The step only runs when the other pre-activation checks pass so skipped runs never leave a pending status behind.
+func buildPendingCommitStatusStep(data *WorkflowData, condition string) []string {
+	createCommitStatusLog.Print("Adding pending commit status step to pre-activation job")
+	var steps []string
+	steps = append(steps, "      - name: Set pending commit status\n")
+	steps = append(steps, "        id: pending_commit_status\n")
+	if condition != "" {
+		steps = append(steps, fmt.Sprintf("        if: %s\n", condition))
+	}
+	steps = append(steps, fmt.Sprintf("        uses: %s\n", GetActionPin("actions/github-script")))
+	steps = append(steps, "        env:\n")
+	steps = append(steps, buildCommitStatusEnvVars(data)...)
+	steps = append(steps, "        with:\n")
+	steps = append(steps, "          github-token: ${{ secrets.GITHUB_TOKEN }}\n")
+	steps = append(steps, "          script: |\n")
+	steps = append(steps, generateGitHubScriptWithRequire("set_pending_commit_status.cjs"))
+	return steps
+}
+
+// buildResolveCommitStatusStep generates the conclusion step that replaces a pending status the
+// agent never resolved, so a failed or silent run cannot leave a required check pending forever
+func (c *Compiler) buildResolveCommitStatusStep(data *WorkflowData, mainJobName string) []string {
+	envVars := buildCommitStatusEnvVars(data)
+	envVars = append(envVars, fmt.Sprintf("          GH_AW_AGENT_CONCLUSION: ${{ needs.%s.result }}\n", mainJobName))
+
+	return c.buildGitHubScriptStepWithoutDownload(data, GitHubScriptStepConfig{
+		StepName:      "Resolve Pending Commit Status",
+		StepID:        "resolve_commit_status",
+		MainJobName:   mainJobName,
+		CustomEnvVars: envVars,
+		Script:        "const { main } = require('/opt/gh-aw/actions/resolve_commit_status.cjs'); await main();",
+		ScriptFile:    "resolve_commit_status.cjs",
+		Token:         "", // Will use default GITHUB_TOKEN
+	})
+}
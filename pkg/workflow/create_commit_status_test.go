@@ -0,0 +1,172 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreateCommitStatusConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   any
+		expected *CreateCommitStatusConfig
+	}{
+		{
+			name:   "null config uses defaults",
+			config: nil,
+			expected: &CreateCommitStatusConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+			},
+		},
+		{
+			name: "full config",
+			config: map[string]any{
+				"context":        "agentic/spec",
+				"allowed-states": []any{"success", "failure"},
+				"target-url":     "{run_url}#summary",
+				"pending":        true,
+				"max":            2,
+			},
+			expected: &CreateCommitStatusConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 2},
+				Context:              "agentic/spec",
+				AllowedStates:        []string{"success", "failure"},
+				TargetURL:            "{run_url}#summary",
+				Pending:              true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewCompiler().parseCreateCommitStatusConfig(map[string]any{"create-commit-status": tt.config})
+			assert.Equal(t, tt.expected, config, "config should be parsed")
+		})
+	}
+
+	assert.Nil(t, NewCompiler().parseCreateCommitStatusConfig(map[string]any{}), "missing config should return nil")
+}
+
+func TestValidateCreateCommitStatusConfig(t *testing.T) {
+	valid := &SafeOutputsConfig{CreateCommitStatus: &CreateCommitStatusConfig{AllowedStates: []string{"success", "error"}}}
+	require.NoError(t, validateCreateCommitStatusConfig(valid), "known states should be accepted")
+
+	invalid := &SafeOutputsConfig{CreateCommitStatus: &CreateCommitStatusConfig{AllowedStates: []string{"neutral"}}}
+	err := validateCreateCommitStatusConfig(invalid)
+	require.Error(t, err, "unknown states should be rejected")
+	assert.Contains(t, err.Error(), "invalid state 'neutral'", "error should name the invalid state")
+}
+
+func TestCreateCommitStatusCompilation(t *testing.T) {
+	tests := []struct {
+		name    string
+		pending bool
+	}{
+		{name: "without pending status", pending: false},
+		{name: "with pending status", pending: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pendingLine := ""
+			if tt.pending {
+				pendingLine = "    pending: true\n"
+			}
+			markdown := `---
+on: workflow_dispatch
+roles: all
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  create-commit-status:
+    context: agentic/changelog
+    allowed-states: [success, failure]
+` + pendingLine + `---
+
+# Changelog check
+
+Check that the change has a changelog entry.
+`
+
+			tmpDir := testutil.TempDir(t, "create-commit-status-*")
+			testFile := filepath.Join(tmpDir, "changelog.md")
+			require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+			require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+			lockContent, err := os.ReadFile(filepath.Join(tmpDir, "changelog.lock.yml"))
+			require.NoError(t, err, "should read lock file")
+			lock := string(lockContent)
+
+			assert.Contains(t, lock, `\"create_commit_status\":{\"allowed_states\":[\"success\",\"failure\"],\"context\":\"agentic/changelog\",\"max\":1}`, "handler config should include the status options")
+			assert.Contains(t, lock, `"name": "create_commit_status"`, "create_commit_status tool should be generated")
+
+			safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+			assert.Contains(t, safeOutputsJob[:strings.Index(safeOutputsJob, "    steps:")], "statuses: write", "safe_outputs job should have statuses: write")
+
+			conclusionJob := lock[strings.Index(lock, "\n  conclusion:\n"):]
+			conclusionHeader := conclusionJob[:strings.Index(conclusionJob, "    steps:")]
+
+			if !tt.pending {
+				assert.NotContains(t, lock, "\n  pre_activation:\n", "pre_activation job should not be needed")
+				assert.NotContains(t, conclusionHeader, "statuses: write", "conclusion job should not have statuses: write")
+				assert.NotContains(t, lock, "resolve_commit_status.cjs", "pending status should not be resolved")
+				return
+			}
+
+			require.Contains(t, lock, "\n  pre_activation:\n", "pre_activation job should set the pending status")
+			preActivationJob := lock[strings.Index(lock, "\n  pre_activation:\n"):]
+			preActivationJob = preActivationJob[:strings.Index(preActivationJob, "\n\n  ")]
+			assert.Contains(t, preActivationJob, "statuses: write", "pre_activation job should have statuses: write")
+			assert.Contains(t, preActivationJob, "activated: ${{ true }}", "activation should be unconditional")
+			assert.Contains(t, preActivationJob, "set_pending_commit_status.cjs", "pre_activation job should set the pending status")
+			assert.Contains(t, preActivationJob, `GH_AW_COMMIT_STATUS_CONTEXT: "agentic/changelog"`, "pending status should use the configured context")
+
+			assert.Contains(t, conclusionHeader, "statuses: write", "conclusion job should have statuses: write")
+			assert.Contains(t, conclusionJob, "resolve_commit_status.cjs", "conclusion job should resolve the pending status")
+		})
+	}
+}
+
+func TestPendingCommitStatusFollowsPreActivationChecks(t *testing.T) {
+	markdown := `---
+on:
+  pull_request:
+    types: [opened]
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  create-commit-status:
+    pending: true
+---
+
+# Spec compliance
+
+Check the pull request against the spec.
+`
+
+	tmpDir := testutil.TempDir(t, "pending-commit-status-*")
+	testFile := filepath.Join(tmpDir, "spec.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "spec.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	stepIndex := strings.Index(lock, "- name: Set pending commit status")
+	require.NotEqual(t, -1, stepIndex, "pending status step should be generated")
+	assert.Contains(t, lock[stepIndex:], "if: steps.check_membership.outputs.is_team_member == 'true'", "pending status should only be set when the run activates")
+	assert.Less(t, strings.Index(lock, "- name: Check team membership"), stepIndex, "pending status should be set after the membership check")
+}
@@ -0,0 +1,143 @@
+package workflow
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var createReleaseLog = logger.New("workflow:create_release")
+
+// releaseAssetsDir is the directory the safe outputs MCP server copies release assets to.
+// It is uploaded with the agent artifacts so threat detection can inspect the assets.
+const releaseAssetsDir = "/tmp/gh-aw/safeoutputs/release-assets/"
+
+// defaultReleaseAssetExts lists the release asset extensions allowed by default
+var defaultReleaseAssetExts = []string{".zip", ".tar.gz", ".tgz", ".txt", ".md", ".json"}
+
+// CreateReleaseConfig holds configuration for creating GitHub releases from agent output
+type CreateReleaseConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	AllowedTags          []string `yaml:"allowed-tags,omitempty"` // Glob patterns of tags releases may be created for (default: any tag)
+	Draft                *bool    `yaml:"draft,omitempty"`        // When true (default), releases are always created as drafts
+	MaxSizeKB            int      `yaml:"max-size,omitempty"`     // Maximum asset size in KB (default: 10240 = 10MB)
+	AllowedExts          []string `yaml:"allowed-exts,omitempty"` // Allowed asset extensions (default: archives and text files)
+	Footer               *bool    `yaml:"footer,omitempty"`       // Controls whether AI-generated footer is added to the release notes
+}
+
+// CreateTagConfig holds configuration for creating git tags from agent output
+type CreateTagConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	AllowedTags          []string `yaml:"allowed-tags,omitempty"` // Glob patterns of tags that may be created (default: any tag)
+}
+
+// parseCreateReleaseConfig handles create-release configuration
+func (c *Compiler) parseCreateReleaseConfig(outputMap map[string]any) *CreateReleaseConfig {
+	configData, exists := outputMap["create-release"]
+	if !exists {
+		return nil
+	}
+
+	createReleaseLog.Print("Parsing create-release configuration")
+	config := &CreateReleaseConfig{
+		MaxSizeKB:   10240,
+		AllowedExts: defaultReleaseAssetExts,
+	}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		config.AllowedTags = ParseStringArrayFromConfig(configMap, "allowed-tags", createReleaseLog)
+
+		if draft, ok := configMap["draft"].(bool); ok {
+			config.Draft = &draft
+		}
+		if footer, ok := configMap["footer"].(bool); ok {
+			config.Footer = &footer
+		}
+
+		if maxSize, exists := configMap["max-size"]; exists {
+			if maxSizeInt, ok := parseIntValue(maxSize); ok && maxSizeInt > 0 {
+				config.MaxSizeKB = maxSizeInt
+			}
+		}
+		if exts := ParseStringArrayFromConfig(configMap, "allowed-exts", createReleaseLog); len(exts) > 0 {
+			config.AllowedExts = exts
+		}
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		config.Max = 1
+	}
+
+	createReleaseLog.Printf("Parsed create-release config: max=%d, allowed_tags=%v, draft=%v, max_size_kb=%d",
+		config.Max, config.AllowedTags, config.forcesDraft(), config.MaxSizeKB)
+	return config
+}
+
+// parseCreateTagConfig handles create-tag configuration
+func (c *Compiler) parseCreateTagConfig(outputMap map[string]any) *CreateTagConfig {
+	configData, exists := outputMap["create-tag"]
+	if !exists {
+		return nil
+	}
+
+	createReleaseLog.Print("Parsing create-tag configuration")
+	config := &CreateTagConfig{}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		config.AllowedTags = ParseStringArrayFromConfig(configMap, "allowed-tags", createReleaseLog)
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		config.Max = 1
+	}
+
+	createReleaseLog.Printf("Parsed create-tag config: max=%d, allowed_tags=%v", config.Max, config.AllowedTags)
+	return config
+}
+
+// forcesDraft reports whether releases are always created as drafts (the default)
+func (config *CreateReleaseConfig) forcesDraft() bool {
+	return config.Draft == nil || *config.Draft
+}
+
+// validateReleaseSafeOutputs validates create-release and create-tag: tag patterns must be
+// valid globs, and threat detection must stay enabled because releases publish agent content
+func validateReleaseSafeOutputs(config *SafeOutputsConfig) error {
+	if config == nil || (config.CreateRelease == nil && config.CreateTag == nil) {
+		return nil
+	}
+
+	check := func(outputType string, patterns []string) error {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("safe-outputs.%s: invalid tag pattern '%s' in allowed-tags", outputType, pattern)
+			}
+		}
+		if config.ThreatDetection == nil {
+			return fmt.Errorf("safe-outputs.%s requires threat detection. Remove 'threat-detection: false' so release content is analyzed before it is published", outputType)
+		}
+		return nil
+	}
+
+	if config.CreateRelease != nil {
+		if err := check("create-release", config.CreateRelease.AllowedTags); err != nil {
+			return err
+		}
+		for _, ext := range config.CreateRelease.AllowedExts {
+			if !strings.HasPrefix(ext, ".") {
+				return fmt.Errorf("safe-outputs.create-release: invalid extension '%s' in allowed-exts (must start with '.')", ext)
+			}
+		}
+	}
+	if config.CreateTag != nil {
+		if err := check("create-tag", config.CreateTag.AllowedTags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreateReleaseConfig(t *testing.T) {
+	draft := false
+	tests := []struct {
+		name     string
+		config   any
+		expected *CreateReleaseConfig
+	}{
+		{
+			name:   "null config uses defaults",
+			config: nil,
+			expected: &CreateReleaseConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				MaxSizeKB:            10240,
+				AllowedExts:          defaultReleaseAssetExts,
+			},
+		},
+		{
+			name: "full config",
+			config: map[string]any{
+				"max":          2,
+				"allowed-tags": []any{"v*", "nightly-*"},
+				"draft":        false,
+				"max-size":     2048,
+				"allowed-exts": []any{".zip"},
+			},
+			expected: &CreateReleaseConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 2},
+				AllowedTags:          []string{"v*", "nightly-*"},
+				Draft:                &draft,
+				MaxSizeKB:            2048,
+				AllowedExts:          []string{".zip"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewCompiler().parseCreateReleaseConfig(map[string]any{"create-release": tt.config})
+			assert.Equal(t, tt.expected, config, "config should be parsed")
+		})
+	}
+
+	assert.Nil(t, NewCompiler().parseCreateReleaseConfig(map[string]any{}), "missing config should return nil")
+	assert.True(t, (&CreateReleaseConfig{}).forcesDraft(), "releases should be drafts by default")
+	assert.False(t, (&CreateReleaseConfig{Draft: &draft}).forcesDraft(), "draft: false should allow publishing")
+}
+
+func TestParseCreateTagConfig(t *testing.T) {
+	config := NewCompiler().parseCreateTagConfig(map[string]any{"create-tag": map[string]any{"allowed-tags": []any{"v*"}}})
+	require.NotNil(t, config, "config should be parsed")
+	assert.Equal(t, 1, config.Max, "max should default to 1")
+	assert.Equal(t, []string{"v*"}, config.AllowedTags, "allowed tags should be parsed")
+
+	assert.Nil(t, NewCompiler().parseCreateTagConfig(map[string]any{}), "missing config should return nil")
+}
+
+func TestValidateReleaseSafeOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SafeOutputsConfig
+		wantErr string
+	}{
+		{
+			name:   "no release outputs",
+			config: &SafeOutputsConfig{},
+		},
+		{
+			name: "valid release config",
+			config: &SafeOutputsConfig{
+				CreateRelease:   &CreateReleaseConfig{AllowedTags: []string{"v*"}, AllowedExts: []string{".zip"}},
+				ThreatDetection: &ThreatDetectionConfig{},
+			},
+		},
+		{
+			name:    "release requires threat detection",
+			config:  &SafeOutputsConfig{CreateRelease: &CreateReleaseConfig{}},
+			wantErr: "create-release requires threat detection",
+		},
+		{
+			name:    "tag requires threat detection",
+			config:  &SafeOutputsConfig{CreateTag: &CreateTagConfig{}},
+			wantErr: "create-tag requires threat detection",
+		},
+		{
+			name: "invalid tag pattern",
+			config: &SafeOutputsConfig{
+				CreateTag:       &CreateTagConfig{AllowedTags: []string{"v[1"}},
+				ThreatDetection: &ThreatDetectionConfig{},
+			},
+			wantErr: "invalid tag pattern 'v[1'",
+		},
+		{
+			name: "extension without dot",
+			config: &SafeOutputsConfig{
+				CreateRelease:   &CreateReleaseConfig{AllowedExts: []string{"zip"}},
+				ThreatDetection: &ThreatDetectionConfig{},
+			},
+			wantErr: "invalid extension 'zip'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReleaseSafeOutputs(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "config should be valid")
+				return
+			}
+			require.Error(t, err, "config should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error should explain the problem")
+		})
+	}
+}
+
+func TestCreateReleaseCompilation(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  create-release:
+    allowed-tags: ["v*"]
+    allowed-exts: [.zip, .md]
+  create-tag:
+    allowed-tags: ["v*"]
+---
+
+# Release
+
+Build the release artifacts and publish a release.
+`
+
+	tmpDir := testutil.TempDir(t, "create-release-*")
+	testFile := filepath.Join(tmpDir, "release.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "release.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `\"create_release\":{\"allowed_exts\":[\".zip\",\".md\"],\"allowed_tags\":[\"v*\"],\"draft\":true,\"max\":1,\"max_size_kb\":10240}`, "handler config should force drafts")
+	assert.Contains(t, lock, `\"create_tag\":{\"allowed_tags\":[\"v*\"],\"max\":1}`, "handler config should include the tag patterns")
+	assert.Contains(t, lock, releaseAssetsDir, "release assets should be uploaded with the agent artifacts")
+
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	assert.Contains(t, safeOutputsJob, "name: Download release assets", "safe_outputs job should download the release assets")
+	assert.Contains(t, safeOutputsJob[:strings.Index(safeOutputsJob, "    steps:")], "contents: write", "safe_outputs job should have contents: write")
+}
+
+func TestCreateReleaseRequiresThreatDetection(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  create-release:
+  threat-detection: false
+---
+
+# Release
+`
+
+	tmpDir := testutil.TempDir(t, "create-release-*")
+	testFile := filepath.Join(tmpDir, "release.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	err := NewCompiler().CompileWorkflow(testFile)
+	require.Error(t, err, "disabling threat detection should fail")
+	assert.Contains(t, err.Error(), "requires threat detection", "error should explain why")
+}
@@ -71,7 +71,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 		ecosystems["npm"] = true
 		dependabotLog.Printf("Found %d unique npm dependencies", len(npmDeps))
 		if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Found %d npm dependencies in workflows", len(npmDeps))))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage(fmt.Sprintf("Found %d npm dependencies in workflows", len(npmDeps))))
 		}
 
 		// Generate package.json
@@ -81,7 +81,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 				return fmt.Errorf("failed to generate package.json: %w", err)
 			}
 			c.IncrementWarningCount()
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate package.json: %v", err)))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("Failed to generate package.json: %v", err)))
 		} else {
 			// Generate package-lock.json
 			if err := c.generatePackageLock(workflowDir); err != nil {
@@ -89,7 +89,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 					return fmt.Errorf("failed to generate package-lock.json: %w", err)
 				}
 				c.IncrementWarningCount()
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate package-lock.json: %v", err)))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("Failed to generate package-lock.json: %v", err)))
 			}
 		}
 	}
@@ -100,7 +100,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 		ecosystems["pip"] = true
 		dependabotLog.Printf("Found %d unique pip dependencies", len(pipDeps))
 		if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Found %d pip dependencies in workflows", len(pipDeps))))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage(fmt.Sprintf("Found %d pip dependencies in workflows", len(pipDeps))))
 		}
 
 		// Generate requirements.txt
@@ -110,7 +110,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 				return fmt.Errorf("failed to generate requirements.txt: %w", err)
 			}
 			c.IncrementWarningCount()
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate requirements.txt: %v", err)))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("Failed to generate requirements.txt: %v", err)))
 		}
 	}
 
@@ -120,7 +120,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 		ecosystems["gomod"] = true
 		dependabotLog.Printf("Found %d unique go dependencies", len(goDeps))
 		if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Found %d go dependencies in workflows", len(goDeps))))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage(fmt.Sprintf("Found %d go dependencies in workflows", len(goDeps))))
 		}
 
 		// Generate go.mod
@@ -130,7 +130,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 				return fmt.Errorf("failed to generate go.mod: %w", err)
 			}
 			c.IncrementWarningCount()
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate go.mod: %v", err)))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("Failed to generate go.mod: %v", err)))
 		}
 	}
 
@@ -138,7 +138,7 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 	if len(ecosystems) == 0 {
 		dependabotLog.Print("No dependencies found, skipping manifest generation")
 		if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No dependencies detected in workflows, skipping Dependabot manifest generation"))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage("No dependencies detected in workflows, skipping Dependabot manifest generation"))
 		}
 		return nil
 	}
@@ -150,11 +150,11 @@ func (c *Compiler) GenerateDependabotManifests(workflowDataList []*WorkflowData,
 			return fmt.Errorf("failed to generate dependabot.yml: %w", err)
 		}
 		c.IncrementWarningCount()
-		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to generate dependabot.yml: %v", err)))
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("Failed to generate dependabot.yml: %v", err)))
 	}
 
 	if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Successfully generated Dependabot manifests"))
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage("Successfully generated Dependabot manifests"))
 	}
 
 	return nil
@@ -250,7 +250,7 @@ func (c *Compiler) generatePackageJSON(path string, deps []NpmDependency, forceO
 		}
 
 		if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Merging with existing package.json"))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage("Merging with existing package.json"))
 		}
 	} else {
 		// New package.json
@@ -287,7 +287,7 @@ func (c *Compiler) generatePackageJSON(path string, deps []NpmDependency, forceO
 
 	dependabotLog.Printf("Successfully wrote package.json with %d dependencies", len(pkgJSON.Dependencies))
 	if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Generated package.json with %d dependencies", len(pkgJSON.Dependencies))))
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage(fmt.Sprintf("Generated package.json with %d dependencies", len(pkgJSON.Dependencies))))
 	}
 
 	// Track the created file
@@ -309,7 +309,7 @@ func (c *Compiler) generatePackageLock(workflowDir string) error {
 	}
 
 	if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Running npm install --package-lock-only..."))
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage("Running npm install --package-lock-only..."))
 	}
 
 	// Run npm install --package-lock-only
@@ -329,7 +329,7 @@ func (c *Compiler) generatePackageLock(workflowDir string) error {
 
 	dependabotLog.Print("Successfully generated package-lock.json")
 	if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Generated package-lock.json"))
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage("Generated package-lock.json"))
 	}
 
 	// Track the created file
@@ -398,7 +398,7 @@ func (c *Compiler) generateDependabotConfig(path string, ecosystems map[string]b
 
 	dependabotLog.Print("Successfully wrote dependabot.yml")
 	if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Updated .github/dependabot.yml"))
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage("Updated .github/dependabot.yml"))
 	}
 
 	// Track the created file
@@ -503,7 +503,7 @@ func (c *Compiler) generateRequirementsTxt(path string, deps []PipDependency, fo
 		}
 
 		if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Merging with existing requirements.txt"))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage("Merging with existing requirements.txt"))
 		}
 	} else {
 		dependabotLog.Print("Creating new requirements.txt")
@@ -535,7 +535,7 @@ func (c *Compiler) generateRequirementsTxt(path string, deps []PipDependency, fo
 
 	dependabotLog.Printf("Successfully wrote requirements.txt with %d dependencies", len(reqMap))
 	if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Generated requirements.txt with %d dependencies", len(reqMap))))
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage(fmt.Sprintf("Generated requirements.txt with %d dependencies", len(reqMap))))
 	}
 
 	// Track the created file
@@ -637,7 +637,7 @@ func (c *Compiler) generateGoMod(path string, deps []GoDependency, forceOverwrit
 		}
 
 		if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Merging with existing go.mod"))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage("Merging with existing go.mod"))
 		}
 	} else {
 		// New go.mod
@@ -673,7 +673,7 @@ func (c *Compiler) generateGoMod(path string, deps []GoDependency, forceOverwrit
 
 	dependabotLog.Printf("Successfully wrote go.mod with %d dependencies", len(deps))
 	if c.verbose {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Generated go.mod with %d dependencies", len(deps))))
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatSuccessMessage(fmt.Sprintf("Generated go.mod with %d dependencies", len(deps))))
 	}
 
 	// Track the created file
@@ -0,0 +1,622 @@
+// This file assigns stable rule IDs to compiler warnings and applies the repository's rule
+// severities and the workflow's inline suppressions to them.
+//
+// # Rule Severities
+//
+// Each rule can be turned off, kept as a warning (the default) or promoted to an error in
+// .github/aw/lint.yml. The same file also sets the severity of the lock file security
+// linter rules (gh aw compile --lint):
+//
+//	rules:
+//	  schedule/fixed-time: off
+//	  id-token-write: error
+//	  unpinned-action: warn
+//
+// The markdown security scanner rules (markdown-security/<category>) are off by default and
+// only report findings once turned on in lint.yml.
+//
+// # Inline Suppressions
+//
+// A workflow accepts a warning with a comment in its frontmatter naming the rule and the
+// reason. The comment applies to the key on the same line, or to the key on the next line,
+// including everything nested under it. Suppressed warnings are not printed or counted, and
+// are listed in the --json output so that accepted exceptions remain visible:
+//
+//	---
+//	on: daily
+//	# gh-aw-ignore: id-token-write deploys to AWS with OIDC
+//	permissions:
+//	  id-token: write
+//	---
+//
+// Warnings that do not point at a line are matched through the top-level keys their rule is
+// about (see diagnosticRuleKeys), so the example above does not accept an id-token-write
+// warning if the comment is moved above on:.
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/goccy/go-yaml"
+)
+
+var diagnosticRulesLog = logger.New("workflow:diagnostic_rules")
+
+// LintConfigFileName is the repository-wide rule severity policy in .github/aw
+const LintConfigFileName = "lint.yml"
+
+// DiagnosticRule is the stable ID of a kind of compiler warning
+type DiagnosticRule string
+
+const (
+	RuleScheduleFixedTime           DiagnosticRule = "schedule/fixed-time"
+	RuleScheduleNoRepository        DiagnosticRule = "schedule/no-repository"
+	RuleActionPinFallback           DiagnosticRule = "action-pin/fallback"
+	RuleActionPinUnresolved         DiagnosticRule = "action-pin/unresolved"
+	RuleStrictMissingPermissions    DiagnosticRule = "strict/missing-permissions"
+	RuleStrictWorkflowRunBranches   DiagnosticRule = "strict/workflow-run-branches"
+	RuleStrictFirewallUnsupported   DiagnosticRule = "strict/firewall-unsupported"
+	RuleStrictFirewallDisabled      DiagnosticRule = "strict/firewall-disabled"
+	RuleStrictCustomStepSecrets     DiagnosticRule = "strict/custom-step-secrets"
+	RuleStrictTransferIssueToken    DiagnosticRule = "strict/transfer-issue-token"
+	RuleStrictDispatchAllowedRepos  DiagnosticRule = "strict/dispatch-allowed-repos"
+	RuleStrictSafeInputsContainer   DiagnosticRule = "strict/safe-inputs-container"
+	RuleSandboxDisabled             DiagnosticRule = "sandbox-disabled"
+	RuleIDTokenWrite                DiagnosticRule = "id-token-write"
+	RuleExperimentalFeature         DiagnosticRule = "experimental-feature"
+	RuleToolsIgnored                DiagnosticRule = "tools-ignored"
+	RuleWebSearchUnsupported        DiagnosticRule = "web-search-unsupported"
+	RuleEngineOverride              DiagnosticRule = "engine-override"
+	RuleDeprecatedField             DiagnosticRule = "deprecated-field"
+	RuleDispatchWorkflowToken       DiagnosticRule = "dispatch-workflow/missing-token"
+	RuleDispatchWorkflowUnvalidated DiagnosticRule = "dispatch-workflow/unvalidated-inputs"
+	RuleContainerImageValidation    DiagnosticRule = "container-image-validation"
+	RuleSchemaValidationSkipped     DiagnosticRule = "schema-validation-skipped"
+	RuleInvalidSuppression          DiagnosticRule = "invalid-suppression"
+	RulePromptMaxTokens             DiagnosticRule = "prompt/max-tokens"
+)
+
+// markdownSecurityRulePrefix prefixes the rules of the markdown security scanner categories
+const markdownSecurityRulePrefix = "markdown-security/"
+
+// suppressionCommentMarker introduces an inline suppression in a frontmatter comment
+const suppressionCommentMarker = "gh-aw-ignore:"
+
+// diagnosticRuleDescriptions describes the compiler warning rules
+var diagnosticRuleDescriptions = map[DiagnosticRule]string{
+	RuleScheduleFixedTime:           "Schedule uses a fixed time instead of a fuzzy schedule",
+	RuleScheduleNoRepository:        "Fuzzy schedule is scattered without repository context",
+	RuleActionPinFallback:           "Action could not be resolved and a hardcoded pin was used",
+	RuleActionPinUnresolved:         "Action could not be pinned to a commit SHA",
+	RuleStrictMissingPermissions:    "GitHub toolsets need permissions the workflow does not grant (error in strict mode)",
+	RuleStrictWorkflowRunBranches:   "workflow_run trigger has no branch restrictions (error in strict mode)",
+	RuleStrictFirewallUnsupported:   "Engine does not support the network firewall (error in strict mode)",
+	RuleStrictFirewallDisabled:      "Network firewall is disabled while network restrictions are set (error in strict mode)",
+	RuleStrictCustomStepSecrets:     "Custom steps use agentic engine secrets (error in strict mode)",
+	RuleStrictTransferIssueToken:    "transfer-issue uses the default GITHUB_TOKEN (error in strict mode)",
+	RuleStrictDispatchAllowedRepos:  "Cross-repository dispatch-workflow is not restricted by allowed-repos (error in strict mode)",
+	RuleStrictSafeInputsContainer:   "safe-inputs container is not pinned or shares the host network (error in strict mode)",
+	RuleSandboxDisabled:             "Agent sandbox is disabled",
+	RuleIDTokenWrite:                "Workflow grants id-token: write",
+	RuleExperimentalFeature:         "Workflow uses an experimental feature or engine",
+	RuleToolsIgnored:                "tools section is ignored by the engine",
+	RuleWebSearchUnsupported:        "Engine does not support the web-search tool",
+	RuleEngineOverride:              "Command line --engine overrides the workflow engine",
+	RuleDeprecatedField:             "Workflow uses a deprecated field",
+	RuleDispatchWorkflowToken:       "Cross-repository dispatch-workflow has no github-token",
+	RuleDispatchWorkflowUnvalidated: "Inputs of a cross-repository dispatch-workflow could not be validated",
+	RuleContainerImageValidation:    "Container image could not be validated",
+	RuleSchemaValidationSkipped:     "Schema validation was skipped",
+	RuleInvalidSuppression:          "gh-aw-ignore comment names an unknown rule, has no reason or is not next to a key",
+	RulePromptMaxTokens:             "Prompt is estimated to exceed prompt.max-tokens",
+}
+
+// diagnosticRuleKeys lists the top-level frontmatter keys that the warnings of a rule are about.
+// A gh-aw-ignore comment only suppresses a warning without a line when it applies to one of them.
+var diagnosticRuleKeys = map[DiagnosticRule][]string{
+	RuleScheduleFixedTime:           {"on"},
+	RuleScheduleNoRepository:        {"on"},
+	RuleActionPinFallback:           {"steps", "post-steps", "jobs", "runtimes", "safe-outputs"},
+	RuleActionPinUnresolved:         {"steps", "post-steps", "jobs", "runtimes", "safe-outputs"},
+	RuleStrictMissingPermissions:    {"permissions", "tools"},
+	RuleStrictWorkflowRunBranches:   {"on"},
+	RuleStrictFirewallUnsupported:   {"engine", "network"},
+	RuleStrictFirewallDisabled:      {"network", "sandbox"},
+	RuleStrictCustomStepSecrets:     {"steps", "post-steps"},
+	RuleStrictTransferIssueToken:    {"safe-outputs"},
+	RuleStrictDispatchAllowedRepos:  {"safe-outputs"},
+	RuleStrictSafeInputsContainer:   {"safe-inputs"},
+	RuleSandboxDisabled:             {"sandbox"},
+	RuleIDTokenWrite:                {"permissions"},
+	RuleExperimentalFeature:         {"engine", "safe-inputs", "plugins", "rate-limit"},
+	RuleToolsIgnored:                {"tools"},
+	RuleWebSearchUnsupported:        {"tools", "engine"},
+	RuleEngineOverride:              {"engine"},
+	RuleDeprecatedField:             {"on"},
+	RuleDispatchWorkflowToken:       {"safe-outputs"},
+	RuleDispatchWorkflowUnvalidated: {"safe-outputs"},
+	RuleContainerImageValidation:    {"tools", "mcp-servers"},
+	RulePromptMaxTokens:             {"prompt"},
+}
+
+// markdownSecurityCategories lists the categories of the markdown security scanner
+var markdownSecurityCategories = []SecurityFindingCategory{
+	CategoryUnicodeAbuse,
+	CategoryHiddenContent,
+	CategoryObfuscatedLinks,
+	CategoryHTMLAbuse,
+	CategoryEmbeddedFiles,
+	CategorySocialEngineering,
+}
+
+// MarkdownSecurityRule returns the rule of a markdown security scanner category
+func MarkdownSecurityRule(category SecurityFindingCategory) DiagnosticRule {
+	return DiagnosticRule(markdownSecurityRulePrefix + string(category))
+}
+
+// DiagnosticRuleDescription returns the description of a compiler warning rule
+func DiagnosticRuleDescription(rule DiagnosticRule) string {
+	if description, ok := diagnosticRuleDescriptions[rule]; ok {
+		return description
+	}
+	if category, ok := strings.CutPrefix(string(rule), markdownSecurityRulePrefix); ok {
+		return "Markdown security scanner: " + category
+	}
+	return string(rule)
+}
+
+// defaultRuleSeverity returns the severity of a rule that is not configured in lint.yml.
+// The markdown security scanner is opt-in, since its heuristics flag legitimate prompts.
+func defaultRuleSeverity(rule DiagnosticRule) RuleSeverity {
+	if strings.HasPrefix(string(rule), markdownSecurityRulePrefix) {
+		return RuleSeverityOff
+	}
+	return RuleSeverityWarn
+}
+
+// isKnownRule reports whether id names a compiler warning rule or a lock file linter rule
+func isKnownRule(id string) bool {
+	if _, ok := diagnosticRuleDescriptions[DiagnosticRule(id)]; ok {
+		return true
+	}
+	for _, category := range markdownSecurityCategories {
+		if id == string(MarkdownSecurityRule(category)) {
+			return true
+		}
+	}
+	for _, info := range LockFileLintRules() {
+		if id == string(info.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleSeverity is the severity configured for a rule in .github/aw/lint.yml
+type RuleSeverity string
+
+const (
+	RuleSeverityOff   RuleSeverity = "off"
+	RuleSeverityWarn  RuleSeverity = "warn"
+	RuleSeverityError RuleSeverity = "error"
+)
+
+// LintConfig holds the rule severities of a repository
+type LintConfig struct {
+	Rules map[string]RuleSeverity `yaml:"rules,omitempty"`
+}
+
+// LoadLintConfig reads .github/aw/lint.yml under gitRoot. A missing file yields an empty
+// configuration, which keeps every rule at its default severity.
+func LoadLintConfig(gitRoot string) (*LintConfig, error) {
+	path := filepath.Join(gitRoot, ".github", "aw", LintConfigFileName)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &LintConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", LintConfigFileName, err)
+	}
+	return ParseLintConfig(content)
+}
+
+// ParseLintConfig parses and validates the content of a lint.yml file
+func ParseLintConfig(content []byte) (*LintConfig, error) {
+	var config LintConfig
+	if err := yaml.UnmarshalWithOptions(content, &config, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", LintConfigFileName, err)
+	}
+
+	ids := make([]string, 0, len(config.Rules))
+	for id := range config.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !isKnownRule(id) {
+			return nil, fmt.Errorf("invalid %s: unknown rule %q", LintConfigFileName, id)
+		}
+		switch severity := config.Rules[id]; severity {
+		case RuleSeverityOff, RuleSeverityWarn, RuleSeverityError:
+		default:
+			return nil, fmt.Errorf("invalid %s: rule %q has severity %q, want one of: off, warn, error", LintConfigFileName, id, severity)
+		}
+	}
+	diagnosticRulesLog.Printf("Loaded lint configuration with %d rule severities", len(config.Rules))
+	return &config, nil
+}
+
+// Severity returns the configured severity of a rule, or fallback when the rule is not configured
+func (l *LintConfig) Severity(id string, fallback RuleSeverity) RuleSeverity {
+	if l == nil {
+		return fallback
+	}
+	if severity, ok := l.Rules[id]; ok {
+		return severity
+	}
+	return fallback
+}
+
+// ApplyToLockFileFindings drops the findings of rules that are turned off and sets the
+// severity of the findings of rules configured as warn or error
+func (l *LintConfig) ApplyToLockFileFindings(findings []LockFileLintFinding) []LockFileLintFinding {
+	if l == nil || len(l.Rules) == 0 {
+		return findings
+	}
+	var result []LockFileLintFinding
+	for _, finding := range findings {
+		switch l.Severity(string(finding.Rule), "") {
+		case RuleSeverityOff:
+			continue
+		case RuleSeverityWarn:
+			finding.Severity = "warning"
+		case RuleSeverityError:
+			finding.Severity = "error"
+		}
+		result = append(result, finding)
+	}
+	return result
+}
+
+// Suppression is a gh-aw-ignore comment in the frontmatter of a workflow
+type Suppression struct {
+	Rule   DiagnosticRule
+	Reason string
+	Line   int // 1-based line in the workflow file
+
+	// Key is the top-level frontmatter key the comment applies to, empty if no key follows it.
+	// StartLine and EndLine are the lines of the adjacent key and everything nested under it.
+	Key       string
+	StartLine int
+	EndLine   int
+}
+
+// covers reports whether the suppression accepts a warning of rule reported at line (0 if unknown)
+func (s Suppression) covers(rule DiagnosticRule, line int) bool {
+	if s.Rule != rule || s.Key == "" {
+		return false
+	}
+	if line > 0 {
+		return line >= s.StartLine && line <= s.EndLine
+	}
+	return slices.Contains(diagnosticRuleKeys[rule], s.Key)
+}
+
+// suppressionCommentPattern matches a gh-aw-ignore comment, on its own line or after a value
+var suppressionCommentPattern = regexp.MustCompile(`(?:^|\s)#\s*` + suppressionCommentMarker + `\s*(\S*)\s*(.*)$`)
+
+// frontmatterKeyPattern matches the key of a frontmatter line, including list items (- key:)
+var frontmatterKeyPattern = regexp.MustCompile(`^\s*(?:-\s+)?([^\s#:'"][^:#]*?|'[^']*'|"[^"]*"):(?:\s|$)`)
+
+// parseSuppressions finds the gh-aw-ignore comments in frontmatter lines that start at line
+// frontmatterStart of the file, with the key each of them applies to
+func parseSuppressions(frontmatterLines []string, frontmatterStart int) []Suppression {
+	var suppressions []Suppression
+	for i, line := range frontmatterLines {
+		if !strings.Contains(line, suppressionCommentMarker) {
+			continue
+		}
+		loc := suppressionCommentPattern.FindStringSubmatchIndex(line)
+		if loc == nil {
+			continue
+		}
+		suppression := Suppression{
+			Rule:   DiagnosticRule(line[loc[2]:loc[3]]),
+			Reason: strings.TrimSpace(line[loc[4]:loc[5]]),
+			Line:   frontmatterStart + i,
+		}
+
+		// A trailing comment applies to the key on its line, a comment line to the next key
+		keyIndex := -1
+		if strings.TrimSpace(line[:loc[0]]) != "" {
+			keyIndex = i
+		} else {
+			for j := i + 1; j < len(frontmatterLines); j++ {
+				if !isFrontmatterFillerLine(frontmatterLines[j]) {
+					keyIndex = j
+					break
+				}
+			}
+		}
+		if keyIndex >= 0 && frontmatterKeyPattern.MatchString(frontmatterLines[keyIndex]) {
+			suppression.Key = topLevelFrontmatterKey(frontmatterLines, keyIndex)
+			suppression.StartLine = frontmatterStart + keyIndex
+			suppression.EndLine = frontmatterStart + frontmatterBlockEnd(frontmatterLines, keyIndex)
+		}
+		suppressions = append(suppressions, suppression)
+	}
+	return suppressions
+}
+
+// isFrontmatterFillerLine reports whether a frontmatter line is blank or only holds a comment
+func isFrontmatterFillerLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// frontmatterBlockEnd returns the index of the last line of the key at index and its nested lines.
+// A key that starts a list item covers the whole item.
+func frontmatterBlockEnd(lines []string, index int) int {
+	keyColumn := len(lines[index]) - len(strings.TrimLeft(lines[index], " "))
+	value := strings.TrimSpace(lines[index][frontmatterKeyPattern.FindStringIndex(lines[index])[1]:])
+	hasBlockValue := value == "" || strings.HasPrefix(value, "#")
+
+	end := index
+	for j := index + 1; j < len(lines); j++ {
+		if isFrontmatterFillerLine(lines[j]) {
+			continue
+		}
+		content := strings.TrimLeft(lines[j], " ")
+		indent := len(lines[j]) - len(content)
+		// A block sequence may start at the indentation of its key
+		nested := indent > keyColumn || (hasBlockValue && indent == keyColumn && strings.HasPrefix(content, "-"))
+		if !nested {
+			break
+		}
+		end = j
+	}
+	return end
+}
+
+// topLevelFrontmatterKey returns the top-level key that contains the line at index
+func topLevelFrontmatterKey(lines []string, index int) string {
+	for j := index; j >= 0; j-- {
+		if isFrontmatterFillerLine(lines[j]) || strings.HasPrefix(lines[j], " ") || strings.HasPrefix(lines[j], "-") {
+			continue
+		}
+		if match := frontmatterKeyPattern.FindStringSubmatch(lines[j]); match != nil {
+			return strings.Trim(match[1], `'"`)
+		}
+		return ""
+	}
+	return ""
+}
+
+// RuleDiagnostic is a compiler warning reported under a rule
+type RuleDiagnostic struct {
+	Rule    DiagnosticRule `json:"rule"`
+	Message string         `json:"message"`
+	Line    int            `json:"line,omitempty"`    // 1-based line in the workflow file, 0 if unknown
+	Located bool           `json:"located,omitempty"` // Printed with the workflow file's location
+}
+
+// SuppressedDiagnostic is a compiler warning silenced by a gh-aw-ignore comment
+type SuppressedDiagnostic struct {
+	Rule    DiagnosticRule `json:"rule"`
+	Message string         `json:"message"`
+	Reason  string         `json:"reason"`
+	Line    int            `json:"line"` // Line of the gh-aw-ignore comment
+}
+
+// WorkflowDiagnostics tracks the rule warnings of the workflow being compiled
+type WorkflowDiagnostics struct {
+	File       string
+	Warnings   []RuleDiagnostic       // Warnings that were printed and counted
+	Suppressed []SuppressedDiagnostic // Warnings silenced by a gh-aw-ignore comment
+	Errors     []RuleDiagnostic       // Warnings promoted to errors in lint.yml
+
+	config       *LintConfig
+	suppressions []Suppression
+	reported     map[string]bool // Rule and message of the warnings already resolved
+	onWarning    func()
+}
+
+// resolve applies the lint configuration and the inline suppressions to a warning and records
+// the outcome. It returns true when the warning should be printed.
+func (d *WorkflowDiagnostics) resolve(rule DiagnosticRule, message string, line int) bool {
+	return d.resolveDiagnostic(RuleDiagnostic{Rule: rule, Message: message, Line: line})
+}
+
+// resolveDiagnostic is resolve for a warning with its print format
+func (d *WorkflowDiagnostics) resolveDiagnostic(diagnostic RuleDiagnostic) bool {
+	rule, message, line := diagnostic.Rule, diagnostic.Message, diagnostic.Line
+	// Warnings reported more than once for a workflow (e.g. an action used by several jobs)
+	// are recorded once
+	if !d.markReported(rule, message) {
+		return false
+	}
+
+	severity := d.config.Severity(string(rule), defaultRuleSeverity(rule))
+	if severity == RuleSeverityOff {
+		diagnosticRulesLog.Printf("Rule %s is off, dropping warning", rule)
+		return false
+	}
+	for _, suppression := range d.suppressions {
+		if suppression.covers(rule, line) {
+			diagnosticRulesLog.Printf("Rule %s is suppressed on line %d: %s", rule, suppression.Line, suppression.Reason)
+			d.Suppressed = append(d.Suppressed, SuppressedDiagnostic{Rule: rule, Message: message, Reason: suppression.Reason, Line: suppression.Line})
+			return false
+		}
+	}
+	if severity == RuleSeverityError {
+		d.Errors = append(d.Errors, diagnostic)
+		return false
+	}
+	d.Warnings = append(d.Warnings, diagnostic)
+	if d.onWarning != nil {
+		d.onWarning()
+	}
+	return true
+}
+
+// markReported records that a warning was resolved, returning false if it already was
+func (d *WorkflowDiagnostics) markReported(rule DiagnosticRule, message string) bool {
+	key := string(rule) + "\x00" + message
+	if d.reported[key] {
+		return false
+	}
+	if d.reported == nil {
+		d.reported = make(map[string]bool)
+	}
+	d.reported[key] = true
+	return true
+}
+
+// err returns the warnings promoted to errors as one error, or nil
+func (d *WorkflowDiagnostics) err() error {
+	if len(d.Errors) == 0 {
+		return nil
+	}
+	var lines []string
+	for _, diagnostic := range d.Errors {
+		lines = append(lines, ruleMessage(diagnostic.Rule, diagnostic.Message))
+	}
+	return fmt.Errorf("%d warning(s) configured as errors in .github/aw/%s:\n%s", len(lines), LintConfigFileName, strings.Join(lines, "\n"))
+}
+
+// ruleMessage prefixes a warning with its rule ID
+func ruleMessage(rule DiagnosticRule, message string) string {
+	return fmt.Sprintf("[%s] %s", rule, message)
+}
+
+// SetLintConfig sets the rule severities applied to compiler warnings
+func (c *Compiler) SetLintConfig(config *LintConfig) {
+	c.lintConfig = config
+}
+
+// GetWorkflowDiagnostics returns the rule warnings of the most recently compiled workflow
+func (c *Compiler) GetWorkflowDiagnostics() *WorkflowDiagnostics {
+	return c.workflowDiagnostics()
+}
+
+// beginWorkflowDiagnostics starts tracking the rule warnings of a workflow
+func (c *Compiler) beginWorkflowDiagnostics(markdownPath string) {
+	c.diagnostics = &WorkflowDiagnostics{
+		File:      markdownPath,
+		config:    c.lintConfig,
+		onWarning: c.IncrementWarningCount,
+	}
+}
+
+// applySuppressions reads the gh-aw-ignore comments of the workflow's frontmatter. Comments
+// naming an unknown rule or without a reason are reported and ignored.
+func (c *Compiler) applySuppressions(markdownPath string, result *parser.FrontmatterResult) {
+	for _, suppression := range parseSuppressions(result.FrontmatterLines, result.FrontmatterStart) {
+		switch {
+		case !isKnownRule(string(suppression.Rule)):
+			c.warnAtLine(RuleInvalidSuppression, markdownPath, suppression.Line,
+				fmt.Sprintf("gh-aw-ignore names unknown rule %q", suppression.Rule))
+		case suppression.Reason == "":
+			c.warnAtLine(RuleInvalidSuppression, markdownPath, suppression.Line,
+				fmt.Sprintf("gh-aw-ignore for %s needs a reason, e.g. '# gh-aw-ignore: %s <why this is accepted>'", suppression.Rule, suppression.Rule))
+		case suppression.Key == "":
+			c.warnAtLine(RuleInvalidSuppression, markdownPath, suppression.Line,
+				fmt.Sprintf("gh-aw-ignore for %s must be on or above the frontmatter key it applies to", suppression.Rule))
+		default:
+			c.diagnostics.suppressions = append(c.diagnostics.suppressions, suppression)
+		}
+	}
+}
+
+// workflowDiagnostics returns the diagnostics of the current workflow, starting them if needed
+func (c *Compiler) workflowDiagnostics() *WorkflowDiagnostics {
+	if c.diagnostics == nil {
+		c.beginWorkflowDiagnostics(c.markdownPath)
+	}
+	return c.diagnostics
+}
+
+// ReplayWorkflowDiagnostics replaces the rule warnings of the current workflow with the warnings
+// and suppressions that an earlier compilation recorded for it, e.g. when its lock file is reused
+// from the compilation cache. Warnings reported since the workflow was parsed are dropped, as
+// they may depend on this run (e.g. action pins resolved by other workflows); the recorded ones
+// are printed and counted like new ones.
+func (c *Compiler) ReplayWorkflowDiagnostics(warnings []RuleDiagnostic, suppressed []SuppressedDiagnostic) {
+	current := c.workflowDiagnostics()
+	c.warningCount -= len(current.Warnings)
+	for _, diagnostic := range current.Warnings {
+		if isScheduleRule(diagnostic.Rule) {
+			// Schedule warnings of the current workflow are the last ones collected
+			c.scheduleWarnings = c.scheduleWarnings[:len(c.scheduleWarnings)-1]
+		}
+	}
+	c.beginWorkflowDiagnostics(current.File)
+	diagnostics := c.diagnostics
+	for _, diagnostic := range suppressed {
+		if diagnostics.markReported(diagnostic.Rule, diagnostic.Message) {
+			diagnostics.Suppressed = append(diagnostics.Suppressed, diagnostic)
+		}
+	}
+	for _, diagnostic := range warnings {
+		if !diagnostics.markReported(diagnostic.Rule, diagnostic.Message) {
+			continue
+		}
+		diagnostics.Warnings = append(diagnostics.Warnings, diagnostic)
+		if diagnostics.onWarning != nil {
+			diagnostics.onWarning()
+		}
+		switch {
+		case isScheduleRule(diagnostic.Rule):
+			c.scheduleWarnings = append(c.scheduleWarnings, ruleMessage(diagnostic.Rule, diagnostic.Message))
+		case isActionPinRule(diagnostic.Rule):
+			writeActionPinWarning(c.GetDiagnosticWriter(), diagnostic.Rule, diagnostic.Message)
+		case diagnostic.Located:
+			c.printWarningAtLine(diagnostic.Rule, diagnostics.File, diagnostic.Line, diagnostic.Message)
+		default:
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(ruleMessage(diagnostic.Rule, diagnostic.Message)))
+		}
+	}
+}
+
+// warn reports a compiler warning that is not tied to a location in the workflow file
+func (c *Compiler) warn(rule DiagnosticRule, message string) {
+	if c.workflowDiagnostics().resolve(rule, message, 0) {
+		fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(ruleMessage(rule, message)))
+	}
+}
+
+// warnAt reports a compiler warning for the workflow file
+func (c *Compiler) warnAt(rule DiagnosticRule, markdownPath string, message string) {
+	c.warnAtLine(rule, markdownPath, 0, message)
+}
+
+// warnAtLine reports a compiler warning for a line of the workflow file (0 if unknown)
+func (c *Compiler) warnAtLine(rule DiagnosticRule, markdownPath string, line int, message string) {
+	if c.workflowDiagnostics().resolveDiagnostic(RuleDiagnostic{Rule: rule, Message: message, Line: line, Located: true}) {
+		c.printWarningAtLine(rule, markdownPath, line, message)
+	}
+}
+
+// printWarningAtLine prints a compiler warning for a line of the workflow file (0 if unknown)
+func (c *Compiler) printWarningAtLine(rule DiagnosticRule, markdownPath string, line int, message string) {
+	fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatError(console.CompilerError{
+		Position: console.ErrorPosition{
+			File:   markdownPath,
+			Line:   max(line, 1),
+			Column: 1,
+		},
+		Type:    "warning",
+		Message: ruleMessage(rule, message),
+	}))
+}
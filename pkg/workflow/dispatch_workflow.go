@@ -1,6 +1,8 @@
 package workflow
 
 import (
+	"strings"
+
 	"github.com/github/gh-aw/pkg/logger"
 )
 
@@ -9,8 +11,63 @@ var dispatchWorkflowLog = logger.New("workflow:dispatch_workflow")
 // DispatchWorkflowConfig holds configuration for dispatching workflows from agent output
 type DispatchWorkflowConfig struct {
 	BaseSafeOutputConfig `yaml:",inline"`
-	Workflows            []string          `yaml:"workflows,omitempty"`      // List of workflow names (without .md extension) to allow dispatching
-	WorkflowFiles        map[string]string `yaml:"workflow_files,omitempty"` // Map of workflow name to file extension (.lock.yml or .yml) - populated at compile time
+	Workflows            []string                           `yaml:"workflows,omitempty"`      // List of workflow names (without .md extension) or owner/repo/workflow references to allow dispatching
+	WorkflowFiles        map[string]string                  `yaml:"workflow_files,omitempty"` // Map of workflow name to file extension (.lock.yml or .yml) - populated at compile time
+	AllowedRepos         []string                           `yaml:"allowed-repos,omitempty"`  // Repositories that cross-repository workflows may be dispatched to
+	Targets              map[string]*DispatchWorkflowTarget `yaml:"-"`                        // Cross-repository workflows keyed by owner/repo/workflow
+	TargetInputs         map[string]map[string]any          `yaml:"-"`                        // workflow_dispatch inputs of cross-repository workflows - populated at compile time
+}
+
+// DispatchWorkflowTarget holds the options of a workflow in another repository
+type DispatchWorkflowTarget struct {
+	Repo        string `yaml:"repo"`                   // Target repository (owner/repo)
+	Workflow    string `yaml:"workflow"`               // Workflow file name without extension
+	GitHubToken string `yaml:"github-token,omitempty"` // Secret expression, or "app" for the safe-outputs GitHub App token
+	Ref         string `yaml:"ref,omitempty"`          // Ref to dispatch on (default: target repository's default branch)
+}
+
+// dispatchWorkflowAppToken is the github-token value that selects the safe-outputs GitHub App token
+const dispatchWorkflowAppToken = "app"
+
+// splitDispatchWorkflowTarget splits an owner/repo/workflow reference into repository and workflow.
+// Returns ok=false for workflows in the current repository (plain workflow names).
+func splitDispatchWorkflowTarget(name string) (repo string, workflow string, ok bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 {
+		return "", "", false
+	}
+	return parts[0] + "/" + parts[1], parts[2], true
+}
+
+// parseDispatchWorkflowEntries parses the workflows list. Entries are workflow names,
+// owner/repo/workflow references, or objects with a workflow reference and per-target options.
+func parseDispatchWorkflowEntries(entries []any, config *DispatchWorkflowConfig) {
+	for _, entry := range entries {
+		var name string
+		target := &DispatchWorkflowTarget{}
+
+		switch v := entry.(type) {
+		case string:
+			name = v
+		case map[string]any:
+			name, _ = v["workflow"].(string)
+			target.GitHubToken, _ = v["github-token"].(string)
+			target.Ref, _ = v["ref"].(string)
+		}
+		if name == "" {
+			continue
+		}
+
+		config.Workflows = append(config.Workflows, name)
+		if repo, workflow, ok := splitDispatchWorkflowTarget(name); ok {
+			target.Repo = repo
+			target.Workflow = workflow
+			if config.Targets == nil {
+				config.Targets = make(map[string]*DispatchWorkflowTarget)
+			}
+			config.Targets[name] = target
+		}
+	}
 }
 
 // parseDispatchWorkflowConfig handles dispatch-workflow configuration
@@ -22,11 +79,7 @@ func (c *Compiler) parseDispatchWorkflowConfig(outputMap map[string]any) *Dispat
 		// Check if it's a list of workflow names (array format)
 		if workflowsArray, ok := configData.([]any); ok {
 			dispatchWorkflowLog.Printf("Found dispatch-workflow as array with %d workflows", len(workflowsArray))
-			for _, workflow := range workflowsArray {
-				if workflowStr, ok := workflow.(string); ok {
-					dispatchWorkflowConfig.Workflows = append(dispatchWorkflowConfig.Workflows, workflowStr)
-				}
-			}
+			parseDispatchWorkflowEntries(workflowsArray, dispatchWorkflowConfig)
 			// Set default max to 1
 			dispatchWorkflowConfig.Max = 1
 			return dispatchWorkflowConfig
@@ -39,9 +92,16 @@ func (c *Compiler) parseDispatchWorkflowConfig(outputMap map[string]any) *Dispat
 			// Parse workflows list
 			if workflows, exists := configMap["workflows"]; exists {
 				if workflowsArray, ok := workflows.([]any); ok {
-					for _, workflow := range workflowsArray {
-						if workflowStr, ok := workflow.(string); ok {
-							dispatchWorkflowConfig.Workflows = append(dispatchWorkflowConfig.Workflows, workflowStr)
+					parseDispatchWorkflowEntries(workflowsArray, dispatchWorkflowConfig)
+				}
+			}
+
+			// Parse allowed-repos for cross-repository workflows
+			if allowedRepos, exists := configMap["allowed-repos"]; exists {
+				if reposArray, ok := allowedRepos.([]any); ok {
+					for _, repo := range reposArray {
+						if repoStr, ok := repo.(string); ok {
+							dispatchWorkflowConfig.AllowedRepos = append(dispatchWorkflowConfig.AllowedRepos, repoStr)
 						}
 					}
 				}
@@ -56,11 +116,34 @@ func (c *Compiler) parseDispatchWorkflowConfig(outputMap map[string]any) *Dispat
 				dispatchWorkflowConfig.Max = 50
 			}
 
-			dispatchWorkflowLog.Printf("Parsed dispatch-workflow config: max=%d, workflows=%v",
-				dispatchWorkflowConfig.Max, dispatchWorkflowConfig.Workflows)
+			dispatchWorkflowLog.Printf("Parsed dispatch-workflow config: max=%d, workflows=%v, cross-repo targets=%d",
+				dispatchWorkflowConfig.Max, dispatchWorkflowConfig.Workflows, len(dispatchWorkflowConfig.Targets))
 			return dispatchWorkflowConfig
 		}
 	}
 
 	return nil
 }
+
+// effectiveGitHubToken returns the token used to dispatch a cross-repository workflow:
+// the target's github-token, falling back to the dispatch-workflow github-token
+func (config *DispatchWorkflowConfig) effectiveGitHubToken(target *DispatchWorkflowTarget) string {
+	if target.GitHubToken != "" {
+		return target.GitHubToken
+	}
+	return config.GitHubToken
+}
+
+// dispatchWorkflowTokenEnvVar returns the env var of the safe_outputs handler step holding
+// the token for a cross-repository workflow
+func dispatchWorkflowTokenEnvVar(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return "GH_AW_DISPATCH_WORKFLOW_TOKEN_" + b.String()
+}
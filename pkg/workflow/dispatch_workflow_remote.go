@@ -0,0 +1,184 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var dispatchWorkflowRemoteLog = logger.New("workflow:dispatch_workflow_remote")
+
+// remoteDispatchWorkflow is a workflow file fetched from another repository
+type remoteDispatchWorkflow struct {
+	Content   []byte
+	Extension string // .lock.yml or .yml
+}
+
+// fetchRemoteDispatchWorkflow fetches a workflow file from another repository.
+// It is a variable so tests can replace the GitHub API call.
+var fetchRemoteDispatchWorkflow = fetchRemoteDispatchWorkflowFromGitHub
+
+// fetchRemoteDispatchWorkflowFromGitHub fetches .github/workflows/<workflow>.lock.yml, falling back
+// to <workflow>.yml, from a repository using the gh CLI
+func fetchRemoteDispatchWorkflowFromGitHub(repo, workflow, ref string) (*remoteDispatchWorkflow, error) {
+	var lastErr error
+	for _, extension := range []string{".lock.yml", ".yml"} {
+		apiPath := fmt.Sprintf("repos/%s/contents/.github/workflows/%s%s", repo, url.PathEscape(workflow), extension)
+		if ref != "" {
+			apiPath += "?ref=" + url.QueryEscape(ref)
+		}
+		dispatchWorkflowRemoteLog.Printf("Fetching remote workflow: %s", apiPath)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		output, err := ExecGHContext(ctx, "api", apiPath, "-H", "Accept: application/vnd.github.raw").Output()
+		cancel()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return &remoteDispatchWorkflow{Content: output, Extension: extension}, nil
+	}
+	return nil, fmt.Errorf("could not fetch .github/workflows/%s.lock.yml or %s.yml from %s: %w", workflow, workflow, repo, lastErr)
+}
+
+// getRemoteDispatchWorkflow fetches a workflow from another repository once per compiler and
+// its forks. Failed fetches are not cached.
+func (c *Compiler) getRemoteDispatchWorkflow(repo, workflow, ref string) (*remoteDispatchWorkflow, error) {
+	cache := c.getRemoteDispatchWorkflowCache()
+	cacheKey := repo + "/" + workflow + "@" + ref
+	if cached, ok := cache.Load(cacheKey); ok {
+		return cached.(*remoteDispatchWorkflow), nil
+	}
+
+	remote, err := fetchRemoteDispatchWorkflow(repo, workflow, ref)
+	if err != nil {
+		return nil, err
+	}
+	cache.Store(cacheKey, remote)
+	return remote, nil
+}
+
+// validateCrossRepoDispatchWorkflow validates a dispatch-workflow target in another repository:
+// the repository allowlist, the token used to dispatch, and the target's workflow_dispatch trigger.
+// The target's workflow_dispatch inputs are recorded so the dispatch tool validates agent inputs.
+func (c *Compiler) validateCrossRepoDispatchWorkflow(data *WorkflowData, name string, target *DispatchWorkflowTarget, markdownPath string) error {
+	config := data.SafeOutputs.DispatchWorkflow
+	dispatchWorkflowRemoteLog.Printf("Validating cross-repository workflow: %s (repo=%s, workflow=%s)", name, target.Repo, target.Workflow)
+
+	owner, repoName, _ := strings.Cut(target.Repo, "/")
+	if owner == "" || repoName == "" || target.Workflow == "" {
+		return fmt.Errorf("dispatch-workflow: invalid workflow reference '%s' (expected 'owner/repo/workflow')", name)
+	}
+
+	// Repository allowlist - enforced when configured, required in strict mode
+	if len(config.AllowedRepos) > 0 {
+		if !slices.Contains(config.AllowedRepos, target.Repo) {
+			return fmt.Errorf("dispatch-workflow: repository '%s' of workflow '%s' is not in allowed-repos (%s)", target.Repo, name, strings.Join(config.AllowedRepos, ", "))
+		}
+	} else if c.strictMode {
+		return fmt.Errorf("dispatch-workflow: strict mode requires allowed-repos for cross-repository workflow '%s'\n\nExample:\nsafe-outputs:\n  dispatch-workflow:\n    workflows: [%s]\n    allowed-repos: [%s]", name, name, target.Repo)
+	} else {
+		c.warnAt(RuleStrictDispatchAllowedRepos, markdownPath, fmt.Sprintf("dispatch-workflow: cross-repository workflow '%s' is not restricted by allowed-repos. Add 'allowed-repos: [%s]' (required in strict mode)", name, target.Repo))
+	}
+
+	// Token selection - the default GITHUB_TOKEN cannot dispatch workflows in other repositories
+	token := config.effectiveGitHubToken(target)
+	switch {
+	case token == dispatchWorkflowAppToken:
+		app := data.SafeOutputs.App
+		if app == nil {
+			return fmt.Errorf("dispatch-workflow: workflow '%s' uses github-token: app, but safe-outputs.app is not configured", name)
+		}
+		if app.Owner != "" && !strings.HasPrefix(app.Owner, "${{") && !strings.EqualFold(app.Owner, owner) {
+			return fmt.Errorf("dispatch-workflow: workflow '%s' uses github-token: app, but safe-outputs.app.owner is '%s' instead of '%s'", name, app.Owner, owner)
+		}
+		if !slices.Contains(app.Repositories, repoName) && !slices.Contains(app.Repositories, target.Repo) {
+			return fmt.Errorf("dispatch-workflow: workflow '%s' uses github-token: app, but safe-outputs.app.repositories does not include '%s'\n\nThe app token is scoped to the listed repositories (default: the current repository)", name, repoName)
+		}
+	case token == "" && data.SafeOutputs.App == nil:
+		c.warnAt(RuleDispatchWorkflowToken, markdownPath, fmt.Sprintf("dispatch-workflow: no github-token configured for cross-repository workflow '%s'. The safe outputs token is used, and GITHUB_TOKEN cannot dispatch workflows in other repositories", name))
+	}
+
+	// Fetch the target workflow to check its trigger and record its inputs
+	remote, err := c.getRemoteDispatchWorkflow(target.Repo, target.Workflow, target.Ref)
+	if err != nil {
+		dispatchWorkflowRemoteLog.Printf("Failed to fetch %s: %v", name, err)
+		if c.strictMode {
+			return fmt.Errorf("dispatch-workflow: %w. Strict mode requires cross-repository workflow '%s' to be validated at compile time: check that the repository is readable with the gh CLI credentials", err, name)
+		}
+		c.warnAt(RuleDispatchWorkflowUnvalidated, markdownPath, fmt.Sprintf("dispatch-workflow: %v. Inputs of '%s' will not be validated", err, name))
+		return nil
+	}
+
+	var workflow map[string]any
+	if err := yaml.Unmarshal(remote.Content, &workflow); err != nil {
+		return fmt.Errorf("dispatch-workflow: failed to parse workflow '%s': %w", name, err)
+	}
+	if !hasWorkflowDispatchTrigger(workflow["on"]) {
+		return fmt.Errorf("dispatch-workflow: workflow '%s' does not support workflow_dispatch trigger (must include 'workflow_dispatch' in the 'on' section)", name)
+	}
+
+	if config.WorkflowFiles == nil {
+		config.WorkflowFiles = make(map[string]string)
+	}
+	if config.TargetInputs == nil {
+		config.TargetInputs = make(map[string]map[string]any)
+	}
+	config.WorkflowFiles[name] = remote.Extension
+	config.TargetInputs[name] = workflowDispatchInputs(workflow)
+
+	dispatchWorkflowRemoteLog.Printf("Cross-repository workflow '%s' is valid for dispatch (%s%s, %d inputs)", name, target.Workflow, remote.Extension, len(config.TargetInputs[name]))
+	return nil
+}
+
+// dispatchWorkflowTargetsConfig builds the handler configuration for cross-repository workflows
+func dispatchWorkflowTargetsConfig(config *DispatchWorkflowConfig) map[string]any {
+	if len(config.Targets) == 0 {
+		return nil
+	}
+	targets := make(map[string]any, len(config.Targets))
+	for name, target := range config.Targets {
+		targetConfig := map[string]any{
+			"repo":     target.Repo,
+			"workflow": target.Workflow,
+		}
+		if target.Ref != "" {
+			targetConfig["ref"] = target.Ref
+		}
+		if config.effectiveGitHubToken(target) != "" {
+			targetConfig["token_env"] = dispatchWorkflowTokenEnvVar(name)
+		}
+		targets[name] = targetConfig
+	}
+	return targets
+}
+
+// addDispatchWorkflowTokenEnvVars adds the tokens of cross-repository dispatch targets
+// to the handler step environment
+func (c *Compiler) addDispatchWorkflowTokenEnvVars(steps *[]string, data *WorkflowData) {
+	if data.SafeOutputs == nil || data.SafeOutputs.DispatchWorkflow == nil {
+		return
+	}
+	config := data.SafeOutputs.DispatchWorkflow
+	for _, name := range config.Workflows {
+		target, ok := config.Targets[name]
+		if !ok {
+			continue
+		}
+		token := config.effectiveGitHubToken(target)
+		if token == "" {
+			continue
+		}
+		if token == dispatchWorkflowAppToken {
+			token = "${{ steps.safe-outputs-app-token.outputs.token }}"
+		}
+		*steps = append(*steps, fmt.Sprintf("          %s: %s\n", dispatchWorkflowTokenEnvVar(name), token))
+	}
+}
@@ -0,0 +1,256 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRemoteDispatchWorkflows replaces the GitHub API call that fetches cross-repository workflows
+func stubRemoteDispatchWorkflows(t *testing.T, workflows map[string]string) {
+	t.Helper()
+	original := fetchRemoteDispatchWorkflow
+	fetchRemoteDispatchWorkflow = func(repo, workflow, ref string) (*remoteDispatchWorkflow, error) {
+		content, ok := workflows[repo+"/"+workflow]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return &remoteDispatchWorkflow{Content: []byte(content), Extension: ".lock.yml"}, nil
+	}
+	t.Cleanup(func() { fetchRemoteDispatchWorkflow = original })
+}
+
+const remoteDeployWorkflow = `name: Deploy
+on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        description: Target environment
+        required: true
+        type: choice
+        options: [staging, production]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`
+
+func TestParseDispatchWorkflowCrossRepoEntries(t *testing.T) {
+	compiler := NewCompiler()
+
+	config := compiler.parseDispatchWorkflowConfig(map[string]any{
+		"dispatch-workflow": map[string]any{
+			"workflows": []any{
+				"local-worker",
+				"acme/payments/deploy",
+				map[string]any{
+					"workflow":     "acme/billing/release",
+					"github-token": "app",
+					"ref":          "main",
+				},
+			},
+			"allowed-repos": []any{"acme/payments", "acme/billing"},
+		},
+	})
+
+	require.NotNil(t, config, "config should be parsed")
+	assert.Equal(t, []string{"local-worker", "acme/payments/deploy", "acme/billing/release"}, config.Workflows, "all workflows should be listed")
+	assert.Equal(t, []string{"acme/payments", "acme/billing"}, config.AllowedRepos, "allowed-repos should be parsed")
+	require.Len(t, config.Targets, 2, "only cross-repository workflows should be targets")
+	assert.Equal(t, &DispatchWorkflowTarget{Repo: "acme/payments", Workflow: "deploy"}, config.Targets["acme/payments/deploy"], "string form should be parsed")
+	assert.Equal(t, &DispatchWorkflowTarget{Repo: "acme/billing", Workflow: "release", GitHubToken: "app", Ref: "main"}, config.Targets["acme/billing/release"], "object form should be parsed")
+}
+
+func TestValidateCrossRepoDispatchWorkflow(t *testing.T) {
+	stubRemoteDispatchWorkflows(t, map[string]string{
+		"acme/payments/deploy": remoteDeployWorkflow,
+		"acme/payments/ci":     "on: push\njobs: {}\n",
+	})
+
+	tests := []struct {
+		name        string
+		target      string
+		token       string
+		allowed     []string
+		app         *GitHubAppConfig
+		strict      bool
+		expectedErr string
+	}{
+		{
+			name:    "valid target records inputs",
+			target:  "acme/payments/deploy",
+			token:   "${{ secrets.PAYMENTS_TOKEN }}",
+			allowed: []string{"acme/payments"},
+		},
+		{
+			name:        "repository not allowed",
+			target:      "acme/payments/deploy",
+			allowed:     []string{"acme/billing"},
+			expectedErr: "is not in allowed-repos",
+		},
+		{
+			name:        "strict mode requires allowed-repos",
+			target:      "acme/payments/deploy",
+			strict:      true,
+			expectedErr: "strict mode requires allowed-repos",
+		},
+		{
+			name:        "app token without app",
+			target:      "acme/payments/deploy",
+			token:       "app",
+			allowed:     []string{"acme/payments"},
+			expectedErr: "safe-outputs.app is not configured",
+		},
+		{
+			name:        "app token not scoped to target",
+			target:      "acme/payments/deploy",
+			token:       "app",
+			allowed:     []string{"acme/payments"},
+			app:         &GitHubAppConfig{AppID: "1", PrivateKey: "k", Repositories: []string{"billing"}},
+			expectedErr: "safe-outputs.app.repositories does not include 'payments'",
+		},
+		{
+			name:    "app token scoped to target",
+			target:  "acme/payments/deploy",
+			token:   "app",
+			allowed: []string{"acme/payments"},
+			app:     &GitHubAppConfig{AppID: "1", PrivateKey: "k", Repositories: []string{"payments"}},
+		},
+		{
+			name:        "target without workflow_dispatch",
+			target:      "acme/payments/ci",
+			allowed:     []string{"acme/payments"},
+			expectedErr: "does not support workflow_dispatch trigger",
+		},
+		{
+			name:    "unreachable target is a warning",
+			target:  "acme/payments/missing",
+			allowed: []string{"acme/payments"},
+		},
+		{
+			name:        "unreachable target fails in strict mode",
+			target:      "acme/payments/missing",
+			allowed:     []string{"acme/payments"},
+			strict:      true,
+			expectedErr: "Strict mode requires cross-repository workflow 'acme/payments/missing' to be validated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler()
+			compiler.SetStrictMode(tt.strict)
+
+			config := &DispatchWorkflowConfig{AllowedRepos: tt.allowed}
+			parseDispatchWorkflowEntries([]any{map[string]any{"workflow": tt.target, "github-token": tt.token}}, config)
+			data := &WorkflowData{SafeOutputs: &SafeOutputsConfig{DispatchWorkflow: config, App: tt.app}}
+
+			err := compiler.validateCrossRepoDispatchWorkflow(data, tt.target, config.Targets[tt.target], "test.md")
+			if tt.expectedErr != "" {
+				require.Error(t, err, "validation should fail")
+				assert.Contains(t, err.Error(), tt.expectedErr, "error should explain the problem")
+				return
+			}
+			require.NoError(t, err, "validation should pass")
+			if tt.target == "acme/payments/deploy" {
+				assert.Equal(t, ".lock.yml", config.WorkflowFiles[tt.target], "extension should be recorded")
+				assert.Contains(t, config.TargetInputs[tt.target], "environment", "inputs should be recorded")
+			}
+		})
+	}
+}
+
+func TestRemoteDispatchWorkflowCache(t *testing.T) {
+	fetches := 0
+	original := fetchRemoteDispatchWorkflow
+	fetchRemoteDispatchWorkflow = func(repo, workflow, ref string) (*remoteDispatchWorkflow, error) {
+		fetches++
+		if workflow == "missing" {
+			return nil, errors.New("not found")
+		}
+		return &remoteDispatchWorkflow{Content: []byte(remoteDeployWorkflow), Extension: ".lock.yml"}, nil
+	}
+	t.Cleanup(func() { fetchRemoteDispatchWorkflow = original })
+
+	compiler := NewCompiler()
+	fork := compiler.Fork()
+	_, err := compiler.getRemoteDispatchWorkflow("acme/payments", "deploy", "")
+	require.NoError(t, err, "workflow should be fetched")
+	_, err = fork.getRemoteDispatchWorkflow("acme/payments", "deploy", "")
+	require.NoError(t, err, "workflow should be served from the cache")
+	assert.Equal(t, 1, fetches, "forks should share the cache")
+
+	_, err = NewCompiler().getRemoteDispatchWorkflow("acme/payments", "deploy", "")
+	require.NoError(t, err, "workflow should be fetched")
+	assert.Equal(t, 2, fetches, "compilers should not share the cache")
+
+	_, err = compiler.getRemoteDispatchWorkflow("acme/payments", "missing", "")
+	require.Error(t, err, "missing workflows should fail")
+	_, err = compiler.getRemoteDispatchWorkflow("acme/payments", "missing", "")
+	require.Error(t, err, "missing workflows should fail")
+	assert.Equal(t, 4, fetches, "failed fetches should not be cached")
+}
+
+func TestValidateDispatchWorkflowTokenEnvVars(t *testing.T) {
+	config := &DispatchWorkflowConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{GitHubToken: "${{ secrets.DISPATCH_TOKEN }}"}}
+	parseDispatchWorkflowEntries([]any{"acme/a-b/deploy", "acme/a_b/deploy"}, config)
+	err := validateDispatchWorkflowTokenEnvVars(config)
+	require.Error(t, err, "colliding token env vars should fail")
+	assert.Contains(t, err.Error(), "GH_AW_DISPATCH_WORKFLOW_TOKEN_ACME_A_B_DEPLOY", "error should name the env var")
+
+	config = &DispatchWorkflowConfig{}
+	parseDispatchWorkflowEntries([]any{"acme/a-b/deploy", "acme/a_b/deploy"}, config)
+	require.NoError(t, validateDispatchWorkflowTokenEnvVars(config), "targets without tokens have no env var")
+
+	config = &DispatchWorkflowConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{GitHubToken: "${{ secrets.DISPATCH_TOKEN }}"}}
+	parseDispatchWorkflowEntries([]any{"acme/payments/deploy", "acme/billing/deploy"}, config)
+	require.NoError(t, validateDispatchWorkflowTokenEnvVars(config), "distinct env vars should pass")
+}
+
+func TestDispatchWorkflowCrossRepoCompilation(t *testing.T) {
+	stubRemoteDispatchWorkflows(t, map[string]string{"acme/payments/deploy": remoteDeployWorkflow})
+
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755), "should create workflows directory")
+
+	markdown := `---
+on: issues
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  dispatch-workflow:
+    workflows:
+      - workflow: acme/payments/deploy
+        github-token: ${{ secrets.PAYMENTS_DISPATCH_TOKEN }}
+    allowed-repos: [acme/payments]
+---
+
+# Orchestrator
+
+Fan out deployments.
+`
+	workflowFile := filepath.Join(workflowsDir, "orchestrator.md")
+	require.NoError(t, os.WriteFile(workflowFile, []byte(markdown), 0644), "should write workflow")
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(workflowsDir, "orchestrator.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "GH_AW_DISPATCH_WORKFLOW_TOKEN_ACME_PAYMENTS_DEPLOY: ${{ secrets.PAYMENTS_DISPATCH_TOKEN }}", "handler step should receive the target token")
+	assert.Contains(t, lock, `\"token_env\":\"GH_AW_DISPATCH_WORKFLOW_TOKEN_ACME_PAYMENTS_DEPLOY\"`, "handler config should reference the token env var")
+	assert.Contains(t, lock, `\"allowed_repos\":[\"acme/payments\"]`, "handler config should include the allowlist")
+	assert.Contains(t, lock, `"name": "acme_payments_deploy"`, "dispatch tool should be generated for the target")
+	assert.Contains(t, lock, `"enum": [`, "tool should use the target's choice input")
+}
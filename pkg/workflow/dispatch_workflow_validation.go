@@ -27,6 +27,10 @@ func (c *Compiler) validateDispatchWorkflow(data *WorkflowData, workflowPath str
 		return fmt.Errorf("dispatch-workflow: must specify at least one workflow in the list\n\nExample configuration in workflow frontmatter:\nsafe-outputs:\n  dispatch-workflow:\n    workflows: [workflow-name-1, workflow-name-2]\n\nWorkflow names should match the filename without the .md extension")
 	}
 
+	if err := validateDispatchWorkflowTokenEnvVars(config); err != nil {
+		return err
+	}
+
 	// Get the current workflow name for self-reference check
 	currentWorkflowName := getCurrentWorkflowName(workflowPath)
 	dispatchWorkflowValidationLog.Printf("Current workflow name: %s", currentWorkflowName)
@@ -37,6 +41,23 @@ func (c *Compiler) validateDispatchWorkflow(data *WorkflowData, workflowPath str
 	for _, workflowName := range config.Workflows {
 		dispatchWorkflowValidationLog.Printf("Validating workflow: %s", workflowName)
 
+		// Workflows in other repositories are validated against the target repository
+		if target, isCrossRepo := config.Targets[workflowName]; isCrossRepo {
+			if err := c.validateCrossRepoDispatchWorkflow(data, workflowName, target, workflowPath); err != nil {
+				if returnErr := collector.Add(err); returnErr != nil {
+					return returnErr // Fail-fast mode
+				}
+			}
+			continue
+		}
+		if strings.Contains(workflowName, "/") {
+			formatErr := fmt.Errorf("dispatch-workflow: invalid workflow reference '%s'\n\nUse the workflow name (without extension) for workflows in this repository, or 'owner/repo/workflow' for workflows in other repositories", workflowName)
+			if returnErr := collector.Add(formatErr); returnErr != nil {
+				return returnErr // Fail-fast mode
+			}
+			continue
+		}
+
 		// Check for self-reference
 		if workflowName == currentWorkflowName {
 			selfRefErr := fmt.Errorf("dispatch-workflow: self-reference not allowed (workflow '%s' cannot dispatch itself)\n\nA workflow cannot trigger itself to prevent infinite loops.\nIf you need recurring execution, use a schedule trigger or workflow_dispatch instead", workflowName)
@@ -127,27 +148,7 @@ func (c *Compiler) validateDispatchWorkflow(data *WorkflowData, workflowPath str
 		}
 
 		// Check if workflow_dispatch is in the "on" section
-		hasWorkflowDispatch := false
-		switch on := onSection.(type) {
-		case string:
-			// Simple trigger like "on: push"
-			if on == "workflow_dispatch" {
-				hasWorkflowDispatch = true
-			}
-		case []any:
-			// Array of triggers like "on: [push, workflow_dispatch]"
-			for _, trigger := range on {
-				if triggerStr, ok := trigger.(string); ok && triggerStr == "workflow_dispatch" {
-					hasWorkflowDispatch = true
-					break
-				}
-			}
-		case map[string]any:
-			// Map of triggers like "on: { push: {}, workflow_dispatch: {} }"
-			_, hasWorkflowDispatch = on["workflow_dispatch"]
-		}
-
-		if !hasWorkflowDispatch {
+		if !hasWorkflowDispatchTrigger(onSection) {
 			dispatchErr := fmt.Errorf("dispatch-workflow: workflow '%s' does not support workflow_dispatch trigger (must include 'workflow_dispatch' in the 'on' section)", workflowName)
 			if returnErr := collector.Add(dispatchErr); returnErr != nil {
 				return returnErr // Fail-fast mode
@@ -183,38 +184,64 @@ func extractWorkflowDispatchInputs(workflowPath string) (map[string]any, error)
 		return nil, fmt.Errorf("failed to parse workflow file %s: %w", workflowPath, err)
 	}
 
+	return workflowDispatchInputs(workflow), nil
+}
+
+// hasWorkflowDispatchTrigger reports whether the "on" section of a workflow includes workflow_dispatch
+func hasWorkflowDispatchTrigger(onSection any) bool {
+	switch on := onSection.(type) {
+	case string:
+		// Simple trigger like "on: push"
+		return on == "workflow_dispatch"
+	case []any:
+		// Array of triggers like "on: [push, workflow_dispatch]"
+		for _, trigger := range on {
+			if triggerStr, ok := trigger.(string); ok && triggerStr == "workflow_dispatch" {
+				return true
+			}
+		}
+	case map[string]any:
+		// Map of triggers like "on: { push: {}, workflow_dispatch: {} }"
+		_, hasWorkflowDispatch := on["workflow_dispatch"]
+		return hasWorkflowDispatch
+	}
+	return false
+}
+
+// workflowDispatchInputs returns the workflow_dispatch inputs of a parsed workflow
+func workflowDispatchInputs(workflow map[string]any) map[string]any {
 	// Navigate to workflow_dispatch.inputs
 	onSection, hasOn := workflow["on"]
 	if !hasOn {
-		return make(map[string]any), nil // No inputs
+		return make(map[string]any) // No inputs
 	}
 
 	onMap, ok := onSection.(map[string]any)
 	if !ok {
-		return make(map[string]any), nil // No inputs
+		return make(map[string]any) // No inputs
 	}
 
 	workflowDispatch, hasWorkflowDispatch := onMap["workflow_dispatch"]
 	if !hasWorkflowDispatch {
-		return make(map[string]any), nil // No inputs
+		return make(map[string]any) // No inputs
 	}
 
 	workflowDispatchMap, ok := workflowDispatch.(map[string]any)
 	if !ok {
-		return make(map[string]any), nil // No inputs
+		return make(map[string]any) // No inputs
 	}
 
 	inputs, hasInputs := workflowDispatchMap["inputs"]
 	if !hasInputs {
-		return make(map[string]any), nil // No inputs
+		return make(map[string]any) // No inputs
 	}
 
 	inputsMap, ok := inputs.(map[string]any)
 	if !ok {
-		return make(map[string]any), nil // No inputs
+		return make(map[string]any) // No inputs
 	}
 
-	return inputsMap, nil
+	return inputsMap
 }
 
 // getCurrentWorkflowName extracts the workflow name from the file path
@@ -295,3 +322,22 @@ func findWorkflowFile(workflowName string, currentWorkflowPath string) (*findWor
 
 	return result, nil
 }
+
+// validateDispatchWorkflowTokenEnvVars checks that no two cross-repository workflows with a token
+// share a token env var, which happens when their names only differ in characters that
+// dispatchWorkflowTokenEnvVar replaces (e.g. acme/a-b/deploy and acme/a_b/deploy)
+func validateDispatchWorkflowTokenEnvVars(config *DispatchWorkflowConfig) error {
+	workflowsByEnvVar := make(map[string]string)
+	for _, name := range config.Workflows {
+		target, ok := config.Targets[name]
+		if !ok || config.effectiveGitHubToken(target) == "" {
+			continue
+		}
+		envVar := dispatchWorkflowTokenEnvVar(name)
+		if other, exists := workflowsByEnvVar[envVar]; exists && other != name {
+			return fmt.Errorf("dispatch-workflow: workflows '%s' and '%s' would both pass their token in %s. Their names only differ in characters other than letters and digits; dispatch one of them from a separate workflow", other, name, envVar)
+		}
+		workflowsByEnvVar[envVar] = name
+	}
+	return nil
+}
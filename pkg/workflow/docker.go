@@ -150,6 +150,17 @@ func collectDockerImages(tools map[string]any, workflowData *WorkflowData, actio
 		}
 	}
 
+	// Collect container images used by safe-input tools
+	if workflowData != nil {
+		for _, image := range collectSafeInputContainerImages(workflowData.SafeInputs) {
+			if !imageSet[image] {
+				images = append(images, image)
+				imageSet[image] = true
+				dockerLog.Printf("Added safe-inputs tool container: %s", image)
+			}
+		}
+	}
+
 	// Collect images from custom MCP tools with container configurations
 	for toolName, toolValue := range tools {
 		if mcpConfig, ok := toolValue.(map[string]any); ok {
@@ -124,6 +124,16 @@ func getEcosystemDomains(category string) []string {
 	return result
 }
 
+// GetEcosystemNames returns the sorted ecosystem identifiers accepted in network.allowed
+func GetEcosystemNames() []string {
+	names := make([]string, 0, len(ecosystemDomains))
+	for name := range ecosystemDomains {
+		names = append(names, name)
+	}
+	SortStrings(names)
+	return names
+}
+
 // runtimeToEcosystem maps runtime IDs to their corresponding ecosystem categories in ecosystem_domains.json
 // Some runtimes share ecosystems (e.g., bun and deno use node ecosystem domains)
 var runtimeToEcosystem = map[string]string{
@@ -32,12 +32,10 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 	"sync"
 
-	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 )
 
@@ -238,8 +236,7 @@ func (c *Compiler) validateImportedStepsNoAgenticSecrets(engineConfig *EngineCon
 
 	// Non-strict mode: warning only
 	importedStepsValidationLog.Printf("Non-strict mode: emitting warning for agentic secrets in custom steps")
-	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(errorMsg))
-	c.IncrementWarningCount()
+	c.warn(RuleStrictCustomStepSecrets, errorMsg)
 	return nil
 }
 
@@ -437,6 +437,8 @@ func hasSafeOutputType(config *SafeOutputsConfig, key string) bool {
 		return config.CloseIssues != nil
 	case "close-pull-request":
 		return config.ClosePullRequests != nil
+	case "merge-pull-request":
+		return config.MergePullRequest != nil
 	case "add-comment":
 		return config.AddComments != nil
 	case "create-pull-request":
@@ -451,10 +453,22 @@ func hasSafeOutputType(config *SafeOutputsConfig, key string) bool {
 		return config.ResolvePullRequestReviewThread != nil
 	case "create-code-scanning-alert":
 		return config.CreateCodeScanningAlerts != nil
+	case "create-check-run":
+		return config.CreateCheckRun != nil
+	case "create-commit-status":
+		return config.CreateCommitStatus != nil
 	case "add-labels":
 		return config.AddLabels != nil
 	case "remove-labels":
 		return config.RemoveLabels != nil
+	case "manage-labels":
+		return config.ManageLabels != nil
+	case "lock-conversation":
+		return config.LockConversation != nil
+	case "pin-issue":
+		return config.PinIssue != nil
+	case "transfer-issue":
+		return config.TransferIssue != nil
 	case "add-reviewer":
 		return config.AddReviewer != nil
 	case "assign-milestone":
@@ -471,6 +485,12 @@ func hasSafeOutputType(config *SafeOutputsConfig, key string) bool {
 		return config.UploadAssets != nil
 	case "update-release":
 		return config.UpdateRelease != nil
+	case "create-release":
+		return config.CreateRelease != nil
+	case "create-tag":
+		return config.CreateTag != nil
+	case "update-file":
+		return config.UpdateFile != nil
 	case "create-agent-session":
 		return config.CreateAgentSessions != nil
 	case "create-agent-task": // Backward compatibility
@@ -520,6 +540,9 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 	if result.ClosePullRequests == nil && importedConfig.ClosePullRequests != nil {
 		result.ClosePullRequests = importedConfig.ClosePullRequests
 	}
+	if result.MergePullRequest == nil && importedConfig.MergePullRequest != nil {
+		result.MergePullRequest = importedConfig.MergePullRequest
+	}
 	if result.MarkPullRequestAsReadyForReview == nil && importedConfig.MarkPullRequestAsReadyForReview != nil {
 		result.MarkPullRequestAsReadyForReview = importedConfig.MarkPullRequestAsReadyForReview
 	}
@@ -544,6 +567,12 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 	if result.CreateCodeScanningAlerts == nil && importedConfig.CreateCodeScanningAlerts != nil {
 		result.CreateCodeScanningAlerts = importedConfig.CreateCodeScanningAlerts
 	}
+	if result.CreateCheckRun == nil && importedConfig.CreateCheckRun != nil {
+		result.CreateCheckRun = importedConfig.CreateCheckRun
+	}
+	if result.CreateCommitStatus == nil && importedConfig.CreateCommitStatus != nil {
+		result.CreateCommitStatus = importedConfig.CreateCommitStatus
+	}
 	if result.AutofixCodeScanningAlert == nil && importedConfig.AutofixCodeScanningAlert != nil {
 		result.AutofixCodeScanningAlert = importedConfig.AutofixCodeScanningAlert
 	}
@@ -553,6 +582,9 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 	if result.RemoveLabels == nil && importedConfig.RemoveLabels != nil {
 		result.RemoveLabels = importedConfig.RemoveLabels
 	}
+	if result.ManageLabels == nil && importedConfig.ManageLabels != nil {
+		result.ManageLabels = importedConfig.ManageLabels
+	}
 	if result.AddReviewer == nil && importedConfig.AddReviewer != nil {
 		result.AddReviewer = importedConfig.AddReviewer
 	}
@@ -580,6 +612,15 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 	if result.UpdateRelease == nil && importedConfig.UpdateRelease != nil {
 		result.UpdateRelease = importedConfig.UpdateRelease
 	}
+	if result.CreateRelease == nil && importedConfig.CreateRelease != nil {
+		result.CreateRelease = importedConfig.CreateRelease
+	}
+	if result.CreateTag == nil && importedConfig.CreateTag != nil {
+		result.CreateTag = importedConfig.CreateTag
+	}
+	if result.UpdateFile == nil && importedConfig.UpdateFile != nil {
+		result.UpdateFile = importedConfig.UpdateFile
+	}
 	if result.CreateAgentSessions == nil && importedConfig.CreateAgentSessions != nil {
 		result.CreateAgentSessions = importedConfig.CreateAgentSessions
 	}
@@ -598,6 +639,15 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 	if result.HideComment == nil && importedConfig.HideComment != nil {
 		result.HideComment = importedConfig.HideComment
 	}
+	if result.LockConversation == nil && importedConfig.LockConversation != nil {
+		result.LockConversation = importedConfig.LockConversation
+	}
+	if result.PinIssue == nil && importedConfig.PinIssue != nil {
+		result.PinIssue = importedConfig.PinIssue
+	}
+	if result.TransferIssue == nil && importedConfig.TransferIssue != nil {
+		result.TransferIssue = importedConfig.TransferIssue
+	}
 	if result.DispatchWorkflow == nil && importedConfig.DispatchWorkflow != nil {
 		result.DispatchWorkflow = importedConfig.DispatchWorkflow
 	}
@@ -645,6 +695,21 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 		}
 	}
 
+	// Merge references at consumer level (main workflow rules override imported rules)
+	for consumer, producers := range importedConfig.References {
+		if result.References == nil {
+			result.References = make(map[string][]string)
+		}
+		if _, exists := result.References[consumer]; !exists {
+			result.References[consumer] = producers
+		}
+	}
+
+	// Use the imported content policy only when the main workflow does not define one
+	if result.ContentPolicy == nil && importedConfig.ContentPolicy != nil {
+		result.ContentPolicy = importedConfig.ContentPolicy
+	}
+
 	// NOTE: Jobs are NOT merged here. They are handled separately in compiler_orchestrator.go
 	// via mergeSafeJobsFromIncludedConfigs and extractSafeJobsFromFrontmatter.
 	// The Jobs field is managed independently from other safe-output types to support
@@ -186,3 +186,124 @@ func TestImportInputsExpressionValidation(t *testing.T) {
 		t.Errorf("Expression validation should allow github.aw.inputs.* expressions: %v", err)
 	}
 }
+
+// TestImportWithSnippetTemplates tests that imported snippets are rendered at compile time
+// with typed inputs, {{#each}} and {{else}} blocks, partials and defaults
+func TestImportWithSnippetTemplates(t *testing.T) {
+	tempDir := filepath.Join(testutil.TempDir(t, "test-import-snippets-*"), ".github", "workflows")
+	if err := os.MkdirAll(filepath.Join(tempDir, "shared", "snippets"), 0755); err != nil {
+		t.Fatalf("Failed to create shared directory: %v", err)
+	}
+
+	files := map[string]string{
+		"shared/review.md": `---
+inputs:
+  checks:
+    type: array
+    items: string
+    required: true
+  strict:
+    type: boolean
+    default: false
+---
+
+# Review Guide
+
+{{#each github.aw.inputs.checks}}
+{{> shared/snippets/check.md name=this position=@index}}
+{{/each}}
+
+{{#if github.aw.inputs.strict}}
+Block the pull request on any failure.
+{{else}}
+Comment on failures without blocking.
+{{/if}}
+`,
+		"shared/snippets/check.md": `---
+inputs:
+  name:
+    type: string
+    required: true
+  position:
+    type: number
+---
+- Check ${{ github.aw.inputs.position }}: run the ${{ github.aw.inputs.name }} check.
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	writeWorkflow := func(inputs string) string {
+		workflowPath := filepath.Join(tempDir, "test-workflow.md")
+		workflowContent := `---
+on: pull_request
+permissions:
+  contents: read
+engine: copilot
+imports:
+  - path: shared/review.md
+    inputs:
+` + inputs + `
+---
+
+# Test Workflow
+`
+		if err := os.WriteFile(workflowPath, []byte(workflowContent), 0644); err != nil {
+			t.Fatalf("Failed to write workflow file: %v", err)
+		}
+		return workflowPath
+	}
+
+	workflowPath := writeWorkflow(`      checks: [lint, unit-tests]`)
+	compiler := workflow.NewCompiler()
+	if err := compiler.CompileWorkflow(workflowPath); err != nil {
+		t.Fatalf("CompileWorkflow failed: %v", err)
+	}
+
+	lockFileContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	if err != nil {
+		t.Fatalf("Failed to read lock file: %v", err)
+	}
+	lockContent := string(lockFileContent)
+
+	for _, expected := range []string{
+		"- Check 0: run the lint check.",
+		"- Check 1: run the unit-tests check.",
+	} {
+		if !strings.Contains(lockContent, expected) {
+			t.Errorf("Expected generated workflow to contain rendered snippet line %q", expected)
+		}
+	}
+	for _, unexpected := range []string{"{{#each", "{{>", "github.aw.inputs"} {
+		if strings.Contains(lockContent, unexpected) {
+			t.Errorf("Generated workflow should not contain %q", unexpected)
+		}
+	}
+
+	// Conditionals on inputs are rendered with the rest of the prompt at runtime
+	rendered, err := compiler.RenderPrompt(workflowPath, workflow.PromptRenderOptions{EventName: "pull_request", Repository: "octo/repo", PromptsDir: tempDir})
+	if err != nil {
+		t.Fatalf("RenderPrompt failed: %v", err)
+	}
+	if !strings.Contains(rendered.Text, "Comment on failures without blocking.") {
+		t.Errorf("Expected rendered prompt to contain the else branch, got:\n%s", rendered.Text)
+	}
+	for _, unexpected := range []string{"{{#if", "{{else}}", "Block the pull request"} {
+		if strings.Contains(rendered.Text, unexpected) {
+			t.Errorf("Rendered prompt should not contain %q", unexpected)
+		}
+	}
+
+	// Inputs are type-checked against the definitions of the imported file
+	workflowPath = writeWorkflow(`      checks: lint`)
+	err = workflow.NewCompiler().CompileWorkflow(workflowPath)
+	if err == nil {
+		t.Fatal("Expected compilation to fail for an input of the wrong type")
+	}
+	if !strings.Contains(err.Error(), "input 'checks' must be an array") {
+		t.Errorf("Expected type error for input 'checks', got: %v", err)
+	}
+}
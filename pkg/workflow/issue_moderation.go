@@ -0,0 +1,203 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var issueModerationLog = logger.New("workflow:issue_moderation")
+
+// lockReasons lists the lock reasons accepted by the GitHub lock API
+var lockReasons = []string{"off-topic", "too heated", "resolved", "spam"}
+
+// LockConversationConfig holds configuration for locking issue and pull request conversations from agent output
+type LockConversationConfig struct {
+	BaseSafeOutputConfig   `yaml:",inline"`
+	SafeOutputTargetConfig `yaml:",inline"`
+	SafeOutputFilterConfig `yaml:",inline"`
+	AllowedReasons         []string `yaml:"allowed-reasons,omitempty"` // Lock reasons the agent may use (default: all reasons)
+}
+
+// PinIssueConfig holds configuration for pinning issues from agent output
+type PinIssueConfig struct {
+	BaseSafeOutputConfig   `yaml:",inline"`
+	SafeOutputTargetConfig `yaml:",inline"`
+	SafeOutputFilterConfig `yaml:",inline"`
+}
+
+// TransferIssueConfig holds configuration for transferring issues to other repositories from agent output
+type TransferIssueConfig struct {
+	BaseSafeOutputConfig   `yaml:",inline"`
+	SafeOutputTargetConfig `yaml:",inline"`
+	SafeOutputFilterConfig `yaml:",inline"`
+	AllowedDestinations    []string `yaml:"allowed-destinations,omitempty"` // Repositories ("owner/repo") issues may be transferred to (required)
+}
+
+// parseModerationTargetConfig parses the target, allowed-repos and filter fields shared by the moderation safe outputs.
+// Returns false when target-repo is invalid (e.g., a wildcard).
+func parseModerationTargetConfig(configMap map[string]any, target *SafeOutputTargetConfig, filter *SafeOutputFilterConfig) bool {
+	targetConfig, isInvalid := ParseTargetConfig(configMap)
+	if isInvalid {
+		return false
+	}
+	targetConfig.AllowedRepos = ParseStringArrayFromConfig(configMap, "allowed-repos", issueModerationLog)
+	*target = targetConfig
+	*filter = ParseFilterConfig(configMap)
+	return true
+}
+
+// parseLockConversationConfig handles lock-conversation configuration
+func (c *Compiler) parseLockConversationConfig(outputMap map[string]any) *LockConversationConfig {
+	configData, exists := outputMap["lock-conversation"]
+	if !exists {
+		return nil
+	}
+
+	issueModerationLog.Print("Parsing lock-conversation configuration")
+	config := &LockConversationConfig{}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		if !parseModerationTargetConfig(configMap, &config.SafeOutputTargetConfig, &config.SafeOutputFilterConfig) {
+			return nil // Invalid configuration (e.g., wildcard target-repo), return nil to cause validation error
+		}
+		config.AllowedReasons = ParseStringArrayFromConfig(configMap, "allowed-reasons", issueModerationLog)
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		config.Max = 1
+	}
+
+	issueModerationLog.Printf("Parsed lock-conversation config: max=%d, target=%s, allowed_reasons=%v", config.Max, config.Target, config.AllowedReasons)
+	return config
+}
+
+// parsePinIssueConfig handles pin-issue configuration
+func (c *Compiler) parsePinIssueConfig(outputMap map[string]any) *PinIssueConfig {
+	configData, exists := outputMap["pin-issue"]
+	if !exists {
+		return nil
+	}
+
+	issueModerationLog.Print("Parsing pin-issue configuration")
+	config := &PinIssueConfig{}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		if !parseModerationTargetConfig(configMap, &config.SafeOutputTargetConfig, &config.SafeOutputFilterConfig) {
+			return nil // Invalid configuration (e.g., wildcard target-repo), return nil to cause validation error
+		}
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		config.Max = 1
+	}
+
+	issueModerationLog.Printf("Parsed pin-issue config: max=%d, target=%s", config.Max, config.Target)
+	return config
+}
+
+// parseTransferIssueConfig handles transfer-issue configuration
+func (c *Compiler) parseTransferIssueConfig(outputMap map[string]any) *TransferIssueConfig {
+	configData, exists := outputMap["transfer-issue"]
+	if !exists {
+		return nil
+	}
+
+	issueModerationLog.Print("Parsing transfer-issue configuration")
+	config := &TransferIssueConfig{}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		if !parseModerationTargetConfig(configMap, &config.SafeOutputTargetConfig, &config.SafeOutputFilterConfig) {
+			return nil // Invalid configuration (e.g., wildcard target-repo), return nil to cause validation error
+		}
+		config.AllowedDestinations = ParseStringArrayFromConfig(configMap, "allowed-destinations", issueModerationLog)
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		config.Max = 1
+	}
+
+	issueModerationLog.Printf("Parsed transfer-issue config: max=%d, target=%s, allowed_destinations=%v", config.Max, config.Target, config.AllowedDestinations)
+	return config
+}
+
+// validateModerationSafeOutputs validates lock-conversation, pin-issue and transfer-issue.
+// Transfers always need an explicit destination allowlist. In strict mode, moderation of
+// arbitrary items (target: "*") must be narrowed by required-labels or required-title-prefix,
+// and transfers must use a token that can reach the destination repositories.
+func (c *Compiler) validateModerationSafeOutputs(data *WorkflowData, markdownPath string) error {
+	config := data.SafeOutputs
+	if config == nil || (config.LockConversation == nil && config.PinIssue == nil && config.TransferIssue == nil) {
+		return nil
+	}
+
+	if lock := config.LockConversation; lock != nil {
+		for _, reason := range lock.AllowedReasons {
+			if !slices.Contains(lockReasons, reason) {
+				return fmt.Errorf("safe-outputs.lock-conversation: invalid reason '%s' in allowed-reasons (must be one of: %s)", reason, strings.Join(lockReasons, ", "))
+			}
+		}
+	}
+
+	if transfer := config.TransferIssue; transfer != nil {
+		if len(transfer.AllowedDestinations) == 0 {
+			return fmt.Errorf("safe-outputs.transfer-issue requires allowed-destinations. Example:\n  transfer-issue:\n    allowed-destinations: [\"my-org/docs\"]")
+		}
+		for _, destination := range transfer.AllowedDestinations {
+			owner, repo, ok := strings.Cut(destination, "/")
+			if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") || strings.Contains(destination, "*") {
+				return fmt.Errorf("safe-outputs.transfer-issue: invalid destination '%s' in allowed-destinations (expected 'owner/repo')", destination)
+			}
+		}
+	}
+
+	if !c.strictMode {
+		if config.TransferIssue != nil && !hasExplicitSafeOutputsToken(data) {
+			c.warnAt(RuleStrictTransferIssueToken, markdownPath, "transfer-issue uses the default GITHUB_TOKEN, which cannot write to other repositories. Set safe-outputs.github-token or safe-outputs.app (required in strict mode)")
+		}
+		return nil
+	}
+
+	// Collect targets of all moderation outputs - each with a name for error messages
+	type moderationTarget struct {
+		name   string
+		target string
+		filter SafeOutputFilterConfig
+	}
+
+	var moderationTargets []moderationTarget
+	if lock := config.LockConversation; lock != nil {
+		moderationTargets = append(moderationTargets, moderationTarget{"lock-conversation", lock.Target, lock.SafeOutputFilterConfig})
+	}
+	if pin := config.PinIssue; pin != nil {
+		moderationTargets = append(moderationTargets, moderationTarget{"pin-issue", pin.Target, pin.SafeOutputFilterConfig})
+	}
+	if transfer := config.TransferIssue; transfer != nil {
+		moderationTargets = append(moderationTargets, moderationTarget{"transfer-issue", transfer.Target, transfer.SafeOutputFilterConfig})
+	}
+	for _, mt := range moderationTargets {
+		if mt.target == "*" && len(mt.filter.RequiredLabels) == 0 && mt.filter.RequiredTitlePrefix == "" {
+			return fmt.Errorf("strict mode: safe-outputs.%s with target: \"*\" requires required-labels or required-title-prefix to limit which items can be moderated", mt.name)
+		}
+	}
+
+	if config.TransferIssue != nil && !hasExplicitSafeOutputsToken(data) {
+		return fmt.Errorf("strict mode: safe-outputs.transfer-issue requires safe-outputs.github-token or safe-outputs.app because the default GITHUB_TOKEN cannot write to the destination repositories")
+	}
+
+	issueModerationLog.Print("Moderation safe outputs passed strict mode validation")
+	return nil
+}
+
+// hasExplicitSafeOutputsToken reports whether the safe outputs job runs with a token other than the default GITHUB_TOKEN
+func hasExplicitSafeOutputsToken(data *WorkflowData) bool {
+	return data.GitHubToken != "" || (data.SafeOutputs != nil && (data.SafeOutputs.GitHubToken != "" || data.SafeOutputs.App != nil))
+}
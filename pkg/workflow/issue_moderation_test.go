@@ -0,0 +1,184 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModerationConfigs(t *testing.T) {
+	outputMap := map[string]any{
+		"lock-conversation": map[string]any{
+			"target":          "*",
+			"required-labels": []any{"moderation"},
+			"allowed-reasons": []any{"too heated", "spam"},
+		},
+		"pin-issue": nil,
+		"transfer-issue": map[string]any{
+			"required-title-prefix": "[docs]",
+			"allowed-destinations":  []any{"octo/docs"},
+			"max":                   3,
+		},
+	}
+
+	compiler := NewCompiler()
+
+	assert.Equal(t, &LockConversationConfig{
+		BaseSafeOutputConfig:   BaseSafeOutputConfig{Max: 1},
+		SafeOutputTargetConfig: SafeOutputTargetConfig{Target: "*"},
+		SafeOutputFilterConfig: SafeOutputFilterConfig{RequiredLabels: []string{"moderation"}},
+		AllowedReasons:         []string{"too heated", "spam"},
+	}, compiler.parseLockConversationConfig(outputMap), "lock-conversation config should be parsed")
+
+	assert.Equal(t, &PinIssueConfig{
+		BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+	}, compiler.parsePinIssueConfig(outputMap), "null pin-issue config should use defaults")
+
+	assert.Equal(t, &TransferIssueConfig{
+		BaseSafeOutputConfig:   BaseSafeOutputConfig{Max: 3},
+		SafeOutputFilterConfig: SafeOutputFilterConfig{RequiredTitlePrefix: "[docs]"},
+		AllowedDestinations:    []string{"octo/docs"},
+	}, compiler.parseTransferIssueConfig(outputMap), "transfer-issue config should be parsed")
+
+	assert.Nil(t, compiler.parseTransferIssueConfig(map[string]any{}), "missing config should return nil")
+	assert.Nil(t, compiler.parseLockConversationConfig(map[string]any{"lock-conversation": map[string]any{"target-repo": "*"}}), "wildcard target-repo should be rejected")
+}
+
+func TestValidateModerationSafeOutputs(t *testing.T) {
+	tests := []struct {
+		name        string
+		strict      bool
+		safeOutputs *SafeOutputsConfig
+		wantErr     string
+	}{
+		{
+			name: "valid lock reasons",
+			safeOutputs: &SafeOutputsConfig{
+				LockConversation: &LockConversationConfig{AllowedReasons: []string{"resolved"}},
+			},
+		},
+		{
+			name: "invalid lock reason",
+			safeOutputs: &SafeOutputsConfig{
+				LockConversation: &LockConversationConfig{AllowedReasons: []string{"boring"}},
+			},
+			wantErr: "invalid reason 'boring'",
+		},
+		{
+			name: "transfer without destinations",
+			safeOutputs: &SafeOutputsConfig{
+				TransferIssue: &TransferIssueConfig{},
+			},
+			wantErr: "requires allowed-destinations",
+		},
+		{
+			name: "transfer with wildcard destination",
+			safeOutputs: &SafeOutputsConfig{
+				TransferIssue: &TransferIssueConfig{AllowedDestinations: []string{"octo/*"}},
+			},
+			wantErr: "invalid destination 'octo/*'",
+		},
+		{
+			name:   "strict mode rejects unfiltered wildcard target",
+			strict: true,
+			safeOutputs: &SafeOutputsConfig{
+				PinIssue: &PinIssueConfig{SafeOutputTargetConfig: SafeOutputTargetConfig{Target: "*"}},
+			},
+			wantErr: "pin-issue with target: \"*\" requires required-labels or required-title-prefix",
+		},
+		{
+			name:   "strict mode accepts filtered wildcard target",
+			strict: true,
+			safeOutputs: &SafeOutputsConfig{
+				LockConversation: &LockConversationConfig{
+					SafeOutputTargetConfig: SafeOutputTargetConfig{Target: "*"},
+					SafeOutputFilterConfig: SafeOutputFilterConfig{RequiredLabels: []string{"moderation"}},
+				},
+			},
+		},
+		{
+			name:   "strict mode requires a token for transfers",
+			strict: true,
+			safeOutputs: &SafeOutputsConfig{
+				TransferIssue: &TransferIssueConfig{AllowedDestinations: []string{"octo/docs"}},
+			},
+			wantErr: "requires safe-outputs.github-token or safe-outputs.app",
+		},
+		{
+			name:   "strict mode accepts transfers with a token",
+			strict: true,
+			safeOutputs: &SafeOutputsConfig{
+				GitHubToken:   "${{ secrets.MODERATION_TOKEN }}",
+				TransferIssue: &TransferIssueConfig{AllowedDestinations: []string{"octo/docs"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler()
+			compiler.strictMode = tt.strict
+			err := compiler.validateModerationSafeOutputs(&WorkflowData{SafeOutputs: tt.safeOutputs}, "test.md")
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "config should be valid")
+				return
+			}
+			require.Error(t, err, "config should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error should explain the problem")
+		})
+	}
+}
+
+func TestModerationSafeOutputsCompilation(t *testing.T) {
+	markdown := `---
+on:
+  issues:
+    types: [opened]
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  github-token: ${{ secrets.MODERATION_TOKEN }}
+  lock-conversation:
+    allowed-reasons: ["too heated", "spam"]
+  pin-issue:
+    target: "*"
+    required-labels: [announcement]
+  transfer-issue:
+    allowed-destinations: ["octo/docs"]
+---
+
+# Moderator
+
+Moderate the new issue.
+`
+
+	tmpDir := testutil.TempDir(t, "moderation-*")
+	testFile := filepath.Join(tmpDir, "moderator.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "moderator.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `\"lock_conversation\":{\"allowed_reasons\":[\"too heated\",\"spam\"],\"max\":1}`, "lock_conversation handler config should be generated")
+	assert.Contains(t, lock, `\"pin_issue\":{\"max\":1,\"required_labels\":[\"announcement\"],\"target\":\"*\"}`, "pin_issue handler config should be generated")
+	assert.Contains(t, lock, `\"transfer_issue\":{\"allowed_destinations\":[\"octo/docs\"],\"max\":1}`, "transfer_issue handler config should be generated")
+	for _, tool := range []string{"lock_conversation", "pin_issue", "transfer_issue"} {
+		assert.Contains(t, lock, `"name": "`+tool+`"`, "%s tool should be generated", tool)
+	}
+
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	jobHeader := safeOutputsJob[:strings.Index(safeOutputsJob, "    steps:")]
+	assert.Contains(t, jobHeader, "issues: write", "safe_outputs job should have issues: write")
+	assert.Contains(t, jobHeader, "pull-requests: write", "safe_outputs job should have pull-requests: write for locking PR conversations")
+}
@@ -0,0 +1,467 @@
+// This file provides a security linter for compiled lock files.
+//
+// # Lock File Security Linter
+//
+// The linter checks the generated GitHub Actions YAML for patterns that are dangerous in
+// agentic workflows. It runs in-process (gh aw compile --lint), so it works where the
+// Docker-based zizmor and poutine scanners cannot be pulled. Each rule has a stable ID:
+//
+//   - untrusted-expression-in-run: untrusted ${{ }} expressions interpolated into run
+//     scripts or github-script code, where they can inject commands
+//   - excessive-permissions: write-all permissions, default token permissions, or write
+//     scopes granted to the agent job
+//   - unpinned-action: actions and reusable workflows not pinned to a commit SHA, docker
+//     images not pinned to a digest
+//   - pull-request-target-checkout: checkouts of the pull request head in workflows
+//     triggered by pull_request_target or workflow_run
+//   - secrets-in-agent-job: secrets other than engine credentials visible to the agent process
+//   - artifact-poisoning: artifacts extracted into the workspace of a job with write
+//     permissions or secrets
+//
+// # Usage
+//
+// Call LintLockFile(content) with the content of a .lock.yml file. Findings carry the line
+// and column of the offending YAML node so they can be reported like compiler warnings.
+
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+var lockFileLintLog = logger.New("workflow:lock_file_security_lint")
+
+// LockFileLintRule is the stable ID of a lock file security linter rule
+type LockFileLintRule string
+
+const (
+	// LintRuleUntrustedExpressionInRun flags untrusted expressions interpolated into scripts
+	LintRuleUntrustedExpressionInRun LockFileLintRule = "untrusted-expression-in-run"
+	// LintRuleExcessivePermissions flags write-all, default and agent job write permissions
+	LintRuleExcessivePermissions LockFileLintRule = "excessive-permissions"
+	// LintRuleUnpinnedAction flags actions, reusable workflows and images not pinned immutably
+	LintRuleUnpinnedAction LockFileLintRule = "unpinned-action"
+	// LintRulePullRequestTargetCheckout flags checkouts of untrusted pull request heads
+	LintRulePullRequestTargetCheckout LockFileLintRule = "pull-request-target-checkout"
+	// LintRuleSecretsInAgentJob flags secrets visible to the agent process
+	LintRuleSecretsInAgentJob LockFileLintRule = "secrets-in-agent-job"
+	// LintRuleArtifactPoisoning flags artifacts extracted into the workspace of privileged jobs
+	LintRuleArtifactPoisoning LockFileLintRule = "artifact-poisoning"
+)
+
+// LockFileLintRuleInfo describes a linter rule
+type LockFileLintRuleInfo struct {
+	ID          LockFileLintRule
+	Severity    string // Default severity: "error" or "warning"
+	Description string
+}
+
+var lockFileLintRules = []LockFileLintRuleInfo{
+	{LintRuleUntrustedExpressionInRun, "error", "Untrusted expression interpolated into a script"},
+	{LintRuleExcessivePermissions, "warning", "Job permissions broader than needed"},
+	{LintRuleUnpinnedAction, "warning", "Action not pinned to a commit SHA"},
+	{LintRulePullRequestTargetCheckout, "error", "Pull request head checked out in a privileged workflow"},
+	{LintRuleSecretsInAgentJob, "warning", "Secret exposed to the agent process"},
+	{LintRuleArtifactPoisoning, "warning", "Artifact extracted into the workspace of a privileged job"},
+}
+
+// LockFileLintRules returns the rules of the lock file security linter
+func LockFileLintRules() []LockFileLintRuleInfo {
+	return append([]LockFileLintRuleInfo(nil), lockFileLintRules...)
+}
+
+// LockFileLintFinding is a single issue found by the lock file security linter
+type LockFileLintFinding struct {
+	Rule     LockFileLintRule
+	Severity string // "error" or "warning"
+	Line     int    // 1-based line of the offending node
+	Column   int    // 1-based column of the offending node
+	Message  string
+	Hint     string // How to fix the issue
+}
+
+// String returns a human-readable description of the finding
+func (f LockFileLintFinding) String() string {
+	return fmt.Sprintf("line %d: [%s] %s", f.Line, f.Rule, f.Message)
+}
+
+var (
+	// untrustedContextRegex matches context references whose values can be controlled by
+	// whoever triggers the workflow
+	untrustedContextRegex = regexp.MustCompile(`github\.event(?:\.[A-Za-z0-9_-]+|\[[^\]]*\])+|github\.head_ref|(?:steps|needs)\.[A-Za-z0-9_-]+\.outputs\.[A-Za-z0-9_-]+|inputs\.[A-Za-z0-9_-]+`)
+
+	// pullRequestHeadRegex matches checkout refs and repositories that point at the pull request head
+	pullRequestHeadRegex = regexp.MustCompile(`github\.event\.pull_request\.head\.|github\.head_ref|github\.event\.workflow_run\.head_|github\.event\.workflow_run\.pull_requests|refs/pull/`)
+
+	// secretReferenceRegex matches secret references in expressions
+	secretReferenceRegex = regexp.MustCompile(`secrets\.([A-Za-z0-9_]+)`)
+
+	// commitSHARegex matches a full-length commit SHA
+	commitSHARegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// LintLockFile checks the content of a compiled lock file against the security linter rules.
+// Findings are sorted by position.
+func LintLockFile(content []byte) ([]LockFileLintFinding, error) {
+	file, err := parser.ParseBytes(content, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	if len(file.Docs) == 0 || file.Docs[0].Body == nil {
+		return nil, nil
+	}
+
+	root := file.Docs[0].Body
+	l := &lockFileLinter{
+		lines:               strings.Split(string(content), "\n"),
+		workflowPermissions: lintLookup(root, "permissions") != nil,
+	}
+	l.checkPermissions(root)
+
+	privilegedTrigger := lintPrivilegedTrigger(lintLookup(root, "on"))
+	if jobs := lintLookup(root, "jobs"); jobs != nil {
+		for _, job := range lintEntries(jobs.Value) {
+			l.checkJob(lintKey(job), job, privilegedTrigger)
+		}
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		if l.findings[i].Line != l.findings[j].Line {
+			return l.findings[i].Line < l.findings[j].Line
+		}
+		return l.findings[i].Column < l.findings[j].Column
+	})
+	lockFileLintLog.Printf("Lock file lint found %d issue(s)", len(l.findings))
+	return l.findings, nil
+}
+
+// lockFileLinter collects findings for one lock file
+type lockFileLinter struct {
+	lines               []string
+	workflowPermissions bool // Whether the workflow sets top-level permissions
+	findings            []LockFileLintFinding
+}
+
+// report records a finding at the position of node with the rule's default severity
+func (l *lockFileLinter) report(rule LockFileLintRule, node ast.Node, message, hint string) {
+	pos := node.GetToken().Position
+	l.reportAt(rule, pos.Line, pos.Column, message, hint)
+}
+
+// reportAt records a finding at a line and column with the rule's default severity
+func (l *lockFileLinter) reportAt(rule LockFileLintRule, line, column int, message, hint string) {
+	severity := "warning"
+	for _, info := range lockFileLintRules {
+		if info.ID == rule {
+			severity = info.Severity
+		}
+	}
+	l.findings = append(l.findings, LockFileLintFinding{
+		Rule:     rule,
+		Severity: severity,
+		Line:     line,
+		Column:   column,
+		Message:  message,
+		Hint:     hint,
+	})
+}
+
+// checkPermissions flags write-all at the workflow level
+func (l *lockFileLinter) checkPermissions(root ast.Node) {
+	if permissions := lintLookup(root, "permissions"); permissions != nil {
+		if value, _ := lintScalar(permissions.Value); value == "write-all" {
+			l.report(LintRuleExcessivePermissions, permissions.Value, "permissions: write-all grants every job write access to all scopes",
+				"Grant only the scopes each job needs, and keep the agent job read-only")
+		}
+	}
+}
+
+// checkJob applies the job and step rules to one job
+func (l *lockFileLinter) checkJob(jobID string, job *ast.MappingValueNode, privilegedTrigger string) {
+	jobWrites := false
+	permissions := lintLookup(job.Value, "permissions")
+	switch {
+	case permissions == nil:
+		// Jobs inherit the workflow permissions; without them the default token permissions apply
+		if lintLookup(job.Value, "uses") == nil && !l.workflowPermissions {
+			l.report(LintRuleExcessivePermissions, job.Key, fmt.Sprintf("job %q uses the default GITHUB_TOKEN permissions", jobID),
+				"Set permissions on the workflow or the job; the default permissions may include write access")
+		}
+	default:
+		if value, ok := lintScalar(permissions.Value); ok {
+			if value == "write-all" {
+				jobWrites = true
+				l.report(LintRuleExcessivePermissions, permissions.Value, fmt.Sprintf("job %q has permissions: write-all", jobID),
+					"Grant only the scopes the job needs")
+			}
+			break
+		}
+		for _, scope := range lintEntries(permissions.Value) {
+			if value, _ := lintScalar(scope.Value); value == "write" {
+				jobWrites = true
+				if jobID == string(constants.AgentJobName) {
+					l.report(LintRuleExcessivePermissions, scope.Value, fmt.Sprintf("agent job has %s: write permission", lintKey(scope)),
+						"The agent processes untrusted input; keep it read-only and perform writes through safe-outputs")
+				}
+			}
+		}
+	}
+
+	if uses := lintLookup(job.Value, "uses"); uses != nil {
+		l.checkUses(uses.Value)
+	}
+
+	steps := lintLookup(job.Value, "steps")
+	if steps == nil {
+		return
+	}
+	stepNodes := lintItems(steps.Value)
+
+	// Secrets are visible to the agent process through the job env and the step running the engine
+	runsAgent := false
+	for _, step := range stepNodes {
+		if id := lintLookup(step, "id"); id != nil {
+			if value, _ := lintScalar(id.Value); value == "agentic_execution" {
+				runsAgent = true
+				l.checkAgentSecrets(lintLookup(step, "env"), "the agent process")
+			}
+		}
+	}
+	if runsAgent {
+		l.checkAgentSecrets(lintLookup(job.Value, "env"), fmt.Sprintf("every step of job %q, including the agent", jobID))
+	}
+
+	privileged := jobWrites || secretReferenceRegex.MatchString(job.Value.String())
+	for _, step := range stepNodes {
+		l.checkStep(step, privilegedTrigger, privileged)
+	}
+}
+
+// checkStep applies the step rules to one step
+func (l *lockFileLinter) checkStep(step ast.Node, privilegedTrigger string, privilegedJob bool) {
+	uses := ""
+	if usesNode := lintLookup(step, "uses"); usesNode != nil {
+		uses, _ = lintScalar(usesNode.Value)
+		l.checkUses(usesNode.Value)
+	}
+	with := lintLookup(step, "with")
+
+	if run := lintLookup(step, "run"); run != nil {
+		l.checkScript(run.Value, "a run script", "reference it as \"$VALUE\" in the script")
+	}
+	if strings.HasPrefix(uses, "actions/github-script@") && with != nil {
+		if script := lintLookup(with.Value, "script"); script != nil {
+			l.checkScript(script.Value, "github-script code", "read it with process.env.VALUE in the script")
+		}
+	}
+
+	if privilegedTrigger != "" && strings.HasPrefix(uses, "actions/checkout@") && with != nil {
+		for _, key := range []string{"ref", "repository"} {
+			if input := lintLookup(with.Value, key); input != nil {
+				if value, _ := lintScalar(input.Value); pullRequestHeadRegex.MatchString(value) {
+					l.report(LintRulePullRequestTargetCheckout, input.Value,
+						fmt.Sprintf("actions/checkout checks out the pull request head in a workflow triggered by %s, which runs with the base repository's secrets and permissions", privilegedTrigger),
+						"Use the pull_request trigger to build pull request code, or do not run code from this checkout")
+					break
+				}
+			}
+		}
+	}
+
+	if privilegedJob && strings.HasPrefix(uses, "actions/download-artifact@") {
+		path := ""
+		if with != nil {
+			if input := lintLookup(with.Value, "path"); input != nil {
+				path, _ = lintScalar(input.Value)
+			}
+		}
+		if isWorkspaceRoot(path) {
+			l.report(LintRuleArtifactPoisoning, lintLookup(step, "uses").Value,
+				"artifact is extracted into the workspace of a job with write permissions or secrets; files from an earlier job can overwrite code that later steps run",
+				"Download the artifact into a directory outside the workspace, for example path: /tmp/gh-aw/artifacts/")
+		}
+	}
+}
+
+// checkUses flags action and reusable workflow references that are not pinned immutably
+func (l *lockFileLinter) checkUses(node ast.Node) {
+	uses, ok := lintScalar(node)
+	if !ok || uses == "" || strings.HasPrefix(uses, "./") {
+		return
+	}
+	if image, isDocker := strings.CutPrefix(uses, "docker://"); isDocker {
+		if !strings.Contains(image, "@sha256:") {
+			l.report(LintRuleUnpinnedAction, node, fmt.Sprintf("docker image %s is not pinned to a digest", image),
+				"Pin the image with @sha256:<digest> so the step cannot change without a lock file change")
+		}
+		return
+	}
+	_, ref, _ := strings.Cut(uses, "@")
+	if !commitSHARegex.MatchString(ref) {
+		l.report(LintRuleUnpinnedAction, node, fmt.Sprintf("%s is not pinned to a commit SHA", uses),
+			"Pin the action to a full commit SHA, for example uses: owner/repo@<sha> # v1")
+	}
+}
+
+// checkScript flags untrusted expressions interpolated into a run script or github-script code
+func (l *lockFileLinter) checkScript(node ast.Node, kind, readHint string) {
+	script, ok := lintScalar(node)
+	if !ok {
+		return
+	}
+	line, column := node.GetToken().Position.Line, 0
+	for _, expression := range inlineExpressionRegex.FindAllString(script, -1) {
+		line, column = l.locate(line, column, expression)
+		untrusted := ""
+		for _, reference := range untrustedContextRegex.FindAllString(expression, -1) {
+			if !isTrustedEventField(reference) {
+				untrusted = reference
+				break
+			}
+		}
+		if untrusted == "" {
+			continue
+		}
+		l.reportAt(LintRuleUntrustedExpressionInRun, line, column,
+			fmt.Sprintf("untrusted expression %s is interpolated into %s and can inject code", expression, kind),
+			fmt.Sprintf("Pass %s through an environment variable (env: VALUE: %s) and %s", untrusted, expression, readHint))
+	}
+}
+
+// checkAgentSecrets flags secrets in env that the agent process can read. Engine credentials
+// and the workflow token are expected there.
+func (l *lockFileLinter) checkAgentSecrets(env *ast.MappingValueNode, exposedTo string) {
+	if env == nil {
+		return
+	}
+	engineSecrets := getAgenticEngineSecrets()
+	for _, variable := range lintEntries(env.Value) {
+		value, _ := lintScalar(variable.Value)
+		for _, match := range secretReferenceRegex.FindAllStringSubmatch(value, -1) {
+			secret := match[1]
+			if _, isEngineSecret := engineSecrets[secret]; isEngineSecret || secret == "GITHUB_TOKEN" {
+				continue
+			}
+			l.report(LintRuleSecretsInAgentJob, variable.Value, fmt.Sprintf("secret %s is exposed to %s", secret, exposedTo),
+				"The agent can read its environment; pass the secret to an MCP server or a safe-outputs job instead")
+		}
+	}
+}
+
+// locate returns the position of text in the lock file, searching from the given position
+func (l *lockFileLinter) locate(line, column int, text string) (int, int) {
+	for i := max(line, 1); i <= len(l.lines); i++ {
+		from := 0
+		if i == line && column > 0 {
+			from = column
+		}
+		if from <= len(l.lines[i-1]) {
+			if index := strings.Index(l.lines[i-1][from:], text); index >= 0 {
+				return i, from + index + 1
+			}
+		}
+	}
+	return line, column
+}
+
+// isTrustedEventField reports whether an event field cannot carry attacker-controlled text,
+// such as issue numbers, IDs and commit SHAs
+func isTrustedEventField(reference string) bool {
+	if !strings.HasPrefix(reference, "github.event.") {
+		return false
+	}
+	field := reference[strings.LastIndex(reference, ".")+1:]
+	return field == "number" || field == "id" || field == "sha" ||
+		strings.HasSuffix(field, "_id") || strings.HasSuffix(field, "_number") || strings.HasSuffix(field, "_sha")
+}
+
+// isWorkspaceRoot reports whether a download-artifact path extracts into the workspace root
+func isWorkspaceRoot(path string) bool {
+	path = strings.TrimSuffix(strings.TrimSpace(path), "/")
+	switch path {
+	case "", ".", "${{ github.workspace }}", "$GITHUB_WORKSPACE", "${GITHUB_WORKSPACE}":
+		return true
+	}
+	return false
+}
+
+// lintPrivilegedTrigger returns the trigger that runs untrusted pull request code with the
+// base repository's privileges, or "" if the workflow has none
+func lintPrivilegedTrigger(on *ast.MappingValueNode) string {
+	if on == nil {
+		return ""
+	}
+	var triggers []string
+	if value, ok := lintScalar(on.Value); ok {
+		triggers = append(triggers, value)
+	}
+	for _, item := range lintItems(on.Value) {
+		if value, ok := lintScalar(item); ok {
+			triggers = append(triggers, value)
+		}
+	}
+	for _, entry := range lintEntries(on.Value) {
+		triggers = append(triggers, lintKey(entry))
+	}
+	for _, trigger := range triggers {
+		if trigger == "pull_request_target" || trigger == "workflow_run" {
+			return trigger
+		}
+	}
+	return ""
+}
+
+// lintEntries returns the key-value pairs of a mapping node
+func lintEntries(node ast.Node) []*ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	}
+	return nil
+}
+
+// lintItems returns the items of a sequence node
+func lintItems(node ast.Node) []ast.Node {
+	if n, ok := node.(*ast.SequenceNode); ok {
+		return n.Values
+	}
+	return nil
+}
+
+// lintLookup returns the entry of a mapping node with the given key
+func lintLookup(node ast.Node, key string) *ast.MappingValueNode {
+	for _, entry := range lintEntries(node) {
+		if lintKey(entry) == key {
+			return entry
+		}
+	}
+	return nil
+}
+
+// lintKey returns the key of a mapping entry
+func lintKey(entry *ast.MappingValueNode) string {
+	return entry.Key.GetToken().Value
+}
+
+// lintScalar returns the string value of a scalar node
+func lintScalar(node ast.Node) (string, bool) {
+	switch n := node.(type) {
+	case *ast.StringNode:
+		return n.Value, true
+	case *ast.LiteralNode:
+		return n.Value.Value, true
+	case *ast.MappingNode, *ast.MappingValueNode, *ast.SequenceNode, nil:
+		return "", false
+	}
+	return node.GetToken().Value, true
+}
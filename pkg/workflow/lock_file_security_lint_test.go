@@ -0,0 +1,261 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lintTestPinnedCheckout = "actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd"
+
+func TestLintLockFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantRule   LockFileLintRule
+		wantLine   int
+		wantColumn int
+		wantText   string
+	}{
+		{
+			name: "untrusted expression in run",
+			content: `permissions: {}
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: |
+          echo "start"
+          echo "${{ github.event.issue.title }}"
+`,
+			wantRule:   LintRuleUntrustedExpressionInRun,
+			wantLine:   8,
+			wantColumn: 17,
+			wantText:   "github.event.issue.title",
+		},
+		{
+			name: "untrusted step output in github-script",
+			content: `permissions: {}
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/github-script@ed597411d8f924073f98dfc5c65a23a2325f34cd
+        with:
+          script: |
+            const body = "${{ steps.agent.outputs.text }}";
+`,
+			wantRule: LintRuleUntrustedExpressionInRun,
+			wantLine: 9,
+			wantText: "github-script code",
+		},
+		{
+			name: "workflow write-all",
+			content: `permissions: write-all
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ok
+`,
+			wantRule: LintRuleExcessivePermissions,
+			wantLine: 1,
+			wantText: "write-all",
+		},
+		{
+			name: "default token permissions",
+			content: `jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ok
+`,
+			wantRule: LintRuleExcessivePermissions,
+			wantLine: 2,
+			wantText: "default GITHUB_TOKEN permissions",
+		},
+		{
+			name: "agent job write permission",
+			content: `permissions: {}
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+      issues: write
+    steps:
+      - run: echo ok
+`,
+			wantRule: LintRuleExcessivePermissions,
+			wantLine: 7,
+			wantText: "issues: write",
+		},
+		{
+			name: "unpinned action",
+			content: `permissions: {}
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/setup-node@v4
+`,
+			wantRule: LintRuleUnpinnedAction,
+			wantLine: 6,
+			wantText: "actions/setup-node@v4",
+		},
+		{
+			name: "docker image without digest",
+			content: `permissions: {}
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: docker://alpine:3.20
+`,
+			wantRule: LintRuleUnpinnedAction,
+			wantLine: 6,
+			wantText: "not pinned to a digest",
+		},
+		{
+			name: "pull_request_target checkout of head",
+			content: `"on":
+  pull_request_target:
+    types: [opened]
+permissions: {}
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ` + lintTestPinnedCheckout + `
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+`,
+			wantRule: LintRulePullRequestTargetCheckout,
+			wantLine: 11,
+			wantText: "pull_request_target",
+		},
+		{
+			name: "non-engine secret in agent step",
+			content: `permissions: {}
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    steps:
+      - name: Execute agent
+        id: agentic_execution
+        run: agent
+        env:
+          COPILOT_GITHUB_TOKEN: ${{ secrets.COPILOT_GITHUB_TOKEN }}
+          TAVILY_API_KEY: ${{ secrets.TAVILY_API_KEY }}
+`,
+			wantRule: LintRuleSecretsInAgentJob,
+			wantLine: 11,
+			wantText: "TAVILY_API_KEY",
+		},
+		{
+			name: "secret in agent job env",
+			content: `permissions: {}
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    env:
+      DEPLOY_KEY: ${{ secrets.DEPLOY_KEY }}
+    steps:
+      - id: agentic_execution
+        run: agent
+`,
+			wantRule: LintRuleSecretsInAgentJob,
+			wantLine: 6,
+			wantText: "including the agent",
+		},
+		{
+			name: "artifact into workspace of write job",
+			content: `permissions: {}
+jobs:
+  publish:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: write
+    steps:
+      - uses: actions/download-artifact@018cc2cf5baa6db3ef3c5f8a56943fffe632ef53
+        with:
+          name: agent-output
+      - run: ./publish.sh
+`,
+			wantRule: LintRuleArtifactPoisoning,
+			wantLine: 8,
+			wantText: "outside the workspace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := LintLockFile([]byte(tt.content))
+			require.NoError(t, err, "lock file should parse")
+			require.Len(t, findings, 1, "should report exactly one finding: %v", findings)
+
+			finding := findings[0]
+			assert.Equal(t, tt.wantRule, finding.Rule, "finding should have the expected rule")
+			assert.Equal(t, tt.wantLine, finding.Line, "finding should point at the offending line")
+			if tt.wantColumn > 0 {
+				assert.Equal(t, tt.wantColumn, finding.Column, "finding should point at the offending column")
+			}
+			assert.Contains(t, finding.Message+" "+finding.Hint, tt.wantText, "finding should describe the issue")
+			assert.NotEmpty(t, finding.Hint, "finding should explain how to fix the issue")
+		})
+	}
+}
+
+func TestLintLockFileSafePatterns(t *testing.T) {
+	content := `"on":
+  pull_request_target:
+permissions: {}
+jobs:
+  agent:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+    steps:
+      - uses: ` + lintTestPinnedCheckout + `
+      - uses: ./actions/setup
+      - name: Use env
+        env:
+          TITLE: ${{ github.event.issue.title }}
+        run: |
+          echo "$TITLE"
+          echo "${{ github.event.issue.number }} ${{ github.run_id }}"
+      - id: agentic_execution
+        run: agent
+        env:
+          ANTHROPIC_API_KEY: ${{ secrets.ANTHROPIC_API_KEY }}
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+  safe_outputs:
+    runs-on: ubuntu-latest
+    permissions:
+      issues: write
+    steps:
+      - uses: actions/download-artifact@018cc2cf5baa6db3ef3c5f8a56943fffe632ef53
+        with:
+          path: /tmp/gh-aw/safeoutputs/
+`
+	findings, err := LintLockFile([]byte(content))
+	require.NoError(t, err, "lock file should parse")
+	assert.Empty(t, findings, "safe patterns should not be reported")
+}
+
+func TestLintLockFileInvalidYAML(t *testing.T) {
+	_, err := LintLockFile([]byte("jobs: [\n"))
+	require.Error(t, err, "invalid YAML should fail")
+}
+
+func TestLockFileLintRules(t *testing.T) {
+	rules := LockFileLintRules()
+	require.Len(t, rules, 6, "should describe every rule")
+	for _, rule := range rules {
+		assert.Contains(t, []string{"error", "warning"}, rule.Severity, "rule %s should have a valid default severity", rule.ID)
+		assert.NotEmpty(t, rule.Description, "rule %s should have a description", rule.ID)
+	}
+}
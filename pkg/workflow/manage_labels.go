@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var manageLabelsLog = logger.New("workflow:manage_labels")
+
+// ManageLabelsConfig holds configuration for creating, editing and deleting repository labels.
+// Every label name touched by an operation (including the new name of a rename) must start
+// with one of the allowed prefixes.
+type ManageLabelsConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	AllowedPrefixes      []string `yaml:"allowed-prefixes,omitempty"` // Label name prefixes the agent may manage (required)
+	AllowDelete          bool     `yaml:"allow-delete,omitempty"`     // Whether delete operations are permitted (default: false)
+	TargetRepoSlug       string   `yaml:"target-repo,omitempty"`      // Target repository in format "owner/repo" for cross-repository operations
+	AllowedRepos         []string `yaml:"allowed-repos,omitempty"`    // List of additional repositories whose labels can be managed
+}
+
+// parseManageLabelsConfig handles manage-labels configuration
+func (c *Compiler) parseManageLabelsConfig(outputMap map[string]any) *ManageLabelsConfig {
+	configData, exists := outputMap["manage-labels"]
+	if !exists {
+		return nil
+	}
+
+	manageLabelsLog.Print("Parsing manage-labels configuration")
+	config := &ManageLabelsConfig{}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		config.AllowedPrefixes = ParseStringArrayFromConfig(configMap, "allowed-prefixes", manageLabelsLog)
+		config.AllowedRepos = ParseStringArrayFromConfig(configMap, "allowed-repos", manageLabelsLog)
+
+		if allowDelete, ok := configMap["allow-delete"].(bool); ok {
+			config.AllowDelete = allowDelete
+		}
+		if targetRepo, ok := configMap["target-repo"].(string); ok {
+			config.TargetRepoSlug = targetRepo
+		}
+
+		// Parse common base fields with default max of 10
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 10)
+	} else {
+		config.Max = 10
+	}
+
+	manageLabelsLog.Printf("Parsed manage-labels config: max=%d, allowed_prefixes=%v, allow_delete=%t",
+		config.Max, config.AllowedPrefixes, config.AllowDelete)
+	return config
+}
+
+// validateManageLabelsConfig ensures manage-labels is scoped to an explicit label namespace
+func validateManageLabelsConfig(config *SafeOutputsConfig) error {
+	if config == nil || config.ManageLabels == nil {
+		return nil
+	}
+
+	if len(config.ManageLabels.AllowedPrefixes) == 0 {
+		return fmt.Errorf("safe-outputs.manage-labels requires allowed-prefixes. Example:\n  manage-labels:\n    allowed-prefixes: [\"area/\", \"priority/\"]")
+	}
+	for _, prefix := range config.ManageLabels.AllowedPrefixes {
+		if strings.TrimSpace(prefix) == "" {
+			return fmt.Errorf("safe-outputs.manage-labels: allowed-prefixes must not contain empty prefixes")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManageLabelsConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   any
+		expected *ManageLabelsConfig
+	}{
+		{
+			name:   "null config uses defaults",
+			config: nil,
+			expected: &ManageLabelsConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 10},
+			},
+		},
+		{
+			name: "full config",
+			config: map[string]any{
+				"allowed-prefixes": []any{"area/", "priority/"},
+				"allow-delete":     true,
+				"max":              25,
+				"target-repo":      "octo/labels",
+			},
+			expected: &ManageLabelsConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 25},
+				AllowedPrefixes:      []string{"area/", "priority/"},
+				AllowDelete:          true,
+				TargetRepoSlug:       "octo/labels",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewCompiler().parseManageLabelsConfig(map[string]any{"manage-labels": tt.config})
+			assert.Equal(t, tt.expected, config, "config should be parsed")
+		})
+	}
+
+	assert.Nil(t, NewCompiler().parseManageLabelsConfig(map[string]any{}), "missing config should return nil")
+}
+
+func TestValidateManageLabelsConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *ManageLabelsConfig
+		wantErr string
+	}{
+		{
+			name:   "valid config",
+			config: &ManageLabelsConfig{AllowedPrefixes: []string{"area/"}},
+		},
+		{
+			name:    "missing allowed-prefixes",
+			config:  &ManageLabelsConfig{},
+			wantErr: "requires allowed-prefixes",
+		},
+		{
+			name:    "blank prefix",
+			config:  &ManageLabelsConfig{AllowedPrefixes: []string{"area/", " "}},
+			wantErr: "must not contain empty prefixes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateManageLabelsConfig(&SafeOutputsConfig{ManageLabels: tt.config})
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "config should be valid")
+				return
+			}
+			require.Error(t, err, "config should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error should explain the problem")
+		})
+	}
+}
+
+func TestManageLabelsCompilation(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  manage-labels:
+    allowed-prefixes: ["area/", "priority/"]
+    max: 20
+---
+
+# Label taxonomy sync
+
+Sync repository labels with .github/labels.yml.
+`
+
+	tmpDir := testutil.TempDir(t, "manage-labels-*")
+	testFile := filepath.Join(tmpDir, "labels.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "labels.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `\"manage_labels\":{\"allowed_prefixes\":[\"area/\",\"priority/\"],\"max\":20}`, "handler config should include the prefixes without allow_delete")
+	assert.Contains(t, lock, `"name": "manage_labels"`, "manage_labels tool should be generated")
+
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	jobHeader := safeOutputsJob[:strings.Index(safeOutputsJob, "    steps:")]
+	assert.Contains(t, jobHeader, "issues: write", "safe_outputs job should have issues: write")
+}
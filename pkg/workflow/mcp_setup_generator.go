@@ -379,6 +379,16 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 					yaml.WriteString("          " + line + "\n")
 				}
 				fmt.Fprintf(yaml, "          %s\n", goDelimiter)
+			} else if toolConfig.Container != nil {
+				// Container image tool (executed through a generated shell wrapper)
+				toolScript := generateSafeInputContainerToolScript(toolConfig)
+				containerDelimiter := GenerateHeredocDelimiter(fmt.Sprintf("SAFE_INPUTS_CONTAINER_%s", strings.ToUpper(toolName)))
+				fmt.Fprintf(yaml, "          cat > /opt/gh-aw/safe-inputs/%s.sh << '%s'\n", toolName, containerDelimiter)
+				for _, line := range strings.Split(toolScript, "\n") {
+					yaml.WriteString("          " + line + "\n")
+				}
+				fmt.Fprintf(yaml, "          %s\n", containerDelimiter)
+				fmt.Fprintf(yaml, "          chmod +x /opt/gh-aw/safe-inputs/%s.sh\n", toolName)
 			}
 		}
 		yaml.WriteString("          \n")
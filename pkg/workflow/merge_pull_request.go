@@ -0,0 +1,94 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mergePullRequestLog = logger.New("workflow:merge_pull_request")
+
+// mergeMethods lists the merge methods accepted by the GitHub merge API
+var mergeMethods = []string{"merge", "squash", "rebase"}
+
+// MergePullRequestConfig holds configuration for merging pull requests from agent output.
+// Every configured gate must pass before a pull request is merged.
+type MergePullRequestConfig struct {
+	BaseSafeOutputConfig   `yaml:",inline"`
+	SafeOutputTargetConfig `yaml:",inline"`
+	SafeOutputFilterConfig `yaml:",inline"`
+	MergeMethod            string   `yaml:"merge-method,omitempty"`       // Merge method: "merge", "squash" (default), or "rebase"
+	RequireChecks          *bool    `yaml:"require-checks,omitempty"`     // When true (default), all check runs and commit statuses must be green
+	RequiredApprovals      int      `yaml:"required-approvals,omitempty"` // Minimum number of approving reviews (default: 0)
+	AllowedAuthors         []string `yaml:"allowed-authors,omitempty"`    // Pull request authors that may be merged (default: any author)
+	RequireTrackerID       bool     `yaml:"require-tracker-id,omitempty"` // Only merge pull requests carrying this workflow's tracker-id
+}
+
+// parseMergePullRequestConfig handles merge-pull-request configuration
+func (c *Compiler) parseMergePullRequestConfig(outputMap map[string]any) *MergePullRequestConfig {
+	configData, exists := outputMap["merge-pull-request"]
+	if !exists {
+		return nil
+	}
+
+	mergePullRequestLog.Print("Parsing merge-pull-request configuration")
+	config := &MergePullRequestConfig{MergeMethod: "squash"}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		targetConfig, isInvalid := ParseTargetConfig(configMap)
+		if isInvalid {
+			return nil // invalid target-repo configuration
+		}
+		config.SafeOutputTargetConfig = targetConfig
+		config.SafeOutputFilterConfig = ParseFilterConfig(configMap)
+
+		if method, ok := configMap["merge-method"].(string); ok && method != "" {
+			config.MergeMethod = method
+		}
+		if requireChecks, ok := configMap["require-checks"].(bool); ok {
+			config.RequireChecks = &requireChecks
+		}
+		if approvals, exists := configMap["required-approvals"]; exists {
+			if approvalsInt, ok := parseIntValue(approvals); ok && approvalsInt > 0 {
+				config.RequiredApprovals = approvalsInt
+			}
+		}
+		config.AllowedAuthors = ParseStringArrayFromConfig(configMap, "allowed-authors", mergePullRequestLog)
+		if requireTrackerID, ok := configMap["require-tracker-id"].(bool); ok {
+			config.RequireTrackerID = requireTrackerID
+		}
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		config.Max = 1
+	}
+
+	mergePullRequestLog.Printf("Parsed merge-pull-request config: max=%d, merge_method=%s, require_checks=%v, required_approvals=%d",
+		config.Max, config.MergeMethod, config.requiresChecks(), config.RequiredApprovals)
+	return config
+}
+
+// requiresChecks reports whether all checks must pass before merging (the default)
+func (config *MergePullRequestConfig) requiresChecks() bool {
+	return config.RequireChecks == nil || *config.RequireChecks
+}
+
+// validateMergePullRequestConfig validates the merge method and that require-tracker-id
+// is only used by workflows that declare a tracker-id
+func validateMergePullRequestConfig(config *SafeOutputsConfig, trackerID string) error {
+	if config == nil || config.MergePullRequest == nil {
+		return nil
+	}
+	mergeConfig := config.MergePullRequest
+
+	if !slices.Contains(mergeMethods, mergeConfig.MergeMethod) {
+		return fmt.Errorf("safe-outputs.merge-pull-request: invalid merge-method '%s'. Valid methods: %v", mergeConfig.MergeMethod, mergeMethods)
+	}
+	if mergeConfig.RequireTrackerID && trackerID == "" {
+		return fmt.Errorf("safe-outputs.merge-pull-request: require-tracker-id needs a tracker-id in the workflow frontmatter. Example: tracker-id: \"deps-updater\"")
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMergePullRequestConfig(t *testing.T) {
+	requireChecks := false
+	tests := []struct {
+		name     string
+		config   any
+		expected *MergePullRequestConfig
+	}{
+		{
+			name:   "null config uses defaults",
+			config: nil,
+			expected: &MergePullRequestConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				MergeMethod:          "squash",
+			},
+		},
+		{
+			name: "full config",
+			config: map[string]any{
+				"target":             "*",
+				"max":                3,
+				"merge-method":       "rebase",
+				"require-checks":     false,
+				"required-approvals": 2,
+				"required-labels":    []any{"dependencies"},
+				"allowed-authors":    []any{"dependabot[bot]"},
+				"require-tracker-id": true,
+			},
+			expected: &MergePullRequestConfig{
+				BaseSafeOutputConfig:   BaseSafeOutputConfig{Max: 3},
+				SafeOutputTargetConfig: SafeOutputTargetConfig{Target: "*"},
+				SafeOutputFilterConfig: SafeOutputFilterConfig{RequiredLabels: []string{"dependencies"}},
+				MergeMethod:            "rebase",
+				RequireChecks:          &requireChecks,
+				RequiredApprovals:      2,
+				AllowedAuthors:         []string{"dependabot[bot]"},
+				RequireTrackerID:       true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewCompiler().parseMergePullRequestConfig(map[string]any{"merge-pull-request": tt.config})
+			assert.Equal(t, tt.expected, config, "config should be parsed")
+		})
+	}
+
+	assert.Nil(t, NewCompiler().parseMergePullRequestConfig(map[string]any{}), "missing config should return nil")
+	assert.Nil(t, NewCompiler().parseMergePullRequestConfig(map[string]any{
+		"merge-pull-request": map[string]any{"target-repo": "*"},
+	}), "wildcard target-repo should return nil")
+}
+
+func TestValidateMergePullRequestConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *MergePullRequestConfig
+		trackerID string
+		wantErr   string
+	}{
+		{
+			name:   "valid config",
+			config: &MergePullRequestConfig{MergeMethod: "merge"},
+		},
+		{
+			name:    "invalid merge method",
+			config:  &MergePullRequestConfig{MergeMethod: "fast-forward"},
+			wantErr: "invalid merge-method 'fast-forward'",
+		},
+		{
+			name:    "tracker-id gate without tracker-id",
+			config:  &MergePullRequestConfig{MergeMethod: "squash", RequireTrackerID: true},
+			wantErr: "require-tracker-id needs a tracker-id",
+		},
+		{
+			name:      "tracker-id gate with tracker-id",
+			config:    &MergePullRequestConfig{MergeMethod: "squash", RequireTrackerID: true},
+			trackerID: "deps-updater",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMergePullRequestConfig(&SafeOutputsConfig{MergePullRequest: tt.config}, tt.trackerID)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "config should be valid")
+				return
+			}
+			require.Error(t, err, "config should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error should explain the problem")
+		})
+	}
+}
+
+func TestMergePullRequestCompilation(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+tracker-id: deps-updater
+permissions:
+  contents: read
+safe-outputs:
+  merge-pull-request:
+    target: "*"
+    allowed-authors: ["dependabot[bot]"]
+    required-approvals: 1
+    require-tracker-id: true
+---
+
+# Dependency merger
+
+Merge dependency updates that are ready.
+`
+
+	tmpDir := testutil.TempDir(t, "merge-pull-request-*")
+	testFile := filepath.Join(tmpDir, "deps.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "deps.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `\"merge_pull_request\":{\"allowed_authors\":[\"dependabot[bot]\"],\"max\":1,\"merge_method\":\"squash\",\"require_checks\":true,\"require_tracker_id\":true,\"required_approvals\":1,\"target\":\"*\"}`, "handler config should include the policy gates")
+	assert.Contains(t, lock, `"name": "merge_pull_request"`, "merge_pull_request tool should be generated")
+
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	jobHeader := safeOutputsJob[:strings.Index(safeOutputsJob, "    steps:")]
+	assert.Contains(t, jobHeader, "contents: write", "safe_outputs job should have contents: write")
+	assert.Contains(t, jobHeader, "pull-requests: write", "safe_outputs job should have pull-requests: write")
+}
@@ -220,6 +220,11 @@ func (c *Compiler) buildConclusionJob(data *WorkflowData, mainJobName string, sa
 	})
 	steps = append(steps, noopMessageSteps...)
 
+	// Resolve a pending commit status the agent did not replace
+	if hasPendingCommitStatus(data) {
+		steps = append(steps, c.buildResolveCommitStatusStep(data, mainJobName)...)
+	}
+
 	// Add create_pull_request error handling step if create-pull-request is configured
 	if data.SafeOutputs != nil && data.SafeOutputs.CreatePullRequests != nil {
 		// Build environment variables for the create PR error handler
@@ -377,6 +382,12 @@ func (c *Compiler) buildConclusionJob(data *WorkflowData, mainJobName string, sa
 
 	// Compute permissions based on configured safe outputs (principle of least privilege)
 	permissions := computePermissionsForSafeOutputs(data.SafeOutputs)
+	// checks: write is only needed by the safe_outputs job that publishes check runs
+	delete(permissions.permissions, PermissionChecks)
+	// statuses: write is only needed here to resolve a pending commit status
+	if !hasPendingCommitStatus(data) {
+		delete(permissions.permissions, PermissionStatuses)
+	}
 
 	job := &Job{
 		Name:        "conclusion",
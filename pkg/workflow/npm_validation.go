@@ -33,7 +33,6 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
 	"strings"
 
@@ -80,7 +79,7 @@ func (c *Compiler) validateNpxPackages(workflowData *WorkflowData) error {
 		} else {
 			npmValidationLog.Printf("Package validated successfully: %s", pkg)
 			if c.verbose {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("✓ npm package validated: %s", pkg)))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage(fmt.Sprintf("✓ npm package validated: %s", pkg)))
 			}
 		}
 	}
@@ -183,6 +183,22 @@ func NewPermissionsContentsReadSecurityEventsWrite() *Permissions {
 	})
 }
 
+// NewPermissionsContentsReadChecksWrite creates permissions with contents: read and checks: write
+func NewPermissionsContentsReadChecksWrite() *Permissions {
+	return NewPermissionsFromMap(map[PermissionScope]PermissionLevel{
+		PermissionContents: PermissionRead,
+		PermissionChecks:   PermissionWrite,
+	})
+}
+
+// NewPermissionsContentsReadStatusesWrite creates permissions with contents: read and statuses: write
+func NewPermissionsContentsReadStatusesWrite() *Permissions {
+	return NewPermissionsFromMap(map[PermissionScope]PermissionLevel{
+		PermissionContents: PermissionRead,
+		PermissionStatuses: PermissionWrite,
+	})
+}
+
 // NewPermissionsContentsReadSecurityEventsWriteActionsRead creates permissions with contents: read, security-events: write, actions: read
 func NewPermissionsContentsReadSecurityEventsWriteActionsRead() *Permissions {
 	return NewPermissionsFromMap(map[PermissionScope]PermissionLevel{
@@ -36,7 +36,6 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
 	"strings"
 
@@ -70,14 +69,14 @@ func (c *Compiler) validatePythonPackagesWithPip(packages []string, packageType
 			pipValidationLog.Printf("Package validation failed for %s: %v", pkg, err)
 			// Treat all pip validation errors as warnings, not compilation failures
 			// The package may be experimental, not yet published, or will be installed at runtime
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s package '%s' validation failed - skipping verification. Package may or may not exist on PyPI.", packageType, pkg)))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("%s package '%s' validation failed - skipping verification. Package may or may not exist on PyPI.", packageType, pkg)))
 			if c.verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("  Details: %s", outputStr)))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(fmt.Sprintf("  Details: %s", outputStr)))
 			}
 		} else {
 			pipValidationLog.Printf("Package validated successfully: %s", pkg)
 			if c.verbose {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("✓ %s package validated: %s", packageType, pkg)))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage(fmt.Sprintf("✓ %s package validated: %s", packageType, pkg)))
 			}
 		}
 	}
@@ -101,7 +100,7 @@ func (c *Compiler) validatePipPackages(workflowData *WorkflowData) error {
 		_, err3 := exec.LookPath("pip3")
 		if err3 != nil {
 			pipValidationLog.Print("pip command not found, skipping validation")
-			fmt.Fprintln(os.Stderr, console.FormatWarningMessage("pip command not found - skipping pip package validation. Install Python/pip for full validation"))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage("pip command not found - skipping pip package validation. Install Python/pip for full validation"))
 			return nil
 		}
 		pipCmd = "pip3"
@@ -168,7 +167,7 @@ func (c *Compiler) validateUvPackages(workflowData *WorkflowData) error {
 			// Package not installed, try to check if it's available
 			errors = append(errors, fmt.Sprintf("uv package '%s' validation requires network access or local cache", pkg))
 		} else if c.verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("✓ uv package validated: %s", pkg)))
+			fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage(fmt.Sprintf("✓ uv package validated: %s", pkg)))
 		}
 	}
 
@@ -0,0 +1,311 @@
+// This file implements prompt size limits, configured in the prompt frontmatter section:
+//
+//	prompt:
+//	  max-tokens: 60000
+//	  summarize: [shared/reference.md]
+//	  truncate: [shared/style-guide.md, imports, workflow]
+//
+// When a limit is set, the prompt creation step wraps each user section (the workflow
+// markdown, each runtime import and the inlined imports with inputs) in section markers.
+// The markers carry a nonce generated for each run, so markers that arrive in user content
+// (event bodies, runtime imports) cannot open or close sections; the step strips them.
+// Once the prompt is rendered, the "Enforce prompt size limit" step (enforce_prompt_limit.cjs)
+// estimates its size and, until the prompt fits, summarizes the sections listed in summarize
+// with GitHub Models, then truncates the sections listed in truncate, each in order. It then
+// removes the markers and records its decisions in aw_info.json, where gh aw audit reads them.
+// Built-in sections are never reduced; if the prompt still does not fit, the step fails before
+// the engine runs. Summarizing needs the models: read permission, which the agent job is given
+// when summarize is set.
+//
+// At compile time, the compiler estimates the size of each section from the content that is
+// known before the run and warns when the prompt cannot fit.
+
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var promptLimitLog = logger.New("workflow:prompt_limit")
+
+// Prompt sections that can be listed in prompt.summarize and prompt.truncate, besides import paths
+const (
+	PromptSectionImports  = "imports"
+	PromptSectionWorkflow = "workflow"
+)
+
+// promptSectionMarkerPrefix starts the chunk that opens a prompt section. The prompt
+// creation step writes it as "<!-- gh-aw-prompt-section <nonce>: <id> -->".
+const promptSectionMarkerPrefix = "<!-- gh-aw-prompt-section: "
+
+// promptSectionEndMarker is the chunk that closes a prompt section. The prompt creation
+// step writes it as "<!-- /gh-aw-prompt-section <nonce> -->".
+const promptSectionEndMarker = "<!-- /gh-aw-prompt-section -->"
+
+// promptSectionNonceEnvVar passes the nonce of the section markers to the prompt creation
+// and prompt size limit steps
+const promptSectionNonceEnvVar = "GH_AW_PROMPT_SECTION_NONCE"
+
+// promptSectionNonceStepID is the ID of the step that generates the nonce of a run
+const promptSectionNonceStepID = "prompt_section_nonce"
+
+// defaultPromptTruncationOrder is used when prompt.truncate is not set
+var defaultPromptTruncationOrder = []string{PromptSectionImports, PromptSectionWorkflow}
+
+// promptSummaryModel is the GitHub Models model that summarizes the sections in prompt.summarize
+const promptSummaryModel = "openai/gpt-4.1-mini"
+
+// PromptLimitConfig is the prompt size limit of a workflow
+type PromptLimitConfig struct {
+	MaxTokens int
+	// Summarize lists the sections that may be summarized, in the order they are summarized.
+	// Sections are summarized before any section is truncated.
+	Summarize []string
+	// Truncate lists the sections that may be truncated, in the order they are truncated
+	Truncate []string
+}
+
+// extractPromptLimitConfig extracts the prompt frontmatter section
+func (c *Compiler) extractPromptLimitConfig(frontmatter map[string]any) *PromptLimitConfig {
+	promptValue, ok := frontmatter["prompt"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	config := &PromptLimitConfig{Truncate: defaultPromptTruncationOrder}
+	switch maxTokens := promptValue["max-tokens"].(type) {
+	case int:
+		config.MaxTokens = maxTokens
+	case int64:
+		config.MaxTokens = int(maxTokens)
+	case uint64:
+		config.MaxTokens = int(maxTokens)
+	case float64:
+		config.MaxTokens = int(maxTokens)
+	}
+	if config.MaxTokens <= 0 {
+		return nil
+	}
+
+	if truncate := ParseStringArrayFromConfig(promptValue, "truncate", nil); truncate != nil {
+		config.Truncate = truncate
+	}
+	config.Summarize = ParseStringArrayFromConfig(promptValue, "summarize", nil)
+
+	promptLimitLog.Printf("Prompt limit: max-tokens=%d, summarize=%v, truncate=%v", config.MaxTokens, config.Summarize, config.Truncate)
+	return config
+}
+
+// promptChunkSectionIDs returns the section of each user prompt chunk: "imports" for the
+// inlined imports with inputs, "import:<path>" for runtime imports and "workflow" for the
+// workflow markdown, which is always the last runtime import
+func promptChunkSectionIDs(userPromptChunks []string) []string {
+	ids := make([]string, len(userPromptChunks))
+	for i, chunk := range userPromptChunks {
+		match := runtimeImportMacroRegex.FindStringSubmatch(chunk)
+		switch {
+		case match == nil || match[0] != chunk:
+			ids[i] = PromptSectionImports
+		case i == len(userPromptChunks)-1:
+			ids[i] = PromptSectionWorkflow
+		default:
+			ids[i] = "import:" + strings.TrimSpace(match[2])
+		}
+	}
+	return ids
+}
+
+// addPromptSectionMarkers wraps each user prompt section in section markers so that the
+// prompt size limit step can truncate sections after the prompt is rendered
+func addPromptSectionMarkers(userPromptChunks []string) []string {
+	ids := promptChunkSectionIDs(userPromptChunks)
+	var chunks []string
+	for i, chunk := range userPromptChunks {
+		if i == 0 || ids[i] != ids[i-1] {
+			chunks = append(chunks, promptSectionMarkerPrefix+ids[i]+" -->")
+		}
+		chunks = append(chunks, chunk)
+		if i == len(userPromptChunks)-1 || ids[i] != ids[i+1] {
+			chunks = append(chunks, promptSectionEndMarker)
+		}
+	}
+	return chunks
+}
+
+// promptSectionMarkerCommand returns the shell command that prints a section marker chunk
+// with the nonce of the run. The prompt heredocs are quoted and do not expand variables, so
+// markers are printed separately. It returns false for other chunks.
+func promptSectionMarkerCommand(chunk string) (string, bool) {
+	if chunk == promptSectionEndMarker {
+		return fmt.Sprintf(`printf '<!-- /gh-aw-prompt-section %%s -->\n' "$%s"`, promptSectionNonceEnvVar), true
+	}
+	id, ok := strings.CutPrefix(chunk, promptSectionMarkerPrefix)
+	if !ok || !strings.HasSuffix(id, " -->") {
+		return "", false
+	}
+	id = strings.TrimSuffix(id, " -->")
+	return fmt.Sprintf(`printf '<!-- gh-aw-prompt-section %%s: %%s -->\n' "$%s" %s`, promptSectionNonceEnvVar, shellEscapeArg(id)), true
+}
+
+// promptSectionNonceExpression is the value of promptSectionNonceEnvVar in the steps that
+// write and read the section markers
+func promptSectionNonceExpression() string {
+	return fmt.Sprintf("${{ steps.%s.outputs.nonce }}", promptSectionNonceStepID)
+}
+
+// generatePromptSectionNonceStep generates the step that picks the random nonce of the
+// section markers of a run
+func (c *Compiler) generatePromptSectionNonceStep(yaml *strings.Builder, data *WorkflowData) {
+	if data.PromptLimit == nil {
+		return
+	}
+	yaml.WriteString("      - name: Generate prompt section nonce\n")
+	fmt.Fprintf(yaml, "        id: %s\n", promptSectionNonceStepID)
+	yaml.WriteString("        run: echo \"nonce=$(od -An -N16 -tx1 /dev/urandom | tr -d ' \\n')\" >> \"$GITHUB_OUTPUT\"\n")
+}
+
+// promptSectionMatches reports whether a prompt.summarize or prompt.truncate entry selects a section
+func promptSectionMatches(entry, sectionID string) bool {
+	switch entry {
+	case PromptSectionImports:
+		return sectionID == PromptSectionImports || strings.HasPrefix(sectionID, "import:")
+	case PromptSectionWorkflow:
+		return sectionID == PromptSectionWorkflow
+	}
+	path, ok := strings.CutPrefix(sectionID, "import:")
+	return ok && (path == entry || strings.HasSuffix(path, "/"+entry))
+}
+
+// validatePromptLimit checks the prompt.summarize and prompt.truncate entries and warns when
+// the prompt, as far as it is known at compile time, does not fit in prompt.max-tokens
+func (c *Compiler) validatePromptLimit(data *WorkflowData, markdownPath string) error {
+	if data.PromptLimit == nil {
+		return nil
+	}
+
+	userPromptChunks, _ := c.collectUserPromptChunks(data)
+	sectionIDs := promptChunkSectionIDs(userPromptChunks)
+	policies := []struct {
+		field   string
+		entries []string
+	}{{"summarize", data.PromptLimit.Summarize}, {"truncate", data.PromptLimit.Truncate}}
+	for _, policy := range policies {
+		for _, entry := range policy.entries {
+			// "imports" and "workflow" are valid even when the workflow has no imports
+			if entry == PromptSectionImports || entry == PromptSectionWorkflow {
+				continue
+			}
+			found := false
+			for _, id := range sectionIDs {
+				found = found || promptSectionMatches(entry, id)
+			}
+			if !found {
+				return formatCompilerError(markdownPath, "error", fmt.Sprintf("prompt.%s entry %q does not match any prompt section. Use %q, %q or the path of an import without inputs", policy.field, entry, PromptSectionImports, PromptSectionWorkflow), nil)
+			}
+		}
+	}
+
+	rendered, err := c.renderPromptFromData(data, markdownPath, PromptRenderOptions{})
+	if err != nil {
+		// Runtime imports are validated when the workflow runs
+		promptLimitLog.Printf("Skipping prompt size estimate: %v", err)
+		return nil
+	}
+
+	reducible := slices.Concat(data.PromptLimit.Summarize, data.PromptLimit.Truncate)
+	fixed := 0
+	var reduced []string
+	for _, source := range rendered.Sources {
+		if !source.Included {
+			continue
+		}
+		if source.Kind == PromptSourceBuiltin || !promptSourceSelected(source, reducible) {
+			fixed += source.Tokens
+			continue
+		}
+		reduced = append(reduced, fmt.Sprintf("%s (~%d)", source.Name, source.Tokens))
+	}
+
+	maxTokens := data.PromptLimit.MaxTokens
+	promptLimitLog.Printf("Prompt estimate: total=%d, fixed=%d, max=%d", rendered.Tokens, fixed, maxTokens)
+	switch {
+	case fixed > maxTokens:
+		c.warnAt(RulePromptMaxTokens, markdownPath, fmt.Sprintf("The prompt is estimated at ~%d tokens that cannot be summarized or truncated, more than prompt.max-tokens (%d). Runs will fail before the engine starts. Raise max-tokens or add sections to prompt.summarize or prompt.truncate.\n%s", fixed, maxTokens, formatPromptSectionEstimates(rendered.Sources)))
+	case rendered.Tokens > maxTokens:
+		c.warnAt(RulePromptMaxTokens, markdownPath, fmt.Sprintf("The prompt is estimated at ~%d tokens before runtime values, more than prompt.max-tokens (%d). Every run will summarize or truncate: %s", rendered.Tokens, maxTokens, strings.Join(reduced, ", ")))
+	}
+	return nil
+}
+
+// promptSourceSelected reports whether a prompt source is selected by one of the
+// prompt.summarize or prompt.truncate entries
+func promptSourceSelected(source PromptSource, entries []string) bool {
+	sectionID := source.Kind
+	switch source.Kind {
+	case PromptSourceImport:
+		sectionID = "import:" + source.Name
+		if source.Name == inlinedImportsSourceName {
+			sectionID = PromptSectionImports
+		}
+	case PromptSourceWorkflow:
+		sectionID = PromptSectionWorkflow
+	}
+	for _, entry := range entries {
+		if promptSectionMatches(entry, sectionID) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPromptSectionEstimates lists the token estimates of the included prompt sources,
+// largest first
+func formatPromptSectionEstimates(sources []PromptSource) string {
+	included := make([]PromptSource, 0, len(sources))
+	for _, source := range sources {
+		if source.Included {
+			included = append(included, source)
+		}
+	}
+	sort.SliceStable(included, func(i, j int) bool { return included[i].Tokens > included[j].Tokens })
+
+	lines := make([]string, len(included))
+	for i, source := range included {
+		lines[i] = fmt.Sprintf("  %s %s: ~%d tokens", source.Kind, source.Name, source.Tokens)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// generateEnforcePromptLimitStep generates the step that summarizes and truncates the rendered
+// prompt to prompt.max-tokens before the engine runs
+func (c *Compiler) generateEnforcePromptLimitStep(yaml *strings.Builder, data *WorkflowData) {
+	if data.PromptLimit == nil {
+		return
+	}
+	promptLimitLog.Printf("Generating prompt size limit step: max-tokens=%d", data.PromptLimit.MaxTokens)
+
+	yaml.WriteString("      - name: Enforce prompt size limit\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
+	yaml.WriteString("        env:\n")
+	yaml.WriteString("          GH_AW_PROMPT: /tmp/gh-aw/aw-prompts/prompt.txt\n")
+	fmt.Fprintf(yaml, "          GH_AW_PROMPT_MAX_TOKENS: %q\n", strconv.Itoa(data.PromptLimit.MaxTokens))
+	fmt.Fprintf(yaml, "          GH_AW_PROMPT_TRUNCATE: %q\n", strings.Join(data.PromptLimit.Truncate, ","))
+	if len(data.PromptLimit.Summarize) > 0 {
+		fmt.Fprintf(yaml, "          GH_AW_PROMPT_SUMMARIZE: %q\n", strings.Join(data.PromptLimit.Summarize, ","))
+		fmt.Fprintf(yaml, "          GH_AW_PROMPT_SUMMARY_MODEL: %q\n", promptSummaryModel)
+		yaml.WriteString("          GH_AW_PROMPT_SUMMARY_TOKEN: ${{ secrets.GITHUB_TOKEN }}\n")
+	}
+	fmt.Fprintf(yaml, "          %s: %s\n", promptSectionNonceEnvVar, promptSectionNonceExpression())
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          script: |\n")
+	yaml.WriteString("            const { setupGlobals } = require('/opt/gh-aw/actions/setup_globals.cjs');\n")
+	yaml.WriteString("            setupGlobals(core, github, context, exec, io);\n")
+	yaml.WriteString("            const { main } = require('/opt/gh-aw/actions/enforce_prompt_limit.cjs');\n")
+	yaml.WriteString("            await main();\n")
+}
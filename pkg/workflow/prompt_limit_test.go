@@ -0,0 +1,200 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPromptLimitConfig(t *testing.T) {
+	compiler := NewCompiler()
+
+	assert.Nil(t, compiler.extractPromptLimitConfig(map[string]any{}), "no prompt section should not set a limit")
+
+	config := compiler.extractPromptLimitConfig(map[string]any{"prompt": map[string]any{"max-tokens": uint64(60000)}})
+	require.NotNil(t, config, "max-tokens should set a limit")
+	assert.Equal(t, 60000, config.MaxTokens, "max-tokens should be read")
+	assert.Equal(t, []string{PromptSectionImports, PromptSectionWorkflow}, config.Truncate, "imports then workflow should be truncated by default")
+
+	config = compiler.extractPromptLimitConfig(map[string]any{"prompt": map[string]any{"max-tokens": 1000, "truncate": []any{}}})
+	require.NotNil(t, config, "max-tokens should set a limit")
+	assert.Empty(t, config.Truncate, "an empty truncate list should disable truncation")
+	assert.Empty(t, config.Summarize, "sections should not be summarized by default")
+
+	config = compiler.extractPromptLimitConfig(map[string]any{"prompt": map[string]any{"max-tokens": 1000, "summarize": []any{"shared/reference.md", "imports"}}})
+	require.NotNil(t, config, "max-tokens should set a limit")
+	assert.Equal(t, []string{"shared/reference.md", "imports"}, config.Summarize, "summarize order should be read")
+	assert.Equal(t, []string{PromptSectionImports, PromptSectionWorkflow}, config.Truncate, "summarize should keep the default truncation order")
+}
+
+func TestAddPromptSectionMarkers(t *testing.T) {
+	chunks := addPromptSectionMarkers([]string{
+		"inlined part 1",
+		"inlined part 2",
+		"{{#runtime-import .github/workflows/shared/guide.md}}",
+		"{{#runtime-import .github/workflows/triage.md}}",
+	})
+
+	assert.Equal(t, []string{
+		"<!-- gh-aw-prompt-section: imports -->",
+		"inlined part 1",
+		"inlined part 2",
+		"<!-- /gh-aw-prompt-section -->",
+		"<!-- gh-aw-prompt-section: import:.github/workflows/shared/guide.md -->",
+		"{{#runtime-import .github/workflows/shared/guide.md}}",
+		"<!-- /gh-aw-prompt-section -->",
+		"<!-- gh-aw-prompt-section: workflow -->",
+		"{{#runtime-import .github/workflows/triage.md}}",
+		"<!-- /gh-aw-prompt-section -->",
+	}, chunks, "each user section should be wrapped in markers")
+}
+
+func TestPromptSectionMarkerCommand(t *testing.T) {
+	command, ok := promptSectionMarkerCommand("<!-- gh-aw-prompt-section: import:.github/workflows/shared/it's.md -->")
+	require.True(t, ok, "start markers should be printed")
+	assert.Equal(t, `printf '<!-- gh-aw-prompt-section %s: %s -->\n' "$GH_AW_PROMPT_SECTION_NONCE" 'import:.github/workflows/shared/it'\''s.md'`, command, "start marker should carry the nonce and a quoted section ID")
+
+	command, ok = promptSectionMarkerCommand(promptSectionEndMarker)
+	require.True(t, ok, "end markers should be printed")
+	assert.Equal(t, `printf '<!-- /gh-aw-prompt-section %s -->\n' "$GH_AW_PROMPT_SECTION_NONCE"`, command, "end marker should carry the nonce")
+
+	_, ok = promptSectionMarkerCommand("{{#runtime-import .github/workflows/triage.md}}")
+	assert.False(t, ok, "other chunks should not be printed as markers")
+}
+
+func TestPromptSectionMatches(t *testing.T) {
+	tests := []struct {
+		entry     string
+		sectionID string
+		want      bool
+	}{
+		{"imports", "imports", true},
+		{"imports", "import:.github/workflows/shared/guide.md", true},
+		{"imports", "workflow", false},
+		{"workflow", "workflow", true},
+		{"shared/guide.md", "import:.github/workflows/shared/guide.md", true},
+		{".github/workflows/shared/guide.md", "import:.github/workflows/shared/guide.md", true},
+		{"guide.md", "import:.github/workflows/shared/myguide.md", false},
+		{"shared/guide.md", "workflow", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry+" "+tt.sectionID, func(t *testing.T) {
+			assert.Equal(t, tt.want, promptSectionMatches(tt.entry, tt.sectionID), "truncate entry should match like enforce_prompt_limit.cjs")
+		})
+	}
+}
+
+func TestCompileWorkflowWithPromptLimit(t *testing.T) {
+	workflowFile := setupPromptRenderRepo(t, "")
+	sharedFile := filepath.Join(filepath.Dir(workflowFile), "shared", "triage.md")
+	require.NoError(t, os.WriteFile(sharedFile, []byte(strings.Repeat("Follow the triage guide.\n", 200)), 0o644), "should write large import")
+
+	writeWorkflow := func(policy string) {
+		content := `---
+on: workflow_dispatch
+engine: copilot
+imports:
+  - shared/triage.md
+prompt:
+  max-tokens: 500
+  ` + policy + `
+---
+
+# Triage
+`
+		require.NoError(t, os.WriteFile(workflowFile, []byte(content), 0o644), "should write workflow")
+	}
+
+	t.Run("truncated on every run", func(t *testing.T) {
+		writeWorkflow("truncate: [shared/triage.md]")
+		compiler := NewCompiler()
+		var stderr bytes.Buffer
+		compiler.SetDiagnosticWriter(&stderr)
+		require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow should compile")
+
+		assert.Contains(t, stderr.String(), "[prompt/max-tokens]", "exceeding the limit should warn")
+		assert.Contains(t, stderr.String(), "Every run will summarize or truncate: .github/workflows/shared/triage.md", "warning should name the truncated sections")
+
+		lockContent, err := os.ReadFile(filepath.Join(filepath.Dir(workflowFile), "triage.lock.yml"))
+		require.NoError(t, err, "lock file should be written")
+		lock := string(lockContent)
+		assert.Contains(t, lock, `"$GH_AW_PROMPT_SECTION_NONCE" import:.github/workflows/shared/triage.md >> "$GH_AW_PROMPT"`, "imports should be marked")
+		assert.Contains(t, lock, `"$GH_AW_PROMPT_SECTION_NONCE" workflow >> "$GH_AW_PROMPT"`, "workflow markdown should be marked")
+		assert.Equal(t, 2, strings.Count(lock, "GH_AW_PROMPT_SECTION_NONCE: ${{ steps.prompt_section_nonce.outputs.nonce }}"), "the nonce should be passed to the prompt creation and enforcement steps")
+		assert.Less(t, strings.Index(lock, "id: prompt_section_nonce"), strings.Index(lock, "Create prompt with built-in context"), "the nonce should be generated before the prompt is created")
+		assert.Contains(t, lock, `GH_AW_PROMPT_MAX_TOKENS: "500"`, "limit should be passed to the enforcement step")
+		assert.Contains(t, lock, `GH_AW_PROMPT_TRUNCATE: "shared/triage.md"`, "truncation order should be passed to the enforcement step")
+		assert.Less(t, strings.Index(lock, "Validate prompt placeholders"), strings.Index(lock, "Enforce prompt size limit"), "limit should be enforced on the rendered prompt")
+		assert.Less(t, strings.Index(lock, "Enforce prompt size limit"), strings.Index(lock, "name: Print prompt"), "limit should be enforced before the prompt is printed")
+		assert.NotContains(t, lock, "GH_AW_PROMPT_SUMMARIZE", "sections should not be summarized without prompt.summarize")
+		assert.NotContains(t, lock, "models: read", "the agent should not call GitHub Models without prompt.summarize")
+	})
+
+	t.Run("summarized on every run", func(t *testing.T) {
+		writeWorkflow("summarize: [shared/triage.md]\n  truncate: []")
+		compiler := NewCompiler()
+		var stderr bytes.Buffer
+		compiler.SetDiagnosticWriter(&stderr)
+		require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow should compile")
+
+		assert.Contains(t, stderr.String(), "Every run will summarize or truncate: .github/workflows/shared/triage.md", "warning should name the summarized sections")
+		assert.NotContains(t, stderr.String(), "Runs will fail before the engine starts", "summarized sections can be reduced")
+
+		lockContent, err := os.ReadFile(filepath.Join(filepath.Dir(workflowFile), "triage.lock.yml"))
+		require.NoError(t, err, "lock file should be written")
+		lock := string(lockContent)
+		assert.Contains(t, lock, `GH_AW_PROMPT_SUMMARIZE: "shared/triage.md"`, "summarize order should be passed to the enforcement step")
+		assert.Contains(t, lock, `GH_AW_PROMPT_SUMMARY_MODEL: "`+promptSummaryModel+`"`, "summary model should be passed to the enforcement step")
+		assert.Contains(t, lock, "GH_AW_PROMPT_SUMMARY_TOKEN: ${{ secrets.GITHUB_TOKEN }}", "token should be passed to the enforcement step")
+		assert.Contains(t, lock, "      models: read", "the agent job should be allowed to call GitHub Models")
+	})
+
+	t.Run("cannot fit", func(t *testing.T) {
+		writeWorkflow("truncate: [workflow]")
+		compiler := NewCompiler()
+		var stderr bytes.Buffer
+		compiler.SetDiagnosticWriter(&stderr)
+		require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow should compile")
+		assert.Contains(t, stderr.String(), "Runs will fail before the engine starts", "sections that cannot be truncated should warn")
+	})
+
+	t.Run("unknown section", func(t *testing.T) {
+		writeWorkflow("truncate: [missing.md]")
+		compiler := NewCompiler()
+		compiler.SetDiagnosticWriter(&bytes.Buffer{})
+		err := compiler.CompileWorkflow(workflowFile)
+		require.Error(t, err, "unknown truncate entries should fail")
+		assert.Contains(t, err.Error(), `prompt.truncate entry "missing.md"`, "error should name the entry")
+
+		writeWorkflow("summarize: [missing.md]")
+		err = compiler.CompileWorkflow(workflowFile)
+		require.Error(t, err, "unknown summarize entries should fail")
+		assert.Contains(t, err.Error(), `prompt.summarize entry "missing.md"`, "error should name the entry")
+	})
+}
+
+func TestCompileWorkflowWithDefaultPromptTruncation(t *testing.T) {
+	workflowFile := setupPromptRenderRepo(t, "---\non: workflow_dispatch\nengine: copilot\nprompt:\n  max-tokens: 1000\n---\n\n# Triage\n")
+	compiler := NewCompiler()
+	compiler.SetDiagnosticWriter(&bytes.Buffer{})
+	require.NoError(t, compiler.CompileWorkflow(workflowFile), "the default truncation order should compile without imports")
+}
+
+func TestCompileWorkflowWithoutPromptLimit(t *testing.T) {
+	workflowFile := setupPromptRenderRepo(t, "---\non: workflow_dispatch\nengine: copilot\n---\n\n# Triage\n")
+	compiler := NewCompiler()
+	compiler.SetDiagnosticWriter(&bytes.Buffer{})
+	require.NoError(t, compiler.CompileWorkflow(workflowFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(filepath.Dir(workflowFile), "triage.lock.yml"))
+	require.NoError(t, err, "lock file should be written")
+	assert.NotContains(t, string(lockContent), "gh-aw-prompt-section", "prompts without a limit should not be marked")
+	assert.NotContains(t, string(lockContent), "Enforce prompt size limit", "prompts without a limit should not be enforced")
+}
@@ -0,0 +1,560 @@
+// This file renders the final prompt of a workflow without running it.
+//
+// At runtime, the agent job assembles the prompt in several steps:
+//
+//  1. "Create prompt with built-in context" writes the built-in sections (wrapped in
+//     <system> tags), the inlined imports with inputs, and a runtime-import macro for
+//     every other import and for the workflow markdown itself.
+//  2. "Substitute placeholders" replaces __GH_AW_*__ placeholders with the values of
+//     the expressions they were extracted from.
+//  3. "Interpolate variables and render templates" expands the runtime-import macros
+//     (runtime_import.cjs), interpolates ${GH_AW_EXPR_*} variables and renders
+//     {{#if}} template conditionals (interpolate_prompt.cjs).
+//
+// RenderPrompt reuses the compiler's own section and chunk collection for step 1 and
+// ports steps 2 and 3 to Go, evaluating expressions against an event fixture. Each
+// contributing source is also rendered on its own so that previews can show source
+// boundaries and per-source token estimates.
+
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var promptRenderLog = logger.New("workflow:prompt_render")
+
+// Prompt source kinds
+const (
+	PromptSourceBuiltin  = "builtin"
+	PromptSourceImport   = "import"
+	PromptSourceWorkflow = "workflow"
+)
+
+// inlinedImportsSourceName names the source of the imports with inputs, which are inlined
+// into the prompt creation step
+const inlinedImportsSourceName = "imports with inputs"
+
+// promptCharsPerToken is the approximate number of characters per token used for estimates
+const promptCharsPerToken = 4
+
+var (
+	runtimeImportMacroRegex   = regexp.MustCompile(`\{\{#runtime-import(\?)?[ \t]+([^\}]+?)\}\}`)
+	runtimeImportRangeRegex   = regexp.MustCompile(`^(.+?):(\d+)-(\d+)$`)
+	runtimeXMLCommentRegex    = regexp.MustCompile(`(?s)<!--.*?-->`)
+	runtimeConditionalRegex   = regexp.MustCompile(`\{\{#if\s+((?:\$\{\{[^\}]*\}\}|[^\}])*?)\s*\}\}`)
+	runtimeWrappedIfRegex     = regexp.MustCompile(`\{\{#if\s+\$\{\{\s*(.*?)\s*\}\}\s*\}\}`)
+	runtimeExpressionRegex    = regexp.MustCompile(`(?s)\$\{\{(.*?)\}\}`)
+	runtimeSimplePathRegex    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.]*$`)
+	runtimePlaceholderRegex   = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	templateIfTagRegex        = regexp.MustCompile(`\{\{#if\s+[^}]+\}\}`)
+	templateBlockRegex        = regexp.MustCompile(`(\n?)([ \t]*\{\{#if\s+([^}]*)\}\}[ \t]*\n)((?s:.*?))([ \t]*\{\{/if\}\}[ \t]*)(\n?)`)
+	templateInlineRegex       = regexp.MustCompile(`\{\{#if\s+([^}]*)\}\}((?s:.*?))\{\{/if\}\}`)
+	templateBlankLinesRegex   = regexp.MustCompile(`\n{3,}`)
+	interpolationVariableExpr = regexp.MustCompile(`\$\{(GH_AW_EXPR_[A-Z0-9_]+)\}`)
+)
+
+// PromptSource is one of the sources a prompt is assembled from
+type PromptSource struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Content  string `json:"-"`
+	Tokens   int    `json:"tokens"`
+	Included bool   `json:"included"`
+	Note     string `json:"note,omitempty"`
+}
+
+// RenderedPrompt is the final prompt of a workflow and the sources it was assembled from
+type RenderedPrompt struct {
+	Text    string
+	Tokens  int
+	Sources []PromptSource
+	// Unresolved lists the expressions and imports that only resolve while the workflow runs
+	Unresolved []string
+	// MaxTokens is the prompt size limit of the workflow (prompt.max-tokens), 0 if unset
+	MaxTokens int
+}
+
+// EstimatePromptTokens estimates the number of tokens of prompt text
+func EstimatePromptTokens(text string) int {
+	return (len(text) + promptCharsPerToken - 1) / promptCharsPerToken
+}
+
+// promptSegment is the text a source contributes to the prompt file before it is processed
+type promptSegment struct {
+	source *PromptSource
+	text   string
+}
+
+// RenderPrompt renders the prompt the agent receives when the workflow at markdownPath
+// runs for the event described by opts
+func (c *Compiler) RenderPrompt(markdownPath string, opts PromptRenderOptions) (*RenderedPrompt, error) {
+	promptRenderLog.Printf("Rendering prompt: workflow=%s, event=%s", markdownPath, opts.EventName)
+	c.markdownPath = markdownPath
+
+	data, err := c.ParseWorkflowFile(markdownPath)
+	if err != nil {
+		return nil, err
+	}
+	return c.renderPromptFromData(data, markdownPath, opts)
+}
+
+// renderPromptFromData renders the prompt of a parsed workflow
+func (c *Compiler) renderPromptFromData(data *WorkflowData, markdownPath string, opts PromptRenderOptions) (*RenderedPrompt, error) {
+	// Runtime imports resolve from the repository root, as in validateWorkflowData
+	workspaceDir := filepath.Dir(filepath.Dir(filepath.Dir(markdownPath)))
+	ctx := newPromptContext(data, opts)
+
+	builtinSections := c.collectPromptSections(data)
+	userPromptChunks, expressionMappings := c.collectUserPromptChunks(data)
+	_, allExpressionMappings := collectPromptExpressionMappings(builtinSections, expressionMappings)
+
+	sources, segments := collectPromptSegments(builtinSections, userPromptChunks, ctx, opts)
+
+	renderer := &promptRenderer{
+		ctx:           ctx,
+		workspaceDir:  workspaceDir,
+		substitutions: evaluatePromptSubstitutions(allExpressionMappings, ctx),
+		variables:     evaluatePromptVariables(expressionMappings, ctx),
+		interpolate:   needsInterpolationAndTemplateStep(expressionMappings, data),
+	}
+
+	// Render the whole prompt file at once, as the runtime does, and each source on its own
+	var file strings.Builder
+	for _, segment := range segments {
+		file.WriteString(segment.text)
+	}
+	text, err := renderer.render(file.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		if segment.source == nil {
+			continue
+		}
+		content, err := renderer.render(segment.text)
+		if err != nil {
+			return nil, err
+		}
+		segment.source.Content += content
+		segment.source.Tokens = EstimatePromptTokens(segment.source.Content)
+	}
+
+	result := &RenderedPrompt{
+		Text:       text,
+		Tokens:     EstimatePromptTokens(text),
+		Unresolved: ctx.unresolved,
+	}
+	if data.PromptLimit != nil {
+		result.MaxTokens = data.PromptLimit.MaxTokens
+	}
+	for _, source := range sources {
+		result.Sources = append(result.Sources, *source)
+	}
+	promptRenderLog.Printf("Rendered prompt: sources=%d, tokens=%d, unresolved=%d", len(result.Sources), result.Tokens, len(result.Unresolved))
+	return result, nil
+}
+
+// collectPromptSegments lays out the prompt file written by the prompt creation step,
+// one segment per source
+func collectPromptSegments(builtinSections []PromptSection, userPromptChunks []string, ctx *promptContext, opts PromptRenderOptions) ([]*PromptSource, []promptSegment) {
+	var sources []*PromptSource
+	var segments []promptSegment
+
+	var builtinSegments []promptSegment
+	for _, section := range builtinSections {
+		name := section.Name
+		if section.IsFile {
+			name = section.Content
+		}
+		source := &PromptSource{Kind: PromptSourceBuiltin, Name: name, Included: true}
+		sources = append(sources, source)
+
+		if section.ShellCondition != "" && !ctx.includesSection(section) {
+			source.Included = false
+			source.Note = "not included for " + ctx.eventName() + " events"
+			continue
+		}
+
+		if !section.IsFile {
+			content := removeConsecutiveEmptyLines(normalizeLeadingWhitespace(section.Content))
+			builtinSegments = append(builtinSegments, promptSegment{source: source, text: content + "\n"})
+			continue
+		}
+
+		content, err := readBuiltinPromptFile(opts.PromptsDir, section.Content)
+		if err != nil {
+			source.Included = false
+			source.Note = err.Error()
+			continue
+		}
+		builtinSegments = append(builtinSegments, promptSegment{source: source, text: content})
+	}
+	if len(builtinSections) > 0 {
+		// The system tags are written even when no built-in section is included
+		if len(builtinSegments) == 0 {
+			builtinSegments = append(builtinSegments, promptSegment{})
+		}
+		builtinSegments[0].text = "<system>\n" + builtinSegments[0].text
+		last := len(builtinSegments) - 1
+		builtinSegments[last].text += "</system>\n"
+		segments = append(segments, builtinSegments...)
+	}
+
+	var inlinedImports *PromptSource
+	for i, chunk := range userPromptChunks {
+		match := runtimeImportMacroRegex.FindStringSubmatch(chunk)
+		if match == nil || match[0] != chunk {
+			// Chunks of imports with inputs are inlined at compile time
+			if inlinedImports == nil {
+				inlinedImports = &PromptSource{Kind: PromptSourceImport, Name: inlinedImportsSourceName, Included: true}
+				sources = append(sources, inlinedImports)
+			}
+			segments = append(segments, promptSegment{source: inlinedImports, text: chunk + "\n"})
+			continue
+		}
+
+		// The workflow markdown is always the last runtime import
+		kind := PromptSourceImport
+		if i == len(userPromptChunks)-1 {
+			kind = PromptSourceWorkflow
+		}
+		source := &PromptSource{Kind: kind, Name: strings.TrimSpace(match[2]), Included: true}
+		sources = append(sources, source)
+		segments = append(segments, promptSegment{source: source, text: chunk + "\n"})
+	}
+
+	return sources, segments
+}
+
+// readBuiltinPromptFile reads a built-in prompt file that the setup action provides at runtime
+func readBuiltinPromptFile(promptsDir, name string) (string, error) {
+	if promptsDir == "" {
+		return "", fmt.Errorf("built-in prompt file %s is only available at runtime", name)
+	}
+	content, err := os.ReadFile(filepath.Join(promptsDir, name))
+	if err != nil {
+		return "", fmt.Errorf("built-in prompt file %s not found in %s", name, promptsDir)
+	}
+	return string(content), nil
+}
+
+// eventName returns the name of the previewed event
+func (p *promptContext) eventName() string {
+	name, _ := p.lookup("github.event_name")
+	return formatPromptValue(name)
+}
+
+// includesSection evaluates the shell condition of a built-in section for the previewed event
+func (p *promptContext) includesSection(section PromptSection) bool {
+	switch section.ShellCondition {
+	case prContextShellCondition:
+		switch p.eventName() {
+		case "pull_request_review_comment", "pull_request_review":
+			return true
+		case "issue_comment":
+			_, onPullRequest := p.lookup("github.event.issue.pull_request")
+			return onPullRequest
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// evaluatePromptSubstitutions evaluates the values of the placeholder substitution step
+func evaluatePromptSubstitutions(mappings []*ExpressionMapping, ctx *promptContext) map[string]string {
+	substitutions := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		if literal, ok := unquotePromptLiteral(mapping.Content); ok && !strings.HasPrefix(mapping.Content, "`") {
+			// Static values are written to the step environment as quoted YAML strings
+			substitutions[mapping.EnvVar] = literal
+			continue
+		}
+		substitutions[mapping.EnvVar], _ = ctx.evaluateActionsExpression(mapping.Content)
+	}
+	return substitutions
+}
+
+// evaluatePromptVariables evaluates the ${GH_AW_EXPR_*} variables of the interpolation step
+func evaluatePromptVariables(mappings []*ExpressionMapping, ctx *promptContext) map[string]string {
+	variables := make(map[string]string)
+	for _, mapping := range mappings {
+		if strings.HasPrefix(mapping.EnvVar, "GH_AW_EXPR_") {
+			variables[mapping.EnvVar], _ = ctx.evaluateActionsExpression(mapping.Content)
+		}
+	}
+	return variables
+}
+
+// promptRenderer applies the runtime prompt processing steps to prompt text
+type promptRenderer struct {
+	ctx           *promptContext
+	workspaceDir  string
+	substitutions map[string]string
+	variables     map[string]string
+	// interpolate is false when the workflow has no interpolation and template step
+	interpolate bool
+}
+
+// render applies the placeholder substitution step and the interpolation and template step
+func (r *promptRenderer) render(content string) (string, error) {
+	keys := make([]string, 0, len(r.substitutions))
+	for key := range r.substitutions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		content = strings.ReplaceAll(content, "__"+key+"__", r.substitutions[key])
+	}
+
+	if !r.interpolate {
+		return content, nil
+	}
+
+	content, err := r.expandRuntimeImports(content, nil, make(map[string]string))
+	if err != nil {
+		return "", err
+	}
+
+	content = interpolationVariableExpr.ReplaceAllStringFunc(content, func(match string) string {
+		name := interpolationVariableExpr.FindStringSubmatch(match)[1]
+		if value, ok := r.variables[name]; ok {
+			return value
+		}
+		return match
+	})
+
+	if templateIfTagRegex.MatchString(content) {
+		content = renderPromptTemplate(content)
+	}
+	return content, nil
+}
+
+// expandRuntimeImports replaces runtime-import macros with the processed content of the
+// files they reference, recursively (see processRuntimeImports in runtime_import.cjs)
+func (r *promptRenderer) expandRuntimeImports(content string, stack []string, cache map[string]string) (string, error) {
+	for _, match := range runtimeImportMacroRegex.FindAllStringSubmatch(content, -1) {
+		macro, optional, target := match[0], match[1] == "?", strings.TrimSpace(match[2])
+
+		if cached, ok := cache[target]; ok {
+			content = strings.Replace(content, macro, cached, 1)
+			continue
+		}
+		for _, importing := range stack {
+			if importing == target {
+				return "", fmt.Errorf("failed to process runtime import for %s: circular dependency detected: %s", target, strings.Join(append(stack, target), " -> "))
+			}
+		}
+
+		path, startLine, endLine := target, 0, 0
+		if rangeMatch := runtimeImportRangeRegex.FindStringSubmatch(target); rangeMatch != nil {
+			path = rangeMatch[1]
+			startLine, _ = strconv.Atoi(rangeMatch[2])
+			endLine, _ = strconv.Atoi(rangeMatch[3])
+		}
+
+		if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+			// URL imports are fetched while the workflow runs
+			r.ctx.markUnresolved(macro)
+			cache[target] = macro
+			continue
+		}
+
+		imported, err := r.importFile(path, optional, startLine, endLine)
+		if err == nil && strings.Contains(imported, "{{#runtime-import") {
+			imported, err = r.expandRuntimeImports(imported, append(stack, target), cache)
+		}
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "failed to process runtime import") {
+				return "", err
+			}
+			return "", fmt.Errorf("failed to process runtime import for %s: %w", target, err)
+		}
+
+		cache[target] = imported
+		content = strings.Replace(content, macro, imported, 1)
+	}
+	return content, nil
+}
+
+// importFile reads and processes a runtime-imported file (see processRuntimeImport in runtime_import.cjs)
+func (r *promptRenderer) importFile(path string, optional bool, startLine, endLine int) (string, error) {
+	baseDir := filepath.Join(r.workspaceDir, ".github")
+	relPath := path
+	switch {
+	case strings.HasPrefix(path, ".agents/"):
+		baseDir = filepath.Join(r.workspaceDir, ".agents")
+		relPath = strings.TrimPrefix(path, ".agents/")
+	case strings.HasPrefix(path, ".github/"):
+		relPath = strings.TrimPrefix(path, ".github/")
+	default:
+		relPath = filepath.Join("workflows", strings.TrimPrefix(path, "./"))
+	}
+	relPath = strings.TrimPrefix(relPath, "./")
+
+	absPath := filepath.Join(baseDir, filepath.FromSlash(relPath))
+	if rel, err := filepath.Rel(baseDir, absPath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("security: path %s must be within the %s folder", path, filepath.Base(baseDir))
+	}
+
+	raw, err := os.ReadFile(absPath)
+	if err != nil {
+		if optional && os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("runtime import file not found: %s", relPath)
+	}
+	content := string(raw)
+
+	if startLine > 0 {
+		lines := strings.Split(content, "\n")
+		if startLine > len(lines) || endLine < 1 || endLine > len(lines) {
+			return "", fmt.Errorf("invalid line range %d-%d for file %s (total lines: %d)", startLine, endLine, relPath, len(lines))
+		}
+		if startLine > endLine {
+			return "", fmt.Errorf("start line %d cannot be greater than end line %d for file %s", startLine, endLine, relPath)
+		}
+		content = strings.Join(lines[startLine-1:endLine], "\n")
+	}
+
+	content = stripRuntimeImportFrontmatter(content)
+	content = removeRuntimeXMLComments(content)
+	content = wrapRuntimeTemplateConditionals(content)
+	content = runtimeWrappedIfRegex.ReplaceAllStringFunc(content, func(match string) string {
+		expr := strings.TrimSpace(runtimeWrappedIfRegex.FindStringSubmatch(match)[1])
+		return "{{#if __" + runtimePlaceholderName(expr) + "__ }}"
+	})
+
+	if runtimeExpressionRegex.MatchString(content) {
+		if err := validateExpressionSafety(content); err != nil {
+			return "", fmt.Errorf("file %s contains unauthorized GitHub Actions expressions: %w", relPath, err)
+		}
+		content = runtimeExpressionRegex.ReplaceAllStringFunc(content, func(match string) string {
+			return r.ctx.evaluateRuntimeImportExpression(runtimeExpressionRegex.FindStringSubmatch(match)[1])
+		})
+	}
+	return content, nil
+}
+
+// stripRuntimeImportFrontmatter removes the frontmatter of a runtime-imported file
+func stripRuntimeImportFrontmatter(content string) string {
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "---\n") && !strings.HasPrefix(trimmed, "---\r\n") {
+		return content
+	}
+
+	var body []string
+	delimiters := 0
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "---" && delimiters < 2 {
+			delimiters++
+			continue
+		}
+		if delimiters >= 2 {
+			body = append(body, line)
+		}
+	}
+	return strings.Join(body, "\n")
+}
+
+// removeRuntimeXMLComments removes XML comments the way runtime_import.cjs does, including
+// comments inside code blocks
+func removeRuntimeXMLComments(content string) string {
+	for {
+		next := runtimeXMLCommentRegex.ReplaceAllString(content, "")
+		if next == content {
+			return content
+		}
+		content = next
+	}
+}
+
+// wrapRuntimeTemplateConditionals wraps bare expressions of template conditionals in ${{ }}.
+// Unlike wrapExpressionsInTemplateConditionals, it only wraps conditions that look like
+// GitHub Actions expressions.
+func wrapRuntimeTemplateConditionals(content string) string {
+	return runtimeConditionalRegex.ReplaceAllStringFunc(content, func(match string) string {
+		expr := strings.TrimSpace(runtimeConditionalRegex.FindStringSubmatch(match)[1])
+		if strings.HasPrefix(expr, "${") || strings.HasPrefix(expr, "__") {
+			return match
+		}
+		if !strings.Contains(expr, ".") && expr != "true" && expr != "false" && expr != "null" {
+			return match
+		}
+		return "{{#if ${{ " + expr + " }} }}"
+	})
+}
+
+// runtimePlaceholderName returns the placeholder runtime_import.cjs uses for an expression
+// in a template conditional
+func runtimePlaceholderName(expr string) string {
+	if runtimeSimplePathRegex.MatchString(expr) {
+		return "GH_AW_" + strings.ToUpper(strings.ReplaceAll(expr, ".", "_"))
+	}
+	return "GH_AW_" + strings.ToUpper(runtimePlaceholderRegex.ReplaceAllString(expr, "_"))
+}
+
+// renderPromptTemplate renders {{#if}} template conditionals and their {{else}} branches (see
+// renderMarkdownTemplate in interpolate_prompt.cjs). Blocks whose tags are on their own lines
+// are removed with their lines, and runs of blank lines are collapsed.
+func renderPromptTemplate(markdown string) string {
+	result := templateBlockRegex.ReplaceAllStringFunc(markdown, func(match string) string {
+		groups := templateBlockRegex.FindStringSubmatch(match)
+		thenBranch, elseBranch, hasElse := splitTemplateElseBranch(groups[4])
+		if isTemplateTruthy(groups[3]) {
+			return groups[1] + thenBranch
+		}
+		if hasElse {
+			return groups[1] + elseBranch
+		}
+		return ""
+	})
+
+	result = templateInlineRegex.ReplaceAllStringFunc(result, func(match string) string {
+		groups := templateInlineRegex.FindStringSubmatch(match)
+		thenBranch, elseBranch, _ := splitTemplateElseBranch(groups[2])
+		if isTemplateTruthy(groups[1]) {
+			return thenBranch
+		}
+		return elseBranch
+	})
+
+	return templateBlankLinesRegex.ReplaceAllString(result, "\n\n")
+}
+
+// splitTemplateElseBranch splits the body of a conditional at its {{else}} tag; an {{else}}
+// alone on its line is removed with its line
+func splitTemplateElseBranch(body string) (string, string, bool) {
+	index := strings.Index(body, "{{else}}")
+	if index < 0 {
+		return body, "", false
+	}
+	end := index + len("{{else}}")
+	lineStart := strings.LastIndexByte(body[:index], '\n') + 1
+	lineEnd := len(body)
+	if newline := strings.IndexByte(body[end:], '\n'); newline >= 0 {
+		lineEnd = end + newline + 1
+	}
+	if strings.TrimSpace(body[lineStart:index]) == "" && strings.TrimSpace(body[end:lineEnd]) == "" {
+		return body[:lineStart], body[lineEnd:], true
+	}
+	return body[:index], body[end:], true
+}
+
+// isTemplateTruthy reports whether a rendered template condition is truthy (see is_truthy.cjs)
+func isTemplateTruthy(condition string) bool {
+	switch strings.ToLower(strings.TrimSpace(condition)) {
+	case "", "false", "0", "null", "undefined":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,331 @@
+// This file provides the expression evaluation used when previewing a workflow prompt.
+//
+// A prompt is rendered at runtime in two different places, and each evaluates
+// GitHub Actions expressions in its own way:
+//
+//   - The placeholder substitution step receives its values from step environment
+//     variables, which GitHub Actions evaluates with its full expression language.
+//     Missing properties evaluate to an empty string.
+//   - runtime_import.cjs evaluates the allowed expressions of imported files itself.
+//     It only resolves property paths and "||" fallbacks, and leaves anything it
+//     cannot resolve as a literal ${{ }} expression in the prompt.
+//
+// promptContext reproduces both behaviors against an event fixture so that the
+// preview matches what the agent receives. Values that only exist while the workflow
+// runs (needs.*, steps.*, env.*, vars.*, secrets.*) cannot be resolved and are
+// recorded so they can be reported.
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+)
+
+var (
+	promptOrExpressionRegex  = regexp.MustCompile(`^(.+?)\s*\|\|\s*(.+)$`)
+	promptComparisonRegex    = regexp.MustCompile(`^(.+?)\s*(==|!=)\s*(.+)$`)
+	promptNumberLiteralRegex = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	promptArrayAccessRegex   = regexp.MustCompile(`^([a-zA-Z0-9_-]+)\[(\d+)\]$`)
+)
+
+// PromptRenderOptions describes the run a prompt is previewed for
+type PromptRenderOptions struct {
+	// EventName is the name of the triggering event (github.event_name)
+	EventName string
+	// Event is the payload of the triggering event (github.event)
+	Event map[string]any
+	// Inputs are the workflow inputs (inputs.* and github.event.inputs.*)
+	Inputs map[string]string
+	// Repository is the repository the workflow runs in, in owner/repo format
+	Repository string
+	// Actor is the user that triggered the run (github.actor)
+	Actor string
+	// PromptsDir is the directory holding the built-in prompt files that the setup
+	// action copies to /opt/gh-aw/prompts (actions/setup/md in the gh-aw repository)
+	PromptsDir string
+}
+
+// promptContext evaluates expressions against the values of a previewed run
+type promptContext struct {
+	root       map[string]any
+	unresolved []string
+}
+
+// newPromptContext builds the expression contexts of a previewed run of the workflow.
+// Run identifiers are fixed so that previews of the same event are stable.
+func newPromptContext(data *WorkflowData, opts PromptRenderOptions) *promptContext {
+	event := make(map[string]any, len(opts.Event)+1)
+	for key, value := range opts.Event {
+		event[key] = value
+	}
+
+	inputs := make(map[string]any, len(opts.Inputs))
+	if existing, ok := event["inputs"].(map[string]any); ok {
+		for key, value := range existing {
+			inputs[key] = value
+		}
+	}
+	for key, value := range opts.Inputs {
+		inputs[key] = value
+	}
+	if len(inputs) > 0 {
+		event["inputs"] = inputs
+	}
+
+	owner, repoName, _ := strings.Cut(opts.Repository, "/")
+	github := map[string]any{
+		"actor":            opts.Actor,
+		"event":            event,
+		"event_name":       opts.EventName,
+		"job":              string(constants.AgentJobName),
+		"owner":            owner,
+		"repository":       opts.Repository,
+		"repository_owner": owner,
+		"run_id":           "1",
+		"run_number":       "1",
+		"server_url":       "https://github.com",
+		"triggering_actor": opts.Actor,
+		"workflow":         data.Name,
+		"workspace":        fmt.Sprintf("/home/runner/work/%s/%s", repoName, repoName),
+	}
+
+	return &promptContext{
+		root: map[string]any{
+			"github": github,
+			"inputs": inputs,
+		},
+	}
+}
+
+// markUnresolved records an expression that the preview cannot evaluate
+func (p *promptContext) markUnresolved(expr string) {
+	for _, existing := range p.unresolved {
+		if existing == expr {
+			return
+		}
+	}
+	p.unresolved = append(p.unresolved, expr)
+}
+
+// lookup resolves a property path such as github.event.release.assets[0].id.
+// It reports false when the path leaves the known contexts or a property is missing.
+func (p *promptContext) lookup(path string) (any, bool) {
+	var value any = p.root
+	for _, part := range strings.Split(path, ".") {
+		index := -1
+		if match := promptArrayAccessRegex.FindStringSubmatch(part); match != nil {
+			part = match[1]
+			index, _ = strconv.Atoi(match[2])
+		}
+
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[part]; !ok || value == nil {
+			return nil, false
+		}
+
+		if index >= 0 {
+			array, ok := value.([]any)
+			if !ok || index >= len(array) {
+				return nil, false
+			}
+			value = array[index]
+		}
+	}
+	return value, true
+}
+
+// isRunOnlyExpression reports whether a property path refers to values that only exist
+// while the workflow runs, such as job outputs, step outputs, variables and secrets
+func isRunOnlyExpression(path string) bool {
+	root, _, _ := strings.Cut(path, ".")
+	return root != "github" && root != "inputs"
+}
+
+// evaluateRuntimeImportExpression evaluates an allowed expression the way runtime_import.cjs
+// does. It returns the expression wrapped in ${{ }} when it cannot be evaluated.
+func (p *promptContext) evaluateRuntimeImportExpression(expr string) string {
+	value, ok := p.evaluateRuntimeImportValue(strings.TrimSpace(expr))
+	if !ok {
+		p.markUnresolved(strings.TrimSpace(expr))
+	}
+	return value
+}
+
+func (p *promptContext) evaluateRuntimeImportValue(expr string) (string, bool) {
+	// OR expressions fall back to the right side when the left side cannot be evaluated
+	if match := promptOrExpressionRegex.FindStringSubmatch(expr); match != nil {
+		if left, ok := p.evaluateRuntimeImportValue(strings.TrimSpace(match[1])); ok {
+			return left, true
+		}
+		right := strings.TrimSpace(match[2])
+		if literal, ok := unquotePromptLiteral(right); ok && literal != "" {
+			// Neutralize expression markers in the fallback, as runtime_import.cjs does
+			return strings.NewReplacer("$", `\$`, "{", `\{`).Replace(literal), true
+		}
+		if promptNumberLiteralRegex.MatchString(right) || right == "true" || right == "false" {
+			return right, true
+		}
+		return p.evaluateRuntimeImportValue(right)
+	}
+
+	if value, ok := p.lookup(expr); ok {
+		return formatPromptValue(value), true
+	}
+	return "${{ " + expr + " }}", false
+}
+
+// evaluateActionsExpression evaluates an expression the way GitHub Actions evaluates the
+// environment variables of a step. ok is false when the expression depends on values
+// that only exist while the workflow runs.
+func (p *promptContext) evaluateActionsExpression(expr string) (string, bool) {
+	expr = strings.TrimSpace(expr)
+	node, err := ParseExpression(expr)
+	if err != nil {
+		p.markUnresolved(expr)
+		return "${{ " + expr + " }}", false
+	}
+	value, ok := p.evaluateNode(node)
+	if !ok {
+		p.markUnresolved(expr)
+		return "${{ " + expr + " }}", false
+	}
+	return formatPromptValue(value), true
+}
+
+func (p *promptContext) evaluateNode(node ConditionNode) (any, bool) {
+	switch n := node.(type) {
+	case *AndNode:
+		left, ok := p.evaluateNode(n.Left)
+		if !ok || !isActionsTruthy(left) {
+			return left, ok
+		}
+		return p.evaluateNode(n.Right)
+	case *OrNode:
+		left, ok := p.evaluateNode(n.Left)
+		if !ok || isActionsTruthy(left) {
+			return left, ok
+		}
+		return p.evaluateNode(n.Right)
+	case *DisjunctionNode:
+		var value any
+		for _, term := range n.Terms {
+			var ok bool
+			if value, ok = p.evaluateNode(term); !ok || isActionsTruthy(value) {
+				return value, ok
+			}
+		}
+		return value, true
+	case *NotNode:
+		value, ok := p.evaluateNode(n.Child)
+		return !isActionsTruthy(value), ok
+	case *ParenthesesNode:
+		return p.evaluateNode(n.Child)
+	case *ExpressionNode:
+		return p.evaluateOperand(n.Expression)
+	default:
+		return nil, false
+	}
+}
+
+// evaluateOperand evaluates a literal, a property path or an equality comparison
+func (p *promptContext) evaluateOperand(operand string) (any, bool) {
+	operand = strings.TrimSpace(operand)
+	if literal, ok := unquotePromptLiteral(operand); ok {
+		return literal, true
+	}
+	switch {
+	case operand == "true":
+		return true, true
+	case operand == "false":
+		return false, true
+	case operand == "null":
+		return nil, true
+	case promptNumberLiteralRegex.MatchString(operand):
+		return json.Number(operand), true
+	}
+
+	if match := promptComparisonRegex.FindStringSubmatch(operand); match != nil {
+		left, leftOK := p.evaluateOperand(match[1])
+		right, rightOK := p.evaluateOperand(match[3])
+		equal := strings.EqualFold(formatPromptValue(left), formatPromptValue(right))
+		return equal == (match[2] == "=="), leftOK && rightOK
+	}
+
+	if strings.Contains(operand, "(") || isRunOnlyExpression(operand) {
+		return nil, false
+	}
+	// Missing properties of known contexts evaluate to null in GitHub Actions
+	value, _ := p.lookup(operand)
+	return value, true
+}
+
+// unquotePromptLiteral returns the content of a quoted string literal
+func unquotePromptLiteral(value string) (string, bool) {
+	if len(value) < 2 {
+		return "", false
+	}
+	quote := value[0]
+	if (quote != '\'' && quote != '"' && quote != '`') || value[len(value)-1] != quote {
+		return "", false
+	}
+	content := value[1 : len(value)-1]
+	if quote == '\'' {
+		content = strings.ReplaceAll(content, "''", "'")
+	}
+	return content, true
+}
+
+// isActionsTruthy reports whether a value is truthy in GitHub Actions expressions
+func isActionsTruthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case json.Number:
+		f, err := v.Float64()
+		return err != nil || f != 0
+	case float64:
+		return v != 0
+	default:
+		return true
+	}
+}
+
+// formatPromptValue converts a context value to the text inserted in the prompt
+func formatPromptValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatPromptValue(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]any:
+		content, _ := json.Marshal(v)
+		return string(content)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,139 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// promptRuntimeDriver runs the interpolation and template step (interpolate_prompt.cjs) on a
+// prompt file, with the github-script globals stubbed from a fixture
+const promptRuntimeDriver = `
+const path = require("path");
+const fixture = JSON.parse(require("fs").readFileSync(process.argv[2], "utf8"));
+const [owner, repo] = fixture.repository.split("/");
+global.core = {
+  info() {},
+  debug() {},
+  warning() {},
+  setFailed(message) {
+    process.stderr.write(String(message));
+    process.exitCode = 1;
+  },
+};
+global.context = { actor: fixture.actor, job: "agent", repo: { owner, repo }, runId: 1, runNumber: 1, workflow: "Parity", payload: fixture.event };
+require(path.join(fixture.jsDir, "interpolate_prompt.cjs")).main();
+`
+
+// TestRenderPromptMatchesRuntime checks that the Go port of the interpolation and template
+// step renders the same prompts as interpolate_prompt.cjs and runtime_import.cjs
+func TestRenderPromptMatchesRuntime(t *testing.T) {
+	nodePath, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node not available, skipping test")
+	}
+	jsDir, err := filepath.Abs(filepath.Join("..", "..", "actions", "setup", "js"))
+	require.NoError(t, err, "should resolve the runtime scripts directory")
+
+	workspaceDir := t.TempDir()
+	files := map[string]string{
+		".github/workflows/triage.md": "---\non: issues\n---\n\n# Triage\n\nRepository: ${{ github.repository }}, triggered by ${{ github.actor }}.\n\n{{#runtime-import .github/workflows/shared/nested.md}}\n",
+		".github/workflows/shared/guide.md": `---
+inputs:
+  mode:
+    type: string
+---
+<!-- maintainers: keep this short -->
+{{#if ${{ github.event.issue.number }} }}
+Issue #${{ github.event.issue.number }}: ${{ github.event.issue.title }}
+{{else}}
+No issue.
+{{/if}}
+{{#if ${{ github.event.pull_request.number }} }}
+Pull request.
+{{/if}}
+`,
+		".github/workflows/shared/nested.md": "Nested {{#if ${{ github.event.issue.number }} }}with issue{{else}}without issue{{/if}}.\n",
+		".github/workflows/shared/lines.md":  "line 1\nline 2\nline 3\nline 4\n",
+		".agents/skills/review.md":           "Review skill for ${{ github.repository }}.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(workspaceDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "should create fixture directory")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644), "should write fixture file")
+	}
+
+	issueEvent := map[string]any{"issue": map[string]any{"number": 42, "title": "Crash on start"}}
+
+	tests := []struct {
+		name      string
+		prompt    string
+		event     map[string]any
+		variables map[string]string
+	}{
+		{
+			name:   "runtime imports with conditionals",
+			prompt: "<system>\nrules\n</system>\n{{#runtime-import .github/workflows/shared/guide.md}}\n{{#runtime-import .github/workflows/triage.md}}\n",
+			event:  issueEvent,
+		},
+		{
+			name:   "runtime imports without the event values",
+			prompt: "{{#runtime-import .github/workflows/shared/guide.md}}\n{{#runtime-import .github/workflows/triage.md}}\n",
+			event:  map[string]any{},
+		},
+		{
+			name:   "line ranges, optional imports and .agents",
+			prompt: "{{#runtime-import shared/lines.md:2-3}}\n{{#runtime-import? .github/workflows/shared/missing.md}}\n{{#runtime-import .agents/skills/review.md}}\n",
+			event:  issueEvent,
+		},
+		{
+			name:      "interpolated variables and template blocks",
+			prompt:    "Issue: ${GH_AW_EXPR_ISSUE}\n\n{{#if ${GH_AW_EXPR_ISSUE} }}\nTriage issue ${GH_AW_EXPR_ISSUE}.\n{{else}}\nNothing to triage.\n{{/if}}\n\n\n\n{{#if ${GH_AW_EXPR_EMPTY} }}\nHidden.\n{{/if}}\nInline {{#if true}}yes{{else}}no{{/if}}, {{#if 0}}zero{{/if}}{{#if false}}{{else}}else{{/if}}.\n",
+			event:     issueEvent,
+			variables: map[string]string{"GH_AW_EXPR_ISSUE": "42", "GH_AW_EXPR_EMPTY": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := PromptRenderOptions{EventName: "issues", Event: tt.event, Repository: "octo/repo", Actor: "octocat"}
+			renderer := &promptRenderer{
+				ctx:          newPromptContext(&WorkflowData{Name: "Parity"}, opts),
+				workspaceDir: workspaceDir,
+				variables:    tt.variables,
+				interpolate:  true,
+			}
+			goPrompt, err := renderer.render(tt.prompt)
+			require.NoError(t, err, "Go renderer should render the prompt")
+
+			testDir := t.TempDir()
+			promptPath := filepath.Join(testDir, "prompt.txt")
+			require.NoError(t, os.WriteFile(promptPath, []byte(tt.prompt), 0o644), "should write prompt file")
+			fixture, err := json.Marshal(map[string]any{"jsDir": jsDir, "repository": opts.Repository, "actor": opts.Actor, "event": tt.event})
+			require.NoError(t, err, "should encode fixture")
+			fixturePath := filepath.Join(testDir, "fixture.json")
+			require.NoError(t, os.WriteFile(fixturePath, fixture, 0o644), "should write fixture")
+			driverPath := filepath.Join(testDir, "driver.cjs")
+			require.NoError(t, os.WriteFile(driverPath, []byte(promptRuntimeDriver), 0o644), "should write driver")
+
+			cmd := exec.Command(nodePath, driverPath, fixturePath)
+			cmd.Env = append(os.Environ(), "GH_AW_PROMPT="+promptPath, "GITHUB_WORKSPACE="+workspaceDir)
+			for name, value := range tt.variables {
+				cmd.Env = append(cmd.Env, name+"="+value)
+			}
+			output, err := cmd.CombinedOutput()
+			require.NoError(t, err, "runtime renderer should render the prompt: %s", output)
+			jsPrompt, err := os.ReadFile(promptPath)
+			require.NoError(t, err, "should read the rendered prompt")
+
+			assert.Equal(t, string(jsPrompt), goPrompt, "Go and runtime renderers should produce the same prompt")
+		})
+	}
+}
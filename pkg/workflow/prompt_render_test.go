@@ -0,0 +1,174 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPromptRenderRepo writes a workflow with a runtime import to a temporary repository
+func setupPromptRenderRepo(t *testing.T, workflowContent string) string {
+	t.Helper()
+	workflowsDir := testutil.WriteWorkflowFiles(t, testutil.TempDir(t, "prompt-render-*"), map[string]string{
+		"shared/triage.md": `---
+description: Shared triage guidance
+---
+<!-- maintainers only -->
+Label issues in ${{ github.repository }} and mention @${{ github.actor }}.
+`,
+		"triage.md": workflowContent,
+	})
+	return filepath.Join(workflowsDir, "triage.md")
+}
+
+func TestRenderPrompt(t *testing.T) {
+	workflowFile := setupPromptRenderRepo(t, `---
+on:
+  issues:
+    types: [opened]
+engine: copilot
+imports:
+  - shared/triage.md
+---
+
+# Triage
+
+Triage issue #${{ github.event.issue.number }}: ${{ github.event.issue.title }}.
+
+{{#if github.event.label.name}}
+The issue was labeled.
+{{/if}}
+
+Summary: ${{ needs.activation.outputs.text }}
+`)
+
+	promptsDir := testutil.TempDir(t, "prompts-*")
+	require.NoError(t, os.WriteFile(filepath.Join(promptsDir, "xpia.md"), []byte("Follow the security policy.\n"), 0o644), "should write built-in prompt")
+
+	compiler := NewCompiler()
+	rendered, err := compiler.RenderPrompt(workflowFile, PromptRenderOptions{
+		EventName: "issues",
+		Event: map[string]any{
+			"issue": map[string]any{"number": json.Number("42"), "title": "Crash on start"},
+		},
+		Repository: "octo/repo",
+		Actor:      "octocat",
+		PromptsDir: promptsDir,
+	})
+	require.NoError(t, err, "prompt should render")
+
+	assert.Contains(t, rendered.Text, "<system>\nFollow the security policy.\n", "built-in file sections should be read from the prompts directory")
+	assert.Contains(t, rendered.Text, "Triage issue #42: Crash on start.", "expressions should be evaluated against the event")
+	assert.Contains(t, rendered.Text, "- **issue-number**: #42\n", "truthy conditionals of built-in sections should be kept")
+	assert.NotContains(t, rendered.Text, "pull-request-number", "falsy conditionals of built-in sections should be removed")
+	assert.Contains(t, rendered.Text, "The issue was labeled.", "conditionals in runtime imports are kept as the runtime does")
+	assert.Contains(t, rendered.Text, "Label issues in octo/repo and mention @octocat.", "runtime imports should be expanded")
+	assert.NotContains(t, rendered.Text, "maintainers only", "XML comments should be removed from runtime imports")
+	assert.NotContains(t, rendered.Text, "{{#runtime-import", "no runtime-import macro should remain")
+	assert.Contains(t, rendered.Text, "Summary: ${{ needs.activation.outputs.text }}", "run-only expressions should be kept as-is")
+	assert.Contains(t, rendered.Unresolved, "needs.activation.outputs.text", "run-only expressions should be reported")
+	assert.Equal(t, EstimatePromptTokens(rendered.Text), rendered.Tokens, "total tokens should be estimated from the text")
+
+	kinds := make(map[string]PromptSource)
+	for _, source := range rendered.Sources {
+		kinds[source.Kind+":"+source.Name] = source
+	}
+	workflowSource, ok := kinds["workflow:.github/workflows/triage.md"]
+	require.True(t, ok, "workflow markdown should be a source")
+	assert.Contains(t, workflowSource.Content, "Triage issue #42", "workflow source should hold its rendered content")
+	assert.Positive(t, workflowSource.Tokens, "workflow source should have a token estimate")
+	importSource, ok := kinds["import:.github/workflows/shared/triage.md"]
+	require.True(t, ok, "runtime import should be a source")
+	assert.Contains(t, importSource.Content, "Label issues", "import source should hold its rendered content")
+	assert.True(t, kinds["builtin:xpia.md"].Included, "available built-in prompt should be included")
+
+	for _, source := range rendered.Sources {
+		if source.Kind == PromptSourceBuiltin && source.Name == "pr_context_prompt.md" {
+			assert.False(t, source.Included, "PR context should not be included for issues events")
+		}
+		if source.Kind == PromptSourceBuiltin && source.Name == "markdown.md" {
+			assert.False(t, source.Included, "missing built-in prompt files should not be included")
+			assert.Contains(t, source.Note, "not found", "missing built-in prompt files should be explained")
+		}
+	}
+}
+
+func TestRenderPromptCircularImport(t *testing.T) {
+	workflowFile := setupPromptRenderRepo(t, `---
+on: workflow_dispatch
+engine: copilot
+---
+
+# Loop
+
+{{#runtime-import loop.md}}
+`)
+	loopFile := filepath.Join(filepath.Dir(workflowFile), "loop.md")
+	require.NoError(t, os.WriteFile(loopFile, []byte("{{#runtime-import loop.md}}\n"), 0o644), "should write looping import")
+
+	_, err := NewCompiler().RenderPrompt(workflowFile, PromptRenderOptions{EventName: "workflow_dispatch", Repository: "octo/repo"})
+	require.Error(t, err, "circular runtime imports should fail")
+	assert.Contains(t, err.Error(), "circular dependency detected", "error should explain the cycle")
+}
+
+func TestPromptContextEvaluateActionsExpression(t *testing.T) {
+	ctx := newPromptContext(&WorkflowData{Name: "Triage"}, PromptRenderOptions{
+		EventName:  "issues",
+		Event:      map[string]any{"issue": map[string]any{"number": json.Number("7"), "labels": []any{map[string]any{"name": "bug"}}}},
+		Inputs:     map[string]string{"mode": "fast"},
+		Repository: "octo/repo",
+		Actor:      "octocat",
+	})
+
+	tests := []struct {
+		expr     string
+		want     string
+		resolved bool
+	}{
+		{"github.event.issue.number", "7", true},
+		{"github.event.issue.labels[0].name", "bug", true},
+		{"github.event.pull_request.number || github.event.issue.number", "7", true},
+		{"github.event.comment.body", "", true},
+		{"inputs.mode", "fast", true},
+		{"github.event.inputs.mode", "fast", true},
+		{"github.event_name == 'ISSUES'", "true", true},
+		{"github.workflow", "Triage", true},
+		{"steps.sanitized.outputs.text", "${{ steps.sanitized.outputs.text }}", false},
+		{"fromJSON(github.event.issue.number)", "${{ fromJSON(github.event.issue.number) }}", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, resolved := ctx.evaluateActionsExpression(tt.expr)
+			assert.Equal(t, tt.want, got, "expression should evaluate like GitHub Actions")
+			assert.Equal(t, tt.resolved, resolved, "expression resolution should be reported")
+		})
+	}
+}
+
+func TestRenderPromptTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"truthy block", "a\n{{#if yes}}\nb\n{{/if}}\nc\n", "a\nb\nc\n"},
+		{"falsy block", "a\n\n{{#if false}}\nb\n{{/if}}\n\nc\n", "a\n\nc\n"},
+		{"inline", "a {{#if 0}}b{{/if}}{{#if x}}c{{/if}}\n", "a c\n"},
+		{"blank lines collapse", "a\n\n\n\nb\n", "a\n\nb\n"},
+		{"falsy block with else", "{{#if false}}\nStrict\n{{else}}\nLenient\n{{/if}}\nEnd", "Lenient\nEnd"},
+		{"truthy block with else", "{{#if true}}\nStrict\n{{else}}\nLenient\n{{/if}}\nEnd", "Strict\nEnd"},
+		{"inline with else", "Item{{#if false}}.{{else}},{{/if}} Item{{#if true}}.{{else}},{{/if}}", "Item, Item."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderPromptTemplate(tt.markdown), "template should render like interpolate_prompt.cjs")
+		})
+	}
+}
@@ -2,7 +2,6 @@ package workflow
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -85,7 +84,7 @@ func (c *Compiler) parsePushToPullRequestBranchConfig(outputMap map[string]any)
 					default:
 						// Invalid value, use default and log warning
 						if c.verbose {
-							fmt.Fprintf(os.Stderr, "Warning: invalid if-no-changes value '%s', using default 'warn'\n", ifNoChangesStr)
+							fmt.Fprintf(c.GetDiagnosticWriter(), "Warning: invalid if-no-changes value '%s', using default 'warn'\n", ifNoChangesStr)
 						}
 						pushToBranchConfig.IfNoChanges = "warn"
 					}
@@ -128,7 +128,7 @@ func (c *Compiler) validateRepositoryFeatures(workflowData *WorkflowData) error
 			// This could happen due to network issues or auth problems
 			repositoryFeaturesLog.Printf("Warning: Could not check if discussions are enabled: %v", err)
 			if c.verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(
 					fmt.Sprintf("Could not verify if discussions are enabled: %v", err)))
 			}
 			// Continue checking other features even if this check fails
@@ -145,7 +145,7 @@ func (c *Compiler) validateRepositoryFeatures(workflowData *WorkflowData) error
 			}
 			repositoryFeaturesLog.Printf("Warning: %s", warningMsg)
 			if c.verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(warningMsg))
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(warningMsg))
 			}
 			// Don't add to error collector - this is a warning, not an error
 		}
@@ -159,7 +159,7 @@ func (c *Compiler) validateRepositoryFeatures(workflowData *WorkflowData) error
 			// If we can't check, log but don't fail
 			repositoryFeaturesLog.Printf("Warning: Could not check if issues are enabled: %v", err)
 			if c.verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(
+				fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatWarningMessage(
 					fmt.Sprintf("Could not verify if issues are enabled: %v", err)))
 			}
 			// Continue to return aggregated errors even if this check fails
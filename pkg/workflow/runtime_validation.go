@@ -48,7 +48,6 @@ package workflow
 import (
 	"errors"
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 
@@ -131,7 +130,7 @@ func (c *Compiler) validateContainerImages(workflowData *WorkflowData) error {
 				if err := validateDockerImage(containerImage, c.verbose); err != nil {
 					errors = append(errors, fmt.Sprintf("tool '%s': %v", toolName, err))
 				} else if c.verbose {
-					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("✓ Container image validated: %s", containerImage)))
+					fmt.Fprintln(c.GetDiagnosticWriter(), console.FormatInfoMessage(fmt.Sprintf("✓ Container image validated: %s", containerImage)))
 				}
 			}
 		}
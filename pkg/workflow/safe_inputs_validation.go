@@ -0,0 +1,327 @@
+// This file provides validation for safe-inputs tool configurations.
+//
+// # Safe Inputs Validation
+//
+// This file validates safe-input tool definitions that cannot be fully expressed
+// in the frontmatter JSON schema, such as the JSON Schemas declared for tool inputs
+// and outputs, container image pinning and references between container arguments
+// and declared tool inputs.
+//
+// # Validation Functions
+//
+//   - validateSafeInputs() - Validates all safe-input tools and reports warnings
+//   - validateSafeInputSchemas() - Compiles input/output schemas and checks input defaults
+//   - validateSafeInputSchemaKeywords() - Rejects keywords the safe-inputs server does not check
+//   - validateSafeInputContainer() - Validates a single container-based tool
+//
+// # Strict Mode
+//
+// In strict mode, container images must be pinned to a digest, host networking is
+// refused and volume mounts must stay in the workspace or the runner temp directory.
+// Outside strict mode, these produce warnings. Mounting the root directory or the
+// Docker socket is always refused.
+//
+// For safe-inputs parsing, see safe_inputs_parser.go.
+// For detailed documentation, see scratchpad/validation-architecture.md
+
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+var safeInputsValidationLog = logger.New("workflow:safe_inputs_validation")
+
+// validateSafeInputs validates safe-input tool configurations.
+// Warnings are printed to stderr and counted; the first error is returned.
+func (c *Compiler) validateSafeInputs(workflowData *WorkflowData, markdownPath string) error {
+	if !HasSafeInputs(workflowData.SafeInputs) {
+		return nil
+	}
+
+	// Sort tool names for deterministic error reporting
+	toolNames := make([]string, 0, len(workflowData.SafeInputs.Tools))
+	for toolName := range workflowData.SafeInputs.Tools {
+		toolNames = append(toolNames, toolName)
+	}
+	sort.Strings(toolNames)
+
+	for _, toolName := range toolNames {
+		toolConfig := workflowData.SafeInputs.Tools[toolName]
+
+		if err := validateSafeInputSchemas(toolConfig); err != nil {
+			return err
+		}
+
+		if toolConfig.Container == nil {
+			continue
+		}
+
+		warnings, err := validateSafeInputContainer(toolConfig, c.strictMode)
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			c.warnAt(RuleStrictSafeInputsContainer, markdownPath, warning)
+		}
+	}
+
+	return nil
+}
+
+// validateSafeInputSchemas compiles the JSON Schema of every tool input and the optional
+// output schema, and checks that input defaults satisfy their own schema
+func validateSafeInputSchemas(toolConfig *SafeInputToolConfig) error {
+	inputNames := make([]string, 0, len(toolConfig.Inputs))
+	for inputName := range toolConfig.Inputs {
+		inputNames = append(inputNames, inputName)
+	}
+	sort.Strings(inputNames)
+
+	for _, inputName := range inputNames {
+		param := toolConfig.Inputs[inputName]
+		location := fmt.Sprintf("safe-inputs.%s.inputs.%s", toolConfig.Name, inputName)
+
+		paramSchema := buildSafeInputParamSchema(param)
+		schema, err := compileSafeInputSchema(location, paramSchema)
+		if err != nil {
+			return fmt.Errorf("%s: invalid JSON schema: %s", location, summarizeJSONSchemaError(err))
+		}
+		if err := validateSafeInputSchemaKeywords(location, paramSchema); err != nil {
+			return err
+		}
+
+		if param.Default != nil {
+			value, err := toJSONSchemaInstance(param.Default)
+			if err != nil {
+				return fmt.Errorf("%s: invalid default value: %w", location, err)
+			}
+			if err := schema.Validate(value); err != nil {
+				return fmt.Errorf("%s: default value %v does not match the input schema: %s", location, param.Default, summarizeJSONSchemaError(err))
+			}
+		}
+	}
+
+	if toolConfig.OutputSchema != nil {
+		location := fmt.Sprintf("safe-inputs.%s.output-schema", toolConfig.Name)
+		if _, err := compileSafeInputSchema(location, toolConfig.OutputSchema); err != nil {
+			return fmt.Errorf("%s: invalid JSON schema: %s", location, summarizeJSONSchemaError(err))
+		}
+		if err := validateSafeInputSchemaKeywords(location, toolConfig.OutputSchema); err != nil {
+			return err
+		}
+	}
+
+	safeInputsValidationLog.Printf("Validated schemas for tool %s: inputs=%d, output_schema=%t", toolConfig.Name, len(toolConfig.Inputs), toolConfig.OutputSchema != nil)
+	return nil
+}
+
+// safeInputSupportedSchemaKeywords lists the JSON Schema keywords that the safe-inputs server
+// checks at runtime (validateValueAgainstSchema in safe_inputs_validation.cjs), followed by the
+// annotations that do not constrain values
+var safeInputSupportedSchemaKeywords = []string{
+	"type", "enum", "const",
+	"minLength", "maxLength", "pattern",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minItems", "maxItems", "uniqueItems", "items",
+	"properties", "required", "additionalProperties",
+	"title", "description", "default", "examples", "format",
+}
+
+// validateSafeInputSchemaKeywords rejects JSON Schema keywords, at any depth, that the
+// safe-inputs server does not check, so that a schema never promises validation that does
+// not happen at runtime
+func validateSafeInputSchemaKeywords(location string, schema map[string]any) error {
+	keywords := make([]string, 0, len(schema))
+	for keyword := range schema {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+		if !slices.Contains(safeInputSupportedSchemaKeywords, keyword) {
+			return fmt.Errorf("%s: JSON schema keyword '%s' is not supported by safe-inputs. Supported keywords: %s", location, keyword, strings.Join(safeInputSupportedSchemaKeywords, ", "))
+		}
+
+		if value, ok := schema[keyword].(map[string]any); ok {
+			switch keyword {
+			case "items", "additionalProperties":
+				if err := validateSafeInputSchemaKeywords(location+"."+keyword, value); err != nil {
+					return err
+				}
+			case "properties":
+				names := make([]string, 0, len(value))
+				for name := range value {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					if property, ok := value[name].(map[string]any); ok {
+						if err := validateSafeInputSchemaKeywords(location+".properties."+name, property); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// compileSafeInputSchema compiles a JSON Schema document declared in frontmatter
+func compileSafeInputSchema(location string, schemaDoc map[string]any) (*jsonschema.Schema, error) {
+	doc, err := toJSONSchemaInstance(schemaDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaURL := "gh-aw://" + location + ".json"
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaURL)
+}
+
+// summarizeJSONSchemaError reduces a jsonschema error to its detail lines
+// (e.g., "value must be one of 'staging', 'production'"), dropping the internal schema URL
+func summarizeJSONSchemaError(err error) string {
+	var details []string
+	for _, line := range strings.Split(err.Error(), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "- at ") {
+			continue
+		}
+		if _, detail, found := strings.Cut(line, ": "); found {
+			details = append(details, detail)
+		}
+	}
+	if len(details) == 0 {
+		return err.Error()
+	}
+	return strings.Join(details, "; ")
+}
+
+// toJSONSchemaInstance normalizes a YAML-decoded value (which may contain uint64 or
+// other Go numeric types) into the representation expected by the jsonschema library
+func toJSONSchemaInstance(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
+
+// validateSafeInputContainer validates a container-based safe-input tool.
+// Returns warnings for non-fatal issues and an error for invalid configurations.
+func validateSafeInputContainer(toolConfig *SafeInputToolConfig, strictMode bool) ([]string, error) {
+	container := toolConfig.Container
+	safeInputsValidationLog.Printf("Validating container tool: tool=%s, image=%s, strict=%t", toolConfig.Name, container.Image, strictMode)
+
+	var warnings []string
+
+	if container.Image == "" {
+		return nil, fmt.Errorf("safe-inputs.%s.container: 'image' is required", toolConfig.Name)
+	}
+
+	if container.Digest != "" && !safeInputImageDigestPattern.MatchString(container.Digest) {
+		return nil, fmt.Errorf("safe-inputs.%s.container: invalid digest '%s'. Expected format: sha256:<64 hex characters>", toolConfig.Name, container.Digest)
+	}
+
+	// The compiler does not resolve digests, so an inline digest and the 'digest' field must agree
+	if _, imageDigest, found := strings.Cut(container.Image, "@"); found {
+		if !safeInputImageDigestPattern.MatchString(imageDigest) {
+			return nil, fmt.Errorf("safe-inputs.%s.container: invalid digest '%s' in image '%s'. Expected format: sha256:<64 hex characters>", toolConfig.Name, imageDigest, container.Image)
+		}
+		if container.Digest != "" && container.Digest != imageDigest {
+			return nil, fmt.Errorf("safe-inputs.%s.container: image '%s' is pinned to a different digest than 'digest: %s'. Remove one of them", toolConfig.Name, container.Image, container.Digest)
+		}
+	}
+
+	if !container.IsPinned() {
+		message := fmt.Sprintf("safe-inputs.%s.container: image '%s' is not pinned to a digest. Add 'digest: sha256:...' so the tool image cannot change without a recompile", toolConfig.Name, container.Image)
+		if strictMode {
+			return nil, fmt.Errorf("strict mode: %s", message)
+		}
+		warnings = append(warnings, message)
+	}
+
+	switch container.Network {
+	case "", SafeInputContainerNetworkNone, SafeInputContainerNetworkBridge:
+	case SafeInputContainerNetworkHost:
+		if strictMode {
+			return nil, fmt.Errorf("strict mode: safe-inputs.%s.container: 'network: host' is not allowed. Use 'none' (default) or 'bridge'", toolConfig.Name)
+		}
+		warnings = append(warnings, fmt.Sprintf("safe-inputs.%s.container: 'network: host' shares the runner network with the tool container", toolConfig.Name))
+	default:
+		return nil, fmt.Errorf("safe-inputs.%s.container: invalid network '%s'. Valid values: none, bridge, host", toolConfig.Name, container.Network)
+	}
+
+	for _, mount := range container.Mounts {
+		outside, err := validateSafeInputContainerMount(mount)
+		if err != nil {
+			return nil, fmt.Errorf("safe-inputs.%s.container: %w", toolConfig.Name, err)
+		}
+		if outside {
+			message := fmt.Sprintf("safe-inputs.%s.container: mount '%s' is outside the workspace and runner temp directory. Use a source under ${{ github.workspace }} or ${{ runner.temp }}", toolConfig.Name, mount)
+			if strictMode {
+				return nil, fmt.Errorf("strict mode: %s", message)
+			}
+			warnings = append(warnings, message)
+		}
+	}
+
+	// Inputs are agent-controlled, so they may only be templated into arguments,
+	// never into the mounts or entrypoint that define what the container can access
+	for _, value := range append([]string{container.Entrypoint}, container.Mounts...) {
+		if len(extractSafeInputTemplateReferences(value)) > 0 {
+			return nil, fmt.Errorf("safe-inputs.%s.container: input templates are only allowed in 'args', found in '%s'", toolConfig.Name, value)
+		}
+	}
+
+	// Every {{ inputs.<name> }} placeholder must reference a declared input
+	for _, value := range container.Args {
+		for _, ref := range extractSafeInputTemplateReferences(value) {
+			if _, exists := toolConfig.Inputs[ref]; !exists {
+				return nil, fmt.Errorf("safe-inputs.%s.container: '{{ inputs.%s }}' references an undeclared input. Declare it under safe-inputs.%s.inputs", toolConfig.Name, ref, toolConfig.Name)
+			}
+		}
+	}
+
+	if toolConfig.Timeout <= 0 {
+		return nil, fmt.Errorf("safe-inputs.%s: container tools require a positive timeout", toolConfig.Name)
+	}
+
+	return warnings, nil
+}
+
+// validateSafeInputContainerMount validates a "source:destination[:ro|rw]" volume mount and reports
+// whether its source is outside the workspace and runner temp directory. Mounting the root
+// directory or the Docker socket is an error, since either gives the tool control of the runner.
+func validateSafeInputContainerMount(mount string) (bool, error) {
+	parsed, ok := parseSafeInputContainerMount(mount)
+	if !ok {
+		return false, fmt.Errorf("invalid mount '%s'. Expected format: source:destination[:ro|rw]", mount)
+	}
+	if !filepath.IsAbs(parsed.Destination) {
+		return false, fmt.Errorf("invalid mount '%s'. Destination must be an absolute path", mount)
+	}
+	if parsed.Mode != "ro" && parsed.Mode != "rw" {
+		return false, fmt.Errorf("invalid mount '%s'. Mode must be 'ro' or 'rw'", mount)
+	}
+	if source := path.Clean(parsed.Source); source == "/" || path.Base(source) == "docker.sock" {
+		return false, fmt.Errorf("invalid mount '%s'. Mounting the root directory or the Docker socket is not allowed", mount)
+	}
+	_, _, inRoot := parsed.rootEnvVar()
+	return !inRoot, nil
+}
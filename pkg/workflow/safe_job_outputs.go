@@ -0,0 +1,186 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var safeJobOutputsLog = logger.New("workflow:safe_job_outputs")
+
+// SafeJobOutput defines a typed output of a safe-job that built-in safe outputs
+// can reference with {{ safe_jobs.<job>.<output> }}
+type SafeJobOutput struct {
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"` // "string" (default), "number" or "boolean"
+	Value       string `yaml:"value" json:"-"`                       // Expression evaluated in the safe-job (e.g., ${{ steps.deploy.outputs.url }})
+}
+
+// safeJobOutputTypes lists the supported safe-job output types
+var safeJobOutputTypes = []string{"string", "number", "boolean"}
+
+// safeJobOutputReferencePattern matches {{ safe_jobs.<job>.<output> }} references
+var safeJobOutputReferencePattern = regexp.MustCompile(`\{\{\s*safe_jobs\.([A-Za-z0-9_-]+)\.([A-Za-z0-9_-]+)\s*\}\}`)
+
+// safeJobOutputTypesEnvVar is the env var of the safe_outputs job holding the declared output types
+const safeJobOutputTypesEnvVar = "GH_AW_SAFE_JOB_OUTPUT_TYPES"
+
+// parseSafeJobOutputs parses the outputs of a safe-job. Each output is either a value
+// expression (shorthand for a string output) or an object with description, type and value.
+func parseSafeJobOutputs(outputsMap map[string]any) map[string]*SafeJobOutput {
+	outputs := make(map[string]*SafeJobOutput)
+	for name, value := range outputsMap {
+		switch v := value.(type) {
+		case string:
+			outputs[name] = &SafeJobOutput{Type: "string", Value: v}
+		case map[string]any:
+			output := &SafeJobOutput{Type: "string"}
+			if description, ok := v["description"].(string); ok {
+				output.Description = description
+			}
+			if outputType, ok := v["type"].(string); ok && outputType != "" {
+				output.Type = outputType
+			}
+			if outputValue, ok := v["value"].(string); ok {
+				output.Value = outputValue
+			}
+			outputs[name] = output
+		}
+	}
+	return outputs
+}
+
+// safeJobsWithOutputs returns the normalized names of safe-jobs that declare outputs, sorted
+func safeJobsWithOutputs(jobs map[string]*SafeJobConfig) []string {
+	var names []string
+	for jobName, jobConfig := range jobs {
+		if len(jobConfig.Outputs) > 0 {
+			names = append(names, stringutil.NormalizeSafeOutputIdentifier(jobName))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// safeJobOutputsEnvVar returns the env var of the safe_outputs job holding the outputs of a safe-job
+func safeJobOutputsEnvVar(normalizedJobName string) string {
+	return "GH_AW_SAFE_JOB_OUTPUTS_" + strings.ToUpper(normalizedJobName)
+}
+
+// addSafeJobOutputEnvVars adds the outputs of safe-jobs, and their declared types, to the
+// environment of the safe_outputs job so the handler manager can substitute references
+func addSafeJobOutputEnvVars(envVars map[string]string, jobs map[string]*SafeJobConfig) {
+	types := make(map[string]map[string]string)
+	for jobName, jobConfig := range jobs {
+		if len(jobConfig.Outputs) == 0 {
+			continue
+		}
+		normalizedJobName := stringutil.NormalizeSafeOutputIdentifier(jobName)
+		types[normalizedJobName] = make(map[string]string, len(jobConfig.Outputs))
+		for outputName, output := range jobConfig.Outputs {
+			types[normalizedJobName][outputName] = output.Type
+		}
+		envVars[safeJobOutputsEnvVar(normalizedJobName)] = fmt.Sprintf("${{ toJSON(needs.%s.outputs) }}", normalizedJobName)
+	}
+	if len(types) == 0 {
+		return
+	}
+
+	typesJSON, err := json.Marshal(types)
+	if err != nil {
+		safeJobOutputsLog.Printf("Failed to marshal safe-job output types: %v", err)
+		return
+	}
+	envVars[safeJobOutputTypesEnvVar] = fmt.Sprintf("%q", string(typesJSON))
+	safeJobOutputsLog.Printf("Added outputs of %d safe-jobs to safe_outputs job environment", len(types))
+}
+
+// validateSafeJobOutputs validates safe-job output declarations and the
+// {{ safe_jobs.<job>.<output> }} references in the workflow
+func validateSafeJobOutputs(data *WorkflowData) error {
+	var jobs map[string]*SafeJobConfig
+	if data.SafeOutputs != nil {
+		jobs = data.SafeOutputs.Jobs
+	}
+
+	// Index declared outputs by normalized job name so that deploy-preview and deploy_preview match
+	declared := make(map[string]map[string]*SafeJobOutput)
+	jobNames := make([]string, 0, len(jobs))
+	for jobName := range jobs {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	for _, jobName := range jobNames {
+		jobConfig := jobs[jobName]
+		if len(jobConfig.Outputs) == 0 {
+			continue
+		}
+
+		outputNames := make([]string, 0, len(jobConfig.Outputs))
+		for outputName := range jobConfig.Outputs {
+			outputNames = append(outputNames, outputName)
+		}
+		sort.Strings(outputNames)
+		for _, outputName := range outputNames {
+			output := jobConfig.Outputs[outputName]
+			if output.Value == "" {
+				return fmt.Errorf("safe-outputs.jobs.%s.outputs.%s: value is required (e.g., '${{ steps.<step-id>.outputs.%s }}')", jobName, outputName, outputName)
+			}
+			if !slices.Contains(safeJobOutputTypes, output.Type) {
+				return fmt.Errorf("safe-outputs.jobs.%s.outputs.%s: invalid type '%s'. Valid types: %s", jobName, outputName, output.Type, strings.Join(safeJobOutputTypes, ", "))
+			}
+		}
+
+		// The safe_outputs job waits for safe-jobs with outputs, so they cannot wait for it
+		for _, need := range jobConfig.Needs {
+			if need == "safe_outputs" {
+				return fmt.Errorf("safe-outputs.jobs.%s: a safe-job that declares outputs cannot depend on '%s' because the %s job waits for its outputs", jobName, need, need)
+			}
+		}
+
+		declared[stringutil.NormalizeSafeOutputIdentifier(jobName)] = jobConfig.Outputs
+	}
+
+	for _, content := range []string{data.FrontmatterYAML, data.MarkdownContent} {
+		for _, match := range safeJobOutputReferencePattern.FindAllStringSubmatch(content, -1) {
+			jobName, outputName := match[1], match[2]
+			outputs, exists := declared[stringutil.NormalizeSafeOutputIdentifier(jobName)]
+			if !exists {
+				if _, isJob := jobs[jobName]; isJob {
+					return fmt.Errorf("invalid reference %s: safe-job '%s' does not declare any outputs. Add them under safe-outputs.jobs.%s.outputs", match[0], jobName, jobName)
+				}
+				return fmt.Errorf("invalid reference %s: unknown safe-job '%s'. Safe-jobs with outputs: %s", match[0], jobName, formatSafeJobNames(declared))
+			}
+			if _, exists := outputs[outputName]; !exists {
+				available := make([]string, 0, len(outputs))
+				for name := range outputs {
+					available = append(available, name)
+				}
+				sort.Strings(available)
+				return fmt.Errorf("invalid reference %s: safe-job '%s' has no output '%s'. Available outputs: %s", match[0], jobName, outputName, strings.Join(available, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// formatSafeJobNames returns a sorted, comma-separated list of safe-job names for error messages
+func formatSafeJobNames(jobs map[string]map[string]*SafeJobOutput) string {
+	if len(jobs) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,205 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSafeJobOutputs(t *testing.T) {
+	outputs := parseSafeJobOutputs(map[string]any{
+		"url": "${{ steps.deploy.outputs.url }}",
+		"ticket-id": map[string]any{
+			"description": "Created ticket ID",
+			"type":        "number",
+			"value":       "${{ steps.ticket.outputs.id }}",
+		},
+		"invalid": 42,
+	})
+
+	require.Len(t, outputs, 2, "non-string, non-object outputs should be ignored")
+	assert.Equal(t, &SafeJobOutput{Type: "string", Value: "${{ steps.deploy.outputs.url }}"}, outputs["url"], "string shorthand should be a string output")
+	assert.Equal(t, &SafeJobOutput{Description: "Created ticket ID", Type: "number", Value: "${{ steps.ticket.outputs.id }}"}, outputs["ticket-id"], "object form should be parsed")
+}
+
+func TestValidateSafeJobOutputs(t *testing.T) {
+	jobs := func() map[string]*SafeJobConfig {
+		return map[string]*SafeJobConfig{
+			"deploy-preview": {
+				Outputs: map[string]*SafeJobOutput{
+					"url": {Type: "string", Value: "${{ steps.deploy.outputs.url }}"},
+				},
+			},
+			"notify": {},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		jobs        map[string]*SafeJobConfig
+		markdown    string
+		expectedErr string
+	}{
+		{
+			name:     "valid reference",
+			jobs:     jobs(),
+			markdown: "Post {{ safe_jobs.deploy-preview.url }} in a comment",
+		},
+		{
+			name:     "normalized job name",
+			jobs:     jobs(),
+			markdown: "Post {{safe_jobs.deploy_preview.url}} in a comment",
+		},
+		{
+			name:        "unknown job",
+			jobs:        jobs(),
+			markdown:    "{{ safe_jobs.deploy.url }}",
+			expectedErr: "unknown safe-job 'deploy'. Safe-jobs with outputs: deploy_preview",
+		},
+		{
+			name:        "job without outputs",
+			jobs:        jobs(),
+			markdown:    "{{ safe_jobs.notify.url }}",
+			expectedErr: "safe-job 'notify' does not declare any outputs",
+		},
+		{
+			name:        "unknown output",
+			jobs:        jobs(),
+			markdown:    "{{ safe_jobs.deploy-preview.link }}",
+			expectedErr: "has no output 'link'. Available outputs: url",
+		},
+		{
+			name: "invalid type",
+			jobs: map[string]*SafeJobConfig{
+				"deploy": {Outputs: map[string]*SafeJobOutput{"url": {Type: "object", Value: "x"}}},
+			},
+			expectedErr: "safe-outputs.jobs.deploy.outputs.url: invalid type 'object'",
+		},
+		{
+			name: "missing value",
+			jobs: map[string]*SafeJobConfig{
+				"deploy": {Outputs: map[string]*SafeJobOutput{"url": {Type: "string"}}},
+			},
+			expectedErr: "safe-outputs.jobs.deploy.outputs.url: value is required",
+		},
+		{
+			name: "depends on safe_outputs",
+			jobs: map[string]*SafeJobConfig{
+				"deploy": {
+					Needs:   []string{"safe_outputs"},
+					Outputs: map[string]*SafeJobOutput{"url": {Type: "string", Value: "x"}},
+				},
+			},
+			expectedErr: "cannot depend on 'safe_outputs'",
+		},
+		{
+			name:        "reference without safe-outputs",
+			markdown:    "{{ safe_jobs.deploy.url }}",
+			expectedErr: "Safe-jobs with outputs: none",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &WorkflowData{MarkdownContent: tt.markdown}
+			if tt.jobs != nil {
+				data.SafeOutputs = &SafeOutputsConfig{Jobs: tt.jobs}
+			}
+
+			err := validateSafeJobOutputs(data)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err, "validation should pass")
+				return
+			}
+			require.Error(t, err, "validation should fail")
+			assert.Contains(t, err.Error(), tt.expectedErr, "error should explain the problem")
+		})
+	}
+}
+
+func TestSafeJobOutputsCompilation(t *testing.T) {
+	markdown := `---
+on: issues
+safe-outputs:
+  add-comment:
+  jobs:
+    deploy-preview:
+      description: Deploy a preview environment
+      outputs:
+        url:
+          description: Preview URL
+          value: ${{ steps.deploy.outputs.url }}
+        ready:
+          type: boolean
+          value: ${{ steps.deploy.outputs.ready }}
+      steps:
+        - id: deploy
+          run: echo "url=https://preview.example.com" >> "$GITHUB_OUTPUT"
+    notify:
+      steps:
+        - run: echo "notify"
+---
+
+# Deploy
+
+Deploy a preview and comment with {{ safe_jobs.deploy-preview.url }}.
+`
+
+	tmpDir := testutil.TempDir(t, "safe-job-outputs-*")
+	testFile := filepath.Join(tmpDir, "deploy.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	c := NewCompiler()
+	require.NoError(t, c.CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "deploy.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "      url: ${{ steps.deploy.outputs.url }}", "safe-job should expose declared outputs")
+	assert.Contains(t, lock, "      ready: ${{ steps.deploy.outputs.ready }}", "safe-job should expose typed outputs")
+	assert.Contains(t, lock, "GH_AW_SAFE_JOB_OUTPUTS_DEPLOY_PREVIEW: ${{ toJSON(needs.deploy_preview.outputs) }}", "safe_outputs job should receive safe-job outputs")
+	assert.Contains(t, lock, `GH_AW_SAFE_JOB_OUTPUT_TYPES: "{\"deploy_preview\":{\"ready\":\"boolean\",\"url\":\"string\"}}"`, "safe_outputs job should receive output types")
+	assert.Contains(t, lock, "{{ safe_jobs.deploy_preview.url }} (string: Preview URL)", "tool description should list the outputs")
+
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	needsEnd := strings.Index(safeOutputsJob, "    runs-on:")
+	require.Positive(t, needsEnd, "safe_outputs job should have runs-on")
+	assert.Contains(t, safeOutputsJob[:needsEnd], "- deploy_preview", "safe_outputs job should wait for safe-jobs with outputs")
+	assert.NotContains(t, safeOutputsJob[:needsEnd], "- notify", "safe_outputs job should not wait for safe-jobs without outputs")
+}
+
+func TestSafeJobOutputsCompilationInvalidReference(t *testing.T) {
+	markdown := `---
+on: issues
+safe-outputs:
+  add-comment:
+  jobs:
+    deploy:
+      outputs:
+        url: ${{ steps.deploy.outputs.url }}
+      steps:
+        - id: deploy
+          run: echo "deploy"
+---
+
+# Deploy
+
+Comment with {{ safe_jobs.deploy.link }}.
+`
+
+	tmpDir := testutil.TempDir(t, "safe-job-outputs-*")
+	testFile := filepath.Join(tmpDir, "deploy.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	err := NewCompiler().CompileWorkflow(testFile)
+	require.Error(t, err, "invalid reference should fail compilation")
+	assert.Contains(t, err.Error(), "safe-job 'deploy' has no output 'link'", "error should name the missing output")
+}
@@ -27,6 +27,7 @@ type SafeJobConfig struct {
 	Inputs      map[string]*InputDefinition `yaml:"inputs,omitempty"`
 	GitHubToken string                      `yaml:"github-token,omitempty"`
 	Output      string                      `yaml:"output,omitempty"`
+	Outputs     map[string]*SafeJobOutput   `yaml:"outputs,omitempty"`
 }
 
 // HasSafeJobsEnabled checks if any safe-jobs are enabled at the top level
@@ -137,6 +138,13 @@ func (c *Compiler) parseSafeJobsConfig(jobsMap map[string]any) map[string]*SafeJ
 			}
 		}
 
+		// Parse typed outputs that other safe outputs can reference
+		if outputs, exists := jobConfig["outputs"]; exists {
+			if outputsMap, ok := outputs.(map[string]any); ok {
+				safeJob.Outputs = parseSafeJobOutputs(outputsMap)
+			}
+		}
+
 		// Parse inputs using the unified parsing function
 		if inputs, exists := jobConfig["inputs"]; exists {
 			if inputsMap, ok := inputs.(map[string]any); ok {
@@ -270,6 +278,14 @@ func (c *Compiler) buildSafeJobs(data *WorkflowData, threatDetectionEnabled bool
 
 		job.Steps = steps
 
+		// Expose declared outputs as job outputs so the safe_outputs job can read them
+		if len(jobConfig.Outputs) > 0 {
+			job.Outputs = make(map[string]string, len(jobConfig.Outputs))
+			for outputName, output := range jobConfig.Outputs {
+				job.Outputs[outputName] = output.Value
+			}
+		}
+
 		// Set permissions if specified
 		if len(jobConfig.Permissions) > 0 {
 			// Build Permissions struct from map
@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var safeOutputApprovalLog = logger.New("workflow:safe_output_approval")
+
+// safeOutputApprovalTypes lists the safe output types that support require-approval.
+// These are applied by the handler manager and are risky enough to warrant a human gate.
+var safeOutputApprovalTypes = []string{
+	"create-pull-request",
+	"create-release",
+	"create-tag",
+	"dispatch-workflow",
+	"merge-pull-request",
+	"push-to-pull-request-branch",
+	"update-file",
+}
+
+// approvalJobNamePattern matches characters that are not allowed in job names
+var approvalJobNamePattern = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// safeOutputApprovalGates returns the safe outputs that require approval, keyed by tool name,
+// with the GitHub Environment that gates each of them
+func safeOutputApprovalGates(safeOutputs *SafeOutputsConfig) map[string]string {
+	if safeOutputs == nil {
+		return nil
+	}
+
+	gates := make(map[string]string)
+	val := reflect.ValueOf(safeOutputs).Elem()
+	for fieldName, toolName := range safeOutputFieldMapping {
+		field := val.FieldByName(fieldName)
+		if !field.IsValid() || field.IsNil() {
+			continue
+		}
+		requireApproval := field.Elem().FieldByName("RequireApproval")
+		if requireApproval.IsValid() && requireApproval.String() != "" {
+			gates[toolName] = requireApproval.String()
+		}
+	}
+
+	if len(gates) == 0 {
+		return nil
+	}
+	safeOutputApprovalLog.Printf("Found %d safe output(s) requiring approval", len(gates))
+	return gates
+}
+
+// validateSafeOutputApprovals checks that require-approval is only used on supported safe output types
+func validateSafeOutputApprovals(safeOutputs *SafeOutputsConfig) error {
+	gates := safeOutputApprovalGates(safeOutputs)
+	toolNames := make([]string, 0, len(gates))
+	for toolName := range gates {
+		toolNames = append(toolNames, toolName)
+	}
+	sort.Strings(toolNames)
+
+	for _, toolName := range toolNames {
+		safeOutputType := strings.ReplaceAll(toolName, "_", "-")
+		if !slices.Contains(safeOutputApprovalTypes, safeOutputType) {
+			return fmt.Errorf("safe-outputs.%s: require-approval is not supported for this type. Supported types: %s", safeOutputType, strings.Join(safeOutputApprovalTypes, ", "))
+		}
+		if strings.TrimSpace(gates[toolName]) == "" {
+			return fmt.Errorf("safe-outputs.%s: require-approval must name a GitHub Environment", safeOutputType)
+		}
+	}
+
+	// Each environment gets its own job, so environments must not normalize to the same job name
+	jobEnvironments := make(map[string]string)
+	for _, environment := range approvalEnvironments(gates) {
+		jobName := approvalJobName(environment)
+		if other, exists := jobEnvironments[jobName]; exists {
+			return fmt.Errorf("safe-outputs: require-approval environments '%s' and '%s' both map to job '%s'. Use environment names that differ in more than case or punctuation", other, environment, jobName)
+		}
+		jobEnvironments[jobName] = environment
+	}
+	return nil
+}
+
+// approvalEnvironments returns the distinct environments used by the approval gates in sorted order
+func approvalEnvironments(gates map[string]string) []string {
+	var environments []string
+	for _, environment := range gates {
+		if !slices.Contains(environments, environment) {
+			environments = append(environments, environment)
+		}
+	}
+	sort.Strings(environments)
+	return environments
+}
+
+// approvalJobName returns the name of the job that applies the safe outputs gated by an environment
+func approvalJobName(environment string) string {
+	suffix := strings.Trim(approvalJobNamePattern.ReplaceAllString(strings.ToLower(environment), "_"), "_")
+	if suffix == "" {
+		suffix = "environment"
+	}
+	return "safe_outputs_approval_" + suffix
+}
+
+// filterSafeOutputTypes returns a shallow copy of the safe outputs configuration that only keeps
+// the safe output types accepted by keep. Global settings (tokens, app, messages) are preserved.
+func filterSafeOutputTypes(safeOutputs *SafeOutputsConfig, keep func(toolName string) bool) *SafeOutputsConfig {
+	filtered := *safeOutputs
+	val := reflect.ValueOf(&filtered).Elem()
+	for fieldName, toolName := range safeOutputFieldMapping {
+		field := val.FieldByName(fieldName)
+		if field.IsValid() && !field.IsNil() && !keep(toolName) {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+	return &filtered
+}
+
+// withoutApprovalGatedOutputs returns the workflow data used to build the safe_outputs job when
+// some safe outputs require approval. Gated types are removed so the job neither applies them
+// nor receives their permissions.
+func withoutApprovalGatedOutputs(data *WorkflowData, gates map[string]string) *WorkflowData {
+	if len(gates) == 0 {
+		return data
+	}
+	mainData := *data
+	mainData.SafeOutputs = filterSafeOutputTypes(data.SafeOutputs, func(toolName string) bool {
+		_, gated := gates[toolName]
+		return !gated
+	})
+	return &mainData
+}
+
+// approvalGatesEnvValue renders the approval gates as the quoted JSON value of GH_AW_SAFE_OUTPUTS_APPROVAL
+func approvalGatesEnvValue(gates map[string]string) string {
+	gatesJSON, err := json.Marshal(gates)
+	if err != nil {
+		safeOutputApprovalLog.Printf("Failed to marshal approval gates: %v", err)
+		return `"{}"`
+	}
+	return fmt.Sprintf("%q", string(gatesJSON))
+}
+
+// addApprovalPreviewToSafeOutputsJob configures the safe_outputs job to skip the gated safe outputs,
+// write a staged preview of them for reviewers, and report which environments have pending items
+func addApprovalPreviewToSafeOutputsJob(job *Job, gates map[string]string) {
+	if job.Env == nil {
+		job.Env = make(map[string]string)
+	}
+	job.Env["GH_AW_SAFE_OUTPUTS_APPROVAL"] = approvalGatesEnvValue(gates)
+	job.Outputs["pending_approval_environments"] = "${{ steps.process_safe_outputs.outputs.pending_approval_environments }}"
+}
+
+// buildApprovalSafeOutputsJobs builds one job per environment that applies the safe outputs
+// requiring approval. Each job is bound to its GitHub Environment so the run pauses until a
+// reviewer approves it. When the safe_outputs job previews the gated items, the approval job
+// waits for it, only runs if that environment has pending items, and reuses its temporary IDs.
+func (c *Compiler) buildApprovalSafeOutputsJobs(data *WorkflowData, gates map[string]string, mainJobName, markdownPath string, previewJob *Job) ([]*Job, error) {
+	var jobs []*Job
+	for _, environment := range approvalEnvironments(gates) {
+		approvalData := *data
+		approvalData.SafeOutputs = filterSafeOutputTypes(data.SafeOutputs, func(toolName string) bool {
+			return gates[toolName] == environment
+		})
+		approvalData.SafeOutputs.Jobs = nil
+
+		job, _, err := c.buildConsolidatedSafeOutputsJob(&approvalData, mainJobName, markdownPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build approval job for environment '%s': %w", environment, err)
+		}
+		if job == nil {
+			continue
+		}
+
+		job.Name = approvalJobName(environment)
+		job.Environment = fmt.Sprintf("environment: %q", stringutil.StripANSIEscapeCodes(environment))
+		job.Env["GH_AW_SAFE_OUTPUTS_APPROVAL"] = approvalGatesEnvValue(gates)
+		job.Env["GH_AW_SAFE_OUTPUTS_APPROVAL_ENVIRONMENT"] = fmt.Sprintf("%q", environment)
+
+		if previewJob != nil {
+			job.Needs = append(job.Needs, previewJob.Name)
+			job.Env["GH_AW_TEMPORARY_ID_MAP"] = fmt.Sprintf("${{ needs.%s.outputs.process_safe_outputs_temporary_id_map }}", previewJob.Name)
+			hasPendingItems := BuildFunctionCall("contains",
+				BuildPropertyAccess(fmt.Sprintf("needs.%s.outputs.pending_approval_environments", previewJob.Name)),
+				BuildStringLiteral(fmt.Sprintf("%q", environment)),
+			)
+			job.If = BuildAnd(&ExpressionNode{Expression: job.If}, hasPendingItems).Render()
+		}
+
+		safeOutputApprovalLog.Printf("Built approval job %s for environment %s", job.Name, environment)
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,111 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeOutputApprovalGates(t *testing.T) {
+	config := &SafeOutputsConfig{
+		AddComments:        &AddCommentsConfig{},
+		CreatePullRequests: &CreatePullRequestsConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{RequireApproval: "production"}},
+		DispatchWorkflow:   &DispatchWorkflowConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{RequireApproval: "ops"}},
+	}
+
+	gates := safeOutputApprovalGates(config)
+	assert.Equal(t, map[string]string{"create_pull_request": "production", "dispatch_workflow": "ops"}, gates, "gated types should map to their environment")
+	assert.Equal(t, []string{"ops", "production"}, approvalEnvironments(gates), "environments should be distinct and sorted")
+	assert.Nil(t, safeOutputApprovalGates(&SafeOutputsConfig{AddComments: &AddCommentsConfig{}}), "no gates without require-approval")
+}
+
+func TestValidateSafeOutputApprovals(t *testing.T) {
+	valid := &SafeOutputsConfig{PushToPullRequestBranch: &PushToPullRequestBranchConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{RequireApproval: "production"}}}
+	require.NoError(t, validateSafeOutputApprovals(valid), "supported types should accept require-approval")
+
+	invalid := &SafeOutputsConfig{AddComments: &AddCommentsConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{RequireApproval: "production"}}}
+	err := validateSafeOutputApprovals(invalid)
+	require.Error(t, err, "unsupported types should reject require-approval")
+	assert.Contains(t, err.Error(), "safe-outputs.add-comment: require-approval is not supported", "error should name the type")
+
+	colliding := &SafeOutputsConfig{
+		CreatePullRequests: &CreatePullRequestsConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{RequireApproval: "Prod-EU"}},
+		DispatchWorkflow:   &DispatchWorkflowConfig{BaseSafeOutputConfig: BaseSafeOutputConfig{RequireApproval: "prod eu"}},
+	}
+	err = validateSafeOutputApprovals(colliding)
+	require.Error(t, err, "environments that normalize to the same job name should be rejected")
+	assert.Contains(t, err.Error(), "both map to job 'safe_outputs_approval_prod_eu'", "error should name the colliding job")
+}
+
+func TestApprovalJobName(t *testing.T) {
+	assert.Equal(t, "safe_outputs_approval_production", approvalJobName("production"), "simple names are kept")
+	assert.Equal(t, "safe_outputs_approval_prod_eu_west", approvalJobName("Prod EU-west"), "names are normalized")
+	assert.Equal(t, "safe_outputs_approval_environment", approvalJobName("🚀"), "names without valid characters get a fallback")
+}
+
+func TestFilterSafeOutputTypes(t *testing.T) {
+	config := &SafeOutputsConfig{
+		AddComments:        &AddCommentsConfig{},
+		CreatePullRequests: &CreatePullRequestsConfig{},
+		GitHubToken:        "${{ secrets.TOKEN }}",
+	}
+
+	filtered := filterSafeOutputTypes(config, func(toolName string) bool { return toolName != "create_pull_request" })
+
+	assert.NotNil(t, filtered.AddComments, "kept types should remain")
+	assert.Nil(t, filtered.CreatePullRequests, "filtered types should be removed")
+	assert.Equal(t, "${{ secrets.TOKEN }}", filtered.GitHubToken, "global settings should be preserved")
+	assert.NotNil(t, config.CreatePullRequests, "original config should not be modified")
+}
+
+func TestSafeOutputApprovalCompilation(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  add-comment:
+  create-pull-request:
+    require-approval: production
+---
+
+# Fixer
+
+Fix the bug and open a pull request.
+`
+
+	tmpDir := testutil.TempDir(t, "safe-output-approval-*")
+	testFile := filepath.Join(tmpDir, "fixer.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "fixer.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	safeOutputsJob = safeOutputsJob[:strings.Index(safeOutputsJob[1:], "\n  safe_outputs_approval_production:\n")+1]
+	assert.NotContains(t, safeOutputsJob, "contents: write", "safe_outputs job should not get the gated permissions")
+	assert.NotContains(t, safeOutputsJob, `\"create_pull_request\":{`, "safe_outputs job should not configure the gated handler")
+	assert.Contains(t, safeOutputsJob, `GH_AW_SAFE_OUTPUTS_APPROVAL: "{\"create_pull_request\":\"production\"}"`, "safe_outputs job should preview the gated outputs")
+	assert.Contains(t, safeOutputsJob, "pending_approval_environments: ${{ steps.process_safe_outputs.outputs.pending_approval_environments }}", "safe_outputs job should report pending environments")
+
+	require.Contains(t, lock, "\n  safe_outputs_approval_production:\n", "approval job should be generated")
+	approvalJob := lock[strings.Index(lock, "\n  safe_outputs_approval_production:\n"):]
+	approvalJob = approvalJob[:strings.Index(approvalJob, "\n    steps:")]
+	assert.Contains(t, approvalJob, `environment: "production"`, "approval job should be bound to the environment")
+	assert.Contains(t, approvalJob, "- safe_outputs", "approval job should wait for the preview")
+	assert.Contains(t, approvalJob, `contains(needs.safe_outputs.outputs.pending_approval_environments, '"production"')`, "approval job should only run with pending items")
+	assert.Contains(t, approvalJob, "contents: write", "approval job should get the gated permissions")
+	assert.Contains(t, approvalJob, "GH_AW_TEMPORARY_ID_MAP: ${{ needs.safe_outputs.outputs.process_safe_outputs_temporary_id_map }}", "approval job should reuse temporary IDs")
+	assert.Contains(t, approvalJob, `GH_AW_SAFE_OUTPUTS_APPROVAL_ENVIRONMENT: "production"`, "approval job should know its environment")
+}
@@ -22,4 +22,11 @@ func (c *Compiler) parseBaseSafeOutputConfig(configMap map[string]any, config *B
 			config.GitHubToken = githubTokenStr
 		}
 	}
+
+	// Parse require-approval (GitHub Environment that gates this output type)
+	if requireApproval, exists := configMap["require-approval"]; exists {
+		if requireApprovalStr, ok := requireApproval.(string); ok {
+			config.RequireApproval = requireApprovalStr
+		}
+	}
 }
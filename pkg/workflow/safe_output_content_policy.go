@@ -0,0 +1,224 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var safeOutputContentPolicyLog = logger.New("workflow:safe_output_content_policy")
+
+// safeOutputToolTextFields returns the text fields of every safe output tool, derived from the
+// tool input schemas: free-form string properties (without enum or pattern), including those of
+// array items and nested objects. "[]" marks array items, e.g. "annotations[].message".
+var safeOutputToolTextFields = sync.OnceValue(func() map[string][]string {
+	var tools []struct {
+		Name        string         `json:"name"`
+		InputSchema map[string]any `json:"inputSchema"`
+	}
+	if err := json.Unmarshal([]byte(GetSafeOutputsToolsJSON()), &tools); err != nil {
+		safeOutputContentPolicyLog.Printf("Failed to parse safe outputs tools JSON: %v", err)
+		return nil
+	}
+	fields := make(map[string][]string, len(tools))
+	for _, tool := range tools {
+		var toolFields []string
+		collectSchemaTextFields(tool.InputSchema, "", &toolFields)
+		fields[tool.Name] = toolFields
+	}
+	return fields
+})
+
+// collectSchemaTextFields appends the paths of the free-form string fields of a JSON schema
+func collectSchemaTextFields(schema map[string]any, path string, fields *[]string) {
+	switch schema["type"] {
+	case "string":
+		_, hasEnum := schema["enum"]
+		_, hasPattern := schema["pattern"]
+		if path != "" && !hasEnum && !hasPattern {
+			*fields = append(*fields, path)
+		}
+	case "array":
+		if items, ok := schema["items"].(map[string]any); ok {
+			collectSchemaTextFields(items, path+"[]", fields)
+		}
+	case "object":
+		properties, _ := schema["properties"].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(properties)) {
+			if property, ok := properties[name].(map[string]any); ok {
+				childPath := name
+				if path != "" {
+					childPath = path + "." + name
+				}
+				collectSchemaTextFields(property, childPath, fields)
+			}
+		}
+	}
+}
+
+// contentPolicyTextFields returns the text fields checked by the content policy for each enabled
+// safe output tool. Tools without a schema (safe jobs) have no entry; all their strings are checked.
+func contentPolicyTextFields(config *SafeOutputsConfig) map[string][]string {
+	allFields := safeOutputToolTextFields()
+	fields := make(map[string][]string)
+	for _, toolName := range getEnabledSafeOutputToolNamesReflection(config) {
+		if toolFields, ok := allFields[toolName]; ok {
+			fields[toolName] = toolFields
+		}
+	}
+	return fields
+}
+
+// jsIncompatibleRegexSyntax matches RE2 syntax that JavaScript rejects or interprets differently:
+// inline flags, (?P<name>...), \A, \z, \Q...\E, \C, Unicode classes, \x{...} and POSIX classes.
+// Escaped backslashes are removed before matching.
+var jsIncompatibleRegexSyntax = regexp.MustCompile(`\(\?[a-zA-Z-]+[:)]|\(\?P<|\\[AzQECpP]|\\x\{|\[\[:`)
+
+// validateContentPolicyPattern checks that a pattern has the same meaning in Go (RE2) and in
+// JavaScript, which enforces it at runtime with the "i" flag
+func validateContentPolicyPattern(key string, pattern string) error {
+	if _, err := regexp.Compile("(?i)" + pattern); err != nil {
+		return fmt.Errorf("safe-outputs.content-policy.%s: invalid regular expression '%s': %w", key, pattern, err)
+	}
+	if syntax := jsIncompatibleRegexSyntax.FindString(strings.ReplaceAll(pattern, `\\`, "")); syntax != "" {
+		return fmt.Errorf("safe-outputs.content-policy.%s: regular expression '%s' uses '%s', which JavaScript does not support. Use syntax that Go and JavaScript share; patterns are always case-insensitive", key, pattern, syntax)
+	}
+	return nil
+}
+
+// ContentPolicyConfig holds team-specific content rules enforced on the text of every safe output
+type ContentPolicyConfig struct {
+	Deny             []string            `yaml:"deny,omitempty" json:"deny,omitempty"`                           // Regular expressions (case-insensitive) that must not match any text field
+	Allow            []string            `yaml:"allow,omitempty" json:"allow,omitempty"`                         // Exceptions: deny matches whose text matches one of these are ignored
+	MaxLength        map[string]int      `yaml:"max-length,omitempty" json:"max_length,omitempty"`               // Maximum length per text field (title, body, annotations[].message, ...)
+	RequiredSections map[string][]string `yaml:"required-sections,omitempty" json:"required_sections,omitempty"` // Headers that must appear in the body, keyed by safe output type
+	BannedWords      []string            `yaml:"banned-words,omitempty" json:"banned_words,omitempty"`           // Words (case-insensitive, whole word) that must not appear
+	DetectSecrets    bool                `yaml:"detect-secrets,omitempty" json:"detect_secrets,omitempty"`       // Reject text containing secret-looking strings
+
+	TextFields map[string][]string `yaml:"-" json:"text_fields,omitempty"` // Text fields by tool name, derived from the tool schemas
+}
+
+// parseContentPolicyConfig parses the content-policy block from the safe-outputs configuration
+func parseContentPolicyConfig(contentPolicy any) *ContentPolicyConfig {
+	policyMap, ok := contentPolicy.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	config := &ContentPolicyConfig{
+		Deny:        ParseStringArrayFromConfig(policyMap, "deny", safeOutputContentPolicyLog),
+		Allow:       ParseStringArrayFromConfig(policyMap, "allow", safeOutputContentPolicyLog),
+		BannedWords: ParseStringArrayFromConfig(policyMap, "banned-words", safeOutputContentPolicyLog),
+	}
+
+	if maxLength, ok := policyMap["max-length"].(map[string]any); ok {
+		config.MaxLength = make(map[string]int, len(maxLength))
+		for field, value := range maxLength {
+			if length, ok := parseIntValue(value); ok {
+				config.MaxLength[field] = length
+			}
+		}
+	}
+
+	if requiredSections, ok := policyMap["required-sections"].(map[string]any); ok {
+		config.RequiredSections = make(map[string][]string, len(requiredSections))
+		for safeOutputType, headers := range requiredSections {
+			config.RequiredSections[safeOutputType] = ParseStringArrayFromConfig(map[string]any{"headers": headers}, "headers", safeOutputContentPolicyLog)
+		}
+	}
+
+	if detectSecrets, ok := policyMap["detect-secrets"].(bool); ok {
+		config.DetectSecrets = detectSecrets
+	}
+
+	safeOutputContentPolicyLog.Printf("Parsed content policy: deny=%d, allow=%d, banned-words=%d, max-length=%d, required-sections=%d, detect-secrets=%t",
+		len(config.Deny), len(config.Allow), len(config.BannedWords), len(config.MaxLength), len(config.RequiredSections), config.DetectSecrets)
+	return config
+}
+
+// validateSafeOutputContentPolicy checks that the content policy patterns compile in both Go and
+// JavaScript, that length limits name text fields of enabled safe outputs, and that required
+// sections target enabled safe output types.
+func validateSafeOutputContentPolicy(config *SafeOutputsConfig) error {
+	if config == nil || config.ContentPolicy == nil {
+		return nil
+	}
+	policy := config.ContentPolicy
+
+	for _, key := range []struct {
+		name     string
+		patterns []string
+	}{{"deny", policy.Deny}, {"allow", policy.Allow}} {
+		for _, pattern := range key.patterns {
+			if err := validateContentPolicyPattern(key.name, pattern); err != nil {
+				return err
+			}
+		}
+	}
+
+	var textFields []string
+	for _, toolFields := range contentPolicyTextFields(config) {
+		textFields = append(textFields, toolFields...)
+	}
+	slices.Sort(textFields)
+	textFields = slices.Compact(textFields)
+	for _, field := range slices.Sorted(maps.Keys(policy.MaxLength)) {
+		if !slices.Contains(textFields, field) {
+			return fmt.Errorf("safe-outputs.content-policy.max-length: unknown field '%s'. Text fields of the enabled safe outputs: %s", field, strings.Join(textFields, ", "))
+		}
+		if policy.MaxLength[field] < 1 {
+			return fmt.Errorf("safe-outputs.content-policy.max-length.%s: must be a positive number, got %d", field, policy.MaxLength[field])
+		}
+	}
+
+	enabledTools := getEnabledSafeOutputToolNamesReflection(config)
+	for _, safeOutputType := range slices.Sorted(maps.Keys(policy.RequiredSections)) {
+		if !slices.Contains(enabledTools, safeOutputTypeToToolName(safeOutputType)) {
+			return fmt.Errorf("safe-outputs.content-policy.required-sections: '%s' is not enabled. Add it to safe-outputs or remove its required sections", safeOutputType)
+		}
+	}
+
+	return nil
+}
+
+// contentPolicyEnvValue renders the content policy as the quoted JSON value of
+// GH_AW_SAFE_OUTPUTS_CONTENT_POLICY, with required sections keyed by tool name and the text
+// fields of the enabled tools.
+// Returns an empty string when no content policy is configured.
+func contentPolicyEnvValue(safeOutputs *SafeOutputsConfig) string {
+	if safeOutputs == nil || safeOutputs.ContentPolicy == nil {
+		return ""
+	}
+
+	policy := *safeOutputs.ContentPolicy
+	if len(policy.RequiredSections) > 0 {
+		policy.RequiredSections = make(map[string][]string, len(safeOutputs.ContentPolicy.RequiredSections))
+		for safeOutputType, headers := range safeOutputs.ContentPolicy.RequiredSections {
+			policy.RequiredSections[safeOutputTypeToToolName(safeOutputType)] = headers
+		}
+	}
+	if textFields := contentPolicyTextFields(safeOutputs); len(textFields) > 0 {
+		policy.TextFields = textFields
+	}
+
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		safeOutputContentPolicyLog.Printf("Failed to marshal content policy: %v", err)
+		return ""
+	}
+	return fmt.Sprintf("%q", string(policyJSON))
+}
+
+// addSafeOutputContentPolicyEnvVar adds GH_AW_SAFE_OUTPUTS_CONTENT_POLICY so the handler manager
+// can block safe outputs whose text violates the content policy before calling the GitHub API
+func (c *Compiler) addSafeOutputContentPolicyEnvVar(steps *[]string, data *WorkflowData) {
+	if value := contentPolicyEnvValue(data.SafeOutputs); value != "" {
+		*steps = append(*steps, fmt.Sprintf("          GH_AW_SAFE_OUTPUTS_CONTENT_POLICY: %s\n", value))
+	}
+}
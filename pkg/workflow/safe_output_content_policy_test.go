@@ -0,0 +1,165 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentPolicyConfig(t *testing.T) {
+	config := parseContentPolicyConfig(map[string]any{
+		"deny":              []any{`internal\.example\.com`},
+		"allow":             []any{`docs\.internal\.example\.com`},
+		"max-length":        map[string]any{"title": 80, "body": uint64(5000)},
+		"required-sections": map[string]any{"create-pull-request": []any{"## Summary", "## Testing"}},
+		"banned-words":      []any{"confidential"},
+		"detect-secrets":    true,
+	})
+
+	require.NotNil(t, config, "content policy should be parsed")
+	assert.Equal(t, []string{`internal\.example\.com`}, config.Deny, "deny patterns")
+	assert.Equal(t, []string{`docs\.internal\.example\.com`}, config.Allow, "allow patterns")
+	assert.Equal(t, map[string]int{"title": 80, "body": 5000}, config.MaxLength, "max lengths")
+	assert.Equal(t, map[string][]string{"create-pull-request": {"## Summary", "## Testing"}}, config.RequiredSections, "required sections")
+	assert.Equal(t, []string{"confidential"}, config.BannedWords, "banned words")
+	assert.True(t, config.DetectSecrets, "detect secrets")
+
+	assert.Nil(t, parseContentPolicyConfig("invalid"), "non-object content policy should be ignored")
+}
+
+func TestValidateSafeOutputContentPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *ContentPolicyConfig
+		wantErr string
+	}{
+		{
+			name:   "valid policy",
+			policy: &ContentPolicyConfig{Deny: []string{`internal\.example\.com`}, MaxLength: map[string]int{"title": 80}, RequiredSections: map[string][]string{"create-issue": {"## Summary"}}},
+		},
+		{
+			name:    "invalid deny pattern",
+			policy:  &ContentPolicyConfig{Deny: []string{"(unclosed"}},
+			wantErr: "safe-outputs.content-policy.deny: invalid regular expression '(unclosed'",
+		},
+		{
+			name:    "invalid allow pattern",
+			policy:  &ContentPolicyConfig{Allow: []string{"[a-"}},
+			wantErr: "safe-outputs.content-policy.allow: invalid regular expression '[a-'",
+		},
+		{
+			name:    "inline flags",
+			policy:  &ContentPolicyConfig{Deny: []string{`(?s)secret.*key`}},
+			wantErr: "uses '(?s)', which JavaScript does not support",
+		},
+		{
+			name:    "RE2-only escape",
+			policy:  &ContentPolicyConfig{Deny: []string{`\Asecret\z`}},
+			wantErr: `uses '\A', which JavaScript does not support`,
+		},
+		{
+			name:    "Unicode class",
+			policy:  &ContentPolicyConfig{Allow: []string{`\pL+`}},
+			wantErr: `uses '\p', which JavaScript does not support`,
+		},
+		{
+			name:   "escaped backslash and non-capturing group",
+			policy: &ContentPolicyConfig{Deny: []string{`C:\\Apps`, `(?:internal|private)\.example`}},
+		},
+		{
+			name:    "lookahead",
+			policy:  &ContentPolicyConfig{Deny: []string{`secret(?!s)`}},
+			wantErr: "safe-outputs.content-policy.deny: invalid regular expression",
+		},
+		{
+			name:   "nested max-length field",
+			policy: &ContentPolicyConfig{MaxLength: map[string]int{"labels[]": 30}},
+		},
+		{
+			name:    "unknown max-length field",
+			policy:  &ContentPolicyConfig{MaxLength: map[string]int{"labels": 10}},
+			wantErr: "safe-outputs.content-policy.max-length: unknown field 'labels'",
+		},
+		{
+			name:    "non-positive max-length",
+			policy:  &ContentPolicyConfig{MaxLength: map[string]int{"body": 0}},
+			wantErr: "safe-outputs.content-policy.max-length.body: must be a positive number",
+		},
+		{
+			name:    "required sections for a disabled type",
+			policy:  &ContentPolicyConfig{RequiredSections: map[string][]string{"create-pull-request": {"## Summary"}}},
+			wantErr: "safe-outputs.content-policy.required-sections: 'create-pull-request' is not enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSafeOutputContentPolicy(&SafeOutputsConfig{CreateIssues: &CreateIssuesConfig{}, ContentPolicy: tt.policy})
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "policy should be valid")
+				return
+			}
+			require.Error(t, err, "policy should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error should explain the problem")
+		})
+	}
+}
+
+func TestContentPolicyEnvValue(t *testing.T) {
+	assert.Empty(t, contentPolicyEnvValue(&SafeOutputsConfig{}), "no env value without a content policy")
+
+	value := contentPolicyEnvValue(&SafeOutputsConfig{CreateIssues: &CreateIssuesConfig{}, ContentPolicy: &ContentPolicyConfig{
+		MaxLength:        map[string]int{"title": 80},
+		RequiredSections: map[string][]string{"create-issue": {"## Summary"}},
+		DetectSecrets:    true,
+	}})
+	assert.Equal(t, `"{\"max_length\":{\"title\":80},\"required_sections\":{\"create_issue\":[\"## Summary\"]},\"detect_secrets\":true,\"text_fields\":{\"create_issue\":[\"body\",\"labels[]\",\"title\"]}}"`, value, "required sections and text fields should be keyed by tool name")
+}
+
+func TestSafeOutputToolTextFields(t *testing.T) {
+	fields := safeOutputToolTextFields()
+	assert.Equal(t, []string{"annotations[].message", "annotations[].path", "annotations[].title", "summary", "text", "title"}, fields["create_check_run"], "nested array fields should be included and enums excluded")
+	assert.Equal(t, []string{"branch", "content", "path", "summary"}, fields["update_file"], "update-file text fields")
+	assert.Contains(t, fields["create_release"], "name", "create-release name should be checked")
+	assert.Equal(t, []string{"commit_message", "commit_title"}, fields["merge_pull_request"], "merge-pull-request text fields")
+	assert.NotContains(t, fields["create_issue"], "temporary_id", "fields constrained by a pattern should be excluded")
+}
+
+func TestSafeOutputContentPolicyCompilation(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  create-issue:
+  content-policy:
+    banned-words: [confidential]
+    detect-secrets: true
+---
+
+# Reporter
+
+Report what you found.
+`
+
+	tmpDir := testutil.TempDir(t, "safe-output-content-policy-*")
+	testFile := filepath.Join(tmpDir, "reporter.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "reporter.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+
+	envPrefix := `GH_AW_SAFE_OUTPUTS_CONTENT_POLICY: "{\"banned_words\":[\"confidential\"],\"detect_secrets\":true,\"text_fields\":{`
+	assert.Equal(t, 2, strings.Count(string(lockContent), envPrefix), "the output collector and the handler manager should both receive the content policy")
+	assert.Contains(t, string(lockContent), `\"create_issue\":[\"body\",\"labels[]\",\"title\"]`, "the text fields of enabled safe outputs should be passed")
+}
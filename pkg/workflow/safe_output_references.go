@@ -0,0 +1,167 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var safeOutputReferencesLog = logger.New("workflow:safe_output_references")
+
+// safeOutputReferenceProducers lists the safe output types whose created items can be
+// referenced by temporary ID from other safe outputs in the same run.
+// Must match REFERENCE_PRODUCER_TYPES in actions/setup/js/safe_output_references.cjs.
+var safeOutputReferenceProducers = []string{
+	"create-issue",
+	"create-pull-request",
+}
+
+// safeOutputReferenceConsumers maps the safe output types that can reference items created
+// in the same run to the fields where the agent puts the #aw_ID reference.
+var safeOutputReferenceConsumers = map[string]string{
+	"add-comment":         "the comment body",
+	"assign-to-user":      "issue_number",
+	"create-discussion":   "the discussion body",
+	"create-issue":        "the issue body or parent",
+	"create-pull-request": "the pull request body",
+	"link-sub-issue":      "parent_issue_number or sub_issue_number",
+	"update-issue":        "the issue body",
+	"update-project":      "content_number",
+}
+
+// parseSafeOutputReferences parses the references map from the safe-outputs configuration.
+// Each key is a consumer type and each value is the list of producer types it may reference.
+func parseSafeOutputReferences(references any) map[string][]string {
+	referencesMap, ok := references.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string][]string, len(referencesMap))
+	for consumer, producers := range referencesMap {
+		result[consumer] = ParseStringArrayFromConfig(map[string]any{"producers": producers}, "producers", safeOutputReferencesLog)
+	}
+	safeOutputReferencesLog.Printf("Parsed %d safe output reference rule(s)", len(result))
+	return result
+}
+
+// validateSafeOutputReferences checks that every reference rule names a known consumer and
+// producer and that both safe output types are enabled in the workflow.
+func validateSafeOutputReferences(config *SafeOutputsConfig) error {
+	if config == nil || len(config.References) == 0 {
+		return nil
+	}
+
+	enabledTools := getEnabledSafeOutputToolNamesReflection(config)
+
+	consumers := make([]string, 0, len(config.References))
+	for consumer := range config.References {
+		consumers = append(consumers, consumer)
+	}
+	sort.Strings(consumers)
+
+	for _, consumer := range consumers {
+		if _, ok := safeOutputReferenceConsumers[consumer]; !ok {
+			return fmt.Errorf("safe-outputs.references: '%s' cannot reference items created in the same run. Supported types: %s", consumer, strings.Join(sortedReferenceConsumers(), ", "))
+		}
+		if !slices.Contains(enabledTools, safeOutputTypeToToolName(consumer)) {
+			return fmt.Errorf("safe-outputs.references: '%s' is not enabled. Add it to safe-outputs or remove the reference rule", consumer)
+		}
+		for _, producer := range config.References[consumer] {
+			if !slices.Contains(safeOutputReferenceProducers, producer) {
+				return fmt.Errorf("safe-outputs.references.%s: '%s' does not create items that can be referenced. Supported types: %s", consumer, producer, strings.Join(safeOutputReferenceProducers, ", "))
+			}
+			if !slices.Contains(enabledTools, safeOutputTypeToToolName(producer)) {
+				return fmt.Errorf("safe-outputs.references.%s: '%s' is not enabled. Add it to safe-outputs or remove it from the reference rule", consumer, producer)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortedReferenceConsumers returns the supported consumer types in alphabetical order
+func sortedReferenceConsumers() []string {
+	consumers := make([]string, 0, len(safeOutputReferenceConsumers))
+	for consumer := range safeOutputReferenceConsumers {
+		consumers = append(consumers, consumer)
+	}
+	sort.Strings(consumers)
+	return consumers
+}
+
+// addSafeOutputReferencesEnvVar adds GH_AW_SAFE_OUTPUT_REFERENCES with the reference policy
+// keyed by tool name so the handler manager can block references that are not allowed.
+func (c *Compiler) addSafeOutputReferencesEnvVar(steps *[]string, data *WorkflowData) {
+	if data.SafeOutputs == nil || len(data.SafeOutputs.References) == 0 {
+		return
+	}
+
+	policy := make(map[string][]string, len(data.SafeOutputs.References))
+	for consumer, producers := range data.SafeOutputs.References {
+		toolProducers := make([]string, 0, len(producers))
+		for _, producer := range producers {
+			toolProducers = append(toolProducers, safeOutputTypeToToolName(producer))
+		}
+		policy[safeOutputTypeToToolName(consumer)] = toolProducers
+	}
+
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		safeOutputReferencesLog.Printf("Failed to marshal reference policy: %v", err)
+		return
+	}
+	*steps = append(*steps, fmt.Sprintf("          GH_AW_SAFE_OUTPUT_REFERENCES: %q\n", string(policyJSON)))
+}
+
+// referenceConstraints describes the cross-item references a tool may make or receive,
+// for inclusion in the safe-outputs MCP tool description.
+func referenceConstraints(toolName string, safeOutputs *SafeOutputsConfig) []string {
+	if safeOutputs == nil || len(safeOutputs.References) == 0 {
+		return nil
+	}
+
+	var constraints []string
+	safeOutputType := strings.ReplaceAll(toolName, "_", "-")
+
+	if producers := safeOutputs.References[safeOutputType]; len(producers) > 0 {
+		constraints = append(constraints, fmt.Sprintf(
+			"May reference items created earlier in this run by %s using their temporary ID ('#aw_ID') in %s; references to other items created in this run are rejected.",
+			strings.Join(toolNamesForTypes(producers), ", "), safeOutputReferenceConsumers[safeOutputType]))
+	}
+
+	if slices.Contains(safeOutputReferenceProducers, safeOutputType) {
+		var consumers []string
+		for consumer, producers := range safeOutputs.References {
+			if slices.Contains(producers, safeOutputType) {
+				consumers = append(consumers, consumer)
+			}
+		}
+		if len(consumers) > 0 {
+			sort.Strings(consumers)
+			constraints = append(constraints, fmt.Sprintf(
+				"Set temporary_id so %s can reference the created item in this run.",
+				strings.Join(toolNamesForTypes(consumers), ", ")))
+		}
+	}
+
+	return constraints
+}
+
+// safeOutputTypeToToolName converts a safe output type (create-issue) to its tool name (create_issue)
+func safeOutputTypeToToolName(safeOutputType string) string {
+	return strings.ReplaceAll(safeOutputType, "-", "_")
+}
+
+// toolNamesForTypes converts safe output types to tool names
+func toolNamesForTypes(types []string) []string {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, safeOutputTypeToToolName(t))
+	}
+	return names
+}
@@ -0,0 +1,136 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSafeOutputReferences(t *testing.T) {
+	references := parseSafeOutputReferences(map[string]any{
+		"link-sub-issue": []any{"create-issue"},
+		"add-comment":    []any{"create-issue", "create-pull-request"},
+	})
+
+	assert.Equal(t, map[string][]string{
+		"link-sub-issue": {"create-issue"},
+		"add-comment":    {"create-issue", "create-pull-request"},
+	}, references, "references should be parsed")
+	assert.Nil(t, parseSafeOutputReferences("create-issue"), "non-map references should be ignored")
+}
+
+func TestValidateSafeOutputReferences(t *testing.T) {
+	enabled := func(references map[string][]string) *SafeOutputsConfig {
+		return &SafeOutputsConfig{
+			CreateIssues: &CreateIssuesConfig{},
+			LinkSubIssue: &LinkSubIssueConfig{},
+			AddComments:  &AddCommentsConfig{},
+			References:   references,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		config      *SafeOutputsConfig
+		expectedErr string
+	}{
+		{
+			name:   "no references",
+			config: &SafeOutputsConfig{},
+		},
+		{
+			name:   "enabled consumer and producer",
+			config: enabled(map[string][]string{"link-sub-issue": {"create-issue"}, "add-comment": {"create-issue"}}),
+		},
+		{
+			name:        "unsupported consumer",
+			config:      enabled(map[string][]string{"noop": {"create-issue"}}),
+			expectedErr: "'noop' cannot reference items created in the same run",
+		},
+		{
+			name:        "consumer not enabled",
+			config:      enabled(map[string][]string{"update-project": {"create-issue"}}),
+			expectedErr: "'update-project' is not enabled",
+		},
+		{
+			name:        "unsupported producer",
+			config:      enabled(map[string][]string{"add-comment": {"add-labels"}}),
+			expectedErr: "'add-labels' does not create items that can be referenced",
+		},
+		{
+			name:        "producer not enabled",
+			config:      enabled(map[string][]string{"add-comment": {"create-pull-request"}}),
+			expectedErr: "safe-outputs.references.add-comment: 'create-pull-request' is not enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSafeOutputReferences(tt.config)
+			if tt.expectedErr == "" {
+				require.NoError(t, err, "references should be valid")
+				return
+			}
+			require.Error(t, err, "references should be rejected")
+			assert.Contains(t, err.Error(), tt.expectedErr, "error should explain the invalid rule")
+		})
+	}
+}
+
+func TestReferenceConstraints(t *testing.T) {
+	config := &SafeOutputsConfig{
+		References: map[string][]string{
+			"link-sub-issue": {"create-issue"},
+			"add-comment":    {"create-issue", "create-pull-request"},
+		},
+	}
+
+	linkConstraints := referenceConstraints("link_sub_issue", config)
+	require.Len(t, linkConstraints, 1, "consumer should get one constraint")
+	assert.Contains(t, linkConstraints[0], "by create_issue using their temporary ID", "consumer should list allowed producers")
+
+	issueConstraints := referenceConstraints("create_issue", config)
+	require.Len(t, issueConstraints, 1, "producer should get one constraint")
+	assert.Contains(t, issueConstraints[0], "Set temporary_id so add_comment, link_sub_issue can reference", "producer should list its consumers")
+
+	assert.Empty(t, referenceConstraints("add_labels", config), "unrelated tools should get no constraints")
+	assert.Empty(t, referenceConstraints("create_issue", &SafeOutputsConfig{}), "no constraints without references")
+}
+
+func TestSafeOutputReferencesCompilation(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  create-issue:
+  link-sub-issue:
+  references:
+    link-sub-issue: [create-issue]
+---
+
+# Epic breakdown
+
+Split the epic into issues and link them as sub-issues.
+`
+
+	tmpDir := testutil.TempDir(t, "safe-output-references-*")
+	testFile := filepath.Join(tmpDir, "epic.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "epic.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `GH_AW_SAFE_OUTPUT_REFERENCES: "{\"link_sub_issue\":[\"create_issue\"]}"`, "reference policy should be passed to the handler manager")
+	assert.Contains(t, lock, "Set temporary_id so link_sub_issue can reference the created item in this run.", "create_issue description should mention the reference")
+}
@@ -70,10 +70,11 @@ var ValidationConfig = map[string]TypeValidationConfig{
 	"create_pull_request": {
 		DefaultMax: 1,
 		Fields: map[string]FieldValidation{
-			"title":  {Required: true, Type: "string", Sanitize: true, MaxLength: 128},
-			"body":   {Required: true, Type: "string", Sanitize: true, MaxLength: MaxBodyLength},
-			"branch": {Required: true, Type: "string", Sanitize: true, MaxLength: 256},
-			"labels": {Type: "array", ItemType: "string", ItemSanitize: true, ItemMaxLength: 128},
+			"title":        {Required: true, Type: "string", Sanitize: true, MaxLength: 128},
+			"body":         {Required: true, Type: "string", Sanitize: true, MaxLength: MaxBodyLength},
+			"branch":       {Required: true, Type: "string", Sanitize: true, MaxLength: 256},
+			"labels":       {Type: "array", ItemType: "string", ItemSanitize: true, ItemMaxLength: 128},
+			"temporary_id": {Type: "string"},
 		},
 	},
 	"add_labels": {
@@ -198,6 +199,29 @@ var ValidationConfig = map[string]TypeValidationConfig{
 			"issue_number": {OptionalPositiveInteger: true},
 		},
 	},
+	"lock_conversation": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"item_number": {IssueOrPRNumber: true},
+			"reason":      {Type: "string", Enum: []string{"off-topic", "too heated", "resolved", "spam"}},
+			"repo":        {Type: "string", MaxLength: 256}, // Optional: target repository in format "owner/repo"
+		},
+	},
+	"pin_issue": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"issue_number": {OptionalPositiveInteger: true},
+			"repo":         {Type: "string", MaxLength: 256}, // Optional: target repository in format "owner/repo"
+		},
+	},
+	"transfer_issue": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"issue_number": {OptionalPositiveInteger: true},
+			"destination":  {Required: true, Type: "string", MaxLength: 256},
+			"repo":         {Type: "string", MaxLength: 256}, // Optional: target repository in format "owner/repo"
+		},
+	},
 	"close_pull_request": {
 		DefaultMax: 1,
 		Fields: map[string]FieldValidation{
@@ -205,6 +229,15 @@ var ValidationConfig = map[string]TypeValidationConfig{
 			"pull_request_number": {OptionalPositiveInteger: true},
 		},
 	},
+	"merge_pull_request": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"pull_request_number": {OptionalPositiveInteger: true},
+			"commit_title":        {Type: "string", Sanitize: true, MaxLength: 256},
+			"commit_message":      {Type: "string", Sanitize: true, MaxLength: MaxBodyLength},
+			"repo":                {Type: "string", MaxLength: 256}, // Optional: target repository in format "owner/repo"
+		},
+	},
 	"missing_tool": {
 		DefaultMax: 20,
 		Fields: map[string]FieldValidation{
@@ -221,6 +254,45 @@ var ValidationConfig = map[string]TypeValidationConfig{
 			"body":      {Required: true, Type: "string", Sanitize: true, MaxLength: MaxBodyLength},
 		},
 	},
+	"create_release": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"tag":              {Required: true, Type: "string", Sanitize: true, MaxLength: 256},
+			"name":             {Type: "string", Sanitize: true, MaxLength: 256},
+			"body":             {Required: true, Type: "string", Sanitize: true, MaxLength: MaxBodyLength},
+			"target_commitish": {Type: "string", Sanitize: true, MaxLength: 256},
+			"draft":            {Type: "boolean"},
+			"prerelease":       {Type: "boolean"},
+			"assets":           {Type: "array", ItemType: "string", ItemSanitize: true, ItemMaxLength: 256},
+		},
+	},
+	"create_tag": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"tag": {Required: true, Type: "string", Sanitize: true, MaxLength: 256},
+			"sha": {Type: "string", Pattern: "^[0-9a-fA-F]{7,40}$", PatternError: "must be a commit SHA"},
+		},
+	},
+	"update_file": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"path":    {Required: true, Type: "string", MaxLength: 512},
+			"content": {Required: true, Type: "string"},
+			"branch":  {Type: "string", MaxLength: 256},
+			"summary": {Type: "string", Sanitize: true, MaxLength: 1024},
+		},
+	},
+	"manage_labels": {
+		DefaultMax: 10,
+		Fields: map[string]FieldValidation{
+			"operation":   {Required: true, Type: "string", Enum: []string{"create", "update", "delete"}},
+			"name":        {Required: true, Type: "string", Sanitize: true, MaxLength: 50},
+			"new_name":    {Type: "string", Sanitize: true, MaxLength: 50},
+			"color":       {Type: "string", Pattern: "^#?[0-9a-fA-F]{6}$", PatternError: "must be a 6-digit hex color"},
+			"description": {Type: "string", Sanitize: true, MaxLength: 100},
+			"repo":        {Type: "string", MaxLength: 256}, // Optional: target repository in format "owner/repo"
+		},
+	},
 	"upload_asset": {
 		DefaultMax: 10,
 		Fields: map[string]FieldValidation{
@@ -244,6 +316,24 @@ var ValidationConfig = map[string]TypeValidationConfig{
 			"ruleIdSuffix": {Type: "string", Pattern: "^[a-zA-Z0-9_-]+$", PatternError: "must contain only alphanumeric characters, hyphens, and underscores", Sanitize: true, MaxLength: 128},
 		},
 	},
+	"create_check_run": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"conclusion":          {Required: true, Type: "string", Enum: []string{"success", "failure", "neutral", "cancelled", "skipped", "timed_out", "action_required"}},
+			"title":               {Required: true, Type: "string", Sanitize: true, MaxLength: 255},
+			"summary":             {Required: true, Type: "string", Sanitize: true, MaxLength: MaxBodyLength},
+			"text":                {Type: "string", Sanitize: true, MaxLength: MaxBodyLength},
+			"annotations":         {Type: "array"},
+			"pull_request_number": {OptionalPositiveInteger: true},
+		},
+	},
+	"create_commit_status": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"state":       {Required: true, Type: "string", Enum: []string{"error", "failure", "pending", "success"}},
+			"description": {Type: "string", Sanitize: true, MaxLength: 140},
+		},
+	},
 	"link_sub_issue": {
 		DefaultMax:       5,
 		CustomValidation: "parentAndSubDifferent",
@@ -113,6 +113,12 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				config.ClosePullRequests = closePullRequestsConfig
 			}
 
+			// Handle merge-pull-request
+			mergePullRequestConfig := c.parseMergePullRequestConfig(outputMap)
+			if mergePullRequestConfig != nil {
+				config.MergePullRequest = mergePullRequestConfig
+			}
+
 			// Handle mark-pull-request-as-ready-for-review
 			markPRReadyConfig := c.parseMarkPullRequestAsReadyForReviewConfig(outputMap)
 			if markPRReadyConfig != nil {
@@ -162,6 +168,18 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				config.CreateCodeScanningAlerts = securityReportsConfig
 			}
 
+			// Handle create-check-run
+			createCheckRunConfig := c.parseCreateCheckRunConfig(outputMap)
+			if createCheckRunConfig != nil {
+				config.CreateCheckRun = createCheckRunConfig
+			}
+
+			// Handle create-commit-status
+			createCommitStatusConfig := c.parseCreateCommitStatusConfig(outputMap)
+			if createCommitStatusConfig != nil {
+				config.CreateCommitStatus = createCommitStatusConfig
+			}
+
 			// Handle autofix-code-scanning-alert
 			autofixCodeScanningAlertConfig := c.parseAutofixCodeScanningAlertConfig(outputMap)
 			if autofixCodeScanningAlertConfig != nil {
@@ -207,6 +225,12 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				config.RemoveLabels = removeLabelsConfig
 			}
 
+			// Parse manage-labels configuration
+			manageLabelsConfig := c.parseManageLabelsConfig(outputMap)
+			if manageLabelsConfig != nil {
+				config.ManageLabels = manageLabelsConfig
+			}
+
 			// Parse add-reviewer configuration
 			addReviewerConfig := c.parseAddReviewerConfig(outputMap)
 			if addReviewerConfig != nil {
@@ -273,6 +297,24 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				config.UpdateRelease = updateReleaseConfig
 			}
 
+			// Handle create-release
+			createReleaseConfig := c.parseCreateReleaseConfig(outputMap)
+			if createReleaseConfig != nil {
+				config.CreateRelease = createReleaseConfig
+			}
+
+			// Handle create-tag
+			createTagConfig := c.parseCreateTagConfig(outputMap)
+			if createTagConfig != nil {
+				config.CreateTag = createTagConfig
+			}
+
+			// Handle update-file
+			updateFileConfig := c.parseUpdateFileConfig(outputMap)
+			if updateFileConfig != nil {
+				config.UpdateFile = updateFileConfig
+			}
+
 			// Handle link-sub-issue
 			linkSubIssueConfig := c.parseLinkSubIssueConfig(outputMap)
 			if linkSubIssueConfig != nil {
@@ -285,6 +327,24 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				config.HideComment = hideCommentConfig
 			}
 
+			// Handle lock-conversation
+			lockConversationConfig := c.parseLockConversationConfig(outputMap)
+			if lockConversationConfig != nil {
+				config.LockConversation = lockConversationConfig
+			}
+
+			// Handle pin-issue
+			pinIssueConfig := c.parsePinIssueConfig(outputMap)
+			if pinIssueConfig != nil {
+				config.PinIssue = pinIssueConfig
+			}
+
+			// Handle transfer-issue
+			transferIssueConfig := c.parseTransferIssueConfig(outputMap)
+			if transferIssueConfig != nil {
+				config.TransferIssue = transferIssueConfig
+			}
+
 			// Handle dispatch-workflow
 			dispatchWorkflowConfig := c.parseDispatchWorkflowConfig(outputMap)
 			if dispatchWorkflowConfig != nil {
@@ -428,6 +488,16 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				}
 			}
 
+			// Handle cross-item references between safe outputs in the same run
+			if references, exists := outputMap["references"]; exists {
+				config.References = parseSafeOutputReferences(references)
+			}
+
+			// Handle content policy for safe output text
+			if contentPolicy, exists := outputMap["content-policy"]; exists {
+				config.ContentPolicy = parseContentPolicyConfig(contentPolicy)
+			}
+
 			// Handle jobs (safe-jobs must be under safe-outputs)
 			if jobs, exists := outputMap["jobs"]; exists {
 				if jobsMap, ok := jobs.(map[string]any); ok {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/github/gh-aw/pkg/stringutil"
 )
@@ -31,6 +32,11 @@ func populateDispatchWorkflowFiles(data *WorkflowData, markdownPath string) {
 	}
 
 	for _, workflowName := range data.SafeOutputs.DispatchWorkflow.Workflows {
+		// Cross-repository workflows are resolved when validating the target repository
+		if _, isCrossRepo := data.SafeOutputs.DispatchWorkflow.Targets[workflowName]; isCrossRepo {
+			continue
+		}
+
 		// Find the workflow file
 		fileResult, err := findWorkflowFile(workflowName, markdownPath)
 		if err != nil {
@@ -173,6 +179,18 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				0, // default: unlimited
 			)
 		}
+		if data.SafeOutputs.CreateCheckRun != nil {
+			safeOutputsConfig["create_check_run"] = generateMaxConfig(
+				data.SafeOutputs.CreateCheckRun.Max,
+				1, // default max
+			)
+		}
+		if data.SafeOutputs.CreateCommitStatus != nil {
+			safeOutputsConfig["create_commit_status"] = generateMaxConfig(
+				data.SafeOutputs.CreateCommitStatus.Max,
+				1, // default max
+			)
+		}
 		if data.SafeOutputs.AutofixCodeScanningAlert != nil {
 			safeOutputsConfig["autofix_code_scanning_alert"] = generateMaxConfig(
 				data.SafeOutputs.AutofixCodeScanningAlert.Max,
@@ -198,6 +216,12 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				data.SafeOutputs.RemoveLabels.Allowed,
 			)
 		}
+		if data.SafeOutputs.ManageLabels != nil {
+			config := generateMaxConfig(data.SafeOutputs.ManageLabels.Max, 10)
+			config["allowed_prefixes"] = data.SafeOutputs.ManageLabels.AllowedPrefixes
+			config["allow_delete"] = data.SafeOutputs.ManageLabels.AllowDelete
+			safeOutputsConfig["manage_labels"] = config
+		}
 		if data.SafeOutputs.AddReviewer != nil {
 			safeOutputsConfig["add_reviewer"] = generateMaxWithReviewersConfig(
 				data.SafeOutputs.AddReviewer.Max,
@@ -260,6 +284,12 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				10, // default max
 			)
 		}
+		if data.SafeOutputs.MergePullRequest != nil {
+			safeOutputsConfig["merge_pull_request"] = generateMaxConfig(
+				data.SafeOutputs.MergePullRequest.Max,
+				1, // default max
+			)
+		}
 		if data.SafeOutputs.PushToPullRequestBranch != nil {
 			safeOutputsConfig["push_to_pull_request_branch"] = generateMaxWithTargetConfig(
 				data.SafeOutputs.PushToPullRequestBranch.Max,
@@ -360,6 +390,24 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				1, // default max
 			)
 		}
+		if data.SafeOutputs.CreateRelease != nil {
+			config := generateMaxConfig(data.SafeOutputs.CreateRelease.Max, 1)
+			config["max_size_kb"] = data.SafeOutputs.CreateRelease.MaxSizeKB
+			config["allowed_exts"] = data.SafeOutputs.CreateRelease.AllowedExts
+			safeOutputsConfig["create_release"] = config
+		}
+		if data.SafeOutputs.CreateTag != nil {
+			safeOutputsConfig["create_tag"] = generateMaxConfig(
+				data.SafeOutputs.CreateTag.Max,
+				1, // default max
+			)
+		}
+		if data.SafeOutputs.UpdateFile != nil {
+			config := generateMaxConfig(data.SafeOutputs.UpdateFile.Max, 1)
+			config["allowed_paths"] = data.SafeOutputs.UpdateFile.AllowedPaths
+			config["max_size_kb"] = data.SafeOutputs.UpdateFile.MaxSizeKB
+			safeOutputsConfig["update_file"] = config
+		}
 		if data.SafeOutputs.LinkSubIssue != nil {
 			safeOutputsConfig["link_sub_issue"] = generateMaxConfig(
 				data.SafeOutputs.LinkSubIssue.Max,
@@ -379,6 +427,34 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				data.SafeOutputs.HideComment.AllowedReasons,
 			)
 		}
+		if data.SafeOutputs.LockConversation != nil {
+			additionalFields := make(map[string]any)
+			if len(data.SafeOutputs.LockConversation.AllowedReasons) > 0 {
+				additionalFields["allowed_reasons"] = data.SafeOutputs.LockConversation.AllowedReasons
+			}
+			safeOutputsConfig["lock_conversation"] = generateTargetConfigWithRepos(
+				data.SafeOutputs.LockConversation.SafeOutputTargetConfig,
+				data.SafeOutputs.LockConversation.Max,
+				1, // default max
+				additionalFields,
+			)
+		}
+		if data.SafeOutputs.PinIssue != nil {
+			safeOutputsConfig["pin_issue"] = generateTargetConfigWithRepos(
+				data.SafeOutputs.PinIssue.SafeOutputTargetConfig,
+				data.SafeOutputs.PinIssue.Max,
+				1, // default max
+				nil,
+			)
+		}
+		if data.SafeOutputs.TransferIssue != nil {
+			safeOutputsConfig["transfer_issue"] = generateTargetConfigWithRepos(
+				data.SafeOutputs.TransferIssue.SafeOutputTargetConfig,
+				data.SafeOutputs.TransferIssue.Max,
+				1, // default max
+				map[string]any{"allowed_destinations": data.SafeOutputs.TransferIssue.AllowedDestinations},
+			)
+		}
 	}
 
 	// Add safe-jobs configuration from SafeOutputs.Jobs
@@ -398,6 +474,21 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				safeJobConfig["output"] = jobConfig.Output
 			}
 
+			// Add typed outputs that other safe outputs can reference
+			if len(jobConfig.Outputs) > 0 {
+				outputsConfig := make(map[string]any)
+				for outputName, output := range jobConfig.Outputs {
+					outputConfig := map[string]any{
+						"type": output.Type,
+					}
+					if output.Description != "" {
+						outputConfig["description"] = output.Description
+					}
+					outputsConfig[outputName] = outputConfig
+				}
+				safeJobConfig["outputs"] = outputsConfig
+			}
+
 			// Add inputs information
 			if len(jobConfig.Inputs) > 0 {
 				inputsConfig := make(map[string]any)
@@ -507,6 +598,27 @@ func generateCustomJobToolDefinition(jobName string, jobConfig *SafeJobConfig) m
 		tool["description"] = fmt.Sprintf("Execute the %s custom job", jobName)
 	}
 
+	// Tell the agent which outputs other safe outputs can reference once this job has run
+	if len(jobConfig.Outputs) > 0 {
+		outputNames := make([]string, 0, len(jobConfig.Outputs))
+		for outputName := range jobConfig.Outputs {
+			outputNames = append(outputNames, outputName)
+		}
+		sort.Strings(outputNames)
+
+		references := make([]string, 0, len(outputNames))
+		for _, outputName := range outputNames {
+			output := jobConfig.Outputs[outputName]
+			reference := fmt.Sprintf("{{ safe_jobs.%s.%s }} (%s", jobName, outputName, output.Type)
+			if output.Description != "" {
+				reference += ": " + output.Description
+			}
+			references = append(references, reference+")")
+		}
+		tool["description"] = fmt.Sprintf("%s. Text fields of other safe outputs may reference this job's outputs, which are filled in after it runs: %s",
+			strings.TrimSuffix(tool["description"].(string), "."), strings.Join(references, ", "))
+	}
+
 	// Build the input schema
 	inputSchema := map[string]any{
 		"type":       "object",
@@ -626,6 +738,9 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 	if data.SafeOutputs.MarkPullRequestAsReadyForReview != nil {
 		enabledTools["mark_pull_request_as_ready_for_review"] = true
 	}
+	if data.SafeOutputs.MergePullRequest != nil {
+		enabledTools["merge_pull_request"] = true
+	}
 	if data.SafeOutputs.AddComments != nil {
 		enabledTools["add_comment"] = true
 	}
@@ -647,6 +762,12 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 	if data.SafeOutputs.CreateCodeScanningAlerts != nil {
 		enabledTools["create_code_scanning_alert"] = true
 	}
+	if data.SafeOutputs.CreateCheckRun != nil {
+		enabledTools["create_check_run"] = true
+	}
+	if data.SafeOutputs.CreateCommitStatus != nil {
+		enabledTools["create_commit_status"] = true
+	}
 	if data.SafeOutputs.AutofixCodeScanningAlert != nil {
 		enabledTools["autofix_code_scanning_alert"] = true
 	}
@@ -656,6 +777,9 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 	if data.SafeOutputs.RemoveLabels != nil {
 		enabledTools["remove_labels"] = true
 	}
+	if data.SafeOutputs.ManageLabels != nil {
+		enabledTools["manage_labels"] = true
+	}
 	if data.SafeOutputs.AddReviewer != nil {
 		enabledTools["add_reviewer"] = true
 	}
@@ -692,6 +816,15 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 	if data.SafeOutputs.UpdateRelease != nil {
 		enabledTools["update_release"] = true
 	}
+	if data.SafeOutputs.CreateRelease != nil {
+		enabledTools["create_release"] = true
+	}
+	if data.SafeOutputs.CreateTag != nil {
+		enabledTools["create_tag"] = true
+	}
+	if data.SafeOutputs.UpdateFile != nil {
+		enabledTools["update_file"] = true
+	}
 	if data.SafeOutputs.NoOp != nil {
 		enabledTools["noop"] = true
 	}
@@ -701,6 +834,15 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 	if data.SafeOutputs.HideComment != nil {
 		enabledTools["hide_comment"] = true
 	}
+	if data.SafeOutputs.LockConversation != nil {
+		enabledTools["lock_conversation"] = true
+	}
+	if data.SafeOutputs.PinIssue != nil {
+		enabledTools["pin_issue"] = true
+	}
+	if data.SafeOutputs.TransferIssue != nil {
+		enabledTools["transfer_issue"] = true
+	}
 	if data.SafeOutputs.UpdateProjects != nil {
 		enabledTools["update_project"] = true
 	}
@@ -777,6 +919,19 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 		}
 
 		for _, workflowName := range data.SafeOutputs.DispatchWorkflow.Workflows {
+			// Cross-repository workflows use the inputs fetched from the target repository at validation time
+			if target, isCrossRepo := data.SafeOutputs.DispatchWorkflow.Targets[workflowName]; isCrossRepo {
+				workflowInputs, known := data.SafeOutputs.DispatchWorkflow.TargetInputs[workflowName]
+				tool := generateDispatchWorkflowTool(workflowName, workflowInputs)
+				tool["description"] = fmt.Sprintf("Dispatch the '%s' workflow in the %s repository with workflow_dispatch trigger.", target.Workflow, target.Repo)
+				if !known {
+					// The target could not be fetched at compile time, so its inputs are not known
+					tool["inputSchema"].(map[string]any)["additionalProperties"] = map[string]any{"type": "string"}
+				}
+				filteredTools = append(filteredTools, tool)
+				continue
+			}
+
 			// Find the workflow file in multiple locations
 			fileResult, err := findWorkflowFile(workflowName, markdownPath)
 			if err != nil {
@@ -880,6 +1035,26 @@ func addRepoParameterIfNeeded(tool map[string]any, toolName string, safeOutputs
 			hasAllowedRepos = len(config.AllowedRepos) > 0
 			targetRepoSlug = config.TargetRepoSlug
 		}
+	case "manage_labels":
+		if config := safeOutputs.ManageLabels; config != nil {
+			hasAllowedRepos = len(config.AllowedRepos) > 0
+			targetRepoSlug = config.TargetRepoSlug
+		}
+	case "lock_conversation":
+		if config := safeOutputs.LockConversation; config != nil {
+			hasAllowedRepos = len(config.AllowedRepos) > 0
+			targetRepoSlug = config.TargetRepoSlug
+		}
+	case "pin_issue":
+		if config := safeOutputs.PinIssue; config != nil {
+			hasAllowedRepos = len(config.AllowedRepos) > 0
+			targetRepoSlug = config.TargetRepoSlug
+		}
+	case "transfer_issue":
+		if config := safeOutputs.TransferIssue; config != nil {
+			hasAllowedRepos = len(config.AllowedRepos) > 0
+			targetRepoSlug = config.TargetRepoSlug
+		}
 	case "close_issue", "update_issue":
 		if config := safeOutputs.CloseIssues; config != nil && toolName == "close_issue" {
 			hasAllowedRepos = len(config.AllowedRepos) > 0
@@ -904,7 +1079,7 @@ func addRepoParameterIfNeeded(tool map[string]any, toolName string, safeOutputs
 			hasAllowedRepos = len(config.AllowedRepos) > 0
 			targetRepoSlug = config.TargetRepoSlug
 		}
-	case "add_labels", "remove_labels", "hide_comment", "link_sub_issue", "mark_pull_request_as_ready_for_review",
+	case "add_labels", "remove_labels", "hide_comment", "link_sub_issue", "mark_pull_request_as_ready_for_review", "merge_pull_request",
 		"add_reviewer", "assign_milestone", "assign_to_agent", "assign_to_user", "unassign_from_user":
 		// These use SafeOutputTargetConfig - check the appropriate config
 		switch toolName {
@@ -933,6 +1108,11 @@ func addRepoParameterIfNeeded(tool map[string]any, toolName string, safeOutputs
 				hasAllowedRepos = len(config.AllowedRepos) > 0
 				targetRepoSlug = config.TargetRepoSlug
 			}
+		case "merge_pull_request":
+			if config := safeOutputs.MergePullRequest; config != nil {
+				hasAllowedRepos = len(config.AllowedRepos) > 0
+				targetRepoSlug = config.TargetRepoSlug
+			}
 		case "add_reviewer":
 			if config := safeOutputs.AddReviewer; config != nil {
 				hasAllowedRepos = len(config.AllowedRepos) > 0
@@ -995,8 +1175,8 @@ func addRepoParameterIfNeeded(tool map[string]any, toolName string, safeOutputs
 // generateDispatchWorkflowTool generates an MCP tool definition for a specific workflow
 // The tool will be named after the workflow and accept the workflow's defined inputs
 func generateDispatchWorkflowTool(workflowName string, workflowInputs map[string]any) map[string]any {
-	// Normalize workflow name to use underscores for tool name
-	toolName := stringutil.NormalizeSafeOutputIdentifier(workflowName)
+	// Normalize workflow name to use underscores for tool name (owner/repo/workflow becomes owner_repo_workflow)
+	toolName := stringutil.NormalizeSafeOutputIdentifier(strings.NewReplacer("/", "_", ".", "_").Replace(workflowName))
 
 	// Build the description
 	description := fmt.Sprintf("Dispatch the '%s' workflow with workflow_dispatch trigger. This workflow must support workflow_dispatch and be in .github/workflows/ directory in the same repository.", workflowName)
@@ -18,6 +18,7 @@ var safeOutputFieldMapping = map[string]string{
 	"CloseDiscussions":                "close_discussion",
 	"CloseIssues":                     "close_issue",
 	"ClosePullRequests":               "close_pull_request",
+	"MergePullRequest":                "merge_pull_request",
 	"AddComments":                     "add_comment",
 	"CreatePullRequests":              "create_pull_request",
 	"CreatePullRequestReviewComments": "create_pull_request_review_comment",
@@ -25,8 +26,11 @@ var safeOutputFieldMapping = map[string]string{
 	"ReplyToPullRequestReviewComment": "reply_to_pull_request_review_comment",
 	"ResolvePullRequestReviewThread":  "resolve_pull_request_review_thread",
 	"CreateCodeScanningAlerts":        "create_code_scanning_alert",
+	"CreateCheckRun":                  "create_check_run",
+	"CreateCommitStatus":              "create_commit_status",
 	"AddLabels":                       "add_labels",
 	"RemoveLabels":                    "remove_labels",
+	"ManageLabels":                    "manage_labels",
 	"AddReviewer":                     "add_reviewer",
 	"AssignMilestone":                 "assign_milestone",
 	"AssignToAgent":                   "assign_to_agent",
@@ -36,11 +40,17 @@ var safeOutputFieldMapping = map[string]string{
 	"PushToPullRequestBranch":         "push_to_pull_request_branch",
 	"UploadAssets":                    "upload_asset",
 	"UpdateRelease":                   "update_release",
+	"CreateRelease":                   "create_release",
+	"CreateTag":                       "create_tag",
+	"UpdateFile":                      "update_file",
 	"UpdateProjects":                  "update_project",
 	"CreateProjects":                  "create_project",
 	"CreateProjectStatusUpdates":      "create_project_status_update",
 	"LinkSubIssue":                    "link_sub_issue",
 	"HideComment":                     "hide_comment",
+	"LockConversation":                "lock_conversation",
+	"PinIssue":                        "pin_issue",
+	"TransferIssue":                   "transfer_issue",
 	"DispatchWorkflow":                "dispatch_workflow",
 	"MissingTool":                     "missing_tool",
 	"NoOp":                            "noop",
@@ -48,6 +48,10 @@ func computePermissionsForSafeOutputs(safeOutputs *SafeOutputsConfig) *Permissio
 		safeOutputsPermissionsLog.Print("Adding permissions for remove-labels")
 		permissions.Merge(NewPermissionsContentsReadIssuesWritePRWrite())
 	}
+	if safeOutputs.ManageLabels != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for manage-labels")
+		permissions.Merge(NewPermissionsContentsReadIssuesWrite())
+	}
 	if safeOutputs.UpdateIssues != nil {
 		safeOutputsPermissionsLog.Print("Adding permissions for update-issue")
 		permissions.Merge(NewPermissionsContentsReadIssuesWrite())
@@ -64,6 +68,14 @@ func computePermissionsForSafeOutputs(safeOutputs *SafeOutputsConfig) *Permissio
 		safeOutputsPermissionsLog.Print("Adding permissions for update-release")
 		permissions.Merge(NewPermissionsContentsWrite())
 	}
+	if safeOutputs.CreateRelease != nil || safeOutputs.CreateTag != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for create-release or create-tag")
+		permissions.Merge(NewPermissionsContentsWrite())
+	}
+	if safeOutputs.UpdateFile != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for update-file")
+		permissions.Merge(NewPermissionsContentsWrite())
+	}
 	if safeOutputs.CreatePullRequestReviewComments != nil || safeOutputs.SubmitPullRequestReview != nil ||
 		safeOutputs.ReplyToPullRequestReviewComment != nil || safeOutputs.ResolvePullRequestReviewThread != nil {
 		safeOutputsPermissionsLog.Print("Adding permissions for PR review operations")
@@ -91,6 +103,10 @@ func computePermissionsForSafeOutputs(safeOutputs *SafeOutputsConfig) *Permissio
 		safeOutputsPermissionsLog.Print("Adding permissions for close-pull-request")
 		permissions.Merge(NewPermissionsContentsReadPRWrite())
 	}
+	if safeOutputs.MergePullRequest != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for merge-pull-request")
+		permissions.Merge(NewPermissionsContentsWritePRWrite())
+	}
 	if safeOutputs.MarkPullRequestAsReadyForReview != nil {
 		safeOutputsPermissionsLog.Print("Adding permissions for mark-pull-request-as-ready-for-review")
 		permissions.Merge(NewPermissionsContentsReadPRWrite())
@@ -99,6 +115,14 @@ func computePermissionsForSafeOutputs(safeOutputs *SafeOutputsConfig) *Permissio
 		safeOutputsPermissionsLog.Print("Adding permissions for hide-comment")
 		permissions.Merge(NewPermissionsContentsReadIssuesWritePRWriteDiscussionsWrite())
 	}
+	if safeOutputs.LockConversation != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for lock-conversation")
+		permissions.Merge(NewPermissionsContentsReadIssuesWritePRWrite())
+	}
+	if safeOutputs.PinIssue != nil || safeOutputs.TransferIssue != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for pin-issue or transfer-issue")
+		permissions.Merge(NewPermissionsContentsReadIssuesWrite())
+	}
 	if safeOutputs.DispatchWorkflow != nil {
 		safeOutputsPermissionsLog.Print("Adding permissions for dispatch-workflow")
 		permissions.Merge(NewPermissionsActionsWrite())
@@ -128,6 +152,14 @@ func computePermissionsForSafeOutputs(safeOutputs *SafeOutputsConfig) *Permissio
 		safeOutputsPermissionsLog.Print("Adding permissions for create-code-scanning-alert")
 		permissions.Merge(NewPermissionsContentsReadSecurityEventsWrite())
 	}
+	if safeOutputs.CreateCheckRun != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for create-check-run")
+		permissions.Merge(NewPermissionsContentsReadChecksWrite())
+	}
+	if safeOutputs.CreateCommitStatus != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for create-commit-status")
+		permissions.Merge(NewPermissionsContentsReadStatusesWrite())
+	}
 	if safeOutputs.AutofixCodeScanningAlert != nil {
 		safeOutputsPermissionsLog.Print("Adding permissions for autofix-code-scanning-alert")
 		permissions.Merge(NewPermissionsContentsReadSecurityEventsWriteActionsRead())
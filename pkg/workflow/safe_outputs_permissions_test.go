@@ -99,6 +99,30 @@ func TestComputePermissionsForSafeOutputs(t *testing.T) {
 				PermissionDiscussions:  PermissionWrite,
 			},
 		},
+		{
+			name: "create-check-run only - checks write",
+			safeOutputs: &SafeOutputsConfig{
+				CreateCheckRun: &CreateCheckRunConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				},
+			},
+			expected: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionRead,
+				PermissionChecks:   PermissionWrite,
+			},
+		},
+		{
+			name: "create-commit-status only - statuses write",
+			safeOutputs: &SafeOutputsConfig{
+				CreateCommitStatus: &CreateCommitStatusConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				},
+			},
+			expected: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionRead,
+				PermissionStatuses: PermissionWrite,
+			},
+		},
 		{
 			name: "add-labels only - no discussions permission",
 			safeOutputs: &SafeOutputsConfig{
@@ -125,6 +149,46 @@ func TestComputePermissionsForSafeOutputs(t *testing.T) {
 				PermissionPullRequests: PermissionWrite,
 			},
 		},
+		{
+			name: "manage-labels only - issues write",
+			safeOutputs: &SafeOutputsConfig{
+				ManageLabels: &ManageLabelsConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 10},
+				},
+			},
+			expected: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionRead,
+				PermissionIssues:   PermissionWrite,
+			},
+		},
+		{
+			name: "lock-conversation only - issues and pull-requests write",
+			safeOutputs: &SafeOutputsConfig{
+				LockConversation: &LockConversationConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				},
+			},
+			expected: map[PermissionScope]PermissionLevel{
+				PermissionContents:     PermissionRead,
+				PermissionIssues:       PermissionWrite,
+				PermissionPullRequests: PermissionWrite,
+			},
+		},
+		{
+			name: "pin-issue and transfer-issue - issues write",
+			safeOutputs: &SafeOutputsConfig{
+				PinIssue: &PinIssueConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				},
+				TransferIssue: &TransferIssueConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				},
+			},
+			expected: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionRead,
+				PermissionIssues:   PermissionWrite,
+			},
+		},
 		{
 			name: "close-issue only - no discussions permission",
 			safeOutputs: &SafeOutputsConfig{
@@ -149,6 +213,29 @@ func TestComputePermissionsForSafeOutputs(t *testing.T) {
 				PermissionPullRequests: PermissionWrite,
 			},
 		},
+		{
+			name: "merge-pull-request only - contents and pull-requests write",
+			safeOutputs: &SafeOutputsConfig{
+				MergePullRequest: &MergePullRequestConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				},
+			},
+			expected: map[PermissionScope]PermissionLevel{
+				PermissionContents:     PermissionWrite,
+				PermissionPullRequests: PermissionWrite,
+			},
+		},
+		{
+			name: "update-file only - contents write",
+			safeOutputs: &SafeOutputsConfig{
+				UpdateFile: &UpdateFileConfig{
+					BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				},
+			},
+			expected: map[PermissionScope]PermissionLevel{
+				PermissionContents: PermissionWrite,
+			},
+		},
 		{
 			name: "create-pull-request with fallback-as-issue (default) - includes issues permission",
 			safeOutputs: &SafeOutputsConfig{
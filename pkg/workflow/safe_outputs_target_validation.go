@@ -51,6 +51,9 @@ func validateSafeOutputsTarget(config *SafeOutputsConfig) error {
 	if config.ClosePullRequests != nil {
 		configs = append(configs, targetConfig{"close-pull-request", config.ClosePullRequests.Target})
 	}
+	if config.MergePullRequest != nil {
+		configs = append(configs, targetConfig{"merge-pull-request", config.MergePullRequest.Target})
+	}
 	if config.AddLabels != nil {
 		configs = append(configs, targetConfig{"add-labels", config.AddLabels.Target})
 	}
@@ -75,6 +78,15 @@ func validateSafeOutputsTarget(config *SafeOutputsConfig) error {
 	if config.HideComment != nil {
 		configs = append(configs, targetConfig{"hide-comment", config.HideComment.Target})
 	}
+	if config.LockConversation != nil {
+		configs = append(configs, targetConfig{"lock-conversation", config.LockConversation.Target})
+	}
+	if config.PinIssue != nil {
+		configs = append(configs, targetConfig{"pin-issue", config.PinIssue.Target})
+	}
+	if config.TransferIssue != nil {
+		configs = append(configs, targetConfig{"transfer-issue", config.TransferIssue.Target})
+	}
 	if config.MarkPullRequestAsReadyForReview != nil {
 		configs = append(configs, targetConfig{"mark-pull-request-as-ready-for-review", config.MarkPullRequestAsReadyForReview.Target})
 	}
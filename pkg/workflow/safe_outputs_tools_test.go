@@ -312,6 +312,7 @@ func TestGetSafeOutputsToolsJSON(t *testing.T) {
 		"close_discussion",
 		"close_issue",
 		"close_pull_request",
+		"merge_pull_request",
 		"mark_pull_request_as_ready_for_review",
 		"add_comment",
 		"create_pull_request",
@@ -320,8 +321,11 @@ func TestGetSafeOutputsToolsJSON(t *testing.T) {
 		"reply_to_pull_request_review_comment",
 		"resolve_pull_request_review_thread",
 		"create_code_scanning_alert",
+		"create_check_run",
+		"create_commit_status",
 		"add_labels",
 		"remove_labels",
+		"manage_labels",
 		"add_reviewer",
 		"assign_milestone",
 		"assign_to_agent",
@@ -331,8 +335,14 @@ func TestGetSafeOutputsToolsJSON(t *testing.T) {
 		"push_to_pull_request_branch",
 		"upload_asset",
 		"update_release",
+		"create_release",
+		"create_tag",
+		"update_file",
 		"link_sub_issue",
 		"hide_comment",
+		"lock_conversation",
+		"pin_issue",
+		"transfer_issue",
 		"update_project",
 		"create_project",
 		"create_project_status_update",
@@ -58,8 +58,7 @@ func (c *Compiler) normalizeScheduleString(scheduleStr string, itemIndex int) (p
 			} else {
 				// Warn if repository slug is not available - scattering will not be org-aware
 				schedulePreprocessingLog.Printf("Warning: repository slug not available for fuzzy schedule scattering")
-				c.IncrementWarningCount()
-				c.addScheduleWarning("Fuzzy schedule scattering without repository context. Workflows with the same name in different repositories may collide. Ensure you are in a git repository with a configured remote.")
+				c.addScheduleWarning(RuleScheduleNoRepository, "Fuzzy schedule scattering without repository context. Workflows with the same name in different repositories may collide. Ensure you are in a git repository with a configured remote.")
 			}
 		} else {
 			// Dev mode: use "dev" prefix for consistent scattering across all workflows
@@ -443,12 +442,8 @@ func (c *Compiler) addDailyCronWarning(cronExpr string) {
 			hour, minute,
 		)
 
-		// This warning is added to the warning count
-		// It will be collected and displayed by the compilation process
-		c.IncrementWarningCount()
-
-		// Store the warning for later display
-		c.addScheduleWarning(warningMsg)
+		// The warning is counted and collected for display by the compilation process
+		c.addScheduleWarning(RuleScheduleFixedTime, warningMsg)
 	}
 }
 
@@ -470,11 +465,8 @@ func (c *Compiler) addHourlyCronWarning(cronExpr string) {
 			minute, interval,
 		)
 
-		// This warning is added to the warning count
-		c.IncrementWarningCount()
-
-		// Store the warning for later display
-		c.addScheduleWarning(warningMsg)
+		// The warning is counted and collected for display by the compilation process
+		c.addScheduleWarning(RuleScheduleFixedTime, warningMsg)
 	}
 }
 
@@ -509,18 +501,24 @@ func (c *Compiler) addWeeklyCronWarning(cronExpr string) {
 			weekdayName, hour, minute, strings.ToLower(weekdayName),
 		)
 
-		// This warning is added to the warning count
-		c.IncrementWarningCount()
-
-		// Store the warning for later display
-		c.addScheduleWarning(warningMsg)
+		// The warning is counted and collected for display by the compilation process
+		c.addScheduleWarning(RuleScheduleFixedTime, warningMsg)
 	}
 }
 
-// addScheduleWarning adds a warning to the compiler's schedule warnings list
-func (c *Compiler) addScheduleWarning(warning string) {
+// isScheduleRule reports whether warnings of a rule are collected by addScheduleWarning
+func isScheduleRule(rule DiagnosticRule) bool {
+	return rule == RuleScheduleFixedTime || rule == RuleScheduleNoRepository
+}
+
+// addScheduleWarning adds a warning to the compiler's schedule warnings list, unless its
+// rule is turned off, suppressed or configured as an error
+func (c *Compiler) addScheduleWarning(rule DiagnosticRule, warning string) {
+	if !c.workflowDiagnostics().resolve(rule, warning, 0) {
+		return
+	}
 	if c.scheduleWarnings == nil {
 		c.scheduleWarnings = []string{}
 	}
-	c.scheduleWarnings = append(c.scheduleWarnings, warning)
+	c.scheduleWarnings = append(c.scheduleWarnings, ruleMessage(rule, warning))
 }
@@ -1,11 +1,17 @@
 package workflow
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 )
 
 var templateLog = logger.New("workflow:template")
@@ -70,6 +76,21 @@ func wrapExpressionsInTemplateConditionals(markdown string) string {
 	return result
 }
 
+// needsInterpolationAndTemplateStep reports whether the prompt needs the step that expands
+// runtime imports, interpolates variables and renders template conditionals
+func needsInterpolationAndTemplateStep(expressionMappings []*ExpressionMapping, data *WorkflowData) bool {
+	// Check if we need interpolation
+	hasExpressions := len(expressionMappings) > 0
+
+	// Check if we need template rendering
+	hasTemplatePattern := strings.Contains(data.MarkdownContent, "{{#if ")
+	hasGitHubContext := hasGitHubTool(data.ParsedTools)
+
+	templateLog.Printf("Checking interpolation and template step: expressions=%d, hasPattern=%v, hasGitHubContext=%v",
+		len(expressionMappings), hasTemplatePattern, hasGitHubContext)
+	return hasExpressions || hasTemplatePattern || hasGitHubContext
+}
+
 // generateInterpolationAndTemplateStep generates a step that interpolates GitHub expression variables
 // and renders template conditionals in the prompt file.
 // This combines both variable interpolation and template filtering into a single step.
@@ -85,22 +106,13 @@ func wrapExpressionsInTemplateConditionals(markdown string) string {
 //   - Sets GH_AW_EXPR_* environment variables with the actual GitHub expressions (${{ ... }})
 //   - Runs interpolate_prompt.cjs script to replace placeholders and render template conditionals
 func (c *Compiler) generateInterpolationAndTemplateStep(yaml *strings.Builder, expressionMappings []*ExpressionMapping, data *WorkflowData) {
-	// Check if we need interpolation
-	hasExpressions := len(expressionMappings) > 0
-
-	// Check if we need template rendering
-	hasTemplatePattern := strings.Contains(data.MarkdownContent, "{{#if ")
-	hasGitHubContext := hasGitHubTool(data.ParsedTools)
-	hasTemplates := hasTemplatePattern || hasGitHubContext
-
 	// Skip if neither interpolation nor template rendering is needed
-	if !hasExpressions && !hasTemplates {
+	if !needsInterpolationAndTemplateStep(expressionMappings, data) {
 		templateLog.Print("No interpolation or template rendering needed, skipping step generation")
 		return
 	}
 
-	templateLog.Printf("Generating interpolation and template step: expressions=%d, hasPattern=%v, hasGitHubContext=%v",
-		len(expressionMappings), hasTemplatePattern, hasGitHubContext)
+	templateLog.Printf("Generating interpolation and template step: expressions=%d", len(expressionMappings))
 
 	yaml.WriteString("      - name: Interpolate variables and render templates\n")
 	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
@@ -123,3 +135,481 @@ func (c *Compiler) generateInterpolationAndTemplateStep(yaml *strings.Builder, e
 	yaml.WriteString("            const { main } = require('/opt/gh-aw/actions/interpolate_prompt.cjs');\n")
 	yaml.WriteString("            await main();\n")
 }
+
+// Snippet templates
+//
+// Imported markdown that is inlined into the prompt (imports with inputs, or files that use
+// the tags below) is rendered here at compile time, before it joins the rest of the prompt
+// template pipeline:
+//
+//	${{ github.aw.inputs.name }}            value of an input (or of a field of an object input)
+//	{{#each github.aw.inputs.items}} - {{this}} ({{@index}}) {{else}} no items {{/each}}
+//	{{> shared/snippets/checklist.md title="Security" items=github.aw.inputs.checks}}
+//
+// Inside {{#each}}, {{this}} is the current item, {{this.field}} a field of an object item and
+// {{@index}}, {{@first}} and {{@last}} its position. A partial ({{> path}}) includes the body of
+// another markdown file, resolved like an import, rendered with the named parameters as its
+// inputs (see parser.LoadTemplatePartial).
+//
+// {{#if}} conditionals are not evaluated here: conditions on inputs, this or @-variables are
+// replaced by true or false, and every conditional is then rendered with the rest of the
+// prompt by the runtime template renderer (renderMarkdownTemplate in interpolate_prompt.cjs).
+// Tags inside code blocks and inline code are left as-is.
+
+var (
+	// snippetBlockTagRegex matches the tags that open, split and close template blocks. The
+	// condition of {{#if}} can contain ${{ }} expressions, as in wrapExpressionsInTemplateConditionals.
+	snippetBlockTagRegex = regexp.MustCompile(`\{\{#(if|each)\s+((?:\$\{\{[^\}]*\}\}|[^\}])*?)\s*\}\}|\{\{else\}\}|\{\{/(if|each)\}\}`)
+
+	// snippetPartialTagRegex matches partials
+	snippetPartialTagRegex = regexp.MustCompile(`\{\{>\s*([^\}]*?)\s*\}\}`)
+
+	// snippetValueRegex matches the tags whose values are substituted: {{#if}} conditions,
+	// loop variables and input expressions
+	snippetValueRegex = regexp.MustCompile(`\{\{#if\s+((?:\$\{\{[^\}]*\}\}|[^\}])*?)\s*\}\}` +
+		`|\{\{\s*(this(?:\.[A-Za-z0-9_-]+)*|@index|@first|@last)\s*\}\}` +
+		`|\$\{\{\s*(github\.aw\.inputs\.[A-Za-z0-9_-]+(?:\.[A-Za-z0-9_-]+)*)\s*\}\}`)
+
+	// snippetReferenceRegex matches the references that are resolved at compile time
+	snippetReferenceRegex = regexp.MustCompile(`^(?:github\.aw\.inputs\.[A-Za-z0-9_-]+(?:\.[A-Za-z0-9_-]+)*|this(?:\.[A-Za-z0-9_-]+)*|@index|@first|@last)$`)
+
+	// snippetExpressionRegex matches a condition written as a ${{ }} expression
+	snippetExpressionRegex = regexp.MustCompile(`^\$\{\{\s*(.*?)\s*\}\}$`)
+)
+
+// snippetScope holds the values compile-time references resolve to
+type snippetScope struct {
+	inputs map[string]any
+	// declared holds the input definitions of a partial; nil for the inlined imports, whose
+	// references are checked by the parser and whose unknown inputs are kept as-is
+	declared map[string]parser.ImportInputDefinition
+	inLoop   bool
+	item     any
+	index    int
+	count    int
+}
+
+// snippetRenderer renders inlined imported markdown and the partials it includes
+type snippetRenderer struct {
+	baseDir string
+	cache   *parser.ImportCache
+	stack   []string // partials being rendered, for cycle detection
+	files   []string // partial files that were included
+}
+
+// renderSnippetTemplates renders the snippet templates of inlined imported markdown with the
+// inputs of the imports. Partials are resolved like imports, from baseDir. It returns the
+// rendered markdown and the paths of the partial files it included.
+func renderSnippetTemplates(markdown string, inputs map[string]any, baseDir string, cache *parser.ImportCache) (string, []string, error) {
+	renderer := &snippetRenderer{baseDir: baseDir, cache: cache}
+	rendered, err := renderer.render(markdown, &snippetScope{inputs: inputs})
+	if err != nil {
+		return "", nil, err
+	}
+	templateLog.Printf("Rendered snippet templates: %d -> %d bytes, %d partials", len(markdown), len(rendered), len(renderer.files))
+	return rendered, renderer.files, nil
+}
+
+// render expands the {{#each}} blocks and partials of markdown and substitutes its values
+func (r *snippetRenderer) render(markdown string, scope *snippetScope) (string, error) {
+	expanded, err := r.expandEachBlocks(markdown, scope)
+	if err != nil {
+		return "", err
+	}
+	if expanded, err = r.expandPartials(expanded, scope); err != nil {
+		return "", err
+	}
+	return substituteSnippetValues(expanded, scope)
+}
+
+// snippetBlock is an {{#if}} or {{#each}} block; offsets are those of its tags, widened to
+// their lines for tags alone on their line
+type snippetBlock struct {
+	kind, arg            string
+	openStart, openEnd   int
+	elseStart, elseEnd   int
+	closeStart, closeEnd int
+}
+
+// expandEachBlocks renders the outermost {{#each}} blocks of markdown. Their content is
+// rendered once per item, so nested blocks are expanded with the scope of the item.
+func (r *snippetRenderer) expandEachBlocks(markdown string, scope *snippetScope) (string, error) {
+	masked := parser.MaskMarkdownCode(markdown)
+	var stack []*snippetBlock
+	var eachBlocks []*snippetBlock
+	for _, m := range snippetBlockTagRegex.FindAllStringSubmatchIndex(masked, -1) {
+		start, end := standaloneTagRange(markdown, m[0], m[1])
+		tag := masked[m[0]:m[1]]
+		switch {
+		case m[2] >= 0:
+			stack = append(stack, &snippetBlock{kind: masked[m[2]:m[3]], arg: strings.TrimSpace(masked[m[4]:m[5]]), openStart: start, openEnd: end, elseStart: -1})
+		case tag == "{{else}}":
+			if len(stack) == 0 {
+				return "", errors.New("{{else}} without a matching {{#if}} or {{#each}}")
+			}
+			block := stack[len(stack)-1]
+			if block.elseStart >= 0 {
+				return "", fmt.Errorf("{{#%s %s}} has more than one {{else}}", block.kind, block.arg)
+			}
+			block.elseStart, block.elseEnd = start, end
+		default:
+			kind := masked[m[6]:m[7]]
+			if len(stack) == 0 {
+				return "", fmt.Errorf("{{/%s}} without a matching {{#%s}}", kind, kind)
+			}
+			block := stack[len(stack)-1]
+			if block.kind != kind {
+				return "", fmt.Errorf("{{#%s %s}} is closed with {{/%s}}", block.kind, block.arg, kind)
+			}
+			stack = stack[:len(stack)-1]
+			block.closeStart, block.closeEnd = start, end
+			if block.kind == "each" && !slices.ContainsFunc(stack, func(b *snippetBlock) bool { return b.kind == "each" }) {
+				eachBlocks = append(eachBlocks, block)
+			}
+		}
+	}
+	if len(stack) > 0 {
+		block := stack[len(stack)-1]
+		return "", fmt.Errorf("{{#%s %s}} is not closed with {{/%s}}", block.kind, block.arg, block.kind)
+	}
+
+	var out strings.Builder
+	pos := 0
+	for _, block := range eachBlocks {
+		out.WriteString(markdown[pos:block.openStart])
+		rendered, err := r.renderEachBlock(markdown, block, scope)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(rendered)
+		pos = block.closeEnd
+	}
+	out.WriteString(markdown[pos:])
+	return out.String(), nil
+}
+
+// renderEachBlock renders the content of an {{#each}} block for each item of its array, or
+// its {{else}} branch when the array is empty
+func (r *snippetRenderer) renderEachBlock(markdown string, block *snippetBlock, scope *snippetScope) (string, error) {
+	ref := block.arg
+	if inner := snippetExpressionRegex.FindStringSubmatch(ref); inner != nil {
+		ref = inner[1]
+	}
+	if !snippetReferenceRegex.MatchString(ref) {
+		return "", fmt.Errorf("{{#each %s}} must iterate over an input, this or a field of this (e.g. {{#each github.aw.inputs.items}})", block.arg)
+	}
+	value, _, err := scope.lookup(ref)
+	if err != nil {
+		return "", err
+	}
+
+	body, elseBody := markdown[block.openEnd:block.closeStart], ""
+	if block.elseStart >= 0 {
+		body, elseBody = markdown[block.openEnd:block.elseStart], markdown[block.elseEnd:block.closeStart]
+	}
+
+	var items []any
+	switch v := value.(type) {
+	case nil:
+	case []any:
+		items = v
+	default:
+		return "", fmt.Errorf("{{#each %s}} expects an array, got %s", block.arg, describeSnippetValue(value))
+	}
+	if len(items) == 0 {
+		return r.render(elseBody, scope)
+	}
+
+	var out strings.Builder
+	for i, item := range items {
+		itemScope := *scope
+		itemScope.inLoop, itemScope.item, itemScope.index, itemScope.count = true, item, i, len(items)
+		rendered, err := r.render(body, &itemScope)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(rendered)
+	}
+	return out.String(), nil
+}
+
+// expandPartials replaces the partials of markdown with their rendered content
+func (r *snippetRenderer) expandPartials(markdown string, scope *snippetScope) (string, error) {
+	masked := parser.MaskMarkdownCode(markdown)
+	var out strings.Builder
+	pos := 0
+	for _, m := range snippetPartialTagRegex.FindAllStringSubmatchIndex(masked, -1) {
+		start, end := standaloneTagRange(markdown, m[0], m[1])
+		rendered, err := r.renderPartial(markdown[m[2]:m[3]], scope)
+		if err != nil {
+			return "", err
+		}
+		if start != m[0] || end != m[1] {
+			// A partial alone on its line takes the line
+			if rendered != "" && !strings.HasSuffix(rendered, "\n") {
+				rendered += "\n"
+			}
+		} else {
+			rendered = strings.TrimRight(rendered, "\n")
+		}
+		out.WriteString(markdown[pos:start])
+		out.WriteString(rendered)
+		pos = end
+	}
+	out.WriteString(markdown[pos:])
+	return out.String(), nil
+}
+
+// renderPartial renders a partial: the body of another markdown file with named parameters
+func (r *snippetRenderer) renderPartial(arg string, scope *snippetScope) (string, error) {
+	partialPath, params, err := parsePartialArguments(arg, scope)
+	if err != nil {
+		return "", fmt.Errorf("invalid partial {{> %s}}: %w", arg, err)
+	}
+	partial, err := parser.LoadTemplatePartial(partialPath, params, r.baseDir, r.cache)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(r.stack, partial.FullPath) {
+		return "", fmt.Errorf("circular partial: %s -> %s", strings.Join(r.stack, " -> "), partial.FullPath)
+	}
+
+	templateLog.Printf("Rendering partial %s with %d parameters", partialPath, len(params))
+	r.files = append(r.files, partial.FullPath)
+	r.stack = append(r.stack, partial.FullPath)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+	rendered, err := r.render(partial.Markdown, &snippetScope{inputs: partial.Inputs, declared: partial.Definitions})
+	if err != nil {
+		return "", fmt.Errorf("in partial '%s': %w", partialPath, err)
+	}
+	return rendered, nil
+}
+
+// parsePartialArguments parses the path and the named parameters of a partial:
+// path key="string" key=42 key=true key=github.aw.inputs.name key=this.field
+func parsePartialArguments(arg string, scope *snippetScope) (string, map[string]any, error) {
+	partialPath, rest, _ := strings.Cut(strings.TrimSpace(arg), " ")
+	if partialPath == "" {
+		return "", nil, errors.New("missing path")
+	}
+
+	params := make(map[string]any)
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return "", nil, fmt.Errorf("expected key=value, got %q", rest)
+		}
+		if strings.HasPrefix(value, `"`) {
+			quoted, err := strconv.QuotedPrefix(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("unterminated string for parameter '%s'", key)
+			}
+			params[key], _ = strconv.Unquote(quoted)
+			rest = value[len(quoted):]
+			continue
+		}
+
+		literal, remaining, _ := strings.Cut(value, " ")
+		rest = remaining
+		switch {
+		case literal == "true" || literal == "false":
+			params[key] = literal == "true"
+		case snippetReferenceRegex.MatchString(literal):
+			resolved, found, err := scope.lookup(literal)
+			if err != nil {
+				return "", nil, err
+			}
+			if found && resolved != nil {
+				params[key] = resolved
+			}
+		default:
+			if number, err := strconv.ParseInt(literal, 10, 64); err == nil {
+				params[key] = number
+			} else if number, err := strconv.ParseFloat(literal, 64); err == nil {
+				params[key] = number
+			} else {
+				return "", nil, fmt.Errorf("invalid value %q for parameter '%s'; quote strings", literal, key)
+			}
+		}
+	}
+	return partialPath, params, nil
+}
+
+// substituteSnippetValues substitutes the values of loop variables and inputs, and replaces
+// {{#if}} conditions on them by true or false for the runtime template renderer
+func substituteSnippetValues(markdown string, scope *snippetScope) (string, error) {
+	masked := parser.MaskMarkdownCode(markdown)
+	var out strings.Builder
+	pos := 0
+	for _, m := range snippetValueRegex.FindAllStringSubmatchIndex(masked, -1) {
+		replacement := markdown[m[0]:m[1]]
+		switch {
+		case m[2] >= 0:
+			ref := strings.TrimSpace(markdown[m[2]:m[3]])
+			if inner := snippetExpressionRegex.FindStringSubmatch(ref); inner != nil {
+				ref = inner[1]
+			}
+			if snippetReferenceRegex.MatchString(ref) {
+				value, found, err := scope.lookup(ref)
+				if err != nil {
+					return "", err
+				}
+				if found {
+					replacement = fmt.Sprintf("{{#if %t}}", isSnippetValueTruthy(value))
+				}
+			}
+		default:
+			var ref string
+			if m[4] >= 0 {
+				ref = markdown[m[4]:m[5]]
+			} else {
+				ref = markdown[m[6]:m[7]]
+			}
+			value, found, err := scope.lookup(ref)
+			if err != nil {
+				return "", err
+			}
+			if found {
+				replacement = formatSnippetValue(value)
+			}
+		}
+		out.WriteString(markdown[pos:m[0]])
+		out.WriteString(replacement)
+		pos = m[1]
+	}
+	out.WriteString(markdown[pos:])
+	return out.String(), nil
+}
+
+// standaloneTagRange widens the range of a block tag or partial that is alone on its line
+// to the whole line, so that it does not leave a blank line when rendered
+func standaloneTagRange(markdown string, start, end int) (int, int) {
+	lineStart := strings.LastIndexByte(markdown[:start], '\n') + 1
+	lineEnd := len(markdown)
+	if i := strings.IndexByte(markdown[end:], '\n'); i >= 0 {
+		lineEnd = end + i + 1
+	}
+	if strings.TrimSpace(markdown[lineStart:start]) == "" && strings.TrimSpace(markdown[end:lineEnd]) == "" {
+		return lineStart, lineEnd
+	}
+	return start, end
+}
+
+// lookup resolves a compile-time reference. found is false for unknown inputs of the inlined
+// imports, which are kept as-is.
+func (s *snippetScope) lookup(ref string) (any, bool, error) {
+	var value any
+	var path []string
+	switch {
+	case strings.HasPrefix(ref, "github.aw.inputs."):
+		parts := strings.Split(strings.TrimPrefix(ref, "github.aw.inputs."), ".")
+		name := parts[0]
+		if s.declared != nil {
+			if _, ok := s.declared[name]; !ok {
+				return nil, false, fmt.Errorf("unknown input '%s' in ${{ %s }}. Declared inputs: %s", name, ref, strings.Join(slices.Sorted(maps.Keys(s.declared)), ", "))
+			}
+		}
+		var ok bool
+		if value, ok = s.inputs[name]; !ok {
+			return nil, s.declared != nil, nil
+		}
+		path = parts[1:]
+	case ref == "this" || strings.HasPrefix(ref, "this."):
+		if !s.inLoop {
+			return nil, false, fmt.Errorf("{{%s}} can only be used inside {{#each}}", ref)
+		}
+		value = s.item
+		path = strings.Split(ref, ".")[1:]
+	default:
+		if !s.inLoop {
+			return nil, false, fmt.Errorf("{{%s}} can only be used inside {{#each}}", ref)
+		}
+		switch ref {
+		case "@index":
+			return s.index, true, nil
+		case "@first":
+			return s.index == 0, true, nil
+		default:
+			return s.index == s.count-1, true, nil
+		}
+	}
+
+	for _, field := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false, fmt.Errorf("cannot read field '%s' of %s in %s", field, describeSnippetValue(value), ref)
+		}
+		value = object[field]
+	}
+	return value, true, nil
+}
+
+// formatSnippetValue formats a value for the rendered markdown
+func formatSnippetValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatSnippetValue(item)
+		}
+		return strings.Join(items, ", ")
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// isSnippetValueTruthy reports whether a value selects the {{#if}} branch: false, 0, empty
+// strings, empty arrays and objects, and unset values select the {{else}} branch
+func isSnippetValueTruthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	case int:
+		return v != 0
+	case int64:
+		return v != 0
+	case uint64:
+		return v != 0
+	case float64:
+		return v != 0
+	}
+	return true
+}
+
+// describeSnippetValue describes the type of a value in template errors
+func describeSnippetValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case int, int64, uint64, float64:
+		return fmt.Sprintf("number %v", v)
+	}
+	return fmt.Sprintf("%T", value)
+}
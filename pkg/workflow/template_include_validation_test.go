@@ -467,3 +467,43 @@ func TestValidateNoIncludesInTemplateRegions_SingleError(t *testing.T) {
 		t.Errorf("Error should contain violation: tools.md")
 	}
 }
+
+func TestValidateNoCompileTimeTemplates(t *testing.T) {
+	valid := `# Test Workflow
+
+{{#if github.event.issue.number}}
+Issue ${{ github.event.issue.number }}
+{{else}}
+No issue
+{{/if}}
+
+Snippets can loop with ` + "`{{#each github.aw.inputs.items}}`" + `:
+
+` + "```markdown" + `
+{{#each github.aw.inputs.items}}
+{{> shared/snippets/item.md}}
+{{/each}}
+` + "```"
+	if err := validateNoCompileTimeTemplates(valid); err != nil {
+		t.Errorf("validateNoCompileTimeTemplates() unexpected error for runtime conditionals and code: %v", err)
+	}
+
+	input := `# Test Workflow
+
+{{#each github.aw.inputs.checks}}
+- {{this}}
+{{/each}}
+
+{{> shared/snippets/footer.md}}`
+	err := validateNoCompileTimeTemplates(input)
+	if err == nil {
+		t.Fatal("validateNoCompileTimeTemplates() expected error, got nil")
+	}
+	errStr := err.Error()
+	if !strings.Contains(errStr, "line 3:") || !strings.Contains(errStr, "line 7:") {
+		t.Errorf("Error should report each line with compile-time tags, got: %v", err)
+	}
+	if !strings.Contains(errStr, "only supported in imported files") {
+		t.Errorf("Error should explain where the tags are supported, got: %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSnippetTemplates(t *testing.T) {
+	inputs := map[string]any{
+		"checks": []any{
+			map[string]any{"name": "lint", "blocking": true},
+			map[string]any{"name": "docs", "blocking": false},
+		},
+		"reviewer": "octocat",
+		"strict":   false,
+		"missing":  nil,
+	}
+
+	tests := []struct {
+		name     string
+		markdown string
+		rendered string // output of the snippet templates
+		prompt   string // output after the runtime template renderer
+	}{
+		{
+			name:     "input values",
+			markdown: "Ask ${{ github.aw.inputs.reviewer }} to review.\n",
+			rendered: "Ask octocat to review.\n",
+			prompt:   "Ask octocat to review.\n",
+		},
+		{
+			name:     "each with fields and position",
+			markdown: "{{#each github.aw.inputs.checks}}\n{{@index}}. {{this.name}}{{#if this.blocking}} (blocking){{/if}}{{#if @last}}.{{else}},{{/if}}\n{{/each}}\n",
+			rendered: "0. lint{{#if true}} (blocking){{/if}}{{#if false}}.{{else}},{{/if}}\n1. docs{{#if false}} (blocking){{/if}}{{#if true}}.{{else}},{{/if}}\n",
+			prompt:   "0. lint (blocking),\n1. docs.\n",
+		},
+		{
+			name:     "each over an unset input",
+			markdown: "{{#each github.aw.inputs.missing}}\n- {{this}}\n{{else}}\nNo checks.\n{{/each}}\n",
+			rendered: "No checks.\n",
+			prompt:   "No checks.\n",
+		},
+		{
+			name:     "nested each over a field of the item",
+			markdown: "{{#each github.aw.inputs.groups}}\n{{this.name}}:\n{{#each this.items}}\n- {{this}}\n{{/each}}\n{{/each}}\n",
+			rendered: "a:\n- 1\n- 2\nb:\n",
+			prompt:   "a:\n- 1\n- 2\nb:\n",
+		},
+		{
+			name:     "conditions on inputs become true or false",
+			markdown: "{{#if github.aw.inputs.strict}}\nBe strict.\n{{else}}\nBe lenient.\n{{/if}}\n{{#if ${{ github.aw.inputs.reviewer }} }}\nReviewer set.\n{{/if}}\n",
+			rendered: "{{#if false}}\nBe strict.\n{{else}}\nBe lenient.\n{{/if}}\n{{#if true}}\nReviewer set.\n{{/if}}\n",
+			prompt:   "Be lenient.\nReviewer set.\n",
+		},
+		{
+			name:     "runtime conditional is kept",
+			markdown: "{{#if ${{ github.event.issue.number }} }}\nIssue ${{ github.aw.inputs.reviewer }}\n{{else}}\nNo issue\n{{/if}}\n",
+			rendered: "{{#if ${{ github.event.issue.number }} }}\nIssue octocat\n{{else}}\nNo issue\n{{/if}}\n",
+		},
+		{
+			name:     "tags in code are kept",
+			markdown: "```markdown\n{{#each github.aw.inputs.checks}}\n- {{this}}\n{{/each}}\n```\nWrite `{{> shared/footer.md}}` or `${{ github.aw.inputs.reviewer }}`.\n",
+			rendered: "```markdown\n{{#each github.aw.inputs.checks}}\n- {{this}}\n{{/each}}\n```\nWrite `{{> shared/footer.md}}` or `${{ github.aw.inputs.reviewer }}`.\n",
+		},
+		{
+			name:     "unknown inputs of the inlined imports are kept",
+			markdown: "Use ${{ github.aw.inputs.other }}.",
+			rendered: "Use ${{ github.aw.inputs.other }}.",
+		},
+	}
+	inputs["groups"] = []any{
+		map[string]any{"name": "a", "items": []any{uint64(1), uint64(2)}},
+		map[string]any{"name": "b", "items": []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, _, err := renderSnippetTemplates(tt.markdown, inputs, t.TempDir(), nil)
+			require.NoError(t, err, "template should render")
+			assert.Equal(t, tt.rendered, rendered, "snippet templates should render as expected")
+			if tt.prompt != "" {
+				assert.Equal(t, tt.prompt, renderPromptTemplate(rendered), "conditionals should render at runtime")
+			}
+		})
+	}
+}
+
+func TestRenderSnippetTemplatesPartials(t *testing.T) {
+	baseDir := t.TempDir()
+	snippetsDir := filepath.Join(baseDir, "snippets")
+	require.NoError(t, os.MkdirAll(snippetsDir, 0o755), "should create snippets directory")
+	require.NoError(t, os.WriteFile(filepath.Join(snippetsDir, "checklist.md"), []byte(`---
+inputs:
+  title:
+    type: string
+    required: true
+  items:
+    type: array
+    items: string
+    default: []
+  level:
+    type: number
+    default: 2
+---
+## {{> snippets/heading.md text=github.aw.inputs.title}} (level ${{ github.aw.inputs.level }})
+
+{{#each github.aw.inputs.items}}
+- [ ] {{this}}
+{{else}}
+Nothing to check.
+{{/each}}
+`), 0o644), "should write checklist partial")
+	require.NoError(t, os.WriteFile(filepath.Join(snippetsDir, "heading.md"), []byte(`---
+inputs:
+  text:
+    type: string
+---
+**${{ github.aw.inputs.text }}**
+`), 0o644), "should write heading partial")
+	require.NoError(t, os.WriteFile(filepath.Join(snippetsDir, "loop.md"), []byte("{{> snippets/loop.md}}\n"), 0o644), "should write recursive partial")
+	require.NoError(t, os.WriteFile(filepath.Join(snippetsDir, "typo.md"), []byte("---\ninputs:\n  text:\n    type: string\n---\n${{ github.aw.inputs.txt }}\n"), 0o644), "should write partial with a typo")
+
+	inputs := map[string]any{"checks": []any{"lint", "test"}}
+
+	rendered, files, err := renderSnippetTemplates("# Review\n\n{{> snippets/checklist.md title=\"Security\" items=github.aw.inputs.checks}}\n\n{{> snippets/checklist.md title=\"Docs\" level=3}}\n", inputs, baseDir, nil)
+	require.NoError(t, err, "partials should render")
+	assert.Equal(t, "# Review\n\n## **Security** (level 2)\n\n- [ ] lint\n- [ ] test\n\n## **Docs** (level 3)\n\nNothing to check.\n", rendered, "partials should render with their parameters and defaults")
+	assert.Contains(t, files, filepath.Join(snippetsDir, "checklist.md"), "partial files should be reported")
+	assert.Contains(t, files, filepath.Join(snippetsDir, "heading.md"), "nested partial files should be reported")
+
+	errorTests := []struct {
+		name     string
+		markdown string
+		errMsg   string
+	}{
+		{"missing required parameter", "{{> snippets/checklist.md}}", "missing required input 'title'"},
+		{"parameter of wrong type", "{{> snippets/checklist.md title=\"x\" level=\"high\"}}", "input 'level' must be a number"},
+		{"unknown parameter", "{{> snippets/heading.md text=\"x\" size=1}}", "unknown input 'size'"},
+		{"unquoted string", "{{> snippets/heading.md text=hello}}", "quote strings"},
+		{"missing file", "{{> snippets/missing.md}}", "failed to resolve partial 'snippets/missing.md'"},
+		{"circular partial", "{{> snippets/loop.md}}", "circular partial"},
+		{"unknown input in partial", "{{> snippets/typo.md text=\"x\"}}", "unknown input 'txt'"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := renderSnippetTemplates(tt.markdown, inputs, baseDir, nil)
+			require.Error(t, err, "invalid partial should fail")
+			assert.Contains(t, err.Error(), tt.errMsg, "error should describe the problem")
+		})
+	}
+}
+
+func TestRenderSnippetTemplatesErrors(t *testing.T) {
+	inputs := map[string]any{"name": "x", "items": []any{"a"}}
+
+	tests := []struct {
+		name     string
+		markdown string
+		errMsg   string
+	}{
+		{"each over a string", "{{#each github.aw.inputs.name}}{{this}}{{/each}}", "expects an array, got string"},
+		{"each over a runtime expression", "{{#each github.event.labels}}{{this}}{{/each}}", "must iterate over an input"},
+		{"this outside each", "{{this}}", "can only be used inside {{#each}}"},
+		{"unclosed block", "{{#each github.aw.inputs.items}}{{this}}", "is not closed"},
+		{"mismatched block", "{{#each github.aw.inputs.items}}{{this}}{{/if}}", "is closed with {{/if}}"},
+		{"stray else", "a{{else}}b", "without a matching"},
+		{"two else branches", "{{#if x}}a{{else}}b{{else}}c{{/if}}", "more than one {{else}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := renderSnippetTemplates(tt.markdown, inputs, t.TempDir(), nil)
+			require.Error(t, err, "invalid template should fail")
+			assert.Contains(t, err.Error(), tt.errMsg, "error should describe the problem")
+		})
+	}
+}
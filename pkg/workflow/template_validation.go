@@ -8,6 +8,7 @@
 // # Validation Functions
 //
 //   - validateNoIncludesInTemplateRegions() - Validates that imports are not inside template blocks
+//   - validateNoCompileTimeTemplates() - Validates that snippet-only tags are not used in the workflow markdown
 //
 // # Validation Pattern: Structure Validation
 //
@@ -89,3 +90,22 @@ func validateNoIncludesInTemplateRegions(markdown string) error {
 
 	return nil
 }
+
+// validateNoCompileTimeTemplates checks that the workflow's own markdown does not use the
+// template tags that are only rendered in imported snippets ({{#each}} and partials).
+// The workflow markdown is loaded at runtime, where only {{#if}} conditionals are rendered.
+// Tags inside code blocks and inline code are documentation and are not checked.
+func validateNoCompileTimeTemplates(markdown string) error {
+	var errs []error
+	lines := strings.Split(markdown, "\n")
+	for lineNum, masked := range strings.Split(parser.MaskMarkdownCode(markdown), "\n") {
+		if parser.UsesCompileTimeTemplates(masked) {
+			errs = append(errs, fmt.Errorf("line %d: {{#each}} and {{> partial}} are only supported in imported files; move '%s' into a shared file and import it", lineNum+1, strings.TrimSpace(lines[lineNum])))
+		}
+	}
+	if len(errs) > 0 {
+		templateValidationLog.Printf("Found %d compile-time template tags in workflow markdown", len(errs))
+		return errors.Join(errs...)
+	}
+	return nil
+}
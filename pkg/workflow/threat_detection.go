@@ -367,10 +367,13 @@ func (c *Compiler) buildEngineSteps(data *WorkflowData) []string {
 		Tools: map[string]any{
 			"bash": []any{"cat", "head", "tail", "wc", "grep", "ls", "jq"},
 		},
-		SafeOutputs:  nil,
-		Network:      "",
-		EngineConfig: detectionEngineConfig,
-		AI:           engineSetting,
+		SafeOutputs:       nil,
+		Network:           "",
+		EngineConfig:      detectionEngineConfig,
+		AI:                engineSetting,
+		DiagnosticWriter:  data.DiagnosticWriter,
+		Diagnostics:       data.Diagnostics,
+		ActionPinWarnings: data.ActionPinWarnings,
 	}
 
 	var steps []string
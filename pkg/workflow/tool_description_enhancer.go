@@ -186,6 +186,61 @@ func enhanceToolDescription(toolName, baseDescription string, safeOutputs *SafeO
 			}
 		}
 
+	case "create_check_run":
+		if config := safeOutputs.CreateCheckRun; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d check run(s) can be created.", config.Max))
+			}
+			if config.MaxAnnotations > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d annotation(s) per check run.", config.MaxAnnotations))
+			}
+			if len(config.AllowedConclusions) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only these conclusions are allowed: %v.", config.AllowedConclusions))
+			}
+		}
+
+	case "merge_pull_request":
+		if config := safeOutputs.MergePullRequest; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d pull request(s) can be merged.", config.Max))
+			}
+			if config.Target != "" {
+				constraints = append(constraints, fmt.Sprintf("Target: %s.", config.Target))
+			}
+			constraints = append(constraints, fmt.Sprintf("Merge method: %s.", config.MergeMethod))
+			if config.requiresChecks() {
+				constraints = append(constraints, "All checks must pass.")
+			}
+			if config.RequiredApprovals > 0 {
+				constraints = append(constraints, fmt.Sprintf("At least %d approving review(s) required.", config.RequiredApprovals))
+			}
+			if len(config.RequiredLabels) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only PRs with labels %v can be merged.", config.RequiredLabels))
+			}
+			if config.RequiredTitlePrefix != "" {
+				constraints = append(constraints, fmt.Sprintf("Only PRs with title prefix %q can be merged.", config.RequiredTitlePrefix))
+			}
+			if len(config.AllowedAuthors) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only PRs authored by %v can be merged.", config.AllowedAuthors))
+			}
+			if config.RequireTrackerID {
+				constraints = append(constraints, "Only PRs created by this workflow can be merged.")
+			}
+		}
+
+	case "create_commit_status":
+		if config := safeOutputs.CreateCommitStatus; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d commit status(es) can be set.", config.Max))
+			}
+			if config.Context != "" {
+				constraints = append(constraints, fmt.Sprintf("The status is reported under the context %q.", config.Context))
+			}
+			if len(config.AllowedStates) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only these states are allowed: %v.", config.AllowedStates))
+			}
+		}
+
 	case "add_labels":
 		if config := safeOutputs.AddLabels; config != nil {
 			if config.Max > 0 {
@@ -276,6 +331,116 @@ func enhanceToolDescription(toolName, baseDescription string, safeOutputs *SafeO
 			}
 		}
 
+	case "create_release":
+		if config := safeOutputs.CreateRelease; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d release(s) can be created.", config.Max))
+			}
+			if len(config.AllowedTags) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Tags must match: %v.", config.AllowedTags))
+			}
+			if config.forcesDraft() {
+				constraints = append(constraints, "Releases are always created as drafts.")
+			}
+			constraints = append(constraints, fmt.Sprintf("Asset extensions allowed: %v (max %d KB each).", config.AllowedExts, config.MaxSizeKB))
+		}
+
+	case "create_tag":
+		if config := safeOutputs.CreateTag; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d tag(s) can be created.", config.Max))
+			}
+			if len(config.AllowedTags) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Tags must match: %v.", config.AllowedTags))
+			}
+		}
+
+	case "update_file":
+		if config := safeOutputs.UpdateFile; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d file(s) can be updated.", config.Max))
+			}
+			if len(config.AllowedPaths) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Paths must match: %v.", config.AllowedPaths))
+			}
+			if config.Branch != "" {
+				constraints = append(constraints, fmt.Sprintf("Default branch: %s.", config.Branch))
+			}
+			if len(config.AllowedBranches) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Branches must match: %v.", config.AllowedBranches))
+			}
+			if config.MaxSizeKB > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum file size: %d KB.", config.MaxSizeKB))
+			}
+		}
+
+	case "lock_conversation":
+		if config := safeOutputs.LockConversation; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d conversation(s) can be locked.", config.Max))
+			}
+			if len(config.AllowedReasons) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Allowed reasons: %v.", config.AllowedReasons))
+			}
+			if config.Target != "" {
+				constraints = append(constraints, fmt.Sprintf("Target: %s.", config.Target))
+			}
+			if len(config.RequiredLabels) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only issues or PRs with labels %v can be locked.", config.RequiredLabels))
+			}
+			if config.RequiredTitlePrefix != "" {
+				constraints = append(constraints, fmt.Sprintf("Only issues or PRs with title prefix %q can be locked.", config.RequiredTitlePrefix))
+			}
+		}
+
+	case "pin_issue":
+		if config := safeOutputs.PinIssue; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d issue(s) can be pinned.", config.Max))
+			}
+			if config.Target != "" {
+				constraints = append(constraints, fmt.Sprintf("Target: %s.", config.Target))
+			}
+			if len(config.RequiredLabels) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only issues with labels %v can be pinned.", config.RequiredLabels))
+			}
+			if config.RequiredTitlePrefix != "" {
+				constraints = append(constraints, fmt.Sprintf("Only issues with title prefix %q can be pinned.", config.RequiredTitlePrefix))
+			}
+		}
+
+	case "transfer_issue":
+		if config := safeOutputs.TransferIssue; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d issue(s) can be transferred.", config.Max))
+			}
+			if len(config.AllowedDestinations) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Allowed destinations: %v.", config.AllowedDestinations))
+			}
+			if config.Target != "" {
+				constraints = append(constraints, fmt.Sprintf("Target: %s.", config.Target))
+			}
+			if len(config.RequiredLabels) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only issues with labels %v can be transferred.", config.RequiredLabels))
+			}
+			if config.RequiredTitlePrefix != "" {
+				constraints = append(constraints, fmt.Sprintf("Only issues with title prefix %q can be transferred.", config.RequiredTitlePrefix))
+			}
+		}
+
+	case "manage_labels":
+		if config := safeOutputs.ManageLabels; config != nil {
+			if config.Max > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d label operation(s) can be performed.", config.Max))
+			}
+			if len(config.AllowedPrefixes) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Label names must start with one of: %v.", config.AllowedPrefixes))
+			}
+			if !config.AllowDelete {
+				constraints = append(constraints, "The 'delete' operation is disabled.")
+			}
+		}
+
 	case "missing_tool":
 		if config := safeOutputs.MissingTool; config != nil {
 			if config.Max > 0 {
@@ -328,6 +493,9 @@ func enhanceToolDescription(toolName, baseDescription string, safeOutputs *SafeO
 		// noop has no configurable constraints
 	}
 
+	// Cross-item references apply to several tools, so they are added after the per-tool constraints
+	constraints = append(constraints, referenceConstraints(toolName, safeOutputs)...)
+
 	if len(constraints) == 0 {
 		toolDescriptionEnhancerLog.Printf("No constraints found for tool: %s", toolName)
 		return baseDescription
@@ -11,8 +11,13 @@ import (
 
 var unifiedPromptLog = logger.New("workflow:unified_prompt_step")
 
+// prContextShellCondition includes the PR context section for comments and reviews on pull requests
+const prContextShellCondition = `[ "$GITHUB_EVENT_NAME" = "issue_comment" ] && [ -n "$GH_AW_IS_PR_COMMENT" ] || [ "$GITHUB_EVENT_NAME" = "pull_request_review_comment" ] || [ "$GITHUB_EVENT_NAME" = "pull_request_review" ]`
+
 // PromptSection represents a section of prompt text to be appended
 type PromptSection struct {
+	// Name identifies an inline section in prompt previews (file sections use their file name)
+	Name string
 	// Content is the actual prompt text or a reference to a file
 	Content string
 	// IsFile indicates if Content is a filename (true) or inline text (false)
@@ -260,6 +265,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		unifiedPromptLog.Print("Adding trial mode section")
 		trialContent := fmt.Sprintf("## Note\nThis workflow is running in directory $GITHUB_WORKSPACE, but that directory actually contains the contents of the repository '%s'.", c.trialLogicalRepoSlug)
 		sections = append(sections, PromptSection{
+			Name:    "trial-mode",
 			Content: trialContent,
 			IsFile:  false,
 		})
@@ -280,6 +286,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		var repoMemContent strings.Builder
 		generateRepoMemoryPromptSection(&repoMemContent, data.RepoMemoryConfig)
 		sections = append(sections, PromptSection{
+			Name:    "repo-memory",
 			Content: repoMemContent.String(),
 			IsFile:  false,
 		})
@@ -317,6 +324,7 @@ Discover available tools from the safeoutputs MCP server.
 </instructions>
 </safe-outputs>`
 		sections = append(sections, PromptSection{
+			Name:    "safe-outputs",
 			Content: safeOutputsContent,
 			IsFile:  false,
 		})
@@ -339,6 +347,7 @@ Discover available tools from the safeoutputs MCP server.
 			}
 
 			sections = append(sections, PromptSection{
+				Name:    "github-context",
 				Content: modifiedPromptText,
 				IsFile:  false,
 				EnvVars: envVars,
@@ -358,8 +367,6 @@ Discover available tools from the safeoutputs MCP server.
 		// This checks for issue_comment, pull_request_review_comment, or pull_request_review events
 		// For issue_comment, we also need to check if it's on a PR (github.event.issue.pull_request != null)
 		// However, for simplicity in the unified step, we'll add an environment variable to check this
-		shellCondition := `[ "$GITHUB_EVENT_NAME" = "issue_comment" ] && [ -n "$GH_AW_IS_PR_COMMENT" ] || [ "$GITHUB_EVENT_NAME" = "pull_request_review_comment" ] || [ "$GITHUB_EVENT_NAME" = "pull_request_review" ]`
-
 		// Add environment variable to check if issue_comment is on a PR
 		envVars := map[string]string{
 			"GH_AW_IS_PR_COMMENT": "${{ github.event.issue.pull_request && 'true' || '' }}",
@@ -368,7 +375,7 @@ Discover available tools from the safeoutputs MCP server.
 		sections = append(sections, PromptSection{
 			Content:        prContextPromptFile,
 			IsFile:         true,
-			ShellCondition: shellCondition,
+			ShellCondition: prContextShellCondition,
 			EnvVars:        envVars,
 		})
 	}
@@ -391,61 +398,7 @@ func (c *Compiler) generateUnifiedPromptCreationStep(yaml *strings.Builder, buil
 	// Get the heredoc delimiter for consistent usage
 	delimiter := GenerateHeredocDelimiter("PROMPT")
 
-	// Collect all environment variables from built-in sections and user prompt expressions
-	allEnvVars := make(map[string]string)
-
-	// Also collect all expression mappings for the substitution step (using a map to avoid duplicates)
-	expressionMappingsMap := make(map[string]*ExpressionMapping)
-
-	// Add environment variables and expression mappings from built-in sections
-	for _, section := range builtinSections {
-		for key, value := range section.EnvVars {
-			// Extract the GitHub expression from the value (e.g., "${{ github.repository }}" -> "github.repository")
-			// This is needed for the substitution step
-			if strings.HasPrefix(value, "${{ ") && strings.HasSuffix(value, " }}") {
-				content := strings.TrimSpace(value[4 : len(value)-3])
-				// Add to both allEnvVars (for prompt creation step) and expressionMappingsMap (for substitution step)
-				allEnvVars[key] = value
-				// Only add if not already present (user prompt expressions take precedence)
-				if _, exists := expressionMappingsMap[key]; !exists {
-					expressionMappingsMap[key] = &ExpressionMapping{
-						EnvVar:  key,
-						Content: content,
-					}
-				}
-			} else {
-				// For static values (not GitHub Actions expressions), only add to expressionMappingsMap
-				// This ensures they're only available in the substitution step, not the prompt creation step
-				if _, exists := expressionMappingsMap[key]; !exists {
-					expressionMappingsMap[key] = &ExpressionMapping{
-						EnvVar:  key,
-						Content: fmt.Sprintf("'%s'", value), // Wrap in quotes for substitution
-					}
-				}
-			}
-		}
-	}
-
-	// Add environment variables from user prompt expressions (these override built-in ones)
-	for _, mapping := range expressionMappings {
-		allEnvVars[mapping.EnvVar] = fmt.Sprintf("${{ %s }}", mapping.Content)
-		expressionMappingsMap[mapping.EnvVar] = mapping
-	}
-
-	// Convert map back to slice for the substitution step
-	allExpressionMappings := make([]*ExpressionMapping, 0, len(expressionMappingsMap))
-
-	// Sort the keys to ensure stable output
-	sortedKeys := make([]string, 0, len(expressionMappingsMap))
-	for key := range expressionMappingsMap {
-		sortedKeys = append(sortedKeys, key)
-	}
-	sort.Strings(sortedKeys)
-
-	// Add mappings in sorted order
-	for _, key := range sortedKeys {
-		allExpressionMappings = append(allExpressionMappings, expressionMappingsMap[key])
-	}
+	allEnvVars, allExpressionMappings := collectPromptExpressionMappings(builtinSections, expressionMappings)
 
 	// Generate the step with all environment variables
 	yaml.WriteString("      - name: Create prompt with built-in context\n")
@@ -455,6 +408,9 @@ func (c *Compiler) generateUnifiedPromptCreationStep(yaml *strings.Builder, buil
 	if data.SafeOutputs != nil {
 		yaml.WriteString("          GH_AW_SAFE_OUTPUTS: ${{ env.GH_AW_SAFE_OUTPUTS }}\n")
 	}
+	if data.PromptLimit != nil {
+		fmt.Fprintf(yaml, "          %s: %s\n", promptSectionNonceEnvVar, promptSectionNonceExpression())
+	}
 
 	// Add all environment variables in sorted order for consistency
 	var envKeys []string
@@ -579,6 +535,21 @@ func (c *Compiler) generateUnifiedPromptCreationStep(yaml *strings.Builder, buil
 	for chunkIdx, chunk := range userPromptChunks {
 		unifiedPromptLog.Printf("Writing user prompt chunk %d/%d", chunkIdx+1, len(userPromptChunks))
 
+		// Section markers are printed with the nonce of the run
+		if command, ok := promptSectionMarkerCommand(chunk); ok {
+			if inHeredoc {
+				yaml.WriteString("          " + delimiter + "\n")
+				inHeredoc = false
+			}
+			if isFirstContent {
+				yaml.WriteString("          " + command + " > \"$GH_AW_PROMPT\"\n")
+				isFirstContent = false
+			} else {
+				yaml.WriteString("          " + command + " >> \"$GH_AW_PROMPT\"\n")
+			}
+			continue
+		}
+
 		// Check if this chunk is a runtime-import macro
 		if strings.HasPrefix(chunk, "{{#runtime-import ") && strings.HasSuffix(chunk, "}}") {
 			// This is a runtime-import macro - write it using heredoc for safe escaping
@@ -639,3 +610,66 @@ func (c *Compiler) generateUnifiedPromptCreationStep(yaml *strings.Builder, buil
 
 	unifiedPromptLog.Print("Unified prompt creation step generated successfully")
 }
+
+// collectPromptExpressionMappings merges the environment variables of the built-in sections with the
+// expressions extracted from the user prompt. It returns the environment of the prompt creation step
+// and the mappings of the placeholder substitution step, sorted by environment variable name.
+func collectPromptExpressionMappings(builtinSections []PromptSection, expressionMappings []*ExpressionMapping) (map[string]string, []*ExpressionMapping) {
+	// Collect all environment variables from built-in sections and user prompt expressions
+	allEnvVars := make(map[string]string)
+
+	// Also collect all expression mappings for the substitution step (using a map to avoid duplicates)
+	expressionMappingsMap := make(map[string]*ExpressionMapping)
+
+	// Add environment variables and expression mappings from built-in sections
+	for _, section := range builtinSections {
+		for key, value := range section.EnvVars {
+			// Extract the GitHub expression from the value (e.g., "${{ github.repository }}" -> "github.repository")
+			// This is needed for the substitution step
+			if strings.HasPrefix(value, "${{ ") && strings.HasSuffix(value, " }}") {
+				content := strings.TrimSpace(value[4 : len(value)-3])
+				// Add to both allEnvVars (for prompt creation step) and expressionMappingsMap (for substitution step)
+				allEnvVars[key] = value
+				// Only add if not already present (user prompt expressions take precedence)
+				if _, exists := expressionMappingsMap[key]; !exists {
+					expressionMappingsMap[key] = &ExpressionMapping{
+						EnvVar:  key,
+						Content: content,
+					}
+				}
+			} else {
+				// For static values (not GitHub Actions expressions), only add to expressionMappingsMap
+				// This ensures they're only available in the substitution step, not the prompt creation step
+				if _, exists := expressionMappingsMap[key]; !exists {
+					expressionMappingsMap[key] = &ExpressionMapping{
+						EnvVar:  key,
+						Content: fmt.Sprintf("'%s'", value), // Wrap in quotes for substitution
+					}
+				}
+			}
+		}
+	}
+
+	// Add environment variables from user prompt expressions (these override built-in ones)
+	for _, mapping := range expressionMappings {
+		allEnvVars[mapping.EnvVar] = fmt.Sprintf("${{ %s }}", mapping.Content)
+		expressionMappingsMap[mapping.EnvVar] = mapping
+	}
+
+	// Convert map back to slice for the substitution step
+	allExpressionMappings := make([]*ExpressionMapping, 0, len(expressionMappingsMap))
+
+	// Sort the keys to ensure stable output
+	sortedKeys := make([]string, 0, len(expressionMappingsMap))
+	for key := range expressionMappingsMap {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	// Add mappings in sorted order
+	for _, key := range sortedKeys {
+		allExpressionMappings = append(allExpressionMappings, expressionMappingsMap[key])
+	}
+
+	return allEnvVars, allExpressionMappings
+}
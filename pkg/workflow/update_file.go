@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var updateFileLog = logger.New("workflow:update_file")
+
+// defaultUpdateFileCommitMessage is the commit message template used when none is configured.
+// Placeholders: {path}, {branch}, {summary}, {workflow_name}, {run_url}
+const defaultUpdateFileCommitMessage = "Update {path}"
+
+// UpdateFileConfig holds configuration for committing single files through the contents API.
+// The agent never receives contents: write; the safe outputs job performs the commit.
+type UpdateFileConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	AllowedPaths         []string `yaml:"allowed-paths,omitempty"`    // Glob patterns of files that may be written (required)
+	Branch               string   `yaml:"branch,omitempty"`           // Branch to commit to when the agent does not specify one (default: repository default branch)
+	AllowedBranches      []string `yaml:"allowed-branches,omitempty"` // Glob patterns of branches the agent may commit to (default: only the configured branch)
+	MaxSizeKB            int      `yaml:"max-size,omitempty"`         // Maximum file size in KB (default: 512)
+	CommitMessage        string   `yaml:"commit-message,omitempty"`   // Commit message template (default: "Update {path}")
+}
+
+// parseUpdateFileConfig handles update-file configuration
+func (c *Compiler) parseUpdateFileConfig(outputMap map[string]any) *UpdateFileConfig {
+	configData, exists := outputMap["update-file"]
+	if !exists {
+		return nil
+	}
+
+	updateFileLog.Print("Parsing update-file configuration")
+	config := &UpdateFileConfig{
+		MaxSizeKB:     512,
+		CommitMessage: defaultUpdateFileCommitMessage,
+	}
+
+	if configMap, ok := configData.(map[string]any); ok {
+		config.AllowedPaths = ParseStringArrayFromConfig(configMap, "allowed-paths", updateFileLog)
+		config.AllowedBranches = ParseStringArrayFromConfig(configMap, "allowed-branches", updateFileLog)
+
+		if branch, ok := configMap["branch"].(string); ok {
+			config.Branch = branch
+		}
+		if maxSize, exists := configMap["max-size"]; exists {
+			if maxSizeInt, ok := parseIntValue(maxSize); ok && maxSizeInt > 0 {
+				config.MaxSizeKB = maxSizeInt
+			}
+		}
+		if commitMessage, ok := configMap["commit-message"].(string); ok && strings.TrimSpace(commitMessage) != "" {
+			config.CommitMessage = commitMessage
+		}
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &config.BaseSafeOutputConfig, 1)
+	} else {
+		config.Max = 1
+	}
+
+	updateFileLog.Printf("Parsed update-file config: max=%d, allowed_paths=%v, branch=%q, allowed_branches=%v, max_size_kb=%d",
+		config.Max, config.AllowedPaths, config.Branch, config.AllowedBranches, config.MaxSizeKB)
+	return config
+}
+
+// validateUpdateFileConfig ensures update-file is restricted to explicit, repository-relative paths
+func validateUpdateFileConfig(config *SafeOutputsConfig) error {
+	if config == nil || config.UpdateFile == nil {
+		return nil
+	}
+
+	if len(config.UpdateFile.AllowedPaths) == 0 {
+		return fmt.Errorf("safe-outputs.update-file requires allowed-paths. Example:\n  update-file:\n    allowed-paths: [\"STATUS.md\", \"dashboards/*.json\"]")
+	}
+	for _, pattern := range config.UpdateFile.AllowedPaths {
+		if strings.HasPrefix(pattern, "/") || strings.Contains(pattern, "..") || strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("safe-outputs.update-file: invalid path pattern '%s' in allowed-paths (must be relative to the repository root)", pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUpdateFileConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   any
+		expected *UpdateFileConfig
+	}{
+		{
+			name:   "null config uses defaults",
+			config: nil,
+			expected: &UpdateFileConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 1},
+				MaxSizeKB:            512,
+				CommitMessage:        "Update {path}",
+			},
+		},
+		{
+			name: "full config",
+			config: map[string]any{
+				"allowed-paths":    []any{"STATUS.md", "dashboards/**/*.json"},
+				"branch":           "status",
+				"allowed-branches": []any{"status", "status-*"},
+				"max-size":         64,
+				"commit-message":   "chore(status): {summary}",
+				"max":              3,
+			},
+			expected: &UpdateFileConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: 3},
+				AllowedPaths:         []string{"STATUS.md", "dashboards/**/*.json"},
+				Branch:               "status",
+				AllowedBranches:      []string{"status", "status-*"},
+				MaxSizeKB:            64,
+				CommitMessage:        "chore(status): {summary}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewCompiler().parseUpdateFileConfig(map[string]any{"update-file": tt.config})
+			assert.Equal(t, tt.expected, config, "config should be parsed")
+		})
+	}
+
+	assert.Nil(t, NewCompiler().parseUpdateFileConfig(map[string]any{}), "missing config should return nil")
+}
+
+func TestValidateUpdateFileConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *UpdateFileConfig
+		wantErr string
+	}{
+		{
+			name:   "valid config",
+			config: &UpdateFileConfig{AllowedPaths: []string{"STATUS.md", "docs/*.md"}},
+		},
+		{
+			name:    "missing allowed-paths",
+			config:  &UpdateFileConfig{},
+			wantErr: "requires allowed-paths",
+		},
+		{
+			name:    "parent directory pattern",
+			config:  &UpdateFileConfig{AllowedPaths: []string{"../secrets.txt"}},
+			wantErr: "invalid path pattern '../secrets.txt'",
+		},
+		{
+			name:    "absolute pattern",
+			config:  &UpdateFileConfig{AllowedPaths: []string{"/etc/passwd"}},
+			wantErr: "invalid path pattern '/etc/passwd'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpdateFileConfig(&SafeOutputsConfig{UpdateFile: tt.config})
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "config should be valid")
+				return
+			}
+			require.Error(t, err, "config should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error should explain the problem")
+		})
+	}
+}
+
+func TestUpdateFileCompilation(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+permissions:
+  contents: read
+safe-outputs:
+  update-file:
+    allowed-paths: ["STATUS.md"]
+    branch: status
+    commit-message: "chore(status): {summary}"
+---
+
+# Status page
+
+Refresh STATUS.md with the current project status.
+`
+
+	tmpDir := testutil.TempDir(t, "update-file-*")
+	testFile := filepath.Join(tmpDir, "status.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	require.NoError(t, NewCompiler().CompileWorkflow(testFile), "workflow should compile")
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "status.lock.yml"))
+	require.NoError(t, err, "should read lock file")
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, `\"update_file\":{\"allowed_paths\":[\"STATUS.md\"],\"branch\":\"status\",\"commit_message\":\"chore(status): {summary}\",\"max\":1,\"max_size_kb\":512}`, "handler config should include the path allowlist")
+	assert.Contains(t, lock, `"name": "update_file"`, "update_file tool should be generated")
+
+	agentJob := lock[strings.Index(lock, "\n  agent:\n"):]
+	agentHeader := agentJob[:strings.Index(agentJob, "    steps:")]
+	assert.NotContains(t, agentHeader, "contents: write", "agent job should not have contents: write")
+
+	safeOutputsJob := lock[strings.Index(lock, "\n  safe_outputs:\n"):]
+	jobHeader := safeOutputsJob[:strings.Index(safeOutputsJob, "    steps:")]
+	assert.Contains(t, jobHeader, "contents: write", "safe_outputs job should have contents: write")
+}
+
+func TestUpdateFileCompilationRequiresAllowedPaths(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: copilot
+safe-outputs:
+  update-file:
+    branch: status
+---
+
+# Status page
+`
+
+	tmpDir := testutil.TempDir(t, "update-file-invalid-*")
+	testFile := filepath.Join(tmpDir, "status.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0o644), "should write workflow")
+
+	err := NewCompiler().CompileWorkflow(testFile)
+	require.Error(t, err, "workflow without allowed-paths should not compile")
+	assert.Contains(t, err.Error(), "allowed-paths", "error should mention allowed-paths")
+}